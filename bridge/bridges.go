@@ -7,6 +7,7 @@ import (
 	"github.com/MichaelMure/git-bug/bridge/gitlab"
 	"github.com/MichaelMure/git-bug/bridge/jira"
 	"github.com/MichaelMure/git-bug/bridge/launchpad"
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/repository"
 )
@@ -30,6 +31,12 @@ func LoginMetaKey(target string) (string, error) {
 	return core.LoginMetaKey(target)
 }
 
+// BugUrl return the url of the remote issue of a bug imported from or exported
+// to a bug-tracker, if any
+func BugUrl(snap *bug.Snapshot) (string, bool) {
+	return core.BugUrl(snap)
+}
+
 // Instantiate a new Bridge for a repo, from the given target and name
 func NewBridge(repo *cache.RepoCache, target string, name string) (*core.Bridge, error) {
 	return core.NewBridge(repo, target, name)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/repository"
 )
@@ -30,6 +31,7 @@ const (
 
 var bridgeImpl map[string]reflect.Type
 var bridgeLoginMetaKey map[string]string
+var bridgeBugUrlMetaKey map[string]string
 
 // Bridge is a wrapper around a BridgeImpl that will bind low-level
 // implementation with utility code to provide high-level functions.
@@ -52,8 +54,14 @@ func Register(impl BridgeImpl) {
 	if bridgeLoginMetaKey == nil {
 		bridgeLoginMetaKey = make(map[string]string)
 	}
+	if bridgeBugUrlMetaKey == nil {
+		bridgeBugUrlMetaKey = make(map[string]string)
+	}
 	bridgeImpl[impl.Target()] = reflect.TypeOf(impl).Elem()
 	bridgeLoginMetaKey[impl.Target()] = impl.LoginMetaKey()
+	if urlImpl, ok := impl.(BugUrlMetaKeyer); ok {
+		bridgeBugUrlMetaKey[impl.Target()] = urlImpl.BugUrlMetaKey()
+	}
 }
 
 // Targets return all known bridge implementation target
@@ -87,6 +95,22 @@ func LoginMetaKey(target string) (string, error) {
 	return metaKey, nil
 }
 
+// BugUrl return the url of the remote issue of a bug imported from or exported
+// to a bug-tracker, if any
+func BugUrl(snap *bug.Snapshot) (string, bool) {
+	for _, target := range Targets() {
+		metaKey, ok := bridgeBugUrlMetaKey[target]
+		if !ok {
+			continue
+		}
+		if url, ok := snap.GetCreateMetadata(metaKey); ok && url != "" {
+			return url, true
+		}
+	}
+
+	return "", false
+}
+
 // Instantiate a new Bridge for a repo, from the given target and name
 func NewBridge(repo *cache.RepoCache, target string, name string) (*Bridge, error) {
 	implType, ok := bridgeImpl[target]
@@ -122,6 +146,10 @@ func LoadBridge(repo *cache.RepoCache, name string) (*Bridge, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid configuration")
 	}
+	_, err = ParseExportQuery(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid configuration")
+	}
 
 	// will avoid reloading configuration before an export or import call
 	bridge.conf = conf
@@ -214,6 +242,10 @@ func (b *Bridge) Configure(params BridgeParams) error {
 	if err != nil {
 		return fmt.Errorf("invalid configuration: %v", err)
 	}
+	_, err = ParseExportQuery(conf)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %v", err)
+	}
 
 	b.conf = conf
 	return b.storeConfig(conf)
@@ -346,8 +378,13 @@ func (b *Bridge) ImportAllSince(ctx context.Context, since time.Time) (<-chan Im
 		return nil, err
 	}
 
+	// the cache is updated and the bug commits written in git once at the end
+	// of the import instead of after every imported change
+	b.repo.BeginTransaction()
+
 	events, err := importer.ImportAll(ctx, b.repo, since)
 	if err != nil {
+		_ = b.repo.CommitTransaction()
 		return nil, err
 	}
 
@@ -364,6 +401,12 @@ func (b *Bridge) ImportAllSince(ctx context.Context, since time.Time) (<-chan Im
 			out <- event
 		}
 
+		err := b.repo.CommitTransaction()
+		if err != nil {
+			noError = false
+			out <- NewImportError(err, "")
+		}
+
 		// store the last import time ONLY if no error happened
 		if noError {
 			key := fmt.Sprintf("git-bug.bridge.%s.lastImportTime", b.Name)
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// ConfKeyExportQuery is the configuration key of the query selecting the
+// bugs exported by a bridge, as "label:public". Without it, all the bugs are
+// exported.
+const ConfKeyExportQuery = "export-query"
+
+// ParseExportQuery read the export query of the configuration of a bridge,
+// or return nil if all the bugs are exported
+func ParseExportQuery(conf Configuration) (*cache.Query, error) {
+	raw, ok := conf[ConfKeyExportQuery]
+	if !ok || raw == "" {
+		return nil, nil
+	}
+
+	query, err := cache.ParseQuery(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", ConfKeyExportQuery)
+	}
+
+	// the bugs are exported in the order of their creation, whatever the
+	// sorting of the query
+	query.OrderBy = cache.OrderByCreation
+	query.OrderDirection = cache.OrderAscending
+
+	return query, nil
+}
+
+// ExportedBugsIds return the id of the bugs to export with the configuration
+// of a bridge
+func ExportedBugsIds(repo *cache.RepoCache, conf Configuration) ([]entity.Id, error) {
+	query, err := ParseExportQuery(conf)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return repo.AllBugsIds(), nil
+	}
+
+	return repo.QueryBugs(query), nil
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestExportedBugsIds(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	author, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+
+	internal, _, err := backend.NewBugRaw(author, time.Now().Unix(), "internal", "message", nil, nil)
+	require.NoError(t, err)
+	public, _, err := backend.NewBugRaw(author, time.Now().Unix(), "public", "message", nil, nil)
+	require.NoError(t, err)
+	_, err = public.ForceChangeLabelsRaw(author, time.Now().Unix(), []string{"public"}, nil, nil)
+	require.NoError(t, err)
+
+	ids, err := ExportedBugsIds(backend, Configuration{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []entity.Id{internal.Id(), public.Id()}, ids)
+
+	ids, err = ExportedBugsIds(backend, Configuration{ConfKeyExportQuery: "label:public"})
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{public.Id()}, ids)
+
+	_, err = ExportedBugsIds(backend, Configuration{ConfKeyExportQuery: "invalid"})
+	require.Error(t, err)
+}
@@ -24,6 +24,12 @@ const (
 	ImportEventTitleEdition
 	// Bug's labels changed
 	ImportEventLabelChange
+	// Bug's milestone changed
+	ImportEventMilestoneChange
+	// Bug's assignees changed
+	ImportEventAssigneeChange
+	// Bug's due date changed
+	ImportEventDueDateChange
 	// Nothing happened on a Bug
 	ImportEventNothing
 
@@ -62,6 +68,12 @@ func (er ImportResult) String() string {
 		return fmt.Sprintf("changed title: %s", er.ID)
 	case ImportEventLabelChange:
 		return fmt.Sprintf("changed label: %s", er.ID)
+	case ImportEventMilestoneChange:
+		return fmt.Sprintf("changed milestone: %s", er.ID)
+	case ImportEventAssigneeChange:
+		return fmt.Sprintf("changed assignees: %s", er.ID)
+	case ImportEventDueDateChange:
+		return fmt.Sprintf("changed due date: %s", er.ID)
 	case ImportEventIdentity:
 		return fmt.Sprintf("new identity: %s", er.ID)
 	case ImportEventNothing:
@@ -152,6 +164,27 @@ func NewImportLabelChange(id entity.Id) ImportResult {
 	}
 }
 
+func NewImportMilestoneChange(id entity.Id) ImportResult {
+	return ImportResult{
+		ID:    id,
+		Event: ImportEventMilestoneChange,
+	}
+}
+
+func NewImportAssigneeChange(id entity.Id) ImportResult {
+	return ImportResult{
+		ID:    id,
+		Event: ImportEventAssigneeChange,
+	}
+}
+
+func NewImportDueDateChange(id entity.Id) ImportResult {
+	return ImportResult{
+		ID:    id,
+		Event: ImportEventDueDateChange,
+	}
+}
+
 func NewImportTitleEdition(id entity.Id) ImportResult {
 	return ImportResult{
 		ID:    id,
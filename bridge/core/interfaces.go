@@ -35,6 +35,14 @@ type BridgeImpl interface {
 	LoginMetaKey() string
 }
 
+// BugUrlMetaKeyer is optionally implemented by a BridgeImpl storing the url of
+// the remote issue on the bugs it import or export.
+type BugUrlMetaKeyer interface {
+	// BugUrlMetaKey return the metadata key used to store the url of the remote
+	// issue on the creation of a bug
+	BugUrlMetaKey() string
+}
+
 type Importer interface {
 	Init(ctx context.Context, repo *cache.RepoCache, conf Configuration) error
 	ImportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan ImportResult, error)
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// The configuration keys of the mapping of the remote data without a direct
+// equivalent in git-bug
+const (
+	ConfKeyMapMilestone = "map-milestone"
+	ConfKeyMapAssignees = "map-assignees"
+	ConfKeyMapDueDate   = "map-due-date"
+)
+
+// MappingTarget is how a remote data is imported
+type MappingTarget string
+
+const (
+	// MapIgnore drop the data
+	MapIgnore MappingTarget = "ignore"
+	// MapLabel flatten the data into a label, as "milestone:v1.0"
+	MapLabel MappingTarget = "label"
+	// MapComponent import the milestone as the component of the bug
+	MapComponent MappingTarget = "component"
+	// MapAssignee import the assignees as assignees of the bug
+	MapAssignee MappingTarget = "assignee"
+)
+
+// The prefix of the labels holding a flattened data
+const (
+	MilestoneLabelPrefix = "milestone:"
+	AssigneeLabelPrefix  = "assignee:"
+	DueDateLabelPrefix   = "due:"
+)
+
+// Mapping tell how an importer convey the milestones, assignees and due dates
+// of the remote bug-tracker
+type Mapping struct {
+	Milestone MappingTarget
+	Assignees MappingTarget
+	DueDate   MappingTarget
+}
+
+// DefaultMapping is the mapping used when a bridge configure none: the
+// milestones become components and the assignees are kept, while the due
+// dates, without equivalent, are dropped
+func DefaultMapping() Mapping {
+	return Mapping{
+		Milestone: MapComponent,
+		Assignees: MapAssignee,
+		DueDate:   MapIgnore,
+	}
+}
+
+// ParseMapping read the mapping of the configuration of a bridge, with the
+// default for the missing keys
+func ParseMapping(conf Configuration) (Mapping, error) {
+	mapping := DefaultMapping()
+
+	fields := []struct {
+		key     string
+		target  *MappingTarget
+		allowed []MappingTarget
+	}{
+		{ConfKeyMapMilestone, &mapping.Milestone, []MappingTarget{MapIgnore, MapLabel, MapComponent}},
+		{ConfKeyMapAssignees, &mapping.Assignees, []MappingTarget{MapIgnore, MapLabel, MapAssignee}},
+		{ConfKeyMapDueDate, &mapping.DueDate, []MappingTarget{MapIgnore, MapLabel}},
+	}
+
+	for _, field := range fields {
+		value, ok := conf[field.key]
+		if !ok {
+			continue
+		}
+
+		valid := false
+		for _, allowed := range field.allowed {
+			if MappingTarget(value) == allowed {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			names := make([]string, len(field.allowed))
+			for i, allowed := range field.allowed {
+				names[i] = string(allowed)
+			}
+			return Mapping{}, fmt.Errorf("invalid %s %s, expected one of %s", field.key, value, strings.Join(names, ", "))
+		}
+
+		*field.target = MappingTarget(value)
+	}
+
+	return mapping, nil
+}
+
+// ImportMilestone convey the milestone of a remote bug, replacing the current
+// one. An empty milestone remove it. The operation is nil if nothing changed.
+func (m Mapping) ImportMilestone(b *cache.BugCache, author *cache.IdentityCache, unixTime int64, milestone string, metadata map[string]string) (bug.Operation, error) {
+	switch m.Milestone {
+	case MapComponent:
+		if b.Snapshot().Component == milestone {
+			return nil, nil
+		}
+		return b.SetComponentRaw(author, unixTime, milestone, metadata)
+	case MapLabel:
+		return replacePrefixedLabel(b, author, unixTime, MilestoneLabelPrefix, milestone, metadata)
+	default:
+		return nil, nil
+	}
+}
+
+// ImportMilestoneRemoval convey the removal of a milestone from a remote bug,
+// if it is still the current one. The operation is nil if nothing changed.
+func (m Mapping) ImportMilestoneRemoval(b *cache.BugCache, author *cache.IdentityCache, unixTime int64, milestone string, metadata map[string]string) (bug.Operation, error) {
+	switch m.Milestone {
+	case MapComponent:
+		if b.Snapshot().Component != milestone {
+			return nil, nil
+		}
+		return b.SetComponentRaw(author, unixTime, "", metadata)
+	case MapLabel:
+		for _, label := range b.Snapshot().Labels {
+			if string(label) == MilestoneLabelPrefix+milestone {
+				return b.ForceChangeLabelsRaw(author, unixTime, nil, []string{string(label)}, metadata)
+			}
+		}
+		return nil, nil
+	default:
+		return nil, nil
+	}
+}
+
+// ImportDueDate convey the due date of a remote bug, replacing the current
+// one. An empty date remove it. The operation is nil if nothing changed.
+func (m Mapping) ImportDueDate(b *cache.BugCache, author *cache.IdentityCache, unixTime int64, dueDate string, metadata map[string]string) (bug.Operation, error) {
+	switch m.DueDate {
+	case MapLabel:
+		return replacePrefixedLabel(b, author, unixTime, DueDateLabelPrefix, dueDate, metadata)
+	default:
+		return nil, nil
+	}
+}
+
+// ImportAssignees convey a change of the assignees of a remote bug. The
+// operation is nil if nothing changed.
+func (m Mapping) ImportAssignees(b *cache.BugCache, author *cache.IdentityCache, unixTime int64, added []*cache.IdentityCache, removed []*cache.IdentityCache, metadata map[string]string) (bug.Operation, error) {
+	snap := b.Snapshot()
+
+	switch m.Assignees {
+	case MapAssignee:
+		var add, remove []*cache.IdentityCache
+		for _, i := range added {
+			if !snap.IsAssigned(i.Id()) {
+				add = append(add, i)
+			}
+		}
+		for _, i := range removed {
+			if snap.IsAssigned(i.Id()) {
+				remove = append(remove, i)
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return nil, nil
+		}
+		return b.ChangeAssigneesRaw(author, unixTime, add, remove, metadata)
+
+	case MapLabel:
+		var add, remove []string
+		for _, label := range assigneeLabels(added) {
+			if !snap.HasLabel(bug.Label(label)) {
+				add = append(add, label)
+			}
+		}
+		for _, label := range assigneeLabels(removed) {
+			if snap.HasLabel(bug.Label(label)) {
+				remove = append(remove, label)
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return nil, nil
+		}
+		return b.ForceChangeLabelsRaw(author, unixTime, add, remove, metadata)
+
+	default:
+		return nil, nil
+	}
+}
+
+func assigneeLabels(identities []*cache.IdentityCache) []string {
+	labels := make([]string, 0, len(identities))
+	for _, i := range identities {
+		name := i.Login()
+		if name == "" {
+			name = i.DisplayName()
+		}
+		labels = append(labels, AssigneeLabelPrefix+name)
+	}
+	return labels
+}
+
+// replacePrefixedLabel replace the labels with the given prefix by the one
+// holding the value, if any
+func replacePrefixedLabel(b *cache.BugCache, author *cache.IdentityCache, unixTime int64, prefix string, value string, metadata map[string]string) (bug.Operation, error) {
+	var added, removed []string
+
+	present := false
+	for _, label := range b.Snapshot().Labels {
+		switch {
+		case value != "" && string(label) == prefix+value:
+			present = true
+		case strings.HasPrefix(string(label), prefix):
+			removed = append(removed, string(label))
+		}
+	}
+	if value != "" && !present {
+		added = append(added, prefix+value)
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return nil, nil
+	}
+
+	return b.ForceChangeLabelsRaw(author, unixTime, added, removed, metadata)
+}
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMapping(t *testing.T) {
+	mapping, err := ParseMapping(Configuration{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultMapping(), mapping)
+
+	mapping, err = ParseMapping(Configuration{
+		ConfKeyMapMilestone: "label",
+		ConfKeyMapAssignees: "ignore",
+		ConfKeyMapDueDate:   "label",
+	})
+	require.NoError(t, err)
+	require.Equal(t, Mapping{Milestone: MapLabel, Assignees: MapIgnore, DueDate: MapLabel}, mapping)
+
+	_, err = ParseMapping(Configuration{ConfKeyMapDueDate: "component"})
+	require.Error(t, err)
+
+	_, err = ParseMapping(Configuration{ConfKeyMapMilestone: "assignee"})
+	require.Error(t, err)
+}
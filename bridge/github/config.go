@@ -165,6 +165,9 @@ func (*Github) ValidateConfig(conf core.Configuration) error {
 	if _, ok := conf[confKeyDefaultLogin]; !ok {
 		return fmt.Errorf("missing %s key", confKeyDefaultLogin)
 	}
+	if _, err := core.ParseMapping(conf); err != nil {
+		return err
+	}
 
 	return nil
 }
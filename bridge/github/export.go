@@ -145,6 +145,11 @@ func (ge *githubExporter) ExportAll(ctx context.Context, repo *cache.RepoCache,
 		return nil, err
 	}
 
+	allBugsIds, err := core.ExportedBugsIds(repo, ge.conf)
+	if err != nil {
+		return nil, err
+	}
+
 	go func() {
 		defer close(out)
 
@@ -153,8 +158,6 @@ func (ge *githubExporter) ExportAll(ctx context.Context, repo *cache.RepoCache,
 			allIdentitiesIds = append(allIdentitiesIds, id)
 		}
 
-		allBugsIds := repo.AllBugsIds()
-
 		for _, id := range allBugsIds {
 			b, err := repo.ResolveBug(id)
 			if err != nil {
@@ -212,6 +215,16 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 		return
 	}
 
+	// the discussions and the pull requests are only imported
+	if _, ok := snapshot.GetCreateMetadata(metaKeyGithubDiscussion); ok {
+		out <- core.NewExportNothing(b.Id(), "imported from a discussion")
+		return
+	}
+	if _, ok := snapshot.GetCreateMetadata(metaKeyGithubPullRequest); ok {
+		out <- core.NewExportNothing(b.Id(), "imported from a pull request")
+		return
+	}
+
 	// get github bug ID
 	githubID, ok := snapshot.GetCreateMetadata(metaKeyGithubId)
 	if ok {
@@ -273,6 +286,15 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			return
 		}
 
+		// the labels set on creation
+		if len(createOp.Labels) > 0 {
+			if err := ge.updateGithubIssueLabels(ctx, client, id, createOp.Labels, nil); err != nil {
+				err := errors.Wrap(err, "updating labels")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+		}
+
 		// cache bug github ID and URL
 		bugGithubID = id
 		bugGithubURL = url
@@ -282,8 +304,12 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 	ge.cachedOperationIDs[createOp.Id()] = bugGithubID
 
 	for _, op := range snapshot.Operations[1:] {
-		// ignore SetMetadata operations
-		if _, ok := op.(*bug.SetMetadataOperation); ok {
+		// ignore the operations without an equivalent in the bridged tracker
+		switch op.(type) {
+		case *bug.SetMetadataOperation, *bug.SetComponentOperation, *bug.AssigneeChangeOperation,
+			*bug.SetParentOperation, *bug.RemoveParentOperation,
+			*bug.AddChecklistItemOperation, *bug.CheckItemOperation, *bug.VoteOperation,
+			*bug.RequestReviewOperation, *bug.CustomOperation, *bug.UnknownOperation:
 			continue
 		}
 
@@ -355,7 +381,20 @@ func (ge *githubExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			}
 
 		case *bug.SetStatusOperation:
-			if err := updateGithubIssueStatus(ctx, client, bugGithubID, op.Status); err != nil {
+			if err := updateGithubIssueStatus(ctx, client, bugGithubID, op.Status, op.Resolution); err != nil {
+				err := errors.Wrap(err, "editing status")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportStatusChange(op.Id())
+
+			id = bugGithubID
+			url = bugGithubURL
+
+		case *bug.MarkDuplicateOperation:
+			// github has no duplicate relation, the issue is only closed
+			if err := updateGithubIssueStatus(ctx, client, bugGithubID, bug.ClosedStatus, bug.DuplicateResolution); err != nil {
 				err := errors.Wrap(err, "editing status")
 				out <- core.NewExportError(err, b.Id())
 				return
@@ -698,7 +737,11 @@ func editCommentGithubIssue(ctx context.Context, gc *githubv4.Client, commentID,
 	return commentID, m.UpdateIssueComment.IssueComment.URL, nil
 }
 
-func updateGithubIssueStatus(ctx context.Context, gc *githubv4.Client, id string, status bug.Status) error {
+func updateGithubIssueStatus(ctx context.Context, gc *githubv4.Client, id string, status bug.Status, resolution bug.Resolution) error {
+	if status == bug.ClosedStatus && resolution != bug.NoResolution {
+		return closeGithubIssue(ctx, gc, id, resolution)
+	}
+
 	m := &updateIssueMutation{}
 
 	// set state
@@ -728,6 +771,36 @@ func updateGithubIssueStatus(ctx context.Context, gc *githubv4.Client, id string
 	return nil
 }
 
+// closeGithubIssue close an issue with the github reason closest to the
+// resolution
+func closeGithubIssue(ctx context.Context, gc *githubv4.Client, id string, resolution bug.Resolution) error {
+	m := &closeIssueMutation{}
+
+	var reason githubv4.String
+	switch resolution {
+	case bug.FixedResolution:
+		reason = "COMPLETED"
+	case bug.DuplicateResolution:
+		reason = "DUPLICATE"
+	default:
+		reason = "NOT_PLANNED"
+	}
+
+	input := CloseIssueInput{
+		IssueID:     id,
+		StateReason: &reason,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if err := gc.Mutate(ctx, m, input, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func updateGithubIssueBody(ctx context.Context, gc *githubv4.Client, id string, body string) error {
 	m := &updateIssueMutation{}
 	input := githubv4.UpdateIssueInput{
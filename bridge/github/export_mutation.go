@@ -1,5 +1,7 @@
 package github
 
+import "github.com/shurcooL/githubv4"
+
 type createIssueMutation struct {
 	CreateIssue struct {
 		Issue struct {
@@ -18,6 +20,23 @@ type updateIssueMutation struct {
 	} `graphql:"updateIssue(input:$input)"`
 }
 
+type closeIssueMutation struct {
+	CloseIssue struct {
+		Issue struct {
+			ID  string `graphql:"id"`
+			URL string `graphql:"url"`
+		}
+	} `graphql:"closeIssue(input:$input)"`
+}
+
+// CloseIssueInput is the input of the closeIssue mutation. It's not
+// provided by githubv4 yet, and the name of the type has to match the
+// one of the schema as it's used to type the query variable.
+type CloseIssueInput struct {
+	IssueID     githubv4.ID      `json:"issueId"`
+	StateReason *githubv4.String `json:"stateReason,omitempty"`
+}
+
 type addCommentToIssueMutation struct {
 	AddComment struct {
 		CommentEdge struct {
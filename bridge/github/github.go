@@ -18,16 +18,36 @@ const (
 	metaKeyGithubId    = "github-id"
 	metaKeyGithubUrl   = "github-url"
 	metaKeyGithubLogin = "github-login"
+	// the category of a bug imported from a discussion
+	metaKeyGithubDiscussion = "github-discussion"
+	// set on the comment marked as the answer of a discussion
+	metaKeyGithubAnswer = "github-answer"
+	// set on a bug imported from a pull request
+	metaKeyGithubPullRequest = "github-pull-request"
+	// the state of the review of a pull request, and the file of a review
+	// comment
+	metaKeyGithubReviewState = "github-review-state"
+	metaKeyGithubReviewPath  = "github-review-path"
 
 	confKeyOwner        = "owner"
 	confKeyProject      = "project"
 	confKeyDefaultLogin = "default-login"
+	// "true" to import the discussions as well as the issues
+	confKeyImportDiscussions = "import-discussions"
+	// "true" to import the conversations of the pull requests as well
+	confKeyImportPullRequests = "import-pull-requests"
+
+	// the label of the bugs imported from a discussion
+	discussionLabel = "discussion"
+	// the label of the bugs imported from a pull request
+	pullRequestLabel = "pull-request"
 
 	githubV3Url    = "https://api.github.com"
 	defaultTimeout = 60 * time.Second
 )
 
 var _ core.BridgeImpl = &Github{}
+var _ core.BugUrlMetaKeyer = &Github{}
 
 type Github struct{}
 
@@ -39,6 +59,10 @@ func (g *Github) LoginMetaKey() string {
 	return metaKeyGithubLogin
 }
 
+func (*Github) BugUrlMetaKey() string {
+	return metaKeyGithubUrl
+}
+
 func (*Github) NewImporter() core.Importer {
 	return &githubImporter{}
 }
@@ -19,6 +19,9 @@ import (
 type githubImporter struct {
 	conf core.Configuration
 
+	// how the milestones and assignees are imported
+	mapping core.Mapping
+
 	// default client
 	client *githubv4.Client
 
@@ -32,6 +35,12 @@ type githubImporter struct {
 func (gi *githubImporter) Init(_ context.Context, repo *cache.RepoCache, conf core.Configuration) error {
 	gi.conf = conf
 
+	mapping, err := core.ParseMapping(conf)
+	if err != nil {
+		return err
+	}
+	gi.mapping = mapping
+
 	creds, err := auth.List(repo,
 		auth.WithTarget(target),
 		auth.WithKind(auth.KindToken),
@@ -94,6 +103,17 @@ func (gi *githubImporter) ImportAll(ctx context.Context, repo *cache.RepoCache,
 
 		if err := gi.iterator.Error(); err != nil {
 			gi.out <- core.NewImportError(err, "")
+			return
+		}
+
+		if gi.conf[confKeyImportDiscussions] == "true" {
+			discussions := newDiscussionIterator(ctx, gi.client, 10, gi.conf[confKeyOwner], gi.conf[confKeyProject], since)
+			gi.importDiscussions(repo, discussions)
+		}
+
+		if gi.conf[confKeyImportPullRequests] == "true" {
+			pullRequests := newPullRequestIterator(ctx, gi.client, 10, gi.conf[confKeyOwner], gi.conf[confKeyProject], since)
+			gi.importPullRequests(repo, pullRequests)
 		}
 	}()
 
@@ -294,9 +314,10 @@ func (gi *githubImporter) ensureTimelineItem(repo *cache.RepoCache, b *cache.Bug
 		if err != nil {
 			return err
 		}
-		op, err := b.CloseRaw(
+		op, err := b.CloseWithResolutionRaw(
 			author,
 			item.ClosedEvent.CreatedAt.Unix(),
+			githubResolution(item.ClosedEvent.StateReason),
 			map[string]string{metaKeyGithubId: id},
 		)
 
@@ -358,6 +379,74 @@ func (gi *githubImporter) ensureTimelineItem(repo *cache.RepoCache, b *cache.Bug
 
 		gi.out <- core.NewImportTitleEdition(op.Id())
 		return nil
+
+	case "AssignedEvent", "UnassignedEvent":
+		event := item.AssignedEvent
+		if item.Typename == "UnassignedEvent" {
+			event = item.UnassignedEvent
+		}
+		id := parseId(event.Id)
+		_, err := b.ResolveOperationWithMetadata(metaKeyGithubId, id)
+		if err != cache.ErrNoMatchingOp {
+			return err
+		}
+		author, err := gi.ensurePerson(repo, event.Actor)
+		if err != nil {
+			return err
+		}
+		// a deleted or unsupported assignee is shown as the ghost
+		assignee, err := gi.ensurePerson(repo, event.Assignee.actor())
+		if err != nil {
+			return err
+		}
+		var added, removed []*cache.IdentityCache
+		if item.Typename == "AssignedEvent" {
+			added = []*cache.IdentityCache{assignee}
+		} else {
+			removed = []*cache.IdentityCache{assignee}
+		}
+		op, err := gi.mapping.ImportAssignees(
+			b,
+			author,
+			event.CreatedAt.Unix(),
+			added,
+			removed,
+			map[string]string{metaKeyGithubId: id},
+		)
+		if err != nil || op == nil {
+			return err
+		}
+
+		gi.out <- core.NewImportAssigneeChange(op.Id())
+		return nil
+
+	case "MilestonedEvent", "DemilestonedEvent":
+		event := item.MilestonedEvent
+		if item.Typename == "DemilestonedEvent" {
+			event = item.DemilestonedEvent
+		}
+		id := parseId(event.Id)
+		_, err := b.ResolveOperationWithMetadata(metaKeyGithubId, id)
+		if err != cache.ErrNoMatchingOp {
+			return err
+		}
+		author, err := gi.ensurePerson(repo, event.Actor)
+		if err != nil {
+			return err
+		}
+		metadata := map[string]string{metaKeyGithubId: id}
+		var op bug.Operation
+		if item.Typename == "MilestonedEvent" {
+			op, err = gi.mapping.ImportMilestone(b, author, event.CreatedAt.Unix(), string(event.MilestoneTitle), metadata)
+		} else {
+			op, err = gi.mapping.ImportMilestoneRemoval(b, author, event.CreatedAt.Unix(), string(event.MilestoneTitle), metadata)
+		}
+		if err != nil || op == nil {
+			return err
+		}
+
+		gi.out <- core.NewImportMilestoneChange(op.Id())
+		return nil
 	}
 
 	return nil
@@ -605,3 +694,22 @@ func (gi *githubImporter) getGhost(repo *cache.RepoCache) (*cache.IdentityCache,
 func parseId(id githubv4.ID) string {
 	return fmt.Sprintf("%v", id)
 }
+
+// githubResolution map the reason given by github when closing an issue to
+// a resolution
+func githubResolution(stateReason *githubv4.String) bug.Resolution {
+	if stateReason == nil {
+		return bug.NoResolution
+	}
+
+	switch *stateReason {
+	case "COMPLETED":
+		return bug.FixedResolution
+	case "NOT_PLANNED":
+		return bug.WontFixResolution
+	case "DUPLICATE":
+		return bug.DuplicateResolution
+	default:
+		return bug.NoResolution
+	}
+}
@@ -0,0 +1,183 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// importDiscussions import the discussions of the repository as bugs with the
+// discussion label. The replies to a comment are imported as a thread.
+func (gi *githubImporter) importDiscussions(repo *cache.RepoCache, iterator *discussionIterator) {
+	for iterator.NextDiscussion() {
+		d := iterator.DiscussionValue()
+
+		b, err := gi.ensureDiscussion(repo, d)
+		if err != nil {
+			err := fmt.Errorf("discussion creation: %v", err)
+			gi.out <- core.NewImportError(err, "")
+			return
+		}
+
+		for _, comment := range d.Comments.Nodes {
+			err := gi.ensureDiscussionComment(repo, b, d, comment)
+			if err != nil {
+				err = fmt.Errorf("discussion comment creation: %v", err)
+				gi.out <- core.NewImportError(err, "")
+				return
+			}
+		}
+
+		if !b.NeedCommit() {
+			gi.out <- core.NewImportNothing(b.Id(), "no imported operation")
+		} else if err := b.Commit(); err != nil {
+			// commit bug state
+			err = fmt.Errorf("bug commit: %v", err)
+			gi.out <- core.NewImportError(err, "")
+			return
+		}
+	}
+
+	if err := iterator.Error(); err != nil {
+		gi.out <- core.NewImportError(err, "")
+	}
+}
+
+func (gi *githubImporter) ensureDiscussion(repo *cache.RepoCache, d discussion) (*cache.BugCache, error) {
+	b, err := repo.ResolveBugCreateMetadata(metaKeyGithubUrl, d.Url.String())
+	if err == nil {
+		return b, nil
+	}
+	if err != bug.ErrBugNotExist {
+		return nil, err
+	}
+
+	// ensure discussion author
+	author, err := gi.ensurePerson(repo, d.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanText, err := text.Cleanup(string(d.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	b, _, err = repo.NewBugRaw(
+		author,
+		d.CreatedAt.Unix(),
+		d.Title,
+		cleanText,
+		nil,
+		map[string]string{
+			core.MetaKeyOrigin:      target,
+			metaKeyGithubId:         parseId(d.Id),
+			metaKeyGithubUrl:        d.Url.String(),
+			metaKeyGithubDiscussion: string(d.Category.Name),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// importing a new bug
+	gi.out <- core.NewImportBug(b.Id())
+
+	_, err = b.ForceChangeLabelsRaw(author, d.CreatedAt.Unix(), []string{discussionLabel}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (gi *githubImporter) ensureDiscussionComment(repo *cache.RepoCache, b *cache.BugCache, d discussion, comment discussionComment) error {
+	var metadata map[string]string
+	if comment.IsAnswer {
+		metadata = map[string]string{metaKeyGithubAnswer: "true"}
+	}
+
+	target, err := gi.ensureThreadedComment(repo, b, "", comment.authorEvent, comment.Body, comment.Url, metadata)
+	if err != nil {
+		return err
+	}
+
+	// the answer can be chosen after the import of the comment
+	if comment.IsAnswer {
+		snap := b.Snapshot()
+		for _, op := range snap.Operations {
+			if op.Id() != target {
+				continue
+			}
+			if _, ok := op.GetMetadata(metaKeyGithubAnswer); !ok {
+				author, err := gi.ensurePerson(repo, d.Author)
+				if err != nil {
+					return err
+				}
+				_, err = b.SetMetadataRaw(author, d.UpdatedAt.Unix(), target, metadata)
+				if err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+
+	for _, reply := range comment.Replies.Nodes {
+		_, err := gi.ensureThreadedComment(repo, b, target, reply.authorEvent, reply.Body, reply.Url, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureThreadedComment create a comment, or a reply to another comment when
+// replyTo is set, if not already imported
+func (gi *githubImporter) ensureThreadedComment(repo *cache.RepoCache, b *cache.BugCache, replyTo entity.Id, event authorEvent, body githubv4.String, url githubv4.URI, metadata map[string]string) (entity.Id, error) {
+	id, err := b.ResolveOperationWithMetadata(metaKeyGithubId, parseId(event.Id))
+	if err == nil {
+		return id, nil
+	}
+	if err != cache.ErrNoMatchingOp {
+		// real error
+		return "", err
+	}
+
+	author, err := gi.ensurePerson(repo, event.Author)
+	if err != nil {
+		return "", err
+	}
+
+	cleanText, err := text.Cleanup(string(body))
+	if err != nil {
+		return "", err
+	}
+
+	meta := map[string]string{
+		metaKeyGithubId:  parseId(event.Id),
+		metaKeyGithubUrl: url.String(),
+	}
+	for key, value := range metadata {
+		meta[key] = value
+	}
+
+	var op *bug.AddCommentOperation
+	if replyTo == "" {
+		op, err = b.AddCommentRaw(author, event.CreatedAt.Unix(), cleanText, nil, meta)
+	} else {
+		op, err = b.AddCommentReplyRaw(author, event.CreatedAt.Unix(), replyTo, cleanText, nil, meta)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	gi.out <- core.NewImportComment(op.Id())
+	return op.Id(), nil
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+// a fake Github API serving a single discussion, with its comments and
+// replies on two pages
+func discussionServer(t *testing.T, answered *bool) *httptest.Server {
+	author := `{"__typename": "User", "login": "rene", "avatarUrl": "", "name": "René Descartes", "email": ""}`
+
+	message := func(id string, body string) string {
+		return fmt.Sprintf(`"id": %q, "createdAt": "2021-01-01T00:00:00Z", "author": %s, "body": %q, "url": "https://github.com/rene/project/discussions/1#%s"`,
+			id, author, body, id)
+	}
+	page := func(next bool) string {
+		return fmt.Sprintf(`"pageInfo": {"endCursor": "cursor", "hasNextPage": %v, "startCursor": "", "hasPreviousPage": false}`, next)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var request struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.Unmarshal(raw, &request))
+
+		var data string
+		switch {
+		case strings.Contains(request.Query, "issues("):
+			data = `{"repository": {"issues": {"nodes": [], ` + page(false) + `}}}`
+
+		case strings.Contains(request.Query, "discussions("):
+			if request.Variables["discussionAfter"] != nil {
+				data = `{"repository": {"discussions": {"nodes": [], ` + page(false) + `}}}`
+				break
+			}
+			data = `{"repository": {"discussions": {"nodes": [{` +
+				message("D1", "how to?") + `, "title": "question", "updatedAt": "2021-01-02T00:00:00Z", "category": {"name": "Q&A"},` +
+				`"comments": {"nodes": [{` + message("C1", "first") + `, "isAnswer": false, "replies": {"nodes": [], ` + page(false) + `}}], ` + page(true) + `}` +
+				`}], ` + page(true) + `}}}`
+
+		case strings.Contains(request.Query, "comments("):
+			data = fmt.Sprintf(`{"node": {"comments": {"nodes": [{`+message("C2", "answer")+`, "isAnswer": %v, "replies": {"nodes": [{`+
+				message("R1", "thanks")+`}], `+page(true)+`}}], `+page(false)+`}}}`, *answered)
+
+		case strings.Contains(request.Query, "replies("):
+			data = `{"node": {"replies": {"nodes": [{` + message("R2", "you're welcome") + `}], ` + page(false) + `}}}`
+
+		default:
+			t.Fatalf("unexpected query %s", request.Query)
+		}
+
+		_, _ = fmt.Fprintf(w, `{"data": %s}`, data)
+	}))
+}
+
+func TestImportDiscussions(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	answered := false
+	server := discussionServer(t, &answered)
+	defer server.Close()
+
+	importer := &githubImporter{
+		conf: core.Configuration{
+			confKeyOwner:             "rene",
+			confKeyProject:           "project",
+			confKeyImportDiscussions: "true",
+		},
+		client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	importAll := func() {
+		events, err := importer.ImportAll(context.Background(), backend, time.Time{})
+		require.NoError(t, err)
+		for result := range events {
+			require.NoError(t, result.Err)
+		}
+	}
+
+	importAll()
+
+	b, err := backend.ResolveBugCreateMetadata(metaKeyGithubUrl, "https://github.com/rene/project/discussions/1#D1")
+	require.NoError(t, err)
+
+	snap := b.Snapshot()
+	require.Equal(t, "question", snap.Title)
+	require.Equal(t, []bug.Label{discussionLabel}, snap.Labels)
+	category, _ := snap.GetCreateMetadata(metaKeyGithubDiscussion)
+	require.Equal(t, "Q&A", category)
+
+	threads := snap.Threads()
+	require.Len(t, threads, 3)
+	require.Equal(t, "first", threads[1].Comment.Message)
+	require.Equal(t, "answer", threads[2].Comment.Message)
+	require.Len(t, threads[2].Replies, 2)
+	require.Equal(t, "thanks", threads[2].Replies[0].Comment.Message)
+	require.Equal(t, "you're welcome", threads[2].Replies[1].Comment.Message)
+
+	// the answer is chosen once imported
+	answered = true
+	importAll()
+
+	answer, err := b.ResolveOperationWithMetadata(metaKeyGithubId, "C2")
+	require.NoError(t, err)
+	for _, op := range b.Snapshot().Operations {
+		if op.Id() == answer {
+			value, ok := op.GetMetadata(metaKeyGithubAnswer)
+			require.True(t, ok)
+			require.Equal(t, "true", value)
+		}
+	}
+	require.Len(t, b.Snapshot().Threads(), 3)
+}
@@ -0,0 +1,111 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func TestImportMapping(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	out := make(chan core.ImportResult, 100)
+	importer := &githubImporter{
+		mapping: core.Mapping{
+			Milestone: core.MapLabel,
+			Assignees: core.MapAssignee,
+		},
+		out: out,
+	}
+
+	rene := &actor{Typename: "User", Login: "rene"}
+	author, err := importer.ensurePerson(backend, rene)
+	require.NoError(t, err)
+
+	b, _, err := backend.NewBugRaw(author, time.Now().Unix(), "title", "message", nil, nil)
+	require.NoError(t, err)
+
+	var assignee assignee
+	assignee.Typename = "User"
+	assignee.User.Login = "ada"
+
+	at := githubv4.DateTime{Time: time.Now()}
+
+	var assigned timelineItem
+	assigned.Typename = "AssignedEvent"
+	assigned.AssignedEvent.Id = "AE1"
+	assigned.AssignedEvent.CreatedAt = at
+	assigned.AssignedEvent.Actor = rene
+	assigned.AssignedEvent.Assignee = assignee
+
+	var milestoned timelineItem
+	milestoned.Typename = "MilestonedEvent"
+	milestoned.MilestonedEvent.Id = "ME1"
+	milestoned.MilestonedEvent.CreatedAt = at
+	milestoned.MilestonedEvent.Actor = rene
+	milestoned.MilestonedEvent.MilestoneTitle = "v1.0"
+
+	var milestoned2 timelineItem
+	milestoned2.Typename = "MilestonedEvent"
+	milestoned2.MilestonedEvent.Id = "ME2"
+	milestoned2.MilestonedEvent.CreatedAt = at
+	milestoned2.MilestonedEvent.Actor = rene
+	milestoned2.MilestonedEvent.MilestoneTitle = "v2.0"
+
+	// removing a milestone which is not the current one does nothing
+	var demilestoned timelineItem
+	demilestoned.Typename = "DemilestonedEvent"
+	demilestoned.DemilestonedEvent.Id = "DE1"
+	demilestoned.DemilestonedEvent.CreatedAt = at
+	demilestoned.DemilestonedEvent.Actor = rene
+	demilestoned.DemilestonedEvent.MilestoneTitle = "v1.0"
+
+	for _, item := range []timelineItem{assigned, milestoned, milestoned2, demilestoned} {
+		require.NoError(t, importer.ensureTimelineItem(backend, b, item))
+	}
+
+	snap := b.Snapshot()
+	require.Len(t, snap.Assignees, 1)
+	require.Equal(t, "ada", snap.Assignees[0].Login())
+	require.Equal(t, []bug.Label{"milestone:v2.0"}, snap.Labels)
+
+	// nothing new the second time
+	count := len(snap.Operations)
+	for _, item := range []timelineItem{assigned, milestoned, milestoned2, demilestoned} {
+		require.NoError(t, importer.ensureTimelineItem(backend, b, item))
+	}
+	require.Len(t, b.Snapshot().Operations, count)
+
+	var unassigned timelineItem
+	unassigned.Typename = "UnassignedEvent"
+	unassigned.UnassignedEvent.Id = "UE1"
+	unassigned.UnassignedEvent.CreatedAt = at
+	unassigned.UnassignedEvent.Actor = rene
+	unassigned.UnassignedEvent.Assignee = assignee
+
+	demilestoned.DemilestonedEvent.Id = "DE2"
+	demilestoned.DemilestonedEvent.MilestoneTitle = "v2.0"
+
+	for _, item := range []timelineItem{unassigned, demilestoned} {
+		require.NoError(t, importer.ensureTimelineItem(backend, b, item))
+	}
+
+	snap = b.Snapshot()
+	require.Empty(t, snap.Assignees)
+	require.Empty(t, snap.Labels)
+}
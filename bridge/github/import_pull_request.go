@@ -0,0 +1,211 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// importPullRequests import the conversations of the pull requests of the
+// repository as bugs with the pull-request label. The diffs are not imported,
+// only the file of a review comment is kept as metadata.
+func (gi *githubImporter) importPullRequests(repo *cache.RepoCache, iterator *pullRequestIterator) {
+	for iterator.NextPullRequest() {
+		pr := iterator.PullRequestValue()
+
+		b, err := gi.ensurePullRequest(repo, pr)
+		if err != nil {
+			err := fmt.Errorf("pull request creation: %v", err)
+			gi.out <- core.NewImportError(err, "")
+			return
+		}
+
+		for _, item := range pr.TimelineItems.Nodes {
+			err := gi.ensurePullRequestItem(repo, b, item)
+			if err != nil {
+				err = fmt.Errorf("pull request timeline item creation: %v", err)
+				gi.out <- core.NewImportError(err, "")
+				return
+			}
+		}
+
+		if !b.NeedCommit() {
+			gi.out <- core.NewImportNothing(b.Id(), "no imported operation")
+		} else if err := b.Commit(); err != nil {
+			// commit bug state
+			err = fmt.Errorf("bug commit: %v", err)
+			gi.out <- core.NewImportError(err, "")
+			return
+		}
+	}
+
+	if err := iterator.Error(); err != nil {
+		gi.out <- core.NewImportError(err, "")
+	}
+}
+
+func (gi *githubImporter) ensurePullRequest(repo *cache.RepoCache, pr pullRequest) (*cache.BugCache, error) {
+	b, err := repo.ResolveBugCreateMetadata(metaKeyGithubUrl, pr.Url.String())
+	if err == nil {
+		return b, nil
+	}
+	if err != bug.ErrBugNotExist {
+		return nil, err
+	}
+
+	// ensure pull request author
+	author, err := gi.ensurePerson(repo, pr.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanText, err := text.Cleanup(string(pr.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	b, _, err = repo.NewBugRaw(
+		author,
+		pr.CreatedAt.Unix(),
+		pr.Title,
+		cleanText,
+		nil,
+		map[string]string{
+			core.MetaKeyOrigin:       target,
+			metaKeyGithubId:          parseId(pr.Id),
+			metaKeyGithubUrl:         pr.Url.String(),
+			metaKeyGithubPullRequest: "true",
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// importing a new bug
+	gi.out <- core.NewImportBug(b.Id())
+
+	_, err = b.ForceChangeLabelsRaw(author, pr.CreatedAt.Unix(), []string{pullRequestLabel}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (gi *githubImporter) ensurePullRequestItem(repo *cache.RepoCache, b *cache.BugCache, item pullRequestTimelineItem) error {
+	switch item.Typename {
+	case "IssueComment":
+		comment := item.IssueComment
+		_, err := gi.ensureThreadedComment(repo, b, "", comment.authorEvent, comment.Body, comment.Url, nil)
+		return err
+
+	case "PullRequestReview":
+		review := item.PullRequestReview
+
+		// a review without message only hold comments on the diff
+		if review.Body != "" {
+			_, err := gi.ensureThreadedComment(repo, b, "", review.authorEvent, review.Body, review.Url,
+				map[string]string{metaKeyGithubReviewState: string(review.State)})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, comment := range review.Comments.Nodes {
+			var replyTo entity.Id
+			if comment.ReplyTo != nil {
+				id, err := b.ResolveOperationWithMetadata(metaKeyGithubId, parseId(comment.ReplyTo.Id))
+				if err != nil && err != cache.ErrNoMatchingOp {
+					return err
+				}
+				// a reply to a missing comment start a thread of its own
+				replyTo = id
+			}
+
+			_, err := gi.ensureThreadedComment(repo, b, replyTo, comment.authorEvent, comment.Body, comment.Url,
+				map[string]string{metaKeyGithubReviewPath: string(comment.Path)})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case "ClosedEvent":
+		id := parseId(item.ClosedEvent.Id)
+		_, err := b.ResolveOperationWithMetadata(metaKeyGithubId, id)
+		if err != cache.ErrNoMatchingOp {
+			return err
+		}
+		// a merge close the pull request as well
+		if b.Snapshot().Status == bug.ClosedStatus {
+			return nil
+		}
+		author, err := gi.ensurePerson(repo, item.ClosedEvent.Actor)
+		if err != nil {
+			return err
+		}
+		op, err := b.CloseWithResolutionRaw(
+			author,
+			item.ClosedEvent.CreatedAt.Unix(),
+			bug.WontFixResolution,
+			map[string]string{metaKeyGithubId: id},
+		)
+		if err != nil {
+			return err
+		}
+
+		gi.out <- core.NewImportStatusChange(op.Id())
+		return nil
+
+	case "MergedEvent":
+		id := parseId(item.MergedEvent.Id)
+		_, err := b.ResolveOperationWithMetadata(metaKeyGithubId, id)
+		if err != cache.ErrNoMatchingOp {
+			return err
+		}
+		author, err := gi.ensurePerson(repo, item.MergedEvent.Actor)
+		if err != nil {
+			return err
+		}
+		op, err := b.CloseWithResolutionRaw(
+			author,
+			item.MergedEvent.CreatedAt.Unix(),
+			bug.FixedResolution,
+			map[string]string{metaKeyGithubId: id},
+		)
+		if err != nil {
+			return err
+		}
+
+		gi.out <- core.NewImportStatusChange(op.Id())
+		return nil
+
+	case "ReopenedEvent":
+		id := parseId(item.ReopenedEvent.Id)
+		_, err := b.ResolveOperationWithMetadata(metaKeyGithubId, id)
+		if err != cache.ErrNoMatchingOp {
+			return err
+		}
+		author, err := gi.ensurePerson(repo, item.ReopenedEvent.Actor)
+		if err != nil {
+			return err
+		}
+		op, err := b.OpenRaw(
+			author,
+			item.ReopenedEvent.CreatedAt.Unix(),
+			map[string]string{metaKeyGithubId: id},
+		)
+		if err != nil {
+			return err
+		}
+
+		gi.out <- core.NewImportStatusChange(op.Id())
+		return nil
+	}
+
+	return nil
+}
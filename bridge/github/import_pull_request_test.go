@@ -0,0 +1,153 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bridge/core"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+// a fake Github API serving a single pull request, with its timeline and
+// review comments on two pages
+func pullRequestServer(t *testing.T) *httptest.Server {
+	author := `{"__typename": "User", "login": "rene", "avatarUrl": "", "name": "René Descartes", "email": ""}`
+
+	event := func(id string, at string) string {
+		return fmt.Sprintf(`"id": %q, "createdAt": "2021-01-01T00:00:%sZ", "actor": %s`, id, at, author)
+	}
+	message := func(id string, at string, body string) string {
+		return fmt.Sprintf(`"id": %q, "createdAt": "2021-01-01T00:00:%sZ", "author": %s, "body": %q, "url": "https://github.com/rene/project/pull/1#%s"`,
+			id, at, author, body, id)
+	}
+	page := func(next bool) string {
+		return fmt.Sprintf(`"pageInfo": {"endCursor": "cursor", "hasNextPage": %v, "startCursor": "", "hasPreviousPage": false}`, next)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var request struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.Unmarshal(raw, &request))
+
+		var data string
+		switch {
+		case strings.Contains(request.Query, "issues("):
+			data = `{"repository": {"issues": {"nodes": [], ` + page(false) + `}}}`
+
+		case strings.Contains(request.Query, "pullRequests("):
+			if request.Variables["pullRequestAfter"] != nil {
+				data = `{"repository": {"pullRequests": {"nodes": [], ` + page(false) + `}}}`
+				break
+			}
+			data = `{"repository": {"pullRequests": {"nodes": [{` +
+				message("PR1", "00", "please merge") + `, "title": "a fix", "updatedAt": "2021-01-02T00:00:00Z",` +
+				`"timelineItems": {"nodes": [` +
+				`{"__typename": "IssueComment", ` + message("IC1", "01", "looks good") + `},` +
+				`{"__typename": "PullRequestReview", ` + message("RV1", "02", "some remarks") + `, "state": "CHANGES_REQUESTED",` +
+				`"comments": {"nodes": [{` + message("RC1", "02", "typo here") + `, "path": "main.go", "replyTo": null}], ` + page(true) + `}}` +
+				`], ` + page(true) + `}}], ` + page(true) + `}}}`
+
+		case strings.Contains(request.Query, "timelineItems("):
+			data = `{"node": {"timelineItems": {"nodes": [` +
+				`{"__typename": "PullRequestReview", ` + message("RV2", "04", "") + `, "state": "COMMENTED",` +
+				`"comments": {"nodes": [{` + message("RC3", "04", "fixed") + `, "path": "main.go", "replyTo": {"id": "RC1"}}], ` + page(false) + `}},` +
+				`{"__typename": "MergedEvent", ` + event("ME1", "05") + `},` +
+				`{"__typename": "ClosedEvent", ` + event("CE1", "05") + `}` +
+				`], ` + page(false) + `}}}`
+
+		case strings.Contains(request.Query, "comments("):
+			data = `{"node": {"comments": {"nodes": [{` + message("RC2", "03", "and here") + `, "path": "util.go", "replyTo": null}], ` + page(false) + `}}}`
+
+		default:
+			t.Fatalf("unexpected query %s", request.Query)
+		}
+
+		_, _ = fmt.Fprintf(w, `{"data": %s}`, data)
+	}))
+}
+
+func TestImportPullRequests(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	server := pullRequestServer(t)
+	defer server.Close()
+
+	importer := &githubImporter{
+		conf: core.Configuration{
+			confKeyOwner:              "rene",
+			confKeyProject:            "project",
+			confKeyImportPullRequests: "true",
+		},
+		client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	importAll := func() {
+		events, err := importer.ImportAll(context.Background(), backend, time.Time{})
+		require.NoError(t, err)
+		for result := range events {
+			require.NoError(t, result.Err)
+		}
+	}
+
+	importAll()
+
+	b, err := backend.ResolveBugCreateMetadata(metaKeyGithubUrl, "https://github.com/rene/project/pull/1#PR1")
+	require.NoError(t, err)
+
+	snap := b.Snapshot()
+	require.Equal(t, "a fix", snap.Title)
+	require.Equal(t, []bug.Label{pullRequestLabel}, snap.Labels)
+	require.Equal(t, bug.ClosedStatus, snap.Status)
+	require.Equal(t, bug.FixedResolution, snap.Resolution)
+
+	threads := snap.Threads()
+	require.Len(t, threads, 5)
+	require.Equal(t, "please merge", threads[0].Comment.Message)
+	require.Equal(t, "looks good", threads[1].Comment.Message)
+	require.Equal(t, "some remarks", threads[2].Comment.Message)
+	require.Equal(t, "typo here", threads[3].Comment.Message)
+	require.Len(t, threads[3].Replies, 1)
+	require.Equal(t, "fixed", threads[3].Replies[0].Comment.Message)
+	require.Equal(t, "and here", threads[4].Comment.Message)
+
+	for _, op := range snap.Operations {
+		if id, _ := op.GetMetadata(metaKeyGithubId); id == "RC2" {
+			path, _ := op.GetMetadata(metaKeyGithubReviewPath)
+			require.Equal(t, "util.go", path)
+		}
+		if id, _ := op.GetMetadata(metaKeyGithubId); id == "RV1" {
+			state, _ := op.GetMetadata(metaKeyGithubReviewState)
+			require.Equal(t, "CHANGES_REQUESTED", state)
+		}
+	}
+
+	// nothing new the second time
+	count := len(snap.Operations)
+	importAll()
+	require.Len(t, b.Snapshot().Operations, count)
+}
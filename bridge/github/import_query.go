@@ -23,6 +23,44 @@ type actor struct {
 	} `graphql:"... on Organization"`
 }
 
+// assignee is one of the possible assignees of an issue
+type assignee struct {
+	Typename githubv4.String `graphql:"__typename"`
+	User     struct {
+		Login     githubv4.String
+		AvatarUrl githubv4.String
+		Name      *githubv4.String
+		Email     githubv4.String
+	} `graphql:"... on User"`
+	Bot struct {
+		Login     githubv4.String
+		AvatarUrl githubv4.String
+	} `graphql:"... on Bot"`
+}
+
+// actor return the assignee as an actor, or nil if it is not a user or a bot
+func (a *assignee) actor() *actor {
+	switch a.Typename {
+	case "User":
+		result := &actor{
+			Typename:  a.Typename,
+			Login:     a.User.Login,
+			AvatarUrl: a.User.AvatarUrl,
+		}
+		result.User.Name = a.User.Name
+		result.User.Email = a.User.Email
+		return result
+	case "Bot":
+		return &actor{
+			Typename:  a.Typename,
+			Login:     a.Bot.Login,
+			AvatarUrl: a.Bot.AvatarUrl,
+		}
+	default:
+		return nil
+	}
+}
+
 type actorEvent struct {
 	Id        githubv4.ID
 	CreatedAt githubv4.DateTime
@@ -83,6 +121,7 @@ type timelineItem struct {
 	ClosedEvent struct {
 		actorEvent
 		// Url githubv4.URI
+		StateReason *githubv4.String
 	} `graphql:"... on  ClosedEvent"`
 	ReopenedEvent struct {
 		actorEvent
@@ -94,6 +133,26 @@ type timelineItem struct {
 		CurrentTitle  githubv4.String
 		PreviousTitle githubv4.String
 	} `graphql:"... on RenamedTitleEvent"`
+
+	// Assignees
+	AssignedEvent struct {
+		actorEvent
+		Assignee assignee
+	} `graphql:"... on AssignedEvent"`
+	UnassignedEvent struct {
+		actorEvent
+		Assignee assignee
+	} `graphql:"... on UnassignedEvent"`
+
+	// Milestone
+	MilestonedEvent struct {
+		actorEvent
+		MilestoneTitle githubv4.String
+	} `graphql:"... on MilestonedEvent"`
+	DemilestonedEvent struct {
+		actorEvent
+		MilestoneTitle githubv4.String
+	} `graphql:"... on DemilestonedEvent"`
 }
 
 type issueTimeline struct {
@@ -160,6 +219,160 @@ type commentEditQuery struct {
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
+type discussionReply struct {
+	authorEvent
+	Body githubv4.String
+	Url  githubv4.URI
+}
+
+type discussionComment struct {
+	authorEvent
+	Body     githubv4.String
+	Url      githubv4.URI
+	IsAnswer bool
+
+	Replies struct {
+		Nodes    []discussionReply
+		PageInfo pageInfo
+	} `graphql:"replies(first: $replyFirst)"`
+}
+
+type discussion struct {
+	authorEvent
+	Title     string
+	Body      githubv4.String
+	Url       githubv4.URI
+	UpdatedAt githubv4.DateTime
+	Category  struct {
+		Name githubv4.String
+	}
+
+	Comments struct {
+		Nodes    []discussionComment
+		PageInfo pageInfo
+	} `graphql:"comments(first: $commentFirst)"`
+}
+
+type discussionQuery struct {
+	Repository struct {
+		Discussions struct {
+			Nodes    []discussion
+			PageInfo pageInfo
+		} `graphql:"discussions(first: $discussionFirst, after: $discussionAfter, orderBy: {field: CREATED_AT, direction: ASC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type discussionCommentQuery struct {
+	Node struct {
+		Discussion struct {
+			Comments struct {
+				Nodes    []discussionComment
+				PageInfo pageInfo
+			} `graphql:"comments(first: $commentFirst, after: $commentAfter)"`
+		} `graphql:"... on Discussion"`
+	} `graphql:"node(id: $id)"`
+}
+
+type discussionReplyQuery struct {
+	Node struct {
+		DiscussionComment struct {
+			Replies struct {
+				Nodes    []discussionReply
+				PageInfo pageInfo
+			} `graphql:"replies(first: $replyFirst, after: $replyAfter)"`
+		} `graphql:"... on DiscussionComment"`
+	} `graphql:"node(id: $id)"`
+}
+
+type pullRequestReviewComment struct {
+	authorEvent
+	Body    githubv4.String
+	Url     githubv4.URI
+	Path    githubv4.String
+	ReplyTo *struct {
+		Id githubv4.ID
+	}
+}
+
+type pullRequestReview struct {
+	authorEvent
+	Body  githubv4.String
+	Url   githubv4.URI
+	State githubv4.String
+
+	Comments struct {
+		Nodes    []pullRequestReviewComment
+		PageInfo pageInfo
+	} `graphql:"comments(first: $reviewCommentFirst)"`
+}
+
+type pullRequestTimelineItem struct {
+	Typename githubv4.String `graphql:"__typename"`
+
+	IssueComment struct {
+		authorEvent
+		Body githubv4.String
+		Url  githubv4.URI
+	} `graphql:"... on IssueComment"`
+	PullRequestReview pullRequestReview `graphql:"... on PullRequestReview"`
+
+	// Status
+	ClosedEvent struct {
+		actorEvent
+	} `graphql:"... on ClosedEvent"`
+	ReopenedEvent struct {
+		actorEvent
+	} `graphql:"... on ReopenedEvent"`
+	MergedEvent struct {
+		actorEvent
+	} `graphql:"... on MergedEvent"`
+}
+
+// the conversation of a pull request, without the diffs
+type pullRequest struct {
+	authorEvent
+	Title     string
+	Body      githubv4.String
+	Url       githubv4.URI
+	UpdatedAt githubv4.DateTime
+
+	TimelineItems struct {
+		Nodes    []pullRequestTimelineItem
+		PageInfo pageInfo
+	} `graphql:"timelineItems(first: $timelineFirst, itemTypes: [ISSUE_COMMENT, PULL_REQUEST_REVIEW, CLOSED_EVENT, REOPENED_EVENT, MERGED_EVENT])"`
+}
+
+type pullRequestQuery struct {
+	Repository struct {
+		PullRequests struct {
+			Nodes    []pullRequest
+			PageInfo pageInfo
+		} `graphql:"pullRequests(first: $pullRequestFirst, after: $pullRequestAfter, orderBy: {field: CREATED_AT, direction: ASC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type pullRequestTimelineQuery struct {
+	Node struct {
+		PullRequest struct {
+			TimelineItems struct {
+				Nodes    []pullRequestTimelineItem
+				PageInfo pageInfo
+			} `graphql:"timelineItems(first: $timelineFirst, after: $timelineAfter, itemTypes: [ISSUE_COMMENT, PULL_REQUEST_REVIEW, CLOSED_EVENT, REOPENED_EVENT, MERGED_EVENT])"`
+		} `graphql:"... on PullRequest"`
+	} `graphql:"node(id: $id)"`
+}
+
+type pullRequestReviewCommentQuery struct {
+	Node struct {
+		PullRequestReview struct {
+			Comments struct {
+				Nodes    []pullRequestReviewComment
+				PageInfo pageInfo
+			} `graphql:"comments(first: $reviewCommentFirst, after: $reviewCommentAfter)"`
+		} `graphql:"... on PullRequestReview"`
+	} `graphql:"node(id: $id)"`
+}
+
 type ghostQuery struct {
 	User struct {
 		Login     githubv4.String
@@ -0,0 +1,156 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// discussionIterator iterate over the discussions of a repository, one at a
+// time. Each discussion is given with all its comments and replies, queried
+// beforehand.
+type discussionIterator struct {
+	// github graphql client
+	gc *githubv4.Client
+
+	// if since is given the iterator will skip the discussions not updated
+	// after this date
+	since time.Time
+
+	// number of comments/replies to query at a time
+	capacity int
+
+	// shared context used for all graphql queries
+	ctx context.Context
+
+	// sticky error
+	err error
+
+	query     discussionQuery
+	variables map[string]interface{}
+
+	// the current discussion, with all its comments and replies
+	current discussion
+}
+
+func newDiscussionIterator(ctx context.Context, client *githubv4.Client, capacity int, owner, project string, since time.Time) *discussionIterator {
+	return &discussionIterator{
+		gc:       client,
+		since:    since,
+		capacity: capacity,
+		ctx:      ctx,
+		variables: map[string]interface{}{
+			"owner":           githubv4.String(owner),
+			"name":            githubv4.String(project),
+			"discussionFirst": githubv4.Int(1),
+			"discussionAfter": (*githubv4.String)(nil),
+			"commentFirst":    githubv4.Int(capacity),
+			"replyFirst":      githubv4.Int(capacity),
+		},
+	}
+}
+
+// Error return last encountered error
+func (i *discussionIterator) Error() error {
+	return i.err
+}
+
+// NextDiscussion query the next discussion updated after the since date and
+// return true if there is one
+func (i *discussionIterator) NextDiscussion() bool {
+	for {
+		if i.err != nil || i.ctx.Err() != nil {
+			return false
+		}
+
+		discussions := i.query.Repository.Discussions
+		if i.variables["discussionAfter"] != (*githubv4.String)(nil) && !discussions.PageInfo.HasNextPage {
+			return false
+		}
+
+		i.query = discussionQuery{}
+		if !i.queryGithub(&i.query, i.variables) {
+			return false
+		}
+
+		discussions = i.query.Repository.Discussions
+		if len(discussions.Nodes) == 0 {
+			return false
+		}
+		// prevent from infinite loop by setting a non nil cursor
+		i.variables["discussionAfter"] = discussions.PageInfo.EndCursor
+
+		current := discussions.Nodes[0]
+		if current.UpdatedAt.Before(i.since) {
+			continue
+		}
+
+		if !i.queryComments(&current) {
+			return false
+		}
+
+		i.current = current
+		return true
+	}
+}
+
+// DiscussionValue return the actual discussion value
+func (i *discussionIterator) DiscussionValue() discussion {
+	return i.current
+}
+
+// queryComments complete a discussion with the comments and replies beyond the
+// first page
+func (i *discussionIterator) queryComments(d *discussion) bool {
+	for d.Comments.PageInfo.HasNextPage {
+		var q discussionCommentQuery
+		variables := map[string]interface{}{
+			"id":           d.Id,
+			"commentFirst": githubv4.Int(i.capacity),
+			"commentAfter": d.Comments.PageInfo.EndCursor,
+			"replyFirst":   githubv4.Int(i.capacity),
+		}
+		if !i.queryGithub(&q, variables) {
+			return false
+		}
+
+		comments := q.Node.Discussion.Comments
+		d.Comments.Nodes = append(d.Comments.Nodes, comments.Nodes...)
+		d.Comments.PageInfo = comments.PageInfo
+	}
+
+	for index := range d.Comments.Nodes {
+		comment := &d.Comments.Nodes[index]
+
+		for comment.Replies.PageInfo.HasNextPage {
+			var q discussionReplyQuery
+			variables := map[string]interface{}{
+				"id":         comment.Id,
+				"replyFirst": githubv4.Int(i.capacity),
+				"replyAfter": comment.Replies.PageInfo.EndCursor,
+			}
+			if !i.queryGithub(&q, variables) {
+				return false
+			}
+
+			replies := q.Node.DiscussionComment.Replies
+			comment.Replies.Nodes = append(comment.Replies.Nodes, replies.Nodes...)
+			comment.Replies.PageInfo = replies.PageInfo
+		}
+	}
+
+	return true
+}
+
+func (i *discussionIterator) queryGithub(query interface{}, variables map[string]interface{}) bool {
+	ctx, cancel := context.WithTimeout(i.ctx, defaultTimeout)
+	defer cancel()
+
+	if err := i.gc.Query(ctx, query, variables); err != nil {
+		i.err = err
+		return false
+	}
+
+	return true
+}
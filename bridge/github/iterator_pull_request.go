@@ -0,0 +1,156 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// pullRequestIterator iterate over the pull requests of a repository, one at
+// a time. Each pull request is given with its full conversation, queried
+// beforehand.
+type pullRequestIterator struct {
+	// github graphql client
+	gc *githubv4.Client
+
+	// if since is given the iterator will skip the pull requests not updated
+	// after this date
+	since time.Time
+
+	// number of timeline items/review comments to query at a time
+	capacity int
+
+	// shared context used for all graphql queries
+	ctx context.Context
+
+	// sticky error
+	err error
+
+	query     pullRequestQuery
+	variables map[string]interface{}
+
+	// the current pull request, with its full conversation
+	current pullRequest
+}
+
+func newPullRequestIterator(ctx context.Context, client *githubv4.Client, capacity int, owner, project string, since time.Time) *pullRequestIterator {
+	return &pullRequestIterator{
+		gc:       client,
+		since:    since,
+		capacity: capacity,
+		ctx:      ctx,
+		variables: map[string]interface{}{
+			"owner":              githubv4.String(owner),
+			"name":               githubv4.String(project),
+			"pullRequestFirst":   githubv4.Int(1),
+			"pullRequestAfter":   (*githubv4.String)(nil),
+			"timelineFirst":      githubv4.Int(capacity),
+			"reviewCommentFirst": githubv4.Int(capacity),
+		},
+	}
+}
+
+// Error return last encountered error
+func (i *pullRequestIterator) Error() error {
+	return i.err
+}
+
+// NextPullRequest query the next pull request updated after the since date
+// and return true if there is one
+func (i *pullRequestIterator) NextPullRequest() bool {
+	for {
+		if i.err != nil || i.ctx.Err() != nil {
+			return false
+		}
+
+		pullRequests := i.query.Repository.PullRequests
+		if i.variables["pullRequestAfter"] != (*githubv4.String)(nil) && !pullRequests.PageInfo.HasNextPage {
+			return false
+		}
+
+		i.query = pullRequestQuery{}
+		if !i.queryGithub(&i.query, i.variables) {
+			return false
+		}
+
+		pullRequests = i.query.Repository.PullRequests
+		if len(pullRequests.Nodes) == 0 {
+			return false
+		}
+		// prevent from infinite loop by setting a non nil cursor
+		i.variables["pullRequestAfter"] = pullRequests.PageInfo.EndCursor
+
+		current := pullRequests.Nodes[0]
+		if current.UpdatedAt.Before(i.since) {
+			continue
+		}
+
+		if !i.queryTimeline(&current) {
+			return false
+		}
+
+		i.current = current
+		return true
+	}
+}
+
+// PullRequestValue return the actual pull request value
+func (i *pullRequestIterator) PullRequestValue() pullRequest {
+	return i.current
+}
+
+// queryTimeline complete a pull request with the timeline items and review
+// comments beyond the first page
+func (i *pullRequestIterator) queryTimeline(pr *pullRequest) bool {
+	for pr.TimelineItems.PageInfo.HasNextPage {
+		var q pullRequestTimelineQuery
+		variables := map[string]interface{}{
+			"id":                 pr.Id,
+			"timelineFirst":      githubv4.Int(i.capacity),
+			"timelineAfter":      pr.TimelineItems.PageInfo.EndCursor,
+			"reviewCommentFirst": githubv4.Int(i.capacity),
+		}
+		if !i.queryGithub(&q, variables) {
+			return false
+		}
+
+		items := q.Node.PullRequest.TimelineItems
+		pr.TimelineItems.Nodes = append(pr.TimelineItems.Nodes, items.Nodes...)
+		pr.TimelineItems.PageInfo = items.PageInfo
+	}
+
+	for index := range pr.TimelineItems.Nodes {
+		review := &pr.TimelineItems.Nodes[index].PullRequestReview
+
+		for review.Comments.PageInfo.HasNextPage {
+			var q pullRequestReviewCommentQuery
+			variables := map[string]interface{}{
+				"id":                 review.Id,
+				"reviewCommentFirst": githubv4.Int(i.capacity),
+				"reviewCommentAfter": review.Comments.PageInfo.EndCursor,
+			}
+			if !i.queryGithub(&q, variables) {
+				return false
+			}
+
+			comments := q.Node.PullRequestReview.Comments
+			review.Comments.Nodes = append(review.Comments.Nodes, comments.Nodes...)
+			review.Comments.PageInfo = comments.PageInfo
+		}
+	}
+
+	return true
+}
+
+func (i *pullRequestIterator) queryGithub(query interface{}, variables map[string]interface{}) bool {
+	ctx, cancel := context.WithTimeout(i.ctx, defaultTimeout)
+	defer cancel()
+
+	if err := i.gc.Query(ctx, query, variables); err != nil {
+		i.err = err
+		return false
+	}
+
+	return true
+}
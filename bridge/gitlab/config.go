@@ -150,6 +150,9 @@ func (g *Gitlab) ValidateConfig(conf core.Configuration) error {
 	if _, ok := conf[confKeyDefaultLogin]; !ok {
 		return fmt.Errorf("missing %s key", confKeyDefaultLogin)
 	}
+	if _, err := core.ParseMapping(conf); err != nil {
+		return err
+	}
 
 	return nil
 }
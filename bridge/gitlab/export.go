@@ -106,6 +106,11 @@ func (ge *gitlabExporter) getIdentityClient(userId entity.Id) (*gitlab.Client, e
 func (ge *gitlabExporter) ExportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ExportResult, error) {
 	out := make(chan core.ExportResult)
 
+	allBugsIds, err := core.ExportedBugsIds(repo, ge.conf)
+	if err != nil {
+		return nil, err
+	}
+
 	go func() {
 		defer close(out)
 
@@ -114,8 +119,6 @@ func (ge *gitlabExporter) ExportAll(ctx context.Context, repo *cache.RepoCache,
 			allIdentitiesIds = append(allIdentitiesIds, id)
 		}
 
-		allBugsIds := repo.AllBugsIds()
-
 		for _, id := range allBugsIds {
 			select {
 			case <-ctx.Done():
@@ -244,6 +247,19 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			return
 		}
 
+		// the labels set on creation
+		if len(createOp.Labels) > 0 {
+			labels := make([]string, len(createOp.Labels))
+			for i, label := range createOp.Labels {
+				labels[i] = label.String()
+			}
+			if err := updateGitlabIssueLabels(ctx, client, ge.repositoryID, id, labels); err != nil {
+				err := errors.Wrap(err, "updating labels")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+		}
+
 		// cache bug gitlab ID and URL
 		bugGitlabID = id
 		bugGitlabIDString = idString
@@ -254,9 +270,16 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 	ge.cachedOperationIDs[bugCreationId] = bugGitlabIDString
 
 	labelSet := make(map[string]struct{})
+	for _, label := range createOp.Labels {
+		labelSet[label.String()] = struct{}{}
+	}
 	for _, op := range snapshot.Operations[1:] {
-		// ignore SetMetadata operations
-		if _, ok := op.(*bug.SetMetadataOperation); ok {
+		// ignore the operations without an equivalent in the bridged tracker
+		switch op.(type) {
+		case *bug.SetMetadataOperation, *bug.SetComponentOperation, *bug.AssigneeChangeOperation,
+			*bug.SetParentOperation, *bug.RemoveParentOperation,
+			*bug.AddChecklistItemOperation, *bug.CheckItemOperation, *bug.VoteOperation,
+			*bug.RequestReviewOperation, *bug.CustomOperation, *bug.UnknownOperation:
 			continue
 		}
 
@@ -343,6 +366,17 @@ func (ge *gitlabExporter) exportBug(ctx context.Context, b *cache.BugCache, out
 			out <- core.NewExportStatusChange(op.Id())
 			id = bugGitlabID
 
+		case *bug.MarkDuplicateOperation:
+			// the relation is not exported, the issue is only closed
+			if err := updateGitlabIssueStatus(ctx, client, ge.repositoryID, bugGitlabID, bug.ClosedStatus); err != nil {
+				err := errors.Wrap(err, "editing status")
+				out <- core.NewExportError(err, b.Id())
+				return
+			}
+
+			out <- core.NewExportStatusChange(op.Id())
+			id = bugGitlabID
+
 		case *bug.SetTitleOperation:
 			if err := updateGitlabIssueTitle(ctx, client, ge.repositoryID, bugGitlabID, op.Title); err != nil {
 				err := errors.Wrap(err, "editing title")
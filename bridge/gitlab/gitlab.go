@@ -28,6 +28,7 @@ const (
 )
 
 var _ core.BridgeImpl = &Gitlab{}
+var _ core.BugUrlMetaKeyer = &Gitlab{}
 
 type Gitlab struct{}
 
@@ -39,6 +40,10 @@ func (g *Gitlab) LoginMetaKey() string {
 	return metaKeyGitlabLogin
 }
 
+func (*Gitlab) BugUrlMetaKey() string {
+	return metaKeyGitlabUrl
+}
+
 func (Gitlab) NewImporter() core.Importer {
 	return &gitlabImporter{}
 }
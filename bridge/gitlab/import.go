@@ -20,6 +20,9 @@ import (
 type gitlabImporter struct {
 	conf core.Configuration
 
+	// how the milestones, assignees and due dates are imported
+	mapping core.Mapping
+
 	// default client
 	client *gitlab.Client
 
@@ -33,6 +36,12 @@ type gitlabImporter struct {
 func (gi *gitlabImporter) Init(_ context.Context, repo *cache.RepoCache, conf core.Configuration) error {
 	gi.conf = conf
 
+	mapping, err := core.ParseMapping(conf)
+	if err != nil {
+		return err
+	}
+	gi.mapping = mapping
+
 	creds, err := auth.List(repo,
 		auth.WithTarget(target),
 		auth.WithKind(auth.KindToken),
@@ -313,13 +322,83 @@ func (gi *gitlabImporter) ensureNote(repo *cache.RepoCache, b *cache.BugCache, n
 
 		gi.out <- core.NewImportTitleEdition(op.Id())
 
+	case NOTE_ASSIGNED, NOTE_UNASSIGNED:
+		if errResolve == nil {
+			return nil
+		}
+
+		assigned, unassigned := getAssignees(body)
+		added, err := gi.ensurePersons(repo, assigned)
+		if err != nil {
+			return err
+		}
+		removed, err := gi.ensurePersons(repo, unassigned)
+		if err != nil {
+			return err
+		}
+
+		op, err := gi.mapping.ImportAssignees(
+			b,
+			author,
+			note.CreatedAt.Unix(),
+			added,
+			removed,
+			map[string]string{
+				metaKeyGitlabId: gitlabID,
+			},
+		)
+		if err != nil || op == nil {
+			return err
+		}
+
+		gi.out <- core.NewImportAssigneeChange(op.Id())
+
+	case NOTE_CHANGED_MILESTONE, NOTE_REMOVED_MILESTONE:
+		if errResolve == nil {
+			return nil
+		}
+
+		metadata := map[string]string{
+			metaKeyGitlabId: gitlabID,
+		}
+
+		var op bug.Operation
+		switch {
+		case noteType == NOTE_CHANGED_MILESTONE:
+			op, err = gi.mapping.ImportMilestone(b, author, note.CreatedAt.Unix(), body, metadata)
+		case body != "":
+			op, err = gi.mapping.ImportMilestoneRemoval(b, author, note.CreatedAt.Unix(), body, metadata)
+		default:
+			// older Gitlab don't tell which milestone is removed
+			op, err = gi.mapping.ImportMilestone(b, author, note.CreatedAt.Unix(), "", metadata)
+		}
+		if err != nil || op == nil {
+			return err
+		}
+
+		gi.out <- core.NewImportMilestoneChange(op.Id())
+
+	case NOTE_CHANGED_DUEDATE, NOTE_REMOVED_DUEDATE:
+		if errResolve == nil {
+			return nil
+		}
+
+		op, err := gi.mapping.ImportDueDate(
+			b,
+			author,
+			note.CreatedAt.Unix(),
+			body,
+			map[string]string{
+				metaKeyGitlabId: gitlabID,
+			},
+		)
+		if err != nil || op == nil {
+			return err
+		}
+
+		gi.out <- core.NewImportDueDateChange(op.Id())
+
 	case NOTE_UNKNOWN,
-		NOTE_ASSIGNED,
-		NOTE_UNASSIGNED,
-		NOTE_CHANGED_MILESTONE,
-		NOTE_REMOVED_MILESTONE,
-		NOTE_CHANGED_DUEDATE,
-		NOTE_REMOVED_DUEDATE,
 		NOTE_LOCKED,
 		NOTE_UNLOCKED,
 		NOTE_MENTIONED_IN_ISSUE,
@@ -410,6 +489,41 @@ func (gi *gitlabImporter) ensurePerson(repo *cache.RepoCache, id int) (*cache.Id
 	return i, nil
 }
 
+// ensurePersons create the identities of the given Gitlab usernames
+func (gi *gitlabImporter) ensurePersons(repo *cache.RepoCache, usernames []string) ([]*cache.IdentityCache, error) {
+	result := make([]*cache.IdentityCache, 0, len(usernames))
+
+	for _, username := range usernames {
+		// Look first in the cache
+		i, err := repo.ResolveIdentityImmutableMetadata(metaKeyGitlabLogin, username)
+		if err == nil {
+			result = append(result, i)
+			continue
+		}
+		if entity.IsErrMultipleMatch(err) {
+			return nil, err
+		}
+
+		users, _, err := gi.client.Users.ListUsers(&gitlab.ListUsersOptions{
+			Username: gitlab.String(username),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("unknown Gitlab user %s", username)
+		}
+
+		i, err = gi.ensurePerson(repo, users[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, i)
+	}
+
+	return result, nil
+}
+
 func parseID(id int) string {
 	return fmt.Sprintf("%d", id)
 }
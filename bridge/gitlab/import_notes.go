@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"strings"
+	"time"
 
 	"github.com/xanzy/go-gitlab"
 )
@@ -101,7 +102,7 @@ func GetNoteType(n *gitlab.Note) (NoteType, string) {
 	}
 
 	if strings.HasPrefix(n.Body, "changed due date to") {
-		return NOTE_CHANGED_DUEDATE, ""
+		return NOTE_CHANGED_DUEDATE, getDueDate(n.Body)
 	}
 
 	if n.Body == "removed due date" {
@@ -109,19 +110,19 @@ func GetNoteType(n *gitlab.Note) (NoteType, string) {
 	}
 
 	if strings.HasPrefix(n.Body, "assigned to @") {
-		return NOTE_ASSIGNED, ""
+		return NOTE_ASSIGNED, n.Body
 	}
 
 	if strings.HasPrefix(n.Body, "unassigned @") {
-		return NOTE_UNASSIGNED, ""
+		return NOTE_UNASSIGNED, n.Body
 	}
 
 	if strings.HasPrefix(n.Body, "changed milestone to %") {
-		return NOTE_CHANGED_MILESTONE, ""
+		return NOTE_CHANGED_MILESTONE, getMilestone(n.Body)
 	}
 
 	if strings.HasPrefix(n.Body, "removed milestone") {
-		return NOTE_REMOVED_MILESTONE, ""
+		return NOTE_REMOVED_MILESTONE, getMilestone(n.Body)
 	}
 
 	if strings.HasPrefix(n.Body, "mentioned in issue") {
@@ -145,3 +146,63 @@ func getNewTitle(diff string) string {
 	newTitle = strings.Replace(newTitle, "+}", "", -1)
 	return strings.TrimSuffix(newTitle, "**")
 }
+
+// getDueDate parses the body of a due date change and return the date as
+// 2006-01-02, or an empty string if the date can't be read
+// example: "changed due date to January 2, 2006"
+func getDueDate(body string) string {
+	date := strings.TrimSpace(strings.TrimPrefix(body, "changed due date to"))
+	parsed, err := time.Parse("January 2, 2006", date)
+	if err != nil {
+		return ""
+	}
+	return parsed.Format("2006-01-02")
+}
+
+// getMilestone parses the body of a milestone change and return the name of
+// the milestone, or an empty string if there is none
+// examples: "changed milestone to %v1.0"
+//           "changed milestone to %\"first release\""
+//           "removed milestone %v1.0"
+func getMilestone(body string) string {
+	i := strings.Index(body, "%")
+	if i < 0 {
+		return ""
+	}
+	return strings.Trim(body[i+1:], "\"")
+}
+
+// getAssignees parses the body of an assignment change and return the
+// usernames assigned and unassigned
+// examples: "assigned to @alice"
+//           "assigned to @alice, @bob and @carol"
+//           "assigned to @alice and unassigned @bob"
+//           "unassigned @alice and @bob"
+// because Gitlab
+func getAssignees(body string) (assigned []string, unassigned []string) {
+	if strings.HasPrefix(body, "assigned to ") {
+		body = strings.TrimPrefix(body, "assigned to ")
+		parts := strings.SplitN(body, " and unassigned ", 2)
+		assigned = getUsernames(parts[0])
+		if len(parts) == 2 {
+			unassigned = getUsernames(parts[1])
+		}
+		return assigned, unassigned
+	}
+
+	return nil, getUsernames(strings.TrimPrefix(body, "unassigned "))
+}
+
+// getUsernames parses a list of usernames as "@alice, @bob and @carol"
+func getUsernames(list string) []string {
+	list = strings.Replace(list, " and ", ", ", -1)
+
+	var usernames []string
+	for _, username := range strings.Split(list, ",") {
+		username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+		if username != "" {
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
@@ -54,3 +54,36 @@ func TestGetNewTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAssignees(t *testing.T) {
+	tests := []struct {
+		body       string
+		assigned   []string
+		unassigned []string
+	}{
+		{"assigned to @alice", []string{"alice"}, nil},
+		{"assigned to @alice, @bob and @carol", []string{"alice", "bob", "carol"}, nil},
+		{"assigned to @alice and unassigned @bob", []string{"alice"}, []string{"bob"}},
+		{"unassigned @alice and @bob", nil, []string{"alice", "bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			assigned, unassigned := getAssignees(tt.body)
+			assert.Equal(t, tt.assigned, assigned)
+			assert.Equal(t, tt.unassigned, unassigned)
+		})
+	}
+}
+
+func TestGetMilestone(t *testing.T) {
+	assert.Equal(t, "v1.0", getMilestone("changed milestone to %v1.0"))
+	assert.Equal(t, "first release", getMilestone(`changed milestone to %"first release"`))
+	assert.Equal(t, "v1.0", getMilestone("removed milestone %v1.0"))
+	assert.Equal(t, "", getMilestone("removed milestone"))
+}
+
+func TestGetDueDate(t *testing.T) {
+	assert.Equal(t, "2006-01-02", getDueDate("changed due date to January 2, 2006"))
+	assert.Equal(t, "", getDueDate("changed due date to someday"))
+}
@@ -1353,8 +1353,9 @@ func getTransitionTo(tlist *TransitionList, desiredStateNameOrID string) *Transi
 	return nil
 }
 
-// DoTransition changes the "status" of an issue
-func (client *Client) DoTransition(issueKeyOrID string, transitionID string) (time.Time, error) {
+// DoTransition changes the "status" of an issue, setting the given
+// "resolution" along
+func (client *Client) DoTransition(issueKeyOrID string, transitionID string, resolution string) (time.Time, error) {
 	url := fmt.Sprintf(
 		"%s/rest/api/2/issue/%s/transitions", client.serverURL, issueKeyOrID)
 	var responseTime time.Time
@@ -1365,8 +1366,8 @@ func (client *Client) DoTransition(issueKeyOrID string, transitionID string) (ti
 	// but that is complex
 	var buffer bytes.Buffer
 	_, _ = fmt.Fprintf(&buffer,
-		`{"transition":{"id":"%s"}, "resolution": {"name": "Done"}}`,
-		transitionID)
+		`{"transition":{"id":"%s"}, "resolution": {"name": "%s"}}`,
+		transitionID, resolution)
 	request, err := http.NewRequest("POST", url, bytes.NewBuffer(buffer.Bytes()))
 	if err != nil {
 		return responseTime, err
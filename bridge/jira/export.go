@@ -137,6 +137,11 @@ func (je *jiraExporter) getClientForIdentity(userId entity.Id) (*Client, error)
 func (je *jiraExporter) ExportAll(ctx context.Context, repo *cache.RepoCache, since time.Time) (<-chan core.ExportResult, error) {
 	out := make(chan core.ExportResult)
 
+	allBugsIds, err := core.ExportedBugsIds(repo, je.conf)
+	if err != nil {
+		return nil, err
+	}
+
 	go func() {
 		defer close(out)
 
@@ -145,8 +150,6 @@ func (je *jiraExporter) ExportAll(ctx context.Context, repo *cache.RepoCache, si
 			allIdentitiesIds = append(allIdentitiesIds, id)
 		}
 
-		allBugsIds := repo.AllBugsIds()
-
 		for _, id := range allBugsIds {
 			b, err := repo.ResolveBug(id)
 			if err != nil {
@@ -288,6 +291,16 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 			return err
 		}
 
+		// the labels set on creation
+		if len(createOp.Labels) > 0 {
+			_, err = client.UpdateLabels(id, createOp.Labels, nil)
+			if err != nil {
+				err := errors.Wrap(err, "updating labels")
+				out <- core.NewExportError(err, b.Id())
+				return err
+			}
+		}
+
 		// cache bug jira ID
 		bugJiraID = id
 	}
@@ -296,8 +309,12 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 	je.cachedOperationIDs[createOp.Id()] = bugJiraID
 
 	for _, op := range snapshot.Operations[1:] {
-		// ignore SetMetadata operations
-		if _, ok := op.(*bug.SetMetadataOperation); ok {
+		// ignore the operations without an equivalent in the bridged tracker
+		switch op.(type) {
+		case *bug.SetMetadataOperation, *bug.SetComponentOperation, *bug.AssigneeChangeOperation,
+			*bug.SetParentOperation, *bug.RemoveParentOperation,
+			*bug.AddChecklistItemOperation, *bug.CheckItemOperation, *bug.VoteOperation,
+			*bug.RequestReviewOperation, *bug.CustomOperation, *bug.UnknownOperation:
 			continue
 		}
 
@@ -374,7 +391,7 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 		case *bug.SetStatusOperation:
 			jiraStatus, hasStatus := je.statusMap[opr.Status.String()]
 			if hasStatus {
-				exportTime, err = UpdateIssueStatus(client, bugJiraID, jiraStatus)
+				exportTime, err = UpdateIssueStatus(client, bugJiraID, jiraStatus, opr.Resolution)
 				if err != nil {
 					err := errors.Wrap(err, "editing status")
 					out <- core.NewExportWarning(err, b.Id())
@@ -390,6 +407,23 @@ func (je *jiraExporter) exportBug(ctx context.Context, b *cache.BugCache, out ch
 					"No jira status mapped for %.8s", opr.Status.String()), b.Id())
 			}
 
+		case *bug.MarkDuplicateOperation:
+			// the relation is not exported, the issue is only closed
+			jiraStatus, hasStatus := je.statusMap[bug.ClosedStatus.String()]
+			if hasStatus {
+				exportTime, err = UpdateIssueStatus(client, bugJiraID, jiraStatus, bug.DuplicateResolution)
+				if err != nil {
+					err := errors.Wrap(err, "editing status")
+					out <- core.NewExportWarning(err, b.Id())
+					continue
+				}
+				out <- core.NewExportStatusChange(op.Id())
+				id = bugJiraID
+			} else {
+				out <- core.NewExportError(fmt.Errorf(
+					"No jira status mapped for %.8s", bug.ClosedStatus.String()), b.Id())
+			}
+
 		case *bug.SetTitleOperation:
 			exportTime, err = client.UpdateIssueTitle(bugJiraID, opr.Title)
 			if err != nil {
@@ -450,10 +484,20 @@ func markOperationAsExported(b *cache.BugCache, target entity.Id, jiraID, jiraPr
 	return err
 }
 
+// jiraResolutions are the names of the default JIRA resolutions, used when
+// closing an issue. "Done" is used for a bug without resolution.
+var jiraResolutions = map[bug.Resolution]string{
+	bug.FixedResolution:      "Fixed",
+	bug.WontFixResolution:    "Won't Fix",
+	bug.DuplicateResolution:  "Duplicate",
+	bug.InvalidResolution:    "Incomplete",
+	bug.WorksForMeResolution: "Cannot Reproduce",
+}
+
 // UpdateIssueStatus attempts to change the "status" field by finding a
 // transition which achieves the desired state and then performing that
 // transition
-func UpdateIssueStatus(client *Client, issueKeyOrID string, desiredStateNameOrID string) (time.Time, error) {
+func UpdateIssueStatus(client *Client, issueKeyOrID string, desiredStateNameOrID string, resolution bug.Resolution) (time.Time, error) {
 	var responseTime time.Time
 
 	tlist, err := client.GetTransitions(issueKeyOrID)
@@ -466,7 +510,12 @@ func UpdateIssueStatus(client *Client, issueKeyOrID string, desiredStateNameOrID
 		return responseTime, errTransitionNotFound
 	}
 
-	responseTime, err = client.DoTransition(issueKeyOrID, transition.ID)
+	resolutionName, ok := jiraResolutions[resolution]
+	if !ok {
+		resolutionName = "Done"
+	}
+
+	responseTime, err = client.DoTransition(issueKeyOrID, transition.ID, resolutionName)
 	if err != nil {
 		return responseTime, err
 	}
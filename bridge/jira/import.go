@@ -534,9 +534,10 @@ func (ji *jiraImporter) ensureChange(repo *cache.RepoCache, b *cache.BugCache, e
 					ji.out <- core.NewImportStatusChange(op.Id())
 
 				case bug.ClosedStatus.String():
-					op, err := b.CloseRaw(
+					op, err := b.CloseWithResolutionRaw(
 						author,
 						entry.Created.Unix(),
+						jiraResolution(entry.Items),
 						map[string]string{
 							metaKeyJiraId:        entry.ID,
 							metaKeyJiraDerivedId: derivedID,
@@ -656,3 +657,20 @@ func removeEmpty(values []string) []string {
 	}
 	return output
 }
+
+// jiraResolution find the resolution set along a change of status in a
+// changelog entry. The resolutions that don't have an equivalent, like the
+// generic "Done", give no resolution.
+func jiraResolution(items []ChangeLogItem) bug.Resolution {
+	for _, item := range items {
+		if item.Field != "resolution" {
+			continue
+		}
+		for resolution, name := range jiraResolutions {
+			if name == item.ToString {
+				return resolution
+			}
+		}
+	}
+	return bug.NoResolution
+}
@@ -0,0 +1,68 @@
+package bug
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// AuditCommit is a commit in the chain of a bug, with the git objects that
+// make its history tamper-evident: each commit hash cover its parent and its
+// tree, the tree cover the blob of the operations, and the id of each
+// operation is the hash of its serialized data.
+type AuditCommit struct {
+	Commit git.Hash `json:"commit"`
+	// the previous commit in the chain, empty for the first one
+	Parent git.Hash `json:"parent,omitempty"`
+	Tree   git.Hash `json:"tree"`
+	// the blob holding the OperationPack
+	Blob      git.Hash                   `json:"blob"`
+	EditTime  lamport.Time               `json:"edit_time"`
+	Signature repository.CommitSignature `json:"signature"`
+	// the signature of the operations, made with the signer of the author
+	OpsSignature OpsSignature `json:"ops_signature"`
+
+	Operations []Operation `json:"operations"`
+}
+
+// Audit return the chain of commits of the bug, in order, with their hashes
+// and signatures. Only the committed operations are covered.
+func (bug *Bug) Audit(repo repository.Repo) ([]AuditCommit, error) {
+	result := make([]AuditCommit, 0, len(bug.packs))
+
+	var parent git.Hash
+
+	for _, pack := range bug.packs {
+		tree, blob, signatureBlob, err := packBlobs(repo, pack)
+		if err != nil {
+			return nil, errors.Wrapf(err, "commit %s", pack.commitHash)
+		}
+
+		signature, err := repo.ReadCommitSignature(pack.commitHash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "commit %s", pack.commitHash)
+		}
+
+		opsSignature, err := verifyOperationPack(repo, pack, blob, signatureBlob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "commit %s", pack.commitHash)
+		}
+
+		result = append(result, AuditCommit{
+			Commit:       pack.commitHash,
+			Parent:       parent,
+			Tree:         tree,
+			Blob:         blob,
+			EditTime:     pack.editTime,
+			Signature:    signature,
+			OpsSignature: opsSignature,
+			Operations:   pack.Operations,
+		})
+
+		parent = pack.commitHash
+	}
+
+	return result, nil
+}
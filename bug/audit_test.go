@@ -0,0 +1,141 @@
+package bug
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestAudit(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	err := rene.Commit(repo)
+	require.NoError(t, err)
+
+	unix := time.Now().Unix()
+
+	b, create, err := Create(rene, unix, "title", "message")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit(repo))
+
+	comment := NewAddCommentOp(rene, unix, "comment", nil)
+	b.Append(comment)
+	labels := NewLabelChangeOperation(rene, unix, []Label{"bug"}, nil)
+	b.Append(labels)
+	require.NoError(t, b.Commit(repo))
+
+	// not committed yet, so out of the audit
+	b.Append(NewSetTitleOp(rene, unix, "other", "title"))
+
+	commits, err := b.Audit(repo)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	require.Equal(t, b.Id().String(), commits[0].Commit.String())
+	require.Empty(t, commits[0].Parent)
+	require.Equal(t, commits[0].Commit, commits[1].Parent)
+	require.Equal(t, []Operation{create}, commits[0].Operations)
+	require.Equal(t, []Operation{comment, labels}, commits[1].Operations)
+
+	for _, commit := range commits {
+		tree, err := repo.GetTreeHash(commit.Commit)
+		require.NoError(t, err)
+		require.Equal(t, tree, commit.Tree)
+
+		entries, err := repo.ListEntries(commit.Tree)
+		require.NoError(t, err)
+		require.Contains(t, entries, repository.TreeEntry{
+			ObjectType: repository.Blob, Hash: commit.Blob, Name: opsEntryName,
+		})
+
+		require.Equal(t, repository.SignatureNone, commit.Signature.Status)
+	}
+
+	require.True(t, commits[0].EditTime < commits[1].EditTime)
+}
+
+func TestAuditOpsSignature(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	// an in-process ssh-agent holding the key
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: private}))
+
+	dir, err := ioutil.TempDir("", "git-bug-agent")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "agent.sock"))
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _ = agent.ServeAgent(keyring, conn) }()
+		}
+	}()
+
+	require.NoError(t, os.Setenv("SSH_AUTH_SOCK", listener.Addr().String()))
+	defer os.Unsetenv("SSH_AUTH_SOCK")
+
+	sshSigner, err := ssh.NewSignerFromKey(private)
+	require.NoError(t, err)
+	fingerprint := ssh.FingerprintSHA256(sshSigner.PublicKey())
+
+	signer, err := identity.NewSigner("ssh:" + fingerprint)
+	require.NoError(t, err)
+	require.NoError(t, identity.SetSigner(repo, signer, false))
+
+	key, err := signer.Key()
+	require.NoError(t, err)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repo))
+
+	unix := time.Now().Unix()
+
+	// signed, but the key is not registered on the author yet
+	b, _, err := Create(rene, unix, "title", "message")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit(repo))
+
+	rene.Mutate(func(m identity.Mutator) identity.Mutator {
+		m.Keys = append(m.Keys, key)
+		return m
+	})
+	require.NoError(t, rene.Commit(repo))
+
+	b.Append(NewAddCommentOp(rene, unix, "comment", nil))
+	require.NoError(t, b.Commit(repo))
+
+	commits, err := b.Audit(repo)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	require.Equal(t, OpsSignature{Status: repository.SignatureUnknownValidity, Key: fingerprint}, commits[0].OpsSignature)
+	require.Equal(t, OpsSignature{Status: repository.SignatureGood, Key: fingerprint}, commits[1].OpsSignature)
+
+	// the signed bug still read fine
+	_, err = ReadLocalBug(repo, b.Id())
+	require.NoError(t, err)
+}
@@ -0,0 +1,117 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// BatchWriter accumulate the operations appended to multiple bugs and write
+// them in git as late as possible, for mass operation creation like a bridge
+// import.
+//
+// Instead of one commit each time an operation is added, each bug get a single
+// commit holding all of its pending operations when the BatchWriter is flushed,
+// and all the git references are updated at once.
+type BatchWriter struct {
+	repo repository.ClockedRepo
+
+	// the number of pending operations that trigger an automatic flush,
+	// 0 means that only an explicit Flush will write in git
+	maxPending int
+
+	// bugs with pending operations, in the order they have been added
+	pending []Interface
+	// the number of pending operations of each bug in pending. A new bug has
+	// no id until it is written, so the bugs are keyed by themselves.
+	counts map[*Bug]int
+	// the total of counts
+	total int
+}
+
+// NewBatchWriter create a new BatchWriter that will automatically flush when
+// maxPending operations are waiting to be written. Use 0 to only flush
+// explicitly.
+func NewBatchWriter(repo repository.ClockedRepo, maxPending int) *BatchWriter {
+	return &BatchWriter{
+		repo:       repo,
+		maxPending: maxPending,
+		counts:     make(map[*Bug]int),
+	}
+}
+
+// Append an operation into the staging area of a bug, to be written on the
+// next flush.
+func (bw *BatchWriter) Append(b Interface, op Operation) error {
+	b.Append(op)
+	return bw.Add(b)
+}
+
+// Add register a bug that has been modified outside of the BatchWriter (for
+// example with the convenience functions of this package) so that its
+// staging area get written on the next flush.
+func (bw *BatchWriter) Add(b Interface) error {
+	raw := bugFromInterface(b)
+
+	count, found := bw.counts[raw]
+	if !found {
+		bw.pending = append(bw.pending, b)
+	}
+	bw.counts[raw] = len(raw.staging.Operations)
+	bw.total += len(raw.staging.Operations) - count
+
+	if bw.maxPending > 0 && bw.total >= bw.maxPending {
+		return bw.Flush()
+	}
+
+	return nil
+}
+
+// Pending return the number of operations waiting to be written
+func (bw *BatchWriter) Pending() int {
+	return bw.total
+}
+
+// Flush write all the pending operations in git, with one commit per bug, and
+// update the git references.
+func (bw *BatchWriter) Flush() error {
+	refs := make(map[string]git.Hash, len(bw.pending))
+
+	for _, b := range bw.pending {
+		if !b.NeedCommit() {
+			continue
+		}
+
+		var err error
+		switch b := b.(type) {
+		case *Bug:
+			err = b.commitStaging(bw.repo)
+		case *WithSnapshot:
+			err = b.commitStaging(bw.repo)
+		default:
+			panic("missing type case")
+		}
+		if err != nil {
+			// still publish the bugs written so far, to not leave dangling commits
+			if errRefs := bw.repo.UpdateRefs(refs); errRefs != nil {
+				return fmt.Errorf("%v, and failed to update the bug references: %v", err, errRefs)
+			}
+			return err
+		}
+
+		raw := bugFromInterface(b)
+		refs[raw.ref()] = raw.lastCommit
+	}
+
+	err := bw.repo.UpdateRefs(refs)
+	if err != nil {
+		return fmt.Errorf("failed to update the bug references: %v", err)
+	}
+
+	bw.pending = nil
+	bw.counts = make(map[*Bug]int)
+	bw.total = 0
+
+	return nil
+}
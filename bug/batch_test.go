@@ -0,0 +1,89 @@
+package bug
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBatchWriter(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	bw := NewBatchWriter(repo, 0)
+
+	bug1 := NewBug()
+	require.NoError(t, bw.Append(bug1, NewCreateOp(rene, unix, "title1", "message", nil)))
+	require.NoError(t, bw.Append(bug1, NewAddCommentOp(rene, unix, "comment1", nil)))
+	require.NoError(t, bw.Append(bug1, NewAddCommentOp(rene, unix, "comment2", nil)))
+
+	bug2 := &WithSnapshot{Bug: NewBug()}
+	require.NoError(t, bw.Append(bug2, NewCreateOp(rene, unix, "title2", "message", nil)))
+	_, err := AddComment(bug2, rene, unix, "comment3")
+	require.NoError(t, err)
+	require.NoError(t, bw.Add(bug2))
+
+	require.Equal(t, 5, bw.Pending())
+
+	// nothing is written before the flush
+	ids, err := ListLocalIds(repo)
+	require.NoError(t, err)
+	require.Len(t, ids, 0)
+
+	require.NoError(t, bw.Flush())
+	require.Equal(t, 0, bw.Pending())
+	require.False(t, bug1.NeedCommit())
+	require.False(t, bug2.NeedCommit())
+	require.Equal(t, bug2.Id(), bug2.Snapshot().Id())
+
+	ids, err = ListLocalIds(repo)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	// a single commit per bug has been written
+	read1, err := ReadLocalBug(repo, bug1.Id())
+	require.NoError(t, err)
+	require.Len(t, read1.packs, 1)
+	require.Len(t, read1.packs[0].Operations, 3)
+
+	read2, err := ReadLocalBug(repo, bug2.Id())
+	require.NoError(t, err)
+	require.Len(t, read2.packs, 1)
+	require.Len(t, read2.packs[0].Operations, 2)
+
+	// the next flush add a commit on top of the existing history
+	require.NoError(t, bw.Append(bug1, NewSetTitleOp(rene, unix, "title1bis", "title1")))
+	require.NoError(t, bw.Flush())
+
+	read1, err = ReadLocalBug(repo, bug1.Id())
+	require.NoError(t, err)
+	require.Len(t, read1.packs, 2)
+	require.Equal(t, "title1bis", read1.Compile().Title)
+}
+
+func TestBatchWriterAutoFlush(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	bw := NewBatchWriter(repo, 3)
+
+	bug1 := NewBug()
+	require.NoError(t, bw.Append(bug1, NewCreateOp(rene, unix, "title", "message", nil)))
+	require.NoError(t, bw.Append(bug1, NewAddCommentOp(rene, unix, "comment1", nil)))
+	require.True(t, bug1.NeedCommit())
+
+	// reaching the limit trigger the flush
+	require.NoError(t, bw.Append(bug1, NewAddCommentOp(rene, unix, "comment2", nil)))
+	require.False(t, bug1.NeedCommit())
+	require.Equal(t, 0, bw.Pending())
+	require.Len(t, bug1.packs, 1)
+}
@@ -4,7 +4,9 @@ package bug
 import (
 	"encoding/json"
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -103,17 +105,21 @@ func FindLocalBug(repo repository.ClockedRepo, prefix string) (*Bug, error) {
 // ReadLocalBug will read a local bug from its hash
 func ReadLocalBug(repo repository.ClockedRepo, id entity.Id) (*Bug, error) {
 	ref := bugsRefPattern + id.String()
-	return readBug(repo, ref)
+	return readBug(repo, identity.NewSimpleResolver(repo), ref, false)
 }
 
 // ReadRemoteBug will read a remote bug from its hash
 func ReadRemoteBug(repo repository.ClockedRepo, remote string, id string) (*Bug, error) {
 	ref := fmt.Sprintf(bugsRemoteRefPattern, remote) + id
-	return readBug(repo, ref)
+	return readBug(repo, identity.NewRemoteResolver(repo, remote), ref, true)
 }
 
-// readBug will read and parse a Bug from git
-func readBug(repo repository.ClockedRepo, ref string) (*Bug, error) {
+// readBug will read and parse a Bug from git, loading the identities through
+// the given resolver. If untrusted is true, as for the bugs coming from a
+// remote, the operations are checked against the JSON schema, and the bug is
+// rejected if its lamport times are absurdly ahead of the local clocks or if
+// it holds the same OperationPack twice.
+func readBug(repo repository.ClockedRepo, resolver identity.Resolver, ref string, untrusted bool) (*Bug, error) {
 	refSplit := strings.Split(ref, "/")
 	id := entity.Id(refSplit[len(refSplit)-1])
 
@@ -121,18 +127,32 @@ func readBug(repo repository.ClockedRepo, ref string) (*Bug, error) {
 		return nil, errors.Wrap(err, "invalid ref ")
 	}
 
-	hashes, err := repo.ListCommits(ref)
+	return readBugRev(repo, resolver, id, ref, untrusted)
+}
+
+// readBugRev will read and parse a Bug from the chain of commits ending at
+// rev, either a ref or a commit hash
+func readBugRev(repo repository.ClockedRepo, resolver identity.Resolver, id entity.Id, rev string, untrusted bool) (*Bug, error) {
+	hashes, err := repo.ListCommits(rev)
 
 	// TODO: this is not perfect, it might be a command invoke error
 	if err != nil {
 		return nil, ErrBugNotExist
 	}
 
+	// the id is the hash of the first commit, missing in a shallow clone
+	if len(hashes) > 0 && string(hashes[0]) != id.String() {
+		return nil, entity.NewErrIncompleteHistory("bug", id)
+	}
+
 	bug := Bug{
 		id:       id,
 		editTime: 0,
 	}
 
+	// the blobs of the OperationPacks already read
+	packBlobs := make(map[git.Hash]git.Hash)
+
 	// Load each OperationPack
 	for _, hash := range hashes {
 		entries, err := repo.ListEntries(hash)
@@ -196,12 +216,15 @@ func readBug(repo repository.ClockedRepo, ref string) (*Bug, error) {
 			bug.editTime = lamport.Time(editTime)
 		}
 
-		// Update the clocks
-		if err := repo.WitnessCreate(bug.createTime); err != nil {
-			return nil, errors.Wrap(err, "failed to update create lamport clock")
-		}
-		if err := repo.WitnessEdit(bug.editTime); err != nil {
-			return nil, errors.Wrap(err, "failed to update edit lamport clock")
+		if untrusted {
+			if err := checkClockDrift(repo, lamport.Time(createTime), lamport.Time(editTime)); err != nil {
+				return nil, errors.Wrapf(err, "commit %s", hash)
+			}
+
+			if previous, ok := packBlobs[opsEntry.Hash]; ok {
+				return nil, fmt.Errorf("duplicated OperationPack in commits %s and %s", previous, hash)
+			}
+			packBlobs[opsEntry.Hash] = hash
 		}
 
 		data, err := repo.ReadData(opsEntry.Hash)
@@ -209,6 +232,12 @@ func readBug(repo repository.ClockedRepo, ref string) (*Bug, error) {
 			return nil, errors.Wrap(err, "failed to read git blob data")
 		}
 
+		if untrusted {
+			if err := validateOperationPackData(data); err != nil {
+				return nil, errors.Wrapf(err, "invalid OperationPack at hash %s", hash)
+			}
+		}
+
 		opp := &OperationPack{}
 		err = json.Unmarshal(data, &opp)
 
@@ -216,14 +245,22 @@ func readBug(repo repository.ClockedRepo, ref string) (*Bug, error) {
 			return nil, errors.Wrap(err, "failed to decode OperationPack json")
 		}
 
-		// tag the pack with the commit hash
+		// tag the pack with the commit hash and its edit time
 		opp.commitHash = hash
+		opp.editTime = lamport.Time(editTime)
 
 		bug.packs = append(bug.packs, *opp)
 	}
 
+	// Update the clocks, once the whole bug has been read
+	if err := repo.WitnessCreate(bug.createTime); err != nil {
+		return nil, errors.Wrap(err, "failed to update create lamport clock")
+	}
+	if err := repo.WitnessEdit(bug.editTime); err != nil {
+		return nil, errors.Wrap(err, "failed to update edit lamport clock")
+	}
+
 	// Make sure that the identities are properly loaded
-	resolver := identity.NewSimpleResolver(repo)
 	err = bug.EnsureIdentities(resolver)
 	if err != nil {
 		return nil, err
@@ -239,17 +276,21 @@ type StreamedBug struct {
 
 // ReadAllLocalBugs read and parse all local bugs
 func ReadAllLocalBugs(repo repository.ClockedRepo) <-chan StreamedBug {
-	return readAllBugs(repo, bugsRefPattern)
+	return readAllBugs(repo, identity.NewSimpleResolver(repo), bugsRefPattern, false)
 }
 
 // ReadAllRemoteBugs read and parse all remote bugs for a given remote
 func ReadAllRemoteBugs(repo repository.ClockedRepo, remote string) <-chan StreamedBug {
 	refPrefix := fmt.Sprintf(bugsRemoteRefPattern, remote)
-	return readAllBugs(repo, refPrefix)
+	return readAllBugs(repo, identity.NewRemoteResolver(repo, remote), refPrefix, true)
 }
 
 // Read and parse all available bug with a given ref prefix
-func readAllBugs(repo repository.ClockedRepo, refPrefix string) <-chan StreamedBug {
+//
+// Bugs are read in parallel by a pool of workers bounded by GOMAXPROCS, so
+// they are not streamed in any particular order. The stream stop at the
+// first error.
+func readAllBugs(repo repository.ClockedRepo, resolver identity.Resolver, refPrefix string, untrusted bool) <-chan StreamedBug {
 	out := make(chan StreamedBug)
 
 	go func() {
@@ -261,16 +302,55 @@ func readAllBugs(repo repository.ClockedRepo, refPrefix string) <-chan StreamedB
 			return
 		}
 
-		for _, ref := range refs {
-			b, err := readBug(repo, ref)
+		refsChan := make(chan string)
+		// closed on the first error to stop the workers
+		done := make(chan struct{})
+		var doneOnce sync.Once
 
-			if err != nil {
-				out <- StreamedBug{Err: err}
-				return
-			}
+		var wg sync.WaitGroup
+
+		workers := runtime.GOMAXPROCS(0)
+		if workers > len(refs) {
+			workers = len(refs)
+		}
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for ref := range refsChan {
+					b, err := readBug(repo, resolver, ref, untrusted)
+
+					if err != nil {
+						select {
+						case out <- StreamedBug{Err: err}:
+						case <-done:
+						}
+						doneOnce.Do(func() { close(done) })
+						return
+					}
+
+					select {
+					case out <- StreamedBug{Bug: b}:
+					case <-done:
+						return
+					}
+				}
+			}()
+		}
 
-			out <- StreamedBug{Bug: b}
+	feed:
+		for _, ref := range refs {
+			select {
+			case refsChan <- ref:
+			case <-done:
+				break feed
+			}
 		}
+		close(refsChan)
+
+		wg.Wait()
 	}()
 
 	return out
@@ -318,9 +398,9 @@ func (bug *Bug) Validate() error {
 		}
 	}
 
-	// The very first Op should be a CreateOp
+	// The very first Op should be a CreateOp, unless it can't be read
 	firstOp := bug.FirstOp()
-	if firstOp == nil || firstOp.base().OperationType != CreateOp {
+	if firstOp == nil || firstOp.base().OperationType != CreateOp && !isUnknownOp(firstOp) {
 		return fmt.Errorf("first operation should be a Create op")
 	}
 
@@ -344,7 +424,7 @@ func (bug *Bug) Validate() error {
 		ids[it.Value().Id()] = struct{}{}
 	}
 
-	if createCount != 1 {
+	if createCount > 1 || createCount == 0 && !isUnknownOp(firstOp) {
 		return fmt.Errorf("only one Create op allowed")
 	}
 
@@ -356,13 +436,49 @@ func (bug *Bug) Append(op Operation) {
 	bug.staging.Append(op)
 }
 
+// StagingSize return the number of operations in the staging area
+func (bug *Bug) StagingSize() int {
+	return len(bug.staging.Operations)
+}
+
+// StagedOperations return the operations of the staging area, in the order
+// they have been appended
+func (bug *Bug) StagedOperations() []Operation {
+	return bug.staging.Operations
+}
+
+// DiscardStaging drop the operations of the staging area appended after the
+// first n ones
+func (bug *Bug) DiscardStaging(n int) {
+	if n < len(bug.staging.Operations) {
+		bug.staging.Operations = bug.staging.Operations[:n]
+	}
+}
+
 // Commit write the staging area in Git and move the operations to the packs
 func (bug *Bug) Commit(repo repository.ClockedRepo) error {
+	err := bug.commitStaging(repo)
+	if err != nil {
+		return err
+	}
+
+	// Create or update the Git reference for this bug
+	// When pushing later, the remote will ensure that this ref update
+	// is fast-forward, that is no data has been overwritten
+	return repo.UpdateRef(bug.ref(), bug.lastCommit)
+}
 
+// commitStaging write the staging area in Git and move the operations to the
+// packs, without updating the Git reference of the bug.
+func (bug *Bug) commitStaging(repo repository.ClockedRepo) error {
 	if !bug.NeedCommit() {
 		return fmt.Errorf("can't commit a bug with no pending operation")
 	}
 
+	if bug.HasUnknownOperations() {
+		return ErrNewerFormat
+	}
+
 	if err := bug.Validate(); err != nil {
 		return errors.Wrap(err, "can't commit a bug with invalid data")
 	}
@@ -385,6 +501,19 @@ func (bug *Bug) Commit(repo repository.ClockedRepo) error {
 		{ObjectType: repository.Blob, Hash: bug.rootPack, Name: rootEntryName},
 	}
 
+	// Sign the ops, if a signer is configured
+	data, err := repo.ReadData(hash)
+	if err != nil {
+		return err
+	}
+	signatureEntry, err := signOperationPack(repo, data)
+	if err != nil {
+		return err
+	}
+	if signatureEntry != nil {
+		tree = append(tree, *signatureEntry)
+	}
+
 	// Reference, if any, all the files required by the ops
 	// Git will check that they actually exist in the storage and will make sure
 	// to push/pull them as needed.
@@ -459,23 +588,19 @@ func (bug *Bug) Commit(repo repository.ClockedRepo) error {
 		bug.id = entity.Id(hash)
 	}
 
-	// Create or update the Git reference for this bug
-	// When pushing later, the remote will ensure that this ref update
-	// is fast-forward, that is no data has been overwritten
-	ref := fmt.Sprintf("%s%s", bugsRefPattern, bug.id)
-	err = repo.UpdateRef(ref, hash)
-
-	if err != nil {
-		return err
-	}
-
 	bug.staging.commitHash = hash
+	bug.staging.editTime = bug.editTime
 	bug.packs = append(bug.packs, bug.staging)
 	bug.staging = OperationPack{}
 
 	return nil
 }
 
+// ref return the Git reference of the bug
+func (bug *Bug) ref() string {
+	return fmt.Sprintf("%s%s", bugsRefPattern, bug.id)
+}
+
 func (bug *Bug) CommitAsNeeded(repo repository.ClockedRepo) error {
 	if !bug.NeedCommit() {
 		return nil
@@ -604,6 +729,47 @@ func (bug *Bug) Merge(repo repository.Repo, other Interface) (bool, error) {
 	return true, nil
 }
 
+// TimedOperation is a committed operation, along with the edit lamport time
+// of the commit holding it
+type TimedOperation struct {
+	Operation
+	EditTime lamport.Time
+}
+
+// CommittedOperations return the committed operations of the bug, in order,
+// with the edit lamport time of their commit
+func (bug *Bug) CommittedOperations() []TimedOperation {
+	var result []TimedOperation
+
+	for _, pack := range bug.packs {
+		for _, op := range pack.Operations {
+			result = append(result, TimedOperation{Operation: op, EditTime: pack.editTime})
+		}
+	}
+
+	return result
+}
+
+// UnpublishedOperations return the committed operations of the bug that the
+// remote doesn't have, as known from its remote-tracking reference after the
+// last fetch or push. For a bug the remote doesn't have at all, all the
+// operations are returned.
+func (bug *Bug) UnpublishedOperations(repo repository.Repo, remote string) ([]Operation, error) {
+	published, err := bug.publishedCommits(repo, []string{remote})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Operation
+	for _, pack := range bug.packs {
+		if !published[pack.commitHash] {
+			result = append(result, pack.Operations...)
+		}
+	}
+
+	return result, nil
+}
+
 // Id return the Bug identifier
 func (bug *Bug) Id() entity.Id {
 	if bug.id == "" {
@@ -662,6 +828,17 @@ func (bug *Bug) LastOp() Operation {
 
 // Compile a bug in a easily usable snapshot
 func (bug *Bug) Compile() Snapshot {
+	return bug.CompileFiltered(nil)
+}
+
+// OperationFilter tell if an operation should be applied when compiling a
+// snapshot
+type OperationFilter func(op Operation) bool
+
+// CompileFiltered compile a bug in a easily usable snapshot, skipping the
+// operations rejected by the filter. The first operation is always applied.
+// A nil filter accept every operation.
+func (bug *Bug) CompileFiltered(filter OperationFilter) Snapshot {
 	snap := Snapshot{
 		id:     bug.id,
 		Status: OpenStatus,
@@ -669,8 +846,13 @@ func (bug *Bug) Compile() Snapshot {
 
 	it := NewOperationIterator(bug)
 
+	first := true
 	for it.Next() {
 		op := it.Value()
+		if !first && filter != nil && !filter(op) {
+			continue
+		}
+		first = false
 		op.Apply(&snap)
 		snap.Operations = append(snap.Operations, op)
 	}
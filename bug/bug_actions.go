@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
 	"github.com/pkg/errors"
 )
 
@@ -15,12 +17,143 @@ func Fetch(repo repository.Repo, remote string) (string, error) {
 	remoteRefSpec := fmt.Sprintf(bugsRemoteRefPattern, remote)
 	fetchRefSpec := fmt.Sprintf("%s*:%s*", bugsRefPattern, remoteRefSpec)
 
-	return repo.FetchRefs(remote, fetchRefSpec)
+	stdout1, err := repo.FetchRefs(remote, fetchRefSpec)
+	if err != nil {
+		return stdout1, err
+	}
+
+	remoteTombstonesRefSpec := fmt.Sprintf(tombstonesRemoteRefPattern, remote)
+	fetchTombstonesRefSpec := fmt.Sprintf("%s*:%s*", tombstonesRefPattern, remoteTombstonesRefSpec)
+
+	stdout2, err := repo.FetchRefs(remote, fetchTombstonesRefSpec)
+	if err != nil {
+		return stdout2, err
+	}
+
+	return stdout1 + stdout2, nil
 }
 
 // Push update a remote with the local changes
 func Push(repo repository.Repo, remote string) (string, error) {
-	return repo.PushRefs(remote, bugsRefPattern+"*")
+	stdout1, err := repo.PushRefs(remote, bugsRefPattern+"*")
+	// the refs accepted by the remote are updated even if others are rejected
+	errTracking := updateRemoteTracking(repo, remote, stdout1)
+	if err != nil {
+		return stdout1, err
+	}
+	if errTracking != nil {
+		return stdout1, errTracking
+	}
+
+	stdout2, err := repo.PushRefs(remote, tombstonesRefPattern+"*")
+	if err != nil {
+		return stdout2, err
+	}
+
+	return stdout1 + stdout2, nil
+}
+
+// updateRemoteTracking set the remote-tracking references of the bugs
+// accepted by a push, as listed in its output, to the local references, as
+// git does for the branches, so that they tell what the remote has until the
+// next fetch. Nothing is done when pushing to an URL instead of a configured
+// remote.
+func updateRemoteTracking(repo repository.Repo, remote string, pushOutput string) error {
+	remotes, err := repo.GetRemotes()
+	if err != nil {
+		return err
+	}
+	if _, ok := remotes[remote]; !ok {
+		return nil
+	}
+
+	remoteRefSpec := fmt.Sprintf(bugsRemoteRefPattern, remote)
+	for _, ref := range pushedRefs(pushOutput, bugsRefPattern) {
+		err = repo.CopyRef(ref, remoteRefSpec+strings.TrimPrefix(ref, bugsRefPattern))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pushedRefs return the references with the given prefix updated on the
+// remote, from the output of a push: each line list a reference as
+// "<status> <ref> -> <ref>", and the rejected ones start with a "!".
+func pushedRefs(pushOutput string, prefix string) []string {
+	var result []string
+
+	for _, line := range strings.Split(pushOutput, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		// the destination is the last one
+		var ref string
+		for _, field := range strings.Fields(line) {
+			if strings.HasPrefix(field, prefix) {
+				ref = field
+			}
+		}
+		if ref != "" {
+			result = append(result, ref)
+		}
+	}
+
+	return result
+}
+
+// PruneRemote remove the remote-tracking references of the bugs that don't
+// exist anymore on the remote or that have been removed with a tombstone, and
+// return the corresponding ids.
+// This does not change the local bugs state.
+func PruneRemote(repo repository.Repo, remote string) ([]entity.Id, error) {
+	upstreamRefs, err := repo.ListRemoteRefs(remote, bugsRefPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := make(map[entity.Id]struct{}, len(upstreamRefs))
+	for _, id := range refsToIds(upstreamRefs) {
+		upstream[id] = struct{}{}
+	}
+
+	remoteRefSpec := fmt.Sprintf(bugsRemoteRefPattern, remote)
+	trackingRefs, err := repo.ListRefs(remoteRefSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstones, err := ListLocalTombstones(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make(map[entity.Id]struct{}, len(tombstones))
+	for _, id := range tombstones {
+		removed[id] = struct{}{}
+	}
+
+	var pruned []entity.Id
+	for _, ref := range trackingRefs {
+		refSplit := strings.Split(ref, "/")
+		id := entity.Id(refSplit[len(refSplit)-1])
+		_, isUpstream := upstream[id]
+		_, isRemoved := removed[id]
+		if isUpstream && !isRemoved {
+			continue
+		}
+
+		err = repo.RemoveRef(ref)
+		if err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, id)
+	}
+
+	return pruned, nil
 }
 
 // Pull will do a Fetch + MergeAll
@@ -31,16 +164,24 @@ func Pull(repo repository.ClockedRepo, remote string) error {
 		return err
 	}
 
-	for merge := range MergeAll(repo, remote) {
+	// the merge goes on after a failure, as a rejected tombstone doesn't
+	// prevent the other bugs to be merged: drain all the results before
+	// returning the first failure
+	var result error
+
+	for merge := range MergeAll(repo, remote, nil) {
+		if result != nil {
+			continue
+		}
 		if merge.Err != nil {
-			return merge.Err
+			result = merge.Err
 		}
 		if merge.Status == entity.MergeStatusInvalid {
-			return errors.Errorf("merge failure: %s", merge.Reason)
+			result = errors.Errorf("merge failure: %s", merge.Reason)
 		}
 	}
 
-	return nil
+	return result
 }
 
 // MergeAll will merge all the available remote bug:
@@ -50,12 +191,32 @@ func Pull(repo repository.ClockedRepo, remote string) error {
 // - if the local bug has new commits but the remote don't, nothing is changed
 // - if both local and remote bug have new commits (that is, we have a concurrent edition),
 //   new local commits are rewritten at the head of the remote history (that is, a rebase)
-func MergeAll(repo repository.ClockedRepo, remote string) <-chan entity.MergeResult {
+//
+// Beforehand, the tombstones of the remote are merged: if allowed by the removal
+// policy, the corresponding bugs are removed. A bug with a local tombstone is
+// never merged back.
+//
+// The remote bugs with lamport times absurdly ahead of the local clocks, with
+// a duplicated OperationPack, or replaying a local bug under another id are
+// reported as invalid and not merged. They stay quarantined in the remote
+// refs. To find the replays, rootPacks is brought up to date and completed with
+// the new bugs; if nil, the index is built from all the local bugs.
+func MergeAll(repo repository.ClockedRepo, remote string, rootPacks RootPacks) <-chan entity.MergeResult {
 	out := make(chan entity.MergeResult)
 
 	go func() {
 		defer close(out)
 
+		if !mergeTombstones(repo, remote, out) {
+			return
+		}
+
+		merger, err := newBugMerger(repo, rootPacks)
+		if err != nil {
+			out <- entity.MergeResult{Err: err}
+			return
+		}
+
 		remoteRefSpec := fmt.Sprintf(bugsRemoteRefPattern, remote)
 		remoteRefs, err := repo.ListRefs(remoteRefSpec)
 
@@ -64,70 +225,232 @@ func MergeAll(repo repository.ClockedRepo, remote string) <-chan entity.MergeRes
 			return
 		}
 
-		for _, remoteRef := range remoteRefs {
-			refSplit := strings.Split(remoteRef, "/")
-			id := entity.Id(refSplit[len(refSplit)-1])
+		resolver := identity.NewRemoteResolver(repo, remote)
 
-			if err := id.Validate(); err != nil {
-				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "invalid ref").Error())
-				continue
+		for _, remoteRef := range remoteRefs {
+			if !merger.merge(remoteRef, resolver, out) {
+				return
 			}
+		}
+	}()
 
-			remoteBug, err := readBug(repo, remoteRef)
+	return out
+}
 
-			if err != nil {
-				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote bug is not readable").Error())
-				continue
-			}
+// bugMerger merge the versions of the bugs coming from a remote into the
+// local bugs
+type bugMerger struct {
+	repo    repository.ClockedRepo
+	removed map[entity.Id]struct{}
 
-			// Check for error in remote data
-			if err := remoteBug.Validate(); err != nil {
-				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote bug is invalid").Error())
-				continue
-			}
+	// the local bugs indexed by their first OperationPack, brought up to
+	// date when the first new bug is found
+	rootPacks RootPacks
+	// the reverse of rootPacks
+	byRootPack map[git.Hash]entity.Id
+}
 
-			localRef := bugsRefPattern + remoteBug.Id().String()
-			localExist, err := repo.RefExist(localRef)
+func newBugMerger(repo repository.ClockedRepo, rootPacks RootPacks) (*bugMerger, error) {
+	tombstones, err := ListLocalTombstones(repo)
+	if err != nil {
+		return nil, err
+	}
 
-			if err != nil {
-				out <- entity.NewMergeError(err, id)
-				continue
-			}
+	removed := make(map[entity.Id]struct{}, len(tombstones))
+	for _, id := range tombstones {
+		removed[id] = struct{}{}
+	}
 
-			// the bug is not local yet, simply create the reference
-			if !localExist {
-				err := repo.CopyRef(remoteRef, localRef)
+	if rootPacks == nil {
+		rootPacks = make(RootPacks)
+	}
 
-				if err != nil {
-					out <- entity.NewMergeError(err, id)
-					return
-				}
+	return &bugMerger{repo: repo, removed: removed, rootPacks: rootPacks}, nil
+}
 
-				out <- entity.NewMergeStatus(entity.MergeStatusNew, id, remoteBug)
-				continue
-			}
+// merge merge the bug at the given ref, read with the resolver, and send the
+// result to out. It return false if a terminal error occurred.
+func (m *bugMerger) merge(remoteRef string, resolver identity.Resolver, out chan<- entity.MergeResult) bool {
+	repo := m.repo
 
-			localBug, err := readBug(repo, localRef)
+	refSplit := strings.Split(remoteRef, "/")
+	id := entity.Id(refSplit[len(refSplit)-1])
 
-			if err != nil {
-				out <- entity.NewMergeError(errors.Wrap(err, "local bug is not readable"), id)
-				return
-			}
+	if err := id.Validate(); err != nil {
+		out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "invalid ref").Error())
+		return true
+	}
+
+	// the bug has been removed, drop the remote version as well
+	if _, ok := m.removed[id]; ok {
+		err := repo.RemoveRef(remoteRef)
+		if err != nil {
+			out <- entity.NewMergeError(err, id)
+			return false
+		}
+		return true
+	}
+
+	remoteBug, err := readBug(repo, resolver, remoteRef, true)
+
+	if err != nil {
+		out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote bug is not readable").Error())
+		return true
+	}
+
+	// Check for error in remote data
+	if err := remoteBug.Validate(); err != nil {
+		out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote bug is invalid").Error())
+		return true
+	}
 
-			updated, err := localBug.Merge(repo, remoteBug)
+	localRef := bugsRefPattern + remoteBug.Id().String()
+	localExist, err := repo.RefExist(localRef)
 
+	if err != nil {
+		out <- entity.NewMergeError(err, id)
+		return true
+	}
+
+	// the bug is not local yet, simply create the reference
+	if !localExist {
+		if m.byRootPack == nil {
+			err = m.rootPacks.Update(repo)
 			if err != nil {
-				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "merge failed").Error())
-				return
+				out <- entity.NewMergeError(err, id)
+				return false
 			}
 
-			if updated {
-				out <- entity.NewMergeStatus(entity.MergeStatusUpdated, id, localBug)
-			} else {
-				out <- entity.NewMergeStatus(entity.MergeStatusNothing, id, localBug)
+			m.byRootPack = make(map[git.Hash]entity.Id, len(m.rootPacks))
+			for local, hash := range m.rootPacks {
+				m.byRootPack[hash] = local
 			}
 		}
-	}()
 
-	return out
+		// the same first operations under another id is a replay
+		if other, ok := m.byRootPack[remoteBug.rootPack]; ok {
+			out <- entity.NewMergeInvalidStatus(id,
+				fmt.Sprintf("remote bug is a replay of the bug %s under another id", other.Human()))
+			return true
+		}
+
+		err := repo.CopyRef(remoteRef, localRef)
+
+		if err != nil {
+			out <- entity.NewMergeError(err, id)
+			return false
+		}
+
+		m.rootPacks[id] = remoteBug.rootPack
+		m.byRootPack[remoteBug.rootPack] = id
+
+		out <- entity.NewMergeStatus(entity.MergeStatusNew, id, remoteBug)
+		return true
+	}
+
+	localBug, err := readBug(repo, identity.NewSimpleResolver(repo), localRef, false)
+
+	if err != nil {
+		out <- entity.NewMergeError(errors.Wrap(err, "local bug is not readable"), id)
+		return false
+	}
+
+	updated, err := localBug.Merge(repo, remoteBug)
+
+	if err != nil {
+		out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "merge failed").Error())
+		return false
+	}
+
+	if updated {
+		out <- entity.NewMergeStatus(entity.MergeStatusUpdated, id, localBug)
+	} else {
+		out <- entity.NewMergeStatus(entity.MergeStatusNothing, id, localBug)
+	}
+
+	return true
+}
+
+// mergeTombstones merge the tombstones of a remote and remove the corresponding
+// bugs. Only the tombstones signed by their author and allowed by the removal
+// policy are merged. It return false if a terminal error occurred.
+func mergeTombstones(repo repository.ClockedRepo, remote string, out chan<- entity.MergeResult) bool {
+	remoteRefSpec := fmt.Sprintf(tombstonesRemoteRefPattern, remote)
+	remoteRefs, err := repo.ListRefs(remoteRefSpec)
+
+	if err != nil {
+		out <- entity.MergeResult{Err: err}
+		return false
+	}
+
+	resolver := identity.NewRemoteResolver(repo, remote)
+
+	for _, remoteRef := range remoteRefs {
+		refSplit := strings.Split(remoteRef, "/")
+		id := entity.Id(refSplit[len(refSplit)-1])
+
+		if err := id.Validate(); err != nil {
+			out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "invalid ref").Error())
+			continue
+		}
+
+		localRef := tombstonesRefPattern + id.String()
+		localExist, err := repo.RefExist(localRef)
+
+		if err != nil {
+			out <- entity.NewMergeError(err, id)
+			return false
+		}
+
+		// already removed
+		if localExist {
+			continue
+		}
+
+		tombstone, err := readTombstone(repo, remoteRef)
+
+		if err != nil {
+			out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote tombstone is not readable").Error())
+			continue
+		}
+
+		// the author id of the tombstone is only trusted if it signed it
+		err = tombstone.verify(resolver)
+		if err != nil {
+			out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "tombstone rejected").Error())
+			continue
+		}
+
+		// find out who created the bug, if we know it
+		var bugAuthor entity.Id
+		b, err := ReadLocalBug(repo, id)
+		if err == ErrBugNotExist {
+			b, err = ReadRemoteBug(repo, remote, id.String())
+		}
+		if err == nil {
+			bugAuthor = b.FirstOp().GetAuthor().Id()
+		}
+
+		err = CheckRemovalPolicy(repo, bugAuthor, tombstone.AuthorId)
+		if err != nil {
+			out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "tombstone rejected").Error())
+			continue
+		}
+
+		err = repo.CopyRef(remoteRef, localRef)
+		if err != nil {
+			out <- entity.NewMergeError(err, id)
+			return false
+		}
+
+		err = removeBugRefs(repo, id)
+		if err != nil {
+			out <- entity.NewMergeError(err, id)
+			return false
+		}
+
+		out <- entity.NewMergeStatus(entity.MergeStatusRemoved, id, nil)
+	}
+
+	return true
 }
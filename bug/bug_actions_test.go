@@ -1,12 +1,15 @@
 package bug
 
 import (
+	"fmt"
+	"os/exec"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/repository"
 )
@@ -65,6 +68,92 @@ func TestPushPull(t *testing.T) {
 	}
 }
 
+func TestUnpublishedOperations(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	err := rene.Commit(repoA)
+	require.NoError(t, err)
+
+	bug1, _, err := Create(rene, time.Now().Unix(), "bug1", "message")
+	require.NoError(t, err)
+	_, err = AddComment(bug1, rene, time.Now().Unix(), "comment")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	// the remote doesn't know the bug
+	ops, err := bug1.UnpublishedOperations(repoA, "origin")
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	// the remote-tracking ref has been updated by the push
+	ops, err = bug1.UnpublishedOperations(repoA, "origin")
+	require.NoError(t, err)
+	require.Len(t, ops, 0)
+
+	_, err = SetTitle(bug1, rene, time.Now().Unix(), "new title")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	ops, err = bug1.UnpublishedOperations(repoA, "origin")
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.IsType(t, &SetTitleOperation{}, ops[0])
+}
+
+func TestPushRemoteTracking(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repoA))
+
+	bug1, _, err := Create(rene, time.Now().Unix(), "bug1", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	_, err = identity.Push(repoA, "origin")
+	require.NoError(t, err)
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	require.NoError(t, identity.Pull(repoB, "origin"))
+	require.NoError(t, Pull(repoB, "origin"))
+
+	// B publish a change first
+	bug1B, err := ReadLocalBug(repoB, bug1.Id())
+	require.NoError(t, err)
+	_, err = AddComment(bug1B, rene, time.Now().Unix(), "from B")
+	require.NoError(t, err)
+	require.NoError(t, bug1B.Commit(repoB))
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+
+	// the push of bug1 is rejected, but not the one of bug2
+	_, err = AddComment(bug1, rene, time.Now().Unix(), "from A")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	bug2, _, err := Create(rene, time.Now().Unix(), "bug2", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit(repoA))
+
+	_, err = Push(repoA, "origin")
+	require.Error(t, err)
+
+	ops, err := bug1.UnpublishedOperations(repoA, "origin")
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+
+	ops, err = bug2.UnpublishedOperations(repoA, "origin")
+	require.NoError(t, err)
+	require.Len(t, ops, 0)
+}
+
 func allBugs(t testing.TB, bugs <-chan StreamedBug) []*Bug {
 	var result []*Bug
 	for streamed := range bugs {
@@ -87,8 +176,7 @@ func BenchmarkRebaseTheirs(b *testing.B) {
 }
 
 func _RebaseTheirs(t testing.TB) {
-	repoA, repoB, remote := repository.SetupReposAndRemote(t)
-	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+	repoA, repoB, _ := repository.SetupMemReposAndRemote()
 
 	reneA := identity.NewIdentity("René Descartes", "rene@descartes.fr")
 
@@ -165,8 +253,7 @@ func BenchmarkRebaseOurs(b *testing.B) {
 }
 
 func _RebaseOurs(t testing.TB) {
-	repoA, repoB, remote := repository.SetupReposAndRemote(t)
-	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+	repoA, repoB, _ := repository.SetupMemReposAndRemote()
 
 	reneA := identity.NewIdentity("René Descartes", "rene@descartes.fr")
 
@@ -254,8 +341,7 @@ func BenchmarkRebaseConflict(b *testing.B) {
 }
 
 func _RebaseConflict(t testing.TB) {
-	repoA, repoB, remote := repository.SetupReposAndRemote(t)
-	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+	repoA, repoB, _ := repository.SetupMemReposAndRemote()
 
 	reneA := identity.NewIdentity("René Descartes", "rene@descartes.fr")
 
@@ -380,3 +466,120 @@ func _RebaseConflict(t testing.TB) {
 		t.Fatal("Unexpected number of operations")
 	}
 }
+
+func TestMergeMaliciousRemote(t *testing.T) {
+	repoA, repoB, _ := repository.SetupMemReposAndRemote()
+
+	reneA := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	err := reneA.Commit(repoA)
+	require.NoError(t, err)
+
+	_, err = identity.Push(repoA, "origin")
+	require.NoError(t, err)
+	err = identity.Pull(repoB, "origin")
+	require.NoError(t, err)
+
+	newBug := func(title string) *Bug {
+		b, _, err := Create(reneA, time.Now().Unix(), title, "message")
+		require.NoError(t, err)
+		err = b.Commit(repoA)
+		require.NoError(t, err)
+		return b
+	}
+
+	original := newBug("original")
+
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+	err = Pull(repoB, "origin")
+	require.NoError(t, err)
+
+	// lamport time absurdly ahead, whatever the order the remote bugs are
+	// merged in
+	drift := newBug("drift")
+	forgeCommit(t, repoA, drift, NewNoOpOp(reneA, time.Now().Unix()), 2*maxClockDrift)
+
+	// the same OperationPack twice
+	duplicated := newBug("duplicated")
+	forgeCommit(t, repoA, duplicated, NewNoOpOp(reneA, time.Now().Unix()), uint64(repoA.EditTime()))
+	entries, err := repoA.ListEntries(duplicated.lastCommit)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if entry.Name == opsEntryName {
+			forgeCommitBlob(t, repoA, duplicated, entry.Hash, uint64(repoA.EditTime()))
+		}
+	}
+
+	// the original bug under another id
+	tree, err := repoA.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: original.rootPack, Name: opsEntryName},
+		{ObjectType: repository.Blob, Hash: original.rootPack, Name: rootEntryName},
+		{ObjectType: repository.Blob, Hash: original.rootPack, Name: fmt.Sprintf(createClockEntryPattern, 42)},
+		{ObjectType: repository.Blob, Hash: original.rootPack, Name: fmt.Sprintf(editClockEntryPattern, 42)},
+	})
+	require.NoError(t, err)
+	replay, err := repoA.StoreCommit(tree)
+	require.NoError(t, err)
+	err = repoA.UpdateRef(bugsRefPattern+replay.String(), replay)
+	require.NoError(t, err)
+
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+	_, err = Fetch(repoB, "origin")
+	require.NoError(t, err)
+
+	invalid := make(map[entity.Id]string)
+	for result := range MergeAll(repoB, "origin", nil) {
+		require.NoError(t, result.Err)
+		if result.Status == entity.MergeStatusInvalid {
+			invalid[result.Id] = result.Reason
+		}
+	}
+
+	require.Len(t, invalid, 3)
+	require.Contains(t, invalid[drift.Id()], "absurdly ahead")
+	require.Contains(t, invalid[duplicated.Id()], "duplicated OperationPack")
+	require.Contains(t, invalid[entity.Id(replay)], "replay")
+
+	// the clocks didn't move
+	require.True(t, repoB.EditTime() < maxClockDrift)
+}
+
+func TestPullShallow(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	reneA := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+
+	bug1, _, err := Create(reneA, time.Now().Unix(), "bug1", "message")
+	require.NoError(t, err)
+	err = bug1.Commit(repoA)
+	require.NoError(t, err)
+
+	_, err = AddComment(bug1, reneA, time.Now().Unix(), "message2")
+	require.NoError(t, err)
+	err = bug1.Commit(repoA)
+	require.NoError(t, err)
+
+	_, err = identity.Push(repoA, "origin")
+	require.NoError(t, err)
+	err = identity.Pull(repoB, "origin")
+	require.NoError(t, err)
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	// fetch only the last commit of the bug, as a shallow clone would
+	out, err := exec.Command("git", "--git-dir", repoB.GetPath(),
+		"fetch", "--depth=1", "file://"+remote.GetPath(), "+refs/bugs/*:refs/bugs/*").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	_, err = ReadLocalBug(repoB, bug1.Id())
+	assert.True(t, entity.IsErrIncompleteHistory(err))
+
+	// fetching again bring the complete history
+	err = Pull(repoB, "origin")
+	require.NoError(t, err)
+
+	_, err = ReadLocalBug(repoB, bug1.Id())
+	require.NoError(t, err)
+}
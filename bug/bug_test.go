@@ -1,12 +1,15 @@
 package bug
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/schema"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBugId(t *testing.T) {
@@ -63,6 +66,30 @@ func TestBugValidity(t *testing.T) {
 	}
 }
 
+func TestSnapshotJSON(t *testing.T) {
+	mockRepo := repository.NewMockRepoForTest()
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	err := rene.Commit(mockRepo)
+	require.NoError(t, err)
+
+	bug1 := NewBug()
+	unix := time.Now().Unix()
+	bug1.Append(NewCreateOp(rene, unix, "title", "message", nil))
+	bug1.Append(NewAddCommentOp(rene, unix, "comment", nil))
+	bug1.Append(NewLabelChangeOperation(rene, unix, []Label{"bug"}, nil))
+	bug1.Append(NewAssigneeChangeOperation(rene, unix, []identity.Interface{rene}, nil))
+
+	err = bug1.Commit(mockRepo)
+	require.NoError(t, err)
+
+	snap := bug1.Compile()
+
+	data, err := json.Marshal(&snap)
+	require.NoError(t, err)
+	require.NoError(t, schema.Validate(schema.Snapshot, data))
+}
+
 func TestBugCommitLoad(t *testing.T) {
 	bug1 := NewBug()
 
@@ -0,0 +1,265 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+const bundleFormatVersion = 1
+
+// BundleRemote is the pseudo-remote under which the bug of a Bundle is stored
+// while being applied, to be merged as a bug fetched from a remote
+const BundleRemote = "git-bug-bundle"
+
+// Bundle is a portable export of the new commits of a bug, so that a
+// contributor without push access can send their changes to a maintainer.
+//
+// As for the identity bundles, the git commits are exported as is, with the
+// git objects they hold, so that the bug is exactly the same once applied.
+type Bundle struct {
+	// Additional field to version the data
+	FormatVersion uint      `json:"version"`
+	Id            entity.Id `json:"id"`
+	// the commit of the bug the bundled commits build on, if any
+	Base    git.Hash        `json:"base,omitempty"`
+	Commits []BundledCommit `json:"commits"`
+	// the data of the blobs of the commits, by hash
+	Blobs map[git.Hash][]byte `json:"blobs"`
+	// the identities involved in the bundled operations
+	Identities *identity.Bundle `json:"identities,omitempty"`
+}
+
+// BundledCommit is a commit of a bug in a Bundle
+type BundledCommit struct {
+	Hash git.Hash `json:"hash"`
+	// the raw git commit
+	Commit []byte         `json:"commit"`
+	Tree   []BundledEntry `json:"tree"`
+}
+
+// BundledEntry is an entry of the git tree of a BundledCommit
+type BundledEntry struct {
+	Name string   `json:"name"`
+	Hash git.Hash `json:"hash"`
+	// the entries of a subtree
+	Entries []BundledEntry `json:"entries,omitempty"`
+	Tree    bool           `json:"tree,omitempty"`
+}
+
+// ExportBundle create a Bundle holding the commits of a local bug which are
+// not on the remote yet, as known from its last fetch. Without remote, or if
+// the bug has never been pushed there, the whole history is bundled.
+func ExportBundle(repo repository.ClockedRepo, id entity.Id, remote string) (*Bundle, error) {
+	localRef := bugsRefPattern + id.String()
+	b, err := readBug(repo, identity.NewSimpleResolver(repo), localRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		FormatVersion: bundleFormatVersion,
+		Id:            id,
+		Blobs:         make(map[git.Hash][]byte),
+	}
+
+	packs := b.packs
+
+	if remote != "" {
+		remoteRef := fmt.Sprintf(bugsRemoteRefPattern, remote) + id.String()
+		remoteExist, err := repo.RefExist(remoteRef)
+		if err != nil {
+			return nil, err
+		}
+
+		if remoteExist {
+			remoteHashes, err := repo.ListCommits(remoteRef)
+			if err != nil {
+				return nil, err
+			}
+			base := remoteHashes[len(remoteHashes)-1]
+
+			found := false
+			for i, pack := range packs {
+				if pack.commitHash == base {
+					packs = packs[i+1:]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("the bug diverged from the one of %s, pull it first", remote)
+			}
+
+			bundle.Base = base
+		}
+	}
+
+	var ops []Operation
+
+	for _, pack := range packs {
+		hash := pack.commitHash
+
+		commit, err := repo.ReadCommitData(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		tree, err := bundleTree(repo, bundle.Blobs, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Commits = append(bundle.Commits, BundledCommit{
+			Hash:   hash,
+			Commit: commit,
+			Tree:   tree,
+		})
+
+		ops = append(ops, pack.Operations...)
+	}
+
+	if len(ops) > 0 {
+		bundle.Identities, err = identity.ExportBundle(repo, IdentitiesOf(ops))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bundle, nil
+}
+
+// bundleTree export the entries of a git tree, storing the data of its blobs
+// in blobs
+func bundleTree(repo repository.Repo, blobs map[git.Hash][]byte, hash git.Hash) ([]BundledEntry, error) {
+	entries, err := repo.ListEntries(hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't list git tree entries")
+	}
+
+	result := make([]BundledEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		bundled := BundledEntry{Name: entry.Name, Hash: entry.Hash}
+
+		switch entry.ObjectType {
+		case repository.Tree:
+			bundled.Tree = true
+			bundled.Entries, err = bundleTree(repo, blobs, entry.Hash)
+			if err != nil {
+				return nil, err
+			}
+		case repository.Blob:
+			if _, ok := blobs[entry.Hash]; !ok {
+				data, err := repo.ReadData(entry.Hash)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to read git blob data")
+				}
+				blobs[entry.Hash] = data
+			}
+		default:
+			return nil, fmt.Errorf("unexpected git object %s", entry.Hash)
+		}
+
+		result = append(result, bundled)
+	}
+
+	return result, nil
+}
+
+// StoreBundle store the git objects of a Bundle and point the ref of the bug
+// under the BundleRemote pseudo-remote to its last commit, so that it can be
+// merged with MergeAll. The identities of the bundle should be imported
+// beforehand.
+func StoreBundle(repo repository.ClockedRepo, bundle *Bundle) error {
+	if bundle.FormatVersion != bundleFormatVersion {
+		return fmt.Errorf("unknown bug bundle format version %v", bundle.FormatVersion)
+	}
+
+	if err := bundle.Id.Validate(); err != nil {
+		return errors.Wrap(err, "invalid id")
+	}
+
+	if len(bundle.Commits) == 0 {
+		return errors.New("empty bug bundle")
+	}
+
+	if bundle.Base != "" {
+		if _, err := repo.ReadCommitData(bundle.Base); err != nil {
+			return fmt.Errorf("the bundle build on the commit %s of the bug, unknown here: pull the bug first", bundle.Base)
+		}
+	}
+
+	for _, commit := range bundle.Commits {
+		treeHash, err := storeBundledTree(repo, bundle.Blobs, commit.Tree)
+		if err != nil {
+			return errors.Wrapf(err, "commit %s", commit.Hash)
+		}
+
+		commitHash, err := repo.StoreCommitData(commit.Commit)
+		if err != nil {
+			return errors.Wrapf(err, "invalid commit %s", commit.Hash)
+		}
+		if commitHash != commit.Hash {
+			return fmt.Errorf("altered commit %s", commit.Hash)
+		}
+
+		commitTree, err := repo.GetTreeHash(commitHash)
+		if err != nil {
+			return err
+		}
+		if commitTree != treeHash {
+			return fmt.Errorf("altered data at hash %s", commit.Hash)
+		}
+	}
+
+	head := bundle.Commits[len(bundle.Commits)-1].Hash
+	ref := fmt.Sprintf(bugsRemoteRefPattern, BundleRemote) + bundle.Id.String()
+
+	return repo.UpdateRef(ref, head)
+}
+
+func storeBundledTree(repo repository.Repo, blobs map[git.Hash][]byte, entries []BundledEntry) (git.Hash, error) {
+	tree := make([]repository.TreeEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.Tree {
+			hash, err := storeBundledTree(repo, blobs, entry.Entries)
+			if err != nil {
+				return "", err
+			}
+			if hash != entry.Hash {
+				return "", fmt.Errorf("altered tree %s", entry.Hash)
+			}
+			tree = append(tree, repository.TreeEntry{ObjectType: repository.Tree, Hash: hash, Name: entry.Name})
+			continue
+		}
+
+		data, ok := blobs[entry.Hash]
+		if !ok {
+			return "", fmt.Errorf("missing blob %s", entry.Hash)
+		}
+		hash, err := repo.StoreData(data)
+		if err != nil {
+			return "", err
+		}
+		if hash != entry.Hash {
+			return "", fmt.Errorf("altered blob %s", entry.Hash)
+		}
+		tree = append(tree, repository.TreeEntry{ObjectType: repository.Blob, Hash: hash, Name: entry.Name})
+	}
+
+	return repo.StoreTree(tree)
+}
+
+// RemoveBundleRef remove the ref of a bug stored with StoreBundle, once
+// merged
+func RemoveBundleRef(repo repository.Repo, id entity.Id) error {
+	ref := fmt.Sprintf(bugsRemoteRefPattern, BundleRemote) + id.String()
+	return repo.RemoveRef(ref)
+}
@@ -0,0 +1,108 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func applyBundle(t *testing.T, repo repository.ClockedRepo, bundle *Bundle) entity.MergeResult {
+	t.Helper()
+
+	if bundle.Identities != nil {
+		_, err := identity.ImportBundle(repo, bundle.Identities)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, StoreBundle(repo, bundle))
+	defer func() {
+		require.NoError(t, RemoveBundleRef(repo, bundle.Id))
+	}()
+
+	var results []entity.MergeResult
+	for result := range MergeAll(repo, BundleRemote, nil) {
+		require.NoError(t, result.Err)
+		results = append(results, result)
+	}
+	require.Len(t, results, 1)
+
+	return results[0]
+}
+
+func TestBundle(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	repoC := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote, repoC)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repoA))
+
+	bug1, _, err := Create(rene, time.Now().Unix(), "bug1", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	_, err = identity.Push(repoA, "origin")
+	require.NoError(t, err)
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+	require.NoError(t, identity.Pull(repoB, "origin"))
+	require.NoError(t, Pull(repoB, "origin"))
+
+	// nothing new since the push
+	bundle, err := ExportBundle(repoA, bug1.Id(), "origin")
+	require.NoError(t, err)
+	require.Empty(t, bundle.Commits)
+
+	_, err = AddComment(bug1, rene, time.Now().Unix(), "comment")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	bundle, err = ExportBundle(repoA, bug1.Id(), "origin")
+	require.NoError(t, err)
+	require.Len(t, bundle.Commits, 1)
+	require.NotEmpty(t, bundle.Base)
+
+	// the bundle survive its serialization
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	var decoded Bundle
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	result := applyBundle(t, repoB, &decoded)
+	require.Equal(t, entity.MergeStatusUpdated, result.Status)
+
+	bugB, err := ReadLocalBug(repoB, bug1.Id())
+	require.NoError(t, err)
+	require.Equal(t, bug1.lastCommit, bugB.lastCommit)
+
+	// the base is unknown in another repository
+	err = StoreBundle(repoC, bundle)
+	require.Error(t, err)
+
+	// the whole bug create it
+	bundle, err = ExportBundle(repoA, bug1.Id(), "")
+	require.NoError(t, err)
+	require.Len(t, bundle.Commits, 2)
+	require.Empty(t, bundle.Base)
+
+	result = applyBundle(t, repoC, bundle)
+	require.Equal(t, entity.MergeStatusNew, result.Status)
+
+	bugC, err := ReadLocalBug(repoC, bug1.Id())
+	require.NoError(t, err)
+	require.Equal(t, bug1.lastCommit, bugC.lastCommit)
+
+	// altered data is rejected
+	for hash, data := range bundle.Blobs {
+		if len(data) > 0 {
+			bundle.Blobs[hash] = append([]byte{' '}, data...)
+		}
+	}
+	require.Error(t, StoreBundle(repoC, bundle))
+}
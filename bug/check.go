@@ -0,0 +1,271 @@
+package bug
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// maxClockSkew is how far in the future the timestamp of an operation can be
+// before being considered bogus
+const maxClockSkew = 24 * time.Hour
+
+// CheckLocalBug verify a local bug before sharing it: the operations are
+// checked against the JSON schema and validated, and the lamport times must
+// not be ahead of the local clocks, as a bogus value would push forward the
+// clocks of everyone pulling the bug. The signatures of the operations not
+// yet in remoteHead, if given, must not be bad.
+func CheckLocalBug(repo repository.ClockedRepo, id entity.Id, remoteHead git.Hash) error {
+	// reading the bug witness its lamport times, so take the clocks first
+	createClock := repo.CreateTime()
+	editClock := repo.EditTime()
+
+	ref := bugsRefPattern + id.String()
+	b, err := readBug(repo, identity.NewSimpleResolver(repo), ref, true)
+	if err != nil {
+		return err
+	}
+
+	if err := b.check(); err != nil {
+		return err
+	}
+
+	if b.createTime > createClock {
+		return fmt.Errorf("create lamport time %d is ahead of the local clock %d", b.createTime, createClock)
+	}
+
+	for _, pack := range b.packs {
+		if pack.editTime > editClock {
+			return fmt.Errorf("edit lamport time %d of commit %s is ahead of the local clock %d",
+				pack.editTime, pack.commitHash, editClock)
+		}
+	}
+
+	// the remote head is not fetched yet, all the operations are checked
+	if remoteHead != "" {
+		if _, err := repo.GetTreeHash(remoteHead); err != nil {
+			remoteHead = ""
+		}
+	}
+
+	return b.checkSignatures(repo, remoteHead, false)
+}
+
+// CheckPushedBug verify a bug pushed to this repository, before its ref is
+// updated from old, empty for a new bug, to the new head: the operations are
+// checked against the JSON schema and validated. The identities are loaded
+// with the given resolver, to find the ones pushed along.
+//
+// The operations added since old must not have a bad signature and, if
+// requireSigned, must be signed with a key of their author.
+//
+// The lamport times are not compared to the clocks of this repository, as
+// they are independent of the ones of the pusher.
+func CheckPushedBug(repo repository.ClockedRepo, resolver identity.Resolver, id entity.Id, old git.Hash, head git.Hash, requireSigned bool) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	b, err := readBugRev(repo, resolver, id, head.String(), true)
+	if err != nil {
+		return err
+	}
+
+	if err := b.check(); err != nil {
+		return err
+	}
+
+	return b.checkSignatures(repo, old, requireSigned)
+}
+
+// CheckPushedTombstone verify the tombstone of a bug pushed to this
+// repository, before its ref is updated from old, empty for a new tombstone,
+// to the new head: it must be signed by its author with a key valid at the
+// time of the removal, and the author must be allowed to remove the bug by
+// the policy of this repository. A tombstone can't be replaced.
+func CheckPushedTombstone(repo repository.ClockedRepo, resolver identity.Resolver, id entity.Id, old git.Hash, head git.Hash) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	if old != "" && old != head {
+		return fmt.Errorf("the tombstone can't be replaced")
+	}
+
+	t, err := readTombstoneRev(repo, id, head.String())
+	if err != nil {
+		return err
+	}
+
+	if err := t.verify(resolver); err != nil {
+		return err
+	}
+
+	// find out who created the bug, if we know it
+	var bugAuthor entity.Id
+	b, err := ReadLocalBug(repo, id)
+	if err == nil {
+		bugAuthor = b.FirstOp().GetAuthor().Id()
+	}
+
+	return CheckRemovalPolicy(repo, bugAuthor, t.AuthorId)
+}
+
+// check validate the bug data and the sanity of its times
+func (bug *Bug) check() error {
+	if err := bug.Validate(); err != nil {
+		return err
+	}
+
+	if bug.createTime == 0 {
+		return fmt.Errorf("create lamport time not set")
+	}
+
+	limit := time.Now().Add(maxClockSkew)
+
+	for _, pack := range bug.packs {
+		if pack.editTime == 0 {
+			return fmt.Errorf("edit lamport time not set in commit %s", pack.commitHash)
+		}
+
+		for _, op := range pack.Operations {
+			if op.Time().After(limit) {
+				return fmt.Errorf("operation %s is dated in the future (%s)",
+					op.Id().Human(), op.Time().Format(time.RFC3339))
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSignatures verify the signatures of the operations of the commits not
+// in the history of since, if given: a bad signature is refused and, if
+// requireSigned, the operations must be signed with a key of their author.
+// The commits already shared were checked when they were.
+func (bug *Bug) checkSignatures(repo repository.Repo, since git.Hash, requireSigned bool) error {
+	known := make(map[git.Hash]bool)
+	if since != "" {
+		hashes, err := repo.ListCommits(since.String())
+		if err != nil {
+			return err
+		}
+		for _, hash := range hashes {
+			known[hash] = true
+		}
+	}
+
+	for _, pack := range bug.packs {
+		if known[pack.commitHash] {
+			continue
+		}
+
+		_, blob, signatureBlob, err := packBlobs(repo, pack)
+		if err != nil {
+			return errors.Wrapf(err, "commit %s", pack.commitHash)
+		}
+
+		signature, err := verifyOperationPack(repo, pack, blob, signatureBlob)
+		if err != nil {
+			return errors.Wrapf(err, "commit %s", pack.commitHash)
+		}
+
+		switch {
+		case signature.Status == repository.SignatureBad:
+			return fmt.Errorf("bad signature of the operations of commit %s", pack.commitHash)
+		case requireSigned && signature.Status != repository.SignatureGood:
+			return fmt.Errorf("the operations of commit %s are not signed with a key of their author (%s)",
+				pack.commitHash, signature.Status)
+		}
+	}
+
+	return nil
+}
+
+// maxClockDrift is how far ahead of the local clocks the lamport times of the
+// bugs coming from elsewhere can be. No honest repository is that far ahead,
+// while a bogus value would push forward the clocks of everyone for good.
+const maxClockDrift = 1 << 20
+
+// checkClockDrift reject the lamport times absurdly ahead of the local clocks
+func checkClockDrift(repo repository.ClockedRepo, createTime lamport.Time, editTime lamport.Time) error {
+	if createTime > repo.CreateTime()+maxClockDrift {
+		return fmt.Errorf("create lamport time %d is absurdly ahead of the local clock %d",
+			createTime, repo.CreateTime())
+	}
+	if editTime > repo.EditTime()+maxClockDrift {
+		return fmt.Errorf("edit lamport time %d is absurdly ahead of the local clock %d",
+			editTime, repo.EditTime())
+	}
+	return nil
+}
+
+// RootPacks index the local bugs by the hash of their first OperationPack, to
+// detect a bug replayed under another id. Kept across the merges, as by the
+// cache, it only need to read the bugs not indexed yet.
+type RootPacks map[entity.Id]git.Hash
+
+// Update bring the index up to date with the local bugs: the removed bugs are
+// dropped, and the new ones read
+func (rp RootPacks) Update(repo repository.ClockedRepo) error {
+	ids, err := ListLocalIds(repo)
+	if err != nil {
+		return err
+	}
+
+	local := make(map[entity.Id]struct{}, len(ids))
+
+	for _, id := range ids {
+		local[id] = struct{}{}
+
+		if _, ok := rp[id]; ok {
+			continue
+		}
+
+		hash, err := readRootPack(repo, id)
+		if err != nil {
+			return err
+		}
+		rp[id] = hash
+	}
+
+	for id := range rp {
+		if _, ok := local[id]; !ok {
+			delete(rp, id)
+		}
+	}
+
+	return nil
+}
+
+// readRootPack read the hash of the first OperationPack of a local bug, from
+// the tree of its first commit
+func readRootPack(repo repository.ClockedRepo, id entity.Id) (git.Hash, error) {
+	hashes, err := repo.ListCommits(bugsRefPattern + id.String())
+	if err != nil {
+		return "", err
+	}
+	if len(hashes) == 0 {
+		return "", nil
+	}
+
+	entries, err := repo.ListEntries(hashes[0])
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.Name == rootEntryName {
+			return entry.Hash, nil
+		}
+	}
+
+	return "", nil
+}
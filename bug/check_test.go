@@ -0,0 +1,269 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// forgeCommit add on top of a bug a commit holding the given operation, with
+// the given edit lamport time and extra tree entries
+func forgeCommit(t testing.TB, repo repository.ClockedRepo, b *Bug, op Operation, editTime uint64, extra ...repository.TreeEntry) {
+	opp := &OperationPack{}
+	opp.Append(op)
+
+	data, err := json.Marshal(opp)
+	require.NoError(t, err)
+
+	hash, err := repo.StoreData(data)
+	require.NoError(t, err)
+
+	forgeCommitBlob(t, repo, b, hash, editTime, extra...)
+}
+
+// forgeCommitBlob add on top of a bug a commit holding an already stored
+// OperationPack
+func forgeCommitBlob(t testing.TB, repo repository.ClockedRepo, b *Bug, blob git.Hash, editTime uint64, extra ...repository.TreeEntry) {
+	tree, err := repo.StoreTree(append([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blob, Name: opsEntryName},
+		{ObjectType: repository.Blob, Hash: b.rootPack, Name: rootEntryName},
+		{ObjectType: repository.Blob, Hash: blob, Name: fmt.Sprintf(createClockEntryPattern, b.createTime)},
+		{ObjectType: repository.Blob, Hash: blob, Name: fmt.Sprintf(editClockEntryPattern, editTime)},
+	}, extra...))
+	require.NoError(t, err)
+
+	commit, err := repo.StoreCommitWithParent(tree, b.lastCommit)
+	require.NoError(t, err)
+
+	err = repo.UpdateRef(bugsRefPattern+b.Id().String(), commit)
+	require.NoError(t, err)
+
+	b.lastCommit = commit
+}
+
+func TestCheckLocalBug(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	err := rene.Commit(repo)
+	require.NoError(t, err)
+
+	newBug := func() *Bug {
+		b, _, err := Create(rene, time.Now().Unix(), "title", "message")
+		require.NoError(t, err)
+		err = b.Commit(repo)
+		require.NoError(t, err)
+		return b
+	}
+
+	b := newBug()
+	require.NoError(t, CheckLocalBug(repo, b.Id(), ""))
+
+	// an edit lamport time ahead of the local clock
+	b = newBug()
+	forgeCommit(t, repo, b, NewNoOpOp(rene, time.Now().Unix()), uint64(repo.EditTime())+1000)
+	require.Error(t, CheckLocalBug(repo, b.Id(), ""))
+
+	// an operation dated in the future
+	b = newBug()
+	forgeCommit(t, repo, b, NewNoOpOp(rene, time.Now().Add(48*time.Hour).Unix()), uint64(repo.EditTime()))
+	require.Error(t, CheckLocalBug(repo, b.Id(), ""))
+
+	// an invalid operation
+	b = newBug()
+	forgeCommit(t, repo, b, NewSetStatusOp(rene, time.Now().Unix(), 1000), uint64(repo.EditTime()))
+	require.Error(t, CheckLocalBug(repo, b.Id(), ""))
+}
+
+func TestRootPacksUpdate(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	err := rene.Commit(repo)
+	require.NoError(t, err)
+
+	b1, _, err := Create(rene, time.Now().Unix(), "title", "message")
+	require.NoError(t, err)
+	err = b1.Commit(repo)
+	require.NoError(t, err)
+
+	b2, _, err := Create(rene, time.Now().Unix(), "other title", "message")
+	require.NoError(t, err)
+	err = b2.Commit(repo)
+	require.NoError(t, err)
+
+	// an indexed bug is not read again, and a removed bug is dropped
+	rootPacks := RootPacks{
+		b1.Id():   "known",
+		"removed": "removed",
+	}
+
+	err = rootPacks.Update(repo)
+	require.NoError(t, err)
+	require.Equal(t, RootPacks{
+		b1.Id(): "known",
+		b2.Id(): b2.rootPack,
+	}, rootPacks)
+}
+
+func TestCheckPushedBug(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	err := rene.Commit(repo)
+	require.NoError(t, err)
+
+	b, _, err := Create(rene, time.Now().Unix(), "title", "message")
+	require.NoError(t, err)
+	err = b.Commit(repo)
+	require.NoError(t, err)
+
+	resolver := identity.NewSimpleResolver(repo)
+
+	require.NoError(t, CheckPushedBug(repo, resolver, b.Id(), "", b.lastCommit, false))
+
+	// the clocks of the pusher are independent
+	forgeCommit(t, repo, b, NewNoOpOp(rene, time.Now().Unix()), uint64(repo.EditTime())+1000)
+	require.NoError(t, CheckPushedBug(repo, resolver, b.Id(), "", b.lastCommit, false))
+
+	require.Error(t, CheckPushedBug(repo, resolver, "invalid", "", b.lastCommit, false))
+}
+
+func TestCheckSignatures(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	signer, err := identity.NewTestSigner()
+	require.NoError(t, err)
+	require.NoError(t, identity.SetSigner(repo, signer, false))
+	key, err := signer.Key()
+	require.NoError(t, err)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	rene.Mutate(func(m identity.Mutator) identity.Mutator {
+		m.Keys = append(m.Keys, key)
+		return m
+	})
+	require.NoError(t, rene.Commit(repo))
+
+	b, _, err := Create(rene, time.Now().Unix(), "title", "message")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit(repo))
+
+	resolver := identity.NewSimpleResolver(repo)
+
+	// signed with a key of the author
+	signed := b.lastCommit
+	require.NoError(t, CheckPushedBug(repo, resolver, b.Id(), "", signed, true))
+	require.NoError(t, CheckLocalBug(repo, b.Id(), ""))
+
+	// not signed
+	forgeCommit(t, repo, b, NewNoOpOp(rene, time.Now().Unix()), uint64(repo.EditTime()))
+	unsigned := b.lastCommit
+	require.NoError(t, CheckPushedBug(repo, resolver, b.Id(), "", unsigned, false))
+	require.Error(t, CheckPushedBug(repo, resolver, b.Id(), "", unsigned, true))
+	require.Error(t, CheckPushedBug(repo, resolver, b.Id(), signed, unsigned, true))
+	require.NoError(t, CheckLocalBug(repo, b.Id(), ""))
+
+	// the signature of other operations
+	tree, err := repo.GetTreeHash(signed)
+	require.NoError(t, err)
+	entries, err := repo.ListEntries(tree)
+	require.NoError(t, err)
+	var signature repository.TreeEntry
+	for _, entry := range entries {
+		if entry.Name == signatureEntryName {
+			signature = entry
+		}
+	}
+	require.NotEmpty(t, signature.Hash)
+
+	forgeCommit(t, repo, b, NewNoOpOp(rene, time.Now().Unix()-1), uint64(repo.EditTime()), signature)
+	bad := b.lastCommit
+	require.Error(t, CheckPushedBug(repo, resolver, b.Id(), "", bad, false))
+	require.Error(t, CheckPushedBug(repo, resolver, b.Id(), unsigned, bad, false))
+	require.Error(t, CheckLocalBug(repo, b.Id(), ""))
+	require.Error(t, CheckLocalBug(repo, b.Id(), unsigned))
+
+	// only the commits not shared yet are checked
+	require.NoError(t, CheckPushedBug(repo, resolver, b.Id(), bad, bad, false))
+	require.NoError(t, CheckLocalBug(repo, b.Id(), bad))
+	// a remote head not fetched yet
+	require.Error(t, CheckLocalBug(repo, b.Id(), "0123456789012345678901234567890123456789"))
+
+	// the operations of several authors, signed with the key of one of them
+	isaac := identity.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, isaac.Commit(repo))
+
+	b.Append(NewNoOpOp(rene, time.Now().Unix()-2))
+	b.Append(NewNoOpOp(isaac, time.Now().Unix()-2))
+	require.NoError(t, b.Commit(repo))
+	require.NoError(t, CheckPushedBug(repo, resolver, b.Id(), bad, b.lastCommit, false))
+	require.Error(t, CheckPushedBug(repo, resolver, b.Id(), bad, b.lastCommit, true))
+}
+
+func TestCheckPushedTombstone(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	signer, err := identity.NewTestSigner()
+	require.NoError(t, err)
+	require.NoError(t, identity.SetSigner(repo, signer, false))
+	key, err := signer.Key()
+	require.NoError(t, err)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	rene.Mutate(func(m identity.Mutator) identity.Mutator {
+		m.Keys = append(m.Keys, key)
+		return m
+	})
+	require.NoError(t, rene.Commit(repo))
+
+	isaac := identity.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, isaac.Commit(repo))
+
+	resolver := identity.NewSimpleResolver(repo)
+
+	// remove a bug, but keep it as on the server, where only the tombstone is
+	// pushed
+	remove := func(author identity.Interface) (entity.Id, git.Hash) {
+		b, _, err := Create(author, time.Now().Unix(), "title", "message")
+		require.NoError(t, err)
+		require.NoError(t, b.Commit(repo))
+
+		_, err = Remove(repo, b, rene, time.Now().Unix(), "")
+		require.NoError(t, err)
+		require.NoError(t, repo.UpdateRef(bugsRefPattern+b.Id().String(), b.lastCommit))
+
+		hashes, err := repo.ListCommits(tombstonesRefPattern + b.Id().String())
+		require.NoError(t, err)
+		return b.Id(), hashes[len(hashes)-1]
+	}
+
+	// removed by the author of the bug
+	id, head := remove(rene)
+	require.NoError(t, CheckPushedTombstone(repo, resolver, id, "", head))
+	require.NoError(t, CheckPushedTombstone(repo, resolver, id, head, head))
+	require.Error(t, CheckPushedTombstone(repo, resolver, "invalid", "", head))
+
+	// removed by a maintainer
+	require.NoError(t, repo.LocalConfig().StoreString(maintainersConfigKey, rene.Id().String()))
+	first := head
+	id, head = remove(isaac)
+	require.NoError(t, CheckPushedTombstone(repo, resolver, id, "", head))
+	// a tombstone can't be replaced
+	require.Error(t, CheckPushedTombstone(repo, resolver, id, first, head))
+	require.NoError(t, repo.LocalConfig().RemoveAll(maintainersConfigKey))
+	require.Error(t, CheckPushedTombstone(repo, resolver, id, "", head))
+
+	// not signed
+	require.NoError(t, repo.LocalConfig().RemoveAll("git-bug.signer"))
+	id, head = remove(rene)
+	require.Error(t, CheckPushedTombstone(repo, resolver, id, "", head))
+}
@@ -0,0 +1,56 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+// ChecklistItem is a task of the checklist of a bug
+type ChecklistItem struct {
+	// the id of the operation that added the item
+	id      entity.Id
+	Author  identity.Interface
+	Text    string
+	Checked bool
+
+	// Creation time of the item.
+	// Should be used only for human display, never for ordering as we can't rely on it in a distributed system.
+	UnixTime timestamp.Timestamp
+}
+
+// Id return the ChecklistItem identifier
+func (i ChecklistItem) Id() entity.Id {
+	if i.id == "" {
+		// simply panic as it would be a coding error
+		panic("no id yet")
+	}
+	return i.id
+}
+
+// Sign post method for gqlgen
+func (i ChecklistItem) IsAuthored() {}
+
+// ChecklistProgress return the number of checked items of the checklist of
+// the bug, and its number of items
+func (snap *Snapshot) ChecklistProgress() (checked int, total int) {
+	for _, item := range snap.Checklist {
+		if item.Checked {
+			checked++
+		}
+	}
+	return checked, len(snap.Checklist)
+}
+
+// SearchChecklistItem will search for a checklist item matching the given hash
+func (snap *Snapshot) SearchChecklistItem(id entity.Id) (*ChecklistItem, error) {
+	for i := range snap.Checklist {
+		if snap.Checklist[i].id == id {
+			return &snap.Checklist[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("checklist item not found")
+}
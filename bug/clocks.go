@@ -1,23 +1,54 @@
 package bug
 
 import (
+	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/repository"
 )
 
 // Witnesser will read all the available Bug to recreate the different logical
 // clocks
 func Witnesser(repo repository.ClockedRepo) error {
-	for b := range ReadAllLocalBugs(repo) {
+	allBugs := ReadAllLocalBugs(repo)
+
+	for b := range allBugs {
+		if entity.IsErrIncompleteHistory(b.Err) {
+			// drain the stream to not leave the readers blocked
+			for range allBugs {
+			}
+			return witnessSkipIncomplete(repo)
+		}
 		if b.Err != nil {
 			return b.Err
 		}
 
-		err := repo.WitnessCreate(b.Bug.createTime)
+		err := witness(repo, b.Bug)
 		if err != nil {
 			return err
 		}
+	}
 
-		err = repo.WitnessEdit(b.Bug.editTime)
+	return nil
+}
+
+// witnessSkipIncomplete read the bugs one by one to recreate the logical
+// clocks, leaving out the bugs whose history is incomplete, as in a shallow
+// clone
+func witnessSkipIncomplete(repo repository.ClockedRepo) error {
+	ids, err := ListLocalIds(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		b, err := ReadLocalBug(repo, id)
+		if entity.IsErrIncompleteHistory(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = witness(repo, b)
 		if err != nil {
 			return err
 		}
@@ -25,3 +56,12 @@ func Witnesser(repo repository.ClockedRepo) error {
 
 	return nil
 }
+
+func witness(repo repository.ClockedRepo, b *Bug) error {
+	err := repo.WitnessCreate(b.createTime)
+	if err != nil {
+		return err
+	}
+
+	return repo.WitnessEdit(b.editTime)
+}
@@ -14,6 +14,8 @@ type Comment struct {
 	Author  identity.Interface
 	Message string
 	Files   []git.Hash
+	// the comment this one reply to, if any
+	ReplyTo entity.Id
 
 	// Creation time of the comment.
 	// Should be used only for human display, never for ordering as we can't rely on it in a distributed system.
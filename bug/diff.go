@@ -0,0 +1,106 @@
+package bug
+
+import (
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+// Diff describe what changed on a bug between two of its snapshots
+type Diff struct {
+	OldTitle string
+	NewTitle string
+
+	OldStatus     Status
+	NewStatus     Status
+	NewResolution Resolution
+
+	AddedLabels   []Label
+	RemovedLabels []Label
+
+	OldComponent string
+	NewComponent string
+
+	AddedAssignees   []identity.Interface
+	RemovedAssignees []identity.Interface
+
+	// the comments added in between
+	NewComments []Comment
+	// the comments existing before, with a different message
+	EditedComments []Comment
+}
+
+// DiffSnapshots compute what changed from the before snapshot to after, two
+// snapshots of the same bug
+func DiffSnapshots(before, after *Snapshot) Diff {
+	diff := Diff{
+		OldTitle:      before.Title,
+		NewTitle:      after.Title,
+		OldStatus:     before.Status,
+		NewStatus:     after.Status,
+		NewResolution: after.Resolution,
+		OldComponent:  before.Component,
+		NewComponent:  after.Component,
+	}
+
+	for _, label := range after.Labels {
+		if !before.HasLabel(label) {
+			diff.AddedLabels = append(diff.AddedLabels, label)
+		}
+	}
+	for _, label := range before.Labels {
+		if !after.HasLabel(label) {
+			diff.RemovedLabels = append(diff.RemovedLabels, label)
+		}
+	}
+
+	for _, assignee := range after.Assignees {
+		if !before.IsAssigned(assignee.Id()) {
+			diff.AddedAssignees = append(diff.AddedAssignees, assignee)
+		}
+	}
+	for _, assignee := range before.Assignees {
+		if !after.IsAssigned(assignee.Id()) {
+			diff.RemovedAssignees = append(diff.RemovedAssignees, assignee)
+		}
+	}
+
+	messages := make(map[entity.Id]string, len(before.Comments))
+	for _, comment := range before.Comments {
+		messages[comment.Id()] = comment.Message
+	}
+
+	for _, comment := range after.Comments {
+		message, ok := messages[comment.Id()]
+		switch {
+		case !ok:
+			diff.NewComments = append(diff.NewComments, comment)
+		case message != comment.Message:
+			diff.EditedComments = append(diff.EditedComments, comment)
+		}
+	}
+
+	return diff
+}
+
+// TitleChanged tell if the title has been changed
+func (d Diff) TitleChanged() bool {
+	return d.OldTitle != d.NewTitle
+}
+
+// StatusChanged tell if the status has been changed
+func (d Diff) StatusChanged() bool {
+	return d.OldStatus != d.NewStatus
+}
+
+// ComponentChanged tell if the component has been changed
+func (d Diff) ComponentChanged() bool {
+	return d.OldComponent != d.NewComponent
+}
+
+// IsEmpty tell if nothing changed
+func (d Diff) IsEmpty() bool {
+	return !d.TitleChanged() && !d.StatusChanged() && !d.ComponentChanged() &&
+		len(d.AddedLabels) == 0 && len(d.RemovedLabels) == 0 &&
+		len(d.AddedAssignees) == 0 && len(d.RemovedAssignees) == 0 &&
+		len(d.NewComments) == 0 && len(d.EditedComments) == 0
+}
@@ -0,0 +1,55 @@
+package bug
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	rene := identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	create := NewCreateOp(rene, unix, "title", "create", nil)
+	comment := NewAddCommentOp(rene, unix, "comment", nil)
+	labels := NewLabelChangeOperation(rene, unix, []Label{"bug", "ui"}, nil)
+
+	ops := []Operation{
+		create,
+		comment,
+		labels,
+		NewSetTitleOp(rene, unix, "new title", "title"),
+		NewLabelChangeOperation(rene, unix, []Label{"crash"}, []Label{"ui"}),
+		NewEditCommentOp(rene, unix, comment.Id(), "edited comment", nil),
+		NewAddCommentOp(rene, unix, "another comment", nil),
+		NewSetStatusOp(rene, unix, ClosedStatus),
+	}
+
+	before := Snapshot{}
+	for _, op := range ops[:3] {
+		op.Apply(&before)
+	}
+	after := Snapshot{}
+	for _, op := range ops {
+		op.Apply(&after)
+	}
+
+	diff := DiffSnapshots(&before, &after)
+	require.False(t, diff.IsEmpty())
+	require.True(t, diff.TitleChanged())
+	require.Equal(t, "new title", diff.NewTitle)
+	require.True(t, diff.StatusChanged())
+	require.Equal(t, ClosedStatus, diff.NewStatus)
+	require.False(t, diff.ComponentChanged())
+	require.Equal(t, []Label{"crash"}, diff.AddedLabels)
+	require.Equal(t, []Label{"ui"}, diff.RemovedLabels)
+	require.Len(t, diff.NewComments, 1)
+	require.Equal(t, "another comment", diff.NewComments[0].Message)
+	require.Len(t, diff.EditedComments, 1)
+	require.Equal(t, "edited comment", diff.EditedComments[0].Message)
+
+	require.True(t, DiffSnapshots(&after, &after).IsEmpty())
+}
@@ -0,0 +1,82 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// the start of the fixed times of the fixtures, 2020-01-01
+const fixtureUnixTime = 1577836800
+
+// Fixtures return the serialized data of an OperationPack holding every
+// operation type, by file name. The data is the same on every run, to be
+// compared with the golden files of the tests and catch the changes of the
+// format between versions.
+func Fixtures() (map[string][]byte, error) {
+	rene, err := fixtureIdentity("a3fb7c1c1d2f6d9e4f3a0b1c2d3e4f5a6b7c8d9e")
+	if err != nil {
+		return nil, err
+	}
+	isaac, err := fixtureIdentity("0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c")
+	if err != nil {
+		return nil, err
+	}
+
+	file := git.Hash("e69de29bb2d1d6434b8b29ae775ad8c2e48c5391")
+	otherBug := entity.Id("c1b29f7e6d5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c")
+
+	unix := int64(fixtureUnixTime)
+	next := func() int64 {
+		unix += 60
+		return unix
+	}
+
+	create := NewCreateOp(rene, next(), "title", "message", []git.Hash{file})
+	create.SetMetadata("origin", "fixture")
+	checklistItem := NewAddChecklistItemOp(rene, next(), "item")
+
+	opp := &OperationPack{}
+	opp.Append(create)
+	opp.Append(NewSetTitleOp(rene, next(), "new title", "title"))
+	opp.Append(NewAddCommentReplyOp(isaac, next(), create.Id(), "comment", []git.Hash{file}))
+	opp.Append(NewEditCommentOp(rene, next(), create.Id(), "edited message", nil))
+	opp.Append(NewLabelChangeOperation(rene, next(), []Label{"bug", "ui"}, []Label{"feature"}))
+	opp.Append(NewSetComponentOp(rene, next(), "core", ""))
+	opp.Append(NewAssigneeChangeOperation(rene, next(), []identity.Interface{isaac}, nil))
+	opp.Append(NewSetParentOp(rene, next(), otherBug))
+	opp.Append(NewRemoveParentOp(rene, next(), otherBug))
+	opp.Append(NewMarkDuplicateOp(rene, next(), otherBug))
+	opp.Append(checklistItem)
+	opp.Append(NewCheckItemOp(isaac, next(), checklistItem.Id(), true))
+	opp.Append(NewVoteOp(isaac, next(), false))
+	opp.Append(NewRequestReviewOp(rene, next(), []identity.Interface{isaac}, "what do you think?"))
+	opp.Append(NewSetMetadataOp(rene, next(), create.Id(), map[string]string{"imported": "true"}))
+	opp.Append(NewNoOpOp(rene, next()))
+	opp.Append(&CustomOperation{
+		OpBase: newOpBase(CustomOp, rene, next()),
+		Tag:    "example.com/fixture",
+		raw:    json.RawMessage(`{"value":"fixture"}`),
+	})
+
+	closing := NewSetStatusOp(isaac, next(), ClosedStatus)
+	closing.Resolution = FixedResolution
+	opp.Append(closing)
+
+	// as written in git
+	data, err := json.Marshal(opp)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		fmt.Sprintf("operation-pack-v%d.json", formatVersion): data,
+	}, nil
+}
+
+func fixtureIdentity(id string) (identity.Interface, error) {
+	return identity.UnmarshalJSON([]byte(fmt.Sprintf(`{"id":"%s"}`, id)))
+}
@@ -0,0 +1,77 @@
+package bug
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateFixtures = flag.Bool("update", false, "update the golden files of the fixtures")
+
+const fixturesDir = "testdata/fixtures"
+
+func TestFixtures(t *testing.T) {
+	fixtures, err := Fixtures()
+	require.NoError(t, err)
+
+	for name, data := range fixtures {
+		path := filepath.Join(fixturesDir, name)
+
+		if *updateFixtures {
+			require.NoError(t, ioutil.WriteFile(path, data, 0644))
+		}
+
+		golden, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(golden), string(data),
+			"the format of %s changed, run the tests with -update if it is intended", name)
+	}
+}
+
+// TestFixturesDecode check that the golden files of every format version can
+// still be read
+func TestFixturesDecode(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(fixturesDir, "operation-pack-*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+
+		var version struct {
+			Version uint `json:"version"`
+		}
+		require.NoError(t, json.Unmarshal(data, &version), path)
+
+		var opp OperationPack
+		require.NoError(t, json.Unmarshal(data, &opp), path)
+		require.NotEmpty(t, opp.Operations, path)
+
+		types := make(map[OperationType]bool)
+		for _, op := range opp.Operations {
+			// a newer format is kept as placeholders, the others are read
+			assert.Equal(t, version.Version > formatVersion, isUnknownOp(op), path)
+			types[op.base().OperationType] = true
+		}
+
+		if version.Version != formatVersion {
+			continue
+		}
+
+		// the current format is written back as is, and cover every type
+		again, err := json.Marshal(&opp)
+		require.NoError(t, err)
+		assert.Equal(t, string(data), string(again), path)
+
+		for opType := CreateOp; opType < endOfBuiltinOps; opType++ {
+			assert.True(t, types[opType], "no fixture for the operation type %d", opType)
+		}
+		assert.True(t, types[CustomOp], "no fixture for the custom operations")
+	}
+}
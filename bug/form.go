@@ -0,0 +1,249 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// The answers to a form are stored as metadata of the create operation, the
+// name of the form under FormMetadataKey and each answer under the id of its
+// field prefixed by FormAnswerMetadataPrefix.
+const (
+	FormMetadataKey          = "form"
+	FormAnswerMetadataPrefix = "form."
+)
+
+// FormFieldType is the type of a field of a form
+type FormFieldType string
+
+const (
+	// a single line of text
+	FormInput FormFieldType = "input"
+	// a text of several lines, as markdown
+	FormTextarea FormFieldType = "textarea"
+	// one of the options of the field
+	FormDropdown FormFieldType = "dropdown"
+	// a confirmation, "true" or "false"
+	FormCheckbox FormFieldType = "checkbox"
+)
+
+// FormField is a question of a form
+type FormField struct {
+	// the identifier of the field, the key of its answer
+	Id          string        `json:"id"`
+	Type        FormFieldType `json:"type"`
+	Label       string        `json:"label"`
+	Description string        `json:"description,omitempty"`
+	// the choices of a dropdown
+	Options []string `json:"options,omitempty"`
+	// a required checkbox must be checked
+	Required bool `json:"required,omitempty"`
+}
+
+// Form describe a structured bug report, as the issue forms of the forges
+type Form struct {
+	// the name of the form, from its file name
+	Name        string `json:"-"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// the labels of the bugs reported with the form
+	Labels []string    `json:"labels,omitempty"`
+	Fields []FormField `json:"fields"`
+}
+
+// ParseForm read the JSON definition of a form
+func ParseForm(name string, data []byte) (*Form, error) {
+	var form Form
+
+	err := json.Unmarshal(data, &form)
+	if err != nil {
+		return nil, fmt.Errorf("invalid form %s: %v", name, err)
+	}
+
+	form.Name = name
+
+	err = form.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid form %s: %v", name, err)
+	}
+
+	return &form, nil
+}
+
+// Validate check that the form is well defined
+func (f *Form) Validate() error {
+	if f.Name == "" || strings.ContainsAny(f.Name, " \t\n/\\") {
+		return fmt.Errorf("invalid form name %q", f.Name)
+	}
+
+	if text.Empty(f.Title) {
+		return fmt.Errorf("title is not set")
+	}
+
+	if len(f.Fields) == 0 {
+		return fmt.Errorf("the form has no field")
+	}
+
+	for _, label := range f.Labels {
+		if err := Label(label).Validate(); err != nil {
+			return err
+		}
+	}
+
+	ids := make(map[string]bool, len(f.Fields))
+	for _, field := range f.Fields {
+		if err := ValidateFieldName(field.Id); err != nil {
+			return err
+		}
+		if ids[field.Id] {
+			return fmt.Errorf("field %s is defined twice", field.Id)
+		}
+		ids[field.Id] = true
+
+		if text.Empty(field.Label) {
+			return fmt.Errorf("field %s has no label", field.Id)
+		}
+
+		switch field.Type {
+		case FormInput, FormTextarea, FormCheckbox:
+			if len(field.Options) > 0 {
+				return fmt.Errorf("field %s of type %s can't have options", field.Id, field.Type)
+			}
+		case FormDropdown:
+			if len(field.Options) == 0 {
+				return fmt.Errorf("dropdown %s has no option", field.Id)
+			}
+		default:
+			return fmt.Errorf("field %s has an unknown type %q", field.Id, field.Type)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAnswers check that the answers of the fields of the form, by id,
+// are valid and that the required fields are answered
+func (f *Form) ValidateAnswers(answers map[string]string) error {
+	for id := range answers {
+		if f.field(id) == nil {
+			return fmt.Errorf("unknown field %s in the form %s", id, f.Name)
+		}
+	}
+
+	for _, field := range f.Fields {
+		answer, answered := answers[field.Id]
+
+		switch field.Type {
+		case FormCheckbox:
+			if answered && answer != "true" && answer != "false" {
+				return fmt.Errorf("the answer of %s should be true or false", field.Id)
+			}
+			if field.Required && answer != "true" {
+				return fmt.Errorf("%s must be checked", field.Id)
+			}
+			continue
+
+		case FormDropdown:
+			if answered && !containsString(field.Options, answer) {
+				return fmt.Errorf("invalid answer %q for %s, expected one of %s",
+					answer, field.Id, strings.Join(field.Options, ", "))
+			}
+
+		case FormInput:
+			if strings.ContainsRune(answer, '\n') {
+				return fmt.Errorf("the answer of %s should be a single line", field.Id)
+			}
+		}
+
+		if field.Required && text.Empty(answer) {
+			return fmt.Errorf("%s is required", field.Id)
+		}
+		if !text.Safe(answer) {
+			return fmt.Errorf("the answer of %s is not fully printable", field.Id)
+		}
+	}
+
+	return nil
+}
+
+// Message return the description of a bug reported with the form, a section
+// for each answered field
+func (f *Form) Message(answers map[string]string) string {
+	var b strings.Builder
+
+	for _, field := range f.Fields {
+		answer := strings.TrimSpace(answers[field.Id])
+		if answer == "" {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+
+		if field.Type == FormCheckbox {
+			check := " "
+			if answer == "true" {
+				check = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] %s", check, field.Label)
+			continue
+		}
+
+		fmt.Fprintf(&b, "### %s\n\n%s", field.Label, answer)
+	}
+
+	return b.String()
+}
+
+// Metadata return the metadata of the create operation of a bug reported with
+// the form
+func (f *Form) Metadata(answers map[string]string) map[string]string {
+	metadata := make(map[string]string, len(answers)+1)
+	metadata[FormMetadataKey] = f.Name
+
+	for id, answer := range answers {
+		metadata[FormAnswerMetadataPrefix+id] = strings.TrimSpace(answer)
+	}
+
+	return metadata
+}
+
+// FormAnswers return the name of the form a bug was reported with and the
+// answers, from the metadata of its create operation
+func FormAnswers(op *CreateOperation) (string, map[string]string) {
+	name, ok := op.GetMetadata(FormMetadataKey)
+	if !ok {
+		return "", nil
+	}
+
+	answers := make(map[string]string)
+	for key, value := range op.AllMetadata() {
+		if strings.HasPrefix(key, FormAnswerMetadataPrefix) {
+			answers[strings.TrimPrefix(key, FormAnswerMetadataPrefix)] = value
+		}
+	}
+
+	return name, answers
+}
+
+func (f *Form) field(id string) *FormField {
+	for i := range f.Fields {
+		if f.Fields[i].Id == id {
+			return &f.Fields[i]
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package bug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForm(t *testing.T) {
+	_, err := ParseForm("crash", []byte(`{"title": "Crash", "fields": []}`))
+	require.Error(t, err)
+
+	_, err = ParseForm("crash", []byte(`{"title": "Crash", "fields": [{"id": "os", "type": "dropdown", "label": "OS"}]}`))
+	require.Error(t, err)
+
+	_, err = ParseForm("crash", []byte(`{"title": "Crash", "fields": [{"id": "os", "type": "radio", "label": "OS"}]}`))
+	require.Error(t, err)
+
+	_, err = ParseForm("crash", []byte(`{"title": "Crash", "fields": [
+		{"id": "os", "type": "input", "label": "OS"},
+		{"id": "os", "type": "input", "label": "OS"}
+	]}`))
+	require.Error(t, err)
+
+	form, err := ParseForm("crash", []byte(`{"title": "Crash", "fields": [{"id": "os", "type": "input", "label": "OS"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, "crash", form.Name)
+}
+
+func TestFormAnswers(t *testing.T) {
+	form := &Form{
+		Name:  "crash",
+		Title: "Crash",
+		Fields: []FormField{
+			{Id: "version", Type: FormInput, Label: "Version", Required: true},
+			{Id: "os", Type: FormDropdown, Label: "OS", Options: []string{"linux", "macos"}},
+			{Id: "steps", Type: FormTextarea, Label: "Steps"},
+			{Id: "searched", Type: FormCheckbox, Label: "I searched", Required: true},
+		},
+	}
+	require.NoError(t, form.Validate())
+
+	answers := map[string]string{"version": "1.2", "os": "linux", "searched": "true"}
+	require.NoError(t, form.ValidateAnswers(answers))
+	require.Equal(t, "### Version\n\n1.2\n\n### OS\n\nlinux\n\n- [x] I searched", form.Message(answers))
+
+	invalid := []map[string]string{
+		{"os": "linux", "searched": "true"},
+		{"version": "1.2", "os": "windows", "searched": "true"},
+		{"version": "1.2", "searched": "false"},
+		{"version": "1.2", "searched": "yes"},
+		{"version": "1\n2", "searched": "true"},
+		{"version": "1.2", "searched": "true", "unknown": "x"},
+	}
+	for _, answers := range invalid {
+		require.Error(t, form.ValidateAnswers(answers), answers)
+	}
+}
@@ -0,0 +1,42 @@
+package bug
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzOperationPack check that any data from a remote is either decoded or
+// refused, without panicking
+func FuzzOperationPack(f *testing.F) {
+	paths, _ := filepath.Glob(filepath.Join(fixturesDir, "*.json"))
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`{"version":1,"ops":[{"type":3,"author":{"id":"invalid"},"timestamp":1,"message":"message"}]}`))
+	f.Add([]byte(`{"version":1,"ops":[null]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = validateOperationPackData(data)
+
+		var opp OperationPack
+		if err := json.Unmarshal(data, &opp); err != nil {
+			return
+		}
+
+		snapshot := Snapshot{}
+		for _, op := range opp.Operations {
+			_ = op.Id()
+			op.Apply(&snapshot)
+		}
+
+		if _, err := json.Marshal(&opp); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
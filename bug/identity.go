@@ -1,6 +1,7 @@
 package bug
 
 import (
+	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/identity"
 )
 
@@ -22,6 +23,69 @@ func (bug *Bug) EnsureIdentities(resolver identity.Resolver) error {
 
 			base.Author = i
 		}
+
+		if op, ok := op.(*AssigneeChangeOperation); ok {
+			if err := resolveIdentities(resolver, op.Added); err != nil {
+				return err
+			}
+			if err := resolveIdentities(resolver, op.Removed); err != nil {
+				return err
+			}
+		}
+
+		if op, ok := op.(*RequestReviewOperation); ok {
+			if err := resolveIdentities(resolver, op.Reviewers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func resolveIdentities(resolver identity.Resolver, identities []identity.Interface) error {
+	for j, id := range identities {
+		if stub, ok := id.(*identity.IdentityStub); ok {
+			i, err := resolver.ResolveIdentity(stub.Id())
+			if err != nil {
+				return err
+			}
+
+			identities[j] = i
+		}
 	}
 	return nil
 }
+
+// IdentitiesOf return the ids of the identities involved in the operations: the
+// authors, the assignees and the reviewers, without duplicate
+func IdentitiesOf(ops []Operation) []entity.Id {
+	seen := make(map[entity.Id]bool)
+	var result []entity.Id
+
+	add := func(i identity.Interface) {
+		if seen[i.Id()] {
+			return
+		}
+		seen[i.Id()] = true
+		result = append(result, i.Id())
+	}
+
+	for _, op := range ops {
+		add(op.GetAuthor())
+		switch op := op.(type) {
+		case *AssigneeChangeOperation:
+			for _, i := range op.Added {
+				add(i)
+			}
+			for _, i := range op.Removed {
+				add(i)
+			}
+		case *RequestReviewOperation:
+			for _, i := range op.Reviewers {
+				add(i)
+			}
+		}
+	}
+
+	return result
+}
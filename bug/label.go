@@ -4,6 +4,7 @@ import (
 	"crypto/sha1"
 	"fmt"
 	"image/color"
+	"strconv"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/util/text"
@@ -56,6 +57,31 @@ func (lc LabelColor) RGBA() color.RGBA {
 	return color.RGBA(lc)
 }
 
+// ParseLabelColor parse a color written as "#rrggbb"
+func ParseLabelColor(raw string) (LabelColor, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(raw), "#")
+	if len(hex) != 6 {
+		return LabelColor{}, fmt.Errorf("invalid color %s, expected #rrggbb", raw)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return LabelColor{}, fmt.Errorf("invalid color %s, expected #rrggbb", raw)
+	}
+
+	return LabelColor{
+		R: uint8(value >> 16),
+		G: uint8(value >> 8),
+		B: uint8(value),
+		A: 255,
+	}, nil
+}
+
+// Hex return the color written as "#rrggbb"
+func (lc LabelColor) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", lc.R, lc.G, lc.B)
+}
+
 type Term256 int
 
 func (lc LabelColor) Term256() Term256 {
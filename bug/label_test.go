@@ -33,3 +33,15 @@ func TestLabelRGBAEqual(t *testing.T) {
 
 	require.Equal(t, color1, color2)
 }
+
+func TestParseLabelColor(t *testing.T) {
+	lc, err := ParseLabelColor("#ff5722")
+	require.NoError(t, err)
+	require.Equal(t, Label("test").Color(), lc)
+	require.Equal(t, "#ff5722", lc.Hex())
+
+	_, err = ParseLabelColor("red")
+	require.Error(t, err)
+	_, err = ParseLabelColor("#gg0000")
+	require.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/text"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &AddChecklistItemOperation{}
+
+// AddChecklistItemOperation will add an unchecked item at the end of the
+// checklist of a bug
+type AddChecklistItemOperation struct {
+	OpBase
+	Text string `json:"text"`
+}
+
+// Sign-post method for gqlgen
+func (op *AddChecklistItemOperation) IsOperation() {}
+
+func (op *AddChecklistItemOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *AddChecklistItemOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *AddChecklistItemOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author)
+
+	snapshot.Checklist = append(snapshot.Checklist, ChecklistItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		Text:     op.Text,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+	})
+
+	item := &AddChecklistItemTimelineItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+		Text:     op.Text,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *AddChecklistItemOperation) Validate() error {
+	if err := opBaseValidate(op, AddChecklistItemOp); err != nil {
+		return err
+	}
+
+	if text.Empty(op.Text) {
+		return fmt.Errorf("checklist item is empty")
+	}
+
+	if strings.Contains(op.Text, "\n") {
+		return fmt.Errorf("checklist item should be a single line")
+	}
+
+	if !text.Safe(op.Text) {
+		return fmt.Errorf("checklist item should be fully printable")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *AddChecklistItemOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Text string `json:"text"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Text = aux.Text
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *AddChecklistItemOperation) IsAuthored() {}
+
+func NewAddChecklistItemOp(author identity.Interface, unixTime int64, text string) *AddChecklistItemOperation {
+	return &AddChecklistItemOperation{
+		OpBase: newOpBase(AddChecklistItemOp, author, unixTime),
+		Text:   text,
+	}
+}
+
+type AddChecklistItemTimelineItem struct {
+	id       entity.Id
+	Author   identity.Interface
+	UnixTime timestamp.Timestamp
+	Text     string
+}
+
+func (a AddChecklistItemTimelineItem) Id() entity.Id {
+	return a.id
+}
+
+// Sign post method for gqlgen
+func (a *AddChecklistItemTimelineItem) IsAuthored() {}
+
+// Convenience function to apply the operation
+func AddChecklistItem(b Interface, author identity.Interface, unixTime int64, text string) (*AddChecklistItemOperation, error) {
+	op := NewAddChecklistItemOp(author, unixTime, text)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
@@ -0,0 +1,80 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func TestAddChecklistItemSerialize(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	before := NewAddChecklistItemOp(rene, unix, "write the tests")
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after AddChecklistItemOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the IDs
+	before.Id()
+	rene.Id()
+
+	assert.Equal(t, before, &after)
+}
+
+func TestCheckItemSerialize(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	before := NewCheckItemOp(rene, unix, entity.Id("0123456789012345678901234567890123456789"), true)
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after CheckItemOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the IDs
+	before.Id()
+	rene.Id()
+
+	assert.Equal(t, before, &after)
+}
+
+func TestChecklistApply(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	snapshot := Snapshot{}
+
+	add1 := NewAddChecklistItemOp(rene, unix, "write the code")
+	add1.Apply(&snapshot)
+	add2 := NewAddChecklistItemOp(rene, unix+1, "write the tests")
+	add2.Apply(&snapshot)
+
+	NewCheckItemOp(rene, unix, add2.Id(), true).Apply(&snapshot)
+
+	checked, total := snapshot.ChecklistProgress()
+	assert.Equal(t, 1, checked)
+	assert.Equal(t, 2, total)
+	assert.False(t, snapshot.Checklist[0].Checked)
+	assert.True(t, snapshot.Checklist[1].Checked)
+	assert.Equal(t, add2.Id(), snapshot.Checklist[1].Id())
+
+	// checking an unknown item is a no-op
+	NewCheckItemOp(rene, unix, entity.Id("0123456789012345678901234567890123456789"), true).Apply(&snapshot)
+	assert.Len(t, snapshot.Timeline, 3)
+
+	NewCheckItemOp(rene, unix, add2.Id(), false).Apply(&snapshot)
+	checked, _ = snapshot.ChecklistProgress()
+	assert.Equal(t, 0, checked)
+	assert.Len(t, snapshot.Timeline, 4)
+}
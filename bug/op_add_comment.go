@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/pkg/errors"
+
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/util/git"
@@ -19,6 +21,8 @@ type AddCommentOperation struct {
 	Message string `json:"message"`
 	// TODO: change for a map[string]util.hash to store the filename ?
 	Files []git.Hash `json:"files"`
+	// the comment this one reply to, if any
+	ReplyTo entity.Id `json:"replyTo,omitempty"`
 }
 
 // Sign-post method for gqlgen
@@ -41,6 +45,7 @@ func (op *AddCommentOperation) Apply(snapshot *Snapshot) {
 		Message:  op.Message,
 		Author:   op.Author,
 		Files:    op.Files,
+		ReplyTo:  op.ReplyTo,
 		UnixTime: timestamp.Timestamp(op.UnixTime),
 	}
 
@@ -48,6 +53,7 @@ func (op *AddCommentOperation) Apply(snapshot *Snapshot) {
 
 	item := &AddCommentTimelineItem{
 		CommentTimelineItem: NewCommentTimelineItem(op.Id(), comment),
+		ReplyTo:             op.ReplyTo,
 	}
 
 	snapshot.Timeline = append(snapshot.Timeline, item)
@@ -66,6 +72,12 @@ func (op *AddCommentOperation) Validate() error {
 		return fmt.Errorf("message is not fully printable")
 	}
 
+	if op.ReplyTo != "" {
+		if err := op.ReplyTo.Validate(); err != nil {
+			return errors.Wrap(err, "reply to")
+		}
+	}
+
 	return nil
 }
 
@@ -84,6 +96,7 @@ func (op *AddCommentOperation) UnmarshalJSON(data []byte) error {
 	aux := struct {
 		Message string     `json:"message"`
 		Files   []git.Hash `json:"files"`
+		ReplyTo entity.Id  `json:"replyTo"`
 	}{}
 
 	err = json.Unmarshal(data, &aux)
@@ -94,6 +107,7 @@ func (op *AddCommentOperation) UnmarshalJSON(data []byte) error {
 	op.OpBase = base
 	op.Message = aux.Message
 	op.Files = aux.Files
+	op.ReplyTo = aux.ReplyTo
 
 	return nil
 }
@@ -109,9 +123,17 @@ func NewAddCommentOp(author identity.Interface, unixTime int64, message string,
 	}
 }
 
+func NewAddCommentReplyOp(author identity.Interface, unixTime int64, replyTo entity.Id, message string, files []git.Hash) *AddCommentOperation {
+	op := NewAddCommentOp(author, unixTime, message, files)
+	op.ReplyTo = replyTo
+	return op
+}
+
 // CreateTimelineItem replace a AddComment operation in the Timeline and hold its edition history
 type AddCommentTimelineItem struct {
 	CommentTimelineItem
+	// the comment this one reply to, if any
+	ReplyTo entity.Id
 }
 
 // Sign post method for gqlgen
@@ -130,3 +152,19 @@ func AddCommentWithFiles(b Interface, author identity.Interface, unixTime int64,
 	b.Append(addCommentOp)
 	return addCommentOp, nil
 }
+
+// AddCommentReply is a convenience function to reply to a comment of the bug
+func AddCommentReply(b Interface, author identity.Interface, unixTime int64, replyTo entity.Id, message string, files []git.Hash) (*AddCommentOperation, error) {
+	snap := b.Compile()
+
+	if _, err := snap.SearchComment(replyTo); err != nil {
+		return nil, err
+	}
+
+	addCommentOp := NewAddCommentReplyOp(author, unixTime, replyTo, message, files)
+	if err := addCommentOp.Validate(); err != nil {
+		return nil, err
+	}
+	b.Append(addCommentOp)
+	return addCommentOp, nil
+}
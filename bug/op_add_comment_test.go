@@ -28,3 +28,27 @@ func TestAddCommentSerialize(t *testing.T) {
 
 	assert.Equal(t, before, &after)
 }
+
+func TestAddCommentReplySerialize(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	before := NewAddCommentReplyOp(rene, unix, "a3fb7c1c1d2f6d9e4f3a0b1c2d3e4f5a6b7c8d9e", "message", nil)
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after AddCommentOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the IDs
+	before.Id()
+	rene.Id()
+
+	assert.Equal(t, before, &after)
+
+	// a comment without a reply doesn't change the format
+	data, err = json.Marshal(NewAddCommentOp(rene, unix, "message", nil))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "replyTo")
+}
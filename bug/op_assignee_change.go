@@ -0,0 +1,213 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &AssigneeChangeOperation{}
+
+// AssigneeChangeOperation define a Bug operation to assign or unassign
+// identities
+type AssigneeChangeOperation struct {
+	OpBase
+	Added   []identity.Interface `json:"added"`
+	Removed []identity.Interface `json:"removed"`
+}
+
+// Sign-post method for gqlgen
+func (op *AssigneeChangeOperation) IsOperation() {}
+
+func (op *AssigneeChangeOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *AssigneeChangeOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+// Apply apply the operation
+func (op *AssigneeChangeOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author)
+
+	for _, added := range op.Added {
+		if !snapshot.IsAssigned(added.Id()) {
+			snapshot.Assignees = append(snapshot.Assignees, added)
+		}
+	}
+
+	for _, removed := range op.Removed {
+		for i, assignee := range snapshot.Assignees {
+			if assignee.Id() == removed.Id() {
+				snapshot.Assignees = append(snapshot.Assignees[:i], snapshot.Assignees[i+1:]...)
+				break
+			}
+		}
+	}
+
+	item := &AssigneeChangeTimelineItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+		Added:    op.Added,
+		Removed:  op.Removed,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *AssigneeChangeOperation) Validate() error {
+	if err := opBaseValidate(op, AssigneeChangeOp); err != nil {
+		return err
+	}
+
+	for _, i := range op.Added {
+		if err := i.Validate(); err != nil {
+			return errors.Wrap(err, "added assignee")
+		}
+	}
+
+	for _, i := range op.Removed {
+		if err := i.Validate(); err != nil {
+			return errors.Wrap(err, "removed assignee")
+		}
+	}
+
+	if len(op.Added)+len(op.Removed) <= 0 {
+		return fmt.Errorf("no assignee change")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *AssigneeChangeOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Added   []json.RawMessage `json:"added"`
+		Removed []json.RawMessage `json:"removed"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	added, err := unmarshalIdentities(aux.Added)
+	if err != nil {
+		return err
+	}
+
+	removed, err := unmarshalIdentities(aux.Removed)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Added = added
+	op.Removed = removed
+
+	return nil
+}
+
+func unmarshalIdentities(raws []json.RawMessage) ([]identity.Interface, error) {
+	if raws == nil {
+		return nil, nil
+	}
+
+	result := make([]identity.Interface, len(raws))
+	for i, raw := range raws {
+		id, err := identity.UnmarshalJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = id
+	}
+
+	return result, nil
+}
+
+// Sign post method for gqlgen
+func (op *AssigneeChangeOperation) IsAuthored() {}
+
+func NewAssigneeChangeOperation(author identity.Interface, unixTime int64, added, removed []identity.Interface) *AssigneeChangeOperation {
+	return &AssigneeChangeOperation{
+		OpBase:  newOpBase(AssigneeChangeOp, author, unixTime),
+		Added:   added,
+		Removed: removed,
+	}
+}
+
+type AssigneeChangeTimelineItem struct {
+	id       entity.Id
+	Author   identity.Interface
+	UnixTime timestamp.Timestamp
+	Added    []identity.Interface
+	Removed  []identity.Interface
+}
+
+func (a AssigneeChangeTimelineItem) Id() entity.Id {
+	return a.id
+}
+
+// Sign post method for gqlgen
+func (a *AssigneeChangeTimelineItem) IsAuthored() {}
+
+// ChangeAssignees is a convenience function to apply the operation.
+// Identities already assigned, or not assigned when removed, are ignored.
+func ChangeAssignees(b Interface, author identity.Interface, unixTime int64, add, remove []identity.Interface) (*AssigneeChangeOperation, error) {
+	var added, removed []identity.Interface
+
+	snap := b.Compile()
+
+	for _, i := range add {
+		if !snap.IsAssigned(i.Id()) && !identityExist(added, i) {
+			added = append(added, i)
+		}
+	}
+
+	for _, i := range remove {
+		if snap.IsAssigned(i.Id()) && !identityExist(removed, i) {
+			removed = append(removed, i)
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return nil, fmt.Errorf("no assignee added or removed")
+	}
+
+	op := NewAssigneeChangeOperation(author, unixTime, added, removed)
+
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+
+	return op, nil
+}
+
+func identityExist(identities []identity.Interface, i identity.Interface) bool {
+	for _, other := range identities {
+		if other.Id() == i.Id() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestAssigneeChangeSerialize(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	isaac := identity.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	assert.NoError(t, rene.Commit(repo))
+	assert.NoError(t, isaac.Commit(repo))
+
+	unix := time.Now().Unix()
+	before := NewAssigneeChangeOperation(rene, unix, []identity.Interface{isaac}, nil)
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after AssigneeChangeOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the ID
+	before.Id()
+
+	// the identities are stubs until resolved
+	assert.Equal(t, before.Id(), after.Id())
+	assert.Len(t, after.Added, 1)
+	assert.Equal(t, isaac.Id(), after.Added[0].Id())
+	assert.Empty(t, after.Removed)
+}
+
+func TestChangeAssignees(t *testing.T) {
+	rene := identity.NewBare("René Descartes", "rene@descartes.fr")
+	isaac := identity.NewBare("Isaac Newton", "isaac@newton.uk")
+	unix := time.Now().Unix()
+
+	b := NewBug()
+	create := NewCreateOp(rene, unix, "title", "message", nil)
+	b.Append(create)
+
+	_, err := ChangeAssignees(b, rene, unix, []identity.Interface{isaac, isaac}, nil)
+	assert.NoError(t, err)
+
+	snap := b.Compile()
+	assert.True(t, snap.IsAssigned(isaac.Id()))
+	assert.Len(t, snap.Assignees, 1)
+
+	// isaac is already assigned
+	_, err = ChangeAssignees(b, rene, unix, []identity.Interface{isaac}, nil)
+	assert.Error(t, err)
+
+	_, err = ChangeAssignees(b, rene, unix, nil, []identity.Interface{isaac})
+	assert.NoError(t, err)
+
+	snap = b.Compile()
+	assert.False(t, snap.IsAssigned(isaac.Id()))
+	assert.Empty(t, snap.Assignees)
+}
@@ -0,0 +1,150 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &CheckItemOperation{}
+
+// CheckItemOperation will check or uncheck an item of the checklist of a bug
+type CheckItemOperation struct {
+	OpBase
+	// the id of the operation that added the item
+	Target  entity.Id `json:"target"`
+	Checked bool      `json:"checked"`
+}
+
+// Sign-post method for gqlgen
+func (op *CheckItemOperation) IsOperation() {}
+
+func (op *CheckItemOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *CheckItemOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *CheckItemOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author)
+
+	target, err := snapshot.SearchChecklistItem(op.Target)
+	if err != nil {
+		// Target not found, the check is a no-op
+		return
+	}
+
+	target.Checked = op.Checked
+
+	item := &CheckItemTimelineItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+		Target:   op.Target,
+		Text:     target.Text,
+		Checked:  op.Checked,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *CheckItemOperation) Validate() error {
+	if err := opBaseValidate(op, CheckItemOp); err != nil {
+		return err
+	}
+
+	if err := op.Target.Validate(); err != nil {
+		return errors.Wrap(err, "target hash is invalid")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *CheckItemOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Target  entity.Id `json:"target"`
+		Checked bool      `json:"checked"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Target = aux.Target
+	op.Checked = aux.Checked
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *CheckItemOperation) IsAuthored() {}
+
+func NewCheckItemOp(author identity.Interface, unixTime int64, target entity.Id, checked bool) *CheckItemOperation {
+	return &CheckItemOperation{
+		OpBase:  newOpBase(CheckItemOp, author, unixTime),
+		Target:  target,
+		Checked: checked,
+	}
+}
+
+type CheckItemTimelineItem struct {
+	id       entity.Id
+	Author   identity.Interface
+	UnixTime timestamp.Timestamp
+	Target   entity.Id
+	// the text of the item, as it was when checked
+	Text    string
+	Checked bool
+}
+
+func (c CheckItemTimelineItem) Id() entity.Id {
+	return c.id
+}
+
+// Sign post method for gqlgen
+func (c *CheckItemTimelineItem) IsAuthored() {}
+
+// Convenience function to apply the operation
+func CheckItem(b Interface, author identity.Interface, unixTime int64, target entity.Id, checked bool) (*CheckItemOperation, error) {
+	snap := b.Compile()
+
+	item, err := snap.SearchChecklistItem(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Checked == checked {
+		if checked {
+			return nil, fmt.Errorf("checklist item already checked")
+		}
+		return nil, fmt.Errorf("checklist item already unchecked")
+	}
+
+	op := NewCheckItemOp(author, unixTime, target, checked)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
@@ -3,6 +3,7 @@ package bug
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/entity"
@@ -20,6 +21,10 @@ type CreateOperation struct {
 	Title   string     `json:"title"`
 	Message string     `json:"message"`
 	Files   []git.Hash `json:"files"`
+	// the labels set on creation, if any
+	Labels []Label `json:"labels,omitempty"`
+	// the custom fields of the bug, as a version, if any
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // Sign-post method for gqlgen
@@ -39,6 +44,20 @@ func (op *CreateOperation) Apply(snapshot *Snapshot) {
 
 	snapshot.Title = op.Title
 
+	if len(op.Labels) > 0 {
+		snapshot.Labels = append([]Label(nil), op.Labels...)
+		sort.Slice(snapshot.Labels, func(i, j int) bool {
+			return string(snapshot.Labels[i]) < string(snapshot.Labels[j])
+		})
+	}
+
+	if len(op.Fields) > 0 {
+		snapshot.Fields = make(map[string]string, len(op.Fields))
+		for name, value := range op.Fields {
+			snapshot.Fields[name] = value
+		}
+	}
+
 	comment := Comment{
 		id:       op.Id(),
 		Message:  op.Message,
@@ -49,6 +68,7 @@ func (op *CreateOperation) Apply(snapshot *Snapshot) {
 	snapshot.Comments = []Comment{comment}
 	snapshot.Author = op.Author
 	snapshot.CreatedAt = op.Time()
+	snapshot.Anonymous = identity.IsAnonymous(op.Author)
 
 	snapshot.Timeline = []TimelineItem{
 		&CreateTimelineItem{
@@ -82,6 +102,121 @@ func (op *CreateOperation) Validate() error {
 		return fmt.Errorf("message is not fully printable")
 	}
 
+	seen := make(map[Label]bool)
+	for _, label := range op.Labels {
+		if err := label.Validate(); err != nil {
+			return fmt.Errorf("label: %v", err)
+		}
+		if seen[label] {
+			return fmt.Errorf("duplicated label %s", label)
+		}
+		seen[label] = true
+	}
+
+	for name, value := range op.Fields {
+		if err := ValidateFieldName(name); err != nil {
+			return err
+		}
+		if text.Empty(value) {
+			return fmt.Errorf("field %s is empty", name)
+		}
+		if strings.Contains(value, "\n") {
+			return fmt.Errorf("field %s should be a single line", name)
+		}
+		if !text.Safe(value) {
+			return fmt.Errorf("field %s is not fully printable", name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateFieldName check that the name of a custom field is a single word,
+// as "version"
+func ValidateFieldName(name string) error {
+	if text.Empty(name) {
+		return fmt.Errorf("field name is empty")
+	}
+
+	if strings.ContainsAny(name, " \t\n=:,") {
+		return fmt.Errorf("invalid field name %q", name)
+	}
+
+	if !text.Safe(name) {
+		return fmt.Errorf("field name is not fully printable")
+	}
+
+	return nil
+}
+
+// Requirements are the labels and the custom fields a new bug must have
+type Requirements struct {
+	// the labels a new bug must have. A label ending with ":", as "type:", is
+	// a prefix: any label starting with it satisfies it.
+	Labels []string
+	// the names of the custom fields a new bug must set
+	Fields []string
+}
+
+// IsEmpty tell if there is nothing to require
+func (r Requirements) IsEmpty() bool {
+	return len(r.Labels) == 0 && len(r.Fields) == 0
+}
+
+// MissingLabels return the required labels, or label prefixes, the given
+// labels don't satisfy
+func (r Requirements) MissingLabels(labels []Label) []string {
+	var missing []string
+
+	for _, required := range r.Labels {
+		found := false
+		for _, label := range labels {
+			if MatchRequiredLabel(required, label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, required)
+		}
+	}
+
+	return missing
+}
+
+// MissingFields return the required custom fields not set
+func (r Requirements) MissingFields(fields map[string]string) []string {
+	var missing []string
+
+	for _, required := range r.Fields {
+		if text.Empty(fields[required]) {
+			missing = append(missing, required)
+		}
+	}
+
+	return missing
+}
+
+// MatchRequiredLabel tell if a label satisfy a required label, or label
+// prefix when it ends with ":"
+func MatchRequiredLabel(required string, label Label) bool {
+	if strings.HasSuffix(required, ":") {
+		return strings.HasPrefix(string(label), required) && len(label) > len(required)
+	}
+	return string(label) == required
+}
+
+// ValidateRequirements check that the creation set the labels and custom
+// fields required by a repository
+func (op *CreateOperation) ValidateRequirements(r Requirements) error {
+	if missing := r.MissingLabels(op.Labels); len(missing) > 0 {
+		return fmt.Errorf("missing required label %s", strings.Join(missing, ", "))
+	}
+
+	if missing := r.MissingFields(op.Fields); len(missing) > 0 {
+		return fmt.Errorf("missing required field %s", strings.Join(missing, ", "))
+	}
+
 	return nil
 }
 
@@ -98,9 +233,11 @@ func (op *CreateOperation) UnmarshalJSON(data []byte) error {
 	}
 
 	aux := struct {
-		Title   string     `json:"title"`
-		Message string     `json:"message"`
-		Files   []git.Hash `json:"files"`
+		Title   string            `json:"title"`
+		Message string            `json:"message"`
+		Files   []git.Hash        `json:"files"`
+		Labels  []Label           `json:"labels"`
+		Fields  map[string]string `json:"fields"`
 	}{}
 
 	err = json.Unmarshal(data, &aux)
@@ -112,6 +249,8 @@ func (op *CreateOperation) UnmarshalJSON(data []byte) error {
 	op.Title = aux.Title
 	op.Message = aux.Message
 	op.Files = aux.Files
+	op.Labels = aux.Labels
+	op.Fields = aux.Fields
 
 	return nil
 }
@@ -142,9 +281,26 @@ func Create(author identity.Interface, unixTime int64, title, message string) (*
 }
 
 func CreateWithFiles(author identity.Interface, unixTime int64, title, message string, files []git.Hash) (*Bug, *CreateOperation, error) {
+	return CreateWithFields(author, unixTime, title, message, nil, nil, files)
+}
+
+// CreateWithFields create a bug with some labels and custom fields set from
+// the start
+func CreateWithFields(author identity.Interface, unixTime int64, title, message string, labels []string, fields map[string]string, files []git.Hash) (*Bug, *CreateOperation, error) {
 	newBug := NewBug()
 	createOp := NewCreateOp(author, unixTime, title, message, files)
 
+	for _, label := range labels {
+		createOp.Labels = append(createOp.Labels, Label(strings.TrimSpace(label)))
+	}
+
+	if len(fields) > 0 {
+		createOp.Fields = make(map[string]string, len(fields))
+		for name, value := range fields {
+			createOp.Fields[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
 	if err := createOp.Validate(); err != nil {
 		return nil, createOp, err
 	}
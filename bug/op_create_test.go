@@ -8,6 +8,7 @@ import (
 	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/util/timestamp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreate(t *testing.T) {
@@ -67,3 +68,58 @@ func TestCreateSerialize(t *testing.T) {
 
 	assert.Equal(t, before, &after)
 }
+
+func TestCreateWithFields(t *testing.T) {
+	rene := identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	b, op, err := CreateWithFields(rene, unix, "title", "message",
+		[]string{"type:bug", "core"}, map[string]string{"version": " 1.2 "}, nil)
+	require.NoError(t, err)
+
+	snap := b.Compile()
+	require.Equal(t, []Label{"core", "type:bug"}, snap.Labels)
+	require.Equal(t, map[string]string{"version": "1.2"}, snap.Fields)
+
+	data, err := json.Marshal(op)
+	require.NoError(t, err)
+
+	var after CreateOperation
+	err = json.Unmarshal(data, &after)
+	require.NoError(t, err)
+	require.Equal(t, op.Labels, after.Labels)
+	require.Equal(t, op.Fields, after.Fields)
+
+	_, _, err = CreateWithFields(rene, unix, "title", "message", []string{"bug", "bug"}, nil, nil)
+	require.Error(t, err)
+
+	_, _, err = CreateWithFields(rene, unix, "title", "message", nil, map[string]string{"a field": "value"}, nil)
+	require.Error(t, err)
+
+	_, _, err = CreateWithFields(rene, unix, "title", "message", nil, map[string]string{"version": ""}, nil)
+	require.Error(t, err)
+}
+
+func TestCreateRequirements(t *testing.T) {
+	rene := identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	requirements := Requirements{
+		Labels: []string{"type:", "triage"},
+		Fields: []string{"version"},
+	}
+
+	op := NewCreateOp(rene, unix, "title", "message", nil)
+	require.Error(t, op.ValidateRequirements(requirements))
+	require.Equal(t, []string{"type:", "triage"}, requirements.MissingLabels(op.Labels))
+	require.Equal(t, []string{"version"}, requirements.MissingFields(op.Fields))
+
+	// the prefix alone doesn't satisfy it
+	op.Labels = []Label{"type:", "triage"}
+	op.Fields = map[string]string{"version": "1.2"}
+	require.Error(t, op.ValidateRequirements(requirements))
+
+	op.Labels = []Label{"type:bug", "triage"}
+	require.NoError(t, op.ValidateRequirements(requirements))
+	require.NoError(t, op.ValidateRequirements(Requirements{}))
+}
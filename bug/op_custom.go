@@ -0,0 +1,203 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+var _ Operation = &CustomOperation{}
+
+// OperationPayload is the data of a custom operation type, defined by a
+// program embedding git-bug and registered with RegisterOperationType.
+type OperationPayload interface {
+	// Validate check if the data is valid
+	Validate() error
+	// Apply the operation to a Snapshot, after the author has been added to
+	// the actors
+	Apply(op *CustomOperation, snapshot *Snapshot)
+}
+
+var operationPayloads = make(map[string]func() OperationPayload)
+
+// RegisterOperationType register a custom operation type under a tag, as
+// "example.com/due-date". newPayload return an empty payload to decode the
+// stored operations into. It has to be called before reading any bug,
+// typically from an init function. It panics if the tag is already used.
+//
+// The operations of the tags not registered, written by another program, are
+// kept as they are but not applied.
+func RegisterOperationType(tag string, newPayload func() OperationPayload) {
+	if err := validateOperationTag(tag); err != nil {
+		panic(err)
+	}
+	if _, ok := operationPayloads[tag]; ok {
+		panic(fmt.Sprintf("operation type %s already registered", tag))
+	}
+	operationPayloads[tag] = newPayload
+}
+
+func validateOperationTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("empty operation tag")
+	}
+	if strings.ContainsAny(tag, " \t\r\n") {
+		return fmt.Errorf("operation tag %q has spaces", tag)
+	}
+	return nil
+}
+
+// CustomOperation is an operation of a type registered with
+// RegisterOperationType, told apart from the others by its tag.
+type CustomOperation struct {
+	OpBase
+	Tag string `json:"tag"`
+	// the decoded data, nil if the tag is not registered
+	Payload OperationPayload `json:"-"`
+
+	// Not serialized. The data as stored, written back as is when the tag is
+	// not registered.
+	raw json.RawMessage
+}
+
+// Sign-post method for gqlgen
+func (op *CustomOperation) IsOperation() {}
+
+func (op *CustomOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *CustomOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+// IsRegistered tell if the type of the operation is registered, that is if
+// the operation is applied
+func (op *CustomOperation) IsRegistered() bool {
+	return op.Payload != nil
+}
+
+// Data return the data of the operation, as JSON
+func (op *CustomOperation) Data() (json.RawMessage, error) {
+	if op.Payload == nil {
+		return op.raw, nil
+	}
+	return json.Marshal(op.Payload)
+}
+
+func (op *CustomOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author)
+
+	if op.Payload != nil {
+		op.Payload.Apply(op, snapshot)
+	}
+}
+
+func (op *CustomOperation) Validate() error {
+	if err := opBaseValidate(op, CustomOp); err != nil {
+		return err
+	}
+
+	if err := validateOperationTag(op.Tag); err != nil {
+		return err
+	}
+
+	if op.Payload == nil {
+		if !json.Valid(op.raw) {
+			return fmt.Errorf("invalid data of the operation %s", op.Tag)
+		}
+		return nil
+	}
+
+	return errors.Wrap(op.Payload.Validate(), op.Tag)
+}
+
+func (op *CustomOperation) MarshalJSON() ([]byte, error) {
+	data, err := op.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		OpBase
+		Tag  string          `json:"tag"`
+		Data json.RawMessage `json:"data"`
+	}{
+		OpBase: op.OpBase,
+		Tag:    op.Tag,
+		Data:   data,
+	})
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *CustomOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Tag  string          `json:"tag"`
+		Data json.RawMessage `json:"data"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Tag = aux.Tag
+	op.raw = aux.Data
+	op.Payload = nil
+
+	newPayload, ok := operationPayloads[aux.Tag]
+	if !ok {
+		return nil
+	}
+
+	payload := newPayload()
+	err = json.Unmarshal(aux.Data, payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode the operation %s", aux.Tag)
+	}
+	op.Payload = payload
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *CustomOperation) IsAuthored() {}
+
+func NewCustomOp(author identity.Interface, unixTime int64, tag string, payload OperationPayload) *CustomOperation {
+	return &CustomOperation{
+		OpBase:  newOpBase(CustomOp, author, unixTime),
+		Tag:     tag,
+		Payload: payload,
+	}
+}
+
+// Convenience function to apply the operation
+func AddCustomOperation(b Interface, author identity.Interface, unixTime int64, tag string, payload OperationPayload) (*CustomOperation, error) {
+	if _, ok := operationPayloads[tag]; !ok {
+		return nil, fmt.Errorf("unknown operation type %s", tag)
+	}
+
+	op := NewCustomOp(author, unixTime, tag, payload)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
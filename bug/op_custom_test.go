@@ -0,0 +1,95 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const dueDateTag = "example.com/due-date"
+
+// dueDatePayload is a custom operation setting the due date of a bug in its
+// custom fields
+type dueDatePayload struct {
+	Due string `json:"due"`
+}
+
+func (p *dueDatePayload) Validate() error {
+	if p.Due == "" {
+		return fmt.Errorf("no due date")
+	}
+	return nil
+}
+
+func (p *dueDatePayload) Apply(op *CustomOperation, snapshot *Snapshot) {
+	if snapshot.Fields == nil {
+		snapshot.Fields = make(map[string]string)
+	}
+	snapshot.Fields["due"] = p.Due
+}
+
+func init() {
+	RegisterOperationType(dueDateTag, func() OperationPayload { return &dueDatePayload{} })
+}
+
+func TestCustomOperationSerialize(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	assert.NoError(t, rene.Commit(repo))
+
+	unix := time.Now().Unix()
+	before := NewCustomOp(rene, unix, dueDateTag, &dueDatePayload{Due: "2024-01-15"})
+	require.NoError(t, before.Validate())
+
+	data, err := json.Marshal(before)
+	require.NoError(t, err)
+
+	var after CustomOperation
+	err = json.Unmarshal(data, &after)
+	require.NoError(t, err)
+
+	assert.Equal(t, before.Id(), after.Id())
+	assert.True(t, after.IsRegistered())
+	assert.Equal(t, &dueDatePayload{Due: "2024-01-15"}, after.Payload)
+
+	assert.Error(t, NewCustomOp(rene, unix, dueDateTag, &dueDatePayload{}).Validate())
+	assert.Panics(t, func() {
+		RegisterOperationType(dueDateTag, func() OperationPayload { return &dueDatePayload{} })
+	})
+}
+
+func TestCustomOperationUnknown(t *testing.T) {
+	data := []byte(`{"type":1000,"author":{"name":"René Descartes","email":"rene@descartes.fr"},"timestamp":1,"tag":"example.com/other","data":{"a":[1,2],"b":"c"}}`)
+
+	var op CustomOperation
+	require.NoError(t, json.Unmarshal(data, &op))
+	assert.False(t, op.IsRegistered())
+	assert.NoError(t, op.Validate())
+
+	// the operation is written back as is
+	again, err := json.Marshal(&op)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+
+	snapshot := Snapshot{}
+	op.Apply(&snapshot)
+	assert.Len(t, snapshot.Actors, 1)
+}
+
+func TestCustomOperationApply(t *testing.T) {
+	rene := identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	snapshot := Snapshot{}
+
+	NewCreateOp(rene, unix, "title", "message", nil).Apply(&snapshot)
+	NewCustomOp(rene, unix, dueDateTag, &dueDatePayload{Due: "2024-01-15"}).Apply(&snapshot)
+	assert.Equal(t, "2024-01-15", snapshot.Fields["due"])
+}
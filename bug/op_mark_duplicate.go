@@ -0,0 +1,122 @@
+package bug
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &MarkDuplicateOperation{}
+
+// MarkDuplicateOperation will mark a bug as a duplicate of another, canonical,
+// bug and close it with the duplicate resolution. Changing the status of the bug
+// again remove the relation.
+type MarkDuplicateOperation struct {
+	OpBase
+	Target entity.Id `json:"target"`
+}
+
+// Sign-post method for gqlgen
+func (op *MarkDuplicateOperation) IsOperation() {}
+
+func (op *MarkDuplicateOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *MarkDuplicateOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *MarkDuplicateOperation) Apply(snapshot *Snapshot) {
+	snapshot.DuplicateOf = op.Target
+	snapshot.Status = ClosedStatus
+	snapshot.Resolution = DuplicateResolution
+	snapshot.addActor(op.Author)
+
+	item := &MarkDuplicateTimelineItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+		Target:   op.Target,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *MarkDuplicateOperation) Validate() error {
+	if err := opBaseValidate(op, MarkDuplicateOp); err != nil {
+		return err
+	}
+
+	if err := op.Target.Validate(); err != nil {
+		return errors.Wrap(err, "target")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *MarkDuplicateOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Target entity.Id `json:"target"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Target = aux.Target
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *MarkDuplicateOperation) IsAuthored() {}
+
+func NewMarkDuplicateOp(author identity.Interface, unixTime int64, target entity.Id) *MarkDuplicateOperation {
+	return &MarkDuplicateOperation{
+		OpBase: newOpBase(MarkDuplicateOp, author, unixTime),
+		Target: target,
+	}
+}
+
+type MarkDuplicateTimelineItem struct {
+	id       entity.Id
+	Author   identity.Interface
+	UnixTime timestamp.Timestamp
+	Target   entity.Id
+}
+
+func (m MarkDuplicateTimelineItem) Id() entity.Id {
+	return m.id
+}
+
+// Sign post method for gqlgen
+func (m *MarkDuplicateTimelineItem) IsAuthored() {}
+
+// Convenience function to apply the operation
+func MarkDuplicate(b Interface, author identity.Interface, unixTime int64, target entity.Id) (*MarkDuplicateOperation, error) {
+	op := NewMarkDuplicateOp(author, unixTime, target)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
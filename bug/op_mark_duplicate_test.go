@@ -0,0 +1,48 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func TestMarkDuplicateSerialize(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	before := NewMarkDuplicateOp(rene, unix, entity.Id("0123456789012345678901234567890123456789"))
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after MarkDuplicateOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the IDs
+	before.Id()
+	rene.Id()
+
+	assert.Equal(t, before, &after)
+}
+
+func TestMarkDuplicateApply(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	target := entity.Id("0123456789012345678901234567890123456789")
+
+	snapshot := Snapshot{}
+
+	NewMarkDuplicateOp(rene, unix, target).Apply(&snapshot)
+	assert.Equal(t, target, snapshot.DuplicateOf)
+	assert.Equal(t, ClosedStatus, snapshot.Status)
+	assert.Equal(t, DuplicateResolution, snapshot.Resolution)
+
+	NewSetStatusOp(rene, unix, OpenStatus).Apply(&snapshot)
+	assert.Equal(t, entity.Id(""), snapshot.DuplicateOf)
+	assert.Equal(t, OpenStatus, snapshot.Status)
+}
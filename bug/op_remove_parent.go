@@ -0,0 +1,125 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &RemoveParentOperation{}
+
+// RemoveParentOperation will make a sub-task a bug on its own again
+type RemoveParentOperation struct {
+	OpBase
+	Was entity.Id `json:"was"`
+}
+
+// Sign-post method for gqlgen
+func (op *RemoveParentOperation) IsOperation() {}
+
+func (op *RemoveParentOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *RemoveParentOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *RemoveParentOperation) Apply(snapshot *Snapshot) {
+	snapshot.Parent = ""
+	snapshot.addActor(op.Author)
+
+	item := &RemoveParentTimelineItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+		Was:      op.Was,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *RemoveParentOperation) Validate() error {
+	if err := opBaseValidate(op, RemoveParentOp); err != nil {
+		return err
+	}
+
+	if err := op.Was.Validate(); err != nil {
+		return errors.Wrap(err, "previous parent")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *RemoveParentOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Was entity.Id `json:"was"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Was = aux.Was
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *RemoveParentOperation) IsAuthored() {}
+
+func NewRemoveParentOp(author identity.Interface, unixTime int64, was entity.Id) *RemoveParentOperation {
+	return &RemoveParentOperation{
+		OpBase: newOpBase(RemoveParentOp, author, unixTime),
+		Was:    was,
+	}
+}
+
+type RemoveParentTimelineItem struct {
+	id       entity.Id
+	Author   identity.Interface
+	UnixTime timestamp.Timestamp
+	Was      entity.Id
+}
+
+func (r RemoveParentTimelineItem) Id() entity.Id {
+	return r.id
+}
+
+// Sign post method for gqlgen
+func (r *RemoveParentTimelineItem) IsAuthored() {}
+
+// Convenience function to apply the operation
+func RemoveParent(b Interface, author identity.Interface, unixTime int64) (*RemoveParentOperation, error) {
+	snap := b.Compile()
+
+	if snap.Parent == "" {
+		return nil, fmt.Errorf("the bug has no parent")
+	}
+
+	op := NewRemoveParentOp(author, unixTime, snap.Parent)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
@@ -0,0 +1,179 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/text"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &RequestReviewOperation{}
+
+// RequestReviewOperation will ask identities for their input on a bug, with
+// an optional message. The requests are answered by the next operation of
+// each reviewer on the bug.
+type RequestReviewOperation struct {
+	OpBase
+	Reviewers []identity.Interface `json:"reviewers"`
+	Message   string               `json:"message,omitempty"`
+}
+
+// Sign-post method for gqlgen
+func (op *RequestReviewOperation) IsOperation() {}
+
+func (op *RequestReviewOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *RequestReviewOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *RequestReviewOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author)
+
+	for _, reviewer := range op.Reviewers {
+		request := ReviewRequest{
+			id:        op.Id(),
+			Reviewer:  reviewer,
+			Requester: op.Author,
+			Message:   op.Message,
+			UnixTime:  timestamp.Timestamp(op.UnixTime),
+		}
+
+		// a new request replace the previous one of the same reviewer
+		replaced := false
+		for i, r := range snapshot.ReviewRequests {
+			if r.Reviewer.Id() == reviewer.Id() {
+				snapshot.ReviewRequests[i] = request
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			snapshot.ReviewRequests = append(snapshot.ReviewRequests, request)
+		}
+	}
+
+	item := &RequestReviewTimelineItem{
+		id:        op.Id(),
+		Author:    op.Author,
+		UnixTime:  timestamp.Timestamp(op.UnixTime),
+		Reviewers: op.Reviewers,
+		Message:   op.Message,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *RequestReviewOperation) Validate() error {
+	if err := opBaseValidate(op, RequestReviewOp); err != nil {
+		return err
+	}
+
+	if len(op.Reviewers) == 0 {
+		return fmt.Errorf("no reviewer")
+	}
+
+	for _, i := range op.Reviewers {
+		if err := i.Validate(); err != nil {
+			return errors.Wrap(err, "reviewer")
+		}
+	}
+
+	if !text.Safe(op.Message) {
+		return fmt.Errorf("message is not fully printable")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *RequestReviewOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Reviewers []json.RawMessage `json:"reviewers"`
+		Message   string            `json:"message"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	reviewers, err := unmarshalIdentities(aux.Reviewers)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Reviewers = reviewers
+	op.Message = aux.Message
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *RequestReviewOperation) IsAuthored() {}
+
+func NewRequestReviewOp(author identity.Interface, unixTime int64, reviewers []identity.Interface, message string) *RequestReviewOperation {
+	return &RequestReviewOperation{
+		OpBase:    newOpBase(RequestReviewOp, author, unixTime),
+		Reviewers: reviewers,
+		Message:   message,
+	}
+}
+
+type RequestReviewTimelineItem struct {
+	id        entity.Id
+	Author    identity.Interface
+	UnixTime  timestamp.Timestamp
+	Reviewers []identity.Interface
+	Message   string
+}
+
+func (r RequestReviewTimelineItem) Id() entity.Id {
+	return r.id
+}
+
+// Sign post method for gqlgen
+func (r *RequestReviewTimelineItem) IsAuthored() {}
+
+// RequestReview is a convenience function to apply the operation. The
+// reviewers with a pending request already are ignored.
+func RequestReview(b Interface, author identity.Interface, unixTime int64, reviewers []identity.Interface, message string) (*RequestReviewOperation, error) {
+	snap := b.Compile()
+
+	var requested []identity.Interface
+	for _, i := range reviewers {
+		if !snap.IsReviewPending(i.Id()) && !identityExist(requested, i) {
+			requested = append(requested, i)
+		}
+	}
+
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("the review has already been requested")
+	}
+
+	op := NewRequestReviewOp(author, unixTime, requested, message)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
@@ -0,0 +1,63 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestRequestReviewSerialize(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	isaac := identity.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	assert.NoError(t, rene.Commit(repo))
+	assert.NoError(t, isaac.Commit(repo))
+
+	unix := time.Now().Unix()
+	before := NewRequestReviewOp(rene, unix, []identity.Interface{isaac}, "what do you think?")
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after RequestReviewOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// the identities are stubs until resolved
+	assert.Equal(t, before.Id(), after.Id())
+	assert.Len(t, after.Reviewers, 1)
+	assert.Equal(t, isaac.Id(), after.Reviewers[0].Id())
+	assert.Equal(t, "what do you think?", after.Message)
+}
+
+func TestRequestReviewApply(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	var isaac = identity.NewBare("Isaac Newton", "isaac@newton.uk")
+	var leonhard = identity.NewBare("Leonhard Euler", "leonhard@euler.ch")
+	unix := time.Now().Unix()
+
+	snapshot := Snapshot{}
+
+	NewCreateOp(rene, unix, "title", "message", nil).Apply(&snapshot)
+	NewRequestReviewOp(rene, unix, []identity.Interface{isaac, leonhard}, "").Apply(&snapshot)
+	assert.Len(t, snapshot.ReviewRequests, 2)
+	assert.True(t, snapshot.IsReviewPending(isaac.Id()))
+	assert.True(t, snapshot.IsReviewPending(leonhard.Id()))
+
+	// any operation of the reviewer answer the request
+	NewAddCommentOp(isaac, unix, "looks good", nil).Apply(&snapshot)
+	assert.False(t, snapshot.IsReviewPending(isaac.Id()))
+	assert.Equal(t, []identity.Interface{leonhard}, snapshot.PendingReviewers())
+
+	// a new request replace the answered one
+	NewRequestReviewOp(leonhard, unix, []identity.Interface{isaac}, "again").Apply(&snapshot)
+	assert.Len(t, snapshot.ReviewRequests, 2)
+	assert.True(t, snapshot.IsReviewPending(isaac.Id()))
+	assert.False(t, snapshot.IsReviewPending(leonhard.Id()))
+	assert.Equal(t, "again", snapshot.ReviewRequests[0].Message)
+}
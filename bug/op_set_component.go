@@ -0,0 +1,148 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/text"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &SetComponentOperation{}
+
+// SetComponentOperation will change the component (or area) of a bug.
+// An empty component unset it.
+type SetComponentOperation struct {
+	OpBase
+	Component string `json:"component"`
+	Was       string `json:"was"`
+}
+
+// Sign-post method for gqlgen
+func (op *SetComponentOperation) IsOperation() {}
+
+func (op *SetComponentOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *SetComponentOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *SetComponentOperation) Apply(snapshot *Snapshot) {
+	snapshot.Component = op.Component
+	snapshot.addActor(op.Author)
+
+	item := &SetComponentTimelineItem{
+		id:        op.Id(),
+		Author:    op.Author,
+		UnixTime:  timestamp.Timestamp(op.UnixTime),
+		Component: op.Component,
+		Was:       op.Was,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *SetComponentOperation) Validate() error {
+	if err := opBaseValidate(op, SetComponentOp); err != nil {
+		return err
+	}
+
+	if err := validateComponent(op.Component); err != nil {
+		return err
+	}
+
+	if err := validateComponent(op.Was); err != nil {
+		return fmt.Errorf("previous %s", err.Error())
+	}
+
+	if op.Component == op.Was {
+		return fmt.Errorf("component unchanged")
+	}
+
+	return nil
+}
+
+func validateComponent(component string) error {
+	if strings.ContainsAny(component, " \t\n") {
+		return fmt.Errorf("component should be a single word")
+	}
+
+	if !text.Safe(component) {
+		return fmt.Errorf("component should be fully printable")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *SetComponentOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Component string `json:"component"`
+		Was       string `json:"was"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Component = aux.Component
+	op.Was = aux.Was
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *SetComponentOperation) IsAuthored() {}
+
+func NewSetComponentOp(author identity.Interface, unixTime int64, component string, was string) *SetComponentOperation {
+	return &SetComponentOperation{
+		OpBase:    newOpBase(SetComponentOp, author, unixTime),
+		Component: component,
+		Was:       was,
+	}
+}
+
+type SetComponentTimelineItem struct {
+	id        entity.Id
+	Author    identity.Interface
+	UnixTime  timestamp.Timestamp
+	Component string
+	Was       string
+}
+
+func (s SetComponentTimelineItem) Id() entity.Id {
+	return s.id
+}
+
+// Sign post method for gqlgen
+func (s *SetComponentTimelineItem) IsAuthored() {}
+
+// Convenience function to apply the operation
+func SetComponent(b Interface, author identity.Interface, unixTime int64, component string) (*SetComponentOperation, error) {
+	snap := b.Compile()
+
+	op := NewSetComponentOp(author, unixTime, component, snap.Component)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
@@ -0,0 +1,128 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &SetParentOperation{}
+
+// SetParentOperation will make a bug a sub-task of another, parent, bug,
+// replacing its previous parent if any.
+type SetParentOperation struct {
+	OpBase
+	Parent entity.Id `json:"parent"`
+}
+
+// Sign-post method for gqlgen
+func (op *SetParentOperation) IsOperation() {}
+
+func (op *SetParentOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *SetParentOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *SetParentOperation) Apply(snapshot *Snapshot) {
+	snapshot.Parent = op.Parent
+	snapshot.addActor(op.Author)
+
+	item := &SetParentTimelineItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+		Parent:   op.Parent,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *SetParentOperation) Validate() error {
+	if err := opBaseValidate(op, SetParentOp); err != nil {
+		return err
+	}
+
+	if err := op.Parent.Validate(); err != nil {
+		return errors.Wrap(err, "parent")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *SetParentOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Parent entity.Id `json:"parent"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Parent = aux.Parent
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *SetParentOperation) IsAuthored() {}
+
+func NewSetParentOp(author identity.Interface, unixTime int64, parent entity.Id) *SetParentOperation {
+	return &SetParentOperation{
+		OpBase: newOpBase(SetParentOp, author, unixTime),
+		Parent: parent,
+	}
+}
+
+type SetParentTimelineItem struct {
+	id       entity.Id
+	Author   identity.Interface
+	UnixTime timestamp.Timestamp
+	Parent   entity.Id
+}
+
+func (s SetParentTimelineItem) Id() entity.Id {
+	return s.id
+}
+
+// Sign post method for gqlgen
+func (s *SetParentTimelineItem) IsAuthored() {}
+
+// Convenience function to apply the operation
+func SetParent(b Interface, author identity.Interface, unixTime int64, parent entity.Id) (*SetParentOperation, error) {
+	if parent == b.Id() {
+		return nil, fmt.Errorf("a bug can't be its own parent")
+	}
+
+	if b.Compile().Parent == parent {
+		return nil, fmt.Errorf("parent unchanged")
+	}
+
+	op := NewSetParentOp(author, unixTime, parent)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
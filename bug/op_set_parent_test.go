@@ -0,0 +1,65 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func TestSetParentSerialize(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	before := NewSetParentOp(rene, unix, entity.Id("0123456789012345678901234567890123456789"))
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after SetParentOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the IDs
+	before.Id()
+	rene.Id()
+
+	assert.Equal(t, before, &after)
+}
+
+func TestRemoveParentSerialize(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	before := NewRemoveParentOp(rene, unix, entity.Id("0123456789012345678901234567890123456789"))
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after RemoveParentOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the IDs
+	before.Id()
+	rene.Id()
+
+	assert.Equal(t, before, &after)
+}
+
+func TestParentApply(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	parent := entity.Id("0123456789012345678901234567890123456789")
+
+	snapshot := Snapshot{}
+
+	NewSetParentOp(rene, unix, parent).Apply(&snapshot)
+	assert.Equal(t, parent, snapshot.Parent)
+
+	NewRemoveParentOp(rene, unix, parent).Apply(&snapshot)
+	assert.Equal(t, entity.Id(""), snapshot.Parent)
+	assert.Len(t, snapshot.Timeline, 2)
+}
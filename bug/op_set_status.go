@@ -2,6 +2,7 @@ package bug
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/pkg/errors"
 
@@ -12,10 +13,12 @@ import (
 
 var _ Operation = &SetStatusOperation{}
 
-// SetStatusOperation will change the status of a bug
+// SetStatusOperation will change the status of a bug. A bug being closed can
+// be given a resolution.
 type SetStatusOperation struct {
 	OpBase
-	Status Status `json:"status"`
+	Status     Status     `json:"status"`
+	Resolution Resolution `json:"resolution,omitempty"`
 }
 
 // Sign-post method for gqlgen
@@ -31,13 +34,17 @@ func (op *SetStatusOperation) Id() entity.Id {
 
 func (op *SetStatusOperation) Apply(snapshot *Snapshot) {
 	snapshot.Status = op.Status
+	snapshot.Resolution = op.Resolution
+	// a bug reopened or closed again is not a duplicate anymore
+	snapshot.DuplicateOf = ""
 	snapshot.addActor(op.Author)
 
 	item := &SetStatusTimelineItem{
-		id:       op.Id(),
-		Author:   op.Author,
-		UnixTime: timestamp.Timestamp(op.UnixTime),
-		Status:   op.Status,
+		id:         op.Id(),
+		Author:     op.Author,
+		UnixTime:   timestamp.Timestamp(op.UnixTime),
+		Status:     op.Status,
+		Resolution: op.Resolution,
 	}
 
 	snapshot.Timeline = append(snapshot.Timeline, item)
@@ -52,6 +59,14 @@ func (op *SetStatusOperation) Validate() error {
 		return errors.Wrap(err, "status")
 	}
 
+	if err := op.Resolution.Validate(); err != nil {
+		return errors.Wrap(err, "resolution")
+	}
+
+	if op.Resolution != NoResolution && op.Status != ClosedStatus {
+		return fmt.Errorf("only a closed bug can have a resolution")
+	}
+
 	return nil
 }
 
@@ -68,7 +83,8 @@ func (op *SetStatusOperation) UnmarshalJSON(data []byte) error {
 	}
 
 	aux := struct {
-		Status Status `json:"status"`
+		Status     Status     `json:"status"`
+		Resolution Resolution `json:"resolution"`
 	}{}
 
 	err = json.Unmarshal(data, &aux)
@@ -78,6 +94,7 @@ func (op *SetStatusOperation) UnmarshalJSON(data []byte) error {
 
 	op.OpBase = base
 	op.Status = aux.Status
+	op.Resolution = aux.Resolution
 
 	return nil
 }
@@ -93,10 +110,11 @@ func NewSetStatusOp(author identity.Interface, unixTime int64, status Status) *S
 }
 
 type SetStatusTimelineItem struct {
-	id       entity.Id
-	Author   identity.Interface
-	UnixTime timestamp.Timestamp
-	Status   Status
+	id         entity.Id
+	Author     identity.Interface
+	UnixTime   timestamp.Timestamp
+	Status     Status
+	Resolution Resolution
 }
 
 func (s SetStatusTimelineItem) Id() entity.Id {
@@ -118,7 +136,13 @@ func Open(b Interface, author identity.Interface, unixTime int64) (*SetStatusOpe
 
 // Convenience function to apply the operation
 func Close(b Interface, author identity.Interface, unixTime int64) (*SetStatusOperation, error) {
+	return CloseWithResolution(b, author, unixTime, NoResolution)
+}
+
+// Convenience function to apply the operation
+func CloseWithResolution(b Interface, author identity.Interface, unixTime int64, resolution Resolution) (*SetStatusOperation, error) {
 	op := NewSetStatusOp(author, unixTime, ClosedStatus)
+	op.Resolution = resolution
 	if err := op.Validate(); err != nil {
 		return nil, err
 	}
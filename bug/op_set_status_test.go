@@ -27,3 +27,35 @@ func TestSetStatusSerialize(t *testing.T) {
 
 	assert.Equal(t, before, &after)
 }
+
+func TestSetStatusResolution(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	closeOp := NewSetStatusOp(rene, unix, ClosedStatus)
+	closeOp.Resolution = WontFixResolution
+	assert.NoError(t, closeOp.Validate())
+
+	data, err := json.Marshal(closeOp)
+	assert.NoError(t, err)
+
+	var after SetStatusOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+	assert.Equal(t, WontFixResolution, after.Resolution)
+
+	// no resolution is not serialized, to keep the id of the existing operations
+	data, err = json.Marshal(NewSetStatusOp(rene, unix, ClosedStatus))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "resolution")
+
+	openOp := NewSetStatusOp(rene, unix, OpenStatus)
+	openOp.Resolution = FixedResolution
+	assert.Error(t, openOp.Validate())
+
+	snapshot := Snapshot{}
+	closeOp.Apply(&snapshot)
+	assert.Equal(t, WontFixResolution, snapshot.Resolution)
+	NewSetStatusOp(rene, unix, OpenStatus).Apply(&snapshot)
+	assert.Equal(t, NoResolution, snapshot.Resolution)
+}
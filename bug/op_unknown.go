@@ -0,0 +1,141 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &UnknownOperation{}
+
+// ErrNewerFormat is returned when editing a bug holding operations written by
+// a newer version of git-bug
+var ErrNewerFormat = errors.New("the bug has been edited with a newer version of git-bug, update git-bug to edit it")
+
+// the author of the operations whose format is unknown, with its id computed
+// right away as it is shared
+var unknownAuthor = func() identity.Interface {
+	i := identity.NewBare("unknown", "")
+	_ = i.Id()
+	return i
+}()
+
+// UnknownOperation is an operation this version of git-bug can't read, either
+// of an unknown type or in a newer format. It is kept as it is, shown as a
+// placeholder in the timeline, and the bug holding it can't be edited.
+type UnknownOperation struct {
+	OpBase
+	// the version of the format of the pack holding the operation, if newer
+	// than the supported one
+	FormatVersion uint `json:"-"`
+
+	// Not serialized. The operation as stored.
+	raw json.RawMessage
+}
+
+// Sign-post method for gqlgen
+func (op *UnknownOperation) IsOperation() {}
+
+func (op *UnknownOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *UnknownOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *UnknownOperation) Apply(snapshot *Snapshot) {
+	// the creation of the bug may be unknown as well
+	if snapshot.Author == nil {
+		snapshot.Author = op.Author
+		snapshot.CreatedAt = op.Time()
+	}
+
+	snapshot.addActor(op.Author)
+
+	item := &UnknownTimelineItem{
+		id:            op.Id(),
+		Author:        op.Author,
+		UnixTime:      timestamp.Timestamp(op.UnixTime),
+		OperationType: op.OperationType,
+		FormatVersion: op.FormatVersion,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+// Validate only check what can be of an operation of an unknown format
+func (op *UnknownOperation) Validate() error {
+	if op.Author == nil {
+		return fmt.Errorf("author not set")
+	}
+
+	if !json.Valid(op.raw) {
+		return fmt.Errorf("invalid data of an unknown operation")
+	}
+
+	return nil
+}
+
+func (op *UnknownOperation) MarshalJSON() ([]byte, error) {
+	return op.raw, nil
+}
+
+// UnmarshalJSON read what it can of the operation: the common fields of the
+// operations if they are there, a placeholder otherwise.
+func (op *UnknownOperation) UnmarshalJSON(data []byte) error {
+	op.raw = append(json.RawMessage(nil), data...)
+
+	base := OpBase{}
+	if err := json.Unmarshal(data, &base); err != nil || base.Author == nil {
+		base = OpBase{Author: unknownAuthor}
+	}
+
+	op.OpBase = base
+	op.id = deriveId(data)
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *UnknownOperation) IsAuthored() {}
+
+// UnknownTimelineItem is the placeholder of an operation this version of
+// git-bug can't read
+type UnknownTimelineItem struct {
+	id            entity.Id
+	Author        identity.Interface
+	UnixTime      timestamp.Timestamp
+	OperationType OperationType
+	FormatVersion uint
+}
+
+func (u UnknownTimelineItem) Id() entity.Id {
+	return u.id
+}
+
+// Sign post method for gqlgen
+func (u *UnknownTimelineItem) IsAuthored() {}
+
+func isUnknownOp(op Operation) bool {
+	_, ok := op.(*UnknownOperation)
+	return ok
+}
+
+// HasUnknownOperations tell if the bug hold operations this version of git-bug
+// can't read, in which case it can't be edited
+func (bug *Bug) HasUnknownOperations() bool {
+	for _, pack := range bug.packs {
+		for _, op := range pack.Operations {
+			if isUnknownOp(op) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestUnknownOperationType(t *testing.T) {
+	unknown := `{"type":42,"author":{"name":"René Descartes","email":"rene@descartes.fr"},"timestamp":1,"what":["is","this"]}`
+	data := []byte(`{"version":1,"ops":[` + unknown + `]}`)
+
+	// the schema of the unknown types is not known
+	require.NoError(t, validateOperationPackData(data))
+
+	var opp OperationPack
+	require.NoError(t, json.Unmarshal(data, &opp))
+	require.Len(t, opp.Operations, 1)
+
+	op, ok := opp.Operations[0].(*UnknownOperation)
+	require.True(t, ok)
+	assert.Equal(t, OperationType(42), op.OperationType)
+	assert.Equal(t, "René Descartes", op.Author.Name())
+	assert.Equal(t, uint(0), op.FormatVersion)
+	assert.NoError(t, op.Validate())
+
+	// the operation is written back as is
+	again, err := json.Marshal(op)
+	require.NoError(t, err)
+	assert.Equal(t, unknown, string(again))
+
+	snapshot := Snapshot{}
+	op.Apply(&snapshot)
+	assert.Len(t, snapshot.Actors, 1)
+	require.Len(t, snapshot.Timeline, 1)
+	assert.IsType(t, &UnknownTimelineItem{}, snapshot.Timeline[0])
+}
+
+func TestUnknownFormatVersion(t *testing.T) {
+	data := []byte(`{"version":2,"ops":[{"type":1,"author":{"name":"René Descartes"},"timestamp":1,"title":{"changed":true}}]}`)
+	require.NoError(t, validateOperationPackData(data))
+
+	var opp OperationPack
+	require.NoError(t, json.Unmarshal(data, &opp))
+	require.Len(t, opp.Operations, 1)
+
+	op := opp.Operations[0].(*UnknownOperation)
+	assert.Equal(t, uint(2), op.FormatVersion)
+	assert.Equal(t, "René Descartes", op.Author.Name())
+
+	// not even the operations can be told apart
+	data = []byte(`{"version":3,"changes":{}}`)
+
+	opp = OperationPack{}
+	require.NoError(t, json.Unmarshal(data, &opp))
+	require.Len(t, opp.Operations, 1)
+
+	op = opp.Operations[0].(*UnknownOperation)
+	assert.Equal(t, uint(3), op.FormatVersion)
+	assert.Equal(t, "unknown", op.Author.Name())
+
+	snapshot := Snapshot{}
+	op.Apply(&snapshot)
+	assert.Equal(t, op.Author, snapshot.Author)
+
+	opp = OperationPack{}
+	assert.Error(t, json.Unmarshal([]byte(`{"version":0,"ops":[]}`), &opp))
+}
+
+func TestUnknownOperationBlockEdits(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repo))
+
+	unix := time.Now().Unix()
+
+	b, _, err := Create(rene, unix, "title", "message")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit(repo))
+	assert.False(t, b.HasUnknownOperations())
+
+	var op UnknownOperation
+	require.NoError(t, json.Unmarshal([]byte(`{"type":42,"author":{"name":"René Descartes"},"timestamp":1}`), &op))
+	b.packs[0].Operations = append(b.packs[0].Operations, &op)
+
+	// the bug is still valid, but can't be edited
+	assert.NoError(t, b.Validate())
+	assert.True(t, b.HasUnknownOperations())
+
+	_, err = AddComment(b, rene, unix, "message")
+	require.NoError(t, err)
+	assert.Equal(t, ErrNewerFormat, b.Commit(repo))
+}
@@ -0,0 +1,133 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+var _ Operation = &VoteOperation{}
+
+// VoteOperation will add the vote of its author to a bug, to tell that they
+// want it fixed too, or retract it. An identity vote at most once per bug.
+type VoteOperation struct {
+	OpBase
+	Retract bool `json:"retract,omitempty"`
+}
+
+// Sign-post method for gqlgen
+func (op *VoteOperation) IsOperation() {}
+
+func (op *VoteOperation) base() *OpBase {
+	return &op.OpBase
+}
+
+func (op *VoteOperation) Id() entity.Id {
+	return idOperation(op)
+}
+
+func (op *VoteOperation) Apply(snapshot *Snapshot) {
+	snapshot.addActor(op.Author)
+
+	voted := snapshot.HasVoted(op.Author.Id())
+
+	switch {
+	case !op.Retract && !voted:
+		snapshot.Voters = append(snapshot.Voters, op.Author)
+	case op.Retract && voted:
+		for i, voter := range snapshot.Voters {
+			if voter.Id() == op.Author.Id() {
+				snapshot.Voters = append(snapshot.Voters[:i], snapshot.Voters[i+1:]...)
+				break
+			}
+		}
+	}
+
+	item := &VoteTimelineItem{
+		id:       op.Id(),
+		Author:   op.Author,
+		UnixTime: timestamp.Timestamp(op.UnixTime),
+		Retract:  op.Retract,
+	}
+
+	snapshot.Timeline = append(snapshot.Timeline, item)
+}
+
+func (op *VoteOperation) Validate() error {
+	return opBaseValidate(op, VoteOp)
+}
+
+// UnmarshalJSON is a two step JSON unmarshaling
+// This workaround is necessary to avoid the inner OpBase.MarshalJSON
+// overriding the outer op's MarshalJSON
+func (op *VoteOperation) UnmarshalJSON(data []byte) error {
+	// Unmarshal OpBase and the op separately
+
+	base := OpBase{}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return err
+	}
+
+	aux := struct {
+		Retract bool `json:"retract"`
+	}{}
+
+	err = json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	op.OpBase = base
+	op.Retract = aux.Retract
+
+	return nil
+}
+
+// Sign post method for gqlgen
+func (op *VoteOperation) IsAuthored() {}
+
+func NewVoteOp(author identity.Interface, unixTime int64, retract bool) *VoteOperation {
+	return &VoteOperation{
+		OpBase:  newOpBase(VoteOp, author, unixTime),
+		Retract: retract,
+	}
+}
+
+type VoteTimelineItem struct {
+	id       entity.Id
+	Author   identity.Interface
+	UnixTime timestamp.Timestamp
+	Retract  bool
+}
+
+func (v VoteTimelineItem) Id() entity.Id {
+	return v.id
+}
+
+// Sign post method for gqlgen
+func (v *VoteTimelineItem) IsAuthored() {}
+
+// Convenience function to apply the operation
+func Vote(b Interface, author identity.Interface, unixTime int64, retract bool) (*VoteOperation, error) {
+	snap := b.Compile()
+	voted := snap.HasVoted(author.Id())
+
+	if voted && !retract {
+		return nil, fmt.Errorf("already voted for this bug")
+	}
+	if !voted && retract {
+		return nil, fmt.Errorf("no vote to retract")
+	}
+
+	op := NewVoteOp(author, unixTime, retract)
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.Append(op)
+	return op, nil
+}
@@ -0,0 +1,50 @@
+package bug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func TestVoteSerialize(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+	before := NewVoteOp(rene, unix, true)
+
+	data, err := json.Marshal(before)
+	assert.NoError(t, err)
+
+	var after VoteOperation
+	err = json.Unmarshal(data, &after)
+	assert.NoError(t, err)
+
+	// enforce creating the IDs
+	before.Id()
+	rene.Id()
+
+	assert.Equal(t, before, &after)
+}
+
+func TestVoteApply(t *testing.T) {
+	var rene = identity.NewBare("René Descartes", "rene@descartes.fr")
+	var isaac = identity.NewBare("Isaac Newton", "isaac@newton.uk")
+	unix := time.Now().Unix()
+
+	snapshot := Snapshot{}
+
+	NewVoteOp(rene, unix, false).Apply(&snapshot)
+	NewVoteOp(isaac, unix, false).Apply(&snapshot)
+	// a second vote of the same identity doesn't count
+	NewVoteOp(rene, unix, false).Apply(&snapshot)
+	assert.Len(t, snapshot.Voters, 2)
+	assert.True(t, snapshot.HasVoted(rene.Id()))
+
+	NewVoteOp(rene, unix, true).Apply(&snapshot)
+	assert.Len(t, snapshot.Voters, 1)
+	assert.False(t, snapshot.HasVoted(rene.Id()))
+	assert.True(t, snapshot.HasVoted(isaac.Id()))
+}
@@ -26,8 +26,30 @@ const (
 	EditCommentOp
 	NoOpOp
 	SetMetadataOp
+	SetComponentOp
+	AssigneeChangeOp
+	MarkDuplicateOp
+	SetParentOp
+	RemoveParentOp
+	AddChecklistItemOp
+	CheckItemOp
+	VoteOp
+	RequestReviewOp
+
+	// not a type, keep it last
+	endOfBuiltinOps
 )
 
+// CustomOp is the type of the operations registered by the programs embedding
+// git-bug, told apart by their tag. It is kept away from the built-in types
+// to leave room for them.
+const CustomOp OperationType = 1000
+
+// isKnown tell if the type is one this version of git-bug can read
+func (t OperationType) isKnown() bool {
+	return t > 0 && t < endOfBuiltinOps || t == CustomOp
+}
+
 // Operation define the interface to fulfill for an edit operation of a Bug
 type Operation interface {
 	// base return the OpBase of the Operation, for package internal use
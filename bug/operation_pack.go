@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/schema"
 	"github.com/MichaelMure/git-bug/util/git"
+	"github.com/MichaelMure/git-bug/util/lamport"
 	"github.com/pkg/errors"
 )
 
@@ -22,6 +24,8 @@ type OperationPack struct {
 
 	// Private field so not serialized
 	commitHash git.Hash
+	// the edit lamport time of the commit, once committed
+	editTime lamport.Time
 }
 
 func (opp *OperationPack) MarshalJSON() ([]byte, error) {
@@ -44,11 +48,21 @@ func (opp *OperationPack) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	if aux.Version != formatVersion {
+	if aux.Version == 0 {
 		return fmt.Errorf("unknown format version %v", aux.Version)
 	}
 
+	// a newer format can't be read, the operations are kept as placeholders
+	if aux.Version > formatVersion {
+		return opp.unmarshalNewerFormat(data, aux.Version, aux.Operations)
+	}
+
 	for _, raw := range aux.Operations {
+		// would be decoded as a nil operation
+		if string(raw) == "null" {
+			return fmt.Errorf("null operation")
+		}
+
 		var t struct {
 			OperationType OperationType `json:"type"`
 		}
@@ -69,12 +83,63 @@ func (opp *OperationPack) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// validateOperationPackData check the operations of a serialized
+// OperationPack against the operation JSON schema
+func validateOperationPackData(data []byte) error {
+	aux := struct {
+		Version    uint              `json:"version"`
+		Operations []json.RawMessage `json:"ops"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// the schema of a newer format is not known
+	if aux.Version > formatVersion {
+		return nil
+	}
+
+	for _, raw := range aux.Operations {
+		var t struct {
+			OperationType OperationType `json:"type"`
+		}
+
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return err
+		}
+
+		// nor is the one of the operations of an unknown type
+		if !t.OperationType.isKnown() {
+			continue
+		}
+
+		if err := schema.Validate(schema.Operation, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (opp *OperationPack) unmarshalOp(raw []byte, _type OperationType) (Operation, error) {
 	switch _type {
+	case AddChecklistItemOp:
+		op := &AddChecklistItemOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
 	case AddCommentOp:
 		op := &AddCommentOperation{}
 		err := json.Unmarshal(raw, &op)
 		return op, err
+	case AssigneeChangeOp:
+		op := &AssigneeChangeOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
+	case CheckItemOp:
+		op := &CheckItemOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
 	case CreateOp:
 		op := &CreateOperation{}
 		err := json.Unmarshal(raw, &op)
@@ -87,14 +152,30 @@ func (opp *OperationPack) unmarshalOp(raw []byte, _type OperationType) (Operatio
 		op := &LabelChangeOperation{}
 		err := json.Unmarshal(raw, &op)
 		return op, err
+	case MarkDuplicateOp:
+		op := &MarkDuplicateOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
 	case NoOpOp:
 		op := &NoOpOperation{}
 		err := json.Unmarshal(raw, &op)
 		return op, err
+	case RemoveParentOp:
+		op := &RemoveParentOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
 	case SetMetadataOp:
 		op := &SetMetadataOperation{}
 		err := json.Unmarshal(raw, &op)
 		return op, err
+	case SetComponentOp:
+		op := &SetComponentOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
+	case SetParentOp:
+		op := &SetParentOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
 	case SetStatusOp:
 		op := &SetStatusOperation{}
 		err := json.Unmarshal(raw, &op)
@@ -103,9 +184,47 @@ func (opp *OperationPack) unmarshalOp(raw []byte, _type OperationType) (Operatio
 		op := &SetTitleOperation{}
 		err := json.Unmarshal(raw, &op)
 		return op, err
+	case VoteOp:
+		op := &VoteOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
+	case RequestReviewOp:
+		op := &RequestReviewOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
+	case CustomOp:
+		op := &CustomOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
 	default:
-		return nil, fmt.Errorf("unknown operation type %v", _type)
+		// most likely added by a newer version of git-bug
+		op := &UnknownOperation{}
+		err := json.Unmarshal(raw, &op)
+		return op, err
+	}
+}
+
+// unmarshalNewerFormat keep the operations of a pack in a newer format as
+// placeholders, or the whole pack if even its operations can't be told apart
+func (opp *OperationPack) unmarshalNewerFormat(data []byte, version uint, raws []json.RawMessage) error {
+	if len(raws) == 0 {
+		raws = []json.RawMessage{data}
+	}
+
+	for _, raw := range raws {
+		if string(raw) == "null" {
+			return fmt.Errorf("null operation")
+		}
+
+		op := &UnknownOperation{}
+		if err := json.Unmarshal(raw, &op); err != nil {
+			return err
+		}
+		op.FormatVersion = version
+		opp.Operations = append(opp.Operations, op)
 	}
+
+	return nil
 }
 
 // Append a new operation to the pack
@@ -171,6 +290,7 @@ func (opp *OperationPack) Clone() OperationPack {
 	clone := OperationPack{
 		Operations: make([]Operation, len(opp.Operations)),
 		commitHash: opp.commitHash,
+		editTime:   opp.editTime,
 	}
 
 	for i, op := range opp.Operations {
@@ -61,3 +61,48 @@ func ensureIDs(t *testing.T, opp *OperationPack) {
 		require.NoError(t, id.Validate())
 	}
 }
+
+func TestOperationPackSchema(t *testing.T) {
+	opp := &OperationPack{}
+
+	rene := identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	createOp := NewCreateOp(rene, unix, "title", "message", nil)
+	createOp.Labels = []Label{"type:bug"}
+	createOp.Fields = map[string]string{"version": "1.2"}
+	opp.Append(createOp)
+	opp.Append(NewSetTitleOp(rene, unix, "title2", "title1"))
+	opp.Append(NewAddCommentOp(rene, unix, "message2", []git.Hash{"0123456789abcdef0123456789abcdef01234567"}))
+	opp.Append(NewSetStatusOp(rene, unix, ClosedStatus))
+	opp.Append(NewLabelChangeOperation(rene, unix, []Label{"added"}, nil))
+	opp.Append(NewEditCommentOp(rene, unix, createOp.Id(), "edited", nil))
+	opp.Append(NewNoOpOp(rene, unix))
+	opp.Append(NewSetMetadataOp(rene, unix, createOp.Id(), map[string]string{"key": "value"}))
+	opp.Append(NewSetComponentOp(rene, unix, "core", ""))
+	opp.Append(NewAssigneeChangeOperation(rene, unix, []identity.Interface{rene}, nil))
+	opp.Append(NewSetParentOp(rene, unix, createOp.Id()))
+	opp.Append(NewRemoveParentOp(rene, unix, createOp.Id()))
+	addItemOp := NewAddChecklistItemOp(rene, unix, "item")
+	opp.Append(addItemOp)
+	opp.Append(NewCheckItemOp(rene, unix, addItemOp.Id(), true))
+	opp.Append(NewVoteOp(rene, unix, false))
+	opp.Append(NewVoteOp(rene, unix, true))
+	opp.Append(NewRequestReviewOp(rene, unix, []identity.Interface{rene}, "ptal"))
+	opp.Append(NewCustomOp(rene, unix, dueDateTag, &dueDatePayload{Due: "2024-01-15"}))
+
+	data, err := json.Marshal(opp)
+	require.NoError(t, err)
+	require.NoError(t, validateOperationPackData(data))
+
+	bad := []string{
+		`{"version":1,"ops":[{"type":1,"author":{"name":"René"},"timestamp":1,"message":"no title"}]}`,
+		`{"version":1,"ops":[{"type":4,"author":{"name":"René"},"timestamp":1,"status":1000}]}`,
+		`{"version":1,"ops":[{"type":3,"author":{"id":"invalid"},"timestamp":1,"message":"message"}]}`,
+		`{"version":1,"ops":[{"type":1000,"author":{"name":"René"},"timestamp":1,"data":{}}]}`,
+	}
+
+	for _, data := range bad {
+		assert.Error(t, validateOperationPackData([]byte(data)), data)
+	}
+}
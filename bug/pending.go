@@ -0,0 +1,246 @@
+package bug
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+const pendingRefPrefix = "refs/pending/"
+const pendingRefPattern = "refs/pending/%s/bugs/"
+const rejectedRefPrefix = "refs/rejected/"
+const rejectedRefPattern = "refs/rejected/%s/bugs/"
+
+// PendingBug is a version of a bug coming from an untrusted remote, held for
+// review until it is accepted or rejected
+type PendingBug struct {
+	Remote string
+	Id     entity.Id
+	Head   git.Hash
+}
+
+func (p PendingBug) ref() string {
+	return fmt.Sprintf(pendingRefPattern, p.Remote) + p.Id.String()
+}
+
+func (p PendingBug) rejectedRef() string {
+	return fmt.Sprintf(rejectedRefPattern, p.Remote) + p.Id.String()
+}
+
+// Read read and validate the pending version of the bug
+func (p PendingBug) Read(repo repository.ClockedRepo) (*Bug, error) {
+	b, err := readBug(repo, identity.NewRemoteResolver(repo, p.Remote), p.ref(), true)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// UnmergedOperations return the operations of another version of a bug, as a
+// pending one, which are not in the local bug
+func UnmergedOperations(repo repository.ClockedRepo, other *Bug) ([]Operation, error) {
+	known := make(map[entity.Id]struct{})
+
+	local, err := ReadLocalBug(repo, other.Id())
+	switch {
+	case err == ErrBugNotExist:
+	case err != nil:
+		return nil, err
+	default:
+		it := NewOperationIterator(local)
+		for it.Next() {
+			known[it.Value().Id()] = struct{}{}
+		}
+	}
+
+	var result []Operation
+	it := NewOperationIterator(other)
+	for it.Next() {
+		if _, ok := known[it.Value().Id()]; !ok {
+			result = append(result, it.Value())
+		}
+	}
+
+	return result, nil
+}
+
+// HoldAll hold for review the bugs of a remote bringing new operations,
+// instead of merging them as MergeAll does. They are stored as PendingBug
+// until accepted or rejected. A version rejected already is not held again.
+//
+// The remote bugs are validated as with MergeAll, the invalid ones being
+// reported and not held.
+func HoldAll(repo repository.ClockedRepo, remote string) <-chan entity.MergeResult {
+	out := make(chan entity.MergeResult)
+
+	go func() {
+		defer close(out)
+
+		merger, err := newBugMerger(repo, nil)
+		if err != nil {
+			out <- entity.MergeResult{Err: err}
+			return
+		}
+
+		remoteRefSpec := fmt.Sprintf(bugsRemoteRefPattern, remote)
+		remoteRefs, err := repo.ListRefs(remoteRefSpec)
+
+		if err != nil {
+			out <- entity.MergeResult{Err: err}
+			return
+		}
+
+		for _, remoteRef := range remoteRefs {
+			refSplit := strings.Split(remoteRef, "/")
+			id := entity.Id(refSplit[len(refSplit)-1])
+
+			if err := id.Validate(); err != nil {
+				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "invalid ref").Error())
+				continue
+			}
+
+			// the bug has been removed, there is nothing to review
+			if _, ok := merger.removed[id]; ok {
+				continue
+			}
+
+			remoteBug, err := readBug(repo, identity.NewRemoteResolver(repo, remote), remoteRef, true)
+			if err != nil {
+				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote bug is not readable").Error())
+				continue
+			}
+
+			if err := remoteBug.Validate(); err != nil {
+				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote bug is invalid").Error())
+				continue
+			}
+
+			result, err := hold(repo, PendingBug{Remote: remote, Id: id, Head: remoteBug.lastCommit}, remoteBug)
+			if err != nil {
+				out <- entity.NewMergeError(err, id)
+				return
+			}
+			out <- result
+		}
+	}()
+
+	return out
+}
+
+func hold(repo repository.ClockedRepo, pending PendingBug, remoteBug *Bug) (entity.MergeResult, error) {
+	ops, err := UnmergedOperations(repo, remoteBug)
+	if err != nil {
+		return entity.MergeResult{}, err
+	}
+
+	exist, err := repo.RefExist(pending.ref())
+	if err != nil {
+		return entity.MergeResult{}, err
+	}
+
+	// nothing new, or the bug caught up in the meantime
+	if len(ops) == 0 {
+		if exist {
+			err = repo.RemoveRef(pending.ref())
+			if err != nil {
+				return entity.MergeResult{}, err
+			}
+		}
+		return entity.NewMergeStatus(entity.MergeStatusNothing, pending.Id, remoteBug), nil
+	}
+
+	for _, ref := range []string{pending.ref(), pending.rejectedRef()} {
+		hashes, err := repo.ListCommits(ref)
+		if err == nil && len(hashes) > 0 && hashes[len(hashes)-1] == pending.Head {
+			return entity.NewMergeStatus(entity.MergeStatusNothing, pending.Id, remoteBug), nil
+		}
+	}
+
+	err = repo.UpdateRef(pending.ref(), pending.Head)
+	if err != nil {
+		return entity.MergeResult{}, err
+	}
+
+	return entity.NewMergeStatus(entity.MergeStatusPending, pending.Id, remoteBug), nil
+}
+
+// ListPending return the bugs held for review
+func ListPending(repo repository.ClockedRepo) ([]PendingBug, error) {
+	refs, err := repo.ListRefs(pendingRefPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PendingBug, 0, len(refs))
+
+	for _, ref := range refs {
+		// refs/pending/<remote>/bugs/<id>
+		trimmed := strings.TrimPrefix(ref, pendingRefPrefix)
+		i := strings.LastIndex(trimmed, "/bugs/")
+		if i < 0 {
+			continue
+		}
+
+		hashes, err := repo.ListCommits(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, PendingBug{
+			Remote: trimmed[:i],
+			Id:     entity.Id(trimmed[i+len("/bugs/"):]),
+			Head:   hashes[len(hashes)-1],
+		})
+	}
+
+	return result, nil
+}
+
+// AcceptPending merge a pending bug into the local bugs, as MergeAll would have
+// done, and drop it from the pending bugs. An invalid pending bug is kept, to
+// be rejected. rootPacks is used as in MergeAll.
+func AcceptPending(repo repository.ClockedRepo, pending PendingBug, rootPacks RootPacks) entity.MergeResult {
+	merger, err := newBugMerger(repo, rootPacks)
+	if err != nil {
+		return entity.NewMergeError(err, pending.Id)
+	}
+
+	out := make(chan entity.MergeResult, 1)
+	merger.merge(pending.ref(), identity.NewRemoteResolver(repo, pending.Remote), out)
+	close(out)
+
+	result, ok := <-out
+	if !ok {
+		// the bug has been removed locally, and the pending ref with it
+		return entity.NewMergeStatus(entity.MergeStatusRemoved, pending.Id, nil)
+	}
+
+	switch result.Status {
+	case entity.MergeStatusNew, entity.MergeStatusUpdated, entity.MergeStatusNothing:
+		err = repo.RemoveRef(pending.ref())
+		if err != nil {
+			return entity.NewMergeError(err, pending.Id)
+		}
+	}
+
+	return result
+}
+
+// RejectPending drop a pending bug. The rejected version is remembered so
+// that it is not held again, until the remote bring new changes.
+func RejectPending(repo repository.ClockedRepo, pending PendingBug) error {
+	err := repo.CopyRef(pending.ref(), pending.rejectedRef())
+	if err != nil {
+		return err
+	}
+	return repo.RemoveRef(pending.ref())
+}
@@ -0,0 +1,124 @@
+package bug
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func holdAll(t *testing.T, repo repository.ClockedRepo, remote string) []entity.MergeResult {
+	t.Helper()
+
+	_, err := Fetch(repo, remote)
+	require.NoError(t, err)
+
+	var results []entity.MergeResult
+	for result := range HoldAll(repo, remote) {
+		require.NoError(t, result.Err)
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestPending(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repoB))
+	_, err := identity.Push(repoB, "origin")
+	require.NoError(t, err)
+	require.NoError(t, identity.Pull(repoA, "origin"))
+
+	bug1, _, err := Create(rene, time.Now().Unix(), "bug1", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoB))
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+
+	// the new bug is held, not merged
+	results := holdAll(t, repoA, "origin")
+	require.Len(t, results, 1)
+	require.Equal(t, entity.MergeStatusPending, results[0].Status)
+
+	_, err = ReadLocalBug(repoA, bug1.Id())
+	require.Equal(t, ErrBugNotExist, err)
+
+	pendings, err := ListPending(repoA)
+	require.NoError(t, err)
+	require.Len(t, pendings, 1)
+	require.Equal(t, "origin", pendings[0].Remote)
+	require.Equal(t, bug1.Id(), pendings[0].Id)
+
+	pendingBug, err := pendings[0].Read(repoA)
+	require.NoError(t, err)
+	ops, err := UnmergedOperations(repoA, pendingBug)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+
+	// held once only
+	results = holdAll(t, repoA, "origin")
+	require.Equal(t, entity.MergeStatusNothing, results[0].Status)
+
+	result := AcceptPending(repoA, pendings[0], nil)
+	require.NoError(t, result.Err)
+	require.Equal(t, entity.MergeStatusNew, result.Status)
+
+	_, err = ReadLocalBug(repoA, bug1.Id())
+	require.NoError(t, err)
+
+	pendings, err = ListPending(repoA)
+	require.NoError(t, err)
+	require.Empty(t, pendings)
+
+	// a rejected change is not held again
+	_, err = AddComment(bug1, rene, time.Now().Unix(), "spam")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoB))
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+
+	results = holdAll(t, repoA, "origin")
+	require.Equal(t, entity.MergeStatusPending, results[0].Status)
+
+	pendings, err = ListPending(repoA)
+	require.NoError(t, err)
+	require.Len(t, pendings, 1)
+	require.NoError(t, RejectPending(repoA, pendings[0]))
+
+	results = holdAll(t, repoA, "origin")
+	require.Equal(t, entity.MergeStatusNothing, results[0].Status)
+
+	pendings, err = ListPending(repoA)
+	require.NoError(t, err)
+	require.Empty(t, pendings)
+
+	// until the bug change again
+	_, err = AddComment(bug1, rene, time.Now().Unix(), "comment")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoB))
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+
+	results = holdAll(t, repoA, "origin")
+	require.Equal(t, entity.MergeStatusPending, results[0].Status)
+
+	pendings, err = ListPending(repoA)
+	require.NoError(t, err)
+	require.Len(t, pendings, 1)
+
+	pendingBug, err = pendings[0].Read(repoA)
+	require.NoError(t, err)
+	ops, err = UnmergedOperations(repoA, pendingBug)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	result = AcceptPending(repoA, pendings[0], nil)
+	require.NoError(t, result.Err)
+	require.Equal(t, entity.MergeStatusUpdated, result.Status)
+}
@@ -0,0 +1,82 @@
+package bug
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// A reference to a bug of another repository is written in a comment as the
+// remote URL of that repository, followed by '#' and the (possibly truncated)
+// id of the bug, for example:
+//
+//	https://github.com/MichaelMure/git-bug.git#7a3b2c1
+//	git@example.com:org/backend.git#7a3b2c1
+var referenceRegexp = regexp.MustCompile(
+	`((?:https?|ssh|git|file)://[^\s#]+|[\w.-]+@[\w.-]+:[^\s#]+)#([0-9a-f]{7,64})\b`)
+
+// Reference point to a bug of another repository
+type Reference struct {
+	// the git remote URL of the repository holding the bug
+	RepoUrl string
+	// the id of the bug, possibly truncated
+	BugId string
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("%s#%s", r.RepoUrl, r.BugId)
+}
+
+// HumanId return the truncated id of the referenced bug
+func (r Reference) HumanId() string {
+	return entity.Id(r.BugId).Human()
+}
+
+// MatchUrl tell if the given git remote URL designate the repository of the
+// reference, ignoring the differences that don't matter to git (trailing
+// slash, ".git" suffix)
+func (r Reference) MatchUrl(url string) bool {
+	return normalizeRepoUrl(r.RepoUrl) == normalizeRepoUrl(url)
+}
+
+func normalizeRepoUrl(url string) string {
+	url = strings.TrimSuffix(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+	return strings.ToLower(url)
+}
+
+// ParseReferences find all the references to other repositories' bugs in a
+// text
+func ParseReferences(text string) []Reference {
+	var result []Reference
+
+	for _, match := range referenceRegexp.FindAllStringSubmatch(text, -1) {
+		result = append(result, Reference{
+			RepoUrl: match[1],
+			BugId:   match[2],
+		})
+	}
+
+	return result
+}
+
+// References return the references to other repositories' bugs found in the
+// comments of the bug, without duplicate
+func (snap *Snapshot) References() []Reference {
+	var result []Reference
+	seen := make(map[Reference]struct{})
+
+	for _, comment := range snap.Comments {
+		for _, ref := range ParseReferences(comment.Message) {
+			if _, ok := seen[ref]; ok {
+				continue
+			}
+			seen[ref] = struct{}{}
+			result = append(result, ref)
+		}
+	}
+
+	return result
+}
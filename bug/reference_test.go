@@ -0,0 +1,23 @@
+package bug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReferences(t *testing.T) {
+	refs := ParseReferences(`Caused by https://github.com/org/backend.git#7a3b2c1 and
+git@example.com:org/frontend#0123456789abcdef, but not by #7a3b2c1 or
+https://example.com/page#section`)
+
+	require.Equal(t, []Reference{
+		{RepoUrl: "https://github.com/org/backend.git", BugId: "7a3b2c1"},
+		{RepoUrl: "git@example.com:org/frontend", BugId: "0123456789abcdef"},
+	}, refs)
+
+	require.Equal(t, "0123456", refs[1].HumanId())
+	require.True(t, refs[0].MatchUrl("https://github.com/org/backend"))
+	require.True(t, refs[0].MatchUrl("https://github.com/Org/Backend.git/"))
+	require.False(t, refs[0].MatchUrl("https://github.com/org/frontend.git"))
+}
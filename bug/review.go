@@ -0,0 +1,67 @@
+package bug
+
+import (
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+// ReviewRequest is the input asked from an identity on a bug. It is answered
+// as soon as the reviewer do anything on the bug, as commenting.
+type ReviewRequest struct {
+	// the id of the operation that requested the review
+	id        entity.Id
+	Reviewer  identity.Interface
+	Requester identity.Interface
+	Message   string
+	Answered  bool
+
+	// Creation time of the request.
+	// Should be used only for human display, never for ordering as we can't rely on it in a distributed system.
+	UnixTime timestamp.Timestamp
+}
+
+// Id return the ReviewRequest identifier
+func (r ReviewRequest) Id() entity.Id {
+	if r.id == "" {
+		// simply panic as it would be a coding error
+		panic("no id yet")
+	}
+	return r.id
+}
+
+// Sign post method for gqlgen
+func (r ReviewRequest) IsAuthored() {}
+
+// PendingReviewers return the identities whose review has been requested,
+// and who didn't answer yet
+func (snap *Snapshot) PendingReviewers() []identity.Interface {
+	var result []identity.Interface
+	for _, r := range snap.ReviewRequests {
+		if !r.Answered {
+			result = append(result, r.Reviewer)
+		}
+	}
+	return result
+}
+
+// IsReviewPending return true if the review of the identity has been
+// requested, and not answered yet
+func (snap *Snapshot) IsReviewPending(id entity.Id) bool {
+	for _, r := range snap.ReviewRequests {
+		if !r.Answered && r.Reviewer.Id() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// answerReviews mark as answered the review requests of an identity acting
+// on the bug
+func (snap *Snapshot) answerReviews(actor identity.Interface) {
+	for i := range snap.ReviewRequests {
+		if snap.ReviewRequests[i].Reviewer.Id() == actor.Id() {
+			snap.ReviewRequests[i].Answered = true
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// RewriteAction tell what to do of an operation when rewriting the history of
+// a bug
+type RewriteAction int
+
+const (
+	// keep the operation as is
+	RewritePick RewriteAction = iota
+	// replace the text of the operation
+	RewriteEdit
+	// merge the operation into the previous one kept
+	RewriteSquash
+	// remove the operation
+	RewriteDrop
+)
+
+// RewriteStep is what to do of one of the rewritable operations of a bug
+type RewriteStep struct {
+	Action    RewriteAction
+	Operation entity.Id
+	// the new text of the operation, for RewriteEdit
+	Text string
+}
+
+// publishedCommits return the commits of the bug known to be on one of the
+// remotes, from their remote-tracking reference
+func (bug *Bug) publishedCommits(repo repository.Repo, remotes []string) (map[git.Hash]bool, error) {
+	published := make(map[git.Hash]bool)
+
+	for _, remote := range remotes {
+		remoteRef := fmt.Sprintf(bugsRemoteRefPattern, remote) + bug.Id().String()
+		exist, err := repo.RefExist(remoteRef)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			continue
+		}
+
+		hashes, err := repo.ListCommits(remoteRef)
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range hashes {
+			published[hash] = true
+		}
+	}
+
+	return published, nil
+}
+
+// firstRewritablePack return the index of the first pack that can be
+// rewritten: the packs after the last one published on the remotes. The first
+// pack is never rewritten as its commit define the id of the bug.
+func (bug *Bug) firstRewritablePack(repo repository.Repo, remotes []string) (int, error) {
+	published, err := bug.publishedCommits(repo, remotes)
+	if err != nil {
+		return 0, err
+	}
+
+	first := 1
+	for i, pack := range bug.packs {
+		if published[pack.commitHash] && i+1 > first {
+			first = i + 1
+		}
+	}
+	return first, nil
+}
+
+// RewritableOperations return the committed operations of the bug that no
+// remote has yet, as known from their remote-tracking references, and that
+// can be rewritten. The operations of the first commit of the bug are never
+// rewritable, as this commit define the id of the bug.
+func (bug *Bug) RewritableOperations(repo repository.Repo, remotes []string) ([]Operation, error) {
+	first, err := bug.firstRewritablePack(repo, remotes)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Operation
+	for _, pack := range bug.packs[first:] {
+		result = append(result, pack.Operations...)
+	}
+	return result, nil
+}
+
+// Rewrite edit, squash or drop the rewritable operations of the bug, and
+// write them again in a single commit on top of the published ones, in place
+// of the previous unpublished commits. The steps follow the order of the
+// operations, and the operations without a step are dropped.
+//
+// The operations referring to an edited operation are updated to refer to the
+// new one, but an operation can't refer to an operation dropped or squashed.
+func (bug *Bug) Rewrite(repo repository.ClockedRepo, remotes []string, steps []RewriteStep) error {
+	if bug.NeedCommit() {
+		return fmt.Errorf("can't rewrite a bug with pending operations")
+	}
+	if bug.HasUnknownOperations() {
+		return ErrNewerFormat
+	}
+
+	first, err := bug.firstRewritablePack(repo, remotes)
+	if err != nil {
+		return err
+	}
+	if first >= len(bug.packs) {
+		return fmt.Errorf("no operation to rewrite")
+	}
+
+	var ops []Operation
+	for _, pack := range bug.packs[first:] {
+		ops = append(ops, pack.Operations...)
+	}
+
+	rewritten, err := rewriteOperations(ops, steps)
+	if err != nil {
+		return err
+	}
+
+	// restored if the new commit fail
+	packs, lastCommit, editTime := bug.packs, bug.lastCommit, bug.editTime
+
+	bug.packs = append([]OperationPack(nil), bug.packs[:first]...)
+	bug.lastCommit = bug.packs[first-1].commitHash
+	bug.editTime = 0
+	for _, pack := range bug.packs {
+		if pack.editTime > bug.editTime {
+			bug.editTime = pack.editTime
+		}
+	}
+
+	if len(rewritten) > 0 {
+		bug.staging = OperationPack{Operations: rewritten}
+		err = bug.commitStaging(repo)
+		if err != nil {
+			bug.packs, bug.lastCommit, bug.editTime = packs, lastCommit, editTime
+			bug.staging = OperationPack{}
+			return err
+		}
+	}
+
+	// not a fast-forward update, but only of the commits the remotes don't have
+	return repo.UpdateRef(bug.ref(), bug.lastCommit)
+}
+
+// rewriteOperations apply the steps on the operations, returning new
+// operations for the edited ones
+func rewriteOperations(ops []Operation, steps []RewriteStep) ([]Operation, error) {
+	// the new ids of the edited operations, and the removed ids
+	newIds := make(map[entity.Id]entity.Id)
+	removed := make(map[entity.Id]RewriteAction)
+
+	var result []Operation
+	next := 0
+
+	for _, step := range steps {
+		// the operations skipped by the steps are dropped
+		for next < len(ops) && ops[next].Id() != step.Operation {
+			removed[ops[next].Id()] = RewriteDrop
+			next++
+		}
+		if next >= len(ops) {
+			return nil, fmt.Errorf("operation %s is not rewritable or out of order", step.Operation.Human())
+		}
+		op := ops[next]
+		next++
+
+		if step.Action == RewriteDrop {
+			removed[op.Id()] = RewriteDrop
+			continue
+		}
+
+		op, err := retarget(op, newIds, removed)
+		if err != nil {
+			return nil, err
+		}
+
+		switch step.Action {
+		case RewritePick:
+
+		case RewriteEdit:
+			op, err = editOperationText(op, step.Text)
+			if err != nil {
+				return nil, err
+			}
+
+		case RewriteSquash:
+			if len(result) == 0 {
+				return nil, fmt.Errorf("no previous operation to squash %s into", step.Operation.Human())
+			}
+			previous := result[len(result)-1]
+			squashed, err := squashOperations(previous, op)
+			if err != nil {
+				return nil, err
+			}
+			// the previous operation changes, but being the last one kept, no
+			// kept operation refer to it yet
+			newIds[previous.Id()] = squashed.Id()
+			removed[step.Operation] = RewriteSquash
+			result[len(result)-1] = squashed
+			continue
+
+		default:
+			return nil, fmt.Errorf("unknown rewrite action %d", step.Action)
+		}
+
+		if op.Id() != step.Operation {
+			newIds[step.Operation] = op.Id()
+		}
+		result = append(result, op)
+	}
+
+	for _, op := range result {
+		if err := op.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// retarget return a copy of the operation referring to the new id of the
+// operation it target, if it changed
+func retarget(op Operation, newIds map[entity.Id]entity.Id, removed map[entity.Id]RewriteAction) (Operation, error) {
+	var target entity.Id
+	switch op := op.(type) {
+	case *AddCommentOperation:
+		if op.ReplyTo == "" {
+			return op, nil
+		}
+		target = op.ReplyTo
+	case *EditCommentOperation:
+		target = op.Target
+	case *CheckItemOperation:
+		target = op.Target
+	case *SetMetadataOperation:
+		target = op.Target
+	default:
+		return op, nil
+	}
+
+	if action, ok := removed[target]; ok {
+		what := "dropped"
+		if action == RewriteSquash {
+			what = "squashed"
+		}
+		return nil, fmt.Errorf("operation %s refer to the %s operation %s", op.Id().Human(), what, target.Human())
+	}
+
+	newId, ok := newIds[target]
+	if !ok {
+		return op, nil
+	}
+	// an edited operation can change again when squashed into
+	for next, ok := newIds[newId]; ok; next, ok = newIds[newId] {
+		newId = next
+	}
+
+	switch op := op.(type) {
+	case *AddCommentOperation:
+		clone := *op
+		clone.ReplyTo = newId
+		return resetId(&clone), nil
+	case *EditCommentOperation:
+		clone := *op
+		clone.Target = newId
+		return resetId(&clone), nil
+	case *CheckItemOperation:
+		clone := *op
+		clone.Target = newId
+		return resetId(&clone), nil
+	case *SetMetadataOperation:
+		clone := *op
+		clone.Target = newId
+		return resetId(&clone), nil
+	}
+	panic("unreachable")
+}
+
+// editOperationText return a copy of the operation with a new text
+func editOperationText(op Operation, text string) (Operation, error) {
+	switch op := op.(type) {
+	case *AddCommentOperation:
+		clone := *op
+		clone.Message = text
+		return resetId(&clone), nil
+	case *EditCommentOperation:
+		clone := *op
+		clone.Message = text
+		return resetId(&clone), nil
+	case *SetTitleOperation:
+		clone := *op
+		clone.Title = text
+		return resetId(&clone), nil
+	case *AddChecklistItemOperation:
+		clone := *op
+		clone.Text = text
+		return resetId(&clone), nil
+	case *RequestReviewOperation:
+		clone := *op
+		clone.Message = text
+		return resetId(&clone), nil
+	default:
+		return nil, fmt.Errorf("operation %s has no text to edit", op.Id().Human())
+	}
+}
+
+// squashOperations merge an operation into the previous one, for two comments
+// or two title changes
+func squashOperations(previous Operation, op Operation) (Operation, error) {
+	switch previous := previous.(type) {
+	case *AddCommentOperation:
+		if op, ok := op.(*AddCommentOperation); ok {
+			clone := *previous
+			clone.Message = previous.Message + "\n\n" + op.Message
+			clone.Files = append(append([]git.Hash(nil), previous.Files...), op.Files...)
+			return resetId(&clone), nil
+		}
+	case *SetTitleOperation:
+		if op, ok := op.(*SetTitleOperation); ok {
+			clone := *previous
+			clone.Title = op.Title
+			return resetId(&clone), nil
+		}
+	}
+
+	return nil, fmt.Errorf("operation %s can only be squashed into a previous operation of the same type, a comment or a title change", op.Id().Human())
+}
+
+// resetId clear the id of a modified operation, for it to be computed again
+func resetId(op Operation) Operation {
+	op.base().id = entity.UnsetId
+	return op
+}
@@ -0,0 +1,134 @@
+package bug
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestRewrite(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repoA))
+
+	unix := time.Now().Unix()
+
+	bug1, _, err := Create(rene, unix, "bug1", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	// the first commit is never rewritable
+	ops, err := bug1.RewritableOperations(repoA, []string{"origin"})
+	require.NoError(t, err)
+	require.Len(t, ops, 0)
+
+	_, err = AddComment(bug1, rene, unix+1, "publshed")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	comment1, err := AddComment(bug1, rene, unix+2, "frist")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+	comment2, err := AddComment(bug1, rene, unix+3, "second")
+	require.NoError(t, err)
+	_, _, err = ChangeLabels(bug1, rene, unix+4, []string{"bug"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+	edit, err := EditComment(bug1, rene, unix+5, comment1.Id(), "first, second")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	ops, err = bug1.RewritableOperations(repoA, []string{"origin"})
+	require.NoError(t, err)
+	require.Len(t, ops, 4)
+
+	// an operation can't refer to a dropped one
+	err = bug1.Rewrite(repoA, []string{"origin"}, []RewriteStep{
+		{Action: RewriteDrop, Operation: comment1.Id()},
+		{Action: RewritePick, Operation: comment2.Id()},
+		{Action: RewritePick, Operation: edit.Id()},
+	})
+	require.Error(t, err)
+
+	// the steps follow the order of the operations
+	err = bug1.Rewrite(repoA, []string{"origin"}, []RewriteStep{
+		{Action: RewritePick, Operation: comment2.Id()},
+		{Action: RewritePick, Operation: comment1.Id()},
+	})
+	require.Error(t, err)
+
+	err = bug1.Rewrite(repoA, []string{"origin"}, []RewriteStep{
+		{Action: RewriteEdit, Operation: comment1.Id(), Text: "first"},
+		{Action: RewriteSquash, Operation: comment2.Id()},
+		// the label change is dropped
+		{Action: RewritePick, Operation: edit.Id()},
+	})
+	require.NoError(t, err)
+
+	check := func(b *Bug) {
+		snap := b.Compile()
+		require.Len(t, snap.Comments, 3)
+		require.Equal(t, "publshed", snap.Comments[1].Message)
+		require.Equal(t, "first, second", snap.Comments[2].Message)
+		require.Empty(t, snap.Labels)
+
+		ops, err := b.RewritableOperations(repoA, []string{"origin"})
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		merged := ops[0].(*AddCommentOperation)
+		require.Equal(t, "first\n\nsecond", merged.Message)
+		require.Equal(t, merged.Id(), ops[1].(*EditCommentOperation).Target)
+		require.NotEqual(t, edit.Id(), ops[1].Id())
+	}
+
+	check(bug1)
+
+	// the reference of the bug has been moved
+	read, err := ReadLocalBug(repoA, bug1.Id())
+	require.NoError(t, err)
+	check(read)
+
+	// and the rewritten history is pushed as a fast-forward
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	ops, err = read.RewritableOperations(repoA, []string{"origin"})
+	require.NoError(t, err)
+	require.Len(t, ops, 0)
+
+	err = read.Rewrite(repoA, []string{"origin"}, nil)
+	require.Error(t, err)
+}
+
+func TestRewriteDropAll(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repo))
+
+	bug1, _, err := Create(rene, time.Now().Unix(), "bug1", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repo))
+
+	_, err = SetTitle(bug1, rene, time.Now().Unix(), "tilte")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repo))
+
+	// no remote, only the first commit stays
+	require.NoError(t, bug1.Rewrite(repo, nil, nil))
+
+	read, err := ReadLocalBug(repo, bug1.Id())
+	require.NoError(t, err)
+	require.Equal(t, "bug1", read.Compile().Title)
+	require.Len(t, read.CommittedOperations(), 1)
+}
@@ -0,0 +1,153 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// the tree entry holding the signature of the OperationPack, if signed
+const signatureEntryName = "signature"
+
+const signatureFormatVersion = 1
+
+// opsSignature is the signature of the blob of an OperationPack, made by the
+// identity.Signer configured when committing. Unlike the signature of a
+// commit, it survive the rebase of the commit when merging.
+type opsSignature struct {
+	// Additional field to version the data
+	FormatVersion uint `json:"version"`
+
+	// the key of the signer, to check the signature even if the key isn't
+	// registered on the author
+	Key       *identity.Key `json:"key"`
+	Signature []byte        `json:"signature"`
+}
+
+// OpsSignature is the validity of the signature of the operations of a commit
+type OpsSignature struct {
+	Status repository.SignatureStatus `json:"status"`
+	// the fingerprint of the key, empty when not signed
+	Key string `json:"key,omitempty"`
+}
+
+// signOperationPack sign the data of an OperationPack with the configured
+// signer, if any, and return the tree entry holding the signature
+func signOperationPack(repo repository.Repo, data []byte) (*repository.TreeEntry, error) {
+	signature, err := identity.SignData(repo, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't sign the operations")
+	}
+	if signature == nil {
+		return nil, nil
+	}
+
+	blob, err := json.Marshal(opsSignature{
+		FormatVersion: signatureFormatVersion,
+		Key:           signature.Key,
+		Signature:     signature.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.StoreData(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.TreeEntry{
+		ObjectType: repository.Blob,
+		Hash:       hash,
+		Name:       signatureEntryName,
+	}, nil
+}
+
+// verifyOperationPack check the signature of the operations of a commit. The
+// signature is good if made by a key of the author at the time of the commit.
+func verifyOperationPack(repo repository.Repo, pack OperationPack, opsBlob git.Hash, signatureBlob git.Hash) (OpsSignature, error) {
+	if signatureBlob == "" {
+		return OpsSignature{Status: repository.SignatureNone}, nil
+	}
+
+	raw, err := repo.ReadData(signatureBlob)
+	if err != nil {
+		return OpsSignature{}, errors.Wrap(err, "failed to read git blob data")
+	}
+
+	var signature opsSignature
+	if err := json.Unmarshal(raw, &signature); err != nil || signature.Key == nil {
+		return OpsSignature{Status: repository.SignatureBad}, nil
+	}
+	if signature.FormatVersion != signatureFormatVersion {
+		return OpsSignature{}, fmt.Errorf("unknown signature format version %v", signature.FormatVersion)
+	}
+
+	result := OpsSignature{Key: signature.Key.Fingerprint}
+
+	data, err := repo.ReadData(opsBlob)
+	if err != nil {
+		return OpsSignature{}, errors.Wrap(err, "failed to read git blob data")
+	}
+
+	err = identity.VerifySignature(signature.Key, data, signature.Signature)
+	switch {
+	case err == identity.ErrBadSignature:
+		result.Status = repository.SignatureBad
+		return result, nil
+	case err != nil:
+		result.Status = repository.SignatureUnverifiable
+		return result, nil
+	}
+
+	// the signature is expected from a key of the author of every operation,
+	// as of the identity version effective at the time of the commit: a pack
+	// holding the operations of several authors, as written by a BatchWriter,
+	// must not vouch for the other authors with the key of one of them.
+	if len(pack.Operations) == 0 {
+		result.Status = repository.SignatureUnknownValidity
+		return result, nil
+	}
+	for _, op := range pack.Operations {
+		if !identity.HasKey(op.GetAuthor().ValidKeysAtTime(pack.editTime), signature.Key.Fingerprint) {
+			result.Status = repository.SignatureUnknownValidity
+			return result, nil
+		}
+	}
+
+	result.Status = repository.SignatureGood
+	return result, nil
+}
+
+// packBlobs return the tree of the commit of an OperationPack, with the blobs
+// of the operations and of their signature, if any
+func packBlobs(repo repository.Repo, pack OperationPack) (tree git.Hash, opsBlob git.Hash, signatureBlob git.Hash, err error) {
+	tree, err = repo.GetTreeHash(pack.commitHash)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	entries, err := repo.ListEntries(tree)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	for _, entry := range entries {
+		switch entry.Name {
+		case opsEntryName:
+			opsBlob = entry.Hash
+		case signatureEntryName:
+			signatureBlob = entry.Hash
+		}
+	}
+	if opsBlob == "" {
+		return "", "", "", errors.New("invalid tree, missing the ops entry")
+	}
+
+	return tree, opsBlob, signatureBlob, nil
+}
@@ -1,25 +1,44 @@
 package bug
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/git"
 )
 
 // Snapshot is a compiled form of the Bug data structure used for storage and merge
 type Snapshot struct {
 	id entity.Id
 
-	Status       Status
-	Title        string
-	Comments     []Comment
-	Labels       []Label
+	Status     Status
+	Resolution Resolution
+	Title      string
+	Comments   []Comment
+	Labels     []Label
+	Component  string
+	// the custom fields set on creation, as a version
+	Fields       map[string]string
+	Assignees    []identity.Interface
 	Author       identity.Interface
 	Actors       []identity.Interface
 	Participants []identity.Interface
 	CreatedAt    time.Time
+	// the canonical bug this bug is a duplicate of, if any
+	DuplicateOf entity.Id
+	// the bug this bug is a sub-task of, if any
+	Parent entity.Id
+	// the tasks to do to fix the bug, if any
+	Checklist []ChecklistItem
+	// the identities that voted for the bug
+	Voters []identity.Interface
+	// the input asked from identities, answered or not
+	ReviewRequests []ReviewRequest
+	// the bug has been reported with an anonymous identity
+	Anonymous bool
 
 	Timeline []TimelineItem
 
@@ -76,8 +95,11 @@ func (snap *Snapshot) SearchComment(id entity.Id) (*Comment, error) {
 	return nil, fmt.Errorf("comment item not found")
 }
 
-// append the operation author to the actors list
+// append the operation author to the actors list, and answer their review
+// requests
 func (snap *Snapshot) addActor(actor identity.Interface) {
+	snap.answerReviews(actor)
+
 	for _, a := range snap.Actors {
 		if actor.Id() == a.Id() {
 			return
@@ -98,6 +120,36 @@ func (snap *Snapshot) addParticipant(participant identity.Interface) {
 	snap.Participants = append(snap.Participants, participant)
 }
 
+// IsAssigned return true if the id is an assignee
+func (snap *Snapshot) IsAssigned(id entity.Id) bool {
+	for _, a := range snap.Assignees {
+		if a.Id() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HasVoted return true if the identity voted for the bug
+func (snap *Snapshot) HasVoted(id entity.Id) bool {
+	for _, v := range snap.Voters {
+		if v.Id() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLabel return true if the label is set on the bug
+func (snap *Snapshot) HasLabel(label Label) bool {
+	for _, l := range snap.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 // HasParticipant return true if the id is a participant
 func (snap *Snapshot) HasParticipant(id entity.Id) bool {
 	for _, p := range snap.Participants {
@@ -138,5 +190,195 @@ func (snap *Snapshot) HasAnyActor(ids ...entity.Id) bool {
 	return false
 }
 
+// ReopenCount return how many times the bug has been reopened after being
+// closed
+func (snap *Snapshot) ReopenCount() int {
+	count := 0
+	closed := false
+
+	for _, op := range snap.Operations {
+		switch op := op.(type) {
+		case *SetStatusOperation:
+			if closed && op.Status == OpenStatus {
+				count++
+			}
+			closed = op.Status == ClosedStatus
+		case *MarkDuplicateOperation:
+			closed = true
+		}
+	}
+
+	return count
+}
+
 // Sign post method for gqlgen
 func (snap *Snapshot) IsAuthored() {}
+
+type snapshotIdentityJSON struct {
+	Id        entity.Id `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Login     string    `json:"login"`
+	AvatarUrl string    `json:"avatar_url"`
+}
+
+type snapshotCommentJSON struct {
+	Id        entity.Id            `json:"id"`
+	Author    snapshotIdentityJSON `json:"author"`
+	Message   string               `json:"message"`
+	Files     []git.Hash           `json:"files"`
+	ReplyTo   *entity.Id           `json:"reply_to"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type snapshotReviewRequestJSON struct {
+	Reviewer  snapshotIdentityJSON `json:"reviewer"`
+	Requester snapshotIdentityJSON `json:"requester"`
+	Message   string               `json:"message"`
+	Answered  bool                 `json:"answered"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type snapshotChecklistItemJSON struct {
+	Id        entity.Id            `json:"id"`
+	Author    snapshotIdentityJSON `json:"author"`
+	Text      string               `json:"text"`
+	Checked   bool                 `json:"checked"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+func newSnapshotIdentityJSON(i identity.Interface) snapshotIdentityJSON {
+	return snapshotIdentityJSON{
+		Id:        i.Id(),
+		Name:      i.Name(),
+		Email:     i.Email(),
+		Login:     i.Login(),
+		AvatarUrl: i.AvatarUrl(),
+	}
+}
+
+func newSnapshotIdentitiesJSON(identities []identity.Interface) []snapshotIdentityJSON {
+	result := make([]snapshotIdentityJSON, len(identities))
+	for i, id := range identities {
+		result[i] = newSnapshotIdentityJSON(id)
+	}
+	return result
+}
+
+// MarshalJSON output the snapshot in the format described by the "snapshot"
+// JSON schema, for the external tools
+func (snap *Snapshot) MarshalJSON() ([]byte, error) {
+	labels := make([]string, len(snap.Labels))
+	for i, label := range snap.Labels {
+		labels[i] = label.String()
+	}
+
+	fields := snap.Fields
+	if fields == nil {
+		fields = map[string]string{}
+	}
+
+	var resolution *string
+	if snap.Resolution != NoResolution {
+		str := snap.Resolution.String()
+		resolution = &str
+	}
+
+	var duplicateOf *entity.Id
+	if snap.DuplicateOf != "" {
+		duplicateOf = &snap.DuplicateOf
+	}
+
+	var parent *entity.Id
+	if snap.Parent != "" {
+		parent = &snap.Parent
+	}
+
+	comments := make([]snapshotCommentJSON, len(snap.Comments))
+	for i, comment := range snap.Comments {
+		files := comment.Files
+		if files == nil {
+			files = []git.Hash{}
+		}
+		var replyTo *entity.Id
+		if comment.ReplyTo != "" {
+			replyTo = &snap.Comments[i].ReplyTo
+		}
+		comments[i] = snapshotCommentJSON{
+			Id:        comment.Id(),
+			Author:    newSnapshotIdentityJSON(comment.Author),
+			Message:   comment.Message,
+			Files:     files,
+			ReplyTo:   replyTo,
+			CreatedAt: comment.UnixTime.Time(),
+		}
+	}
+
+	checklist := make([]snapshotChecklistItemJSON, len(snap.Checklist))
+	for i, item := range snap.Checklist {
+		checklist[i] = snapshotChecklistItemJSON{
+			Id:        item.Id(),
+			Author:    newSnapshotIdentityJSON(item.Author),
+			Text:      item.Text,
+			Checked:   item.Checked,
+			CreatedAt: item.UnixTime.Time(),
+		}
+	}
+
+	reviewRequests := make([]snapshotReviewRequestJSON, len(snap.ReviewRequests))
+	for i, r := range snap.ReviewRequests {
+		reviewRequests[i] = snapshotReviewRequestJSON{
+			Reviewer:  newSnapshotIdentityJSON(r.Reviewer),
+			Requester: newSnapshotIdentityJSON(r.Requester),
+			Message:   r.Message,
+			Answered:  r.Answered,
+			CreatedAt: r.UnixTime.Time(),
+		}
+	}
+
+	return json.Marshal(struct {
+		Id           entity.Id                   `json:"id"`
+		HumanId      string                      `json:"human_id"`
+		Title        string                      `json:"title"`
+		Status       string                      `json:"status"`
+		Resolution   *string                     `json:"resolution"`
+		Component    string                      `json:"component"`
+		Labels       []string                    `json:"labels"`
+		Fields       map[string]string           `json:"fields"`
+		Author       snapshotIdentityJSON        `json:"author"`
+		Assignees    []snapshotIdentityJSON      `json:"assignees"`
+		Actors       []snapshotIdentityJSON      `json:"actors"`
+		Participants []snapshotIdentityJSON      `json:"participants"`
+		CreatedAt    time.Time                   `json:"created_at"`
+		EditedAt     time.Time                   `json:"edited_at"`
+		Anonymous    bool                        `json:"anonymous"`
+		DuplicateOf  *entity.Id                  `json:"duplicate_of"`
+		Parent       *entity.Id                  `json:"parent"`
+		Checklist    []snapshotChecklistItemJSON `json:"checklist"`
+		Voters       []snapshotIdentityJSON      `json:"voters"`
+		Reviews      []snapshotReviewRequestJSON `json:"review_requests"`
+		Comments     []snapshotCommentJSON       `json:"comments"`
+	}{
+		Id:           snap.id,
+		HumanId:      snap.id.Human(),
+		Title:        snap.Title,
+		Status:       snap.Status.String(),
+		Resolution:   resolution,
+		Component:    snap.Component,
+		Labels:       labels,
+		Fields:       fields,
+		Author:       newSnapshotIdentityJSON(snap.Author),
+		Assignees:    newSnapshotIdentitiesJSON(snap.Assignees),
+		Actors:       newSnapshotIdentitiesJSON(snap.Actors),
+		Participants: newSnapshotIdentitiesJSON(snap.Participants),
+		CreatedAt:    snap.CreatedAt,
+		EditedAt:     snap.LastEditTime(),
+		Anonymous:    snap.Anonymous,
+		DuplicateOf:  duplicateOf,
+		Parent:       parent,
+		Checklist:    checklist,
+		Voters:       newSnapshotIdentitiesJSON(snap.Voters),
+		Reviews:      reviewRequests,
+		Comments:     comments,
+	})
+}
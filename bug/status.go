@@ -55,3 +55,64 @@ func (s Status) Validate() error {
 
 	return nil
 }
+
+// Resolution tell why a bug has been closed. It's optional, a closed bug
+// can have no resolution.
+type Resolution int
+
+const (
+	NoResolution Resolution = iota
+	FixedResolution
+	WontFixResolution
+	DuplicateResolution
+	InvalidResolution
+	WorksForMeResolution
+)
+
+// Resolutions list the valid resolutions, in order
+var Resolutions = []Resolution{
+	FixedResolution,
+	WontFixResolution,
+	DuplicateResolution,
+	InvalidResolution,
+	WorksForMeResolution,
+}
+
+func (r Resolution) String() string {
+	switch r {
+	case NoResolution:
+		return ""
+	case FixedResolution:
+		return "fixed"
+	case WontFixResolution:
+		return "wontfix"
+	case DuplicateResolution:
+		return "duplicate"
+	case InvalidResolution:
+		return "invalid"
+	case WorksForMeResolution:
+		return "works-for-me"
+	default:
+		return "unknown resolution"
+	}
+}
+
+func ResolutionFromString(str string) (Resolution, error) {
+	cleaned := strings.ToLower(strings.TrimSpace(str))
+
+	for _, r := range Resolutions {
+		if r.String() == cleaned {
+			return r, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown resolution %s", str)
+}
+
+func (r Resolution) Validate() error {
+	if r < NoResolution || r > WorksForMeResolution {
+		return fmt.Errorf("invalid")
+	}
+
+	return nil
+}
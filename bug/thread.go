@@ -0,0 +1,100 @@
+package bug
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// CommentThread is a comment with the replies to it, in order
+type CommentThread struct {
+	Comment Comment
+	Replies []*CommentThread
+}
+
+// Threads return the comments of the bug as trees of replies, in order. A
+// comment replying to a comment missing from the snapshot, or coming after it,
+// starts a thread of its own.
+func (snap *Snapshot) Threads() []*CommentThread {
+	threads := make(map[entity.Id]*CommentThread, len(snap.Comments))
+	var roots []*CommentThread
+
+	for _, comment := range snap.Comments {
+		thread := &CommentThread{Comment: comment}
+
+		if parent, ok := threads[comment.ReplyTo]; ok && comment.ReplyTo != "" {
+			parent.Replies = append(parent.Replies, thread)
+		} else {
+			roots = append(roots, thread)
+		}
+
+		threads[comment.id] = thread
+	}
+
+	return roots
+}
+
+// ThreadedTimelineItem is an item of the timeline with its depth in the
+// thread of replies it belong to, 0 if it's not a reply
+type ThreadedTimelineItem struct {
+	TimelineItem
+	Depth int
+}
+
+// ThreadedTimeline return the timeline of the bug with the replies to a
+// comment moved right after the comment and the previous replies to it, as in
+// Threads.
+func (snap *Snapshot) ThreadedTimeline() []ThreadedTimelineItem {
+	replies := make(map[entity.Id][]TimelineItem)
+	// the items seen, to only thread the replies to a previous item
+	seen := make(map[entity.Id]bool, len(snap.Timeline))
+	isReply := make(map[entity.Id]bool)
+
+	for _, item := range snap.Timeline {
+		if comment, ok := item.(*AddCommentTimelineItem); ok && seen[comment.ReplyTo] {
+			replies[comment.ReplyTo] = append(replies[comment.ReplyTo], item)
+			isReply[item.Id()] = true
+		}
+		seen[item.Id()] = true
+	}
+
+	result := make([]ThreadedTimelineItem, 0, len(snap.Timeline))
+
+	var add func(item TimelineItem, depth int)
+	add = func(item TimelineItem, depth int) {
+		result = append(result, ThreadedTimelineItem{TimelineItem: item, Depth: depth})
+		for _, reply := range replies[item.Id()] {
+			add(reply, depth+1)
+		}
+	}
+
+	for _, item := range snap.Timeline {
+		if !isReply[item.Id()] {
+			add(item, 0)
+		}
+	}
+
+	return result
+}
+
+// ResolveCommentPrefix search for a comment whose id start with the prefix
+func (snap *Snapshot) ResolveCommentPrefix(prefix string) (*Comment, error) {
+	var matching []entity.Id
+	var found *Comment
+
+	for i, c := range snap.Comments {
+		if c.id.HasPrefix(prefix) {
+			matching = append(matching, c.id)
+			found = &snap.Comments[i]
+		}
+	}
+
+	switch len(matching) {
+	case 0:
+		return nil, fmt.Errorf("comment %s not found", prefix)
+	case 1:
+		return found, nil
+	default:
+		return nil, entity.NewErrMultipleMatch("comment", matching)
+	}
+}
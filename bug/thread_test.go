@@ -0,0 +1,68 @@
+package bug
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func TestThreads(t *testing.T) {
+	rene := identity.NewBare("René Descartes", "rene@descartes.fr")
+	unix := time.Now().Unix()
+
+	b, create, err := Create(rene, unix, "title", "message")
+	require.NoError(t, err)
+
+	first, err := AddComment(b, rene, unix+1, "first")
+	require.NoError(t, err)
+	reply, err := AddCommentReply(b, rene, unix+2, first.Id(), "reply", nil)
+	require.NoError(t, err)
+	_, _, err = ChangeLabels(b, rene, unix+3, []string{"bug"}, nil)
+	require.NoError(t, err)
+	_, err = AddCommentReply(b, rene, unix+4, create.Id(), "reply to the description", nil)
+	require.NoError(t, err)
+	_, err = AddCommentReply(b, rene, unix+5, reply.Id(), "nested", nil)
+	require.NoError(t, err)
+	_, err = AddComment(b, rene, unix+6, "last")
+	require.NoError(t, err)
+
+	// the replied comment has to exist
+	_, err = AddCommentReply(b, rene, unix+7, "a3fb7c1c1d2f6d9e4f3a0b1c2d3e4f5a6b7c8d9e", "orphan", nil)
+	require.Error(t, err)
+
+	snap := b.Compile()
+
+	threads := snap.Threads()
+	require.Len(t, threads, 3)
+	require.Equal(t, "message", threads[0].Comment.Message)
+	require.Len(t, threads[0].Replies, 1)
+	require.Equal(t, "reply to the description", threads[0].Replies[0].Comment.Message)
+	require.Equal(t, "first", threads[1].Comment.Message)
+	require.Len(t, threads[1].Replies, 1)
+	require.Equal(t, "nested", threads[1].Replies[0].Replies[0].Comment.Message)
+	require.Equal(t, "last", threads[2].Comment.Message)
+
+	var messages []string
+	var depths []int
+	for _, item := range snap.ThreadedTimeline() {
+		depths = append(depths, item.Depth)
+		switch item := item.TimelineItem.(type) {
+		case *CreateTimelineItem:
+			messages = append(messages, item.Message)
+		case *AddCommentTimelineItem:
+			messages = append(messages, item.Message)
+		default:
+			messages = append(messages, "other")
+		}
+	}
+	require.Equal(t, []string{"message", "reply to the description", "first", "reply", "nested", "other", "last"}, messages)
+	require.Equal(t, []int{0, 1, 0, 1, 2, 0, 0}, depths)
+
+	comment, err := snap.ResolveCommentPrefix(reply.Id().String()[:10])
+	require.NoError(t, err)
+	require.Equal(t, "reply", comment.Message)
+	require.Equal(t, first.Id(), comment.ReplyTo)
+}
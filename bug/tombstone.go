@@ -0,0 +1,427 @@
+package bug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+const tombstonesRefPattern = "refs/tombstones/bugs/"
+const tombstonesRemoteRefPattern = "refs/remotes/%s/tombstones/bugs/"
+
+const tombstoneEntryName = "tombstone"
+
+// the tree entry holding the signature of the tombstone, if signed
+const tombstoneSignatureEntryName = "signature"
+
+const tombstoneFormatVersion = 1
+
+// maintainersConfigKey is the repository configuration listing the ids of the
+// identities allowed to remove any bug, separated by commas or spaces.
+const maintainersConfigKey = "git-bug.maintainers"
+
+var ErrTombstoneNotExist = errors.New("tombstone doesn't exist")
+
+// Tombstone mark a bug as removed. It is stored in git next to the bugs and
+// propagate the same way, so that every repository merging it drop the bug.
+//
+// A tombstone is only merged from elsewhere if signed by its author, with a
+// key valid at the time of the removal, as the author id alone could be
+// forged by anyone able to push.
+type Tombstone struct {
+	// the removed bug
+	BugId entity.Id
+	// the identity that removed the bug
+	AuthorId entity.Id
+	// the edit lamport time of the removal, giving the keys of the author
+	// valid to sign it
+	EditTime lamport.Time
+	UnixTime int64
+	Reason   string
+
+	// the serialized tombstone and its signature, once stored
+	data      []byte
+	signature *identity.DataSignature
+}
+
+type tombstoneJSON struct {
+	Version  uint         `json:"version"`
+	BugId    entity.Id    `json:"bug_id"`
+	AuthorId entity.Id    `json:"author"`
+	EditTime lamport.Time `json:"edit_time"`
+	UnixTime int64        `json:"timestamp"`
+	Reason   string       `json:"reason,omitempty"`
+}
+
+func (t *Tombstone) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tombstoneJSON{
+		Version:  tombstoneFormatVersion,
+		BugId:    t.BugId,
+		AuthorId: t.AuthorId,
+		EditTime: t.EditTime,
+		UnixTime: t.UnixTime,
+		Reason:   t.Reason,
+	})
+}
+
+func (t *Tombstone) UnmarshalJSON(data []byte) error {
+	var aux tombstoneJSON
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Version != tombstoneFormatVersion {
+		return fmt.Errorf("unknown tombstone format version %v", aux.Version)
+	}
+
+	t.BugId = aux.BugId
+	t.AuthorId = aux.AuthorId
+	t.EditTime = aux.EditTime
+	t.UnixTime = aux.UnixTime
+	t.Reason = aux.Reason
+
+	return nil
+}
+
+// Time return the time when the bug has been removed
+func (t *Tombstone) Time() time.Time {
+	return time.Unix(t.UnixTime, 0)
+}
+
+// IsSigned tell if the tombstone is signed, and so can be merged by the other
+// repositories
+func (t *Tombstone) IsSigned() bool {
+	return t.signature != nil
+}
+
+// verify check that the tombstone is signed by its author, with a key valid at
+// the time of the removal
+func (t *Tombstone) verify(resolver identity.Resolver) error {
+	if t.signature == nil {
+		return fmt.Errorf("the tombstone is not signed")
+	}
+
+	author, err := resolver.ResolveIdentity(t.AuthorId)
+	if err != nil {
+		return errors.Wrap(err, "unknown author")
+	}
+
+	return t.signature.Verify(t.data, author.ValidKeysAtTime(t.EditTime))
+}
+
+// Validate check if the Tombstone data is valid
+func (t *Tombstone) Validate() error {
+	if err := t.BugId.Validate(); err != nil {
+		return errors.Wrap(err, "invalid bug id")
+	}
+
+	if err := t.AuthorId.Validate(); err != nil {
+		return errors.Wrap(err, "invalid author id")
+	}
+
+	if t.EditTime == 0 {
+		return fmt.Errorf("lamport time not set")
+	}
+
+	if t.UnixTime <= 0 {
+		return fmt.Errorf("time not set")
+	}
+
+	return nil
+}
+
+// Remove check the removal policy, then write a tombstone for the given bug and
+// remove the bug from the repository, including the remote-tracking
+// references.
+//
+// The tombstone is signed with the Signer configured in the repository, if
+// any. An unsigned tombstone only remove the bug locally, as the other
+// repositories refuse to merge it.
+func Remove(repo repository.ClockedRepo, b Interface, author identity.Interface, unixTime int64, reason string) (*Tombstone, error) {
+	err := CheckRemovalPolicy(repo, b.FirstOp().GetAuthor().Id(), author.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	// the removal is dated after the last version of the author, for the
+	// current keys of the author to be the valid ones
+	err = repo.WitnessEdit(author.LastModificationLamport())
+	if err != nil {
+		return nil, err
+	}
+
+	editTime, err := repo.EditTimeIncrement()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tombstone{
+		BugId:    b.Id(),
+		AuthorId: author.Id(),
+		EditTime: editTime,
+		UnixTime: unixTime,
+		Reason:   reason,
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	t.data, err = json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	t.signature, err = identity.SignData(repo, t.data)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't sign the tombstone")
+	}
+	if t.signature != nil && !identity.HasKey(author.ValidKeysAtTime(editTime), t.signature.Key.Fingerprint) {
+		return nil, fmt.Errorf("the key of the signer is not a key of %s", author.DisplayName())
+	}
+
+	blobHash, err := repo.StoreData(t.data)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := []repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blobHash, Name: tombstoneEntryName},
+	}
+
+	if t.signature != nil {
+		signature, err := json.Marshal(t.signature)
+		if err != nil {
+			return nil, err
+		}
+
+		signatureHash, err := repo.StoreData(signature)
+		if err != nil {
+			return nil, err
+		}
+
+		tree = append(tree, repository.TreeEntry{
+			ObjectType: repository.Blob, Hash: signatureHash, Name: tombstoneSignatureEntryName,
+		})
+	}
+
+	treeHash, err := repo.StoreTree(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	commitHash, err := repo.StoreCommit(treeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.UpdateRef(tombstonesRefPattern+t.BugId.String(), commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	err = removeBugRefs(repo, t.BugId)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// removeBugRefs remove the local and remote-tracking references of a bug
+func removeBugRefs(repo repository.Repo, id entity.Id) error {
+	refs, err := repo.ListRefs("refs/")
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		if !isBugRef(ref, id) {
+			continue
+		}
+
+		err = repo.RemoveRef(ref)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isBugRef tell if a reference is the local or a remote-tracking reference of
+// the given bug
+func isBugRef(ref string, id entity.Id) bool {
+	if ref == bugsRefPattern+id.String() {
+		return true
+	}
+
+	// refs/remotes/<remote>/bugs/<id>, and the same for the bugs held for
+	// review, or rejected
+	return (strings.HasPrefix(ref, "refs/remotes/") ||
+		strings.HasPrefix(ref, pendingRefPrefix) ||
+		strings.HasPrefix(ref, rejectedRefPrefix)) &&
+		strings.HasSuffix(ref, "/bugs/"+id.String()) &&
+		!strings.HasSuffix(ref, "/tombstones/bugs/"+id.String())
+}
+
+// CheckRemovalPolicy return an error if the remover is not allowed to remove a
+// bug created by the given author. A bug can be removed by its author, or by
+// one of the maintainers configured in the repository. The bug author can be
+// unset if the bug is not known, in which case only the maintainers can remove
+// it.
+func CheckRemovalPolicy(repo repository.RepoConfig, bugAuthor entity.Id, remover entity.Id) error {
+	if bugAuthor != "" && bugAuthor == remover {
+		return nil
+	}
+
+	maintainers, err := ReadMaintainers(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, maintainer := range maintainers {
+		if maintainer == remover {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is neither the author of the bug nor a maintainer", remover.Human())
+}
+
+// ReadMaintainers return the identities allowed to remove any bug
+func ReadMaintainers(repo repository.RepoConfig) ([]entity.Id, error) {
+	raw, err := repo.LocalConfig().ReadString(maintainersConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	split := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	result := make([]entity.Id, len(split))
+	for i, s := range split {
+		id := entity.Id(s)
+		if err := id.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid maintainer in %s", maintainersConfigKey)
+		}
+		result[i] = id
+	}
+
+	return result, nil
+}
+
+// ReadLocalTombstone will read the local tombstone of a bug
+func ReadLocalTombstone(repo repository.Repo, id entity.Id) (*Tombstone, error) {
+	return readTombstone(repo, tombstonesRefPattern+id.String())
+}
+
+// ReadRemoteTombstone will read a remote tombstone of a bug, as of the last fetch
+func ReadRemoteTombstone(repo repository.Repo, remote string, id entity.Id) (*Tombstone, error) {
+	return readTombstone(repo, fmt.Sprintf(tombstonesRemoteRefPattern, remote)+id.String())
+}
+
+func readTombstone(repo repository.Repo, ref string) (*Tombstone, error) {
+	refSplit := strings.Split(ref, "/")
+	id := entity.Id(refSplit[len(refSplit)-1])
+
+	if err := id.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid ref")
+	}
+
+	return readTombstoneRev(repo, id, ref)
+}
+
+// readTombstoneRev read the tombstone of a bug at the given git revision
+func readTombstoneRev(repo repository.Repo, id entity.Id, rev string) (*Tombstone, error) {
+	hashes, err := repo.ListCommits(rev)
+	if err != nil || len(hashes) == 0 {
+		return nil, ErrTombstoneNotExist
+	}
+
+	treeHash, err := repo.GetTreeHash(hashes[len(hashes)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := repo.ListEntries(treeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var t *Tombstone
+	var signature *identity.DataSignature
+
+	for _, entry := range entries {
+		switch entry.Name {
+		case tombstoneEntryName:
+			data, err := repo.ReadData(entry.Hash)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read git blob data")
+			}
+
+			t = &Tombstone{}
+			err = json.Unmarshal(data, t)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decode tombstone")
+			}
+			t.data = data
+
+		case tombstoneSignatureEntryName:
+			data, err := repo.ReadData(entry.Hash)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read git blob data")
+			}
+
+			signature = &identity.DataSignature{}
+			err = json.Unmarshal(data, signature)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decode the tombstone signature")
+			}
+		}
+	}
+
+	if t == nil {
+		return nil, fmt.Errorf("no tombstone entry in the git tree")
+	}
+
+	if t.BugId != id {
+		return nil, fmt.Errorf("tombstone for %s stored under %s", t.BugId.Human(), id.Human())
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid tombstone")
+	}
+
+	t.signature = signature
+
+	return t, nil
+}
+
+// IsRemoved tell if a bug has a local tombstone
+func IsRemoved(repo repository.Repo, id entity.Id) (bool, error) {
+	return repo.RefExist(tombstonesRefPattern + id.String())
+}
+
+// ListLocalTombstones list the ids of all the locally removed bugs
+func ListLocalTombstones(repo repository.Repo) ([]entity.Id, error) {
+	refs, err := repo.ListRefs(tombstonesRefPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return refsToIds(refs), nil
+}
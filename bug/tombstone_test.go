@@ -0,0 +1,169 @@
+package bug
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestRemovePropagation(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	// the tombstones are signed with a key of their author
+	signerA, err := identity.NewTestSigner()
+	require.NoError(t, err)
+	require.NoError(t, identity.SetSigner(repoA, signerA, false))
+	keyA, err := signerA.Key()
+	require.NoError(t, err)
+
+	signerB, err := identity.NewTestSigner()
+	require.NoError(t, err)
+	require.NoError(t, identity.SetSigner(repoB, signerB, false))
+	keyB, err := signerB.Key()
+	require.NoError(t, err)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	rene.Mutate(func(m identity.Mutator) identity.Mutator {
+		m.Keys = append(m.Keys, keyA)
+		return m
+	})
+	require.NoError(t, rene.Commit(repoA))
+
+	isaac := identity.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	isaac.Mutate(func(m identity.Mutator) identity.Mutator {
+		m.Keys = append(m.Keys, keyB)
+		return m
+	})
+	require.NoError(t, isaac.Commit(repoB))
+
+	bug1, _, err := Create(rene, time.Now().Unix(), "bug1", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit(repoA))
+
+	bug2, _, err := Create(rene, time.Now().Unix(), "bug2", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug2.Commit(repoA))
+
+	bug3, _, err := Create(rene, time.Now().Unix(), "bug3", "message")
+	require.NoError(t, err)
+	require.NoError(t, bug3.Commit(repoA))
+
+	_, err = identity.Push(repoA, "origin")
+	require.NoError(t, err)
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	require.NoError(t, identity.Pull(repoB, "origin"))
+	require.NoError(t, Pull(repoB, "origin"))
+	require.Len(t, allBugs(t, ReadAllLocalBugs(repoB)), 3)
+
+	// the author can remove its own bug
+	tombstone, err := Remove(repoA, bug1, rene, time.Now().Unix(), "spam")
+	require.NoError(t, err)
+	require.Equal(t, bug1.Id(), tombstone.BugId)
+	require.Equal(t, rene.Id(), tombstone.AuthorId)
+	require.True(t, tombstone.IsSigned())
+
+	removed, err := IsRemoved(repoA, bug1.Id())
+	require.NoError(t, err)
+	require.True(t, removed)
+
+	_, err = ReadLocalBug(repoA, bug1.Id())
+	require.Equal(t, ErrBugNotExist, err)
+
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	require.NoError(t, Pull(repoB, "origin"))
+
+	_, err = ReadLocalBug(repoB, bug1.Id())
+	require.Equal(t, ErrBugNotExist, err)
+
+	read, err := ReadLocalTombstone(repoB, bug1.Id())
+	require.NoError(t, err)
+	require.Equal(t, "spam", read.Reason)
+	require.True(t, read.IsSigned())
+
+	// the bug still exist on the remote, but is not merged back
+	require.NoError(t, Pull(repoB, "origin"))
+	require.Len(t, allBugs(t, ReadAllLocalBugs(repoB)), 2)
+
+	// someone else can't remove the bug without being a maintainer
+	_, err = Remove(repoB, bug2, isaac, time.Now().Unix(), "")
+	require.Error(t, err)
+
+	require.NoError(t, repoB.LocalConfig().StoreString(maintainersConfigKey, isaac.Id().String()))
+
+	_, err = Remove(repoB, bug2, isaac, time.Now().Unix(), "")
+	require.NoError(t, err)
+
+	_, err = identity.Push(repoB, "origin")
+	require.NoError(t, err)
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+	require.NoError(t, identity.Pull(repoA, "origin"))
+
+	// A doesn't trust isaac as a maintainer, the tombstone is rejected
+	err = Pull(repoA, "origin")
+	require.Error(t, err)
+
+	_, err = ReadLocalBug(repoA, bug2.Id())
+	require.NoError(t, err)
+
+	require.NoError(t, repoA.LocalConfig().StoreString(maintainersConfigKey, rene.Id().String()+", "+isaac.Id().String()))
+
+	require.NoError(t, Pull(repoA, "origin"))
+
+	_, err = ReadLocalBug(repoA, bug2.Id())
+	require.Equal(t, ErrBugNotExist, err)
+	require.Len(t, allBugs(t, ReadAllLocalBugs(repoA)), 1)
+
+	// the signer must hold a key of the author
+	_, err = Remove(repoB, bug3, rene, time.Now().Unix(), "")
+	require.Error(t, err)
+
+	// a tombstone claiming to be from the author of the bug, but unsigned, is
+	// rejected
+	require.NoError(t, repoB.LocalConfig().RemoveAll("git-bug.signer"))
+	tombstone, err = Remove(repoB, bug3, rene, time.Now().Unix(), "")
+	require.NoError(t, err)
+	require.False(t, tombstone.IsSigned())
+
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+
+	err = Pull(repoA, "origin")
+	require.Error(t, err)
+
+	_, err = ReadLocalBug(repoA, bug3.Id())
+	require.NoError(t, err)
+}
+
+func TestReadMaintainers(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	maintainers, err := ReadMaintainers(repo)
+	require.NoError(t, err)
+	require.Empty(t, maintainers)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repo))
+
+	require.NoError(t, repo.LocalConfig().StoreString(maintainersConfigKey, "invalid"))
+	_, err = ReadMaintainers(repo)
+	require.Error(t, err)
+
+	require.NoError(t, repo.LocalConfig().StoreString(maintainersConfigKey, rene.Id().String()))
+	maintainers, err = ReadMaintainers(repo)
+	require.NoError(t, err)
+	require.Len(t, maintainers, 1)
+	require.Equal(t, rene.Id(), maintainers[0])
+
+	require.NoError(t, CheckRemovalPolicy(repo, "", rene.Id()))
+	require.NoError(t, CheckRemovalPolicy(repo, rene.Id(), rene.Id()))
+}
@@ -8,17 +8,25 @@ var _ Interface = &WithSnapshot{}
 type WithSnapshot struct {
 	*Bug
 	snap *Snapshot
+
+	// Filter, if set, exclude operations from the snapshot
+	Filter OperationFilter
 }
 
 // Snapshot return the current snapshot
 func (b *WithSnapshot) Snapshot() *Snapshot {
 	if b.snap == nil {
-		snap := b.Bug.Compile()
+		snap := b.Bug.CompileFiltered(b.Filter)
 		b.snap = &snap
 	}
 	return b.snap
 }
 
+// ResetSnapshot drop the current snapshot, to be compiled again when needed
+func (b *WithSnapshot) ResetSnapshot() {
+	b.snap = nil
+}
+
 // Append intercept Bug.Append() to update the snapshot efficiently
 func (b *WithSnapshot) Append(op Operation) {
 	b.Bug.Append(op)
@@ -27,6 +35,10 @@ func (b *WithSnapshot) Append(op Operation) {
 		return
 	}
 
+	if b.Filter != nil && !b.Filter(op) {
+		return
+	}
+
 	op.Apply(b.snap)
 	b.snap.Operations = append(b.snap.Operations, op)
 }
@@ -51,8 +63,37 @@ func (b *WithSnapshot) Commit(repo repository.ClockedRepo) error {
 	return nil
 }
 
+// commitStaging intercept Bug.commitStaging() to update the snapshot efficiently
+func (b *WithSnapshot) commitStaging(repo repository.ClockedRepo) error {
+	err := b.Bug.commitStaging(repo)
+
+	if err != nil {
+		b.snap = nil
+		return err
+	}
+
+	if b.snap == nil {
+		return nil
+	}
+
+	b.snap.id = b.Bug.id
+	return nil
+}
+
+// DiscardStaging intercept Bug.DiscardStaging() and clear the snapshot
+func (b *WithSnapshot) DiscardStaging(n int) {
+	b.snap = nil
+	b.Bug.DiscardStaging(n)
+}
+
 // Merge intercept Bug.Merge() and clear the snapshot
 func (b *WithSnapshot) Merge(repo repository.Repo, other Interface) (bool, error) {
 	b.snap = nil
 	return b.Bug.Merge(repo, other)
 }
+
+// Rewrite intercept Bug.Rewrite() and clear the snapshot
+func (b *WithSnapshot) Rewrite(repo repository.ClockedRepo, remotes []string, steps []RewriteStep) error {
+	b.snap = nil
+	return b.Bug.Rewrite(repo, remotes, steps)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// ExportBugBundle create a portable bundle of the commits of a bug which are
+// not on the given remote yet, or of the whole bug without remote
+func (c *RepoCache) ExportBugBundle(id entity.Id, remote string) (*bug.Bundle, error) {
+	return bug.ExportBundle(c.repo, id, remote)
+}
+
+// ApplyBugBundle import the identities of a bundle then merge its bug, as if
+// fetched from a remote, and update the excerpts accordingly
+func (c *RepoCache) ApplyBugBundle(bundle *bug.Bundle) ([]entity.MergeResult, error) {
+	return c.applyBugBundle(bundle, c.MergeAll)
+}
+
+// HoldBugBundle import the identities of a bundle then hold its bug for
+// review, as if fetched from an untrusted remote
+func (c *RepoCache) HoldBugBundle(bundle *bug.Bundle) ([]entity.MergeResult, error) {
+	return c.applyBugBundle(bundle, func(remote string) <-chan entity.MergeResult {
+		return bug.HoldAll(c.repo, remote)
+	})
+}
+
+func (c *RepoCache) applyBugBundle(bundle *bug.Bundle, merge func(remote string) <-chan entity.MergeResult) ([]entity.MergeResult, error) {
+	var results []entity.MergeResult
+
+	if bundle.Identities != nil {
+		identityResults, err := c.ImportIdentities(bundle.Identities)
+		results = append(results, identityResults...)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	err := bug.StoreBundle(c.repo, bundle)
+	if err != nil {
+		return results, err
+	}
+
+	for result := range merge(bug.BundleRemote) {
+		if result.Err != nil {
+			err = result.Err
+			continue
+		}
+		results = append(results, result)
+	}
+
+	// the bug is never kept under the pseudo-remote, even if invalid
+	removeErr := bug.RemoveBundleRef(c.repo, bundle.Id)
+	if err != nil {
+		return results, err
+	}
+
+	return results, removeErr
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/util/git"
 )
 
@@ -18,12 +19,19 @@ var ErrNoMatchingOp = fmt.Errorf("no matching operation found")
 type BugCache struct {
 	repoCache *RepoCache
 	bug       *bug.WithSnapshot
+
+	// set during a Batch, to update the cache only once at the end
+	batching bool
+
+	// the number of staged operations already committed within the open
+	// transaction, and waiting to be written in git
+	deferred int
 }
 
 func NewBugCache(repoCache *RepoCache, b *bug.Bug) *BugCache {
 	return &BugCache{
 		repoCache: repoCache,
-		bug:       &bug.WithSnapshot{Bug: b},
+		bug:       &bug.WithSnapshot{Bug: b, Filter: repoCache.operationFilter()},
 	}
 }
 
@@ -31,14 +39,75 @@ func (c *BugCache) Snapshot() *bug.Snapshot {
 	return c.bug.Snapshot()
 }
 
+// SnapshotAt compile the bug as it was with only the committed operations
+// accepted by the filter, the first operation being always applied
+func (c *BugCache) SnapshotAt(filter func(op bug.TimedOperation) bool) *bug.Snapshot {
+	kept := make(map[entity.Id]bool)
+	for _, op := range c.bug.CommittedOperations() {
+		if filter(op) {
+			kept[op.Id()] = true
+		}
+	}
+
+	moderation := c.repoCache.operationFilter()
+
+	snap := c.bug.CompileFiltered(func(op bug.Operation) bool {
+		return kept[op.Id()] && moderation(op)
+	})
+	return &snap
+}
+
+// CommittedOperations return the committed operations of the bug, in order,
+// with the edit lamport time of their commit
+func (c *BugCache) CommittedOperations() []bug.TimedOperation {
+	return c.bug.CommittedOperations()
+}
+
+// HasUnknownOperations tell if the bug hold operations this version of git-bug
+// can't read, in which case it can't be edited
+func (c *BugCache) HasUnknownOperations() bool {
+	return c.bug.HasUnknownOperations()
+}
+
+// Audit return the chain of commits of the bug, with their hashes and
+// signatures
+func (c *BugCache) Audit() ([]bug.AuditCommit, error) {
+	return c.bug.Audit(c.repoCache.repo)
+}
+
 func (c *BugCache) Id() entity.Id {
 	return c.bug.Id()
 }
 
 func (c *BugCache) notifyUpdated() error {
+	if c.batching {
+		return nil
+	}
 	return c.repoCache.bugUpdated(c.bug.Id())
 }
 
+// Batch apply several edits on the bug at once: the cache is updated only
+// when all of them are done, and if one fail, the operations added by the
+// previous ones are discarded. The operations still need to be committed.
+func (c *BugCache) Batch(edits func() error) error {
+	size := c.bug.StagingSize()
+
+	c.batching = true
+	err := edits()
+	c.batching = false
+
+	if err != nil {
+		// the operations committed within a transaction are kept
+		if size < c.deferred {
+			size = c.deferred
+		}
+		c.bug.DiscardStaging(size)
+		return err
+	}
+
+	return c.notifyUpdated()
+}
+
 // ResolveOperationWithMetadata will find an operation that has the matching metadata
 func (c *BugCache) ResolveOperationWithMetadata(key string, value string) (entity.Id, error) {
 	// preallocate but empty
@@ -86,6 +155,36 @@ func (c *BugCache) AddCommentRaw(author *IdentityCache, unixTime int64, message
 	for key, value := range metadata {
 		op.SetMetadata(key, value)
 	}
+	if len(metadata) > 0 {
+		// the metadata change the id of the comment already in the snapshot
+		c.bug.ResetSnapshot()
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// AddCommentReply add a comment replying to another comment of the bug
+func (c *BugCache) AddCommentReply(replyTo entity.Id, message string, files []git.Hash) (*bug.AddCommentOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.AddCommentReplyRaw(author, time.Now().Unix(), replyTo, message, files, nil)
+}
+
+func (c *BugCache) AddCommentReplyRaw(author *IdentityCache, unixTime int64, replyTo entity.Id, message string, files []git.Hash, metadata map[string]string) (*bug.AddCommentOperation, error) {
+	op, err := bug.AddCommentReply(c.bug, author.Identity, unixTime, replyTo, message, files)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+	if len(metadata) > 0 {
+		c.bug.ResetSnapshot()
+	}
 
 	return op, c.notifyUpdated()
 }
@@ -176,7 +275,21 @@ func (c *BugCache) Close() (*bug.SetStatusOperation, error) {
 }
 
 func (c *BugCache) CloseRaw(author *IdentityCache, unixTime int64, metadata map[string]string) (*bug.SetStatusOperation, error) {
-	op, err := bug.Close(c.bug, author.Identity, unixTime)
+	return c.CloseWithResolutionRaw(author, unixTime, bug.NoResolution, metadata)
+}
+
+// CloseWithResolution close the bug, telling why with a resolution
+func (c *BugCache) CloseWithResolution(resolution bug.Resolution) (*bug.SetStatusOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CloseWithResolutionRaw(author, time.Now().Unix(), resolution, nil)
+}
+
+func (c *BugCache) CloseWithResolutionRaw(author *IdentityCache, unixTime int64, resolution bug.Resolution, metadata map[string]string) (*bug.SetStatusOperation, error) {
+	op, err := bug.CloseWithResolution(c.bug, author.Identity, unixTime, resolution)
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +323,308 @@ func (c *BugCache) SetTitleRaw(author *IdentityCache, unixTime int64, title stri
 	return op, c.notifyUpdated()
 }
 
+// SetComponent change the component of the bug, after validating it against
+// the configured components. The default assignees of the component are
+// assigned if the bug has none yet. An empty component unset it.
+func (c *BugCache) SetComponent(name string) (*bug.SetComponentOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	var component *Component
+	if name != "" {
+		component, err = c.repoCache.ResolveComponent(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	unixTime := time.Now().Unix()
+
+	op, err := c.SetComponentRaw(author, unixTime, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if component == nil || len(component.Assignees) == 0 || len(c.Snapshot().Assignees) > 0 {
+		return op, nil
+	}
+
+	assignees := make([]*IdentityCache, len(component.Assignees))
+	for i, prefix := range component.Assignees {
+		assignees[i], err = c.repoCache.ResolveIdentityPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = c.ChangeAssigneesRaw(author, unixTime, assignees, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+func (c *BugCache) SetComponentRaw(author *IdentityCache, unixTime int64, component string, metadata map[string]string) (*bug.SetComponentOperation, error) {
+	op, err := bug.SetComponent(c.bug, author.Identity, unixTime, component)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+func (c *BugCache) ChangeAssignees(added []*IdentityCache, removed []*IdentityCache) (*bug.AssigneeChangeOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ChangeAssigneesRaw(author, time.Now().Unix(), added, removed, nil)
+}
+
+func (c *BugCache) ChangeAssigneesRaw(author *IdentityCache, unixTime int64, added []*IdentityCache, removed []*IdentityCache, metadata map[string]string) (*bug.AssigneeChangeOperation, error) {
+	op, err := bug.ChangeAssignees(c.bug, author.Identity, unixTime, identities(added), identities(removed))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// MarkDuplicate mark the bug as a duplicate of a canonical bug, and close it.
+// The canonical bug can't be itself a duplicate.
+func (c *BugCache) MarkDuplicate(canonical *BugCache) (*bug.MarkDuplicateOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	if target := canonical.Snapshot().DuplicateOf; target != "" {
+		return nil, fmt.Errorf("bug %s is itself a duplicate of %s", canonical.Id().Human(), target.Human())
+	}
+
+	return c.MarkDuplicateRaw(author, time.Now().Unix(), canonical.Id(), nil)
+}
+
+func (c *BugCache) MarkDuplicateRaw(author *IdentityCache, unixTime int64, target entity.Id, metadata map[string]string) (*bug.MarkDuplicateOperation, error) {
+	if target == c.Id() {
+		return nil, fmt.Errorf("a bug can't be a duplicate of itself")
+	}
+
+	op, err := bug.MarkDuplicate(c.bug, author.Identity, unixTime, target)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// SetParent make the bug a sub-task of a parent bug. The parent can't be one
+// of the sub-tasks of the bug, directly or not.
+func (c *BugCache) SetParent(parent *BugCache) (*bug.SetParentOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.repoCache.isAncestor(c.Id(), parent.Id()) {
+		return nil, fmt.Errorf("bug %s is a sub-task of %s", parent.Id().Human(), c.Id().Human())
+	}
+
+	return c.SetParentRaw(author, time.Now().Unix(), parent.Id(), nil)
+}
+
+func (c *BugCache) SetParentRaw(author *IdentityCache, unixTime int64, parent entity.Id, metadata map[string]string) (*bug.SetParentOperation, error) {
+	op, err := bug.SetParent(c.bug, author.Identity, unixTime, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// RemoveParent make the bug a bug on its own, instead of a sub-task
+func (c *BugCache) RemoveParent() (*bug.RemoveParentOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.RemoveParentRaw(author, time.Now().Unix(), nil)
+}
+
+func (c *BugCache) RemoveParentRaw(author *IdentityCache, unixTime int64, metadata map[string]string) (*bug.RemoveParentOperation, error) {
+	op, err := bug.RemoveParent(c.bug, author.Identity, unixTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// RequestReview ask identities for their input on the bug, with an optional
+// message. The reviewers with a pending request already are ignored.
+func (c *BugCache) RequestReview(reviewers []*IdentityCache, message string) (*bug.RequestReviewOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.RequestReviewRaw(author, time.Now().Unix(), reviewers, message, nil)
+}
+
+func (c *BugCache) RequestReviewRaw(author *IdentityCache, unixTime int64, reviewers []*IdentityCache, message string, metadata map[string]string) (*bug.RequestReviewOperation, error) {
+	op, err := bug.RequestReview(c.bug, author.Identity, unixTime, identities(reviewers), message)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// AddCustomOperation add an operation of a type registered with
+// bug.RegisterOperationType to the bug
+func (c *BugCache) AddCustomOperation(tag string, payload bug.OperationPayload) (*bug.CustomOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.AddCustomOperationRaw(author, time.Now().Unix(), tag, payload, nil)
+}
+
+func (c *BugCache) AddCustomOperationRaw(author *IdentityCache, unixTime int64, tag string, payload bug.OperationPayload, metadata map[string]string) (*bug.CustomOperation, error) {
+	op, err := bug.AddCustomOperation(c.bug, author.Identity, unixTime, tag, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// Vote add the vote of the user identity to the bug
+func (c *BugCache) Vote() (*bug.VoteOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.VoteRaw(author, time.Now().Unix(), false, nil)
+}
+
+// RetractVote remove the vote of the user identity from the bug
+func (c *BugCache) RetractVote() (*bug.VoteOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.VoteRaw(author, time.Now().Unix(), true, nil)
+}
+
+func (c *BugCache) VoteRaw(author *IdentityCache, unixTime int64, retract bool, metadata map[string]string) (*bug.VoteOperation, error) {
+	op, err := bug.Vote(c.bug, author.Identity, unixTime, retract)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// AddChecklistItem add an unchecked item at the end of the checklist of the
+// bug
+func (c *BugCache) AddChecklistItem(text string) (*bug.AddChecklistItemOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.AddChecklistItemRaw(author, time.Now().Unix(), text, nil)
+}
+
+func (c *BugCache) AddChecklistItemRaw(author *IdentityCache, unixTime int64, text string, metadata map[string]string) (*bug.AddChecklistItemOperation, error) {
+	op, err := bug.AddChecklistItem(c.bug, author.Identity, unixTime, text)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+	if len(metadata) > 0 {
+		// the metadata change the id of the item already in the snapshot
+		c.bug.ResetSnapshot()
+	}
+
+	return op, c.notifyUpdated()
+}
+
+// CheckItem check or uncheck an item of the checklist of the bug
+func (c *BugCache) CheckItem(target entity.Id, checked bool) (*bug.CheckItemOperation, error) {
+	author, err := c.repoCache.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CheckItemRaw(author, time.Now().Unix(), target, checked, nil)
+}
+
+func (c *BugCache) CheckItemRaw(author *IdentityCache, unixTime int64, target entity.Id, checked bool, metadata map[string]string) (*bug.CheckItemOperation, error) {
+	op, err := bug.CheckItem(c.bug, author.Identity, unixTime, target, checked)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return op, c.notifyUpdated()
+}
+
+func identities(cached []*IdentityCache) []identity.Interface {
+	result := make([]identity.Interface, len(cached))
+	for i, c := range cached {
+		result[i] = c.Identity
+	}
+	return result
+}
+
 func (c *BugCache) EditCreateComment(body string) (*bug.EditCommentOperation, error) {
 	author, err := c.repoCache.GetUserIdentity()
 	if err != nil {
@@ -273,21 +688,120 @@ func (c *BugCache) SetMetadataRaw(author *IdentityCache, unixTime int64, target
 }
 
 func (c *BugCache) Commit() error {
-	err := c.bug.Commit(c.repoCache.repo)
+	return c.commit(false)
+}
+
+func (c *BugCache) CommitAsNeeded() error {
+	return c.commit(true)
+}
+
+// commit check and commit the staged operations. Within a transaction, the
+// commit is added to the batch of the transaction, and only written in git
+// when the transaction is committed.
+func (c *BugCache) commit(asNeeded bool) error {
+	// the operations deferred in the open transaction are already committed
+	if c.bug.StagingSize() == c.deferred {
+		if asNeeded {
+			return nil
+		}
+		return fmt.Errorf("can't commit a bug with no pending operation")
+	}
+
+	err := c.checkEditable()
+	if err != nil {
+		return err
+	}
+
+	err = c.checkPolicies()
+	if err != nil {
+		return err
+	}
+
+	deferred, err := c.repoCache.deferCommit(c)
+	if err != nil {
+		return err
+	}
+	if deferred {
+		return c.notifyUpdated()
+	}
+
+	err = c.repoCache.commitKeepRead(c.bug.Bug, func() error {
+		return c.bug.Commit(c.repoCache.repo)
+	})
+	// the staging area is empty, nothing is deferred anymore
+	c.deferred = 0
 	if err != nil {
 		return err
 	}
 	return c.notifyUpdated()
 }
 
-func (c *BugCache) CommitAsNeeded() error {
-	err := c.bug.CommitAsNeeded(c.repoCache.repo)
+// RewritableOperations return the committed operations of the bug that none
+// of the remotes has yet, and that can be rewritten
+func (c *BugCache) RewritableOperations() ([]bug.Operation, error) {
+	remotes, err := c.remoteNames()
+	if err != nil {
+		return nil, err
+	}
+	return c.bug.RewritableOperations(c.repoCache.repo, remotes)
+}
+
+// Rewrite edit, squash or drop the operations of the bug that none of the
+// remotes has yet, as bug.Bug.Rewrite
+func (c *BugCache) Rewrite(steps []bug.RewriteStep) error {
+	remotes, err := c.remoteNames()
+	if err != nil {
+		return err
+	}
+
+	err = c.bug.Rewrite(c.repoCache.repo, remotes, steps)
 	if err != nil {
 		return err
 	}
 	return c.notifyUpdated()
 }
 
+func (c *BugCache) remoteNames() ([]string, error) {
+	remotes, err := c.repoCache.GetRemotes()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// checkEditable refuse the pending operations on a bug holding operations of a
+// newer version of git-bug. The operations are dropped.
+func (c *BugCache) checkEditable() error {
+	if !c.bug.HasUnknownOperations() {
+		return nil
+	}
+
+	c.bug.DiscardStaging(c.deferred)
+	if err := c.notifyUpdated(); err != nil {
+		return err
+	}
+	return bug.ErrNewerFormat
+}
+
+// checkPolicies check the pending operations against the policies of the
+// repository. The operations are dropped if they are refused.
+func (c *BugCache) checkPolicies() error {
+	// the operations deferred in the open transaction are already accepted
+	err := c.repoCache.checkPolicies(c.bug.Bug, c.bug.StagedOperations()[c.deferred:])
+	if _, ok := err.(ErrPolicy); ok {
+		c.bug.DiscardStaging(c.deferred)
+		if err := c.notifyUpdated(); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
 func (c *BugCache) NeedCommit() bool {
 	return c.bug.NeedCommit()
 }
@@ -26,19 +26,44 @@ type BugExcerpt struct {
 	EditUnixTime      int64
 
 	Status       bug.Status
+	Resolution   bug.Resolution
 	Labels       []bug.Label
+	Component    string
+	Assignees    []entity.Id
 	Title        string
 	LenComments  int
 	Actors       []entity.Id
 	Participants []entity.Id
+	DuplicateOf  entity.Id
+	ReopenCount  int
+	Parent       entity.Id
+
+	// the number of identities that voted for the bug
+	Votes int
+
+	// the identities whose review has been requested, and who didn't answer
+	// yet
+	PendingReviewers []entity.Id
+
+	// the progress of the checklist of the bug
+	ChecklistChecked int
+	ChecklistTotal   int
+
+	// the roll-up of the sub-tasks of the bug, maintained by the cache
+	Children       int
+	ChildrenClosed int
 
 	// If author is identity.Bare, LegacyAuthor is set
 	// If author is identity.Identity, AuthorId is set and data is deported
 	// in a IdentityExcerpt
 	LegacyAuthor LegacyAuthorExcerpt
 	AuthorId     entity.Id
+	Anonymous    bool
 
 	CreateMetadata map[string]string
+
+	// the values of the registered ExcerptField, by name
+	Fields map[string]string
 }
 
 // identity.Bare data are directly embedded in the bug excerpt
@@ -75,6 +100,22 @@ func NewBugExcerpt(b bug.Interface, snap *bug.Snapshot) *BugExcerpt {
 		}
 	}
 
+	assigneesIds := make([]entity.Id, 0, len(snap.Assignees))
+	for _, assignee := range snap.Assignees {
+		if _, ok := assignee.(*identity.Identity); ok {
+			assigneesIds = append(assigneesIds, assignee.Id())
+		}
+	}
+
+	reviewersIds := make([]entity.Id, 0, len(snap.ReviewRequests))
+	for _, reviewer := range snap.PendingReviewers() {
+		if _, ok := reviewer.(*identity.Identity); ok {
+			reviewersIds = append(reviewersIds, reviewer.Id())
+		}
+	}
+
+	checked, total := snap.ChecklistProgress()
+
 	e := &BugExcerpt{
 		Id:                b.Id(),
 		CreateLamportTime: b.CreateLamportTime(),
@@ -82,12 +123,24 @@ func NewBugExcerpt(b bug.Interface, snap *bug.Snapshot) *BugExcerpt {
 		CreateUnixTime:    b.FirstOp().GetUnixTime(),
 		EditUnixTime:      snap.LastEditUnix(),
 		Status:            snap.Status,
+		Resolution:        snap.Resolution,
 		Labels:            snap.Labels,
+		Component:         snap.Component,
+		Assignees:         assigneesIds,
 		Actors:            actorsIds,
 		Participants:      participantsIds,
+		DuplicateOf:       snap.DuplicateOf,
+		Parent:            snap.Parent,
+		Votes:             len(snap.Voters),
+		PendingReviewers:  reviewersIds,
+		ChecklistChecked:  checked,
+		ChecklistTotal:    total,
+		ReopenCount:       snap.ReopenCount(),
 		Title:             snap.Title,
 		LenComments:       len(snap.Comments),
 		CreateMetadata:    b.FirstOp().AllMetadata(),
+		Anonymous:         snap.Anonymous,
+		Fields:            computeExcerptFields(b, snap),
 	}
 
 	switch snap.Author.(type) {
@@ -178,3 +231,22 @@ func (b BugsByEditTime) Less(i, j int) bool {
 func (b BugsByEditTime) Swap(i, j int) {
 	b[i], b[j] = b[j], b[i]
 }
+
+type BugsByVotes []*BugExcerpt
+
+func (b BugsByVotes) Len() int {
+	return len(b)
+}
+
+func (b BugsByVotes) Less(i, j int) bool {
+	if b[i].Votes != b[j].Votes {
+		return b[i].Votes < b[j].Votes
+	}
+
+	// on a tie, the oldest bug has been waiting for longer
+	return BugsByCreationTime(b).Less(j, i)
+}
+
+func (b BugsByVotes) Swap(i, j int) {
+	b[i], b[j] = b[j], b[i]
+}
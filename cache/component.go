@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// The components of a repository are configured in git:
+//
+//	git-bug.components                   the valid components
+//	git-bug.component.<name>.paths       the paths belonging to a component
+//	git-bug.component.<name>.assignees   the identities to assign by default
+//
+// All of them are lists separated by commas or spaces.
+const (
+	componentsConfigKey   = "git-bug.components"
+	componentPathsKey     = "git-bug.component.%s.paths"
+	componentAssigneesKey = "git-bug.component.%s.assignees"
+)
+
+// a path mentioned in a text, like "webui/src/App.tsx" or "./cache/query.go"
+var pathRegexp = regexp.MustCompile(`(?:\./)?[\w.-]+(?:/[\w.-]+)+/?|[\w-]+\.\w+`)
+
+// Component is an area of a (mono-)repository that bugs can be attached to
+type Component struct {
+	Name string
+	// path prefixes belonging to the component
+	Paths []string
+	// prefixes of the ids of the identities to assign by default
+	Assignees []string
+}
+
+// ErrUnknownComponent is returned when setting a component not configured
+type ErrUnknownComponent struct {
+	Name string
+}
+
+func (e ErrUnknownComponent) Error() string {
+	return fmt.Sprintf("unknown component %s, valid components are configured with %s", e.Name, componentsConfigKey)
+}
+
+// Components return the configured components
+func (c *RepoCache) Components() ([]Component, error) {
+	names, err := readConfigList(c.repo.LocalConfig(), componentsConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Component, len(names))
+
+	for i, name := range names {
+		paths, err := readConfigList(c.repo.LocalConfig(), fmt.Sprintf(componentPathsKey, name))
+		if err != nil {
+			return nil, err
+		}
+
+		assignees, err := readConfigList(c.repo.LocalConfig(), fmt.Sprintf(componentAssigneesKey, name))
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = Component{
+			Name:      name,
+			Paths:     paths,
+			Assignees: assignees,
+		}
+	}
+
+	return result, nil
+}
+
+// ResolveComponent retrieve a configured component by name
+func (c *RepoCache) ResolveComponent(name string) (*Component, error) {
+	components, err := c.Components()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		if component.Name == name {
+			return &component, nil
+		}
+	}
+
+	return nil, ErrUnknownComponent{Name: name}
+}
+
+// SuggestComponent find the component matching best the paths mentioned in a
+// text, if any
+func (c *RepoCache) SuggestComponent(text string) (string, bool, error) {
+	components, err := c.Components()
+	if err != nil {
+		return "", false, err
+	}
+
+	mentioned := pathRegexp.FindAllString(text, -1)
+
+	scores := make(map[string]int)
+	best := ""
+
+	for _, path := range mentioned {
+		path = strings.TrimPrefix(path, "./")
+
+		for _, component := range components {
+			for _, prefix := range component.Paths {
+				prefix = strings.TrimPrefix(prefix, "./")
+				if prefix != "" && strings.HasPrefix(path, prefix) {
+					scores[component.Name] += len(prefix)
+				}
+			}
+		}
+	}
+
+	for _, component := range components {
+		if scores[component.Name] > scores[best] {
+			best = component.Name
+		}
+	}
+
+	return best, best != "", nil
+}
+
+func readConfigList(config repository.Config, key string) ([]string, error) {
+	raw, err := config.ReadString(key)
+	if err == repository.ErrNoConfigEntry {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}), nil
+}
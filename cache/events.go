@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// Event is an operation on a bug, as part of the stream of all the changes
+// of the repository
+type Event struct {
+	BugId entity.Id
+	// the edit lamport time of the commit holding the operation
+	EditTime  lamport.Time
+	Operation bug.Operation
+}
+
+// Events return the committed operations of all the bugs whose commit has
+// an edit lamport time greater than sinceEditTime, and that have been issued
+// after since if not zero. The events are ordered by edit lamport time, and
+// in the order of the bug for the operations of a same commit.
+//
+// The edit lamport time of the last event can be given back later to only
+// get the new events.
+func (c *RepoCache) Events(sinceEditTime lamport.Time, since time.Time) ([]Event, error) {
+	c.muBug.RLock()
+	var ids []entity.Id
+	for id, excerpt := range c.bugExcerpts {
+		// the excerpt hold the most recent edit time of the bug
+		if excerpt.EditLamportTime > sinceEditTime {
+			ids = append(ids, id)
+		}
+	}
+	c.muBug.RUnlock()
+
+	filter := c.operationFilter()
+
+	var events []Event
+
+	for _, id := range ids {
+		b, err := c.ResolveBug(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range b.bug.CommittedOperations() {
+			if op.EditTime <= sinceEditTime {
+				continue
+			}
+			if !since.IsZero() && op.Time().Before(since) {
+				continue
+			}
+			if !filter(op.Operation) {
+				continue
+			}
+			events = append(events, Event{
+				BugId:     id,
+				EditTime:  op.EditTime,
+				Operation: op.Operation,
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].EditTime != events[j].EditTime {
+			return events[i].EditTime < events[j].EditTime
+		}
+		return events[i].BugId < events[j].BugId
+	})
+
+	return events, nil
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// ExcerptField is a value derived from the bugs and kept in their excerpt,
+// to filter and sort the bugs with it without compiling them again. Once
+// registered, a field named "due" is queried with "due:VALUE", "no:due" and
+// "sort:due", "sort:due-desc".
+//
+// The excerpts are computed again when the registered fields change, so a new
+// field doesn't need a new cache format version.
+type ExcerptField struct {
+	// the name of the field, used as the qualifier in the queries
+	Name string
+
+	// the version of the computation, to increase when Compute change so the
+	// excerpts are computed again
+	Version int
+
+	// Compute derive the value of the field from a bug. An empty value means
+	// the bug doesn't have the field.
+	Compute func(b bug.Interface, snap *bug.Snapshot) string
+
+	// Match tell if a value match a query. If not set, the value has to be
+	// equal to the query, ignoring the case.
+	Match func(value string, query string) bool
+
+	// Less order two values when sorting. If not set, the values are compared
+	// as strings. The bugs without the field are sorted as the empty value.
+	Less func(a string, b string) bool
+}
+
+// the names already used by the query language
+var reservedFieldNames = map[string]bool{
+	"status": true, "state": true, "resolution": true, "author": true,
+	"actor": true, "participant": true, "label": true, "component": true,
+	"assignee": true, "review": true, "duplicate": true, "parent": true,
+	"reopened": true, "title": true, "is": true, "no": true, "sort": true,
+	"id": true, "creation": true, "edit": true, "votes": true,
+}
+
+var excerptFields = make(map[string]*ExcerptField)
+
+// RegisterExcerptField add a field to the excerpts of the bugs. It has to be
+// called before opening any cache, typically from an init function. It panics
+// if the name is already used.
+func RegisterExcerptField(field ExcerptField) {
+	if field.Name == "" || strings.ContainsAny(field.Name, ": \t\"") {
+		panic(fmt.Sprintf("invalid excerpt field name %q", field.Name))
+	}
+	if reservedFieldNames[field.Name] {
+		panic(fmt.Sprintf("excerpt field %s conflict with the query language", field.Name))
+	}
+	if _, ok := excerptFields[field.Name]; ok {
+		panic(fmt.Sprintf("excerpt field %s already registered", field.Name))
+	}
+	if field.Compute == nil {
+		panic(fmt.Sprintf("excerpt field %s has no Compute function", field.Name))
+	}
+
+	excerptFields[field.Name] = &field
+}
+
+// computeExcerptFields return the values of the registered fields for a bug,
+// or nil if none is registered
+func computeExcerptFields(b bug.Interface, snap *bug.Snapshot) map[string]string {
+	if len(excerptFields) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(excerptFields))
+	for name, field := range excerptFields {
+		if value := field.Compute(b, snap); value != "" {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// excerptFieldsSignature describe the registered fields and their version, to
+// tell if the excerpts of the cache file have been computed with them
+func excerptFieldsSignature() string {
+	signatures := make([]string, 0, len(excerptFields))
+	for name, field := range excerptFields {
+		signatures = append(signatures, fmt.Sprintf("%s@%d", name, field.Version))
+	}
+	sort.Strings(signatures)
+	return strings.Join(signatures, ",")
+}
+
+func (f *ExcerptField) match(value string, query string) bool {
+	if f.Match != nil {
+		return f.Match(value, query)
+	}
+	return strings.EqualFold(value, query)
+}
+
+func (f *ExcerptField) less(a string, b string) bool {
+	if f.Less != nil {
+		return f.Less(a, b)
+	}
+	return a < b
+}
+
+// ExcerptFieldFilter return a Filter that match the bugs whose value of a
+// registered field match the query
+func ExcerptFieldFilter(field *ExcerptField, query string) Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		value, ok := excerpt.Fields[field.Name]
+		return ok && field.match(value, query)
+	}
+}
+
+// NoExcerptFieldFilter return a Filter that match the bugs without a value
+// for a registered field
+func NoExcerptFieldFilter(field *ExcerptField) Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		_, ok := excerpt.Fields[field.Name]
+		return !ok
+	}
+}
+
+type BugsByField struct {
+	excerpts []*BugExcerpt
+	field    *ExcerptField
+}
+
+func (b BugsByField) Len() int {
+	return len(b.excerpts)
+}
+
+func (b BugsByField) Less(i, j int) bool {
+	if c := b.field.compare(b.excerpts[i], b.excerpts[j]); c != 0 {
+		return c < 0
+	}
+	return BugsByCreationTime(b.excerpts).Less(i, j)
+}
+
+func (b BugsByField) Swap(i, j int) {
+	b.excerpts[i], b.excerpts[j] = b.excerpts[j], b.excerpts[i]
+}
+
+// compare order two bugs by their value of the field
+func (f *ExcerptField) compare(a, b *BugExcerpt) int {
+	valueA, valueB := a.Fields[f.Name], b.Fields[f.Name]
+	switch {
+	case f.less(valueA, valueB):
+		return -1
+	case f.less(valueB, valueA):
+		return 1
+	default:
+		return 0
+	}
+}
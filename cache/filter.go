@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/MichaelMure/git-bug/bug"
@@ -11,6 +13,33 @@ import (
 // This exist mainly to go through the functions of the cache with proper locking.
 type resolver interface {
 	ResolveIdentityExcerpt(id entity.Id) (*IdentityExcerpt, error)
+	GetUserIdentityExcerpt() (*IdentityExcerpt, error)
+	IsUnread(excerpt *BugExcerpt) bool
+}
+
+// meQuery is the query of the author, actor, participant and assignee filters
+// designating the user identity
+const meQuery = "me"
+
+// isMe tell if the search of an identity is the search of the user identity
+func isMe(query string) bool {
+	return strings.ToLower(query) == meQuery
+}
+
+// containsMe tell if a set of identities contains the user identity. Without
+// user identity, nobody is matched.
+func containsMe(ids []entity.Id, resolver resolver) bool {
+	me, err := resolver.GetUserIdentityExcerpt()
+	if err != nil {
+		return false
+	}
+
+	for _, id := range ids {
+		if id == me.Id {
+			return true
+		}
+	}
+	return false
 }
 
 // Filter is a predicate that match a subset of bugs
@@ -28,8 +57,27 @@ func StatusFilter(query string) (Filter, error) {
 	}, nil
 }
 
-// AuthorFilter return a Filter that match a bug author
+// ResolutionFilter return a Filter that match a bug resolution
+func ResolutionFilter(query string) (Filter, error) {
+	resolution, err := bug.ResolutionFromString(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return excerpt.Resolution == resolution
+	}, nil
+}
+
+// AuthorFilter return a Filter that match a bug author, or the user identity
+// with the "me" query
 func AuthorFilter(query string) Filter {
+	if isMe(query) {
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return excerpt.AuthorId != "" && containsMe([]entity.Id{excerpt.AuthorId}, resolver)
+		}
+	}
+
 	return func(excerpt *BugExcerpt, resolver resolver) bool {
 		query = strings.ToLower(query)
 
@@ -61,8 +109,15 @@ func LabelFilter(label string) Filter {
 	}
 }
 
-// ActorFilter return a Filter that match a bug actor
+// ActorFilter return a Filter that match a bug actor, or the user identity
+// with the "me" query
 func ActorFilter(query string) Filter {
+	if isMe(query) {
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return containsMe(excerpt.Actors, resolver)
+		}
+	}
+
 	return func(excerpt *BugExcerpt, resolver resolver) bool {
 		query = strings.ToLower(query)
 
@@ -80,8 +135,15 @@ func ActorFilter(query string) Filter {
 	}
 }
 
-// ParticipantFilter return a Filter that match a bug participant
+// ParticipantFilter return a Filter that match a bug participant, or the user identity
+// with the "me" query
 func ParticipantFilter(query string) Filter {
+	if isMe(query) {
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return containsMe(excerpt.Participants, resolver)
+		}
+	}
+
 	return func(excerpt *BugExcerpt, resolver resolver) bool {
 		query = strings.ToLower(query)
 
@@ -99,6 +161,137 @@ func ParticipantFilter(query string) Filter {
 	}
 }
 
+// ComponentFilter return a Filter that match a bug component
+func ComponentFilter(component string) Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return excerpt.Component == component
+	}
+}
+
+// AssigneeFilter return a Filter that match a bug assignee, or the user identity
+// with the "me" query
+func AssigneeFilter(query string) Filter {
+	if isMe(query) {
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return containsMe(excerpt.Assignees, resolver)
+		}
+	}
+
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		query = strings.ToLower(query)
+
+		for _, id := range excerpt.Assignees {
+			identityExcerpt, err := resolver.ResolveIdentityExcerpt(id)
+			if err != nil {
+				panic(err)
+			}
+
+			if identityExcerpt.Match(query) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ReviewFilter return a Filter that match a bug waiting for the review of an
+// identity, or of the user identity with the "me" query
+func ReviewFilter(query string) Filter {
+	if isMe(query) {
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return containsMe(excerpt.PendingReviewers, resolver)
+		}
+	}
+
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		query = strings.ToLower(query)
+
+		for _, id := range excerpt.PendingReviewers {
+			identityExcerpt, err := resolver.ResolveIdentityExcerpt(id)
+			if err != nil {
+				panic(err)
+			}
+
+			if identityExcerpt.Match(query) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DuplicateFilter return a Filter that match whether a bug is a duplicate of
+// another one. Valid queries are "yes", "no" and "any".
+func DuplicateFilter(query string) (Filter, error) {
+	switch query {
+	case "yes":
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return excerpt.DuplicateOf != ""
+		}, nil
+	case "no":
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return excerpt.DuplicateOf == ""
+		}, nil
+	case "any":
+		return func(excerpt *BugExcerpt, resolver resolver) bool {
+			return true
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown duplicate filter %s", query)
+}
+
+// ParentFilter return a Filter that match the sub-tasks of the bugs whose id
+// start with the given prefix
+func ParentFilter(prefix string) Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return excerpt.Parent != "" && excerpt.Parent.HasPrefix(prefix)
+	}
+}
+
+// ReopenedFilter return a Filter that match how many times a bug has been
+// reopened. The query is a number, optionally prefixed by a comparison
+// operator among >, >=, < and <=.
+func ReopenedFilter(query string) (Filter, error) {
+	var compare func(count, n int) bool
+	var number string
+
+	switch {
+	case strings.HasPrefix(query, ">="):
+		compare = func(count, n int) bool { return count >= n }
+		number = query[2:]
+	case strings.HasPrefix(query, "<="):
+		compare = func(count, n int) bool { return count <= n }
+		number = query[2:]
+	case strings.HasPrefix(query, ">"):
+		compare = func(count, n int) bool { return count > n }
+		number = query[1:]
+	case strings.HasPrefix(query, "<"):
+		compare = func(count, n int) bool { return count < n }
+		number = query[1:]
+	default:
+		compare = func(count, n int) bool { return count == n }
+		number = query
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid reopened filter %s", query)
+	}
+
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return compare(excerpt.ReopenCount, n)
+	}, nil
+}
+
+// UnreadFilter return a Filter that match the bugs the user identity didn't
+// read since their last edit, or the ones it did if unread is false
+func UnreadFilter(unread bool) Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return resolver.IsUnread(excerpt) == unread
+	}
+}
+
 // TitleFilter return a Filter that match if the title contains the given query
 func TitleFilter(query string) Filter {
 	return func(excerpt *BugExcerpt, resolver resolver) bool {
@@ -116,15 +309,55 @@ func NoLabelFilter() Filter {
 	}
 }
 
-// Filters is a collection of Filter that implement a complex filter
+// NoComponentFilter return a Filter that match the absence of component
+func NoComponentFilter() Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return excerpt.Component == ""
+	}
+}
+
+// NoResolutionFilter return a Filter that match the absence of resolution
+func NoResolutionFilter() Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return excerpt.Resolution == bug.NoResolution
+	}
+}
+
+// NoAssigneeFilter return a Filter that match the absence of assignees
+func NoAssigneeFilter() Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return len(excerpt.Assignees) == 0
+	}
+}
+
+// NoParentFilter return a Filter that match the bugs that are not a sub-task
+func NoParentFilter() Filter {
+	return func(excerpt *BugExcerpt, resolver resolver) bool {
+		return excerpt.Parent == ""
+	}
+}
+
+// Filters is a collection of Filter that implement a complex filter.
+// Without Duplicate filter, the bugs marked as duplicate are excluded.
 type Filters struct {
 	Status      []Filter
+	Resolution  []Filter
 	Author      []Filter
 	Actor       []Filter
 	Participant []Filter
 	Label       []Filter
+	Component   []Filter
+	Assignee    []Filter
+	Review      []Filter
+	Duplicate   []Filter
+	Parent      []Filter
+	Reopened    []Filter
+	Unread      []Filter
 	Title       []Filter
 	NoFilters   []Filter
+
+	// the filters of the registered ExcerptField, by name
+	ExcerptFields map[string][]Filter
 }
 
 // Match check if a bug match the set of filters
@@ -133,6 +366,10 @@ func (f *Filters) Match(excerpt *BugExcerpt, resolver resolver) bool {
 		return false
 	}
 
+	if match := f.orMatch(f.Resolution, excerpt, resolver); !match {
+		return false
+	}
+
 	if match := f.orMatch(f.Author, excerpt, resolver); !match {
 		return false
 	}
@@ -149,6 +386,38 @@ func (f *Filters) Match(excerpt *BugExcerpt, resolver resolver) bool {
 		return false
 	}
 
+	if match := f.orMatch(f.Component, excerpt, resolver); !match {
+		return false
+	}
+
+	if match := f.orMatch(f.Assignee, excerpt, resolver); !match {
+		return false
+	}
+
+	if match := f.orMatch(f.Review, excerpt, resolver); !match {
+		return false
+	}
+
+	if len(f.Duplicate) == 0 {
+		if excerpt.DuplicateOf != "" {
+			return false
+		}
+	} else if match := f.orMatch(f.Duplicate, excerpt, resolver); !match {
+		return false
+	}
+
+	if match := f.orMatch(f.Parent, excerpt, resolver); !match {
+		return false
+	}
+
+	if match := f.andMatch(f.Reopened, excerpt, resolver); !match {
+		return false
+	}
+
+	if match := f.orMatch(f.Unread, excerpt, resolver); !match {
+		return false
+	}
+
 	if match := f.andMatch(f.NoFilters, excerpt, resolver); !match {
 		return false
 	}
@@ -157,6 +426,12 @@ func (f *Filters) Match(excerpt *BugExcerpt, resolver resolver) bool {
 		return false
 	}
 
+	for _, filters := range f.ExcerptFields {
+		if match := f.orMatch(filters, excerpt, resolver); !match {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// The forms of a repository are JSON files committed with the code, in the
+// working tree, one per form, as .git-bug/forms/crash-report.json for the
+// form crash-report.
+const formsDir = ".git-bug/forms"
+
+// ErrUnknownForm is returned when using a form not defined in the repository
+type ErrUnknownForm struct {
+	Name string
+}
+
+func (e ErrUnknownForm) Error() string {
+	return fmt.Sprintf("unknown form %s, the forms are defined in %s", e.Name, formsDir)
+}
+
+// Forms return the forms defined in the repository, by name
+func (c *RepoCache) Forms() ([]*bug.Form, error) {
+	dir := filepath.Join(filepath.Dir(c.repo.GetPath()), formsDir)
+
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*bug.Form
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		form, err := bug.ParseForm(strings.TrimSuffix(entry.Name(), ".json"), data)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, form)
+	}
+
+	return result, nil
+}
+
+// ResolveForm return the form of the given name
+func (c *RepoCache) ResolveForm(name string) (*bug.Form, error) {
+	forms, err := c.Forms()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, form := range forms {
+		if form.Name == name {
+			return form, nil
+		}
+	}
+
+	return nil, ErrUnknownForm{Name: name}
+}
+
+// NewBugFromForm create a new bug from the answers to a form, by id of its
+// fields. The description is made of the answers, also stored as metadata of
+// the create operation, and the labels of the form are added to the given
+// ones. The labels and fields required by the repository must be given.
+// The new bug is written in the repository (commit)
+func (c *RepoCache) NewBugFromForm(title string, form *bug.Form, answers map[string]string, labels []string, fields map[string]string) (*BugCache, *bug.CreateOperation, error) {
+	err := form.ValidateAnswers(answers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	author, err := c.GetUserIdentity()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requirements, err := c.Requirements()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, label := range form.Labels {
+		if !containsLabel(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+
+	b, op, err := bug.CreateWithFields(author.Identity, time.Now().Unix(), title, form.Message(answers), labels, fields, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = op.ValidateRequirements(requirements)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key, value := range form.Metadata(answers) {
+		op.SetMetadata(key, value)
+	}
+
+	return c.commitNewBug(b, op)
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if strings.TrimSpace(l) == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"testing"
+)
+
+// FuzzParseQuery check that any query is either parsed or refused, without
+// panicking
+func FuzzParseQuery(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"status:open author:rene",
+		`label:"a b" no:label sort:edit-asc`,
+		"is:unread title:foo review:me",
+		`"unterminated`,
+		"sort:",
+		"status:",
+		`author:""`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, query string) {
+		_, _ = ParseQuery(query)
+	})
+}
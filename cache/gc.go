@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"os"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+// GCReport describe what has been cleaned by a garbage collection
+type GCReport struct {
+	// remote-tracking bugs removed, by remote
+	PrunedRemoteBugs map[string][]entity.Id
+	// remote-tracking identities removed, by remote
+	PrunedRemoteIdentities map[string][]entity.Id
+	// remotes that couldn't be checked
+	RemoteErrors map[string]error
+
+	// excerpts of missing bugs removed from the cache
+	PrunedBugExcerpts []entity.Id
+	// excerpts of missing identities removed from the cache
+	PrunedIdentityExcerpts []entity.Id
+
+	// size in bytes of the cache files, before and after the collection
+	CacheSizeBefore int64
+	CacheSizeAfter  int64
+}
+
+// ReclaimedCacheSpace return the number of bytes freed in the cache files
+func (r *GCReport) ReclaimedCacheSpace() int64 {
+	return r.CacheSizeBefore - r.CacheSizeAfter
+}
+
+// GC clean up the git-bug data of the repository:
+//
+// - if checkRemotes is true, the remote-tracking references of the bugs and
+//   identities deleted on their remote are removed. The corresponding git
+//   objects become unreachable and are left for git to prune.
+// - the cached data of the bugs and identities that don't exist anymore are
+//   removed
+// - the cache files are rewritten
+func (c *RepoCache) GC(checkRemotes bool) (*GCReport, error) {
+	report := &GCReport{
+		PrunedRemoteBugs:       make(map[string][]entity.Id),
+		PrunedRemoteIdentities: make(map[string][]entity.Id),
+		RemoteErrors:           make(map[string]error),
+	}
+
+	if checkRemotes {
+		remotes, err := c.GetRemotes()
+		if err != nil {
+			return nil, err
+		}
+
+		for remote := range remotes {
+			bugs, err := bug.PruneRemote(c.repo, remote)
+			report.PrunedRemoteBugs[remote] = bugs
+			if err != nil {
+				report.RemoteErrors[remote] = err
+				continue
+			}
+
+			identities, err := identity.PruneRemote(c.repo, remote)
+			report.PrunedRemoteIdentities[remote] = identities
+			if err != nil {
+				report.RemoteErrors[remote] = err
+			}
+		}
+	}
+
+	report.CacheSizeBefore = c.cacheFilesSize()
+
+	bugIds, err := bug.ListLocalIds(c.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedBugIds := c.AllBugsIds()
+
+	c.muBug.Lock()
+	report.PrunedBugExcerpts = pruneMissing(bugIds, cachedBugIds, func(id entity.Id) {
+		delete(c.bugExcerpts, id)
+		c.searchIndex.remove(id)
+		delete(c.bugs, id)
+	})
+	c.rollUpAllChildren()
+	c.muBug.Unlock()
+
+	identityIds, err := identity.ListLocalIds(c.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedIdentityIds := c.AllIdentityIds()
+
+	c.muIdentity.Lock()
+	report.PrunedIdentityExcerpts = pruneMissing(identityIds, cachedIdentityIds, func(id entity.Id) {
+		delete(c.identitiesExcerpts, id)
+		delete(c.identities, id)
+	})
+	c.muIdentity.Unlock()
+
+	err = c.write()
+	if err != nil {
+		return nil, err
+	}
+
+	report.CacheSizeAfter = c.cacheFilesSize()
+
+	return report, nil
+}
+
+// pruneMissing call remove for each cached id that is not in the existing ids,
+// and return the removed ids
+func pruneMissing(existing []entity.Id, cached []entity.Id, remove func(id entity.Id)) []entity.Id {
+	set := make(map[entity.Id]struct{}, len(existing))
+	for _, id := range existing {
+		set[id] = struct{}{}
+	}
+
+	var pruned []entity.Id
+	for _, id := range cached {
+		if _, ok := set[id]; !ok {
+			remove(id)
+			pruned = append(pruned, id)
+		}
+	}
+
+	return pruned
+}
+
+// cacheFilesSize return the total size in bytes of the cache files on disk
+func (c *RepoCache) cacheFilesSize() int64 {
+	var total int64
+	for _, p := range []string{bugCacheFilePath(c.repo), identityCacheFilePath(c.repo)} {
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		total += info.Size()
+	}
+	return total
+}
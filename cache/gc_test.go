@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestGC(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	cacheA, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+
+	cacheB, err := NewRepoCache(repoB)
+	require.NoError(t, err)
+
+	reneA, err := cacheA.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	err = cacheA.SetUserIdentity(reneA)
+	require.NoError(t, err)
+
+	bug1, _, err := cacheA.NewBug("bug1", "message")
+	require.NoError(t, err)
+	bug2, _, err := cacheA.NewBug("bug2", "message")
+	require.NoError(t, err)
+
+	_, err = cacheA.Push("origin")
+	require.NoError(t, err)
+
+	_, err = cacheB.Fetch("origin")
+	require.NoError(t, err)
+
+	// bug2 get deleted on the remote
+	require.NoError(t, remote.RemoveRef("refs/bugs/"+bug2.Id().String()))
+
+	report, err := cacheB.GC(true)
+	require.NoError(t, err)
+	require.Empty(t, report.RemoteErrors)
+	require.Equal(t, bug2.Id(), report.PrunedRemoteBugs["origin"][0])
+	require.Len(t, report.PrunedRemoteBugs["origin"], 1)
+	require.Empty(t, report.PrunedRemoteIdentities["origin"])
+
+	exist, err := repoB.RefExist("refs/remotes/origin/bugs/" + bug1.Id().String())
+	require.NoError(t, err)
+	require.True(t, exist)
+	exist, err = repoB.RefExist("refs/remotes/origin/bugs/" + bug2.Id().String())
+	require.NoError(t, err)
+	require.False(t, exist)
+
+	// bug1 get deleted locally, outside of the cache
+	require.NoError(t, repoA.RemoveRef("refs/bugs/"+bug1.Id().String()))
+
+	report, err = cacheA.GC(false)
+	require.NoError(t, err)
+	require.Empty(t, report.PrunedRemoteBugs)
+	require.Len(t, report.PrunedBugExcerpts, 1)
+	require.Equal(t, bug1.Id(), report.PrunedBugExcerpts[0])
+	require.Empty(t, report.PrunedIdentityExcerpts)
+	require.True(t, report.ReclaimedCacheSpace() > 0)
+
+	require.Len(t, cacheA.AllBugsIds(), 1)
+	_, err = cacheA.ResolveBugExcerpt(bug1.Id())
+	require.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// ChildrenOf return the id of the sub-tasks of the given bug, ordered by
+// creation
+func (c *RepoCache) ChildrenOf(id entity.Id) []entity.Id {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	var children []*BugExcerpt
+	for _, excerpt := range c.bugExcerpts {
+		if excerpt.Parent == id {
+			children = append(children, excerpt)
+		}
+	}
+
+	sort.Sort(BugsByCreationTime(children))
+
+	result := make([]entity.Id, len(children))
+	for i, excerpt := range children {
+		result[i] = excerpt.Id
+	}
+
+	return result
+}
+
+// isAncestor tell if a bug is the parent of another one, or the parent of
+// its parent and so on
+func (c *RepoCache) isAncestor(ancestor entity.Id, id entity.Id) bool {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	// the visited bugs, to not loop forever on a cycle made by concurrent
+	// edits
+	seen := make(map[entity.Id]bool)
+
+	for id != "" && !seen[id] {
+		seen[id] = true
+
+		excerpt, ok := c.bugExcerpts[id]
+		if !ok {
+			return false
+		}
+		if excerpt.Parent == ancestor {
+			return true
+		}
+		id = excerpt.Parent
+	}
+
+	return false
+}
+
+// rollUpChildren count again the sub-tasks of a bug, and how many of them
+// are closed, in its excerpt
+// muBug must be held
+func (c *RepoCache) rollUpChildren(id entity.Id) {
+	if id == "" {
+		return
+	}
+
+	excerpt, ok := c.bugExcerpts[id]
+	if !ok {
+		return
+	}
+
+	// the excerpts are shared with the readers, a new one is made
+	rolled := *excerpt
+	rolled.Children, rolled.ChildrenClosed = 0, 0
+
+	for _, child := range c.bugExcerpts {
+		if child.Parent == id {
+			rolled.Children++
+			if child.Status == bug.ClosedStatus {
+				rolled.ChildrenClosed++
+			}
+		}
+	}
+
+	c.bugExcerpts[id] = &rolled
+}
+
+// rollUpAllChildren count again the sub-tasks of all the bugs, after the
+// excerpts have been built
+// muBug must be held
+func (c *RepoCache) rollUpAllChildren() {
+	children := make(map[entity.Id]int)
+	closed := make(map[entity.Id]int)
+
+	for _, excerpt := range c.bugExcerpts {
+		if excerpt.Parent == "" {
+			continue
+		}
+		children[excerpt.Parent]++
+		if excerpt.Status == bug.ClosedStatus {
+			closed[excerpt.Parent]++
+		}
+	}
+
+	for id, excerpt := range c.bugExcerpts {
+		if excerpt.Children == children[id] && excerpt.ChildrenClosed == closed[id] {
+			continue
+		}
+
+		rolled := *excerpt
+		rolled.Children = children[id]
+		rolled.ChildrenClosed = closed[id]
+		c.bugExcerpts[id] = &rolled
+	}
+}
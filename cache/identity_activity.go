@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// IdentityActivity summarize the participation of an identity in the bugs
+type IdentityActivity struct {
+	// The bugs created by the identity
+	Authored []entity.Id
+	// The bugs created by someone else where the identity commented
+	Commented []entity.Id
+	// The number of bugs where the identity did anything (comment, label, status...)
+	Involved int
+	// The most recent edition of a bug where the identity was involved
+	LastActivityUnixTime int64
+}
+
+// IdentityActivity compute the activity of an identity from the bug excerpts
+func (c *RepoCache) IdentityActivity(id entity.Id) IdentityActivity {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	var result IdentityActivity
+
+	for _, excerpt := range c.bugExcerpts {
+		involved := excerpt.AuthorId == id || containsId(excerpt.Actors, id)
+		if !involved {
+			continue
+		}
+
+		result.Involved++
+		if excerpt.EditUnixTime > result.LastActivityUnixTime {
+			result.LastActivityUnixTime = excerpt.EditUnixTime
+		}
+
+		switch {
+		case excerpt.AuthorId == id:
+			result.Authored = append(result.Authored, excerpt.Id)
+		case containsId(excerpt.Participants, id):
+			result.Commented = append(result.Commented, excerpt.Id)
+		}
+	}
+
+	sortIds(result.Authored)
+	sortIds(result.Commented)
+
+	return result
+}
+
+// IdentityOperation is an operation made by an identity, with the bug it has
+// been made on
+type IdentityOperation struct {
+	BugId     entity.Id
+	Operation bug.Operation
+}
+
+// RecentOperations return the operations made by an identity, the most recent
+// first. If limit is positive, at most limit operations are returned.
+func (c *RepoCache) RecentOperations(id entity.Id, limit int) ([]IdentityOperation, error) {
+	c.muBug.RLock()
+	var involved []entity.Id
+	for _, excerpt := range c.bugExcerpts {
+		if excerpt.AuthorId == id || containsId(excerpt.Actors, id) {
+			involved = append(involved, excerpt.Id)
+		}
+	}
+	c.muBug.RUnlock()
+
+	var result []IdentityOperation
+
+	for _, bugId := range involved {
+		b, err := c.ResolveBug(bugId)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range b.Snapshot().Operations {
+			if op.GetAuthor().Id() == id {
+				result = append(result, IdentityOperation{BugId: bugId, Operation: op})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Operation.GetUnixTime() > result[j].Operation.GetUnixTime()
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func sortIds(ids []entity.Id) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+}
+
+func containsId(ids []entity.Id, id entity.Id) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
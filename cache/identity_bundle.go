@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+// ExportIdentities create a portable bundle of the given identities, with
+// their full history
+func (c *RepoCache) ExportIdentities(ids []entity.Id) (*identity.Bundle, error) {
+	return identity.ExportBundle(c.repo, ids)
+}
+
+// ImportIdentities store the identities of a bundle, and update the identity
+// excerpts accordingly
+func (c *RepoCache) ImportIdentities(bundle *identity.Bundle) ([]entity.MergeResult, error) {
+	results, err := identity.ImportBundle(c.repo, bundle)
+
+	for _, result := range results {
+		switch result.Status {
+		case entity.MergeStatusNew, entity.MergeStatusUpdated:
+			i := result.Entity.(*identity.Identity)
+			c.muIdentity.Lock()
+			c.identitiesExcerpts[result.Id] = NewIdentityExcerpt(i)
+			// the loaded identity is outdated
+			delete(c.identities, result.Id)
+			c.muIdentity.Unlock()
+		}
+	}
+
+	if err != nil {
+		return results, err
+	}
+
+	return results, c.writeIdentityCache()
+}
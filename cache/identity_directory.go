@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// an identity directory is a git remote holding only identities, shared
+// between the repositories of an organization
+const identityDirectoryConfigKey = "git-bug.identity-directory"
+
+// IdentityDirectory return the git remote configured as the identity
+// directory, or an empty string if there is none
+func (c *RepoCache) IdentityDirectory() (string, error) {
+	remote, err := c.repo.LocalConfig().ReadString(identityDirectoryConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return remote, nil
+}
+
+// SetIdentityDirectory configure the git remote to use as the identity
+// directory
+func (c *RepoCache) SetIdentityDirectory(remote string) error {
+	remotes, err := c.repo.GetRemotes()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := remotes[remote]; !ok {
+		return fmt.Errorf("unknown remote %s", remote)
+	}
+
+	return c.repo.LocalConfig().StoreString(identityDirectoryConfigKey, remote)
+}
+
+// FetchIdentities retrieve the identities of a remote, without the bugs
+// This does not change the local identities state
+func (c *RepoCache) FetchIdentities(remote string) (string, error) {
+	return identity.Fetch(c.repo, remote)
+}
+
+// MergeIdentities will merge all the available remote identities, without the
+// bugs
+func (c *RepoCache) MergeIdentities(remote string) <-chan entity.MergeResult {
+	out := make(chan entity.MergeResult)
+
+	go func() {
+		defer close(out)
+
+		c.mergeIdentities(remote, out)
+
+		err := c.writeIdentityCache()
+
+		// No easy way out here ..
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	return out
+}
+
+// PushIdentities update a remote with the local identities, without the bugs
+func (c *RepoCache) PushIdentities(remote string) (string, error) {
+	return identity.Push(c.repo, remote)
+}
+
+// PullIdentities will do a FetchIdentities + MergeIdentities
+// This function will return an error if a merge fail
+func (c *RepoCache) PullIdentities(remote string) error {
+	_, err := c.FetchIdentities(remote)
+	if err != nil {
+		return err
+	}
+
+	for merge := range c.MergeIdentities(remote) {
+		if merge.Err != nil {
+			return merge.Err
+		}
+		if merge.Status == entity.MergeStatusInvalid {
+			return errors.Errorf("merge failure: %s", merge.Reason)
+		}
+	}
+
+	return nil
+}
+
+// mergeIdentities merge the remote identities and update the identity
+// excerpts accordingly
+func (c *RepoCache) mergeIdentities(remote string, out chan<- entity.MergeResult) {
+	results := identity.MergeAll(c.repo, remote)
+	for result := range results {
+		out <- result
+
+		if result.Err != nil {
+			continue
+		}
+
+		switch result.Status {
+		case entity.MergeStatusNew, entity.MergeStatusUpdated:
+			i := result.Entity.(*identity.Identity)
+			c.muIdentity.Lock()
+			c.identitiesExcerpts[result.Id] = NewIdentityExcerpt(i)
+			c.muIdentity.Unlock()
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// The label registry of a repository is configured in git, with a section
+// per registered label:
+//
+//	git-bug.label.<key>.name    the label
+//	git-bug.label.<key>.color   the color of the label, as "#rrggbb"
+//
+// The key is derived from the label, as a label can hold characters not
+// allowed in a git config key.
+const (
+	labelRegistryConfigPrefix = "git-bug.label."
+	labelSectionPattern       = "git-bug.label.%s"
+	labelNameConfigKey        = labelSectionPattern + ".name"
+	labelColorConfigKey       = labelSectionPattern + ".color"
+)
+
+// LabelEntry is a label of the repository, registered or used by some bugs
+type LabelEntry struct {
+	Label bug.Label
+	Color bug.LabelColor
+	// true if the label is in the label registry
+	Registered bool
+	// the number of bugs having the label
+	Count int
+}
+
+func labelConfigKey(label bug.Label) string {
+	hash := sha256.Sum256([]byte(label))
+	return hex.EncodeToString(hash[:8])
+}
+
+// LabelEntries list the labels of the label registry and the labels used by
+// the bugs, sorted by name
+func (c *RepoCache) LabelEntries() ([]LabelEntry, error) {
+	c.muLabels.Lock()
+	registry, err := c.labelRegistry()
+	c.muLabels.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[bug.Label]*LabelEntry)
+
+	for label, color := range registry {
+		entries[label] = &LabelEntry{Label: label, Color: color, Registered: true}
+	}
+
+	for _, usage := range c.LabelsUsage() {
+		entry, ok := entries[usage.Label]
+		if !ok {
+			entry = &LabelEntry{Label: usage.Label, Color: usage.Label.Color()}
+			entries[usage.Label] = entry
+		}
+		entry.Count = usage.Count
+	}
+
+	result := make([]LabelEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, *entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return string(result[i].Label) < string(result[j].Label)
+	})
+
+	return result, nil
+}
+
+// LabelEntry return a label of the label registry or used by some bugs
+func (c *RepoCache) LabelEntry(label bug.Label) (*LabelEntry, error) {
+	entries, err := c.LabelEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Label == label {
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown label %s", label)
+}
+
+// LabelColor return the color of a label, as set in the label registry or
+// computed from the label otherwise
+func (c *RepoCache) LabelColor(label bug.Label) bug.LabelColor {
+	c.muLabels.Lock()
+	defer c.muLabels.Unlock()
+
+	registry, err := c.labelRegistry()
+	if err == nil {
+		if color, ok := registry[label]; ok {
+			return color
+		}
+	}
+
+	return label.Color()
+}
+
+// CreateLabel add a label to the label registry, so that it can be offered
+// before any bug has it. Without color, the color computed from the label is
+// used.
+func (c *RepoCache) CreateLabel(label bug.Label, color *bug.LabelColor) error {
+	if err := label.Validate(); err != nil {
+		return fmt.Errorf("invalid label: %v", err)
+	}
+
+	if c.labelExists(label) {
+		return fmt.Errorf("the label %s already exists", label)
+	}
+
+	if color == nil {
+		lc := label.Color()
+		color = &lc
+	}
+
+	return c.registerLabel(label, *color)
+}
+
+// SetLabelColor change the color of a label, adding it to the label registry
+// if needed
+func (c *RepoCache) SetLabelColor(label bug.Label, color bug.LabelColor) error {
+	if !c.labelExists(label) {
+		return fmt.Errorf("unknown label %s", label)
+	}
+
+	return c.registerLabel(label, color)
+}
+
+// RenameLabel rename a label on all the bugs having it and in the label
+// registry, and return the ids of the changed bugs, even if an error
+// interrupt the change. The new label must not exist already.
+func (c *RepoCache) RenameLabel(old bug.Label, new bug.Label) ([]entity.Id, error) {
+	if err := new.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid label: %v", err)
+	}
+	if old == new {
+		return nil, fmt.Errorf("the two labels are the same")
+	}
+	if !c.labelExists(old) {
+		return nil, fmt.Errorf("unknown label %s", old)
+	}
+	if c.labelExists(new) {
+		return nil, fmt.Errorf("the label %s already exists, merge the labels instead", new)
+	}
+
+	c.muLabels.Lock()
+	registry, err := c.labelRegistry()
+	c.muLabels.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := c.ReplaceLabel(old, new)
+	if err != nil {
+		return ids, err
+	}
+
+	// the renamed label keep its color
+	if color, ok := registry[old]; ok {
+		err = c.registerLabel(new, color)
+		if err != nil {
+			return ids, err
+		}
+		return ids, c.unregisterLabel(old)
+	}
+
+	return ids, nil
+}
+
+// MergeLabel replace a label by another existing one on all the bugs having
+// it, and remove it from the label registry. The ids of the changed bugs are
+// returned, even if an error interrupt the change.
+func (c *RepoCache) MergeLabel(label bug.Label, into bug.Label) ([]entity.Id, error) {
+	if label == into {
+		return nil, fmt.Errorf("the two labels are the same")
+	}
+	if !c.labelExists(label) {
+		return nil, fmt.Errorf("unknown label %s", label)
+	}
+	if !c.labelExists(into) {
+		return nil, fmt.Errorf("unknown label %s, rename the label instead", into)
+	}
+
+	ids, err := c.ReplaceLabel(label, into)
+	if err != nil {
+		return ids, err
+	}
+
+	return ids, c.unregisterLabel(label)
+}
+
+// labelExists tell if a label is registered or used by some bugs
+func (c *RepoCache) labelExists(label bug.Label) bool {
+	c.muLabels.Lock()
+	registry, err := c.labelRegistry()
+	c.muLabels.Unlock()
+	if err == nil {
+		if _, ok := registry[label]; ok {
+			return true
+		}
+	}
+
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	for _, excerpt := range c.bugExcerpts {
+		for _, l := range excerpt.Labels {
+			if l == label {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// labelRegistry return the label registry, read from the config when first
+// needed. The caller must hold muLabels.
+func (c *RepoCache) labelRegistry() (map[bug.Label]bug.LabelColor, error) {
+	if c.labels != nil {
+		return c.labels, nil
+	}
+
+	raw, err := c.repo.LocalConfig().ReadAll(labelRegistryConfigPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := make(map[bug.Label]bug.LabelColor)
+
+	for key, name := range raw {
+		if !strings.HasSuffix(key, ".name") {
+			continue
+		}
+
+		label := bug.Label(name)
+		color := label.Color()
+
+		rawColor, ok := raw[strings.TrimSuffix(key, ".name")+".color"]
+		if ok {
+			color, err = bug.ParseLabelColor(rawColor)
+			if err != nil {
+				return nil, fmt.Errorf("label %s: %v", name, err)
+			}
+		}
+
+		registry[label] = color
+	}
+
+	c.labels = registry
+
+	return registry, nil
+}
+
+func (c *RepoCache) registerLabel(label bug.Label, color bug.LabelColor) error {
+	c.muLabels.Lock()
+	defer c.muLabels.Unlock()
+
+	key := labelConfigKey(label)
+
+	err := c.repo.LocalConfig().StoreString(fmt.Sprintf(labelNameConfigKey, key), label.String())
+	if err == nil {
+		err = c.repo.LocalConfig().StoreString(fmt.Sprintf(labelColorConfigKey, key), color.Hex())
+	}
+
+	// read the registry again on the next use
+	c.labels = nil
+
+	return err
+}
+
+func (c *RepoCache) unregisterLabel(label bug.Label) error {
+	c.muLabels.Lock()
+	defer c.muLabels.Unlock()
+
+	registry, err := c.labelRegistry()
+	if err != nil {
+		return err
+	}
+	if _, ok := registry[label]; !ok {
+		return nil
+	}
+
+	err = c.repo.LocalConfig().RemoveAll(fmt.Sprintf(labelSectionPattern, labelConfigKey(label)))
+
+	// read the registry again on the next use
+	c.labels = nil
+
+	return err
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// LabelUsage is a label used in the repository, with the number of bugs
+// having it
+type LabelUsage struct {
+	Label bug.Label
+	Count int
+}
+
+// LabelsUsage list the labels used in the repository with the number of bugs
+// having them, the most used first, to offer them for autocompletion
+func (c *RepoCache) LabelsUsage() []LabelUsage {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	return c.labelsUsage()
+}
+
+func (c *RepoCache) labelsUsage() []LabelUsage {
+	counts := make(map[bug.Label]int)
+
+	for _, excerpt := range c.bugExcerpts {
+		for _, l := range excerpt.Labels {
+			counts[l]++
+		}
+	}
+
+	result := make([]LabelUsage, 0, len(counts))
+	for l, count := range counts {
+		result = append(result, LabelUsage{Label: l, Count: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return string(result[i].Label) < string(result[j].Label)
+	})
+
+	return result
+}
+
+// SuggestLabel return the existing label a new label is likely a typo of, if
+// any: the most used label within a small edit distance of it, ignoring the
+// case. No label is suggested for a label already in use.
+func (c *RepoCache) SuggestLabel(label bug.Label) (bug.Label, bool) {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	usages := c.labelsUsage()
+
+	for _, usage := range usages {
+		if usage.Label == label {
+			return "", false
+		}
+	}
+
+	text := strings.ToLower(string(label))
+
+	// one typo in a short label, two in a longer one
+	maxDistance := 1
+	if utf8.RuneCountInString(text) > 4 {
+		maxDistance = 2
+	}
+
+	best := -1
+	var suggestion bug.Label
+
+	// usages is sorted by usage, so the first label at a given distance is
+	// the most used
+	for _, usage := range usages {
+		d := editDistance(text, strings.ToLower(string(usage.Label)))
+		if d <= maxDistance && (best < 0 || d < best) {
+			best = d
+			suggestion = usage.Label
+		}
+	}
+
+	return suggestion, best >= 0
+}
+
+// ReplaceLabel replace a label by another one on all the bugs having it,
+// with a label change operation committed on each, and return the ids of the
+// changed bugs, even if an error interrupt the change. This rename a label, or merge it into another one if the
+// new label is already in use.
+func (c *RepoCache) ReplaceLabel(old bug.Label, new bug.Label) ([]entity.Id, error) {
+	if old == new {
+		return nil, nil
+	}
+
+	c.muBug.RLock()
+	var ids []entity.Id
+	for id, excerpt := range c.bugExcerpts {
+		for _, l := range excerpt.Labels {
+			if l == old {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	c.muBug.RUnlock()
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var changed []entity.Id
+
+	err := c.Transaction(func() error {
+		for _, id := range ids {
+			b, err := c.ResolveBug(id)
+			if err != nil {
+				return err
+			}
+
+			_, _, err = b.ChangeLabels([]string{new.String()}, []string{old.String()})
+			if err != nil {
+				return err
+			}
+
+			err = b.Commit()
+			if err != nil {
+				return err
+			}
+
+			changed = append(changed, id)
+		}
+		return nil
+	})
+
+	return changed, err
+}
+
+// editDistance compute the number of insertions, deletions, substitutions
+// and transpositions of adjacent characters needed to go from a to b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// the last three rows of the distance matrix
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d := prev[j-1] + cost
+			if prev[j]+1 < d {
+				d = prev[j] + 1
+			}
+			if cur[j-1]+1 < d {
+				d = cur[j-1] + 1
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && prev2[j-2]+1 < d {
+				d = prev2[j-2] + 1
+			}
+			cur[j] = d
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// The content lint of a repository, checking the titles and the messages of
+// the user before committing them, is configured in git:
+//
+//	git-bug.lint.max-title-length    the max length of the titles, in characters
+//	git-bug.lint.forbidden-words     the words refused in the titles and the messages, separated by commas or spaces
+//	git-bug.lint.required-sections   the headings the description of a new bug must have, as "Steps to reproduce", separated by commas
+//
+// As the policies, the content lint doesn't apply to the changes imported by
+// the bridges or pulled from a remote.
+const (
+	lintMaxTitleLengthKey   = "git-bug.lint.max-title-length"
+	lintForbiddenWordsKey   = "git-bug.lint.forbidden-words"
+	lintRequiredSectionsKey = "git-bug.lint.required-sections"
+)
+
+// the name of the content lint in the ErrPolicy it returns
+const lintPolicyName = "lint"
+
+// Lint is the content lint the titles and the messages of the user must pass
+type Lint struct {
+	// the max length of the titles, in characters, 0 for no limit
+	MaxTitleLength int
+	// the words refused in the titles and the messages, lowercase
+	ForbiddenWords []string
+	// the headings the description of a new bug must have
+	RequiredSections []string
+}
+
+// IsEmpty tell if the content lint check nothing
+func (l Lint) IsEmpty() bool {
+	return l.MaxTitleLength == 0 && len(l.ForbiddenWords) == 0 && len(l.RequiredSections) == 0
+}
+
+// Lint return the configured content lint
+func (c *RepoCache) Lint() (Lint, error) {
+	config := c.repo.LocalConfig()
+
+	var lint Lint
+
+	raw, err := config.ReadString(lintMaxTitleLengthKey)
+	if err != nil && err != repository.ErrNoConfigEntry {
+		return Lint{}, err
+	}
+	if err == nil {
+		lint.MaxTitleLength, err = strconv.Atoi(raw)
+		if err != nil || lint.MaxTitleLength < 0 {
+			return Lint{}, fmt.Errorf("invalid %s: %s", lintMaxTitleLengthKey, raw)
+		}
+	}
+
+	words, err := readConfigList(config, lintForbiddenWordsKey)
+	if err != nil {
+		return Lint{}, err
+	}
+	for _, word := range words {
+		lint.ForbiddenWords = append(lint.ForbiddenWords, strings.ToLower(word))
+	}
+
+	// the headings hold spaces, they are separated by commas only
+	raw, err = config.ReadString(lintRequiredSectionsKey)
+	if err != nil && err != repository.ErrNoConfigEntry {
+		return Lint{}, err
+	}
+	for _, section := range strings.Split(raw, ",") {
+		section = strings.TrimSpace(section)
+		if section != "" {
+			lint.RequiredSections = append(lint.RequiredSections, section)
+		}
+	}
+
+	return lint, nil
+}
+
+// DisableLint skip the content lint for the lifetime of this cache, as asked
+// with --no-verify
+func (c *RepoCache) DisableLint() {
+	c.noLint = true
+}
+
+// check check the titles and the messages of the operations
+func (l Lint) check(ops []bug.Operation) error {
+	for _, op := range ops {
+		var err error
+
+		switch op := op.(type) {
+		case *bug.CreateOperation:
+			err = l.checkTitle(op.Title)
+			if err == nil {
+				err = l.checkMessage(op.Message)
+			}
+			if err == nil {
+				err = l.checkSections(op.Message)
+			}
+		case *bug.SetTitleOperation:
+			err = l.checkTitle(op.Title)
+		case *bug.AddCommentOperation:
+			err = l.checkMessage(op.Message)
+		case *bug.EditCommentOperation:
+			err = l.checkMessage(op.Message)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l Lint) checkTitle(title string) error {
+	if l.MaxTitleLength > 0 && len([]rune(title)) > l.MaxTitleLength {
+		return ErrPolicy{
+			Policy:  lintPolicyName,
+			Message: fmt.Sprintf("the title is longer than %d characters", l.MaxTitleLength),
+		}
+	}
+
+	return l.checkWords(title)
+}
+
+func (l Lint) checkMessage(message string) error {
+	return l.checkWords(message)
+}
+
+func (l Lint) checkWords(text string) error {
+	if len(l.ForbiddenWords) == 0 {
+		return nil
+	}
+
+	for _, word := range searchWords(text) {
+		for _, forbidden := range l.ForbiddenWords {
+			if word == forbidden {
+				return ErrPolicy{
+					Policy:  lintPolicyName,
+					Message: fmt.Sprintf("the word \"%s\" is forbidden", forbidden),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSections check that the description has the required headings, as
+// "## Steps to reproduce", of any level
+func (l Lint) checkSections(message string) error {
+	headings := make(map[string]bool)
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		heading := strings.Trim(line, "# ")
+		heading = strings.TrimSuffix(heading, ":")
+		headings[strings.ToLower(strings.TrimSpace(heading))] = true
+	}
+
+	for _, section := range l.RequiredSections {
+		if !headings[strings.ToLower(section)] {
+			return ErrPolicy{
+				Policy:  lintPolicyName,
+				Message: fmt.Sprintf("the description must have a \"%s\" section", section),
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,443 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+// The metadata linking the bugs and operations mirrored from another
+// repository to their origin. As the ids are hashes, they designate the
+// original bug or operation without ambiguity, whatever the repository.
+const (
+	// the id of the original operation, on every mirrored operation
+	MirrorOriginMetadataKey = "mirror-origin"
+	// the id of the original bug, on the Create operation of a mirrored bug
+	MirrorBugMetadataKey = "mirror-bug"
+)
+
+type MirrorStatus int
+
+const (
+	_ MirrorStatus = iota
+	MirrorStatusNew
+	MirrorStatusUpdated
+	MirrorStatusNothing
+)
+
+func (s MirrorStatus) String() string {
+	switch s {
+	case MirrorStatusNew:
+		return "new"
+	case MirrorStatusUpdated:
+		return "updated"
+	case MirrorStatusNothing:
+		return "nothing"
+	default:
+		return "unknown status"
+	}
+}
+
+// MirrorResult describe what happened to a bug when mirroring it to another
+// repository
+type MirrorResult struct {
+	// the bug of the source repository
+	Source entity.Id
+	// the mirrored bug, in the target repository
+	Target entity.Id
+
+	Status MirrorStatus
+	// the number of operations copied
+	Operations int
+	// the operations which could not be copied, to be tried again on the next
+	// mirroring
+	Warnings []string
+}
+
+// MirrorTo copy the bugs matching the query, or all the bugs if nil, to
+// another repository, as well as the identities involved. Unlike with a git
+// remote, the bugs are copied as new bugs, linked to the original ones with
+// the metadata MirrorBugMetadataKey and MirrorOriginMetadataKey. The
+// operations already mirrored in one way or the other are not copied again,
+// so that mirroring in both directions synchronize the bugs.
+//
+// The bugs shared with the other repository through git keep being
+// synchronized by git alone, and are skipped.
+func (c *RepoCache) MirrorTo(target *RepoCache, query *Query) ([]MirrorResult, error) {
+	if query == nil {
+		query = NewQuery()
+	}
+	// the older bugs first, so that the relations between bugs can be mirrored
+	ordered := *query
+	ordered.OrderBy = OrderByCreation
+	ordered.OrderDirection = OrderAscending
+
+	var results []MirrorResult
+
+	for _, id := range c.QueryBugs(&ordered) {
+		b, err := c.ResolveBug(id)
+		if err != nil {
+			return results, err
+		}
+
+		result, err := c.mirrorBug(target, b)
+		if err != nil {
+			return results, errors.Wrapf(err, "can't mirror bug %s", id.Human())
+		}
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+
+	return results, nil
+}
+
+func (c *RepoCache) mirrorBug(target *RepoCache, b *BugCache) (*MirrorResult, error) {
+	// shared through git already
+	if _, err := target.ResolveBugExcerpt(b.Id()); err == nil {
+		return nil, nil
+	}
+
+	ops := b.Snapshot().Operations
+
+	err := c.mirrorIdentities(target, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.mirrorFiles(target, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MirrorResult{
+		Source: b.Id(),
+		Status: MirrorStatusUpdated,
+	}
+
+	mirrored, err := target.resolveMirroredBug(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if mirrored == nil {
+		create := ops[0].(*bug.CreateOperation)
+		author, err := target.ResolveIdentity(create.GetAuthor().Id())
+		if err != nil {
+			return nil, err
+		}
+
+		labels := make([]string, len(create.Labels))
+		for i, label := range create.Labels {
+			labels[i] = string(label)
+		}
+
+		mirrored, _, err = target.NewBugRawWithFields(author, create.GetUnixTime(),
+			create.Title, create.Message, labels, create.Fields, create.Files,
+			map[string]string{
+				MirrorOriginMetadataKey: create.Id().String(),
+				MirrorBugMetadataKey:    b.Id().String(),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Status = MirrorStatusNew
+		result.Operations++
+	}
+
+	result.Target = mirrored.Id()
+
+	index := mirroredOperations(mirrored)
+
+	for _, op := range ops {
+		if _, ok := index.resolve(op); ok {
+			continue
+		}
+
+		copied, err := c.mirrorOperation(target, mirrored, index, ops, op)
+		if err != nil {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("operation %s not mirrored: %v", op.Id().Human(), err))
+			continue
+		}
+		if copied == nil {
+			continue
+		}
+
+		index[op.Id()] = copied.Id()
+		result.Operations++
+	}
+
+	if result.Operations == 0 {
+		result.Status = MirrorStatusNothing
+		return result, nil
+	}
+
+	return result, mirrored.CommitAsNeeded()
+}
+
+// resolveMirroredBug find the bug mirroring the given one, or mirrored by it,
+// or return nil if there is none
+func (c *RepoCache) resolveMirroredBug(b *BugCache) (*BugCache, error) {
+	mirrored, err := c.ResolveBugCreateMetadata(MirrorBugMetadataKey, b.Id().String())
+	if err == nil {
+		return mirrored, nil
+	}
+	if entity.IsErrMultipleMatch(err) {
+		return nil, err
+	}
+
+	if origin, ok := b.Snapshot().Operations[0].GetMetadata(MirrorBugMetadataKey); ok {
+		mirrored, err := c.ResolveBug(entity.Id(origin))
+		if err == nil {
+			return mirrored, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// mirrorIndex map the id of the operations of a bug to the id of the
+// matching operations of its mirror
+type mirrorIndex map[entity.Id]entity.Id
+
+func mirroredOperations(b *BugCache) mirrorIndex {
+	index := make(mirrorIndex)
+
+	for _, op := range b.Snapshot().Operations {
+		index[op.Id()] = op.Id()
+		if origin, ok := op.GetMetadata(MirrorOriginMetadataKey); ok {
+			index[entity.Id(origin)] = op.Id()
+		}
+	}
+
+	return index
+}
+
+// resolve return the id of the operation matching the given one in the
+// mirror, if any
+func (index mirrorIndex) resolve(op bug.Operation) (entity.Id, bool) {
+	if origin, ok := op.GetMetadata(MirrorOriginMetadataKey); ok {
+		if id, ok := index[entity.Id(origin)]; ok {
+			return id, true
+		}
+	}
+
+	id, ok := index[op.Id()]
+	return id, ok
+}
+
+func (index mirrorIndex) resolveId(ops []bug.Operation, id entity.Id) (entity.Id, error) {
+	for _, op := range ops {
+		if op.Id() == id {
+			if mirrored, ok := index.resolve(op); ok {
+				return mirrored, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("operation %s is not mirrored", id.Human())
+}
+
+// mirrorOperation copy an operation, one of the given operations of a bug, to
+// the mirrored bug, or return nil if the operation is not meant to be mirrored
+func (c *RepoCache) mirrorOperation(target *RepoCache, mirrored *BugCache, index mirrorIndex, ops []bug.Operation, op bug.Operation) (bug.Operation, error) {
+	author, err := target.ResolveIdentity(op.GetAuthor().Id())
+	if err != nil {
+		return nil, err
+	}
+
+	unixTime := op.GetUnixTime()
+	metadata := map[string]string{
+		MirrorOriginMetadataKey: op.Id().String(),
+	}
+
+	switch op := op.(type) {
+	case *bug.AddCommentOperation:
+		if op.ReplyTo == "" {
+			return mirrored.AddCommentRaw(author, unixTime, op.Message, op.Files, metadata)
+		}
+		replyTo, err := index.resolveId(ops, op.ReplyTo)
+		if err != nil {
+			return nil, err
+		}
+		return mirrored.AddCommentReplyRaw(author, unixTime, replyTo, op.Message, op.Files, metadata)
+
+	case *bug.EditCommentOperation:
+		target, err := index.resolveId(ops, op.Target)
+		if err != nil {
+			return nil, err
+		}
+		return mirrored.EditCommentRaw(author, unixTime, target, op.Message, metadata)
+
+	case *bug.LabelChangeOperation:
+		return mirrored.ForceChangeLabelsRaw(author, unixTime, labelStrings(op.Added), labelStrings(op.Removed), metadata)
+
+	case *bug.SetStatusOperation:
+		if op.Status == bug.OpenStatus {
+			return mirrored.OpenRaw(author, unixTime, metadata)
+		}
+		return mirrored.CloseWithResolutionRaw(author, unixTime, op.Resolution, metadata)
+
+	case *bug.SetTitleOperation:
+		return mirrored.SetTitleRaw(author, unixTime, op.Title, metadata)
+
+	case *bug.SetComponentOperation:
+		return mirrored.SetComponentRaw(author, unixTime, op.Component, metadata)
+
+	case *bug.AssigneeChangeOperation:
+		added, err := target.resolveIdentities(op.Added)
+		if err != nil {
+			return nil, err
+		}
+		removed, err := target.resolveIdentities(op.Removed)
+		if err != nil {
+			return nil, err
+		}
+		return mirrored.ChangeAssigneesRaw(author, unixTime, added, removed, metadata)
+
+	case *bug.RequestReviewOperation:
+		reviewers, err := target.resolveIdentities(op.Reviewers)
+		if err != nil {
+			return nil, err
+		}
+		return mirrored.RequestReviewRaw(author, unixTime, reviewers, op.Message, metadata)
+
+	case *bug.MarkDuplicateOperation:
+		canonical, err := c.resolveMirroredBugId(target, op.Target)
+		if err != nil {
+			return nil, err
+		}
+		return mirrored.MarkDuplicateRaw(author, unixTime, canonical, metadata)
+
+	case *bug.SetParentOperation:
+		parent, err := c.resolveMirroredBugId(target, op.Parent)
+		if err != nil {
+			return nil, err
+		}
+		return mirrored.SetParentRaw(author, unixTime, parent, metadata)
+
+	case *bug.RemoveParentOperation:
+		return mirrored.RemoveParentRaw(author, unixTime, metadata)
+
+	case *bug.VoteOperation:
+		return mirrored.VoteRaw(author, unixTime, op.Retract, metadata)
+
+	case *bug.AddChecklistItemOperation:
+		return mirrored.AddChecklistItemRaw(author, unixTime, op.Text, metadata)
+
+	case *bug.CheckItemOperation:
+		item, err := index.resolveId(ops, op.Target)
+		if err != nil {
+			return nil, err
+		}
+		return mirrored.CheckItemRaw(author, unixTime, item, op.Checked, metadata)
+
+	case *bug.CustomOperation:
+		if op.Payload == nil {
+			return nil, fmt.Errorf("unknown custom operation %s", op.Tag)
+		}
+		return mirrored.AddCustomOperationRaw(author, unixTime, op.Tag, op.Payload, metadata)
+
+	default:
+		// the metadata being specific to a repository, the
+		// SetMetadataOperation are not mirrored, no more than the NoOp and
+		// the unknown operations
+		return nil, nil
+	}
+}
+
+// resolveMirroredBugId return the id of the bug of the target repository
+// matching a bug of this repository
+func (c *RepoCache) resolveMirroredBugId(target *RepoCache, id entity.Id) (entity.Id, error) {
+	if _, err := target.ResolveBugExcerpt(id); err == nil {
+		return id, nil
+	}
+
+	b, err := c.ResolveBug(id)
+	if err != nil {
+		return "", err
+	}
+
+	mirrored, err := target.resolveMirroredBug(b)
+	if err != nil {
+		return "", err
+	}
+	if mirrored == nil {
+		return "", fmt.Errorf("bug %s is not mirrored", id.Human())
+	}
+
+	return mirrored.Id(), nil
+}
+
+func (c *RepoCache) resolveIdentities(identities []identity.Interface) ([]*IdentityCache, error) {
+	result := make([]*IdentityCache, len(identities))
+	for i, id := range identities {
+		cached, err := c.ResolveIdentity(id.Id())
+		if err != nil {
+			return nil, err
+		}
+		result[i] = cached
+	}
+	return result, nil
+}
+
+// mirrorIdentities copy to the target repository the identities involved in
+// the operations and missing there, keeping their id
+func (c *RepoCache) mirrorIdentities(target *RepoCache, ops []bug.Operation) error {
+	var missing []entity.Id
+	for _, id := range bug.IdentitiesOf(ops) {
+		if _, err := target.ResolveIdentityExcerpt(id); err != nil {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	bundle, err := c.ExportIdentities(missing)
+	if err != nil {
+		return err
+	}
+
+	_, err = target.ImportIdentities(bundle)
+	return err
+}
+
+// mirrorFiles copy to the target repository the files attached to the
+// operations
+func (c *RepoCache) mirrorFiles(target *RepoCache, ops []bug.Operation) error {
+	for _, op := range ops {
+		for _, hash := range op.GetFiles() {
+			data, err := c.repo.ReadData(hash)
+			if err != nil {
+				return err
+			}
+
+			// the hash only depend on the content
+			stored, err := target.repo.StoreData(data)
+			if err != nil {
+				return err
+			}
+			if stored != hash {
+				return fmt.Errorf("file %s stored as %s", hash, stored)
+			}
+		}
+	}
+
+	return nil
+}
+
+func labelStrings(labels []bug.Label) []string {
+	result := make([]string, len(labels))
+	for i, label := range labels {
+		result[i] = string(label)
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestMirror(t *testing.T) {
+	repoA := repository.CreateTestRepo(false)
+	repoB := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repoA, repoB)
+
+	cacheA, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+	cacheB, err := NewRepoCache(repoB)
+	require.NoError(t, err)
+
+	rene, err := cacheA.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cacheA.SetUserIdentity(rene))
+
+	public, _, err := cacheA.NewBug("public", "message")
+	require.NoError(t, err)
+	_, _, err = public.ChangeLabels([]string{"public"}, nil)
+	require.NoError(t, err)
+	comment, err := public.AddComment("comment")
+	require.NoError(t, err)
+	_, err = public.AddCommentReply(comment.Id(), "reply", nil)
+	require.NoError(t, err)
+	item, err := public.AddChecklistItem("write the tests")
+	require.NoError(t, err)
+	_, err = public.CheckItem(item.Id(), true)
+	require.NoError(t, err)
+	require.NoError(t, public.Commit())
+
+	_, _, err = cacheA.NewBug("internal", "message")
+	require.NoError(t, err)
+
+	query, err := ParseQuery("label:public")
+	require.NoError(t, err)
+
+	results, err := cacheA.MirrorTo(cacheB, query)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, MirrorStatusNew, results[0].Status)
+	require.Empty(t, results[0].Warnings)
+	require.Len(t, cacheB.AllBugsIds(), 1)
+
+	// the authors keep their identity
+	_, err = cacheB.ResolveIdentity(rene.Id())
+	require.NoError(t, err)
+
+	mirrored, err := cacheB.ResolveBugCreateMetadata(MirrorBugMetadataKey, public.Id().String())
+	require.NoError(t, err)
+	require.Equal(t, results[0].Target, mirrored.Id())
+
+	snap := mirrored.Snapshot()
+	require.Equal(t, "public", snap.Title)
+	require.Equal(t, []bug.Label{"public"}, snap.Labels)
+	threads := snap.Threads()
+	require.Len(t, threads, 2)
+	require.Len(t, threads[1].Replies, 1)
+	require.Equal(t, "reply", threads[1].Replies[0].Comment.Message)
+	require.Len(t, snap.Checklist, 1)
+	require.True(t, snap.Checklist[0].Checked)
+
+	// nothing new, in either direction
+	results, err = cacheA.MirrorTo(cacheB, query)
+	require.NoError(t, err)
+	require.Equal(t, MirrorStatusNothing, results[0].Status)
+	results, err = cacheB.MirrorTo(cacheA, nil)
+	require.NoError(t, err)
+	require.Equal(t, MirrorStatusNothing, results[0].Status)
+
+	// a change on the mirror come back to the original bug, once
+	descartes, err := cacheB.ResolveIdentity(rene.Id())
+	require.NoError(t, err)
+	require.NoError(t, cacheB.SetUserIdentity(descartes))
+	_, err = mirrored.AddComment("from the mirror")
+	require.NoError(t, err)
+	require.NoError(t, mirrored.Commit())
+
+	results, err = cacheB.MirrorTo(cacheA, nil)
+	require.NoError(t, err)
+	require.Equal(t, MirrorStatusUpdated, results[0].Status)
+	require.Equal(t, 1, results[0].Operations)
+	comments := public.Snapshot().Comments
+	require.Equal(t, "from the mirror", comments[len(comments)-1].Message)
+
+	count := len(mirrored.Snapshot().Operations)
+	results, err = cacheA.MirrorTo(cacheB, query)
+	require.NoError(t, err)
+	require.Equal(t, MirrorStatusNothing, results[0].Status)
+	require.Len(t, mirrored.Snapshot().Operations, count)
+
+	require.NoError(t, cacheA.Close())
+	require.NoError(t, cacheB.Close())
+}
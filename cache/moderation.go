@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/moderation"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// BlockIdentity add an identity to the blocklist, as the current user. The
+// operations of this identity are ignored from now on, and the bugs it created
+// are hidden.
+func (c *RepoCache) BlockIdentity(id entity.Id, reason string) error {
+	author, err := c.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	return c.updateBlocklist(func(b *moderation.Blocklist) error {
+		return b.Block(author.Identity, time.Now().Unix(), id, reason)
+	})
+}
+
+// UnblockIdentity remove an identity from the blocklist, as the current user.
+func (c *RepoCache) UnblockIdentity(id entity.Id) error {
+	author, err := c.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	return c.updateBlocklist(func(b *moderation.Blocklist) error {
+		return b.Unblock(author.Identity, time.Now().Unix(), id)
+	})
+}
+
+// BlocklistEntries return the honored entries of the blocklist
+func (c *RepoCache) BlocklistEntries() []moderation.Entry {
+	c.muBlocklist.RLock()
+	defer c.muBlocklist.RUnlock()
+
+	return c.blocklist.Entries()
+}
+
+// IsBlocklistSigned tell if the honored entries of the blocklist are all
+// signed, and so can be merged by the other repositories
+func (c *RepoCache) IsBlocklistSigned() bool {
+	c.muBlocklist.RLock()
+	defer c.muBlocklist.RUnlock()
+
+	return c.blocklist.IsSigned()
+}
+
+// IsBlocked tell if an identity is in the blocklist
+func (c *RepoCache) IsBlocked(id entity.Id) bool {
+	return c.isBlocked(id)
+}
+
+func (c *RepoCache) updateBlocklist(f func(b *moderation.Blocklist) error) error {
+	c.muBlocklist.Lock()
+	err := f(c.blocklist)
+	if err == nil {
+		err = c.blocklist.Commit(c.repo)
+	}
+	c.muBlocklist.Unlock()
+
+	if err != nil {
+		// drop the uncommitted change
+		_ = c.reloadBlocklist()
+		return err
+	}
+
+	return c.rebuildBugExcerpts()
+}
+
+// reloadBlocklist read again the blocklist from git and recompile the bugs
+// accordingly
+func (c *RepoCache) reloadBlocklist() error {
+	blocklist, err := moderation.Read(c.repo)
+	if err != nil {
+		return err
+	}
+
+	c.muBlocklist.Lock()
+	c.blocklist = blocklist
+	c.muBlocklist.Unlock()
+
+	return c.rebuildBugExcerpts()
+}
+
+// rebuildBugExcerpts compile again all the bugs, after a change of the
+// blocklist
+func (c *RepoCache) rebuildBugExcerpts() error {
+	c.muBug.Lock()
+	for _, b := range c.bugs {
+		b.bug.ResetSnapshot()
+	}
+	err := c.buildBugExcerpts()
+	c.muBug.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return c.writeBugCache()
+}
+
+func (c *RepoCache) blocklistCommit() git.Hash {
+	c.muBlocklist.RLock()
+	defer c.muBlocklist.RUnlock()
+
+	return c.blocklist.LastCommit()
+}
+
+func (c *RepoCache) isBlocked(id entity.Id) bool {
+	c.muBlocklist.RLock()
+	defer c.muBlocklist.RUnlock()
+
+	return c.blocklist.IsBlocked(id)
+}
+
+// operationFilter return a filter dropping the operations of the blocked
+// identities
+func (c *RepoCache) operationFilter() bug.OperationFilter {
+	return func(op bug.Operation) bool {
+		return !c.isBlocked(op.GetAuthor().Id())
+	}
+}
+
+// updateBugExcerpt compile the excerpt of a bug, or drop it if the bug has been
+// created by a blocked identity, and update the roll-up of its parents
+// muBug must be held
+func (c *RepoCache) updateBugExcerpt(b *bug.Bug, snap *bug.Snapshot) {
+	old := c.bugExcerpts[b.Id()]
+	excerpt := c.compileBugExcerpt(b, snap)
+
+	if old != nil && (excerpt == nil || old.Parent != excerpt.Parent) {
+		c.rollUpChildren(old.Parent)
+	}
+	if excerpt != nil {
+		c.rollUpChildren(excerpt.Parent)
+	}
+}
+
+// compileBugExcerpt compile the excerpt of a bug, keeping its roll-up, or
+// drop it if the bug has been created by a blocked identity. The roll-up of
+// its parent is not updated.
+// muBug must be held
+func (c *RepoCache) compileBugExcerpt(b *bug.Bug, snap *bug.Snapshot) *BugExcerpt {
+	if c.isBlocked(b.FirstOp().GetAuthor().Id()) {
+		delete(c.bugExcerpts, b.Id())
+		c.searchIndex.remove(b.Id())
+		return nil
+	}
+
+	excerpt := NewBugExcerpt(b, snap)
+	if old, ok := c.bugExcerpts[b.Id()]; ok {
+		excerpt.Children = old.Children
+		excerpt.ChildrenClosed = old.ChildrenClosed
+	}
+	c.bugExcerpts[b.Id()] = excerpt
+	c.searchIndex.update(excerpt)
+
+	return excerpt
+}
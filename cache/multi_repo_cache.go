@@ -2,47 +2,53 @@ package cache
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/repository"
 )
 
 const lockfile = "lock"
 
 // MultiRepoCache is the root cache, holding multiple RepoCache.
+// The repositories can be registered and resolved concurrently.
 type MultiRepoCache struct {
+	mu    *sync.RWMutex
 	repos map[string]*RepoCache
 }
 
 func NewMultiRepoCache() MultiRepoCache {
 	return MultiRepoCache{
+		mu:    &sync.RWMutex{},
 		repos: make(map[string]*RepoCache),
 	}
 }
 
 // RegisterRepository register a named repository. Use this for multi-repo setup
 func (c *MultiRepoCache) RegisterRepository(ref string, repo repository.ClockedRepo) error {
-	r, err := NewRepoCache(repo)
+	r, err := NewNamedRepoCache(repo, ref)
 	if err != nil {
 		return err
 	}
 
+	c.mu.Lock()
 	c.repos[ref] = r
+	c.mu.Unlock()
 	return nil
 }
 
 // RegisterDefaultRepository register a unnamed repository. Use this for mono-repo setup
 func (c *MultiRepoCache) RegisterDefaultRepository(repo repository.ClockedRepo) error {
-	r, err := NewRepoCache(repo)
-	if err != nil {
-		return err
-	}
-
-	c.repos[""] = r
-	return nil
+	return c.RegisterRepository("", repo)
 }
 
 // DefaultRepo retrieve the default repository
 func (c *MultiRepoCache) DefaultRepo() (*RepoCache, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if len(c.repos) != 1 {
 		return nil, fmt.Errorf("repository is not unique")
 	}
@@ -56,6 +62,9 @@ func (c *MultiRepoCache) DefaultRepo() (*RepoCache, error) {
 
 // ResolveRepo retrieve a repository with a reference
 func (c *MultiRepoCache) ResolveRepo(ref string) (*RepoCache, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	r, ok := c.repos[ref]
 	if !ok {
 		return nil, fmt.Errorf("unknown repo")
@@ -63,8 +72,168 @@ func (c *MultiRepoCache) ResolveRepo(ref string) (*RepoCache, error) {
 	return r, nil
 }
 
+// AllRepos return the registered repositories, ordered by name
+func (c *MultiRepoCache) AllRepos() []*RepoCache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*RepoCache, 0, len(c.repos))
+	for _, r := range c.repos {
+		result = append(result, r)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+
+	return result
+}
+
+// RepoBug designate a bug in one of the repositories of a MultiRepoCache
+type RepoBug struct {
+	Repo *RepoCache
+	Id   entity.Id
+}
+
+// QualifiedBugId return the id of a bug qualified by the name of its
+// repository, as "<repo>/<human id>", to designate it among several
+// repositories. The bugs of the unnamed default repository are not qualified.
+func QualifiedBugId(repo *RepoCache, id entity.Id) string {
+	if repo.Name() == "" {
+		return id.Human()
+	}
+	return repo.Name() + "/" + id.Human()
+}
+
+// QueryBugs return the bugs of all the repositories matching a query, in
+// the order asked by the query, for a combined view of the repositories.
+//
+// The logical clocks of different repositories can't be compared: the bugs
+// of each repository keep their order, and are merged with the bugs of the
+// other repositories according to their timestamp.
+func (c *MultiRepoCache) QueryBugs(query *Query) []RepoBug {
+	if query == nil {
+		query = NewQuery()
+	}
+
+	repos := c.AllRepos()
+	lists := make([][]*BugExcerpt, len(repos))
+
+	for i, r := range repos {
+		r.muBug.RLock()
+		lists[i] = filterExcerpts(r.bugExcerpts, query, r)
+		r.muBug.RUnlock()
+
+		sortExcerpts(lists[i], query)
+	}
+
+	var before func(a, b *BugExcerpt) bool
+	switch query.OrderBy {
+	case OrderById:
+		before = func(a, b *BugExcerpt) bool { return a.Id < b.Id }
+	case OrderByCreation:
+		before = func(a, b *BugExcerpt) bool { return a.CreateUnixTime < b.CreateUnixTime }
+	case OrderByEdit:
+		before = func(a, b *BugExcerpt) bool { return a.EditUnixTime < b.EditUnixTime }
+	case OrderByVotes:
+		before = func(a, b *BugExcerpt) bool {
+			if a.Votes != b.Votes {
+				return a.Votes < b.Votes
+			}
+			return a.CreateUnixTime > b.CreateUnixTime
+		}
+	case OrderByField:
+		field := excerptFields[query.OrderField]
+		before = func(a, b *BugExcerpt) bool {
+			if c := field.compare(a, b); c != 0 {
+				return c < 0
+			}
+			return a.CreateUnixTime < b.CreateUnixTime
+		}
+	default:
+		panic("missing sort type")
+	}
+
+	if query.OrderDirection == OrderDescending {
+		ascending := before
+		before = func(a, b *BugExcerpt) bool { return ascending(b, a) }
+	}
+
+	var result []RepoBug
+
+	for {
+		next := -1
+		for i, list := range lists {
+			if len(list) == 0 {
+				continue
+			}
+			if next < 0 || before(list[0], lists[next][0]) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return result
+		}
+
+		result = append(result, RepoBug{Repo: repos[next], Id: lists[next][0].Id})
+		lists[next] = lists[next][1:]
+	}
+}
+
+// SearchBugsPrefix return the bugs of all the repositories matching a text
+// typed so far, as RepoCache.SearchBugsPrefix does, best match first
+func (c *MultiRepoCache) SearchBugsPrefix(text string, limit int) []RepoBug {
+	var matches []prefixMatch
+	for _, r := range c.AllRepos() {
+		matches = append(matches, r.searchBugsPrefix(text)...)
+	}
+
+	sortPrefixMatches(matches)
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]RepoBug, len(matches))
+	for i, m := range matches {
+		result[i] = RepoBug{Repo: m.repo, Id: m.excerpt.Id}
+	}
+
+	return result
+}
+
+// ResolveBugExcerptQualified retrieve a bug excerpt and its repository with a
+// qualified id, as given by QualifiedBugId. The id can be a prefix.
+func (c *MultiRepoCache) ResolveBugExcerptQualified(qualifiedId string) (*RepoCache, *BugExcerpt, error) {
+	var repo *RepoCache
+	var err error
+
+	// the ids are hexadecimal, the repository name is everything before
+	// the last slash
+	prefix := qualifiedId
+	if i := strings.LastIndex(qualifiedId, "/"); i >= 0 {
+		prefix = qualifiedId[i+1:]
+		repo, err = c.ResolveRepo(qualifiedId[:i])
+	} else {
+		repo, err = c.DefaultRepo()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	excerpt, err := repo.ResolveBugExcerptPrefix(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return repo, excerpt, nil
+}
+
 // Close will do anything that is needed to close the cache properly
 func (c *MultiRepoCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for _, cachedRepo := range c.repos {
 		err := cachedRepo.Close()
 		if err != nil {
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/numbering"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// if true, the bugs get a sequential number when pushed, to refer to them as
+// #123 in addition to their id
+const numberingConfigKey = "git-bug.numbering"
+
+// NumberingEnabled tell if the bugs of the repository get sequential numbers
+func (c *RepoCache) NumberingEnabled() (bool, error) {
+	enabled, err := c.repo.LocalConfig().ReadBool(numberingConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		return false, nil
+	}
+	return enabled, err
+}
+
+// BugNumber return the sequential number of a bug, if allocated
+func (c *RepoCache) BugNumber(id entity.Id) (int, bool) {
+	c.muNumbers.RLock()
+	defer c.muNumbers.RUnlock()
+
+	return c.numbers.Number(id)
+}
+
+// HasBugNumbers tell if some bugs have a sequential number
+func (c *RepoCache) HasBugNumbers() bool {
+	c.muNumbers.RLock()
+	defer c.muNumbers.RUnlock()
+
+	return c.numbers.Len() > 0
+}
+
+// AllocateBugNumbers give a sequential number to the bugs without one, in the
+// order of their creation, and return the count of allocated numbers. The
+// numbers are only allocated when pushing, once merged with the ones of the
+// remote, so that the published numbers never change.
+func (c *RepoCache) AllocateBugNumbers() (int, error) {
+	c.muBug.RLock()
+	excerpts := make([]*BugExcerpt, 0, len(c.bugExcerpts))
+	for _, excerpt := range c.bugExcerpts {
+		excerpts = append(excerpts, excerpt)
+	}
+	c.muBug.RUnlock()
+
+	sort.Slice(excerpts, func(i, j int) bool {
+		if excerpts[i].CreateLamportTime != excerpts[j].CreateLamportTime {
+			return excerpts[i].CreateLamportTime < excerpts[j].CreateLamportTime
+		}
+		return excerpts[i].Id < excerpts[j].Id
+	})
+
+	ids := make([]entity.Id, len(excerpts))
+	for i, excerpt := range excerpts {
+		ids[i] = excerpt.Id
+	}
+
+	c.muNumbers.Lock()
+	defer c.muNumbers.Unlock()
+
+	count := c.numbers.Allocate(ids)
+	if count == 0 {
+		return 0, nil
+	}
+
+	err := c.numbers.Commit(c.repo)
+	if err != nil {
+		// drop the uncommitted numbers
+		numbers, readErr := numbering.Read(c.repo)
+		if readErr == nil {
+			c.numbers = numbers
+		}
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// allocateBugNumbersForPush merge the numbers of a remote and allocate the
+// missing ones, if the numbering is enabled, before pushing
+func (c *RepoCache) allocateBugNumbersForPush(remote string) (string, error) {
+	enabled, err := c.NumberingEnabled()
+	if err != nil || !enabled {
+		return "", err
+	}
+
+	stdout, err := numbering.Fetch(c.repo, remote)
+	if err != nil {
+		return stdout, err
+	}
+
+	_, err = numbering.Merge(c.repo, remote)
+	if err != nil {
+		return stdout, err
+	}
+
+	err = c.reloadNumbers()
+	if err != nil {
+		return stdout, err
+	}
+
+	_, err = c.AllocateBugNumbers()
+	return stdout, err
+}
+
+// numberedBug return the bug of a reference as #123, if it is one
+func (c *RepoCache) numberedBug(ref string) (entity.Id, bool, error) {
+	number, ok := numbering.Parse(ref)
+	if !ok {
+		return entity.UnsetId, false, nil
+	}
+
+	c.muNumbers.RLock()
+	id, ok := c.numbers.Id(number)
+	c.muNumbers.RUnlock()
+
+	if !ok {
+		return entity.UnsetId, true, bug.ErrBugNotExist
+	}
+
+	return id, true, nil
+}
+
+func (c *RepoCache) reloadNumbers() error {
+	numbers, err := numbering.Read(c.repo)
+	if err != nil {
+		return err
+	}
+
+	c.muNumbers.Lock()
+	c.numbers = numbers
+	c.muNumbers.Unlock()
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// OutboxEntry is a bug with local operations that a remote doesn't have yet
+type OutboxEntry struct {
+	Id    entity.Id
+	Title string
+	// the remote doesn't have the bug at all
+	New        bool
+	Operations []bug.Operation
+}
+
+// Outbox return the bugs with committed operations not published yet on a
+// remote, ordered by creation. The remote is known from its remote-tracking
+// references, as of the last fetch or push.
+func (c *RepoCache) Outbox(remote string) ([]OutboxEntry, error) {
+	c.muBug.RLock()
+	excerpts := make([]*BugExcerpt, 0, len(c.bugExcerpts))
+	for _, excerpt := range c.bugExcerpts {
+		excerpts = append(excerpts, excerpt)
+	}
+	c.muBug.RUnlock()
+
+	sort.Sort(BugsByCreationTime(excerpts))
+
+	var result []OutboxEntry
+
+	for _, excerpt := range excerpts {
+		b, err := c.ResolveBug(excerpt.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		ops, err := b.bug.UnpublishedOperations(c.repo, remote)
+		if err != nil {
+			return nil, err
+		}
+		if len(ops) == 0 {
+			continue
+		}
+
+		result = append(result, OutboxEntry{
+			Id:         excerpt.Id,
+			Title:      excerpt.Title,
+			New:        len(ops) == len(b.bug.CommittedOperations()),
+			Operations: ops,
+		})
+	}
+
+	return result, nil
+}
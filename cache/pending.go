@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// untrustedRemotesConfigKey is the repository configuration listing the
+// remotes whose bugs are held for review when pulled, separated by commas or
+// spaces
+const untrustedRemotesConfigKey = "git-bug.untrusted-remotes"
+
+// IsUntrustedRemote tell if the bugs pulled from the remote are held for
+// review instead of being merged
+func (c *RepoCache) IsUntrustedRemote(remote string) (bool, error) {
+	remotes, err := readConfigList(c.repo.LocalConfig(), untrustedRemotesConfigKey)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range remotes {
+		if r == remote {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// PendingBugs return the bugs held for review
+func (c *RepoCache) PendingBugs() ([]bug.PendingBug, error) {
+	return bug.ListPending(c.repo)
+}
+
+// ReadPendingBug read the pending version of a bug, with the operations it
+// brings
+func (c *RepoCache) ReadPendingBug(pending bug.PendingBug) (*bug.Bug, []bug.Operation, error) {
+	b, err := pending.Read(c.repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ops, err := bug.UnmergedOperations(c.repo, b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b, ops, nil
+}
+
+// ResolvePendingPrefix find the pending bug matching a bug id prefix, from
+// the given remote if not empty
+func (c *RepoCache) ResolvePendingPrefix(prefix string, remote string) (bug.PendingBug, error) {
+	pendings, err := c.PendingBugs()
+	if err != nil {
+		return bug.PendingBug{}, err
+	}
+
+	var matching []bug.PendingBug
+	for _, p := range pendings {
+		if remote != "" && p.Remote != remote {
+			continue
+		}
+		if p.Id.HasPrefix(prefix) {
+			matching = append(matching, p)
+		}
+	}
+
+	switch len(matching) {
+	case 0:
+		return bug.PendingBug{}, fmt.Errorf("no bug matching %s is pending review", prefix)
+	case 1:
+		return matching[0], nil
+	}
+
+	seen := make(map[entity.Id]bool)
+	var ids []entity.Id
+	var remotes []string
+	for _, p := range matching {
+		if !seen[p.Id] {
+			seen[p.Id] = true
+			ids = append(ids, p.Id)
+		}
+		remotes = append(remotes, p.Remote)
+	}
+
+	// the same bug can be pending from several remotes
+	if len(ids) == 1 {
+		return bug.PendingBug{}, fmt.Errorf("the bug %s is pending from several remotes (%s), choose one",
+			ids[0].Human(), strings.Join(remotes, ", "))
+	}
+
+	return bug.PendingBug{}, entity.NewErrMultipleMatch("bug", ids)
+}
+
+// AcceptPending merge a pending bug into the local bugs, and update the cache
+// accordingly
+func (c *RepoCache) AcceptPending(pending bug.PendingBug) (entity.MergeResult, error) {
+	rootPacks := c.copyRootPacks()
+
+	result := bug.AcceptPending(c.repo, pending, rootPacks)
+	if result.Err != nil {
+		return result, result.Err
+	}
+
+	c.setRootPacks(rootPacks)
+
+	c.updateMergedBug(result)
+
+	return result, c.write()
+}
+
+// RejectPending drop a pending bug
+func (c *RepoCache) RejectPending(pending bug.PendingBug) error {
+	return bug.RejectPending(c.repo, pending)
+}
@@ -0,0 +1,365 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// The policies of a repository, rules checked before committing the changes
+// of the user, are configured in git:
+//
+//	git-bug.policies                 the rules, separated by commas or spaces
+//	git-bug.policy.<name>.on         the change the rule apply to, as "close"
+//	git-bug.policy.<name>.label      if set, the rule only apply to the bugs having this label
+//	git-bug.policy.<name>.require    "comment" to allow the change along a comment, the change is forbidden otherwise
+//	git-bug.policy.<name>.message    the error reported when the rule is broken, if any
+//	git-bug.policy-hook              an executable run before committing, refusing the changes when failing
+//
+// The hook is run from the root of the working tree, with the changed bug and
+// the new operations given as JSON on its standard input.
+const (
+	policiesConfigKey    = "git-bug.policies"
+	policyOnKey          = "git-bug.policy.%s.on"
+	policyLabelKey       = "git-bug.policy.%s.label"
+	policyRequireKey     = "git-bug.policy.%s.require"
+	policyMessageKey     = "git-bug.policy.%s.message"
+	policyHookConfigKey  = "git-bug.policy-hook"
+	policyRequireComment = "comment"
+)
+
+// PolicyChanges are the changes a policy can apply to
+var PolicyChanges = []string{
+	"create", "title", "comment", "edit-comment", "open", "close", "label",
+	"component", "assignee", "duplicate", "parent", "checklist", "vote",
+	"review",
+}
+
+// Policy is a rule the changes of the user must follow
+type Policy struct {
+	Name string
+	// the change the rule apply to, one of PolicyChanges
+	On string
+	// if set, the rule only apply to the bugs having this label, before or
+	// after the change
+	Label string
+	// "comment" if the change is allowed along a comment, empty if it is
+	// forbidden
+	Require string
+	Message string
+}
+
+// ErrPolicy is returned when a change is refused by a policy
+type ErrPolicy struct {
+	Policy  string
+	Message string
+}
+
+func (e ErrPolicy) Error() string {
+	return fmt.Sprintf("refused by the policy %s: %s", e.Policy, e.Message)
+}
+
+// Policies return the configured policies
+func (c *RepoCache) Policies() ([]Policy, error) {
+	config := c.repo.LocalConfig()
+
+	names, err := readConfigList(config, policiesConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Policy, 0, len(names))
+
+	for _, name := range names {
+		p := Policy{Name: name}
+
+		p.On, err = config.ReadString(fmt.Sprintf(policyOnKey, name))
+		if err == repository.ErrNoConfigEntry {
+			return nil, fmt.Errorf("policy %s: missing %s", name, fmt.Sprintf(policyOnKey, name))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !isPolicyChange(p.On) {
+			return nil, fmt.Errorf("policy %s: unknown change %s, valid changes are [%s]",
+				name, p.On, strings.Join(PolicyChanges, ","))
+		}
+
+		p.Label, err = config.ReadString(fmt.Sprintf(policyLabelKey, name))
+		if err != nil && err != repository.ErrNoConfigEntry {
+			return nil, err
+		}
+
+		p.Require, err = config.ReadString(fmt.Sprintf(policyRequireKey, name))
+		if err != nil && err != repository.ErrNoConfigEntry {
+			return nil, err
+		}
+		if p.Require != "" && p.Require != policyRequireComment {
+			return nil, fmt.Errorf("policy %s: invalid requirement %s, only %s is supported",
+				name, p.Require, policyRequireComment)
+		}
+
+		p.Message, err = config.ReadString(fmt.Sprintf(policyMessageKey, name))
+		if err != nil && err != repository.ErrNoConfigEntry {
+			return nil, err
+		}
+
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+// PolicyHook return the executable run before committing changes, if any
+func (c *RepoCache) PolicyHook() (string, error) {
+	hook, err := c.repo.LocalConfig().ReadString(policyHookConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		return "", nil
+	}
+	return hook, err
+}
+
+func isPolicyChange(change string) bool {
+	for _, c := range PolicyChanges {
+		if c == change {
+			return true
+		}
+	}
+	return false
+}
+
+// policyChange return the change an operation does, as named by the policies
+func policyChange(op bug.Operation) string {
+	switch op := op.(type) {
+	case *bug.CreateOperation:
+		return "create"
+	case *bug.SetTitleOperation:
+		return "title"
+	case *bug.AddCommentOperation:
+		return "comment"
+	case *bug.EditCommentOperation:
+		return "edit-comment"
+	case *bug.SetStatusOperation:
+		if op.Status == bug.ClosedStatus {
+			return "close"
+		}
+		return "open"
+	case *bug.LabelChangeOperation:
+		return "label"
+	case *bug.SetComponentOperation:
+		return "component"
+	case *bug.AssigneeChangeOperation:
+		return "assignee"
+	case *bug.MarkDuplicateOperation:
+		return "duplicate"
+	case *bug.SetParentOperation, *bug.RemoveParentOperation:
+		return "parent"
+	case *bug.AddChecklistItemOperation, *bug.CheckItemOperation:
+		return "checklist"
+	case *bug.VoteOperation:
+		return "vote"
+	case *bug.RequestReviewOperation:
+		return "review"
+	default:
+		return ""
+	}
+}
+
+// checkPolicies check the operations of the user identity among the given
+// staged operations of a bug against the content lint and the policies,
+// before committing them. The operations of the other identities, as the ones
+// imported by the bridges, are not checked.
+func (c *RepoCache) checkPolicies(b *bug.Bug, ops []bug.Operation) error {
+	policies, err := c.Policies()
+	if err != nil {
+		return err
+	}
+
+	hook, err := c.PolicyHook()
+	if err != nil {
+		return err
+	}
+
+	lint, err := c.Lint()
+	if err != nil {
+		return err
+	}
+	if c.noLint {
+		lint = Lint{}
+	}
+
+	if len(policies) == 0 && hook == "" && lint.IsEmpty() {
+		return nil
+	}
+
+	isSet, err := c.IsUserIdentitySet()
+	if err != nil {
+		return err
+	}
+	if !isSet {
+		return nil
+	}
+
+	user, err := c.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	var staged []bug.Operation
+	for _, op := range ops {
+		if op.GetAuthor().Id() == user.Id() {
+			staged = append(staged, op)
+		}
+	}
+
+	if len(staged) == 0 {
+		return nil
+	}
+
+	err = lint.check(staged)
+	if err != nil {
+		return err
+	}
+
+	err = checkPolicyRules(policies, b, staged)
+	if err != nil {
+		return err
+	}
+
+	if hook == "" {
+		return nil
+	}
+
+	return c.runPolicyHook(hook, b, staged)
+}
+
+func checkPolicyRules(policies []Policy, b *bug.Bug, staged []bug.Operation) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	commented := false
+	for _, op := range staged {
+		if _, ok := op.(*bug.AddCommentOperation); ok {
+			commented = true
+		}
+	}
+
+	// the bug before the changes, each change being checked against the
+	// state it applies to
+	snap := bug.Snapshot{Status: bug.OpenStatus}
+	for _, op := range b.CommittedOperations() {
+		op.Apply(&snap)
+	}
+
+	isStaged := make(map[bug.Operation]bool, len(staged))
+	for _, op := range staged {
+		isStaged[op] = true
+	}
+
+	for _, op := range b.StagedOperations() {
+		before := snap.Labels
+		op.Apply(&snap)
+
+		if !isStaged[op] {
+			continue
+		}
+
+		for _, p := range policies {
+			if p.On != policyChange(op) {
+				continue
+			}
+			if p.Label != "" && !hasLabel(before, p.Label) && !hasLabel(snap.Labels, p.Label) {
+				continue
+			}
+			if p.Require == policyRequireComment && commented {
+				continue
+			}
+			return ErrPolicy{Policy: p.Name, Message: p.describe()}
+		}
+	}
+
+	return nil
+}
+
+func hasLabel(labels []bug.Label, label string) bool {
+	for _, l := range labels {
+		if string(l) == label {
+			return true
+		}
+	}
+	return false
+}
+
+// describe tell what the policy forbid, unless configured with a message
+func (p Policy) describe() string {
+	if p.Message != "" {
+		return p.Message
+	}
+
+	var msg string
+	if p.Require == policyRequireComment {
+		msg = fmt.Sprintf("a comment is required with the change %s", p.On)
+	} else {
+		msg = fmt.Sprintf("the change %s is forbidden", p.On)
+	}
+
+	if p.Label != "" {
+		msg += fmt.Sprintf(" on the bugs labeled %s", p.Label)
+	}
+
+	return msg
+}
+
+// runPolicyHook run the policy hook with the bug as it would be after the
+// changes and the new operations given as JSON on the standard input, the
+// changes being refused if it fails
+func (c *RepoCache) runPolicyHook(hook string, b *bug.Bug, staged []bug.Operation) error {
+	snap := b.Compile()
+
+	data, err := json.Marshal(struct {
+		Bug        *bug.Snapshot   `json:"bug"`
+		Operations []bug.Operation `json:"operations"`
+	}{
+		Bug:        &snap,
+		Operations: staged,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the hook is run from the root of the working tree, as the git hooks
+	root := filepath.Dir(c.repo.GetPath())
+	if !filepath.IsAbs(hook) && strings.ContainsRune(hook, filepath.Separator) {
+		hook = filepath.Join(root, hook)
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command(hook)
+	cmd.Dir = root
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.Env = append(os.Environ(), "GIT_BUG_ID="+snap.Id().String())
+
+	err = cmd.Run()
+	if _, ok := err.(*exec.ExitError); ok {
+		msg := strings.TrimSpace(output.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return ErrPolicy{Policy: "hook", Message: msg}
+	}
+	if err != nil {
+		return fmt.Errorf("running the policy hook %s: %v", hook, err)
+	}
+
+	return nil
+}
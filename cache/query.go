@@ -10,6 +10,9 @@ type Query struct {
 	Filters
 	OrderBy
 	OrderDirection
+
+	// the name of the ExcerptField to sort by, with OrderByField
+	OrderField string
 }
 
 // Return an identity query with default sorting (creation-desc)
@@ -36,7 +39,8 @@ func ParseQuery(query string) (*Query, error) {
 	sortingDone := false
 
 	for _, field := range fields {
-		split := strings.Split(field, ":")
+		// the value can hold a colon, as the labels "severity:high"
+		split := strings.SplitN(field, ":", 2)
 		if len(split) != 2 {
 			return nil, fmt.Errorf("can't parse \"%s\"", field)
 		}
@@ -52,6 +56,13 @@ func ParseQuery(query string) (*Query, error) {
 			}
 			result.Status = append(result.Status, f)
 
+		case "resolution":
+			f, err := ResolutionFilter(qualifierQuery)
+			if err != nil {
+				return nil, err
+			}
+			result.Resolution = append(result.Resolution, f)
+
 		case "author":
 			f := AuthorFilter(qualifierQuery)
 			result.Author = append(result.Author, f)
@@ -68,10 +79,46 @@ func ParseQuery(query string) (*Query, error) {
 			f := LabelFilter(qualifierQuery)
 			result.Label = append(result.Label, f)
 
+		case "component":
+			f := ComponentFilter(qualifierQuery)
+			result.Component = append(result.Component, f)
+
+		case "assignee":
+			f := AssigneeFilter(qualifierQuery)
+			result.Assignee = append(result.Assignee, f)
+
+		case "review":
+			f := ReviewFilter(qualifierQuery)
+			result.Review = append(result.Review, f)
+
+		case "duplicate":
+			f, err := DuplicateFilter(qualifierQuery)
+			if err != nil {
+				return nil, err
+			}
+			result.Duplicate = append(result.Duplicate, f)
+
+		case "parent":
+			f := ParentFilter(qualifierQuery)
+			result.Parent = append(result.Parent, f)
+
+		case "reopened":
+			f, err := ReopenedFilter(qualifierQuery)
+			if err != nil {
+				return nil, err
+			}
+			result.Reopened = append(result.Reopened, f)
+
 		case "title":
 			f := TitleFilter(qualifierQuery)
 			result.Title = append(result.Title, f)
 
+		case "is":
+			err := result.parseIsFilter(qualifierQuery)
+			if err != nil {
+				return nil, err
+			}
+
 		case "no":
 			err := result.parseNoFilter(qualifierQuery)
 			if err != nil {
@@ -91,7 +138,14 @@ func ParseQuery(query string) (*Query, error) {
 			sortingDone = true
 
 		default:
-			return nil, fmt.Errorf("unknown qualifier name %s", qualifierName)
+			field, ok := excerptFields[qualifierName]
+			if !ok {
+				return nil, fmt.Errorf("unknown qualifier name %s", qualifierName)
+			}
+			if result.ExcerptFields == nil {
+				result.ExcerptFields = make(map[string][]Filter)
+			}
+			result.ExcerptFields[field.Name] = append(result.ExcerptFields[field.Name], ExcerptFieldFilter(field, qualifierQuery))
 		}
 	}
 
@@ -127,12 +181,37 @@ func removeQuote(field string) string {
 	return field
 }
 
+func (q *Query) parseIsFilter(query string) error {
+	switch query {
+	case "unread":
+		q.Unread = append(q.Unread, UnreadFilter(true))
+	case "read":
+		q.Unread = append(q.Unread, UnreadFilter(false))
+	default:
+		return fmt.Errorf("unknown \"is\" filter %s", query)
+	}
+
+	return nil
+}
+
 func (q *Query) parseNoFilter(query string) error {
 	switch query {
 	case "label":
 		q.NoFilters = append(q.NoFilters, NoLabelFilter())
+	case "component":
+		q.NoFilters = append(q.NoFilters, NoComponentFilter())
+	case "assignee":
+		q.NoFilters = append(q.NoFilters, NoAssigneeFilter())
+	case "resolution":
+		q.NoFilters = append(q.NoFilters, NoResolutionFilter())
+	case "parent":
+		q.NoFilters = append(q.NoFilters, NoParentFilter())
 	default:
-		return fmt.Errorf("unknown \"no\" filter %s", query)
+		field, ok := excerptFields[query]
+		if !ok {
+			return fmt.Errorf("unknown \"no\" filter %s", query)
+		}
+		q.NoFilters = append(q.NoFilters, NoExcerptFieldFilter(field))
 	}
 
 	return nil
@@ -164,8 +243,30 @@ func (q *Query) parseSorting(query string) error {
 		q.OrderBy = OrderByEdit
 		q.OrderDirection = OrderAscending
 
+	// default DESC
+	case "votes", "votes-desc":
+		q.OrderBy = OrderByVotes
+		q.OrderDirection = OrderDescending
+	case "votes-asc":
+		q.OrderBy = OrderByVotes
+		q.OrderDirection = OrderAscending
+
+	// default ASC
 	default:
-		return fmt.Errorf("unknown sorting %s", query)
+		name, direction := query, OrderAscending
+		switch {
+		case strings.HasSuffix(query, "-asc"):
+			name = strings.TrimSuffix(query, "-asc")
+		case strings.HasSuffix(query, "-desc"):
+			name, direction = strings.TrimSuffix(query, "-desc"), OrderDescending
+		}
+
+		if _, ok := excerptFields[name]; !ok {
+			return fmt.Errorf("unknown sorting %s", query)
+		}
+		q.OrderBy = OrderByField
+		q.OrderField = name
+		q.OrderDirection = direction
 	}
 
 	return nil
@@ -24,11 +24,46 @@ func TestQueryParse(t *testing.T) {
 
 		{"label:hello", true},
 		{`label:"Good first issue"`, true},
+		{"label:severity:high", true},
+		{`label:"scan:codeql"`, true},
+
+		{"component:frontend", true},
+		{"assignee:isaac", true},
+		{"review:me", true},
+		{"no:component", true},
+		{"no:assignee", true},
+		{"no:unknown", false},
+
+		{"resolution:wontfix", true},
+		{"resolution:works-for-me", true},
+		{"resolution:unknown", false},
+		{"resolution:", false},
+		{"no:resolution", true},
+
+		{"duplicate:yes", true},
+		{"duplicate:any", true},
+		{"duplicate:maybe", false},
+
+		{"parent:abc", true},
+		{"no:parent", true},
+
+		{"reopened:>0", true},
+		{"reopened:<=2", true},
+		{"reopened:3", true},
+		{"reopened:>", false},
+		{"reopened:-1", false},
+		{"reopened:often", false},
+
+		{"is:unread", true},
+		{"is:read", true},
+		{"is:new", false},
 
 		{"title:titleOne", true},
 		{`title:"Bug titleTwo"`, true},
 
 		{"sort:edit", true},
+		{"sort:votes", true},
+		{"sort:votes-asc", true},
 		{"sort:unknown", false},
 	}
 
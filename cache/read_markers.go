@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/readmarker"
+)
+
+// IsUnread tell if a bug has been edited since the user identity read it, or
+// has never been read. Nothing is unread without a user identity.
+func (c *RepoCache) IsUnread(excerpt *BugExcerpt) bool {
+	c.muReadMarkers.Lock()
+	defer c.muReadMarkers.Unlock()
+
+	markers, err := c.userReadMarkers()
+	if err != nil {
+		return false
+	}
+
+	return markers.IsUnread(excerpt.Id, excerpt.EditLamportTime)
+}
+
+// MarkRead record that the user identity has seen all the operations of the
+// given bugs
+func (c *RepoCache) MarkRead(ids ...entity.Id) error {
+	excerpts := make([]*BugExcerpt, len(ids))
+	for i, id := range ids {
+		excerpt, err := c.ResolveBugExcerpt(id)
+		if err != nil {
+			return err
+		}
+		excerpts[i] = excerpt
+	}
+
+	return c.updateReadMarkers(func(markers *readmarker.Markers) bool {
+		changed := false
+		for _, excerpt := range excerpts {
+			changed = markers.MarkRead(excerpt.Id, excerpt.EditLamportTime) || changed
+		}
+		return changed
+	})
+}
+
+// MarkUnread forget that the user identity has read the given bugs
+func (c *RepoCache) MarkUnread(ids ...entity.Id) error {
+	return c.updateReadMarkers(func(markers *readmarker.Markers) bool {
+		changed := false
+		for _, id := range ids {
+			changed = markers.MarkUnread(id) || changed
+		}
+		return changed
+	})
+}
+
+// updateReadMarkers apply a change to the read markers of the user identity,
+// and write them if the change tell they changed
+func (c *RepoCache) updateReadMarkers(f func(markers *readmarker.Markers) bool) error {
+	c.muReadMarkers.Lock()
+	defer c.muReadMarkers.Unlock()
+
+	markers, err := c.userReadMarkers()
+	if err != nil {
+		return err
+	}
+
+	if !f(markers) {
+		return nil
+	}
+
+	err = markers.Commit(c.repo)
+	if err != nil {
+		// drop the uncommitted change
+		c.readMarkers = nil
+	}
+	return err
+}
+
+// userReadMarkers return the read markers of the user identity, loading them
+// if needed. The caller must hold muReadMarkers.
+func (c *RepoCache) userReadMarkers() (*readmarker.Markers, error) {
+	user, err := c.GetUserIdentityExcerpt()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.readMarkers != nil && c.readMarkers.IdentityId() == user.Id {
+		return c.readMarkers, nil
+	}
+
+	c.readMarkers, err = readmarker.Read(c.repo, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	return c.readMarkers, nil
+}
+
+// commitKeepRead commit the pending operations of a bug with the given
+// function. If they are all from the user identity, on a bug the user already
+// read or is creating, the bug stays read: the own edits of the user don't
+// make a bug unread.
+func (c *RepoCache) commitKeepRead(b *bug.Bug, commit func() error) error {
+	keepRead := c.isOwnEdit(b)
+
+	err := commit()
+	if err != nil {
+		return err
+	}
+
+	if !keepRead {
+		return nil
+	}
+
+	return c.updateReadMarkers(func(markers *readmarker.Markers) bool {
+		return markers.MarkRead(b.Id(), b.EditLamportTime())
+	})
+}
+
+func (c *RepoCache) isOwnEdit(b *bug.Bug) bool {
+	staged := b.StagedOperations()
+	if len(staged) == 0 {
+		return false
+	}
+
+	user, err := c.GetUserIdentityExcerpt()
+	if err != nil {
+		return false
+	}
+
+	for _, op := range staged {
+		if op.GetAuthor().Id() != user.Id {
+			return false
+		}
+	}
+
+	// a new bug
+	if len(b.CommittedOperations()) == 0 {
+		return true
+	}
+
+	c.muReadMarkers.Lock()
+	defer c.muReadMarkers.Unlock()
+
+	markers, err := c.userReadMarkers()
+	if err != nil {
+		return false
+	}
+
+	return !markers.IsUnread(b.Id(), b.EditLamportTime())
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"errors"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// linked repositories are local clones of other repositories, configured with
+// "git-bug.linked-repository.<name> = <path>", where the bugs referenced
+// from this repository can be found
+const linkedRepositoryConfigKeyPrefix = "git-bug.linked-repository"
+
+var ErrUnresolvedReference = errors.New("the repository of the referenced bug is not available locally")
+
+// ResolvedReference is a reference to a bug of another repository, with the
+// excerpt of that bug
+type ResolvedReference struct {
+	bug.Reference
+
+	// the bug is shared with this repository through one of its remotes
+	Local bool
+	// the path of the linked repository holding the bug, if not local
+	Path string
+
+	Excerpt *BugExcerpt
+}
+
+// ResolveReference find the bug designated by a reference, either in this
+// repository if the reference point to one of its remotes, or in one of the
+// linked repositories.
+func (c *RepoCache) ResolveReference(ref bug.Reference) (*ResolvedReference, error) {
+	match, err := matchRemotes(c.repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if match {
+		excerpt, err := c.ResolveBugExcerptPrefix(ref.BugId)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResolvedReference{
+			Reference: ref,
+			Local:     true,
+			Excerpt:   excerpt,
+		}, nil
+	}
+
+	paths, err := c.repo.LocalConfig().ReadAll(linkedRepositoryConfigKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		linked, err := repository.NewGitRepo(path, bug.Witnesser)
+		if err != nil {
+			return nil, err
+		}
+
+		match, err := matchRemotes(linked, ref)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+
+		excerpt, err := readLinkedBugExcerpt(linked, ref.BugId)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResolvedReference{
+			Reference: ref,
+			Path:      path,
+			Excerpt:   excerpt,
+		}, nil
+	}
+
+	return nil, ErrUnresolvedReference
+}
+
+// ResolveReference find the bug designated by a reference in the registered
+// repositories, then in their linked repositories
+func (c *MultiRepoCache) ResolveReference(ref bug.Reference) (*ResolvedReference, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, r := range c.repos {
+		match, err := matchRemotes(r.repo, ref)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			return r.ResolveReference(ref)
+		}
+	}
+
+	for _, r := range c.repos {
+		resolved, err := r.ResolveReference(ref)
+		if err == ErrUnresolvedReference {
+			continue
+		}
+		return resolved, err
+	}
+
+	return nil, ErrUnresolvedReference
+}
+
+func matchRemotes(repo repository.Repo, ref bug.Reference) (bool, error) {
+	remotes, err := repo.GetRemotes()
+	if err != nil {
+		return false, err
+	}
+
+	for _, url := range remotes {
+		if ref.MatchUrl(url) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// readLinkedBugExcerpt read a bug from a repository without using its cache,
+// which might be in use by another process
+func readLinkedBugExcerpt(repo repository.ClockedRepo, prefix string) (*BugExcerpt, error) {
+	ids, err := bug.ListLocalIds(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []entity.Id
+	for _, id := range ids {
+		if id.HasPrefix(prefix) {
+			matching = append(matching, id)
+		}
+	}
+
+	if len(matching) > 1 {
+		return nil, bug.NewErrMultipleMatchBug(matching)
+	}
+
+	if len(matching) == 0 {
+		return nil, bug.ErrBugNotExist
+	}
+
+	b, err := bug.ReadLocalBug(repo, matching[0])
+	if err != nil {
+		return nil, err
+	}
+
+	snap := b.Compile()
+
+	return NewBugExcerpt(b, &snap), nil
+}
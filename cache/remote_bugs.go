@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+// RemoteBugs hold the excerpts of the bugs available on a remote, as of the
+// last fetch. Those bugs are not merged with the local ones, which allow to
+// take a peek at the remote state before synchronizing.
+type RemoteBugs struct {
+	repoCache *RepoCache
+
+	bugExcerpts map[entity.Id]*BugExcerpt
+	// identities only known by the remote
+	identitiesExcerpts map[entity.Id]*IdentityExcerpt
+}
+
+// FetchRemoteBugs retrieve the bugs and identities of a remote without
+// merging them, and compile an excerpt of each remote bugs. The bugs are
+// fetched in full, as the excerpts need their whole history.
+func (c *RepoCache) FetchRemoteBugs(remote string) (*RemoteBugs, error) {
+	_, err := c.Fetch(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ReadRemoteBugs(remote)
+}
+
+// ReadRemoteBugs compile an excerpt of each bug of a remote, as of the last
+// fetch.
+func (c *RepoCache) ReadRemoteBugs(remote string) (*RemoteBugs, error) {
+	result := &RemoteBugs{
+		repoCache:          c,
+		bugExcerpts:        make(map[entity.Id]*BugExcerpt),
+		identitiesExcerpts: make(map[entity.Id]*IdentityExcerpt),
+	}
+
+	for i := range identity.ReadAllRemoteIdentities(c.repo, remote) {
+		if i.Err != nil {
+			return nil, i.Err
+		}
+		result.identitiesExcerpts[i.Identity.Id()] = NewIdentityExcerpt(i.Identity)
+	}
+
+	for b := range bug.ReadAllRemoteBugs(c.repo, remote) {
+		if b.Err != nil {
+			return nil, b.Err
+		}
+		if c.isBlocked(b.Bug.FirstOp().GetAuthor().Id()) {
+			continue
+		}
+		snap := b.Bug.CompileFiltered(c.operationFilter())
+		result.bugExcerpts[b.Bug.Id()] = NewBugExcerpt(b.Bug, &snap)
+	}
+
+	return result, nil
+}
+
+// ResolveBugExcerpt retrieve a remote BugExcerpt matching the exact given id
+func (r *RemoteBugs) ResolveBugExcerpt(id entity.Id) (*BugExcerpt, error) {
+	e, ok := r.bugExcerpts[id]
+	if !ok {
+		return nil, bug.ErrBugNotExist
+	}
+
+	return e, nil
+}
+
+// ResolveIdentityExcerpt retrieve an IdentityExcerpt matching the exact given
+// id, from the remote identities first, then from the local ones.
+func (r *RemoteBugs) ResolveIdentityExcerpt(id entity.Id) (*IdentityExcerpt, error) {
+	e, ok := r.identitiesExcerpts[id]
+	if ok {
+		return e, nil
+	}
+
+	return r.repoCache.ResolveIdentityExcerpt(id)
+}
+
+// GetUserIdentityExcerpt return the excerpt of the local user identity
+func (r *RemoteBugs) GetUserIdentityExcerpt() (*IdentityExcerpt, error) {
+	return r.repoCache.GetUserIdentityExcerpt()
+}
+
+// IsUnread tell if the user identity didn't read the bug since its last edit
+func (r *RemoteBugs) IsUnread(excerpt *BugExcerpt) bool {
+	return r.repoCache.IsUnread(excerpt)
+}
+
+// QueryBugs return the id of all remote Bug matching the given Query
+func (r *RemoteBugs) QueryBugs(query *Query) []entity.Id {
+	if query == nil {
+		return r.AllBugsIds()
+	}
+
+	return queryExcerpts(r.bugExcerpts, query, r)
+}
+
+// AllBugsIds return all known remote bug ids
+func (r *RemoteBugs) AllBugsIds() []entity.Id {
+	result := make([]entity.Id, 0, len(r.bugExcerpts))
+
+	for id := range r.bugExcerpts {
+		result = append(result, id)
+	}
+
+	return result
+}
+
+// BugIdLength return the minimal length of the prefixes of the ids of the
+// remote bugs to be unambiguous
+func (r *RemoteBugs) BugIdLength() int {
+	return entity.UniquePrefixLength(r.AllBugsIds())
+}
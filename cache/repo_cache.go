@@ -18,6 +18,9 @@ import (
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/moderation"
+	"github.com/MichaelMure/git-bug/numbering"
+	"github.com/MichaelMure/git-bug/readmarker"
 	"github.com/MichaelMure/git-bug/repository"
 	"github.com/MichaelMure/git-bug/util/git"
 	"github.com/MichaelMure/git-bug/util/process"
@@ -26,6 +29,9 @@ import (
 const bugCacheFile = "bug-cache"
 const identityCacheFile = "identity-cache"
 
+const defaultRemoteConfigKey = "git-bug.remote"
+const defaultRemote = "origin"
+
 // 1: original format
 // 2: added cache for identities with a reference in the bug cache
 const formatVersion = 2
@@ -64,6 +70,11 @@ type RepoCache struct {
 	muBug sync.RWMutex
 	// excerpt of bugs data for all bugs
 	bugExcerpts map[entity.Id]*BugExcerpt
+	// the index of the excerpts for the prefix search
+	searchIndex *searchIndex
+	// the bugs left out of the excerpts for their incomplete history, until
+	// it's fetched
+	incompleteBugs map[entity.Id]struct{}
 	// bug loaded in memory
 	bugs map[entity.Id]*BugCache
 
@@ -74,7 +85,35 @@ type RepoCache struct {
 	identities map[entity.Id]*IdentityCache
 
 	// the user identity's id, if known
+	// guarded by muIdentity
 	userIdentityId entity.Id
+
+	muBlocklist sync.RWMutex
+	// identities moderated out of the bugs
+	blocklist *moderation.Blocklist
+
+	muNumbers sync.RWMutex
+	// the sequential numbers of the bugs
+	numbers *numbering.Numbers
+
+	muReadMarkers sync.Mutex
+	// the read markers of the user identity, loaded when first needed
+	readMarkers *readmarker.Markers
+
+	muLabels sync.Mutex
+	// the label registry, loaded when first needed
+	labels map[bug.Label]bug.LabelColor
+
+	muRootPacks sync.Mutex
+	// the local bugs indexed by their first OperationPack, brought up to date
+	// by the merges, and saved in the bug cache file
+	rootPacks bug.RootPacks
+
+	// if true, the content lint is skipped, as asked with --no-verify
+	noLint bool
+
+	// the open transaction, if any
+	tx transaction
 }
 
 func NewRepoCache(r repository.ClockedRepo) (*RepoCache, error) {
@@ -94,6 +133,16 @@ func NewNamedRepoCache(r repository.ClockedRepo, name string) (*RepoCache, error
 		return &RepoCache{}, err
 	}
 
+	c.blocklist, err = moderation.Read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c.numbers, err = numbering.Read(r)
+	if err != nil {
+		return nil, err
+	}
+
 	err = c.load()
 	if err == nil {
 		return c, nil
@@ -134,6 +183,11 @@ func (c *RepoCache) GetCoreEditor() (string, error) {
 	return c.repo.GetCoreEditor()
 }
 
+// GetCorePager returns the pager that the user has configured for git.
+func (c *RepoCache) GetCorePager() (string, error) {
+	return c.repo.GetCorePager()
+}
+
 // GetRemotes returns the configured remotes repositories.
 func (c *RepoCache) GetRemotes() (map[string]string, error) {
 	return c.repo.GetRemotes()
@@ -172,6 +226,9 @@ func (c *RepoCache) lock() error {
 }
 
 func (c *RepoCache) Close() error {
+	// don't lose the changes of an unfinished transaction
+	errFlush := c.flushTransaction()
+
 	c.muBug.Lock()
 	defer c.muBug.Unlock()
 	c.muIdentity.Lock()
@@ -181,9 +238,14 @@ func (c *RepoCache) Close() error {
 	c.identitiesExcerpts = nil
 	c.bugs = make(map[entity.Id]*BugCache)
 	c.bugExcerpts = nil
+	c.searchIndex = nil
 
 	lockPath := repoLockFilePath(c.repo)
-	return os.Remove(lockPath)
+	err := os.Remove(lockPath)
+	if errFlush != nil {
+		return errFlush
+	}
+	return err
 }
 
 // bugUpdated is a callback to trigger when the excerpt of a bug changed,
@@ -197,7 +259,12 @@ func (c *RepoCache) bugUpdated(id entity.Id) error {
 		panic("missing bug in the cache")
 	}
 
-	c.bugExcerpts[id] = NewBugExcerpt(b.bug, b.Snapshot())
+	// during a transaction, only a new bug get its excerpt right away, to be
+	// found by the queries
+	_, known := c.bugExcerpts[id]
+	if !known || !c.deferBugUpdate(id) {
+		c.updateBugExcerpt(b.bug.Bug, b.Snapshot())
+	}
 	c.muBug.Unlock()
 
 	// we only need to write the bug cache
@@ -215,7 +282,10 @@ func (c *RepoCache) identityUpdated(id entity.Id) error {
 		panic("missing identity in the cache")
 	}
 
-	c.identitiesExcerpts[id] = NewIdentityExcerpt(i.Identity)
+	_, known := c.identitiesExcerpts[id]
+	if !known || !c.deferIdentityUpdate(id) {
+		c.identitiesExcerpts[id] = NewIdentityExcerpt(i.Identity)
+	}
 	c.muIdentity.Unlock()
 
 	// we only need to write the identity cache
@@ -244,8 +314,12 @@ func (c *RepoCache) loadBugCache() error {
 	decoder := gob.NewDecoder(f)
 
 	aux := struct {
-		Version  uint
-		Excerpts map[entity.Id]*BugExcerpt
+		Version    uint
+		Excerpts   map[entity.Id]*BugExcerpt
+		Incomplete []entity.Id
+		RootPacks  bug.RootPacks
+		Blocklist  git.Hash
+		Fields     string
 	}{}
 
 	err = decoder.Decode(&aux)
@@ -259,7 +333,30 @@ func (c *RepoCache) loadBugCache() error {
 		}
 	}
 
+	// the excerpts have been compiled with a different blocklist
+	if aux.Blocklist != c.blocklistCommit() {
+		return fmt.Errorf("outdated bug cache")
+	}
+
+	// the excerpts have been computed with different fields
+	if aux.Fields != excerptFieldsSignature() {
+		return fmt.Errorf("outdated bug cache")
+	}
+
 	c.bugExcerpts = aux.Excerpts
+	c.searchIndex = newSearchIndex(c.bugExcerpts)
+	c.incompleteBugs = nil
+	if len(aux.Incomplete) > 0 {
+		c.incompleteBugs = make(map[entity.Id]struct{}, len(aux.Incomplete))
+		for _, id := range aux.Incomplete {
+			c.incompleteBugs[id] = struct{}{}
+		}
+	}
+
+	c.muRootPacks.Lock()
+	c.rootPacks = aux.RootPacks
+	c.muRootPacks.Unlock()
+
 	return nil
 }
 
@@ -306,17 +403,36 @@ func (c *RepoCache) write() error {
 
 // write will serialize on disk the bug cache file
 func (c *RepoCache) writeBugCache() error {
+	if c.deferWrite(&c.tx.bugCacheDirty) {
+		return nil
+	}
+
+	rootPacks := c.copyRootPacks()
+
 	c.muBug.RLock()
 	defer c.muBug.RUnlock()
 
 	var data bytes.Buffer
 
+	incomplete := make([]entity.Id, 0, len(c.incompleteBugs))
+	for id := range c.incompleteBugs {
+		incomplete = append(incomplete, id)
+	}
+
 	aux := struct {
-		Version  uint
-		Excerpts map[entity.Id]*BugExcerpt
+		Version    uint
+		Excerpts   map[entity.Id]*BugExcerpt
+		Incomplete []entity.Id
+		RootPacks  bug.RootPacks
+		Blocklist  git.Hash
+		Fields     string
 	}{
-		Version:  formatVersion,
-		Excerpts: c.bugExcerpts,
+		Version:    formatVersion,
+		Excerpts:   c.bugExcerpts,
+		Incomplete: incomplete,
+		RootPacks:  rootPacks,
+		Blocklist:  c.blocklistCommit(),
+		Fields:     excerptFieldsSignature(),
 	}
 
 	encoder := gob.NewEncoder(&data)
@@ -341,6 +457,10 @@ func (c *RepoCache) writeBugCache() error {
 
 // write will serialize on disk the identity cache file
 func (c *RepoCache) writeIdentityCache() error {
+	if c.deferWrite(&c.tx.identityCacheDirty) {
+		return nil
+	}
+
 	c.muIdentity.RLock()
 	defer c.muIdentity.RUnlock()
 
@@ -382,6 +502,18 @@ func identityCacheFilePath(repo repository.Repo) string {
 	return path.Join(repo.GetPath(), "git-bug", identityCacheFile)
 }
 
+// RemoveCacheFiles remove the cache files of a repository, for the cache to be
+// built again from the bugs and identities when opened next
+func RemoveCacheFiles(repo repository.Repo) error {
+	for _, p := range []string{bugCacheFilePath(repo), identityCacheFilePath(repo)} {
+		err := os.Remove(p)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *RepoCache) buildCache() error {
 	c.muBug.Lock()
 	defer c.muBug.Unlock()
@@ -395,6 +527,16 @@ func (c *RepoCache) buildCache() error {
 	allIdentities := identity.ReadAllLocalIdentities(c.repo)
 
 	for i := range allIdentities {
+		if entity.IsErrIncompleteHistory(i.Err) {
+			// drain the stream to not leave the reader blocked
+			for range allIdentities {
+			}
+			err := c.buildIdentityExcerptsSkipIncomplete()
+			if err != nil {
+				return err
+			}
+			break
+		}
 		if i.Err != nil {
 			return i.Err
 		}
@@ -406,20 +548,105 @@ func (c *RepoCache) buildCache() error {
 
 	_, _ = fmt.Fprintf(os.Stderr, "Building bug cache... ")
 
+	err := c.buildBugExcerpts()
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(os.Stderr, "Done.")
+	return nil
+}
+
+// buildBugExcerpts compile the excerpts of all the local bugs
+// muBug must be held
+func (c *RepoCache) buildBugExcerpts() error {
 	c.bugExcerpts = make(map[entity.Id]*BugExcerpt)
+	c.searchIndex = newSearchIndex(c.bugExcerpts)
+	c.incompleteBugs = nil
 
 	allBugs := bug.ReadAllLocalBugs(c.repo)
 
 	for b := range allBugs {
+		if entity.IsErrIncompleteHistory(b.Err) {
+			// drain the stream to not leave the readers blocked
+			for range allBugs {
+			}
+			return c.buildBugExcerptsSkipIncomplete()
+		}
 		if b.Err != nil {
 			return b.Err
 		}
 
-		snap := b.Bug.Compile()
-		c.bugExcerpts[b.Bug.Id()] = NewBugExcerpt(b.Bug, &snap)
+		snap := b.Bug.CompileFiltered(c.operationFilter())
+		c.compileBugExcerpt(b.Bug, &snap)
 	}
 
-	_, _ = fmt.Fprintln(os.Stderr, "Done.")
+	c.rollUpAllChildren()
+
+	return nil
+}
+
+// buildIdentityExcerptsSkipIncomplete compile the excerpts of the local
+// identities one by one, leaving out the identities whose history is
+// incomplete, as in a shallow clone, until it's fetched
+// muIdentity must be held
+func (c *RepoCache) buildIdentityExcerptsSkipIncomplete() error {
+	c.identitiesExcerpts = make(map[entity.Id]*IdentityExcerpt)
+
+	ids, err := identity.ListLocalIds(c.repo)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		i, err := identity.ReadLocal(c.repo, id)
+		if entity.IsErrIncompleteHistory(err) {
+			_, _ = fmt.Fprintf(os.Stderr, "\n%v", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		c.identitiesExcerpts[i.Id()] = NewIdentityExcerpt(i)
+	}
+
+	return nil
+}
+
+// buildBugExcerptsSkipIncomplete compile the excerpts of the local bugs one
+// by one, leaving out the bugs whose history is incomplete, as in a shallow
+// clone, until it's fetched
+// muBug must be held
+func (c *RepoCache) buildBugExcerptsSkipIncomplete() error {
+	c.bugExcerpts = make(map[entity.Id]*BugExcerpt)
+	c.searchIndex = newSearchIndex(c.bugExcerpts)
+	c.incompleteBugs = make(map[entity.Id]struct{})
+
+	ids, err := bug.ListLocalIds(c.repo)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		b, err := bug.ReadLocalBug(c.repo, id)
+		if entity.IsErrIncompleteHistory(err) {
+			_, _ = fmt.Fprintf(os.Stderr, "\n%v", err)
+			c.incompleteBugs[id] = struct{}{}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		snap := b.CompileFiltered(c.operationFilter())
+		c.compileBugExcerpt(b, &snap)
+	}
+
+	c.rollUpAllChildren()
+
+	_, _ = fmt.Fprintln(os.Stderr)
+
 	return nil
 }
 
@@ -462,6 +689,13 @@ func (c *RepoCache) ResolveBug(id entity.Id) (*BugCache, error) {
 // ResolveBugExcerptPrefix retrieve a BugExcerpt matching an id prefix. It fails if multiple
 // bugs match.
 func (c *RepoCache) ResolveBugExcerptPrefix(prefix string) (*BugExcerpt, error) {
+	if id, ok, err := c.numberedBug(prefix); ok {
+		if err != nil {
+			return nil, err
+		}
+		return c.ResolveBugExcerpt(id)
+	}
+
 	return c.ResolveBugExcerptMatcher(func(excerpt *BugExcerpt) bool {
 		return excerpt.Id.HasPrefix(prefix)
 	})
@@ -470,6 +704,13 @@ func (c *RepoCache) ResolveBugExcerptPrefix(prefix string) (*BugExcerpt, error)
 // ResolveBugPrefix retrieve a bug matching an id prefix. It fails if multiple
 // bugs match.
 func (c *RepoCache) ResolveBugPrefix(prefix string) (*BugCache, error) {
+	if id, ok, err := c.numberedBug(prefix); ok {
+		if err != nil {
+			return nil, err
+		}
+		return c.ResolveBug(id)
+	}
+
 	return c.ResolveBugMatcher(func(excerpt *BugExcerpt) bool {
 		return excerpt.Id.HasPrefix(prefix)
 	})
@@ -514,7 +755,13 @@ func (c *RepoCache) resolveBugMatcher(f func(*BugExcerpt) bool) (entity.Id, erro
 	}
 
 	if len(matching) > 1 {
-		return entity.UnsetId, bug.NewErrMultipleMatchBug(matching)
+		sort.Slice(matching, func(i, j int) bool { return matching[i] < matching[j] })
+		descriptions := make(map[entity.Id]string, len(matching))
+		for _, id := range matching {
+			excerpt := c.bugExcerpts[id]
+			descriptions[id] = fmt.Sprintf("[%s] %s", excerpt.Status, excerpt.Title)
+		}
+		return entity.UnsetId, bug.NewErrMultipleMatchBug(matching).Describe(descriptions)
 	}
 
 	if len(matching) == 0 {
@@ -533,23 +780,100 @@ func (c *RepoCache) QueryBugs(query *Query) []entity.Id {
 		return c.AllBugsIds()
 	}
 
-	var filtered []*BugExcerpt
+	return queryExcerpts(c.bugExcerpts, query, c)
+}
+
+// DuplicatesOf return the id of the bugs marked as a duplicate of the given
+// bug, ordered by creation
+func (c *RepoCache) DuplicatesOf(id entity.Id) []entity.Id {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
 
+	var duplicates []*BugExcerpt
 	for _, excerpt := range c.bugExcerpts {
-		if query.Match(excerpt, c) {
+		if excerpt.DuplicateOf == id {
+			duplicates = append(duplicates, excerpt)
+		}
+	}
+
+	sort.Sort(BugsByCreationTime(duplicates))
+
+	result := make([]entity.Id, len(duplicates))
+	for i, excerpt := range duplicates {
+		result[i] = excerpt.Id
+	}
+
+	return result
+}
+
+// FrequentlyReopened return the id of the bugs reopened at least the given
+// number of times, the most reopened first
+func (c *RepoCache) FrequentlyReopened(minimum int) []entity.Id {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	var reopened []*BugExcerpt
+	for _, excerpt := range c.bugExcerpts {
+		if excerpt.ReopenCount >= minimum {
+			reopened = append(reopened, excerpt)
+		}
+	}
+
+	sort.Sort(BugsByCreationTime(reopened))
+	sort.SliceStable(reopened, func(i, j int) bool {
+		return reopened[i].ReopenCount > reopened[j].ReopenCount
+	})
+
+	result := make([]entity.Id, len(reopened))
+	for i, excerpt := range reopened {
+		result[i] = excerpt.Id
+	}
+
+	return result
+}
+
+// queryExcerpts filter and sort a set of BugExcerpt according to a Query
+func queryExcerpts(excerpts map[entity.Id]*BugExcerpt, query *Query, resolver resolver) []entity.Id {
+	filtered := filterExcerpts(excerpts, query, resolver)
+	sortExcerpts(filtered, query)
+
+	result := make([]entity.Id, len(filtered))
+
+	for i, val := range filtered {
+		result[i] = val.Id
+	}
+
+	return result
+}
+
+// filterExcerpts return the BugExcerpt matching a Query, in no particular order
+func filterExcerpts(excerpts map[entity.Id]*BugExcerpt, query *Query, resolver resolver) []*BugExcerpt {
+	var filtered []*BugExcerpt
+
+	for _, excerpt := range excerpts {
+		if query.Match(excerpt, resolver) {
 			filtered = append(filtered, excerpt)
 		}
 	}
 
+	return filtered
+}
+
+// sortExcerpts sort a set of BugExcerpt in the order asked by a Query
+func sortExcerpts(excerpts []*BugExcerpt, query *Query) {
 	var sorter sort.Interface
 
 	switch query.OrderBy {
 	case OrderById:
-		sorter = BugsById(filtered)
+		sorter = BugsById(excerpts)
 	case OrderByCreation:
-		sorter = BugsByCreationTime(filtered)
+		sorter = BugsByCreationTime(excerpts)
 	case OrderByEdit:
-		sorter = BugsByEditTime(filtered)
+		sorter = BugsByEditTime(excerpts)
+	case OrderByVotes:
+		sorter = BugsByVotes(excerpts)
+	case OrderByField:
+		sorter = BugsByField{excerpts: excerpts, field: excerptFields[query.OrderField]}
 	default:
 		panic("missing sort type")
 	}
@@ -559,14 +883,6 @@ func (c *RepoCache) QueryBugs(query *Query) []entity.Id {
 	}
 
 	sort.Sort(sorter)
-
-	result := make([]entity.Id, len(filtered))
-
-	for i, val := range filtered {
-		result[i] = val.Id
-	}
-
-	return result
 }
 
 // AllBugsIds return all known bug ids
@@ -585,17 +901,28 @@ func (c *RepoCache) AllBugsIds() []entity.Id {
 	return result
 }
 
-// ValidLabels list valid labels
-//
-// Note: in the future, a proper label policy could be implemented where valid
-// labels are defined in a configuration file. Until that, the default behavior
-// is to return the list of labels already used.
+// BugIdLength return the minimal length of the prefixes of the ids of the bugs
+// to be unambiguous in this repository. It grows with the count of bugs.
+func (c *RepoCache) BugIdLength() int {
+	return entity.UniquePrefixLength(c.AllBugsIds())
+}
+
+// ValidLabels list valid labels, that is the labels of the label registry and
+// the labels already used.
 func (c *RepoCache) ValidLabels() []bug.Label {
+	set := map[bug.Label]interface{}{}
+
+	c.muLabels.Lock()
+	registry, _ := c.labelRegistry()
+	c.muLabels.Unlock()
+
+	for l := range registry {
+		set[l] = nil
+	}
+
 	c.muBug.RLock()
 	defer c.muBug.RUnlock()
 
-	set := map[bug.Label]interface{}{}
-
 	for _, excerpt := range c.bugExcerpts {
 		for _, l := range excerpt.Labels {
 			set[l] = nil
@@ -627,12 +954,41 @@ func (c *RepoCache) NewBug(title string, message string) (*BugCache, *bug.Create
 // NewBugWithFiles create a new bug with attached files for the message
 // The new bug is written in the repository (commit)
 func (c *RepoCache) NewBugWithFiles(title string, message string, files []git.Hash) (*BugCache, *bug.CreateOperation, error) {
+	return c.NewBugWithFields(title, message, nil, nil, files)
+}
+
+// NewBugWithFields create a new bug with some labels and custom fields set
+// from the start, and attached files for the message. The labels and fields
+// required by the repository must be given.
+// The new bug is written in the repository (commit)
+func (c *RepoCache) NewBugWithFields(title string, message string, labels []string, fields map[string]string, files []git.Hash) (*BugCache, *bug.CreateOperation, error) {
 	author, err := c.GetUserIdentity()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return c.NewBugRaw(author, time.Now().Unix(), title, message, files, nil)
+	return c.newRequiredBug(author, title, message, labels, fields, files)
+}
+
+// newRequiredBug create a new bug now, ensuring that the labels and fields
+// required by the repository are given
+func (c *RepoCache) newRequiredBug(author *IdentityCache, title string, message string, labels []string, fields map[string]string, files []git.Hash) (*BugCache, *bug.CreateOperation, error) {
+	requirements, err := c.Requirements()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, op, err := bug.CreateWithFields(author.Identity, time.Now().Unix(), title, message, labels, fields, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = op.ValidateRequirements(requirements)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.commitNewBug(b, op)
 }
 
 // NewBugWithFilesMeta create a new bug with attached files for the message, as
@@ -648,7 +1004,35 @@ func (c *RepoCache) NewBugRaw(author *IdentityCache, unixTime int64, title strin
 		op.SetMetadata(key, value)
 	}
 
-	err = b.Commit(c.repo)
+	return c.commitNewBug(b, op)
+}
+
+// NewBugRawWithFields create a new bug with some labels and custom fields set
+// from the start, as well as metadata for the Create operation, without
+// checking the requirements of the repository.
+// The new bug is written in the repository (commit)
+func (c *RepoCache) NewBugRawWithFields(author *IdentityCache, unixTime int64, title string, message string, labels []string, fields map[string]string, files []git.Hash, metadata map[string]string) (*BugCache, *bug.CreateOperation, error) {
+	b, op, err := bug.CreateWithFields(author.Identity, unixTime, title, message, labels, fields, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key, value := range metadata {
+		op.SetMetadata(key, value)
+	}
+
+	return c.commitNewBug(b, op)
+}
+
+func (c *RepoCache) commitNewBug(b *bug.Bug, op *bug.CreateOperation) (*BugCache, *bug.CreateOperation, error) {
+	err := c.checkPolicies(b, b.StagedOperations())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = c.commitKeepRead(b, func() error {
+		return b.Commit(c.repo)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -672,6 +1056,69 @@ func (c *RepoCache) NewBugRaw(author *IdentityCache, unixTime int64, title strin
 	return cached, op, nil
 }
 
+// RemoveBug remove a bug from the repository by writing a tombstone, as the
+// current user. The tombstone will propagate with the next push.
+func (c *RepoCache) RemoveBug(id entity.Id, reason string) (*bug.Tombstone, error) {
+	author, err := c.GetUserIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.RemoveBugRaw(author, time.Now().Unix(), id, reason)
+}
+
+// RemoveBugRaw remove a bug from the repository by writing a tombstone, with
+// the given author and time.
+func (c *RepoCache) RemoveBugRaw(author *IdentityCache, unixTime int64, id entity.Id, reason string) (*bug.Tombstone, error) {
+	b, err := c.ResolveBug(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstone, err := bug.Remove(c.repo, b.bug, author.Identity, unixTime, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	c.muBug.Lock()
+	delete(c.bugs, id)
+	if excerpt, ok := c.bugExcerpts[id]; ok {
+		delete(c.bugExcerpts, id)
+		c.searchIndex.remove(id)
+		c.rollUpChildren(excerpt.Parent)
+	}
+	c.muBug.Unlock()
+
+	return tombstone, c.writeBugCache()
+}
+
+// DefaultRemote return the remote to use when none is specified, as configured
+// with SetDefaultRemote, falling back to "origin"
+func (c *RepoCache) DefaultRemote() (string, error) {
+	remote, err := c.repo.LocalConfig().ReadString(defaultRemoteConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		return defaultRemote, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return remote, nil
+}
+
+// SetDefaultRemote configure the remote to use when none is specified
+func (c *RepoCache) SetDefaultRemote(remote string) error {
+	remotes, err := c.repo.GetRemotes()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := remotes[remote]; !ok {
+		return fmt.Errorf("unknown remote %s", remote)
+	}
+
+	return c.repo.LocalConfig().StoreString(defaultRemoteConfigKey, remote)
+}
+
 // Fetch retrieve updates from a remote
 // This does not change the local bugs or identities state
 func (c *RepoCache) Fetch(remote string) (string, error) {
@@ -685,10 +1132,21 @@ func (c *RepoCache) Fetch(remote string) (string, error) {
 		return stdout2, err
 	}
 
-	return stdout1 + stdout2, nil
+	stdout3, err := moderation.Fetch(c.repo, remote)
+	if err != nil {
+		return stdout3, err
+	}
+
+	stdout4, err := numbering.Fetch(c.repo, remote)
+	if err != nil {
+		return stdout4, err
+	}
+
+	return stdout1 + stdout2 + stdout3 + stdout4, nil
 }
 
-// MergeAll will merge all the available remote bug and identities
+// MergeAll will merge all the available remote bug and identities, as well as
+// the blocklist and the numbers of the bugs
 func (c *RepoCache) MergeAll(remote string) <-chan entity.MergeResult {
 	out := make(chan entity.MergeResult)
 
@@ -696,42 +1154,69 @@ func (c *RepoCache) MergeAll(remote string) <-chan entity.MergeResult {
 	go func() {
 		defer close(out)
 
-		results := identity.MergeAll(c.repo, remote)
-		for result := range results {
-			out <- result
-
-			if result.Err != nil {
-				continue
-			}
+		blocklistUpdated, err := moderation.Merge(c.repo, remote)
+		if err != nil {
+			out <- entity.MergeResult{Err: errors.Wrap(err, "blocklist merge failed")}
+			blocklistUpdated = false
+		}
 
-			switch result.Status {
-			case entity.MergeStatusNew, entity.MergeStatusUpdated:
-				i := result.Entity.(*identity.Identity)
-				c.muIdentity.Lock()
-				c.identitiesExcerpts[result.Id] = NewIdentityExcerpt(i)
-				c.muIdentity.Unlock()
+		numbersUpdated, err := numbering.Merge(c.repo, remote)
+		if err != nil {
+			out <- entity.MergeResult{Err: errors.Wrap(err, "numbers merge failed")}
+		}
+		if numbersUpdated {
+			if err := c.reloadNumbers(); err != nil {
+				out <- entity.MergeResult{Err: err}
 			}
 		}
 
-		results = bug.MergeAll(c.repo, remote)
-		for result := range results {
-			out <- result
+		c.mergeIdentities(remote, out)
+
+		untrusted, err := c.IsUntrustedRemote(remote)
+		if err != nil {
+			out <- entity.MergeResult{Err: err}
+		}
 
-			if result.Err != nil {
-				continue
+		// the bugs of an untrusted remote are held for review instead
+		if untrusted {
+			for result := range bug.HoldAll(c.repo, remote) {
+				out <- result
 			}
+		} else {
+			rootPacks := c.copyRootPacks()
+
+			for result := range bug.MergeAll(c.repo, remote, rootPacks) {
+				if result.Err != nil {
+					out <- result
+					continue
+				}
+
+				// a bug left out of the cache for its incomplete history is now
+				// readable, the whole history having been fetched
+				if result.Status == entity.MergeStatusNothing {
+					c.muBug.Lock()
+					if _, ok := c.incompleteBugs[result.Id]; ok {
+						delete(c.incompleteBugs, result.Id)
+						result.Status = entity.MergeStatusNew
+					}
+					c.muBug.Unlock()
+				}
+
+				out <- result
+
+				c.updateMergedBug(result)
+			}
+
+			c.setRootPacks(rootPacks)
+		}
 
-			switch result.Status {
-			case entity.MergeStatusNew, entity.MergeStatusUpdated:
-				b := result.Entity.(*bug.Bug)
-				snap := b.Compile()
-				c.muBug.Lock()
-				c.bugExcerpts[result.Id] = NewBugExcerpt(b, &snap)
-				c.muBug.Unlock()
+		if blocklistUpdated {
+			if err := c.reloadBlocklist(); err != nil {
+				out <- entity.MergeResult{Err: err}
 			}
 		}
 
-		err := c.write()
+		err = c.write()
 
 		// No easy way out here ..
 		if err != nil {
@@ -742,8 +1227,53 @@ func (c *RepoCache) MergeAll(remote string) <-chan entity.MergeResult {
 	return out
 }
 
+// copyRootPacks return a copy of the index of the local bugs by their first
+// OperationPack, for a merge to bring up to date
+func (c *RepoCache) copyRootPacks() bug.RootPacks {
+	c.muRootPacks.Lock()
+	defer c.muRootPacks.Unlock()
+
+	rootPacks := make(bug.RootPacks, len(c.rootPacks))
+	for id, hash := range c.rootPacks {
+		rootPacks[id] = hash
+	}
+	return rootPacks
+}
+
+// setRootPacks replace the index of the local bugs by their first
+// OperationPack with the one brought up to date by a merge
+func (c *RepoCache) setRootPacks(rootPacks bug.RootPacks) {
+	c.muRootPacks.Lock()
+	c.rootPacks = rootPacks
+	c.muRootPacks.Unlock()
+}
+
+// updateMergedBug update the cache with the result of the merge of a bug
+func (c *RepoCache) updateMergedBug(result entity.MergeResult) {
+	switch result.Status {
+	case entity.MergeStatusNew, entity.MergeStatusUpdated:
+		b := result.Entity.(*bug.Bug)
+		snap := b.CompileFiltered(c.operationFilter())
+		c.muBug.Lock()
+		c.updateBugExcerpt(b, &snap)
+		c.muBug.Unlock()
+	case entity.MergeStatusRemoved:
+		c.muBug.Lock()
+		delete(c.bugs, result.Id)
+		delete(c.bugExcerpts, result.Id)
+		c.searchIndex.remove(result.Id)
+		c.muBug.Unlock()
+	}
+}
+
 // Push update a remote with the local changes
 func (c *RepoCache) Push(remote string) (string, error) {
+	// the numbers are allocated when publishing the bugs
+	stdout0, err := c.allocateBugNumbersForPush(remote)
+	if err != nil {
+		return stdout0, err
+	}
+
 	stdout1, err := identity.Push(c.repo, remote)
 	if err != nil {
 		return stdout1, err
@@ -754,7 +1284,17 @@ func (c *RepoCache) Push(remote string) (string, error) {
 		return stdout2, err
 	}
 
-	return stdout1 + stdout2, nil
+	stdout3, err := moderation.Push(c.repo, remote)
+	if err != nil {
+		return stdout3, err
+	}
+
+	stdout4, err := numbering.Push(c.repo, remote)
+	if err != nil {
+		return stdout4, err
+	}
+
+	return stdout0 + stdout1 + stdout2 + stdout3 + stdout4, nil
 }
 
 // Pull will do a Fetch + MergeAll
@@ -931,7 +1471,12 @@ func (c *RepoCache) resolveIdentityMatcher(f func(*IdentityExcerpt) bool) (entit
 	}
 
 	if len(matching) > 1 {
-		return entity.UnsetId, identity.NewErrMultipleMatch(matching)
+		sort.Slice(matching, func(i, j int) bool { return matching[i] < matching[j] })
+		descriptions := make(map[entity.Id]string, len(matching))
+		for _, id := range matching {
+			descriptions[id] = c.identitiesExcerpts[id].DisplayName()
+		}
+		return entity.UnsetId, identity.NewErrMultipleMatch(matching).Describe(descriptions)
 	}
 
 	if len(matching) == 0 {
@@ -963,8 +1508,8 @@ func (c *RepoCache) SetUserIdentity(i *IdentityCache) error {
 		return err
 	}
 
-	c.muIdentity.RLock()
-	defer c.muIdentity.RUnlock()
+	c.muIdentity.Lock()
+	defer c.muIdentity.Unlock()
 
 	// Make sure that everything is fine
 	if _, ok := c.identities[i.Id()]; !ok {
@@ -976,13 +1521,36 @@ func (c *RepoCache) SetUserIdentity(i *IdentityCache) error {
 	return nil
 }
 
+// SetGlobalUserIdentity store the identity as the default user identity for
+// all the repositories where it exist and where no identity is set
+func (c *RepoCache) SetGlobalUserIdentity(i *IdentityCache) error {
+	return identity.SetGlobalUserIdentity(c.repo, i.Identity)
+}
+
+// UseIdentity make the given identity the user identity for the lifetime of
+// this cache, without changing the configuration
+func (c *RepoCache) UseIdentity(i *IdentityCache) {
+	c.muIdentity.Lock()
+	defer c.muIdentity.Unlock()
+
+	// Make sure that everything is fine
+	if _, ok := c.identities[i.Id()]; !ok {
+		panic("UseIdentity while the identity is not from the cache, something is wrong")
+	}
+
+	c.userIdentityId = i.Id()
+}
+
 func (c *RepoCache) GetUserIdentity() (*IdentityCache, error) {
+	c.muIdentity.RLock()
 	if c.userIdentityId != "" {
 		i, ok := c.identities[c.userIdentityId]
 		if ok {
+			c.muIdentity.RUnlock()
 			return i, nil
 		}
 	}
+	c.muIdentity.RUnlock()
 
 	c.muIdentity.Lock()
 	defer c.muIdentity.Unlock()
@@ -1000,6 +1568,20 @@ func (c *RepoCache) GetUserIdentity() (*IdentityCache, error) {
 }
 
 func (c *RepoCache) GetUserIdentityExcerpt() (*IdentityExcerpt, error) {
+	// the queries matching the user identity ask for it for each bug
+	c.muIdentity.RLock()
+	if c.userIdentityId != "" {
+		excerpt, ok := c.identitiesExcerpts[c.userIdentityId]
+		if ok {
+			c.muIdentity.RUnlock()
+			return excerpt, nil
+		}
+	}
+	c.muIdentity.RUnlock()
+
+	c.muIdentity.Lock()
+	defer c.muIdentity.Unlock()
+
 	if c.userIdentityId == "" {
 		id, err := identity.GetUserIdentityId(c.repo)
 		if err != nil {
@@ -1008,9 +1590,6 @@ func (c *RepoCache) GetUserIdentityExcerpt() (*IdentityExcerpt, error) {
 		c.userIdentityId = id
 	}
 
-	c.muIdentity.RLock()
-	defer c.muIdentity.RUnlock()
-
 	excerpt, ok := c.identitiesExcerpts[c.userIdentityId]
 	if !ok {
 		return nil, fmt.Errorf("cache: missing identity excerpt %v", c.userIdentityId)
@@ -1051,6 +1630,24 @@ func (c *RepoCache) NewIdentityRaw(name string, email string, login string, avat
 	return c.finishIdentity(i, metadata)
 }
 
+// NewAnonymousIdentity create a new throwaway identity, without name or email
+// The new identity is written in the repository (commit)
+func (c *RepoCache) NewAnonymousIdentity() (*IdentityCache, error) {
+	return c.finishIdentity(identity.NewAnonymousIdentity(), nil)
+}
+
+// NewAnonymousBug create a new bug, reported with a new throwaway identity.
+// The labels and fields required by the repository must be given.
+// The new bug and identity are written in the repository (commit)
+func (c *RepoCache) NewAnonymousBug(title string, message string, labels []string, fields map[string]string) (*BugCache, *bug.CreateOperation, error) {
+	author, err := c.NewAnonymousIdentity()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.newRequiredBug(author, title, message, labels, fields, nil)
+}
+
 func (c *RepoCache) finishIdentity(i *identity.Identity, metadata map[string]string) (*IdentityCache, error) {
 	for key, value := range metadata {
 		i.SetMetadata(key, value)
@@ -1,10 +1,20 @@
 package cache
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/repository"
 )
 
@@ -155,4 +165,2097 @@ func TestPushPull(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Len(t, cacheA.AllBugsIds(), 2)
+
+	// the index of the local bugs by their first pack is kept in the cache
+	// file, for the next merges to only read the new bugs
+	require.Len(t, cacheA.copyRootPacks(), 2)
+
+	require.NoError(t, cacheA.Close())
+	cacheA, err = NewRepoCache(repoA)
+	require.NoError(t, err)
+	defer cacheA.Close()
+
+	require.Len(t, cacheA.copyRootPacks(), 2)
+}
+
+func TestIdentityDirectory(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	cacheA, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+
+	cacheB, err := NewRepoCache(repoB)
+	require.NoError(t, err)
+
+	directory, err := cacheB.IdentityDirectory()
+	require.NoError(t, err)
+	require.Empty(t, directory)
+
+	require.Error(t, cacheB.SetIdentityDirectory("unknown"))
+	require.NoError(t, cacheB.SetIdentityDirectory("origin"))
+	directory, err = cacheB.IdentityDirectory()
+	require.NoError(t, err)
+	require.Equal(t, "origin", directory)
+
+	reneA, err := cacheA.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cacheA.SetUserIdentity(reneA))
+	_, _, err = cacheA.NewBug("bug1", "message")
+	require.NoError(t, err)
+
+	// push everything, but only pull the identities
+	_, err = cacheA.Push("origin")
+	require.NoError(t, err)
+
+	require.NoError(t, cacheB.PullIdentities(directory))
+
+	_, err = cacheB.ResolveIdentity(reneA.Id())
+	require.NoError(t, err)
+	require.Empty(t, cacheB.AllBugsIds())
+}
+
+func TestRemoteBugs(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	cacheA, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+
+	cacheB, err := NewRepoCache(repoB)
+	require.NoError(t, err)
+
+	reneA, err := cacheA.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	err = cacheA.SetUserIdentity(reneA)
+	require.NoError(t, err)
+
+	bug1, _, err := cacheA.NewBug("bug1", "message")
+	require.NoError(t, err)
+	_, _, err = cacheA.NewBug("bug2", "message")
+	require.NoError(t, err)
+
+	_, err = cacheA.Push("origin")
+	require.NoError(t, err)
+
+	remoteBugs, err := cacheB.FetchRemoteBugs("origin")
+	require.NoError(t, err)
+
+	// nothing has been merged locally
+	require.Len(t, cacheB.AllBugsIds(), 0)
+	require.Len(t, cacheB.AllIdentityIds(), 0)
+
+	require.Len(t, remoteBugs.AllBugsIds(), 2)
+
+	excerpt, err := remoteBugs.ResolveBugExcerpt(bug1.Id())
+	require.NoError(t, err)
+	require.Equal(t, "bug1", excerpt.Title)
+
+	// identities only known by the remote can be resolved
+	author, err := remoteBugs.ResolveIdentityExcerpt(excerpt.AuthorId)
+	require.NoError(t, err)
+	require.Equal(t, "René Descartes", author.Name)
+
+	query, err := ParseQuery("author:descartes title:bug1")
+	require.NoError(t, err)
+	require.Len(t, remoteBugs.QueryBugs(query), 1)
+}
+
+func TestRemoveBug(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	iden1, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	err = cache.SetUserIdentity(iden1)
+	require.NoError(t, err)
+
+	bug1, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+	_, _, err = cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	tombstone, err := cache.RemoveBug(bug1.Id(), "duplicate")
+	require.NoError(t, err)
+	require.Equal(t, bug1.Id(), tombstone.BugId)
+	require.Len(t, cache.AllBugsIds(), 1)
+
+	_, err = cache.ResolveBug(bug1.Id())
+	require.Error(t, err)
+
+	// the removal survive a reload of the cache
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	require.Len(t, cache.AllBugsIds(), 1)
+}
+
+func TestPullIncompleteHistory(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	cacheA, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+
+	rene, err := cacheA.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cacheA.SetUserIdentity(rene))
+	spammer, err := cacheA.NewIdentity("Spammer", "spam@spam.com")
+	require.NoError(t, err)
+
+	bug1, _, err := cacheA.NewBug("bug1", "message")
+	require.NoError(t, err)
+	_, err = bug1.AddComment("comment")
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	spam, _, err := cacheA.NewBugRaw(spammer, time.Now().Unix(), "spam", "spam", nil, nil)
+	require.NoError(t, err)
+
+	_, err = cacheA.Push("origin")
+	require.NoError(t, err)
+
+	// fetch only the last commit of the bugs, as a shallow clone would
+	require.NoError(t, identity.Pull(repoB, "origin"))
+	out, err := exec.Command("git", "--git-dir", repoB.GetPath(),
+		"fetch", "--depth=1", "file://"+remote.GetPath(), "+refs/bugs/*:refs/bugs/*").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	// bug1 is left out, but not the bug of a single commit
+	cacheB, err := NewRepoCache(repoB)
+	require.NoError(t, err)
+	require.Len(t, cacheB.AllBugsIds(), 1)
+
+	reneB, err := cacheB.ResolveIdentity(rene.Id())
+	require.NoError(t, err)
+	require.NoError(t, cacheB.SetUserIdentity(reneB))
+	require.NoError(t, cacheB.BlockIdentity(spammer.Id(), "spam"))
+
+	// the bugs left out are remembered in the cache file
+	require.NoError(t, cacheB.Close())
+	cacheB, err = NewRepoCache(repoB)
+	require.NoError(t, err)
+
+	merge := func() map[entity.Id]entity.MergeStatus {
+		_, err := cacheB.Fetch("origin")
+		require.NoError(t, err)
+
+		result := make(map[entity.Id]entity.MergeStatus)
+		for merge := range cacheB.MergeAll("origin") {
+			require.NoError(t, merge.Err)
+			result[merge.Id] = merge.Status
+		}
+		return result
+	}
+
+	// bug1 is complete once fetched again, and reported as new, unlike the
+	// bug of a blocked identity left out of the cache
+	statuses := merge()
+	require.Equal(t, entity.MergeStatusNew, statuses[bug1.Id()])
+	require.Equal(t, entity.MergeStatusNothing, statuses[spam.Id()])
+	require.Equal(t, []entity.Id{bug1.Id()}, cacheB.AllBugsIds())
+
+	// only once
+	statuses = merge()
+	require.Equal(t, entity.MergeStatusNothing, statuses[bug1.Id()])
+	require.Equal(t, entity.MergeStatusNothing, statuses[spam.Id()])
+}
+
+func TestModeration(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	err = cache.SetUserIdentity(rene)
+	require.NoError(t, err)
+
+	spammer, err := cache.NewIdentity("Spammer", "spam@spam.com")
+	require.NoError(t, err)
+
+	bug1, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+	_, err = bug1.AddCommentRaw(spammer, time.Now().Unix(), "buy now", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, bug1.Commit())
+
+	_, _, err = cache.NewBugRaw(spammer, time.Now().Unix(), "spam", "spam", nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, cache.AllBugsIds(), 2)
+	require.Len(t, bug1.Snapshot().Comments, 2)
+
+	require.NoError(t, cache.BlockIdentity(spammer.Id(), "spam"))
+	require.True(t, cache.IsBlocked(spammer.Id()))
+
+	require.Len(t, cache.AllBugsIds(), 1)
+	bug1, err = cache.ResolveBug(bug1.Id())
+	require.NoError(t, err)
+	require.Len(t, bug1.Snapshot().Comments, 1)
+
+	excerpt, err := cache.ResolveBugExcerpt(bug1.Id())
+	require.NoError(t, err)
+	require.Equal(t, 1, excerpt.LenComments)
+
+	// the blocklist is honored after a reload
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	require.Len(t, cache.AllBugsIds(), 1)
+
+	require.NoError(t, cache.UnblockIdentity(spammer.Id()))
+	require.Len(t, cache.AllBugsIds(), 2)
+}
+
+func TestAnonymousBug(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	// no user identity required
+	b, _, err := cache.NewAnonymousBug("title", "message", nil, nil)
+	require.NoError(t, err)
+	require.True(t, b.Snapshot().Anonymous)
+
+	excerpt, err := cache.ResolveBugExcerpt(b.Id())
+	require.NoError(t, err)
+	require.True(t, excerpt.Anonymous)
+
+	author, err := cache.ResolveIdentityExcerpt(excerpt.AuthorId)
+	require.NoError(t, err)
+	require.Equal(t, "Anonymous", author.Name)
+
+	isSet, err := cache.IsUserIdentitySet()
+	require.NoError(t, err)
+	require.False(t, isSet)
+}
+
+func TestUseIdentity(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	work, err := cache.NewIdentity("René Descartes", "rene@work.fr")
+	require.NoError(t, err)
+	personal, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+
+	err = cache.SetUserIdentity(work)
+	require.NoError(t, err)
+
+	b1, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+	require.Equal(t, work.Id(), b1.Snapshot().Author.Id())
+
+	// author the following changes as another identity, without changing the
+	// configured one
+	cache.UseIdentity(personal)
+
+	b2, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+	require.Equal(t, personal.Id(), b2.Snapshot().Author.Id())
+
+	op, err := b1.AddComment("comment")
+	require.NoError(t, err)
+	require.Equal(t, personal.Id(), op.Author.Id())
+
+	require.NoError(t, cache.Close())
+
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	userIden, err := cache.GetUserIdentity()
+	require.NoError(t, err)
+	require.Equal(t, work.Id(), userIden.Id())
+}
+
+func TestMeQuery(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	// "me" designate the user identity, not the identities matching "me"
+	mehdi, err := cache.NewIdentity("Mehdi", "mehdi@example.fr")
+	require.NoError(t, err)
+
+	cache.UseIdentity(mehdi)
+	b1, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	cache.UseIdentity(rene)
+	b2, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+	_, err = b1.AddComment("comment")
+	require.NoError(t, err)
+	_, err = b1.ChangeAssignees([]*IdentityCache{rene}, nil)
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+
+	query := func(q string) []entity.Id {
+		parsed, err := ParseQuery(q)
+		require.NoError(t, err)
+		return cache.QueryBugs(parsed)
+	}
+
+	require.Equal(t, []entity.Id{b2.Id()}, query("author:me"))
+	require.Equal(t, []entity.Id{b1.Id()}, query("assignee:me"))
+	require.ElementsMatch(t, []entity.Id{b1.Id(), b2.Id()}, query("participant:me"))
+	require.ElementsMatch(t, []entity.Id{b1.Id(), b2.Id()}, query("actor:ME"))
+	require.Equal(t, []entity.Id{b1.Id()}, query("author:mehdi"))
+
+	cache.UseIdentity(mehdi)
+	require.Equal(t, []entity.Id{b1.Id()}, query("author:me"))
+	require.Empty(t, query("assignee:me"))
+}
+
+func TestIdentityActivity(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	isaac, err := cache.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.SetUserIdentity(rene))
+	b1, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+	_, _, err = cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.SetUserIdentity(isaac))
+	_, err = b1.AddComment("comment")
+	require.NoError(t, err)
+	_, _, err = b1.ChangeLabels([]string{"bug"}, nil)
+	require.NoError(t, err)
+	b3, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	activity := cache.IdentityActivity(rene.Id())
+	require.Len(t, activity.Authored, 2)
+	require.Empty(t, activity.Commented)
+	require.Equal(t, 2, activity.Involved)
+
+	activity = cache.IdentityActivity(isaac.Id())
+	require.Equal(t, []entity.Id{b3.Id()}, activity.Authored)
+	require.Equal(t, []entity.Id{b1.Id()}, activity.Commented)
+	require.Equal(t, 2, activity.Involved)
+	require.NotZero(t, activity.LastActivityUnixTime)
+
+	ops, err := cache.RecentOperations(isaac.Id(), 0)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+	for _, op := range ops {
+		require.Equal(t, isaac.Id(), op.Operation.GetAuthor().Id())
+	}
+
+	ops, err = cache.RecentOperations(isaac.Id(), 1)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+}
+
+func TestResolveReference(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	other := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote, other)
+
+	require.NoError(t, other.AddRemote("origin", "https://example.com/org/other.git"))
+
+	cacheA, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+	rene, err := cacheA.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cacheA.SetUserIdentity(rene))
+	local, _, err := cacheA.NewBug("local", "message")
+	require.NoError(t, err)
+
+	cacheOther, err := NewRepoCache(other)
+	require.NoError(t, err)
+	reneOther, err := cacheOther.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cacheOther.SetUserIdentity(reneOther))
+	linked, _, err := cacheOther.NewBug("linked", "message")
+	require.NoError(t, err)
+	require.NoError(t, cacheOther.Close())
+
+	remotes, err := repoA.GetRemotes()
+	require.NoError(t, err)
+
+	// a bug shared through a remote
+	resolved, err := cacheA.ResolveReference(bug.Reference{
+		RepoUrl: remotes["origin"],
+		BugId:   local.Id().Human(),
+	})
+	require.NoError(t, err)
+	require.True(t, resolved.Local)
+	require.Equal(t, "local", resolved.Excerpt.Title)
+
+	// a bug of another repository
+	ref := bug.Reference{
+		RepoUrl: "https://example.com/org/other",
+		BugId:   linked.Id().Human(),
+	}
+	_, err = cacheA.ResolveReference(ref)
+	require.Equal(t, ErrUnresolvedReference, err)
+
+	require.NoError(t, repoA.LocalConfig().StoreString("git-bug.linked-repository.other", other.GetPath()))
+	resolved, err = cacheA.ResolveReference(ref)
+	require.NoError(t, err)
+	require.False(t, resolved.Local)
+	require.Equal(t, "linked", resolved.Excerpt.Title)
+}
+
+func TestComponents(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+	isaac, err := cache.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+
+	config := repo.LocalConfig()
+	require.NoError(t, config.StoreString("git-bug.components", "core, webui"))
+	require.NoError(t, config.StoreString("git-bug.component.core.paths", "cache/ bug/"))
+	require.NoError(t, config.StoreString("git-bug.component.webui.paths", "webui/"))
+	require.NoError(t, config.StoreString("git-bug.component.webui.assignees", isaac.Id().Human()))
+
+	components, err := cache.Components()
+	require.NoError(t, err)
+	require.Len(t, components, 2)
+
+	suggested, ok, err := cache.SuggestComponent("crash in ./webui/src/App.tsx")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "webui", suggested)
+
+	_, ok, err = cache.SuggestComponent("crash in termui/")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	b, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	_, err = b.SetComponent("unknown")
+	require.Error(t, err)
+
+	// the default assignees of the component are assigned
+	_, err = b.SetComponent("webui")
+	require.NoError(t, err)
+	require.Equal(t, "webui", b.Snapshot().Component)
+	require.True(t, b.Snapshot().IsAssigned(isaac.Id()))
+
+	// but don't override the existing assignees
+	_, err = b.ChangeAssignees([]*IdentityCache{rene}, []*IdentityCache{isaac})
+	require.NoError(t, err)
+	_, err = b.SetComponent("core")
+	require.NoError(t, err)
+	require.Len(t, b.Snapshot().Assignees, 1)
+	require.NoError(t, b.Commit())
+
+	query, err := ParseQuery("component:core")
+	require.NoError(t, err)
+	require.Len(t, cache.QueryBugs(query), 1)
+
+	query, err = ParseQuery("assignee:isaac")
+	require.NoError(t, err)
+	require.Len(t, cache.QueryBugs(query), 0)
+
+	query, err = ParseQuery("no:assignee")
+	require.NoError(t, err)
+	require.Len(t, cache.QueryBugs(query), 0)
+}
+
+func TestSearchBugs(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	crash, _, err := cache.NewBug("Crash when pushing", "message")
+	require.NoError(t, err)
+	cookies, _, err := cache.NewBug("Add cookies in the web UI", "message")
+	require.NoError(t, err)
+	_, _, err = cache.NewBug("Improve the documentation", "message")
+	require.NoError(t, err)
+
+	require.Equal(t, []entity.Id{crash.Id()}, cache.SearchBugs("crsh push", 10))
+	require.Equal(t, []entity.Id{cookies.Id()}, cache.SearchBugs(cookies.Id().Human(), 10))
+
+	// the matches on the start of the words rank first
+	result := cache.SearchBugs("cu", 10)
+	require.Len(t, result, 3)
+	require.Equal(t, cookies.Id(), result[0])
+
+	require.Len(t, cache.SearchBugs("", 2), 2)
+	require.Empty(t, cache.SearchBugs("nothing matching", 10))
+}
+
+func TestSearchBugsPrefix(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	crash, _, err := cache.NewBug("Crash when pushing", "message")
+	require.NoError(t, err)
+	push, _, err := cache.NewBug("Push the bridges", "message")
+	require.NoError(t, err)
+	doc, _, err := cache.NewBug("Improve the documentation", "message")
+	require.NoError(t, err)
+
+	require.Equal(t, []entity.Id{crash.Id()}, cache.SearchBugsPrefix("cra pus", 10))
+	require.Equal(t, []entity.Id{doc.Id()}, cache.SearchBugsPrefix(doc.Id().Human(), 10))
+	require.Empty(t, cache.SearchBugsPrefix("crsh", 10))
+	require.Empty(t, cache.SearchBugsPrefix("", 10))
+
+	// the full words rank first
+	require.Equal(t, []entity.Id{push.Id(), crash.Id()}, cache.SearchBugsPrefix("push", 10))
+	require.Len(t, cache.SearchBugsPrefix("pu", 1), 1)
+
+	// the index follow the changes of the titles
+	_, err = doc.SetTitle("Document the bridges")
+	require.NoError(t, err)
+	require.Empty(t, cache.SearchBugsPrefix("improve", 10))
+	require.Equal(t, []entity.Id{doc.Id()}, cache.SearchBugsPrefix("document", 10))
+
+	_, err = cache.RemoveBug(push.Id(), "")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{doc.Id()}, cache.SearchBugsPrefix("bridges", 10))
+
+	// the index is built again from the cache file
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	require.Equal(t, []entity.Id{crash.Id()}, cache.SearchBugsPrefix("crash", 10))
+	require.Equal(t, []entity.Id{doc.Id()}, cache.SearchBugsPrefix("bridges", 10))
+}
+
+func TestDefaultRemote(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	cache, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+
+	r, err := cache.DefaultRemote()
+	require.NoError(t, err)
+	require.Equal(t, "origin", r)
+
+	require.Error(t, cache.SetDefaultRemote("unknown"))
+
+	require.NoError(t, cache.SetDefaultRemote("origin"))
+	r, err = cache.DefaultRemote()
+	require.NoError(t, err)
+	require.Equal(t, "origin", r)
+}
+
+func TestBatch(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	b, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	err = b.Batch(func() error {
+		if _, err := b.AddComment("comment"); err != nil {
+			return err
+		}
+		if _, _, err := b.ChangeLabels([]string{"bug"}, nil); err != nil {
+			return err
+		}
+		_, err := b.Close()
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	require.Len(t, b.Snapshot().Operations, 4)
+	require.Equal(t, bug.ClosedStatus, cache.bugExcerpts[b.Id()].Status)
+
+	// a failing edit discard the whole batch
+	err = b.Batch(func() error {
+		if _, err := b.Open(); err != nil {
+			return err
+		}
+		_, err := b.SetTitle("")
+		return err
+	})
+	require.Error(t, err)
+	require.False(t, b.NeedCommit())
+	require.Len(t, b.Snapshot().Operations, 4)
+	require.Equal(t, bug.ClosedStatus, b.Snapshot().Status)
+}
+
+func TestTransaction(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	b1, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	cache.BeginTransaction()
+	cache.BeginTransaction()
+
+	// a new bug is found right away
+	b2, _, err := cache.NewBug("other", "message")
+	require.NoError(t, err)
+	_, err = cache.ResolveBugExcerpt(b2.Id())
+	require.NoError(t, err)
+
+	// but the update of an existing one are on hold
+	_, err = b1.SetTitle("new title")
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+	require.Equal(t, "title", cache.bugExcerpts[b1.Id()].Title)
+
+	require.NoError(t, cache.CommitTransaction())
+	require.Equal(t, "title", cache.bugExcerpts[b1.Id()].Title)
+
+	require.NoError(t, cache.CommitTransaction())
+	require.Equal(t, "new title", cache.bugExcerpts[b1.Id()].Title)
+
+	// the cache file has been written
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	require.Equal(t, "new title", cache.bugExcerpts[b1.Id()].Title)
+}
+
+func TestTransactionCommits(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.policies", "noclose"))
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.policy.noclose.on", "close"))
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	b1, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	cache.BeginTransaction()
+
+	_, err = b1.AddComment("comment")
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+	_, err = b1.SetTitle("new title")
+	require.NoError(t, err)
+	require.NoError(t, b1.CommitAsNeeded())
+
+	// the commits are not written in git yet
+	read, err := bug.ReadLocalBug(repo, b1.Id())
+	require.NoError(t, err)
+	require.Len(t, read.CommittedOperations(), 1)
+
+	// a refused change only drop its own operations
+	_, err = b1.Close()
+	require.NoError(t, err)
+	require.IsType(t, ErrPolicy{}, b1.Commit())
+	require.Equal(t, 2, b1.bug.StagingSize())
+
+	require.NoError(t, cache.CommitTransaction())
+	require.False(t, b1.NeedCommit())
+
+	// a single commit holding both operations
+	read, err = bug.ReadLocalBug(repo, b1.Id())
+	require.NoError(t, err)
+	require.Len(t, read.CommittedOperations(), 3)
+	require.Equal(t, "new title", read.Compile().Title)
+	require.Equal(t, "new title", cache.bugExcerpts[b1.Id()].Title)
+	commits, err := repo.ListCommits("refs/bugs/" + b1.Id().String())
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	// committing again with nothing new keep the commits in the batch, and
+	// the next changes are still checked
+	cache.BeginTransaction()
+
+	_, err = b1.AddComment("another comment")
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+	require.NoError(t, b1.CommitAsNeeded())
+	require.Error(t, b1.Commit())
+
+	read, err = bug.ReadLocalBug(repo, b1.Id())
+	require.NoError(t, err)
+	require.Len(t, read.CommittedOperations(), 3)
+
+	_, err = b1.Close()
+	require.NoError(t, err)
+	require.IsType(t, ErrPolicy{}, b1.Commit())
+	require.Equal(t, 1, b1.bug.StagingSize())
+
+	require.NoError(t, cache.CommitTransaction())
+
+	read, err = bug.ReadLocalBug(repo, b1.Id())
+	require.NoError(t, err)
+	require.Len(t, read.CommittedOperations(), 4)
+	commits, err = repo.ListCommits("refs/bugs/" + b1.Id().String())
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+
+	require.NoError(t, cache.Close())
+}
+
+func TestEvents(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	b1, _, err := cache.NewBug("first", "message")
+	require.NoError(t, err)
+	b2, _, err := cache.NewBug("second", "message")
+	require.NoError(t, err)
+
+	_, err = b1.AddComment("comment")
+	require.NoError(t, err)
+	_, err = b1.Close()
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+
+	// not committed yet, so not part of the stream
+	_, err = b2.AddComment("comment")
+	require.NoError(t, err)
+
+	events, err := cache.Events(0, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	require.Equal(t, b1.Id(), events[0].BugId)
+	require.Equal(t, b2.Id(), events[1].BugId)
+	require.IsType(t, &bug.AddCommentOperation{}, events[2].Operation)
+	require.IsType(t, &bug.SetStatusOperation{}, events[3].Operation)
+	require.Equal(t, events[2].EditTime, events[3].EditTime)
+
+	// only the new events
+	require.NoError(t, b2.Commit())
+	events, err = cache.Events(events[3].EditTime, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, b2.Id(), events[0].BugId)
+}
+
+func TestDuplicates(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	canonical, _, err := cache.NewBug("Crash when pushing", "message")
+	require.NoError(t, err)
+	duplicate, _, err := cache.NewBug("Push crash", "message")
+	require.NoError(t, err)
+	other, _, err := cache.NewBug("Another bug", "message")
+	require.NoError(t, err)
+
+	_, err = duplicate.MarkDuplicate(duplicate)
+	require.Error(t, err)
+
+	_, err = duplicate.MarkDuplicate(canonical)
+	require.NoError(t, err)
+	require.Equal(t, canonical.Id(), duplicate.Snapshot().DuplicateOf)
+	require.Equal(t, bug.ClosedStatus, duplicate.Snapshot().Status)
+
+	// a duplicate can't be the canonical bug
+	_, err = other.MarkDuplicate(duplicate)
+	require.Error(t, err)
+
+	require.Equal(t, []entity.Id{duplicate.Id()}, cache.DuplicatesOf(canonical.Id()))
+
+	// the duplicates are excluded by default
+	query, err := ParseQuery("")
+	require.NoError(t, err)
+	require.Len(t, cache.QueryBugs(query), 2)
+
+	query, err = ParseQuery("duplicate:yes")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{duplicate.Id()}, cache.QueryBugs(query))
+
+	query, err = ParseQuery("duplicate:any")
+	require.NoError(t, err)
+	require.Len(t, cache.QueryBugs(query), 3)
+
+	// reopening the bug remove the relation
+	_, err = duplicate.Open()
+	require.NoError(t, err)
+	require.Empty(t, cache.DuplicatesOf(canonical.Id()))
+}
+
+func TestResolution(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	wontfix, _, err := cache.NewBug("Support Windows 95", "message")
+	require.NoError(t, err)
+	closed, _, err := cache.NewBug("Crash when pushing", "message")
+	require.NoError(t, err)
+	_, _, err = cache.NewBug("Another bug", "message")
+	require.NoError(t, err)
+
+	_, err = wontfix.CloseWithResolution(bug.WontFixResolution)
+	require.NoError(t, err)
+	_, err = closed.Close()
+	require.NoError(t, err)
+
+	query, err := ParseQuery("resolution:wontfix")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{wontfix.Id()}, cache.QueryBugs(query))
+
+	query, err = ParseQuery("status:closed no:resolution")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{closed.Id()}, cache.QueryBugs(query))
+
+	// a reopened bug lose its resolution
+	_, err = wontfix.Open()
+	require.NoError(t, err)
+	require.Equal(t, bug.NoResolution, wontfix.Snapshot().Resolution)
+}
+
+func TestFrequentlyReopened(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	flapping, _, err := cache.NewBug("Flaky test", "message")
+	require.NoError(t, err)
+	once, _, err := cache.NewBug("Crash when pushing", "message")
+	require.NoError(t, err)
+	never, _, err := cache.NewBug("Another bug", "message")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = flapping.Close()
+		require.NoError(t, err)
+		_, err = flapping.Open()
+		require.NoError(t, err)
+	}
+
+	_, err = once.Close()
+	require.NoError(t, err)
+	_, err = once.Open()
+	require.NoError(t, err)
+
+	// opening an open bug is not a reopening
+	_, err = never.Open()
+	require.NoError(t, err)
+
+	require.Equal(t, 3, flapping.Snapshot().ReopenCount())
+	require.Equal(t, []entity.Id{flapping.Id(), once.Id()}, cache.FrequentlyReopened(1))
+	require.Equal(t, []entity.Id{flapping.Id()}, cache.FrequentlyReopened(2))
+
+	query, err := ParseQuery("reopened:>0")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []entity.Id{flapping.Id(), once.Id()}, cache.QueryBugs(query))
+
+	query, err = ParseQuery("reopened:0")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{never.Id()}, cache.QueryBugs(query))
+}
+
+func TestConcurrentRepositories(t *testing.T) {
+	repoA := repository.CreateTestRepo(false)
+	repoB := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repoA, repoB)
+
+	multi := NewMultiRepoCache()
+	defer multi.Close()
+
+	query, err := ParseQuery("")
+	require.NoError(t, err)
+
+	// the goroutines report their failures for the test to assert on them
+	var group errgroup.Group
+	for name, repo := range map[string]*repository.GitRepo{"a": repoA, "b": repoB} {
+		err := multi.RegisterRepository(name, repo)
+		require.NoError(t, err)
+
+		cache, err := multi.ResolveRepo(name)
+		require.NoError(t, err)
+		require.Equal(t, name, cache.Name())
+
+		iden, err := cache.NewIdentity("René Descartes", name+"@descartes.fr")
+		require.NoError(t, err)
+		err = cache.SetUserIdentity(iden)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			group.Go(func() error {
+				for j := 0; j < 5; j++ {
+					b, _, err := cache.NewBug("title", "message")
+					if err != nil {
+						return err
+					}
+					_, err = b.AddComment("comment")
+					if err != nil {
+						return err
+					}
+					err = b.Commit()
+					if err != nil {
+						return err
+					}
+
+					excerpt, err := cache.GetUserIdentityExcerpt()
+					if err != nil {
+						return err
+					}
+					if excerpt.Id != iden.Id() {
+						return fmt.Errorf("the user identity is %s, expected %s", excerpt.Id, iden.Id())
+					}
+
+					cache.QueryBugs(query)
+				}
+				return nil
+			})
+		}
+	}
+	require.NoError(t, group.Wait())
+
+	// each repository only hold its own bugs, authored by its own user
+	for _, name := range []string{"a", "b"} {
+		cache, err := multi.ResolveRepo(name)
+		require.NoError(t, err)
+		require.Len(t, cache.AllBugsIds(), 10)
+
+		for _, id := range cache.AllBugsIds() {
+			b, err := cache.ResolveBug(id)
+			require.NoError(t, err)
+			require.Equal(t, name+"@descartes.fr", b.Snapshot().Author.Email())
+		}
+	}
+}
+
+func TestMultiRepoQuery(t *testing.T) {
+	repoA := repository.CreateTestRepo(false)
+	repoB := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repoA, repoB)
+
+	multi := NewMultiRepoCache()
+	defer multi.Close()
+
+	ids := make(map[string][]entity.Id)
+	for _, name := range []string{"b", "a"} {
+		repo := repoA
+		if name == "b" {
+			repo = repoB
+		}
+		err := multi.RegisterRepository(name, repo)
+		require.NoError(t, err)
+
+		cache, err := multi.ResolveRepo(name)
+		require.NoError(t, err)
+
+		iden, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+		require.NoError(t, err)
+		err = cache.SetUserIdentity(iden)
+		require.NoError(t, err)
+
+		for _, title := range []string{"first " + name, "second " + name} {
+			b, _, err := cache.NewBug(title, "message")
+			require.NoError(t, err)
+			ids[name] = append(ids[name], b.Id())
+		}
+	}
+
+	repos := multi.AllRepos()
+	require.Len(t, repos, 2)
+	require.Equal(t, "a", repos[0].Name())
+	require.Equal(t, "b", repos[1].Name())
+
+	// the bugs of all the repositories are merged, each repository keeping
+	// its order
+	query, err := ParseQuery("sort:creation-asc")
+	require.NoError(t, err)
+	all := multi.QueryBugs(query)
+	require.Len(t, all, 4)
+	result := make(map[string][]entity.Id)
+	for _, repoBug := range all {
+		result[repoBug.Repo.Name()] = append(result[repoBug.Repo.Name()], repoBug.Id)
+	}
+	require.Equal(t, ids, result)
+
+	query, err = ParseQuery("title:second")
+	require.NoError(t, err)
+	found := multi.QueryBugs(query)
+	require.Len(t, found, 2)
+	qualified := QualifiedBugId(found[0].Repo, found[0].Id)
+	require.Equal(t, found[0].Repo.Name()+"/"+found[0].Id.Human(), qualified)
+
+	repo, excerpt, err := multi.ResolveBugExcerptQualified(qualified)
+	require.NoError(t, err)
+	require.Equal(t, found[0].Repo, repo)
+	require.Equal(t, found[0].Id, excerpt.Id)
+
+	_, _, err = multi.ResolveBugExcerptQualified("c/" + found[0].Id.Human())
+	require.Error(t, err)
+
+	// without repository name, the id is resolved in the default repository,
+	// which doesn't exist with several repositories
+	_, _, err = multi.ResolveBugExcerptQualified(found[0].Id.Human())
+	require.Error(t, err)
+}
+
+func TestLabelsUsage(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	for _, labels := range [][]string{{"bug", "windows"}, {"bug", "ui"}, {"enhancement"}, {"bug"}} {
+		b, _, err := cache.NewBug("title", "message")
+		require.NoError(t, err)
+		_, _, err = b.ChangeLabels(labels, nil)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []LabelUsage{
+		{Label: "bug", Count: 3},
+		{Label: "enhancement", Count: 1},
+		{Label: "ui", Count: 1},
+		{Label: "windows", Count: 1},
+	}, cache.LabelsUsage())
+
+	suggestions := map[bug.Label]bug.Label{
+		"bgu":         "bug",
+		"Bug":         "bug",
+		"enhancment":  "enhancement",
+		"widnows":     "windows",
+		"ux":          "ui",
+		"u":           "ui",
+		"bug":         "",
+		"feature":     "",
+		"windows-xp":  "",
+		"enhancement": "",
+	}
+	for label, expected := range suggestions {
+		suggestion, ok := cache.SuggestLabel(label)
+		require.Equal(t, expected != "", ok, label)
+		require.Equal(t, expected, suggestion, label)
+	}
+}
+
+func TestReplaceLabel(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	var bugs []*BugCache
+	for _, labels := range [][]string{{"bug", "ui"}, {"defect"}, {"defect", "bug"}, {"ui"}} {
+		b, _, err := cache.NewBug("title", "message")
+		require.NoError(t, err)
+		_, _, err = b.ChangeLabels(labels, nil)
+		require.NoError(t, err)
+		require.NoError(t, b.Commit())
+		bugs = append(bugs, b)
+	}
+
+	// rename
+	ids, err := cache.ReplaceLabel("ui", "frontend")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []entity.Id{bugs[0].Id(), bugs[3].Id()}, ids)
+	require.Equal(t, []bug.Label{"bug", "frontend"}, bugs[0].Snapshot().Labels)
+
+	// merge
+	ids, err = cache.ReplaceLabel("defect", "bug")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []entity.Id{bugs[1].Id(), bugs[2].Id()}, ids)
+	require.Equal(t, []bug.Label{"bug"}, bugs[1].Snapshot().Labels)
+	require.Equal(t, []bug.Label{"bug"}, bugs[2].Snapshot().Labels)
+
+	require.Equal(t, []LabelUsage{
+		{Label: "bug", Count: 3},
+		{Label: "frontend", Count: 2},
+	}, cache.LabelsUsage())
+
+	// the changes are stored in the repository
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+	require.Len(t, cache.LabelsUsage(), 2)
+}
+
+func TestLabelRegistry(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	b, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels([]string{"bug", "good first issue"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	// a label is offered before any bug has it
+	red := bug.LabelColor{R: 255, A: 255}
+	require.NoError(t, cache.CreateLabel("needs triage", &red))
+	require.Error(t, cache.CreateLabel("bug", nil))
+	require.Contains(t, cache.ValidLabels(), bug.Label("needs triage"))
+	require.Equal(t, red, cache.LabelColor("needs triage"))
+
+	blue := bug.LabelColor{B: 255, A: 255}
+	require.NoError(t, cache.SetLabelColor("good first issue", blue))
+	require.Error(t, cache.SetLabelColor("unknown", blue))
+
+	// a renamed label keep its color
+	ids, err := cache.RenameLabel("good first issue", "easy")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{b.Id()}, ids)
+	require.Equal(t, blue, cache.LabelColor("easy"))
+	require.Equal(t, bug.Label("good first issue").Color(), cache.LabelColor("good first issue"))
+	_, err = cache.RenameLabel("easy", "bug")
+	require.Error(t, err)
+
+	_, err = cache.MergeLabel("easy", "needs triage")
+	require.NoError(t, err)
+	require.Equal(t, []bug.Label{"bug", "needs triage"}, b.Snapshot().Labels)
+
+	// the registry is stored in the repository
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	entries, err := cache.LabelEntries()
+	require.NoError(t, err)
+	require.Equal(t, []LabelEntry{
+		{Label: "bug", Color: bug.Label("bug").Color(), Count: 1},
+		{Label: "needs triage", Color: red, Registered: true, Count: 1},
+	}, entries)
+}
+
+func TestSchedules(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	config := repo.LocalConfig()
+	require.NoError(t, config.StoreString("git-bug.schedules", "credentials"))
+	require.NoError(t, config.StoreString("git-bug.schedule.credentials.cron", "0 9 1 * *"))
+	require.NoError(t, config.StoreString("git-bug.schedule.credentials.title", "Rotate the credentials"))
+	require.NoError(t, config.StoreString("git-bug.schedule.credentials.labels", "chore"))
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	schedules, err := cache.Schedules()
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	next, ok := schedules[0].Next(time.Date(2020, 3, 15, 0, 0, 0, 0, time.Local))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2020, 4, 1, 9, 0, 0, 0, time.Local), next)
+
+	created, err := cache.RunSchedules(time.Date(2020, 3, 15, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.Equal(t, "Rotate the credentials", created[0].Snapshot().Title)
+	require.Equal(t, []bug.Label{"chore"}, created[0].Snapshot().Labels)
+
+	// the occurrence has its bug already
+	created, err = cache.RunSchedules(time.Date(2020, 3, 20, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Empty(t, created)
+
+	created, err = cache.RunSchedules(time.Date(2020, 4, 1, 9, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	// still known after reloading the cache
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	created, err = cache.RunSchedules(time.Date(2020, 4, 2, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Empty(t, created)
+
+	require.NoError(t, config.StoreString("git-bug.schedule.credentials.cron", "61 * * * *"))
+	_, err = cache.Schedules()
+	require.Error(t, err)
+}
+
+func TestParent(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	epic, _, err := cache.NewBug("Epic", "message")
+	require.NoError(t, err)
+	task1, _, err := cache.NewBug("Task 1", "message")
+	require.NoError(t, err)
+	task2, _, err := cache.NewBug("Task 2", "message")
+	require.NoError(t, err)
+
+	_, err = task1.SetParent(epic)
+	require.NoError(t, err)
+	_, err = task2.SetParent(epic)
+	require.NoError(t, err)
+	_, err = task1.Close()
+	require.NoError(t, err)
+	require.NoError(t, task1.Commit())
+	require.NoError(t, task2.Commit())
+
+	excerpt, err := cache.ResolveBugExcerpt(epic.Id())
+	require.NoError(t, err)
+	require.Equal(t, 2, excerpt.Children)
+	require.Equal(t, 1, excerpt.ChildrenClosed)
+	require.Equal(t, []entity.Id{task1.Id(), task2.Id()}, cache.ChildrenOf(epic.Id()))
+
+	// no cycle
+	_, err = epic.SetParent(task1)
+	require.Error(t, err)
+	_, err = epic.SetParent(epic)
+	require.Error(t, err)
+
+	query, err := ParseQuery("parent:" + epic.Id().Human())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []entity.Id{task1.Id(), task2.Id()}, cache.QueryBugs(query))
+
+	query, err = ParseQuery("no:parent")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{epic.Id()}, cache.QueryBugs(query))
+
+	_, err = task2.RemoveParent()
+	require.NoError(t, err)
+	_, err = task2.RemoveParent()
+	require.Error(t, err)
+	require.NoError(t, task2.Commit())
+
+	excerpt, err = cache.ResolveBugExcerpt(epic.Id())
+	require.NoError(t, err)
+	require.Equal(t, 1, excerpt.Children)
+	require.Equal(t, 1, excerpt.ChildrenClosed)
+
+	// the roll-up is computed when building the cache from scratch
+	require.NoError(t, cache.Close())
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+	require.NoError(t, cache.buildCache())
+
+	excerpt, err = cache.ResolveBugExcerpt(epic.Id())
+	require.NoError(t, err)
+	require.Equal(t, 1, excerpt.Children)
+	require.Equal(t, 1, excerpt.ChildrenClosed)
+}
+
+func TestChecklist(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	b, _, err := cache.NewBug("title", "message")
+	require.NoError(t, err)
+
+	item1, err := b.AddChecklistItem("write the code")
+	require.NoError(t, err)
+	_, err = b.AddChecklistItem("write the tests")
+	require.NoError(t, err)
+	_, err = b.AddChecklistItem("multi\nline")
+	require.Error(t, err)
+
+	_, err = b.CheckItem(item1.Id(), true)
+	require.NoError(t, err)
+	_, err = b.CheckItem(item1.Id(), true)
+	require.Error(t, err)
+	_, err = b.CheckItem(b.Id(), true)
+	require.Error(t, err)
+	require.NoError(t, b.Commit())
+
+	excerpt, err := cache.ResolveBugExcerpt(b.Id())
+	require.NoError(t, err)
+	require.Equal(t, 1, excerpt.ChecklistChecked)
+	require.Equal(t, 2, excerpt.ChecklistTotal)
+
+	require.NoError(t, cache.Close())
+}
+
+func TestVotes(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	isaac, err := cache.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	popular, _, err := cache.NewBug("popular", "message")
+	require.NoError(t, err)
+	unpopular, _, err := cache.NewBug("unpopular", "message")
+	require.NoError(t, err)
+
+	_, err = popular.Vote()
+	require.NoError(t, err)
+	_, err = popular.Vote()
+	require.Error(t, err)
+	_, err = popular.VoteRaw(isaac, time.Now().Unix(), false, nil)
+	require.NoError(t, err)
+	require.NoError(t, popular.Commit())
+
+	_, err = unpopular.RetractVote()
+	require.Error(t, err)
+
+	excerpt, err := cache.ResolveBugExcerpt(popular.Id())
+	require.NoError(t, err)
+	require.Equal(t, 2, excerpt.Votes)
+
+	query, err := ParseQuery("sort:votes")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{popular.Id(), unpopular.Id()}, cache.QueryBugs(query))
+
+	_, err = popular.RetractVote()
+	require.NoError(t, err)
+	require.NoError(t, popular.Commit())
+
+	excerpt, err = cache.ResolveBugExcerpt(popular.Id())
+	require.NoError(t, err)
+	require.Equal(t, 1, excerpt.Votes)
+
+	require.NoError(t, cache.Close())
+}
+
+func TestReviewRequests(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	isaac, err := cache.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(isaac))
+
+	b, _, err := cache.NewBug("bug", "message")
+	require.NoError(t, err)
+	_, _, err = cache.NewBug("other", "message")
+	require.NoError(t, err)
+
+	_, err = b.RequestReview([]*IdentityCache{rene}, "what do you think?")
+	require.NoError(t, err)
+	_, err = b.RequestReview([]*IdentityCache{rene}, "")
+	require.Error(t, err)
+	require.NoError(t, b.Commit())
+
+	excerpt, err := cache.ResolveBugExcerpt(b.Id())
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{rene.Id()}, excerpt.PendingReviewers)
+
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	query, err := ParseQuery("review:me")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{b.Id()}, cache.QueryBugs(query))
+
+	// commenting answer the request
+	_, err = b.AddComment("looks good")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	require.Len(t, b.Snapshot().ReviewRequests, 1)
+	require.True(t, b.Snapshot().ReviewRequests[0].Answered)
+	require.Empty(t, cache.QueryBugs(query))
+
+	// the review can be requested again once answered
+	_, err = b.RequestReviewRaw(isaac, time.Now().Unix(), []*IdentityCache{rene}, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+	require.Equal(t, []entity.Id{b.Id()}, cache.QueryBugs(query))
+
+	require.NoError(t, cache.Close())
+}
+
+func TestExcerptFields(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	later, _, err := cache.NewBugWithFields("later", "message", nil, map[string]string{"due": "2024-03-01"}, nil)
+	require.NoError(t, err)
+	soon, _, err := cache.NewBugWithFields("soon", "message", nil, map[string]string{"due": "2024-01-15"}, nil)
+	require.NoError(t, err)
+	whenever, _, err := cache.NewBug("whenever", "message")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Close())
+
+	RegisterExcerptField(ExcerptField{
+		Name: "due",
+		Compute: func(b bug.Interface, snap *bug.Snapshot) string {
+			return snap.Fields["due"]
+		},
+	})
+	defer delete(excerptFields, "due")
+
+	require.Panics(t, func() {
+		RegisterExcerptField(ExcerptField{Name: "status", Compute: func(bug.Interface, *bug.Snapshot) string { return "" }})
+	})
+
+	// the excerpts of the cache file are computed again with the new field
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	excerpt, err := cache.ResolveBugExcerpt(soon.Id())
+	require.NoError(t, err)
+	require.Equal(t, "2024-01-15", excerpt.Fields["due"])
+
+	query, err := ParseQuery("due:2024-03-01")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{later.Id()}, cache.QueryBugs(query))
+
+	query, err = ParseQuery("no:due")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{whenever.Id()}, cache.QueryBugs(query))
+
+	query, err = ParseQuery("sort:due")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{whenever.Id(), soon.Id(), later.Id()}, cache.QueryBugs(query))
+
+	query, err = ParseQuery("no:label sort:due-desc")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{later.Id(), soon.Id(), whenever.Id()}, cache.QueryBugs(query))
+
+	_, err = ParseQuery("sort:unknown")
+	require.Error(t, err)
+}
+
+func TestReadMarkers(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	isaac, err := cache.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	unread, err := ParseQuery("is:unread")
+	require.NoError(t, err)
+
+	// the own bugs and edits of the user are read
+	own, _, err := cache.NewBug("own", "message")
+	require.NoError(t, err)
+	_, err = own.AddComment("comment")
+	require.NoError(t, err)
+	require.NoError(t, own.Commit())
+
+	other, _, err := cache.NewBugRaw(isaac, time.Now().Unix(), "other", "message", nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []entity.Id{other.Id()}, cache.QueryBugs(unread))
+
+	require.NoError(t, cache.MarkRead(other.Id()))
+	require.Empty(t, cache.QueryBugs(unread))
+
+	// the edit of someone else make the bug unread again
+	_, err = own.AddCommentRaw(isaac, time.Now().Unix(), "other comment", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, own.Commit())
+	require.Equal(t, []entity.Id{own.Id()}, cache.QueryBugs(unread))
+
+	require.NoError(t, cache.MarkUnread(other.Id()))
+	require.Len(t, cache.QueryBugs(unread), 2)
+
+	read, err := ParseQuery("is:read")
+	require.NoError(t, err)
+	require.Empty(t, cache.QueryBugs(read))
+
+	// the markers are per user and survive the cache
+	require.NoError(t, cache.MarkRead(own.Id()))
+	require.NoError(t, cache.Close())
+
+	cache, err = NewRepoCache(repo)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Id{other.Id()}, cache.QueryBugs(unread))
+
+	isaacCache, err := cache.ResolveIdentity(isaac.Id())
+	require.NoError(t, err)
+	cache.UseIdentity(isaacCache)
+	require.Len(t, cache.QueryBugs(unread), 2)
+
+	require.NoError(t, cache.Close())
+}
+
+func TestRequirements(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.required.labels", "type: triage"))
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.required.fields", "version"))
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	requirements, err := cache.Requirements()
+	require.NoError(t, err)
+	require.Equal(t, []string{"type:", "triage"}, requirements.Labels)
+	require.Equal(t, []string{"version"}, requirements.Fields)
+
+	_, _, err = cache.NewBug("title", "message")
+	require.Error(t, err)
+
+	_, _, err = cache.NewBugWithFields("title", "message", []string{"type:bug", "triage"}, nil, nil)
+	require.Error(t, err)
+
+	_, _, err = cache.NewAnonymousBug("title", "message", []string{"triage"}, map[string]string{"version": "1.2"})
+	require.Error(t, err)
+
+	b, _, err := cache.NewBugWithFields("title", "message",
+		[]string{"type:bug", "triage"}, map[string]string{"version": "1.2"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"version": "1.2"}, b.Snapshot().Fields)
+
+	excerpt, err := cache.ResolveBugExcerpt(b.Id())
+	require.NoError(t, err)
+	require.Equal(t, []bug.Label{"triage", "type:bug"}, excerpt.Labels)
+
+	// the imported bugs don't have to follow the requirements
+	_, _, err = cache.NewBugRaw(rene, time.Now().Unix(), "imported", "message", nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, cache.AllBugsIds(), 2)
+	require.NoError(t, cache.Close())
+}
+
+func TestPolicies(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	config := repo.LocalConfig()
+	require.NoError(t, config.StoreString("git-bug.policies", "blockers reopen"))
+	require.NoError(t, config.StoreString("git-bug.policy.blockers.on", "close"))
+	require.NoError(t, config.StoreString("git-bug.policy.blockers.label", "release-blocker"))
+	require.NoError(t, config.StoreString("git-bug.policy.blockers.message", "ask the release manager"))
+	require.NoError(t, config.StoreString("git-bug.policy.reopen.on", "open"))
+	require.NoError(t, config.StoreString("git-bug.policy.reopen.require", "comment"))
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	isaac, err := cache.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	policies, err := cache.Policies()
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+
+	blocker, _, err := cache.NewBugWithFields("blocker", "message", []string{"release-blocker"}, nil, nil)
+	require.NoError(t, err)
+
+	_, err = blocker.Close()
+	require.NoError(t, err)
+	err = blocker.Commit()
+	require.Equal(t, ErrPolicy{Policy: "blockers", Message: "ask the release manager"}, err)
+
+	// the refused operation is dropped
+	require.Equal(t, bug.OpenStatus, blocker.Snapshot().Status)
+	require.False(t, blocker.NeedCommit())
+	excerpt, err := cache.ResolveBugExcerpt(blocker.Id())
+	require.NoError(t, err)
+	require.Equal(t, bug.OpenStatus, excerpt.Status)
+
+	// the operations of the other identities are not checked
+	_, err = blocker.CloseRaw(isaac, time.Now().Unix(), nil)
+	require.NoError(t, err)
+	require.NoError(t, blocker.Commit())
+
+	other, _, err := cache.NewBug("other", "message")
+	require.NoError(t, err)
+	_, err = other.Close()
+	require.NoError(t, err)
+	require.NoError(t, other.Commit())
+
+	_, err = other.Open()
+	require.NoError(t, err)
+	require.IsType(t, ErrPolicy{}, other.Commit())
+
+	_, err = other.Open()
+	require.NoError(t, err)
+	_, err = other.AddComment("still happening")
+	require.NoError(t, err)
+	require.NoError(t, other.Commit())
+
+	require.NoError(t, config.StoreString("git-bug.policy.reopen.on", "nope"))
+	_, err = cache.Policies()
+	require.Error(t, err)
+	require.NoError(t, config.RemoveAll("git-bug.policies"))
+
+	// the hook refuses the bugs it is given with "forbidden" in them
+	hook := filepath.Join(filepath.Dir(repo.GetPath()), "policy-hook")
+	script := "#!/bin/sh\nif grep -q forbidden; then echo \"forbidden word\"; exit 1; fi\n"
+	require.NoError(t, ioutil.WriteFile(hook, []byte(script), 0755))
+	require.NoError(t, config.StoreString("git-bug.policy-hook", hook))
+
+	_, _, err = cache.NewBug("forbidden", "message")
+	require.Equal(t, ErrPolicy{Policy: "hook", Message: "forbidden word"}, err)
+
+	_, err = other.AddComment("forbidden")
+	require.NoError(t, err)
+	require.Error(t, other.Commit())
+	require.Len(t, other.Snapshot().Comments, 2)
+
+	_, err = other.AddComment("allowed")
+	require.NoError(t, err)
+	require.NoError(t, other.Commit())
+
+	require.Len(t, cache.AllBugsIds(), 2)
+	require.NoError(t, cache.Close())
+}
+
+func TestLint(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	config := repo.LocalConfig()
+	require.NoError(t, config.StoreString("git-bug.lint.max-title-length", "10"))
+	require.NoError(t, config.StoreString("git-bug.lint.forbidden-words", "ASAP, urgent"))
+	require.NoError(t, config.StoreString("git-bug.lint.required-sections", "Steps to reproduce, Expected"))
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	lint, err := cache.Lint()
+	require.NoError(t, err)
+	require.Equal(t, Lint{
+		MaxTitleLength:   10,
+		ForbiddenWords:   []string{"asap", "urgent"},
+		RequiredSections: []string{"Steps to reproduce", "Expected"},
+	}, lint)
+
+	description := "## Steps to reproduce\nrun it\n\n### expected:\nno crash"
+
+	_, _, err = cache.NewBug("crash at start", description)
+	require.Equal(t, ErrPolicy{Policy: "lint", Message: "the title is longer than 10 characters"}, err)
+
+	_, _, err = cache.NewBug("crash", "run it")
+	require.Equal(t, ErrPolicy{Policy: "lint", Message: "the description must have a \"Steps to reproduce\" section"}, err)
+
+	_, _, err = cache.NewBug("fix Asap", description)
+	require.Equal(t, ErrPolicy{Policy: "lint", Message: "the word \"asap\" is forbidden"}, err)
+
+	b, _, err := cache.NewBug("crash", description)
+	require.NoError(t, err)
+
+	// the words are matched whole
+	_, err = b.AddComment("that's urgent!")
+	require.NoError(t, err)
+	require.IsType(t, ErrPolicy{}, b.Commit())
+	_, err = b.AddComment("urgently")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	_, err = b.SetTitle("a crash at start")
+	require.NoError(t, err)
+	require.IsType(t, ErrPolicy{}, b.Commit())
+	require.Equal(t, "crash", b.Snapshot().Title)
+
+	// the operations of the other identities are not checked
+	isaac, err := cache.NewIdentity("Isaac Newton", "isaac@newton.uk")
+	require.NoError(t, err)
+	_, err = b.AddCommentRaw(isaac, time.Now().Unix(), "urgent", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	cache.DisableLint()
+	_, err = b.SetTitle("a crash at start")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	require.NoError(t, config.StoreString("git-bug.lint.max-title-length", "nope"))
+	_, err = cache.Lint()
+	require.Error(t, err)
+
+	require.NoError(t, cache.Close())
+}
+
+func TestStale(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	now := time.Now()
+	longAgo := now.AddDate(0, 0, -100).Unix()
+
+	old, _, err := cache.NewBugRaw(rene, longAgo, "old", "message", nil, nil)
+	require.NoError(t, err)
+	active, _, err := cache.NewBugRaw(rene, longAgo, "active", "message", nil, nil)
+	require.NoError(t, err)
+	pinned, _, err := cache.NewBugRaw(rene, longAgo, "pinned", "message", nil, nil)
+	require.NoError(t, err)
+	_, _, err = pinned.ChangeLabelsRaw(rene, longAgo, []string{"pinned"}, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, pinned.Commit())
+	_, _, err = cache.NewBugRaw(rene, now.AddDate(0, 0, -1).Unix(), "fresh", "message", nil, nil)
+	require.NoError(t, err)
+
+	_, err = cache.RunStale(now, false)
+	require.Equal(t, ErrStaleNotConfigured, err)
+
+	config := repo.LocalConfig()
+	require.NoError(t, config.StoreString("git-bug.stale.days", "30"))
+	require.NoError(t, config.StoreString("git-bug.stale.close-days", "7"))
+	require.NoError(t, config.StoreString("git-bug.stale.exempt-labels", "pinned"))
+
+	staleConfig, err := cache.StaleConfig()
+	require.NoError(t, err)
+	require.Equal(t, bug.Label("stale"), staleConfig.Label)
+
+	// nothing change in a dry run
+	changes, err := cache.RunStale(now, true)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []StaleChange{
+		{Id: old.Id(), Title: "old", Action: StaleMarked},
+		{Id: active.Id(), Title: "active", Action: StaleMarked},
+	}, changes)
+	require.Empty(t, old.Snapshot().Labels)
+
+	changes, err = cache.RunStale(now, false)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, []bug.Label{"stale"}, old.Snapshot().Labels)
+	require.Equal(t, "This bug has been marked as stale, as it had no activity for 30 days. It will be closed in 7 days if nothing happens.",
+		old.Snapshot().Comments[1].Message)
+
+	// marked already
+	changes, err = cache.RunStale(now, false)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = active.AddCommentRaw(rene, now.AddDate(0, 0, 1).Unix(), "still there", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, active.Commit())
+
+	changes, err = cache.RunStale(now.AddDate(0, 0, 8), false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []StaleChange{
+		{Id: old.Id(), Title: "old", Action: StaleClosed},
+		{Id: active.Id(), Title: "active", Action: StaleCleared},
+	}, changes)
+
+	require.Equal(t, bug.ClosedStatus, old.Snapshot().Status)
+	require.Equal(t, bug.OpenStatus, active.Snapshot().Status)
+	require.Empty(t, active.Snapshot().Labels)
+	require.Equal(t, bug.OpenStatus, pinned.Snapshot().Status)
+
+	require.NoError(t, config.StoreString("git-bug.stale.days", "soon"))
+	_, err = cache.StaleConfig()
+	require.Error(t, err)
+}
+
+func TestForms(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(rene))
+
+	// no form defined
+	forms, err := cache.Forms()
+	require.NoError(t, err)
+	require.Empty(t, forms)
+
+	dir := filepath.Join(filepath.Dir(repo.GetPath()), formsDir)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "crash-report.json"), []byte(`{
+		"title": "Crash report",
+		"labels": ["type:crash"],
+		"fields": [
+			{"id": "version", "type": "input", "label": "Version", "required": true},
+			{"id": "os", "type": "dropdown", "label": "Operating system", "options": ["linux", "macos"]}
+		]
+	}`), 0644))
+
+	forms, err = cache.Forms()
+	require.NoError(t, err)
+	require.Len(t, forms, 1)
+
+	_, err = cache.ResolveForm("feature")
+	require.Error(t, err)
+
+	form, err := cache.ResolveForm("crash-report")
+	require.NoError(t, err)
+
+	_, _, err = cache.NewBugFromForm("crash", form, map[string]string{"os": "linux"}, nil, nil)
+	require.Error(t, err)
+
+	b, op, err := cache.NewBugFromForm("crash", form, map[string]string{"version": "1.2", "os": "linux"}, nil, nil)
+	require.NoError(t, err)
+
+	snap := b.Snapshot()
+	require.Equal(t, []bug.Label{"type:crash"}, snap.Labels)
+	require.Equal(t, "### Version\n\n1.2\n\n### Operating system\n\nlinux", snap.Comments[0].Message)
+
+	name, answers := bug.FormAnswers(op)
+	require.Equal(t, "crash-report", name)
+	require.Equal(t, map[string]string{"version": "1.2", "os": "linux"}, answers)
+}
+
+func TestBugNumbers(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	cacheA, err := NewRepoCache(repoA)
+	require.NoError(t, err)
+	cacheB, err := NewRepoCache(repoB)
+	require.NoError(t, err)
+
+	rene, err := cacheA.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cacheA.SetUserIdentity(rene))
+
+	bug1, _, err := cacheA.NewBug("bug1", "message")
+	require.NoError(t, err)
+	bug2, _, err := cacheA.NewBug("bug2", "message")
+	require.NoError(t, err)
+
+	// no number without the numbering enabled
+	_, err = cacheA.Push("origin")
+	require.NoError(t, err)
+	require.False(t, cacheA.HasBugNumbers())
+
+	_, err = cacheA.ResolveBugPrefix("#1")
+	require.Equal(t, bug.ErrBugNotExist, err)
+
+	// the numbers are allocated when pushing, in the order of creation
+	require.NoError(t, repoA.LocalConfig().StoreBool(numberingConfigKey, true))
+	_, err = cacheA.Push("origin")
+	require.NoError(t, err)
+
+	number, ok := cacheA.BugNumber(bug1.Id())
+	require.True(t, ok)
+	require.Equal(t, 1, number)
+	number, ok = cacheA.BugNumber(bug2.Id())
+	require.True(t, ok)
+	require.Equal(t, 2, number)
+
+	b, err := cacheA.ResolveBugPrefix("#2")
+	require.NoError(t, err)
+	require.Equal(t, bug2.Id(), b.Id())
+
+	excerpt, err := cacheA.ResolveBugExcerptPrefix("#1")
+	require.NoError(t, err)
+	require.Equal(t, bug1.Id(), excerpt.Id)
+
+	// the hashes remain usable
+	b, err = cacheA.ResolveBugPrefix(bug1.Id().Human())
+	require.NoError(t, err)
+	require.Equal(t, bug1.Id(), b.Id())
+
+	// the numbers propagate with the bugs
+	require.NoError(t, cacheB.Pull("origin"))
+	b, err = cacheB.ResolveBugPrefix("#1")
+	require.NoError(t, err)
+	require.Equal(t, bug1.Id(), b.Id())
+
+	// a bug created in B gets the next number when B push
+	require.NoError(t, repoB.LocalConfig().StoreBool(numberingConfigKey, true))
+	reneB, err := cacheB.ResolveIdentity(rene.Id())
+	require.NoError(t, err)
+	require.NoError(t, cacheB.SetUserIdentity(reneB))
+	bug3, _, err := cacheB.NewBug("bug3", "message")
+	require.NoError(t, err)
+	_, ok = cacheB.BugNumber(bug3.Id())
+	require.False(t, ok)
+
+	_, err = cacheB.Push("origin")
+	require.NoError(t, err)
+	number, ok = cacheB.BugNumber(bug3.Id())
+	require.True(t, ok)
+	require.Equal(t, 3, number)
+
+	require.NoError(t, cacheA.Pull("origin"))
+	b, err = cacheA.ResolveBugPrefix("#3")
+	require.NoError(t, err)
+	require.Equal(t, bug3.Id(), b.Id())
+}
+
+func TestBugIdLength(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	cache, err := NewRepoCache(repo)
+	require.NoError(t, err)
+
+	iden, err := cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, cache.SetUserIdentity(iden))
+
+	require.Equal(t, 7, cache.BugIdLength())
+
+	// create bugs until two of them share the first character of their id
+	byFirst := make(map[byte]*BugCache)
+	var first, second *BugCache
+	for i := 0; first == nil; i++ {
+		b, _, err := cache.NewBug(fmt.Sprintf("bug %d", i), "message")
+		require.NoError(t, err)
+		if other, ok := byFirst[b.Id().String()[0]]; ok {
+			first, second = other, b
+		}
+		byFirst[b.Id().String()[0]] = b
+	}
+
+	// the ids are still unambiguous with the default length
+	require.Equal(t, 7, cache.BugIdLength())
+	require.Equal(t, first.Id().String()[:7], first.Id().Short(cache.BugIdLength()))
+
+	// an ambiguous prefix list the candidates with their title
+	_, err = cache.ResolveBugPrefix(first.Id().String()[:1])
+	require.IsType(t, &entity.ErrMultipleMatch{}, err)
+	require.Contains(t, err.Error(), first.Id().String()+" [open] "+first.Snapshot().Title)
+	require.Contains(t, err.Error(), second.Id().String()+" [open] "+second.Snapshot().Title)
+
+	ids := []entity.Id{
+		"1234567800000000000000000000000000000000000000000000000000000000",
+		"1234567890000000000000000000000000000000000000000000000000000000",
+		"abcdef0000000000000000000000000000000000000000000000000000000000",
+	}
+	require.Equal(t, 9, entity.UniquePrefixLength(ids))
+	require.Equal(t, "123456789", ids[1].Short(9))
+	require.Equal(t, "1234567", ids[1].Short(3))
 }
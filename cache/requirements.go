@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"github.com/MichaelMure/git-bug/bug"
+)
+
+// The labels and custom fields a new bug must have are configured in git:
+//
+//	git-bug.required.labels   the labels required, or the label prefixes as "type:"
+//	git-bug.required.fields   the names of the custom fields required, as "version"
+//
+// Both are lists separated by commas or spaces. They don't apply to the bugs
+// imported by the bridges or created by the schedules.
+const (
+	requiredLabelsConfigKey = "git-bug.required.labels"
+	requiredFieldsConfigKey = "git-bug.required.fields"
+)
+
+// Requirements return the labels and custom fields a new bug must have
+func (c *RepoCache) Requirements() (bug.Requirements, error) {
+	labels, err := readConfigList(c.repo.LocalConfig(), requiredLabelsConfigKey)
+	if err != nil {
+		return bug.Requirements{}, err
+	}
+
+	fields, err := readConfigList(c.repo.LocalConfig(), requiredFieldsConfigKey)
+	if err != nil {
+		return bug.Requirements{}, err
+	}
+
+	for _, field := range fields {
+		err = bug.ValidateFieldName(field)
+		if err != nil {
+			return bug.Requirements{}, err
+		}
+	}
+
+	return bug.Requirements{
+		Labels: labels,
+		Fields: fields,
+	}, nil
+}
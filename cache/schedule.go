@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/cron"
+)
+
+// The schedules of a repository, creating bugs periodically, are configured
+// in git:
+//
+//	git-bug.schedules                  the schedules, separated by commas or spaces
+//	git-bug.schedule.<name>.cron       when to create a bug, as a cron expression
+//	git-bug.schedule.<name>.title      the title of the bugs created
+//	git-bug.schedule.<name>.message    the message of the bugs created, if any
+//	git-bug.schedule.<name>.labels     the labels of the bugs created, if any
+const (
+	schedulesConfigKey = "git-bug.schedules"
+	scheduleCronKey    = "git-bug.schedule.%s.cron"
+	scheduleTitleKey   = "git-bug.schedule.%s.title"
+	scheduleMessageKey = "git-bug.schedule.%s.message"
+	scheduleLabelsKey  = "git-bug.schedule.%s.labels"
+
+	// the metadata of the bugs created by a schedule, to not create them
+	// twice
+	scheduleMetadataKey   = "git-bug-schedule"
+	scheduleOccurrenceKey = "git-bug-schedule-time"
+)
+
+// Schedule create a bug periodically, as a recurring chore
+type Schedule struct {
+	Name string
+	// the cron expression, as "0 9 1 * *"
+	Cron    string
+	Title   string
+	Message string
+	Labels  []string
+
+	schedule *cron.Schedule
+}
+
+// Next return the next time a bug will be created after t, or false if never
+func (s Schedule) Next(t time.Time) (time.Time, bool) {
+	return s.schedule.Next(t)
+}
+
+// Schedules return the configured schedules
+func (c *RepoCache) Schedules() ([]Schedule, error) {
+	config := c.repo.LocalConfig()
+
+	names, err := readConfigList(config, schedulesConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Schedule, 0, len(names))
+
+	for _, name := range names {
+		s := Schedule{Name: name}
+
+		s.Cron, err = config.ReadString(fmt.Sprintf(scheduleCronKey, name))
+		if err == repository.ErrNoConfigEntry {
+			return nil, fmt.Errorf("schedule %s: missing %s", name, fmt.Sprintf(scheduleCronKey, name))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.schedule, err = cron.Parse(s.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %v", name, err)
+		}
+
+		s.Title, err = config.ReadString(fmt.Sprintf(scheduleTitleKey, name))
+		if err == repository.ErrNoConfigEntry {
+			return nil, fmt.Errorf("schedule %s: missing %s", name, fmt.Sprintf(scheduleTitleKey, name))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.Message, err = config.ReadString(fmt.Sprintf(scheduleMessageKey, name))
+		if err != nil && err != repository.ErrNoConfigEntry {
+			return nil, err
+		}
+
+		s.Labels, err = readConfigList(config, fmt.Sprintf(scheduleLabelsKey, name))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+// RunSchedules create the bugs of the schedules due at the given time: for
+// each schedule, a bug is created for its last occurrence if none exist yet.
+// The missed occurrences before that are not caught up, and a new schedule
+// create its first bug right away. The user identity is the author of the
+// bugs.
+func (c *RepoCache) RunSchedules(now time.Time) ([]*BugCache, error) {
+	schedules, err := c.Schedules()
+	if err != nil {
+		return nil, err
+	}
+
+	var created []*BugCache
+
+	for _, s := range schedules {
+		occurrence, ok := s.schedule.Prev(now)
+		if !ok {
+			continue
+		}
+
+		occurrenceStr := strconv.FormatInt(occurrence.Unix(), 10)
+
+		if c.hasScheduledBug(s.Name, occurrenceStr) {
+			continue
+		}
+
+		author, err := c.GetUserIdentity()
+		if err != nil {
+			return created, err
+		}
+
+		b, _, err := c.NewBugRaw(author, now.Unix(), s.Title, s.Message, nil, map[string]string{
+			scheduleMetadataKey:   s.Name,
+			scheduleOccurrenceKey: occurrenceStr,
+		})
+		if err != nil {
+			return created, fmt.Errorf("schedule %s: %v", s.Name, err)
+		}
+
+		if len(s.Labels) > 0 {
+			_, _, err = b.ChangeLabels(s.Labels, nil)
+			if err != nil {
+				return created, fmt.Errorf("schedule %s: %v", s.Name, err)
+			}
+			err = b.Commit()
+			if err != nil {
+				return created, err
+			}
+		}
+
+		created = append(created, b)
+	}
+
+	return created, nil
+}
+
+// hasScheduledBug tell if a bug has already been created for an occurrence
+// of a schedule, locally or by another clone of the repository
+func (c *RepoCache) hasScheduledBug(name string, occurrence string) bool {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	for _, excerpt := range c.bugExcerpts {
+		if excerpt.CreateMetadata[scheduleMetadataKey] == name &&
+			excerpt.CreateMetadata[scheduleOccurrenceKey] == occurrence {
+			return true
+		}
+	}
+
+	return false
+}
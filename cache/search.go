@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// the score of a bug whose id start with the searched text, to always rank it
+// first
+const idPrefixScore = 1000
+
+// SearchBugs return the id of the bugs matching approximately a text, best
+// match first. The text is matched against the ids and the titles of the
+// bugs: all its characters have to be found in order, and consecutive
+// characters or characters starting a word make a better match.
+func (c *RepoCache) SearchBugs(text string, limit int) []entity.Id {
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	type match struct {
+		excerpt *BugExcerpt
+		score   int
+	}
+
+	var matches []match
+
+	for _, excerpt := range c.bugExcerpts {
+		score := 0
+		if text != "" && excerpt.Id.HasPrefix(text) {
+			score = idPrefixScore
+		} else if s, ok := fuzzyScore(text, strings.ToLower(excerpt.Title)); ok {
+			score = s
+		} else {
+			continue
+		}
+
+		matches = append(matches, match{excerpt: excerpt, score: score})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].excerpt.EditUnixTime > matches[j].excerpt.EditUnixTime
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]entity.Id, len(matches))
+	for i, m := range matches {
+		result[i] = m.excerpt.Id
+	}
+
+	return result
+}
+
+// SearchBugsPrefix return the id of the bugs matching a text typed so far:
+// each word of the text has to start a word of the title or the id of the
+// bug. The bugs whose id start with the text come first, then the bugs with
+// the most words fully typed, then the most recently edited.
+//
+// Unlike SearchBugs, the bugs are found through an index, for the search to
+// stay fast on large repositories while the text is typed.
+func (c *RepoCache) SearchBugsPrefix(text string, limit int) []entity.Id {
+	matches := c.searchBugsPrefix(text)
+	sortPrefixMatches(matches)
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]entity.Id, len(matches))
+	for i, m := range matches {
+		result[i] = m.excerpt.Id
+	}
+
+	return result
+}
+
+// a bug matching a prefix search
+type prefixMatch struct {
+	repo    *RepoCache
+	excerpt *BugExcerpt
+	score   int
+}
+
+func (c *RepoCache) searchBugsPrefix(text string) []prefixMatch {
+	words := searchWords(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	c.muBug.RLock()
+	defer c.muBug.RUnlock()
+
+	if c.searchIndex == nil {
+		return nil
+	}
+
+	// the bugs matching all the words so far, with the number of words
+	// matching a full word of the bug
+	var candidates map[entity.Id]int
+
+	for i, word := range words {
+		matched := c.searchIndex.match(word)
+
+		if i == 0 {
+			candidates = make(map[entity.Id]int, len(matched))
+			for id, full := range matched {
+				candidates[id] = 0
+				if full {
+					candidates[id] = 1
+				}
+			}
+			continue
+		}
+
+		for id := range candidates {
+			full, ok := matched[id]
+			if !ok {
+				delete(candidates, id)
+			} else if full {
+				candidates[id]++
+			}
+		}
+	}
+
+	idPrefix := strings.ToLower(strings.TrimSpace(text))
+
+	result := make([]prefixMatch, 0, len(candidates))
+	for id, score := range candidates {
+		if id.HasPrefix(idPrefix) {
+			score += idPrefixScore
+		}
+		result = append(result, prefixMatch{repo: c, excerpt: c.bugExcerpts[id], score: score})
+	}
+
+	return result
+}
+
+func sortPrefixMatches(matches []prefixMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].excerpt.EditUnixTime > matches[j].excerpt.EditUnixTime
+	})
+}
+
+// fuzzyScore tell if all the characters of the pattern appear in order in the
+// target, and how good the match is
+func fuzzyScore(pattern string, target string) (int, bool) {
+	score := 0
+	previous := -2
+	runes := []rune(target)
+	i := 0
+
+	for _, p := range pattern {
+		for i < len(runes) && runes[i] != p {
+			i++
+		}
+		if i >= len(runes) {
+			return 0, false
+		}
+
+		score++
+		if i == previous+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3
+		}
+
+		previous = i
+		i++
+	}
+
+	return score, true
+}
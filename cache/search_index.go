@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/MichaelMure/git-bug/entity"
+)
+
+// searchIndex index the bugs by the words of their title and by their id, so
+// that the bugs matching a prefix are found without going through all the
+// excerpts. It is kept up to date with the excerpts, and guarded by muBug.
+type searchIndex struct {
+	// the distinct tokens, sorted, for the prefixes to be found by binary
+	// search
+	tokens []string
+	// the bugs having each token
+	postings map[string]map[entity.Id]struct{}
+	// the tokens of each bug, to update them
+	bugTokens map[entity.Id][]string
+}
+
+func newSearchIndex(excerpts map[entity.Id]*BugExcerpt) *searchIndex {
+	idx := &searchIndex{
+		postings:  make(map[string]map[entity.Id]struct{}),
+		bugTokens: make(map[entity.Id][]string),
+	}
+
+	for _, excerpt := range excerpts {
+		tokens := searchTokens(excerpt)
+		idx.bugTokens[excerpt.Id] = tokens
+		for _, token := range tokens {
+			if _, ok := idx.postings[token]; !ok {
+				idx.postings[token] = make(map[entity.Id]struct{})
+				idx.tokens = append(idx.tokens, token)
+			}
+			idx.postings[token][excerpt.Id] = struct{}{}
+		}
+	}
+
+	sort.Strings(idx.tokens)
+
+	return idx
+}
+
+// searchTokens return the distinct tokens of a bug: its id and the lowercase
+// words of its title
+func searchTokens(excerpt *BugExcerpt) []string {
+	tokens := []string{excerpt.Id.String()}
+	seen := map[string]bool{tokens[0]: true}
+
+	for _, word := range searchWords(excerpt.Title) {
+		if !seen[word] {
+			seen[word] = true
+			tokens = append(tokens, word)
+		}
+	}
+
+	return tokens
+}
+
+// searchWords split a text in lowercase words of letters and digits
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// update index the bug of an excerpt again, as its title may have changed
+func (idx *searchIndex) update(excerpt *BugExcerpt) {
+	if idx == nil {
+		return
+	}
+
+	tokens := searchTokens(excerpt)
+	if equalStrings(tokens, idx.bugTokens[excerpt.Id]) {
+		return
+	}
+
+	idx.remove(excerpt.Id)
+
+	idx.bugTokens[excerpt.Id] = tokens
+	for _, token := range tokens {
+		if _, ok := idx.postings[token]; !ok {
+			idx.postings[token] = make(map[entity.Id]struct{})
+			i := sort.SearchStrings(idx.tokens, token)
+			idx.tokens = append(idx.tokens, "")
+			copy(idx.tokens[i+1:], idx.tokens[i:])
+			idx.tokens[i] = token
+		}
+		idx.postings[token][excerpt.Id] = struct{}{}
+	}
+}
+
+// remove drop a bug from the index
+func (idx *searchIndex) remove(id entity.Id) {
+	if idx == nil {
+		return
+	}
+
+	for _, token := range idx.bugTokens[id] {
+		delete(idx.postings[token], id)
+		if len(idx.postings[token]) > 0 {
+			continue
+		}
+		delete(idx.postings, token)
+		i := sort.SearchStrings(idx.tokens, token)
+		idx.tokens = append(idx.tokens[:i], idx.tokens[i+1:]...)
+	}
+
+	delete(idx.bugTokens, id)
+}
+
+// match return the bugs having a token starting with the prefix, and if a
+// token is the prefix itself
+func (idx *searchIndex) match(prefix string) map[entity.Id]bool {
+	result := make(map[entity.Id]bool)
+
+	for i := sort.SearchStrings(idx.tokens, prefix); i < len(idx.tokens); i++ {
+		token := idx.tokens[i]
+		if !strings.HasPrefix(token, prefix) {
+			break
+		}
+		for id := range idx.postings[token] {
+			result[id] = result[id] || token == prefix
+		}
+	}
+
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
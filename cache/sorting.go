@@ -7,6 +7,9 @@ const (
 	OrderById
 	OrderByCreation
 	OrderByEdit
+	OrderByVotes
+	// by the value of a registered ExcerptField, named in Query.OrderField
+	OrderByField
 )
 
 type OrderDirection int
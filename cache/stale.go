@@ -0,0 +1,267 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// The stale bugs, open for long without activity, are labeled and optionally
+// closed according to the git config:
+//
+//	git-bug.stale.days             the days without activity for a bug to be stale, required
+//	git-bug.stale.close-days       the days a stale bug is closed after, if any
+//	git-bug.stale.label            the label of the stale bugs, "stale" by default
+//	git-bug.stale.exempt-labels    the labels of the bugs never stale, separated by commas or spaces
+//	git-bug.stale.message          the comment posted on the bugs becoming stale
+//	git-bug.stale.close-message    the comment posted on the stale bugs closed
+const (
+	staleDaysKey         = "git-bug.stale.days"
+	staleCloseDaysKey    = "git-bug.stale.close-days"
+	staleLabelKey        = "git-bug.stale.label"
+	staleExemptLabelsKey = "git-bug.stale.exempt-labels"
+	staleMessageKey      = "git-bug.stale.message"
+	staleCloseMessageKey = "git-bug.stale.close-message"
+
+	defaultStaleLabel = "stale"
+)
+
+// ErrStaleNotConfigured is returned when no stale delay is configured
+var ErrStaleNotConfigured = fmt.Errorf("the stale bugs are not configured, set %s", staleDaysKey)
+
+// StaleConfig is how the stale bugs are handled
+type StaleConfig struct {
+	// the delay without activity for a bug to be stale
+	Days int
+	// the delay a stale bug is closed after, 0 to never close them
+	CloseDays    int
+	Label        bug.Label
+	ExemptLabels []bug.Label
+	Message      string
+	CloseMessage string
+}
+
+// StaleAction is the change done on a bug, as "stale" for a bug becoming
+// stale, "close" for a stale bug closed, or "fresh" for a stale bug with a
+// new activity, whose label is removed
+type StaleAction string
+
+const (
+	StaleMarked  StaleAction = "stale"
+	StaleClosed  StaleAction = "close"
+	StaleCleared StaleAction = "fresh"
+)
+
+// StaleChange is a change done, or to be done, on a bug
+type StaleChange struct {
+	Id     entity.Id
+	Title  string
+	Action StaleAction
+}
+
+// StaleConfig return how the stale bugs are handled, or
+// ErrStaleNotConfigured
+func (c *RepoCache) StaleConfig() (StaleConfig, error) {
+	config := c.repo.LocalConfig()
+
+	readDays := func(key string) (int, error) {
+		raw, err := config.ReadString(key)
+		if err != nil {
+			return 0, err
+		}
+		days, err := strconv.Atoi(raw)
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("invalid %s: %q is not a positive number of days", key, raw)
+		}
+		return days, nil
+	}
+
+	var result StaleConfig
+	var err error
+
+	result.Days, err = readDays(staleDaysKey)
+	if err == repository.ErrNoConfigEntry {
+		return StaleConfig{}, ErrStaleNotConfigured
+	}
+	if err != nil {
+		return StaleConfig{}, err
+	}
+
+	result.CloseDays, err = readDays(staleCloseDaysKey)
+	if err != nil && err != repository.ErrNoConfigEntry {
+		return StaleConfig{}, err
+	}
+
+	label, err := config.ReadString(staleLabelKey)
+	if err == repository.ErrNoConfigEntry {
+		label = defaultStaleLabel
+	} else if err != nil {
+		return StaleConfig{}, err
+	}
+	result.Label = bug.Label(label)
+	if err := result.Label.Validate(); err != nil {
+		return StaleConfig{}, fmt.Errorf("invalid %s: %v", staleLabelKey, err)
+	}
+
+	exempt, err := readConfigList(config, staleExemptLabelsKey)
+	if err != nil {
+		return StaleConfig{}, err
+	}
+	for _, l := range exempt {
+		result.ExemptLabels = append(result.ExemptLabels, bug.Label(l))
+	}
+
+	result.Message, err = config.ReadString(staleMessageKey)
+	if err == repository.ErrNoConfigEntry {
+		result.Message = fmt.Sprintf("This bug has been marked as stale, as it had no activity for %d days.", result.Days)
+		if result.CloseDays > 0 {
+			result.Message += fmt.Sprintf(" It will be closed in %d days if nothing happens.", result.CloseDays)
+		}
+	} else if err != nil {
+		return StaleConfig{}, err
+	}
+
+	result.CloseMessage, err = config.ReadString(staleCloseMessageKey)
+	if err == repository.ErrNoConfigEntry {
+		result.CloseMessage = fmt.Sprintf("This bug has been closed, as it had no activity for %d days after being marked as stale.", result.CloseDays)
+	} else if err != nil {
+		return StaleConfig{}, err
+	}
+
+	return result, nil
+}
+
+// RunStale handle the open bugs without activity at the given time: a bug
+// without activity for the configured days is labeled and commented, and a
+// stale bug without activity since is closed after the configured close
+// days. The label of a stale bug with a new activity is removed. With
+// dryRun, the changes are only reported. The user identity is the author of
+// the changes.
+func (c *RepoCache) RunStale(now time.Time, dryRun bool) ([]StaleChange, error) {
+	config, err := c.StaleConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		if _, err := c.GetUserIdentity(); err != nil {
+			return nil, err
+		}
+	}
+
+	staleBefore := now.AddDate(0, 0, -config.Days).Unix()
+
+	c.muBug.RLock()
+	var candidates []entity.Id
+	for id, excerpt := range c.bugExcerpts {
+		if excerpt.Status != bug.OpenStatus {
+			continue
+		}
+		if hasLabel(excerpt.Labels, config.Label.String()) || excerpt.EditUnixTime < staleBefore {
+			candidates = append(candidates, id)
+		}
+	}
+	c.muBug.RUnlock()
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+
+	var changes []StaleChange
+
+	err = c.Transaction(func() error {
+		for _, id := range candidates {
+			b, err := c.ResolveBug(id)
+			if err != nil {
+				return err
+			}
+
+			action, ok := staleAction(b.Snapshot(), config, now)
+			if !ok {
+				continue
+			}
+
+			changes = append(changes, StaleChange{Id: id, Title: b.Snapshot().Title, Action: action})
+
+			if dryRun {
+				continue
+			}
+
+			switch action {
+			case StaleMarked:
+				_, _, err = b.ChangeLabels([]string{config.Label.String()}, nil)
+				if err == nil && config.Message != "" {
+					_, err = b.AddComment(config.Message)
+				}
+			case StaleClosed:
+				if config.CloseMessage != "" {
+					_, err = b.AddComment(config.CloseMessage)
+				}
+				if err == nil {
+					_, err = b.Close()
+				}
+			case StaleCleared:
+				_, _, err = b.ChangeLabels(nil, []string{config.Label.String()})
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := b.Commit(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return changes, err
+}
+
+// staleAction tell what to do with an open bug, if anything
+func staleAction(snap *bug.Snapshot, config StaleConfig, now time.Time) (StaleAction, bool) {
+	for _, l := range config.ExemptLabels {
+		if hasLabel(snap.Labels, l.String()) {
+			return "", false
+		}
+	}
+
+	if !hasLabel(snap.Labels, config.Label.String()) {
+		if snap.LastEditUnix() < now.AddDate(0, 0, -config.Days).Unix() {
+			return StaleMarked, true
+		}
+		return "", false
+	}
+
+	// when the bug has been labeled as stale, and what happened since
+	var markedAt time.Time
+	var activity bool
+	for _, op := range snap.Operations {
+		var added []bug.Label
+		switch op := op.(type) {
+		case *bug.CreateOperation:
+			added = op.Labels
+		case *bug.LabelChangeOperation:
+			added = op.Added
+		}
+		if hasLabel(added, config.Label.String()) {
+			markedAt = op.Time()
+			activity = false
+			continue
+		}
+		if op.Time().After(markedAt) {
+			activity = true
+		}
+	}
+
+	switch {
+	case activity:
+		return StaleCleared, true
+	case config.CloseDays > 0 && !markedAt.After(now.AddDate(0, 0, -config.CloseDays)):
+		return StaleClosed, true
+	default:
+		return "", false
+	}
+}
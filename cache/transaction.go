@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/readmarker"
+)
+
+// transaction hold the excerpt updates and the cache file writes while a
+// transaction is open, to apply them once when it is committed
+type transaction struct {
+	mu sync.Mutex
+	// number of nested transactions open
+	depth int
+	// bugs and identities updated during the transaction
+	bugs       map[entity.Id]struct{}
+	identities map[entity.Id]struct{}
+	// cache files to write when committing
+	bugCacheDirty      bool
+	identityCacheDirty bool
+	// the bug commits, written in git at once when committing
+	batch *bug.BatchWriter
+	// the bugs with commits waiting in batch
+	deferred map[entity.Id]*BugCache
+}
+
+// BeginTransaction open a transaction on the cache: until it is committed,
+// the excerpts of the updated bugs and identities are not compiled again and
+// the cache files are not written. This make the bulk changes much cheaper,
+// at the price of the queries not seeing those changes until the commit. The
+// new bugs and identities are still found right away.
+//
+// The changes of the bugs still need to be committed on their own, but the
+// commits of the existing bugs are only written in git when the transaction
+// is committed, with a single git commit per bug and all the references
+// updated at once.
+//
+// Transactions can be nested, only the outermost commit apply the changes.
+func (c *RepoCache) BeginTransaction() {
+	c.tx.mu.Lock()
+	defer c.tx.mu.Unlock()
+
+	if c.tx.depth == 0 {
+		c.tx.bugs = make(map[entity.Id]struct{})
+		c.tx.identities = make(map[entity.Id]struct{})
+		c.tx.batch = bug.NewBatchWriter(c.repo, 0)
+		c.tx.deferred = make(map[entity.Id]*BugCache)
+	}
+	c.tx.depth++
+}
+
+// CommitTransaction close a transaction opened with BeginTransaction. When
+// closing the outermost one, the pending bug commits are written in git, the
+// pending excerpts compiled and the cache files written.
+func (c *RepoCache) CommitTransaction() error {
+	c.tx.mu.Lock()
+
+	if c.tx.depth == 0 {
+		c.tx.mu.Unlock()
+		panic("no transaction to commit")
+	}
+
+	c.tx.depth--
+	if c.tx.depth > 0 {
+		c.tx.mu.Unlock()
+		return nil
+	}
+
+	bugs, identities := c.tx.bugs, c.tx.identities
+	bugCacheDirty, identityCacheDirty := c.tx.bugCacheDirty, c.tx.identityCacheDirty
+	batch, deferred := c.tx.batch, c.tx.deferred
+	c.tx.bugs, c.tx.identities = nil, nil
+	c.tx.bugCacheDirty, c.tx.identityCacheDirty = false, false
+	c.tx.batch, c.tx.deferred = nil, nil
+	c.tx.mu.Unlock()
+
+	err := c.writeDeferredCommits(batch, deferred)
+	if err != nil {
+		return err
+	}
+
+	if len(bugs) > 0 {
+		c.muBug.Lock()
+		for id := range bugs {
+			// the bug might have been removed in the meantime
+			if b, ok := c.bugs[id]; ok {
+				c.updateBugExcerpt(b.bug.Bug, b.Snapshot())
+			}
+		}
+		c.muBug.Unlock()
+		bugCacheDirty = true
+	}
+
+	if len(identities) > 0 {
+		c.muIdentity.Lock()
+		for id := range identities {
+			if i, ok := c.identities[id]; ok {
+				c.identitiesExcerpts[id] = NewIdentityExcerpt(i.Identity)
+			}
+		}
+		c.muIdentity.Unlock()
+		identityCacheDirty = true
+	}
+
+	if bugCacheDirty {
+		err := c.writeBugCache()
+		if err != nil {
+			return err
+		}
+	}
+
+	if identityCacheDirty {
+		return c.writeIdentityCache()
+	}
+
+	return nil
+}
+
+// writeDeferredCommits write in git the bug commits deferred during a
+// transaction
+func (c *RepoCache) writeDeferredCommits(batch *bug.BatchWriter, deferred map[entity.Id]*BugCache) error {
+	if len(deferred) == 0 {
+		return nil
+	}
+
+	keepRead := make(map[entity.Id]bool, len(deferred))
+
+	c.muBug.RLock()
+	for id, b := range deferred {
+		// a bug removed in the meantime must not be written back
+		if _, ok := c.bugs[id]; !ok {
+			b.bug.DiscardStaging(0)
+		}
+		keepRead[id] = c.isOwnEdit(b.bug.Bug)
+		b.deferred = 0
+	}
+	c.muBug.RUnlock()
+
+	err := batch.Flush()
+	if err != nil {
+		return err
+	}
+
+	return c.updateReadMarkers(func(markers *readmarker.Markers) bool {
+		changed := false
+		for id, b := range deferred {
+			if keepRead[id] && markers.MarkRead(id, b.bug.EditLamportTime()) {
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// flushTransaction commit the open transaction, if any, however deeply it is
+// nested
+func (c *RepoCache) flushTransaction() error {
+	c.tx.mu.Lock()
+	open := c.tx.depth > 0
+	if open {
+		c.tx.depth = 1
+	}
+	c.tx.mu.Unlock()
+
+	if !open {
+		return nil
+	}
+	return c.CommitTransaction()
+}
+
+// Transaction run a function within a transaction, committed whether the
+// function succeed or not
+func (c *RepoCache) Transaction(fn func() error) error {
+	c.BeginTransaction()
+
+	err := fn()
+
+	errCommit := c.CommitTransaction()
+	if err != nil {
+		return err
+	}
+	return errCommit
+}
+
+// deferBugUpdate tell if the excerpt of a bug has to be compiled later, as
+// part of an open transaction, and record it as such
+func (c *RepoCache) deferBugUpdate(id entity.Id) bool {
+	c.tx.mu.Lock()
+	defer c.tx.mu.Unlock()
+
+	if c.tx.depth == 0 {
+		return false
+	}
+	c.tx.bugs[id] = struct{}{}
+	return true
+}
+
+// deferCommit tell if the commit of a bug has to be written later, as part of
+// an open transaction, and record it as such
+func (c *RepoCache) deferCommit(b *BugCache) (bool, error) {
+	c.tx.mu.Lock()
+	defer c.tx.mu.Unlock()
+
+	if c.tx.depth == 0 {
+		return false, nil
+	}
+
+	err := c.tx.batch.Add(b.bug)
+	if err != nil {
+		return false, err
+	}
+	c.tx.deferred[b.Id()] = b
+	b.deferred = b.bug.StagingSize()
+	return true, nil
+}
+
+// deferIdentityUpdate tell if the excerpt of an identity has to be compiled
+// later, as part of an open transaction, and record it as such
+func (c *RepoCache) deferIdentityUpdate(id entity.Id) bool {
+	c.tx.mu.Lock()
+	defer c.tx.mu.Unlock()
+
+	if c.tx.depth == 0 {
+		return false
+	}
+	c.tx.identities[id] = struct{}{}
+	return true
+}
+
+// deferWrite tell if writing a cache file has to wait for the commit of an
+// open transaction, and flag it as such
+func (c *RepoCache) deferWrite(dirty *bool) bool {
+	c.tx.mu.Lock()
+	defer c.tx.mu.Unlock()
+
+	if c.tx.depth == 0 {
+		return false
+	}
+	*dirty = true
+	return true
+}
@@ -2,22 +2,53 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/util/i18n"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 var (
 	addTitle       string
 	addMessage     string
 	addMessageFile string
+	addAnonymous   bool
+	addComponent   string
+	addLabels      []string
+	addFields      []string
+	addForm        string
+	addAnswers     []string
 )
 
 func runAddBug(cmd *cobra.Command, args []string) error {
 	var err error
 
+	if addAnonymous && asIdentity != "" {
+		return fmt.Errorf("an anonymous bug can't be authored with a given identity")
+	}
+
+	if addAnonymous && addComponent != "" {
+		return fmt.Errorf("the component of an anonymous bug can't be set")
+	}
+
+	if addForm != "" && (addMessage != "" || addMessageFile != "") {
+		return fmt.Errorf("the description of a bug reported with a form is made of the answers")
+	}
+
+	if addForm == "" && len(addAnswers) > 0 {
+		return fmt.Errorf("answers can only be given with a form")
+	}
+
+	if addAnonymous && addForm != "" {
+		return fmt.Errorf("an anonymous bug can't be reported with a form")
+	}
+
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
 		return err
@@ -25,18 +56,46 @@ func runAddBug(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
-	if addMessageFile != "" && addMessage == "" {
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	if noVerify {
+		backend.DisableLint()
+	}
+
+	var form *bug.Form
+	var answers map[string]string
+	if addForm != "" {
+		form, err = backend.ResolveForm(addForm)
+		if err != nil {
+			return err
+		}
+
+		answers, err = parseFields(addAnswers)
+		if err != nil {
+			return err
+		}
+
+		addTitle, err = promptForm(form, addTitle, answers)
+		if err != nil {
+			return err
+		}
+	}
+
+	if form == nil && addMessageFile != "" && addMessage == "" {
 		addTitle, addMessage, err = input.BugCreateFileInput(addMessageFile)
 		if err != nil {
 			return err
 		}
 	}
 
-	if addMessageFile == "" && (addMessage == "" || addTitle == "") {
+	if form == nil && addMessageFile == "" && (addMessage == "" || addTitle == "") {
 		addTitle, addMessage, err = input.BugCreateEditorInput(backend, addTitle, addMessage)
 
 		if err == input.ErrEmptyTitle {
-			fmt.Println("Empty title, aborting.")
+			i18n.Println("Empty title, aborting.")
 			return nil
 		}
 		if err != nil {
@@ -44,21 +103,249 @@ func runAddBug(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	b, _, err := backend.NewBug(addTitle, addMessage)
+	fields, err := parseFields(addFields)
+	if err != nil {
+		return err
+	}
+
+	labels, err := promptRequirements(backend, addLabels, fields)
+	if err != nil {
+		return err
+	}
+
+	// validate the component before creating anything
+	if addComponent != "" {
+		_, err = backend.ResolveComponent(addComponent)
+		if err != nil {
+			return err
+		}
+	}
+
+	var b *cache.BugCache
+	if addAnonymous {
+		b, _, err = backend.NewAnonymousBug(addTitle, addMessage, labels, fields)
+	} else if form != nil {
+		b, _, err = backend.NewBugFromForm(addTitle, form, answers, labels, fields)
+	} else {
+		b, _, err = backend.NewBugWithFields(addTitle, addMessage, labels, fields, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	i18n.Printf("%s created\n", b.Id().Human())
+
+	if addAnonymous {
+		return nil
+	}
+
+	component := addComponent
+	if component == "" {
+		suggested, ok, err := backend.SuggestComponent(addTitle + "\n" + b.Snapshot().Comments[0].Message)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		component = suggested
+		i18n.Printf("component %s, from the paths mentioned in the description\n", component)
+	}
+
+	err = setComponent(b, component)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("%s created\n", b.Id().Human())
+	return b.Commit()
+}
+
+// parseFields read the custom fields given as name=value
+func parseFields(raw []string) (map[string]string, error) {
+	fields := make(map[string]string)
+
+	for _, field := range raw {
+		split := strings.SplitN(field, "=", 2)
+		if len(split) != 2 {
+			return nil, fmt.Errorf("invalid field %q, expected <name>=<value>", field)
+		}
+		fields[strings.TrimSpace(split[0])] = split[1]
+	}
 
-	return nil
+	return fields, nil
+}
+
+// promptForm ask for the title and the answers of a form not given, when
+// running in a terminal. The checkboxes are confirmed with y or n, and the
+// dropdowns are answered with the number of an option.
+func promptForm(form *bug.Form, title string, answers map[string]string) (string, error) {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		if title == "" {
+			return "", fmt.Errorf("the title of the bug is required")
+		}
+		return title, nil
+	}
+
+	fmt.Println(form.Title)
+	if form.Description != "" {
+		fmt.Println(form.Description)
+	}
+	fmt.Println()
+
+	var err error
+	if title == "" {
+		title, err = input.Prompt("title", "title", input.Required)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for _, field := range form.Fields {
+		if _, ok := answers[field.Id]; ok {
+			continue
+		}
+
+		question := field.Label
+		if field.Description != "" {
+			question = fmt.Sprintf("%s (%s)", field.Label, field.Description)
+		}
+
+		var validators []input.PromptValidator
+		if field.Required {
+			validators = append(validators, input.Required)
+		}
+
+		switch field.Type {
+		case bug.FormInput:
+			answers[field.Id], err = input.Prompt(question, field.Id, validators...)
+
+		case bug.FormTextarea:
+			for {
+				answers[field.Id], err = input.FormAnswerEditorInput(repo, question)
+				if err != nil || !field.Required || answers[field.Id] != "" {
+					break
+				}
+				i18n.Printf("%s is required\n", field.Label)
+			}
+
+		case bug.FormDropdown:
+			var index int
+			index, err = input.PromptChoice(question, field.Options)
+			if err == nil {
+				answers[field.Id] = field.Options[index]
+			}
+
+		case bug.FormCheckbox:
+			var key rune
+			key, err = input.PromptKey(question+" [y/n]", "yn")
+			if err == nil {
+				answers[field.Id] = fmt.Sprint(key == 'y')
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return title, nil
+}
+
+// promptRequirements add the labels required by the repository to the given
+// ones, and ask for the values of the required label prefixes and custom
+// fields not given. Nothing is asked outside of a terminal, the creation fails
+// instead.
+func promptRequirements(backend *cache.RepoCache, labels []string, fields map[string]string) ([]string, error) {
+	requirements, err := backend.Requirements()
+	if err != nil {
+		return nil, err
+	}
+
+	given := make([]bug.Label, len(labels))
+	for i, label := range labels {
+		given[i] = bug.Label(strings.TrimSpace(label))
+	}
+
+	interactive := terminal.IsTerminal(int(os.Stdin.Fd()))
+
+	for _, required := range requirements.MissingLabels(given) {
+		if !strings.HasSuffix(required, ":") {
+			i18n.Printf("label %s, required by the repository\n", required)
+			labels = append(labels, required)
+			continue
+		}
+
+		if !interactive {
+			continue
+		}
+
+		// offer the values already in use
+		var existing []string
+		for _, usage := range backend.LabelsUsage() {
+			if bug.MatchRequiredLabel(required, usage.Label) {
+				existing = append(existing, strings.TrimPrefix(usage.Label.String(), required))
+			}
+		}
+
+		prompt := fmt.Sprintf("label %s", required)
+		if len(existing) > 0 {
+			prompt = fmt.Sprintf("label %s (%s)", required, strings.Join(existing, ", "))
+		}
+
+		value, err := input.Prompt(prompt, "label", input.Required)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(value, required) {
+			value = required + value
+		}
+		labels = append(labels, value)
+	}
+
+	if !interactive {
+		return labels, nil
+	}
+
+	for _, name := range requirements.MissingFields(fields) {
+		value, err := input.Prompt(name, name, input.Required)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = value
+	}
+
+	return labels, nil
 }
 
 var addCmd = &cobra.Command{
-	Use:     "add",
-	Short:   "Create a new bug.",
-	PreRunE: loadRepoEnsureUser,
-	RunE:    runAddBug,
+	Use:   "add",
+	Short: "Create a new bug.",
+	Long: `Create a new bug.
+
+The labels and the custom fields a new bug must have are configured with git. A label ending with ":" is a prefix, any label starting with it is accepted. The missing ones are asked for when running in a terminal:
+
+  git config git-bug.required.labels "type: triage"
+  git config git-bug.required.fields "version"
+
+The forms of a repository, structured bug reports as the issue forms of the forges, are JSON files committed in .git-bug/forms. With --form, the questions not answered with --answer are asked for, and the answers make the description of the bug:
+
+  {
+    "title": "Crash report",
+    "labels": ["type:crash"],
+    "fields": [
+      {"id": "version", "type": "input", "label": "Version", "required": true},
+      {"id": "os", "type": "dropdown", "label": "Operating system", "options": ["linux", "macos", "windows"]},
+      {"id": "steps", "type": "textarea", "label": "Steps to reproduce"},
+      {"id": "searched", "type": "checkbox", "label": "I searched for a similar bug", "required": true}
+    ]
+  }`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		// an anonymous report doesn't need the user identity
+		if addAnonymous {
+			return loadRepo(cmd, args)
+		}
+		return loadRepoEnsureUser(cmd, args)
+	},
+	RunE: runAddBug,
 }
 
 func init() {
@@ -75,4 +362,25 @@ func init() {
 	addCmd.Flags().StringVarP(&addMessageFile, "file", "F", "",
 		"Take the message from the given file. Use - to read the message from the standard input",
 	)
+	addCmd.Flags().StringVarP(&addComponent, "component", "c", "",
+		"Set the component of the bug. By default, the component is suggested from the paths mentioned in the description",
+	)
+	addCmd.Flags().StringSliceVarP(&addLabels, "label", "l", nil,
+		"Add labels to the bug, as \"type:bug\"",
+	)
+	addCmd.Flags().StringArrayVar(&addFields, "field", nil,
+		"Set a custom field of the bug, as \"version=1.2\". Can be repeated",
+	)
+	addCmd.Flags().StringVar(&addForm, "form", "",
+		"Report the bug with a form of the repository, answering its questions",
+	)
+	addCmd.Flags().StringArrayVar(&addAnswers, "answer", nil,
+		"Answer a question of the form, as \"version=1.2\". Can be repeated",
+	)
+	addCmd.Flags().BoolVar(&addAnonymous, "anonymous", false,
+		"Report the bug with a new throwaway identity instead of the user identity",
+	)
+
+	addAsFlag(addCmd)
+	addNoVerifyFlag(addCmd)
 }
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runAssignee(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	snap := b.Snapshot()
+
+	for _, assignee := range snap.Assignees {
+		fmt.Printf("%s %s\n",
+			colors.Cyan(assignee.Id().Human()),
+			assignee.DisplayName(),
+		)
+	}
+
+	return nil
+}
+
+var assigneeCmd = &cobra.Command{
+	Use:     "assignee [<id>]",
+	Short:   "Display or change the identities assigned to a bug.",
+	PreRunE: loadRepo,
+	RunE:    runAssignee,
+}
+
+func init() {
+	RootCmd.AddCommand(assigneeCmd)
+
+	assigneeCmd.Flags().SortFlags = false
+}
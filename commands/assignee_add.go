@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runAssigneeAdd(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	assignees, err := resolveIdentities(backend, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.ChangeAssignees(assignees, nil)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+// resolveIdentities resolve a list of identity prefixes
+func resolveIdentities(backend *cache.RepoCache, prefixes []string) ([]*cache.IdentityCache, error) {
+	if len(prefixes) == 0 {
+		return nil, errors.New("an identity is required")
+	}
+
+	result := make([]*cache.IdentityCache, len(prefixes))
+	for i, prefix := range prefixes {
+		id, err := backend.ResolveIdentityPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = id
+	}
+
+	return result, nil
+}
+
+var assigneeAddCmd = &cobra.Command{
+	Use:     "add [<id>] <user-id>[...]",
+	Short:   "Assign identities to a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runAssigneeAdd,
+}
+
+func init() {
+	assigneeCmd.AddCommand(assigneeAddCmd)
+
+	assigneeAddCmd.Flags().SortFlags = false
+
+	addAsFlag(assigneeAddCmd)
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runAssigneeRm(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	assignees, err := resolveIdentities(backend, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.ChangeAssignees(nil, assignees)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var assigneeRmCmd = &cobra.Command{
+	Use:     "rm [<id>] <user-id>[...]",
+	Short:   "Unassign identities from a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runAssigneeRm,
+}
+
+func init() {
+	assigneeCmd.AddCommand(assigneeRmCmd)
+
+	assigneeRmCmd.Flags().SortFlags = false
+
+	addAsFlag(assigneeRmCmd)
+}
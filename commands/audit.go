@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	auditFormat string
+)
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	commits, err := b.Audit()
+	if err != nil {
+		return err
+	}
+
+	switch auditFormat {
+	case "text":
+		printAuditText(commits)
+		return nil
+	case "json":
+		return printAuditJson(b, commits)
+	default:
+		return fmt.Errorf("unknown format %s", auditFormat)
+	}
+}
+
+func printAuditText(commits []bug.AuditCommit) {
+	for i, commit := range commits {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("commit %s\n", colors.Yellow(commit.Commit))
+		if commit.Parent != "" {
+			fmt.Printf("parent    %s\n", commit.Parent)
+		}
+		fmt.Printf("tree      %s\n", commit.Tree)
+		fmt.Printf("blob      %s\n", commit.Blob)
+		fmt.Printf("edit time %d\n", commit.EditTime)
+		fmt.Printf("signature %s\n", describeSignature(commit.Signature))
+		fmt.Printf("ops sig   %s\n", describeOpsSignature(commit.OpsSignature))
+
+		for _, op := range commit.Operations {
+			fmt.Printf("  %s %s %s %s\n",
+				colors.Cyan(op.Id().Human()),
+				op.Time().Format("2006-01-02 15:04"),
+				colors.Magenta(op.GetAuthor().DisplayName()),
+				describeOperation(op),
+			)
+		}
+	}
+}
+
+func describeSignature(signature repository.CommitSignature) string {
+	if signature.Status == repository.SignatureNone {
+		return string(signature.Status)
+	}
+	return fmt.Sprintf("%s from %s (key %s)", signature.Status, signature.Signer, signature.Key)
+}
+
+func describeOpsSignature(signature bug.OpsSignature) string {
+	if signature.Status == repository.SignatureNone {
+		return string(signature.Status)
+	}
+	return fmt.Sprintf("%s (key %s)", signature.Status, signature.Key)
+}
+
+func printAuditJson(b *cache.BugCache, commits []bug.AuditCommit) error {
+	type jsonAuthor struct {
+		Id    string `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email,omitempty"`
+		Login string `json:"login,omitempty"`
+	}
+
+	type jsonOperation struct {
+		Id        string        `json:"id"`
+		Author    jsonAuthor    `json:"author"`
+		Operation bug.Operation `json:"operation"`
+	}
+
+	type jsonCommit struct {
+		bug.AuditCommit
+		Operations []jsonOperation `json:"operations"`
+	}
+
+	result := struct {
+		Bug     string       `json:"bug"`
+		Commits []jsonCommit `json:"commits"`
+	}{
+		Bug:     b.Id().String(),
+		Commits: make([]jsonCommit, 0, len(commits)),
+	}
+
+	for _, commit := range commits {
+		c := jsonCommit{
+			AuditCommit: commit,
+			Operations:  make([]jsonOperation, 0, len(commit.Operations)),
+		}
+
+		for _, op := range commit.Operations {
+			author := op.GetAuthor()
+			c.Operations = append(c.Operations, jsonOperation{
+				Id: op.Id().String(),
+				Author: jsonAuthor{
+					Id:    author.Id().String(),
+					Name:  author.Name(),
+					Email: author.Email(),
+					Login: author.Login(),
+				},
+				Operation: op,
+			})
+		}
+
+		result.Commits = append(result.Commits, c)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [<id>]",
+	Short: "Export the chain of commits of a bug, with their hashes and signatures.",
+	Long: `Export the chain of commits of a bug, with their hashes and signatures, as an evidence of how the bug has been handled.
+
+Each commit hash cover its parent and its tree, the tree cover the blob holding the operations, and the id of each operation is the hash of its data: altering any past operation would change all the hashes after it. The GPG signature of each commit is given as checked by git, with the signer and the key.
+
+The operations can also be signed by the key of a signer configured with "git bug user signer". Unlike the commit signature, this signature survive the rebase of the commits when merging. It is good when made by a key of the author of every operation of the commit, at the time of the commit.
+
+The "json" format is meant to be archived, and hold the full data of the operations.`,
+	Example: `git bug audit 5c9bf2f --format json > audit.json`,
+	PreRunE: loadRepo,
+	RunE:    runAudit,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().SortFlags = false
+
+	auditCmd.Flags().StringVarP(&auditFormat, "format", "f", "text",
+		"Format of the output. Valid values are [text,json]")
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const (
+	// if true, the bugs are pulled before the git-bug commands, at most once
+	// per TTL, and along a regular git pull
+	autoSyncPullConfigKey = "git-bug.auto-sync.pull"
+	// if true, the bugs are pushed after the git-bug commands changing them,
+	// and along a regular git push
+	autoSyncPushConfigKey = "git-bug.auto-sync.push"
+	// the minimum duration between two automatic pulls
+	autoSyncTTLConfigKey = "git-bug.auto-sync.ttl"
+	// when the last automatic pull happened
+	autoSyncLastPullConfigKey = "git-bug.auto-sync.last-pull"
+
+	defaultAutoSyncTTL = 10 * time.Minute
+
+	// set in the environment of the automatic push, so that the pre-push
+	// hook triggered by it doesn't push again
+	autoSyncEnv = "GIT_BUG_AUTO_SYNC"
+)
+
+// set by the commands changing the bugs, to push them afterward
+var autoSyncPushNeeded bool
+
+// readAutoSyncConfig read a boolean auto sync setting of the repository,
+// false if not set
+func readAutoSyncConfig(key string) bool {
+	enabled, err := repo.LocalConfig().ReadBool(key)
+	return err == nil && enabled
+}
+
+// skipAutoSync tell if the command must not trigger an automatic sync,
+// because it is already syncing or is run by a git hook
+func skipAutoSync(cmd *cobra.Command) bool {
+	if os.Getenv(autoSyncEnv) != "" {
+		return true
+	}
+
+	switch cmd.CommandPath() {
+	case rootCommandName + " pull", rootCommandName + " push", rootCommandName + " sync",
+		rootCommandName + " receive-pack-hook":
+		return true
+	}
+
+	return strings.HasPrefix(cmd.CommandPath(), rootCommandName+" hook ")
+}
+
+// autoSyncPullIfStale pull the bugs from the default remote if the automatic
+// pull is enabled and the last one is older than the TTL
+func autoSyncPullIfStale() error {
+	if !readAutoSyncConfig(autoSyncPullConfigKey) {
+		return nil
+	}
+
+	ttl := defaultAutoSyncTTL
+	raw, err := repo.LocalConfig().ReadString(autoSyncTTLConfigKey)
+	if err == nil {
+		ttl, err = time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", autoSyncTTLConfigKey, err)
+		}
+	}
+
+	last, err := repo.LocalConfig().ReadTimestamp(autoSyncLastPullConfigKey)
+	if err == nil && time.Since(last) < ttl {
+		return nil
+	}
+	if err != nil && err != repository.ErrNoConfigEntry {
+		return err
+	}
+
+	return autoSyncPull("")
+}
+
+// autoSyncPull pull and merge the bugs of a remote, the default one if empty
+func autoSyncPull(remote string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	if remote == "" {
+		remote, err = backend.DefaultRemote()
+		if err != nil {
+			return err
+		}
+	}
+
+	// the pull is not tried again before the TTL, even if it fails because
+	// the remote is unreachable
+	err = repo.LocalConfig().StoreTimestamp(autoSyncLastPullConfigKey, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = backend.Fetch(remote)
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for result := range backend.MergeAll(remote) {
+		if result.Err != nil {
+			return result.Err
+		}
+		if result.Status == entity.MergeStatusNew || result.Status == entity.MergeStatusUpdated {
+			updated++
+		}
+	}
+
+	if updated > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Pulled %d updated bugs and identities from %s\n", updated, remote)
+	}
+
+	return nil
+}
+
+// autoSyncPush push the bugs to a remote, the default one if empty
+func autoSyncPush(remote string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	if remote == "" {
+		remote, err = backend.DefaultRemote()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Setenv(autoSyncEnv, "1")
+	if err != nil {
+		return err
+	}
+
+	_, err = backend.Push(remote)
+	return err
+}
+
+// autoSyncAfter push the bugs once a command changing them is done, if the
+// automatic push is enabled. A failure doesn't fail the command.
+func autoSyncAfter(cmd *cobra.Command, args []string) {
+	if !autoSyncPushNeeded || repo == nil || skipAutoSync(cmd) {
+		return
+	}
+
+	if !readAutoSyncConfig(autoSyncPushConfigKey) {
+		return
+	}
+
+	if err := autoSyncPush(""); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "automatic push failed: %v\n", err)
+	}
+}
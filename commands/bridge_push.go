@@ -88,8 +88,16 @@ func runBridgePush(cmd *cobra.Command, args []string) error {
 }
 
 var bridgePushCmd = &cobra.Command{
-	Use:     "push [<name>]",
-	Short:   "Push updates.",
+	Use:   "push [<name>]",
+	Short: "Push updates.",
+	Long: `Push updates.
+
+When the bridge has an export query, only the matching bugs are exported, for
+example to mirror the public bugs only:
+
+    git config git-bug.bridge.<name>.export-query label:public
+
+The bugs already exported which don't match the query anymore are not updated.`,
 	PreRunE: loadRepoEnsureUser,
 	RunE:    runBridgePush,
 	Args:    cobra.MaximumNArgs(1),
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/skratchdot/open-golang/open"
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bridge"
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/numbering"
+)
+
+var (
+	browseWebUI   bool
+	browseComment string
+	browsePrint   bool
+)
+
+// how long to wait for a web UI started on demand to answer
+const browseStartTimeout = 10 * time.Second
+
+func runBrowse(cmd *cobra.Command, args []string) error {
+	// the cache is not used, as it is locked by the web UI while it runs
+	b, err := browseResolveBug(args[0])
+	if err != nil {
+		return err
+	}
+
+	snap := b.Compile()
+
+	// a comment is only linked to in the web UI
+	if browseComment != "" {
+		comment, err := snap.ResolveCommentPrefix(browseComment)
+		if err != nil {
+			return err
+		}
+
+		url, err := browseWebUIUrl(b.Id())
+		if err != nil {
+			return err
+		}
+
+		return browseOpen(url + "#" + comment.Id().String())
+	}
+
+	url, ok := bridge.BugUrl(&snap)
+	if !ok || browseWebUI {
+		url, err = browseWebUIUrl(b.Id())
+		if err != nil {
+			return err
+		}
+	}
+
+	return browseOpen(url)
+}
+
+// browseOpen print the url, and open it in the browser unless only printing
+// it is asked
+func browseOpen(url string) error {
+	fmt.Println(url)
+
+	if browsePrint {
+		return nil
+	}
+
+	return open.Run(url)
+}
+
+// browseResolveBug read the bug referred to by an id prefix or a sequential
+// number
+func browseResolveBug(ref string) (*bug.Bug, error) {
+	if _, ok := numbering.Parse(ref); !ok {
+		return bug.FindLocalBug(repo, ref)
+	}
+
+	numbers, err := numbering.Read(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	number, _ := numbering.Parse(ref)
+	id, ok := numbers.Id(number)
+	if !ok {
+		return nil, bug.ErrBugNotExist
+	}
+
+	return bug.ReadLocalBug(repo, id)
+}
+
+// browseWebUIUrl return the url of a bug in the web UI, which is started in
+// the background if it doesn't run already
+func browseWebUIUrl(id entity.Id) (string, error) {
+	address, ok := runningWebUI()
+	if !ok {
+		var err error
+		address, err = startWebUI()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if address.Repo != "" {
+		return fmt.Sprintf("%s/bug/%s/%s", address.Url, address.Repo, id), nil
+	}
+	return fmt.Sprintf("%s/bug/%s", address.Url, id), nil
+}
+
+// runningWebUI return the address of the web UI serving the repository, if it
+// runs and answers
+func runningWebUI() (webUIAddress, bool) {
+	var address webUIAddress
+
+	data, err := ioutil.ReadFile(webUIAddressPath(repo))
+	if err != nil {
+		return address, false
+	}
+
+	err = json.Unmarshal(data, &address)
+	if err != nil || address.Url == "" {
+		return address, false
+	}
+
+	client := http.Client{Timeout: time.Second}
+	resp, err := client.Get(address.Url)
+	if err != nil {
+		// a left-over of a web UI which didn't stop properly
+		return address, false
+	}
+	_ = resp.Body.Close()
+
+	return address, true
+}
+
+// startWebUI start the web UI in the background, and wait for it to answer
+func startWebUI() (webUIAddress, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return webUIAddress{}, err
+	}
+
+	port, err := freeport.GetFreePort()
+	if err != nil {
+		return webUIAddress{}, err
+	}
+
+	webUI := exec.Command(executable, "webui", "--no-open", "--port", strconv.Itoa(port))
+	err = webUI.Start()
+	if err != nil {
+		return webUIAddress{}, err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- webUI.Wait()
+	}()
+
+	timeout := time.After(browseStartTimeout)
+
+	for {
+		select {
+		case <-exited:
+			return webUIAddress{}, fmt.Errorf("the web UI failed to start, run \"git bug webui\" to see why")
+		case <-timeout:
+			_ = webUI.Process.Kill()
+			return webUIAddress{}, fmt.Errorf("the web UI didn't start in %s", browseStartTimeout)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if address, ok := runningWebUI(); ok {
+			fmt.Fprintf(os.Stderr, "Web UI started in the background, as process %d, stop it to use the other commands\n", webUI.Process.Pid)
+			return address, nil
+		}
+	}
+}
+
+var browseCmd = &cobra.Command{
+	Use:   "browse <id>",
+	Short: "Open a bug in the browser.",
+	Long: `Open a bug in the browser.
+
+A bug imported from or exported to a bug-tracker with a bridge is opened on the bug-tracker. Otherwise, or with --webui, the bug is opened in the web UI, which is started in the background if it doesn't run already for this repository. A web UI started this way keeps running until stopped, and as the web UI does, lock the repository for the other commands meanwhile.
+
+With --comment, the web UI is opened at the given comment of the bug, as listed by "git bug comment".`,
+	Example: `git bug browse 2f15
+git bug browse --webui 2f15
+git bug browse --comment 9fc1 2f15
+git bug browse '#12'`,
+	PreRunE: loadRepo,
+	RunE:    runBrowse,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(browseCmd)
+
+	browseCmd.Flags().SortFlags = false
+
+	browseCmd.Flags().BoolVarP(&browseWebUI, "webui", "w", false,
+		"Open the bug in the web UI, even if it comes from a bug-tracker")
+	browseCmd.Flags().StringVarP(&browseComment, "comment", "c", "",
+		"Open the web UI at the comment with this id prefix")
+	browseCmd.Flags().BoolVarP(&browsePrint, "print", "p", false,
+		"Only print the url, without opening the browser")
+}
@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Exchange the changes of a bug as a file.",
+	Long: `Exchange the changes of a bug as a file.
+
+A bug bundle holds the new operations of a bug, with the identities of their authors, in a self-contained JSON file. A contributor without push access to the shared remote can create one with "git bug bundle create" and send it by email or attach it to a pull request, then a maintainer can review it and apply it with "git bug bundle apply". The bug is the same as if it had been pushed, signatures included.`,
+}
+
+func init() {
+	RootCmd.AddCommand(bundleCmd)
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	bundleApplyReview bool
+)
+
+func runBundleApply(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+
+	if len(args) == 0 || args[0] == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	var bundle bug.Bundle
+	err = json.Unmarshal(data, &bundle)
+	if err != nil {
+		return fmt.Errorf("invalid bug bundle: %v", err)
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	var results []entity.MergeResult
+	if bundleApplyReview {
+		results, err = backend.HoldBugBundle(&bundle)
+	} else {
+		results, err = backend.ApplyBugBundle(&bundle)
+	}
+
+	invalid := 0
+	for _, result := range results {
+		if result.Status == entity.MergeStatusInvalid {
+			invalid++
+		}
+
+		name := result.Id.Human()
+		if i, ok := result.Entity.(*identity.Identity); ok {
+			name = fmt.Sprintf("%s %s", name, i.DisplayName())
+		}
+		fmt.Printf("%s: %s\n", name, result)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("the bundle could not be applied entirely")
+	}
+
+	return nil
+}
+
+var bundleApplyCmd = &cobra.Command{
+	Use:   "apply [<file>]",
+	Short: "Apply a bug bundle.",
+	Long: `Apply a bug bundle created with "git bug bundle create".
+
+Without file, or with "-", the bundle is read from the standard input. The bundle is checked and merged as the bugs pulled from a remote: the new operations are added to the bug, or the bug is created if unknown. The identities of the authors are imported along.
+
+With --review, the bug is held for review as the bugs pulled from an untrusted remote, to be accepted with "git bug review accept".
+
+A bundle building on operations missing here is refused: pull the bug first.`,
+	Example: `git bug bundle apply fix-title.json
+git bug show 3ae8`,
+	PreRunE: loadRepo,
+	RunE:    runBundleApply,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	bundleCmd.AddCommand(bundleApplyCmd)
+	bundleApplyCmd.Flags().SortFlags = false
+
+	bundleApplyCmd.Flags().BoolVar(&bundleApplyReview, "review", false,
+		"Hold the changes for review instead of merging them")
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	bundleCreateRemote string
+	bundleCreateAll    bool
+	bundleCreateOutput string
+)
+
+func runBundleCreate(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	remote := ""
+	if !bundleCreateAll {
+		remote = bundleCreateRemote
+		if remote == "" {
+			remote, err = backend.DefaultRemote()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	bundle, err := backend.ExportBugBundle(b.Id(), remote)
+	if err != nil {
+		return err
+	}
+
+	if len(bundle.Commits) == 0 {
+		return fmt.Errorf("the bug has no new operation since the last push to %s", remote)
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if bundleCreateOutput == "" || bundleCreateOutput == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	err = ioutil.WriteFile(bundleCreateOutput, append(data, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "%d commits of the bug %s bundled in %s\n",
+		len(bundle.Commits), b.Id().Human(), bundleCreateOutput)
+
+	return nil
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create [<id>]",
+	Short: "Bundle the new operations of a bug in a file.",
+	Long: `Bundle the new operations of a bug in a file, to be applied in another repository with "git bug bundle apply".
+
+The operations already pushed to the remote, as known from the last pull or push, are left out so that the bundle only holds your changes. The bug being new, or with --all, the whole bug is bundled.`,
+	Example: `git bug bundle create 3ae8 -o fix-title.json
+git bug bundle create 3ae8 --all > bug.json`,
+	PreRunE: loadRepo,
+	RunE:    runBundleCreate,
+}
+
+func init() {
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCreateCmd.Flags().SortFlags = false
+
+	bundleCreateCmd.Flags().StringVarP(&bundleCreateRemote, "remote", "r", "",
+		"The remote to compare the bug with (default to the default remote)")
+	bundleCreateCmd.Flags().BoolVarP(&bundleCreateAll, "all", "a", false,
+		"Bundle the whole bug")
+	bundleCreateCmd.Flags().StringVarP(&bundleCreateOutput, "output", "o", "",
+		"Write the bundle to a file instead of the standard output")
+}
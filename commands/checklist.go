@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runChecklist(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range b.Snapshot().Checklist {
+		fmt.Println(formatChecklistItem(i, item))
+	}
+
+	return nil
+}
+
+func formatChecklistItem(index int, item bug.ChecklistItem) string {
+	box := "[ ]"
+	if item.Checked {
+		box = colors.Green("[x]")
+	}
+	return fmt.Sprintf("%s %d. %s", box, index+1, item.Text)
+}
+
+// resolveChecklistItem find an item of the checklist of a bug from its
+// number, as displayed by "git bug checklist"
+func resolveChecklistItem(snap *bug.Snapshot, number string) (bug.ChecklistItem, error) {
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 || n > len(snap.Checklist) {
+		return bug.ChecklistItem{}, fmt.Errorf("invalid checklist item %q: the checklist has %d items", number, len(snap.Checklist))
+	}
+	return snap.Checklist[n-1], nil
+}
+
+var checklistCmd = &cobra.Command{
+	Use:   "checklist [<id>]",
+	Short: "Display or change the checklist of a bug.",
+	Long: `Display or change the checklist of a bug.
+
+The checklist of a bug is a list of tasks to do, each of them checked once done. The items are numbered in the order they have been added, and "git bug ls" and "git bug show" display how many of them are checked.`,
+	PreRunE: loadRepo,
+	RunE:    runChecklist,
+}
+
+func init() {
+	RootCmd.AddCommand(checklistCmd)
+
+	checklistCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runChecklistAdd(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("the text of the item is required")
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	// the text is the last argument, to not mistake it for a bug id
+	text := args[len(args)-1]
+
+	b, args, err := _select.ResolveBug(backend, args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		return errors.New("the text of the item should be a single argument")
+	}
+
+	_, err = b.AddChecklistItem(text)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var checklistAddCmd = &cobra.Command{
+	Use:     "add [<id>] <text>",
+	Short:   "Add an item to the checklist of a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runChecklistAdd,
+}
+
+func init() {
+	checklistCmd.AddCommand(checklistAddCmd)
+
+	checklistAddCmd.Flags().SortFlags = false
+
+	addAsFlag(checklistAddCmd)
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runChecklistCheck(cmd *cobra.Command, args []string) error {
+	return checkChecklistItem(args, true)
+}
+
+func runChecklistUncheck(cmd *cobra.Command, args []string) error {
+	return checkChecklistItem(args, false)
+}
+
+func checkChecklistItem(args []string, checked bool) error {
+	if len(args) == 0 {
+		return errors.New("the number of the item is required")
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	// the number is the last argument, to not mistake it for a bug id
+	number := args[len(args)-1]
+
+	b, args, err := _select.ResolveBug(backend, args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		return errors.New("a single item is required")
+	}
+
+	snap := b.Snapshot()
+
+	item, err := resolveChecklistItem(snap, number)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.CheckItem(item.Id(), checked)
+	if err != nil {
+		return err
+	}
+
+	checkedCount, total := b.Snapshot().ChecklistProgress()
+	fmt.Printf("%d/%d done\n", checkedCount, total)
+
+	return b.Commit()
+}
+
+var checklistCheckCmd = &cobra.Command{
+	Use:     "check [<id>] <number>",
+	Short:   "Check an item of the checklist of a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runChecklistCheck,
+}
+
+var checklistUncheckCmd = &cobra.Command{
+	Use:     "uncheck [<id>] <number>",
+	Short:   "Uncheck an item of the checklist of a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runChecklistUncheck,
+}
+
+func init() {
+	checklistCmd.AddCommand(checklistCheckCmd)
+	checklistCmd.AddCommand(checklistUncheckCmd)
+
+	checklistCheckCmd.Flags().SortFlags = false
+	checklistUncheckCmd.Flags().SortFlags = false
+
+	addAsFlag(checklistCheckCmd)
+	addAsFlag(checklistUncheckCmd)
+}
@@ -0,0 +1,14 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Integrate with continuous integration.",
+}
+
+func init() {
+	RootCmd.AddCommand(ciCmd)
+}
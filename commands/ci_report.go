@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/importer"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	ciReportBuild string
+)
+
+func runCiReport(cmd *cobra.Command, args []string) error {
+	var results []importer.TestResult
+
+	if len(args) == 0 {
+		res, err := importer.ParseJUnit(os.Stdin)
+		if err != nil {
+			return err
+		}
+		results = res
+	}
+
+	for _, path := range args {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		res, err := importer.ParseJUnit(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		results = append(results, res...)
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	stats, err := importer.ReportTests(backend, results, ciReportBuild)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d test(s): %d bug(s) opened, %d reopened, %d closed, %d unchanged\n",
+		len(results), stats.Opened, stats.Reopened, stats.Closed, stats.Unchanged)
+
+	return nil
+}
+
+var ciReportCmd = &cobra.Command{
+	Use:   "report [<file>...]",
+	Short: "Open bugs for the failing tests of a JUnit report.",
+	Long: `Open bugs for the failing tests of JUnit XML reports, read from the files or from the standard input.
+
+Each failing test open a bug labeled "failing-test", holding the name of the test as metadata to find it again on the next reports. A test still failing doesn't open another bug; a test failing again reopen its bug if it has been closed as fixed, with a comment. The bug of a test passing again is closed as fixed.`,
+	Example: `git bug ci report --build "$CI_JOB_URL" build/test-results/*.xml`,
+	PreRunE: loadRepo,
+	RunE:    runCiReport,
+}
+
+func init() {
+	ciCmd.AddCommand(ciReportCmd)
+
+	ciReportCmd.Flags().SortFlags = false
+
+	ciReportCmd.Flags().StringVarP(&ciReportBuild, "build", "b", "",
+		"The build the report comes from, as the URL of the CI job, mentioned in the bugs")
+}
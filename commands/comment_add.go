@@ -1,11 +1,11 @@
 package commands
 
 import (
-	"fmt"
-
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/commands/select"
 	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/util/i18n"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +13,7 @@ import (
 var (
 	commentAddMessageFile string
 	commentAddMessage     string
+	commentAddReplyTo     string
 )
 
 func runCommentAdd(cmd *cobra.Command, args []string) error {
@@ -23,11 +24,28 @@ func runCommentAdd(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	if noVerify {
+		backend.DisableLint()
+	}
+
 	b, args, err := _select.ResolveBug(backend, args)
 	if err != nil {
 		return err
 	}
 
+	var replyTo *bug.Comment
+	if commentAddReplyTo != "" {
+		replyTo, err = b.Snapshot().ResolveCommentPrefix(commentAddReplyTo)
+		if err != nil {
+			return err
+		}
+	}
+
 	if commentAddMessageFile != "" && commentAddMessage == "" {
 		commentAddMessage, err = input.BugCommentFileInput(commentAddMessageFile)
 		if err != nil {
@@ -38,7 +56,7 @@ func runCommentAdd(cmd *cobra.Command, args []string) error {
 	if commentAddMessageFile == "" && commentAddMessage == "" {
 		commentAddMessage, err = input.BugCommentEditorInput(backend, "")
 		if err == input.ErrEmptyMessage {
-			fmt.Println("Empty message, aborting.")
+			i18n.Println("Empty message, aborting.")
 			return nil
 		}
 		if err != nil {
@@ -46,7 +64,11 @@ func runCommentAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	_, err = b.AddComment(commentAddMessage)
+	if replyTo != nil {
+		_, err = b.AddCommentReply(replyTo.Id(), commentAddMessage, nil)
+	} else {
+		_, err = b.AddComment(commentAddMessage)
+	}
 	if err != nil {
 		return err
 	}
@@ -73,4 +95,11 @@ func init() {
 	commentAddCmd.Flags().StringVarP(&commentAddMessage, "message", "m", "",
 		"Provide the new message from the command line",
 	)
+
+	commentAddCmd.Flags().StringVarP(&commentAddReplyTo, "reply-to", "r", "",
+		"Reply to the comment with the given id, as shown by \"git bug show\"",
+	)
+
+	addAsFlag(commentAddCmd)
+	addNoVerifyFlag(commentAddCmd)
 }
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runComponent(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	snap := b.Snapshot()
+
+	if snap.Component != "" {
+		fmt.Println(snap.Component)
+	}
+
+	return nil
+}
+
+var componentCmd = &cobra.Command{
+	Use:   "component [<id>]",
+	Short: "Display or change the component of a bug.",
+	Long: `Display or change the component of a bug.
+
+The valid components of a repository, the paths belonging to them and the identities to assign by default are configured with git:
+
+  git config git-bug.components "frontend backend"
+  git config git-bug.component.frontend.paths "webui/ termui/"
+  git config git-bug.component.frontend.assignees "<identity id>"`,
+	PreRunE: loadRepo,
+	RunE:    runComponent,
+}
+
+func init() {
+	RootCmd.AddCommand(componentCmd)
+
+	componentCmd.Flags().SortFlags = false
+}
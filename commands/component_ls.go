@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runComponentLs(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	components, err := backend.Components()
+	if err != nil {
+		return err
+	}
+
+	for _, component := range components {
+		fmt.Printf("%s\t%s\n",
+			colors.Cyan(component.Name),
+			strings.Join(component.Paths, " "),
+		)
+	}
+
+	return nil
+}
+
+var componentLsCmd = &cobra.Command{
+	Use:     "ls",
+	Short:   "List the valid components and their paths.",
+	PreRunE: loadRepo,
+	RunE:    runComponentLs,
+}
+
+func init() {
+	componentCmd.AddCommand(componentLsCmd)
+
+	componentLsCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runComponentRm(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.SetComponent("")
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var componentRmCmd = &cobra.Command{
+	Use:     "rm [<id>]",
+	Short:   "Remove the component of a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runComponentRm,
+}
+
+func init() {
+	componentCmd.AddCommand(componentRmCmd)
+
+	componentRmCmd.Flags().SortFlags = false
+
+	addAsFlag(componentRmCmd)
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runComponentSet(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errors.New("a single component is required")
+	}
+
+	err = setComponent(b, args[0])
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+// setComponent set the component of a bug and report the identities
+// assigned by default
+func setComponent(b *cache.BugCache, component string) error {
+	before := len(b.Snapshot().Assignees)
+
+	_, err := b.SetComponent(component)
+	if err != nil {
+		return err
+	}
+
+	for _, assignee := range b.Snapshot().Assignees[before:] {
+		fmt.Printf("assigned to %s\n", assignee.DisplayName())
+	}
+
+	return nil
+}
+
+var componentSetCmd = &cobra.Command{
+	Use:     "set [<id>] <component>",
+	Short:   "Set the component of a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runComponentSet,
+}
+
+func init() {
+	componentCmd.AddCommand(componentSetCmd)
+
+	componentSetCmd.Flags().SortFlags = false
+
+	addAsFlag(componentSetCmd)
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+var (
+	daemonInterval time.Duration
+)
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	for {
+		if err := daemonTick(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+		}
+
+		time.Sleep(daemonInterval)
+	}
+}
+
+// daemonTick run the periodic tasks once. The cache is only held during the
+// tick, to not prevent the other git-bug commands from running meanwhile.
+func daemonTick() error {
+	// pull first, to not create again a bug created by another clone
+	if err := autoSyncPullIfStale(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "automatic pull failed: %v\n", err)
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+
+	created, err := backend.RunSchedules(time.Now())
+	for _, b := range created {
+		fmt.Printf("%s %s created: %s\n",
+			time.Now().Format("2006-01-02 15:04"), b.Id().Human(), b.Snapshot().Title)
+	}
+
+	errClose := backend.Close()
+	if err != nil {
+		return err
+	}
+	if errClose != nil {
+		return errClose
+	}
+
+	if len(created) > 0 && readAutoSyncConfig(autoSyncPushConfigKey) {
+		if err := autoSyncPush(""); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "automatic push failed: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the periodic tasks of the repository, until interrupted.",
+	Long: `Run the periodic tasks of the repository, until interrupted.
+
+The bugs of the schedules are created when they are due, as "git bug schedule run" would. With the automatic sync enabled, the bugs are pulled before and pushed after.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runDaemon,
+}
+
+func init() {
+	RootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().SortFlags = false
+
+	daemonCmd.Flags().DurationVarP(&daemonInterval, "interval", "i", time.Minute,
+		"How often the periodic tasks are run")
+}
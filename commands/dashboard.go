@@ -0,0 +1,283 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	text "github.com/MichaelMure/go-term-text"
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	dashboardSince string
+	dashboardLimit int
+	dashboardJson  bool
+)
+
+type dashboardRepo struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Open         int    `json:"open"`
+	Closed       int    `json:"closed"`
+	AssignedToMe int    `json:"assigned_to_me"`
+}
+
+type dashboardBug struct {
+	Repo        string    `json:"repo"`
+	Id          string    `json:"id"`
+	QualifiedId string    `json:"qualified_id"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	EditedAt    time.Time `json:"edited_at"`
+}
+
+type dashboard struct {
+	Open            int             `json:"open"`
+	AssignedToMe    int             `json:"assigned_to_me"`
+	Repositories    []dashboardRepo `json:"repositories"`
+	RecentlyUpdated []dashboardBug  `json:"recently_updated"`
+	Assigned        []dashboardBug  `json:"assigned"`
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	since, err := parseSince(dashboardSince)
+	if err != nil {
+		return err
+	}
+
+	multi := cache.NewMultiRepoCache()
+	defer multi.Close()
+	interrupt.RegisterCleaner(multi.Close)
+
+	paths, err := scanRepositories(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		name := repoDirName(path)
+
+		r, err := repository.NewGitRepo(path, bug.Witnesser)
+		if err != nil {
+			return err
+		}
+
+		// a repository already used by another git-bug doesn't prevent the
+		// summary of the others
+		err = multi.RegisterRepository(name, r)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		}
+	}
+
+	result := dashboard{
+		Repositories:    []dashboardRepo{},
+		RecentlyUpdated: []dashboardBug{},
+		Assigned:        []dashboardBug{},
+	}
+	me := make(map[*cache.RepoCache]entity.Id)
+
+	for _, r := range multi.AllRepos() {
+		summary := dashboardRepo{
+			Name: r.Name(),
+			Path: r.GetPath(),
+		}
+
+		user, err := r.GetUserIdentity()
+		if err == nil {
+			me[r] = user.Id()
+		}
+
+		for _, id := range r.AllBugsIds() {
+			excerpt, err := r.ResolveBugExcerpt(id)
+			if err != nil {
+				return err
+			}
+
+			if excerpt.Status == bug.ClosedStatus {
+				summary.Closed++
+				continue
+			}
+
+			summary.Open++
+			if isAssignedTo(excerpt, me[r]) {
+				summary.AssignedToMe++
+			}
+		}
+
+		result.Open += summary.Open
+		result.AssignedToMe += summary.AssignedToMe
+		result.Repositories = append(result.Repositories, summary)
+	}
+
+	// most recently edited first
+	query, err := cache.ParseQuery("sort:edit")
+	if err != nil {
+		return err
+	}
+
+	for _, repoBug := range multi.QueryBugs(query) {
+		excerpt, err := repoBug.Repo.ResolveBugExcerpt(repoBug.Id)
+		if err != nil {
+			return err
+		}
+
+		if len(result.RecentlyUpdated) < dashboardLimit && excerpt.EditUnixTime >= since.Unix() {
+			result.RecentlyUpdated = append(result.RecentlyUpdated, newDashboardBug(repoBug.Repo, excerpt))
+		}
+
+		if len(result.Assigned) < dashboardLimit && excerpt.Status == bug.OpenStatus &&
+			isAssignedTo(excerpt, me[repoBug.Repo]) {
+			result.Assigned = append(result.Assigned, newDashboardBug(repoBug.Repo, excerpt))
+		}
+	}
+
+	if dashboardJson {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(result.Repositories) == 0 {
+		fmt.Printf("No repository with bugs in %s\n", dir)
+		return nil
+	}
+
+	fmt.Printf("%d open bugs, %d assigned to me\n", result.Open, result.AssignedToMe)
+
+	fmt.Println("\nRepositories:")
+	for _, summary := range result.Repositories {
+		fmt.Printf("  %s\t%s open\t%d closed\t%s assigned to me\n",
+			text.LeftPadMaxLine(summary.Name, 20, 0),
+			colors.Yellow(summary.Open),
+			summary.Closed,
+			colors.Yellow(summary.AssignedToMe),
+		)
+	}
+
+	if len(result.RecentlyUpdated) > 0 {
+		fmt.Println("\nRecently updated:")
+		dashboardTextBugs(result.RecentlyUpdated)
+	}
+
+	if len(result.Assigned) > 0 {
+		fmt.Println("\nAssigned to me:")
+		dashboardTextBugs(result.Assigned)
+	}
+
+	return nil
+}
+
+// scanRepositories return the git repositories directly in a directory, or
+// the directory itself if it's a repository, that hold some bugs
+func scanRepositories(dir string) ([]string, error) {
+	if isRepoWithBugs(dir) {
+		return []string{dir}, nil
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() && isRepoWithBugs(path) {
+			result = append(result, path)
+		}
+	}
+
+	return result, nil
+}
+
+// isRepoWithBugs tell if a directory is the root of a git repository holding
+// some bugs. The other repositories are not loaded, to not create the git-bug
+// data where it's not used.
+func isRepoWithBugs(dir string) bool {
+	refs, err := repository.ListRefsAt(dir, "refs/bugs/")
+	return err == nil && len(refs) > 0
+}
+
+func isAssignedTo(excerpt *cache.BugExcerpt, id entity.Id) bool {
+	if id == "" {
+		return false
+	}
+
+	for _, assignee := range excerpt.Assignees {
+		if assignee == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newDashboardBug(repo *cache.RepoCache, excerpt *cache.BugExcerpt) dashboardBug {
+	return dashboardBug{
+		Repo:        repo.Name(),
+		Id:          excerpt.Id.String(),
+		QualifiedId: cache.QualifiedBugId(repo, excerpt.Id),
+		Title:       excerpt.Title,
+		Status:      excerpt.Status.String(),
+		EditedAt:    time.Unix(excerpt.EditUnixTime, 0),
+	}
+}
+
+func dashboardTextBugs(bugs []dashboardBug) {
+	for _, b := range bugs {
+		fmt.Printf("  %s %s\t%s\t%s\n",
+			colors.Cyan(text.LeftPadMaxLine(b.QualifiedId, 28, 0)),
+			colors.Yellow(b.Status),
+			text.LeftPadMaxLine(strings.TrimSpace(b.Title), 50, 0),
+			b.EditedAt.Format("2006-01-02 15:04"),
+		)
+	}
+}
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard [<directory>]",
+	Short: "Summarize the bugs of all the repositories of a directory.",
+	Long: `Summarize the bugs of all the repositories of a directory.
+
+The git repositories directly in the directory, or the current one, are scanned. For each repository holding some bugs, the number of open and closed bugs and of open bugs assigned to the user identity of that repository are shown, followed by the bugs recently updated and the bugs assigned to the user, across all the repositories.
+
+With "--json", the summary is given as a JSON object, for example for a status bar.`,
+	Example: `git bug dashboard ~/projects
+git bug dashboard --json | jq .assigned_to_me`,
+	RunE: runDashboard,
+	Args: cobra.MaximumNArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(dashboardCmd)
+
+	dashboardCmd.Flags().SortFlags = false
+
+	dashboardCmd.Flags().StringVarP(&dashboardSince, "since", "s", "168h",
+		"Show the bugs updated after the given date as recently updated (ex: \"48h\" or \"june 2 2019\")")
+	dashboardCmd.Flags().IntVarP(&dashboardLimit, "limit", "n", 10,
+		"Maximum number of bugs in each list")
+	dashboardCmd.Flags().BoolVar(&dashboardJson, "json", false,
+		"Output the summary as JSON")
+}
@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Tools for the development of git-bug.",
+	Long: `Tools for the development of git-bug.
+
+These commands help to test git-bug itself, and are not needed to track bugs.`,
+}
+
+func init() {
+	RootCmd.AddCommand(devCmd)
+}
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/misc/random_bugs"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+var (
+	devBenchBugs int
+	devBenchSeed int64
+	devBenchKeep bool
+)
+
+// the queries run by the benchmark of the query filtering
+var devBenchQueries = []string{
+	"status:open",
+	"status:closed sort:edit-asc",
+	"label:bug no:assignee",
+	"title:the author:a",
+}
+
+// benchStep is a measured part of the benchmark, with its budget for each bug
+// of the repository. run return the time taken by the measured part only.
+type benchStep struct {
+	name   string
+	budget time.Duration
+	run    func(repo repository.ClockedRepo) (time.Duration, error)
+}
+
+// the reads of git data are bound by the git processes, the budgets are large
+var devBenchSteps = []benchStep{
+	{"read bugs", 100 * time.Millisecond, benchReadBugs},
+	{"build cache", 100 * time.Millisecond, benchBuildCache},
+	{"load cache", 500 * time.Microsecond, benchLoadCache},
+	{"query bugs", 10 * time.Microsecond, benchQueryBugs},
+	{"replay bugs", 200 * time.Microsecond, benchReplayBugs},
+}
+
+func runDevBench(cmd *cobra.Command, args []string) error {
+	if devBenchBugs <= 0 {
+		return fmt.Errorf("the number of bugs should be positive")
+	}
+
+	dir, err := ioutil.TempDir("", "git-bug-bench")
+	if err != nil {
+		return err
+	}
+	if devBenchKeep {
+		fmt.Printf("repository: %s\n", dir)
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	benchRepo, err := repository.InitGitRepo(dir)
+	if err != nil {
+		return err
+	}
+
+	config := benchRepo.LocalConfig()
+	if err := config.StoreString("user.name", "bench"); err != nil {
+		return err
+	}
+	if err := config.StoreString("user.email", "bench@example.com"); err != nil {
+		return err
+	}
+
+	fmt.Printf("generating %d bugs...\n", devBenchBugs)
+
+	options := random_bugs.DefaultOptions()
+	options.BugNumber = devBenchBugs
+	random_bugs.CommitRandomBugsWithSeed(benchRepo, options, devBenchSeed)
+
+	fmt.Println()
+
+	overBudget := 0
+
+	for _, step := range devBenchSteps {
+		total, err := step.run(benchRepo)
+		if err != nil {
+			return fmt.Errorf("%s: %v", step.name, err)
+		}
+		perBug := total / time.Duration(devBenchBugs)
+
+		status := colors.Green("ok")
+		if perBug > step.budget {
+			status = colors.Red("over budget")
+			overBudget++
+		}
+
+		fmt.Printf("%-12s %10s total %10s/bug  budget %10s/bug  %s\n",
+			step.name,
+			total.Round(time.Microsecond),
+			perBug.Round(time.Microsecond),
+			step.budget,
+			status,
+		)
+	}
+
+	if overBudget > 0 {
+		return fmt.Errorf("%d step(s) over the performance budget", overBudget)
+	}
+
+	return nil
+}
+
+func benchReadBugs(repo repository.ClockedRepo) (time.Duration, error) {
+	start := time.Now()
+	_, err := readBenchBugs(repo)
+	return time.Since(start), err
+}
+
+func benchBuildCache(repo repository.ClockedRepo) (time.Duration, error) {
+	err := cache.RemoveCacheFiles(repo)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	c, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+
+	return elapsed, c.Close()
+}
+
+func benchLoadCache(repo repository.ClockedRepo) (time.Duration, error) {
+	start := time.Now()
+	c, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+
+	return elapsed, c.Close()
+}
+
+func benchQueryBugs(repo repository.ClockedRepo) (time.Duration, error) {
+	c, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+
+	queries := make([]*cache.Query, len(devBenchQueries))
+	for i, raw := range devBenchQueries {
+		queries[i], err = cache.ParseQuery(raw)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	start := time.Now()
+	for _, query := range queries {
+		c.QueryBugs(query)
+	}
+	return time.Since(start), nil
+}
+
+func benchReplayBugs(repo repository.ClockedRepo) (time.Duration, error) {
+	bugs, err := readBenchBugs(repo)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	for _, b := range bugs {
+		b.Compile()
+	}
+	return time.Since(start), nil
+}
+
+func readBenchBugs(repo repository.ClockedRepo) ([]*bug.Bug, error) {
+	var bugs []*bug.Bug
+	for b := range bug.ReadAllLocalBugs(repo) {
+		if b.Err != nil {
+			return nil, b.Err
+		}
+		bugs = append(bugs, b.Bug)
+	}
+	return bugs, nil
+}
+
+var devBenchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure the performance of git-bug on a synthetic repository.",
+	Long: `Measure the performance of git-bug on a synthetic repository.
+
+A temporary repository is filled with random bugs, always the same for a given seed, then the reading of the bugs, the building and loading of the cache, the query filtering and the replay of the operations of the bugs are measured.
+
+Each step has a budget of time for each bug of the repository, and the command fail if one is exceeded, to catch the performance regressions. The Go benchmarks of the tests package measure the same steps: "go test -bench . ./tests".`,
+	Example: `git bug dev bench --bugs 500`,
+	RunE:    runDevBench,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	devCmd.AddCommand(devBenchCmd)
+
+	devBenchCmd.Flags().SortFlags = false
+
+	devBenchCmd.Flags().IntVarP(&devBenchBugs, "bugs", "b", 200,
+		"The number of bugs of the synthetic repository")
+	devBenchCmd.Flags().Int64VarP(&devBenchSeed, "seed", "s", 42,
+		"The seed of the random generation of the bugs")
+	devBenchCmd.Flags().BoolVarP(&devBenchKeep, "keep", "k", false,
+		"Keep the synthetic repository instead of removing it")
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func runDevDumpFixtures(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	fixtures := make(map[string][]byte)
+	for _, generate := range []func() (map[string][]byte, error){bug.Fixtures, identity.Fixtures} {
+		generated, err := generate()
+		if err != nil {
+			return err
+		}
+		for name, data := range generated {
+			fixtures[name] = data
+		}
+	}
+
+	names := make([]string, 0, len(fixtures))
+	for name := range fixtures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		err := ioutil.WriteFile(path, fixtures[name], 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+
+	return nil
+}
+
+var devDumpFixturesCmd = &cobra.Command{
+	Use:   "dump-fixtures [<dir>]",
+	Short: "Write the fixtures of the data formats.",
+	Long: `Write the fixtures of the data formats in a directory, the current one by default.
+
+The fixtures are the data stored in git by this version of git-bug, holding every operation type: an OperationPack and a version of an identity. They are the same on every run. The golden files of the tests are these fixtures, kept for every format version to make sure the data written by an older version is still read.
+
+To update the golden files after an intended change of the format, run "go test ./bug ./identity -run Fixtures -update".`,
+	Example: `git bug dev dump-fixtures /tmp/fixtures`,
+	RunE:    runDevDumpFixtures,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	devCmd.AddCommand(devDumpFixturesCmd)
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/misc/random_bugs"
+)
+
+var (
+	devPopulateBugs       int
+	devPopulateComments   int
+	devPopulateIdentities int
+	devPopulateSeed       int64
+)
+
+func runDevPopulate(cmd *cobra.Command, args []string) error {
+	if devPopulateBugs <= 0 {
+		return fmt.Errorf("the number of bugs should be positive")
+	}
+	if devPopulateIdentities <= 0 {
+		return fmt.Errorf("the number of identities should be positive")
+	}
+	if devPopulateComments < 0 {
+		return fmt.Errorf("the number of comments can't be negative")
+	}
+
+	options := random_bugs.DefaultPopulateOptions()
+	options.BugNumber = devPopulateBugs
+	options.MaxComments = devPopulateComments
+	options.IdentityNumber = devPopulateIdentities
+	if cmd.Flags().Changed("seed") {
+		options.Seed = devPopulateSeed
+	}
+
+	start := time.Now()
+	options.Progress = func(done int) {
+		if done%100 == 0 || done == devPopulateBugs {
+			_, _ = fmt.Fprintf(os.Stderr, "\r%d/%d bugs", done, devPopulateBugs)
+		}
+	}
+
+	err := random_bugs.Populate(repo, options)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	// the bugs have been written without the cache, it has to be built again
+	err = cache.RemoveCacheFiles(repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d bugs and %d identities generated in %s\n",
+		devPopulateBugs, devPopulateIdentities, time.Since(start).Round(time.Second))
+
+	return nil
+}
+
+var devPopulateCmd = &cobra.Command{
+	Use:   "populate",
+	Short: "Fill the repository with synthetic bugs and identities.",
+	Long: `Fill the repository with synthetic bugs and identities.
+
+Realistic random identities and bugs are committed in the repository, with histories of comments, labels, component, assignees, votes, checklists and status changes spread over the last two years. The content is the same for a given seed. Most bugs have a few comments, some up to the given maximum.
+
+This is meant to load test the cache, the web UI and the terminal UI at scale, on a repository dedicated to it. The cache is built again on the next use of git-bug.`,
+	Example: `git bug dev populate --bugs 10000 --comments 50`,
+	PreRunE: loadRepo,
+	RunE:    runDevPopulate,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	devCmd.AddCommand(devPopulateCmd)
+
+	devPopulateCmd.Flags().SortFlags = false
+
+	devPopulateCmd.Flags().IntVarP(&devPopulateBugs, "bugs", "b", 1000,
+		"The number of bugs to generate")
+	devPopulateCmd.Flags().IntVarP(&devPopulateComments, "comments", "c", 50,
+		"The maximum number of comments of a bug")
+	devPopulateCmd.Flags().IntVarP(&devPopulateIdentities, "identities", "i", 50,
+		"The number of identities to generate")
+	devPopulateCmd.Flags().Int64VarP(&devPopulateSeed, "seed", "s", 0,
+		"The seed of the random generation, random if not set")
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+var (
+	diffSince string
+)
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffSince == "" {
+		return fmt.Errorf("the --since flag is required")
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	filter, err := diffSinceFilter(b, diffSince)
+	if err != nil {
+		return err
+	}
+
+	diff := bug.DiffSnapshots(b.SnapshotAt(filter), b.Snapshot())
+
+	if diff.IsEmpty() {
+		fmt.Println("no change")
+		return nil
+	}
+
+	if diff.TitleChanged() {
+		fmt.Printf("title: %s -> %s\n", diff.OldTitle, colors.Bold(diff.NewTitle))
+	}
+
+	if diff.StatusChanged() {
+		status := diff.NewStatus.String()
+		if diff.NewResolution != bug.NoResolution {
+			status = fmt.Sprintf("%s (%s)", status, diff.NewResolution)
+		}
+		fmt.Printf("status: %s -> %s\n", diff.OldStatus, colors.Yellow(status))
+	}
+
+	if len(diff.AddedLabels) > 0 || len(diff.RemovedLabels) > 0 {
+		var changes []string
+		for _, label := range diff.AddedLabels {
+			changes = append(changes, colors.Green("+"+label.String()))
+		}
+		for _, label := range diff.RemovedLabels {
+			changes = append(changes, colors.Red("-"+label.String()))
+		}
+		fmt.Printf("labels: %s\n", strings.Join(changes, " "))
+	}
+
+	if diff.ComponentChanged() {
+		fmt.Printf("component: %s -> %s\n", diff.OldComponent, diff.NewComponent)
+	}
+
+	if len(diff.AddedAssignees) > 0 || len(diff.RemovedAssignees) > 0 {
+		var changes []string
+		for _, assignee := range diff.AddedAssignees {
+			changes = append(changes, colors.Green("+"+assignee.DisplayName()))
+		}
+		for _, assignee := range diff.RemovedAssignees {
+			changes = append(changes, colors.Red("-"+assignee.DisplayName()))
+		}
+		fmt.Printf("assignees: %s\n", strings.Join(changes, " "))
+	}
+
+	for _, comment := range diff.NewComments {
+		printDiffComment("new comment", comment)
+	}
+
+	for _, comment := range diff.EditedComments {
+		printDiffComment("edited comment", comment)
+	}
+
+	return nil
+}
+
+func printDiffComment(kind string, comment bug.Comment) {
+	fmt.Printf("\n%s #%s by %s %s:\n\n",
+		kind,
+		comment.Id().Human(),
+		colors.Magenta(comment.Author.DisplayName()),
+		comment.FormatTimeRel(),
+	)
+
+	message := strings.ReplaceAll(comment.Message, "\n", "\n    ")
+	fmt.Printf("    %s\n", message)
+}
+
+// diffSinceFilter give a filter accepting the operations that were already
+// there at the point given by since: an edit lamport time, the hash of an
+// operation or a date
+func diffSinceFilter(b *cache.BugCache, since string) (func(op bug.TimedOperation) bool, error) {
+	editTime, err := strconv.ParseUint(since, 10, 64)
+	if err == nil {
+		return func(op bug.TimedOperation) bool {
+			return op.EditTime <= lamport.Time(editTime)
+		}, nil
+	}
+
+	ops := b.CommittedOperations()
+	position := -1
+	for i, op := range ops {
+		if op.Id().HasPrefix(since) {
+			if position >= 0 {
+				return nil, fmt.Errorf("multiple operations match the prefix %s", since)
+			}
+			position = i
+		}
+	}
+	if position >= 0 {
+		before := make(map[entity.Id]bool, position+1)
+		for _, op := range ops[:position+1] {
+			before[op.Id()] = true
+		}
+		return func(op bug.TimedOperation) bool {
+			return before[op.Id()]
+		}, nil
+	}
+
+	date, err := parseSince(since)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an edit lamport time, an operation or a date", since)
+	}
+
+	return func(op bug.TimedOperation) bool {
+		return !op.Time().After(date)
+	}, nil
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [<id>]",
+	Short: "Show what changed on a bug since a point in time.",
+	Long: `Show what changed on a bug since a point in time: the title, status, labels, component and assignees changes, and the new or edited comments.
+
+The point in time is given with "--since", either as an edit lamport time (as displayed by "git bug events"), the hash of an operation of the bug, or a date.`,
+	Example: `git bug diff --since 42
+git bug diff 2f15 --since "2 days ago"
+git bug diff --since 27e9a3c`,
+	PreRunE: loadRepo,
+	RunE:    runDiff,
+}
+
+func init() {
+	RootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().SortFlags = false
+
+	diffCmd.Flags().StringVarP(&diffSince, "since", "s", "",
+		"The point in time to compare with: an edit lamport time (ex: \"42\"), an operation hash, or a date (ex: \"200h\" or \"june 2 2019\")")
+}
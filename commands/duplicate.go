@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runDuplicate(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errors.New("a single canonical bug is required")
+	}
+
+	canonical, err := backend.ResolveBugPrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = b.MarkDuplicate(canonical)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s marked as duplicate of %s\n", b.Id().Human(), canonical.Id().Human())
+
+	return b.Commit()
+}
+
+var duplicateCmd = &cobra.Command{
+	Use:   "duplicate [<id>] <canonical>",
+	Short: "Mark a bug as a duplicate of another bug.",
+	Long: `Mark a bug as a duplicate of another, canonical, bug and close it.
+
+The bugs marked as duplicate are hidden from the queries unless asked for with "duplicate:yes" or "duplicate:any". Reopening the bug remove the relation.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runDuplicate,
+}
+
+func init() {
+	RootCmd.AddCommand(duplicateCmd)
+
+	duplicateCmd.Flags().SortFlags = false
+
+	addAsFlag(duplicateCmd)
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+var (
+	eventsSince string
+	eventsJson  bool
+)
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	var sinceEditTime lamport.Time
+	var since time.Time
+
+	if eventsSince != "" {
+		editTime, err := strconv.ParseUint(eventsSince, 10, 64)
+		if err == nil {
+			sinceEditTime = lamport.Time(editTime)
+		} else {
+			since, err = parseSince(eventsSince)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	events, err := backend.Events(sinceEditTime, since)
+	if err != nil {
+		return err
+	}
+
+	stopPager, err := startPager()
+	if err != nil {
+		return err
+	}
+	defer stopPager()
+
+	if eventsJson {
+		return printEventsJson(events)
+	}
+
+	for _, event := range events {
+		fmt.Printf("%d %s %s %s %s %s\n",
+			event.EditTime,
+			colors.Cyan(event.BugId.Human()),
+			colors.Cyan(event.Operation.Id().Human()),
+			event.Operation.Time().Format("2006-01-02 15:04"),
+			colors.Magenta(event.Operation.GetAuthor().DisplayName()),
+			describeOperation(event.Operation),
+		)
+	}
+
+	return nil
+}
+
+func printEventsJson(events []cache.Event) error {
+	type jsonEvent struct {
+		Bug       string        `json:"bug"`
+		EditTime  lamport.Time  `json:"edit_time"`
+		Id        string        `json:"id"`
+		Operation bug.Operation `json:"operation"`
+	}
+
+	for _, event := range events {
+		data, err := json.Marshal(jsonEvent{
+			Bug:       event.BugId.String(),
+			EditTime:  event.EditTime,
+			Id:        event.Operation.Id().String(),
+			Operation: event.Operation,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	}
+
+	return nil
+}
+
+// describeOperation give a one line summary of an operation
+func describeOperation(op bug.Operation) string {
+	switch op := op.(type) {
+	case *bug.CreateOperation:
+		return fmt.Sprintf("created the bug \"%s\"", op.Title)
+	case *bug.SetTitleOperation:
+		return fmt.Sprintf("changed the title to \"%s\"", op.Title)
+	case *bug.AddCommentOperation:
+		if op.ReplyTo != "" {
+			return fmt.Sprintf("replied to the comment %s", op.ReplyTo.Human())
+		}
+		return "commented"
+	case *bug.EditCommentOperation:
+		return fmt.Sprintf("edited the comment %s", op.Target.Human())
+	case *bug.SetStatusOperation:
+		if op.Resolution != bug.NoResolution {
+			return fmt.Sprintf("%s the bug as %s", op.Status.Action(), op.Resolution)
+		}
+		return fmt.Sprintf("%s the bug", op.Status.Action())
+	case *bug.LabelChangeOperation:
+		var changes []string
+		for _, label := range op.Added {
+			changes = append(changes, "+"+label.String())
+		}
+		for _, label := range op.Removed {
+			changes = append(changes, "-"+label.String())
+		}
+		return fmt.Sprintf("changed the labels %s", strings.Join(changes, " "))
+	case *bug.SetComponentOperation:
+		if op.Component == "" {
+			return "removed the component"
+		}
+		return fmt.Sprintf("set the component to %s", op.Component)
+	case *bug.AssigneeChangeOperation:
+		return "changed the assignees"
+	case *bug.MarkDuplicateOperation:
+		return fmt.Sprintf("marked the bug as duplicate of %s", op.Target.Human())
+	case *bug.SetParentOperation:
+		return fmt.Sprintf("made the bug a sub-task of %s", op.Parent.Human())
+	case *bug.RemoveParentOperation:
+		return fmt.Sprintf("removed the bug from the sub-tasks of %s", op.Was.Human())
+	case *bug.AddChecklistItemOperation:
+		return fmt.Sprintf("added %q to the checklist", op.Text)
+	case *bug.CheckItemOperation:
+		if op.Checked {
+			return fmt.Sprintf("checked the checklist item %s", op.Target.Human())
+		}
+		return fmt.Sprintf("unchecked the checklist item %s", op.Target.Human())
+	case *bug.VoteOperation:
+		if op.Retract {
+			return "retracted their vote"
+		}
+		return "voted for the bug"
+	case *bug.RequestReviewOperation:
+		names := make([]string, len(op.Reviewers))
+		for i, reviewer := range op.Reviewers {
+			names[i] = reviewer.DisplayName()
+		}
+		return fmt.Sprintf("requested the review of %s", strings.Join(names, ", "))
+	case *bug.CustomOperation:
+		return fmt.Sprintf("applied the operation %s", op.Tag)
+	case *bug.UnknownOperation:
+		return "did an operation unknown to this version of git-bug"
+	case *bug.SetMetadataOperation:
+		return fmt.Sprintf("set metadata on %s", op.Target.Human())
+	case *bug.NoOpOperation:
+		return "no-op"
+	default:
+		return "unknown operation"
+	}
+}
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Display the stream of operations of all the bugs.",
+	Long: `Display the stream of operations of all the bugs, ordered by edit lamport time.
+
+The edit lamport time is the first column of the output. Giving back the last one seen with "--since" only display the newer operations, which allow an external tool to consume the changes incrementally.
+
+Note that the operations pulled from a remote keep the lamport time given where they have been created, that can be older than the operations already seen locally.`,
+	PreRunE: loadRepo,
+	RunE:    runEvents,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().SortFlags = false
+
+	eventsCmd.Flags().StringVarP(&eventsSince, "since", "s", "",
+		"Only display the operations after an edit lamport time (ex: \"42\") or a date (ex: \"200h\" or \"june 2 2019\")")
+	eventsCmd.Flags().BoolVar(&eventsJson, "json", false,
+		"Output the operations as JSON, one per line")
+}
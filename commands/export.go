@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/export"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	exportFormat string
+)
+
+func runExport(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	switch exportFormat {
+	case "sqlite":
+		stats, err := export.SQLite(backend, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d bug(s) exported, %d updated, %d removed\n", stats.Total, stats.Updated, stats.Removed)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown format %s", exportFormat)
+	}
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <path>",
+	Short: "Export the bugs and identities in another format.",
+	Long: `Export the bugs and identities in another format.
+
+The "sqlite" format mirror the repository in a SQLite database, with the tables bugs, operations, comments, labels and identities, to run arbitrary SQL queries. Exporting again to the same database only write again the bugs edited since.`,
+	Example: `git bug export --format sqlite bugs.db
+sqlite3 bugs.db "SELECT name, COUNT(*) FROM labels GROUP BY name"`,
+	PreRunE: loadRepo,
+	RunE:    runExport,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().SortFlags = false
+
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "sqlite",
+		"Format of the export. Valid values are [sqlite]")
+}
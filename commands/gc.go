@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	gcOffline bool
+)
+
+func runGC(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	report, err := backend.GC(!gcOffline)
+	if err != nil {
+		return err
+	}
+
+	for remote, err := range report.RemoteErrors {
+		fmt.Printf("couldn't check remote %s: %v\n", remote, err)
+	}
+
+	for remote, ids := range report.PrunedRemoteBugs {
+		for _, id := range ids {
+			fmt.Printf("%s: removed stale bug reference from %s\n", id.Human(), remote)
+		}
+	}
+
+	for remote, ids := range report.PrunedRemoteIdentities {
+		for _, id := range ids {
+			fmt.Printf("%s: removed stale identity reference from %s\n", id.Human(), remote)
+		}
+	}
+
+	for _, id := range report.PrunedBugExcerpts {
+		fmt.Printf("%s: removed missing bug from the cache\n", id.Human())
+	}
+
+	for _, id := range report.PrunedIdentityExcerpts {
+		fmt.Printf("%s: removed missing identity from the cache\n", id.Human())
+	}
+
+	reclaimed := report.ReclaimedCacheSpace()
+	if reclaimed < 0 {
+		reclaimed = 0
+	}
+
+	fmt.Printf("cache: %s -> %s (%s reclaimed)\n",
+		humanize.Bytes(uint64(report.CacheSizeBefore)),
+		humanize.Bytes(uint64(report.CacheSizeAfter)),
+		humanize.Bytes(uint64(reclaimed)),
+	)
+
+	return nil
+}
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Clean up stale references and cached data.",
+	Long: `Clean up stale references and cached data.
+
+The remote-tracking references of the bugs and identities that don't exist anymore on their remote are removed, the cached data of missing bugs and identities is pruned and the cache files are compacted.
+
+The git objects that become unreachable are left for git to prune, for example with "git gc".`,
+	PreRunE: loadRepo,
+	RunE:    runGC,
+}
+
+func init() {
+	RootCmd.AddCommand(gcCmd)
+
+	gcCmd.Flags().SortFlags = false
+
+	gcCmd.Flags().BoolVar(&gcOffline, "offline", false,
+		"Don't contact the remotes to find the stale references")
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// hookMarker identify the git hooks installed by git-bug
+const hookMarker = "# installed by git-bug"
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage the git hooks of git-bug.",
+	Long: `Manage the git hooks of git-bug.
+
+The pre-push hook validate the bugs and identities about to be pushed, so broken or malicious data is caught before it reaches the shared remote. The pre-receive hook does the same on the server side.
+
+The bugs can also be kept in sync automatically, with these settings of the git config of the repository:
+- "git-bug.auto-sync.pull": if true, the bugs are pulled before running a git-bug command, at most once per "git-bug.auto-sync.ttl" (10m by default), and along a regular "git pull" with the post-merge hook.
+- "git-bug.auto-sync.push": if true, the bugs are pushed after a git-bug command changing them, and along a regular "git push" with the pre-push hook.`,
+}
+
+func init() {
+	RootCmd.AddCommand(hookCmd)
+}
+
+// hooksDir return the directory where git look for the hooks of the
+// repository
+func hooksDir(repo repository.ClockedRepo) (string, error) {
+	dir, err := repo.LocalConfig().ReadString("core.hooksPath")
+	if err == repository.ErrNoConfigEntry {
+		return filepath.Join(repo.GetPath(), "hooks"), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(dir) {
+		// relative to the root of the working tree, where the hooks are run
+		dir = filepath.Join(filepath.Dir(repo.GetPath()), dir)
+	}
+
+	return dir, nil
+}
+
+// isGitBugHook tell if the hook file has been installed by git-bug. A missing
+// file is reported as not being one, with no error.
+func isGitBugHook(path string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(data), hookMarker), nil
+}
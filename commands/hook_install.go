@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+const prePushHook = `#!/bin/sh
+` + hookMarker + `
+# validate the bugs and identities about to be pushed
+exec git bug hook pre-push "$@"
+`
+
+const preReceiveHook = `#!/bin/sh
+` + hookMarker + `
+# reject the invalid bugs, identities, tombstones and blocklist pushed to this repository
+exec git bug receive-pack-hook
+`
+
+const postMergeHook = `#!/bin/sh
+` + hookMarker + `
+# pull the bugs along a regular git pull, if enabled in the config
+exec git bug hook post-merge
+`
+
+var (
+	hookInstallForce    bool
+	hookInstallServer   bool
+	hookInstallAutoSync bool
+)
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	dir, err := hooksDir(repo)
+	if err != nil {
+		return err
+	}
+
+	hooks := [][2]string{{"pre-push", prePushHook}}
+	if hookInstallAutoSync {
+		hooks = append(hooks, [2]string{"post-merge", postMergeHook})
+	}
+	if hookInstallServer {
+		hooks = [][2]string{{"pre-receive", preReceiveHook}}
+	}
+
+	for _, hook := range hooks {
+		err = installHook(dir, hook[0], hook[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func installHook(dir string, name string, content string) error {
+	path := filepath.Join(dir, name)
+
+	ours, err := isGitBugHook(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(path)
+	if err == nil && !ours && !hookInstallForce {
+		return fmt.Errorf("a %s hook already exist at %s, use --force to replace it", name, path)
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(path, []byte(content), 0755)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s hook installed at %s\n", name, path)
+	return nil
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install a git hook validating the bugs and identities pushed.",
+	Long: `Install a pre-push git hook validating the bugs and identities about to be pushed, with "git bug push" or a plain "git push".
+
+For each bug, the operations are checked against the JSON schema and validated, the lamport times must not be ahead of the local clocks and the operations must not be dated in the future. The identities are checked against the JSON schema and validated as well. The operations signed with the signer of their author, see "git bug user signer", are verified: a bad signature aborts the push.
+
+With --auto-sync, also install a post-merge hook pulling the bugs along a regular "git pull". The hooks then pull and push the bugs if "git-bug.auto-sync.pull" and "git-bug.auto-sync.push" are set to true in the git config of the repository. See "git bug hook post-merge".
+
+With --server, install instead a pre-receive hook rejecting the pushes of invalid bugs, identities, tombstones and blocklist, for a repository hosted on a self-hosted git server. It can also require the operations to be signed with a key of their author. See "git bug receive-pack-hook".
+
+An existing hook is only replaced with --force.`,
+	PreRunE: loadRepo,
+	RunE:    runHookInstall,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	hookCmd.AddCommand(hookInstallCmd)
+
+	hookInstallCmd.Flags().SortFlags = false
+
+	hookInstallCmd.Flags().BoolVarP(&hookInstallForce, "force", "f", false,
+		"Replace an existing hook")
+	hookInstallCmd.Flags().BoolVar(&hookInstallAutoSync, "auto-sync", false,
+		"Also install the post-merge hook syncing the bugs along a regular git pull")
+	hookInstallCmd.Flags().BoolVar(&hookInstallServer, "server", false,
+		"Install the pre-receive hook of a server repository")
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func runHookPostMerge(cmd *cobra.Command, args []string) error {
+	if !readAutoSyncConfig(autoSyncPullConfigKey) || os.Getenv(autoSyncEnv) != "" {
+		return nil
+	}
+
+	// a failure must not look like a failure of the git pull itself
+	if err := autoSyncPull(""); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "automatic pull of the bugs failed: %v\n", err)
+	}
+
+	return nil
+}
+
+var hookPostMergeCmd = &cobra.Command{
+	Use:   "post-merge [<squash>]",
+	Short: "Pull the bugs along a regular git pull.",
+	Long: `Pull the bugs and identities of the default remote, if "git-bug.auto-sync.pull" is set to true in the git config of the repository.
+
+This command is run by the post-merge hook installed with "git bug hook install --auto-sync", once a regular "git pull" has merged the remote changes.`,
+	PreRunE: loadRepo,
+	RunE:    runHookPostMerge,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	hookCmd.AddCommand(hookPostMergeCmd)
+}
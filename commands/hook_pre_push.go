@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+const zeroHash = "0000000000000000000000000000000000000000"
+
+func runHookPrePush(cmd *cobra.Command, args []string) error {
+	scanner := bufio.NewScanner(os.Stdin)
+
+	checked := 0
+	failed := 0
+	pushingBugs := false
+
+	// each line is "<local ref> <local sha> <remote ref> <remote sha>"
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+
+		localRef, localHash, remoteHash := fields[0], fields[1], git.Hash(fields[3])
+
+		// deleting a ref, nothing to validate
+		if localHash == zeroHash {
+			continue
+		}
+
+		var kind string
+		var err error
+
+		switch {
+		case strings.HasPrefix(localRef, "refs/bugs/"):
+			kind = "bug"
+			id := entity.Id(strings.TrimPrefix(localRef, "refs/bugs/"))
+			if remoteHash == zeroHash {
+				remoteHash = ""
+			}
+			err = bug.CheckLocalBug(repo, id, remoteHash)
+		case strings.HasPrefix(localRef, "refs/identities/"):
+			kind = "identity"
+			id := entity.Id(strings.TrimPrefix(localRef, "refs/identities/"))
+			err = identity.CheckLocal(repo, id)
+		default:
+			continue
+		}
+
+		checked++
+		pushingBugs = true
+
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(os.Stderr, "%s %s: %s\n", kind, localRef, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of the %d bugs and identities pushed are invalid, push aborted", failed, checked)
+	}
+
+	// along a regular git push, push the bugs as well
+	if !pushingBugs && len(args) > 0 && os.Getenv(autoSyncEnv) == "" &&
+		readAutoSyncConfig(autoSyncPushConfigKey) {
+		if err := autoSyncPush(args[0]); err != nil {
+			// the regular push goes on
+			_, _ = fmt.Fprintf(os.Stderr, "automatic push of the bugs failed: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+var hookPrePushCmd = &cobra.Command{
+	Use:   "pre-push [<remote> [<url>]]",
+	Short: "Validate the bugs and identities about to be pushed.",
+	Long: `Validate the bugs and identities about to be pushed, as listed by git on the standard input of a pre-push hook.
+
+This command is run by the hook installed with "git bug hook install", and fail if any of the pushed bug or identity is invalid. The operations not yet on the remote must not have a bad signature.
+
+If "git-bug.auto-sync.push" is set to true in the git config of the repository, the bugs and identities are pushed as well along a push of regular git refs.`,
+	PreRunE: loadRepo,
+	RunE:    runHookPrePush,
+	Args:    cobra.MaximumNArgs(2),
+}
+
+func init() {
+	hookCmd.AddCommand(hookPrePushCmd)
+}
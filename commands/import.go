@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/importer"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	importFormat string
+)
+
+func runImport(cmd *cobra.Command, args []string) error {
+	var parse func(io.Reader) ([]importer.Finding, error)
+
+	switch importFormat {
+	case "sarif":
+		parse = importer.ParseSARIF
+	case "osv":
+		parse = importer.ParseOSV
+	case "":
+		return fmt.Errorf("the format is required, with --format")
+	default:
+		return fmt.Errorf("unknown format %s", importFormat)
+	}
+
+	var input io.Reader = os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	findings, err := parse(input)
+	if err != nil {
+		return err
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	stats, err := importer.Import(backend, findings)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d finding(s): %d bug(s) created, %d reopened, %d updated, %d unchanged\n",
+		len(findings), stats.Created, stats.Reopened, stats.Updated, stats.Unchanged)
+
+	return nil
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [<file>]",
+	Short: "Create bugs from the findings of a scanner.",
+	Long: `Create bugs from the findings of a scanner, read from a file or from the standard input.
+
+The "sarif" format is the output of the static analysis tools. The "osv" format is the output of osv-scanner, or a list of OSV vulnerabilities.
+
+Each finding create a bug, labeled with its severity as "severity:high", and holding its tool, rule and package as metadata. Importing again the same finding doesn't create another bug: its bug is reopened if it has been closed as fixed, and its severity label is updated if it changed. The bugs closed with another resolution, as "wontfix", are left alone.`,
+	Example: `git bug import --format sarif results.sarif
+osv-scanner --format json -r . | git bug import --format osv`,
+	PreRunE: loadRepo,
+	RunE:    runImport,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().SortFlags = false
+
+	importCmd.Flags().StringVarP(&importFormat, "format", "f", "",
+		"Format of the findings. Valid values are [sarif,osv]")
+}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runInit(cmd *cobra.Command, args []string) error {
+	configureBridge, err := runInitRepo()
+	if err != nil {
+		return err
+	}
+
+	// the bridge configuration use its own cache, so this happen once the
+	// previous one is closed
+	if configureBridge {
+		err = runBridgeConfigure(cmd, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(`All set! A few things to know:
+- bugs and identities are stored in git, next to your code but outside of
+  its history, under refs/bugs and refs/identities
+- they are not transferred by "git push" and "git pull": use "git bug push"
+  and "git bug pull" to share them through a regular git remote
+- "git bug add" create a new bug, "git bug ls" list them and "git bug termui"
+  open an interactive interface`)
+
+	return nil
+}
+
+// runInitRepo create the user identity and configure the default remote, then
+// tell if the user want to configure a bridge
+func runInitRepo() (bool, error) {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return false, err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = initIdentity(backend)
+	if err != nil {
+		return false, err
+	}
+
+	err = initRemote(backend)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Println()
+	fmt.Println("A bridge can import and export bugs from another bug tracker (GitHub, GitLab, ...).")
+
+	return promptYesNo("Configure a bridge now? [y/N]")
+}
+
+func initIdentity(backend *cache.RepoCache) error {
+	set, err := backend.IsUserIdentitySet()
+	if err != nil {
+		return err
+	}
+
+	if set {
+		id, err := backend.GetUserIdentity()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Using the identity %s %s\n", id.Id().Human(), id.DisplayName())
+		return nil
+	}
+
+	fmt.Println("Your identity will be attached to the bugs and comments you write.")
+
+	preName, err := backend.GetUserName()
+	if err != nil {
+		return err
+	}
+
+	name, err := input.PromptDefault("Name", "name", preName, input.Required)
+	if err != nil {
+		return err
+	}
+
+	preEmail, err := backend.GetUserEmail()
+	if err != nil {
+		return err
+	}
+
+	email, err := input.PromptDefault("Email", "email", preEmail, input.Required)
+	if err != nil {
+		return err
+	}
+
+	id, err := backend.NewIdentity(name, email)
+	if err != nil {
+		return err
+	}
+
+	err = backend.SetUserIdentity(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created the identity %s %s\n", id.Id().Human(), id.DisplayName())
+
+	return nil
+}
+
+func initRemote(backend *cache.RepoCache) error {
+	remotes, err := backend.GetRemotes()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+
+	switch len(remotes) {
+	case 0:
+		fmt.Println(`No git remote is configured. Once you have one ("git remote add"), bugs can be shared with "git bug push" and "git bug pull".`)
+		return nil
+
+	case 1:
+		for name := range remotes {
+			fmt.Printf("Bugs will be shared through the remote %s\n", name)
+			return backend.SetDefaultRemote(name)
+		}
+	}
+
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	choices := make([]string, len(names))
+	for i, name := range names {
+		choices[i] = fmt.Sprintf("%s (%s)", name, remotes[name])
+	}
+
+	index, err := input.PromptChoice("Remote to share the bugs with by default", choices)
+	if err != nil {
+		return err
+	}
+
+	return backend.SetDefaultRemote(names[index])
+}
+
+func promptYesNo(prompt string) (bool, error) {
+	answer, err := input.Prompt(prompt, "answer")
+	if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up git-bug in the current repository.",
+	Long: `Set up git-bug in the current repository.
+
+This interactive wizard create your identity, pre-filled from the git configuration, choose the remote to share the bugs with and optionally configure a bridge. It can be run again safely.`,
+	PreRunE: loadRepo,
+	RunE:    runInit,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(initCmd)
+	initCmd.Flags().SortFlags = false
+}
@@ -2,7 +2,9 @@ package commands
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/commands/select"
 	"github.com/MichaelMure/git-bug/util/interrupt"
@@ -17,11 +19,24 @@ func runLabelAdd(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
 	b, args, err := _select.ResolveBug(backend, args)
 	if err != nil {
 		return err
 	}
 
+	// look for typos before the new labels become existing ones
+	var hints []string
+	for _, arg := range args {
+		if suggestion, ok := backend.SuggestLabel(bug.Label(arg)); ok {
+			hints = append(hints, fmt.Sprintf("%q is a new label, did you mean %q?", arg, suggestion))
+		}
+	}
+
 	changes, _, err := b.ChangeLabels(args, nil)
 
 	for _, change := range changes {
@@ -32,6 +47,10 @@ func runLabelAdd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	for _, hint := range hints {
+		_, _ = fmt.Fprintln(os.Stderr, hint)
+	}
+
 	return b.Commit()
 }
 
@@ -44,4 +63,5 @@ var labelAddCmd = &cobra.Command{
 
 func init() {
 	labelCmd.AddCommand(labelAddCmd)
+	addAsFlag(labelAddCmd)
 }
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func runLabelMerge(cmd *cobra.Command, args []string) error {
+	return replaceLabel(args[0], args[1], true)
+}
+
+var labelMergeCmd = &cobra.Command{
+	Use:   "merge <label> <into>",
+	Short: "Merge a label into another one on all the bugs having it.",
+	Long: `Merge a label into another one on all the bugs having it.
+
+A label change operation is added to each bug having the first label, removing it and adding the second one if the bug doesn't have it already. The first label is removed from the label registry.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runLabelMerge,
+	Args:    cobra.ExactArgs(2),
+}
+
+func init() {
+	labelCmd.AddCommand(labelMergeCmd)
+	addAsFlag(labelMergeCmd)
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runLabelRename(cmd *cobra.Command, args []string) error {
+	return replaceLabel(args[0], args[1], false)
+}
+
+// replaceLabel replace a label by another one on all the bugs having it, and
+// in the label registry. The new label must not exist already when renaming,
+// and must when merging.
+func replaceLabel(old string, new string, merge bool) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	var ids []entity.Id
+	if merge {
+		ids, err = backend.MergeLabel(bug.Label(old), bug.Label(new))
+	} else {
+		ids, err = backend.RenameLabel(bug.Label(old), bug.Label(new))
+	}
+	for _, id := range ids {
+		fmt.Printf("%s: label %s replaced by %s\n", id.Human(), old, new)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d bug(s) updated\n", len(ids))
+
+	return nil
+}
+
+var labelRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a label on all the bugs having it.",
+	Long: `Rename a label on all the bugs having it.
+
+A label change operation is added to each of these bugs, replacing the old label by the new one. The label keep its color in the label registry.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runLabelRename,
+	Args:    cobra.ExactArgs(2),
+}
+
+func init() {
+	labelCmd.AddCommand(labelRenameCmd)
+	addAsFlag(labelRenameCmd)
+}
@@ -18,6 +18,11 @@ func runLabelRm(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
 	b, args, err := _select.ResolveBug(backend, args)
 	if err != nil {
 		return err
@@ -45,4 +50,5 @@ var labelRmCmd = &cobra.Command{
 
 func init() {
 	labelCmd.AddCommand(labelRmCmd)
+	addAsFlag(labelRmCmd)
 }
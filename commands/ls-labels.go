@@ -8,6 +8,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	lsLabelUsage bool
+)
+
 func runLsLabel(cmd *cobra.Command, args []string) error {
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
@@ -16,6 +20,13 @@ func runLsLabel(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	if lsLabelUsage {
+		for _, usage := range backend.LabelsUsage() {
+			fmt.Printf("%s\t%d\n", usage.Label, usage.Count)
+		}
+		return nil
+	}
+
 	labels := backend.ValidLabels()
 
 	for _, l := range labels {
@@ -37,4 +48,9 @@ Note: in the future, a proper label policy could be implemented where valid labe
 
 func init() {
 	RootCmd.AddCommand(lsLabelCmd)
+
+	lsLabelCmd.Flags().SortFlags = false
+
+	lsLabelCmd.Flags().BoolVarP(&lsLabelUsage, "usage", "u", false,
+		"Show the number of bugs having each label, the most used first")
 }
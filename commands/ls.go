@@ -2,28 +2,45 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	text "github.com/MichaelMure/go-term-text"
 	"github.com/spf13/cobra"
 
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/numbering"
 	"github.com/MichaelMure/git-bug/util/colors"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 )
 
 var (
 	lsStatusQuery      []string
+	lsResolutionQuery  []string
 	lsAuthorQuery      []string
 	lsParticipantQuery []string
 	lsLabelQuery       []string
+	lsComponentQuery   []string
+	lsAssigneeQuery    []string
 	lsTitleQuery       []string
 	lsActorQuery       []string
+	lsParentQuery      []string
 	lsNoQuery          []string
+	lsDuplicateQuery   string
 	lsSortBy           string
 	lsSortDirection    string
+	lsRemote           string
 )
 
+// lsExcerptResolver is the source of the excerpts displayed by "ls", either
+// the local cache or the bugs of a remote
+type lsExcerptResolver interface {
+	ResolveBugExcerpt(id entity.Id) (*cache.BugExcerpt, error)
+	ResolveIdentityExcerpt(id entity.Id) (*cache.IdentityExcerpt, error)
+	BugIdLength() int
+}
+
 func runLsBug(cmd *cobra.Command, args []string) error {
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
@@ -40,23 +57,60 @@ func runLsBug(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		query, err = lsQueryFromFlags()
+		query, err = lsQueryFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lsRemote != "" {
+		remoteBugs, err := backend.FetchRemoteBugs(lsRemote)
 		if err != nil {
 			return err
 		}
+
+		return lsTextOutput(remoteBugs, remoteBugs.QueryBugs(query), nil)
+	}
+
+	// a clone, or a regular fetch, don't bring the bugs
+	if len(backend.AllBugsIds()) == 0 {
+		remotes, err := backend.GetRemotes()
+		if err == nil && len(remotes) > 0 {
+			_, _ = fmt.Fprintln(os.Stderr, "No bug yet. The bugs of a remote are not fetched with git: run \"git bug pull\" to get them.")
+		}
+		return nil
+	}
+
+	// the sequential numbers are displayed once some bugs have one
+	var numbers func(id entity.Id) (int, bool)
+	if backend.HasBugNumbers() {
+		numbers = backend.BugNumber
+	}
+
+	return lsTextOutput(backend, backend.QueryBugs(query), numbers)
+}
+
+// lsTextOutput print the bugs, with their sequential number if numbers is
+// not nil
+func lsTextOutput(resolver lsExcerptResolver, ids []entity.Id, numbers func(id entity.Id) (int, bool)) error {
+	stopPager, err := startPager()
+	if err != nil {
+		return err
 	}
+	defer stopPager()
 
-	allIds := backend.QueryBugs(query)
+	// the ids are shortened as much as possible while staying unambiguous
+	idLength := resolver.BugIdLength()
 
-	for _, id := range allIds {
-		b, err := backend.ResolveBugExcerpt(id)
+	for _, id := range ids {
+		b, err := resolver.ResolveBugExcerpt(id)
 		if err != nil {
 			return err
 		}
 
 		var name string
 		if b.AuthorId != "" {
-			author, err := backend.ResolveIdentityExcerpt(b.AuthorId)
+			author, err := resolver.ResolveIdentityExcerpt(b.AuthorId)
 			if err != nil {
 				name = "<missing author data>"
 			} else {
@@ -69,9 +123,7 @@ func runLsBug(cmd *cobra.Command, args []string) error {
 		var labelsTxt strings.Builder
 		for _, l := range b.Labels {
 			lc256 := l.Color().Term256()
-			labelsTxt.WriteString(lc256.Escape())
-			labelsTxt.WriteString(" ◼")
-			labelsTxt.WriteString(lc256.Unescape())
+			labelsTxt.WriteString(colors.Wrap(lc256.Escape(), lc256.Unescape(), " ◼"))
 		}
 
 		// truncate + pad if needed
@@ -84,12 +136,28 @@ func runLsBug(cmd *cobra.Command, args []string) error {
 			comments = "    ∞ 💬"
 		}
 
-		fmt.Printf("%s %s\t%s\t%s\t%s\n",
-			colors.Cyan(b.Id.Human()),
-			colors.Yellow(b.Status),
+		// the progress of the checklist, if any
+		var checklist string
+		if b.ChecklistTotal > 0 {
+			checklist = fmt.Sprintf("\t%d/%d done", b.ChecklistChecked, b.ChecklistTotal)
+		}
+
+		idFmt := colors.Id(b.Id.Short(idLength))
+		if numbers != nil {
+			var numberFmt string
+			if number, ok := numbers(b.Id); ok {
+				numberFmt = numbering.Format(number)
+			}
+			idFmt += " " + colors.Id(text.LeftPadMaxLine(numberFmt, 6, 0))
+		}
+
+		fmt.Printf("%s %s\t%s\t%s\t%s%s\n",
+			idFmt,
+			colors.Status(b.Status),
 			titleFmt+labelsFmt,
-			colors.Magenta(authorFmt),
+			colors.Author(authorFmt),
 			comments,
+			checklist,
 		)
 	}
 
@@ -97,7 +165,7 @@ func runLsBug(cmd *cobra.Command, args []string) error {
 }
 
 // Transform the command flags into a query
-func lsQueryFromFlags() (*cache.Query, error) {
+func lsQueryFromFlags(cmd *cobra.Command) (*cache.Query, error) {
 	query := cache.NewQuery()
 
 	for _, status := range lsStatusQuery {
@@ -108,6 +176,14 @@ func lsQueryFromFlags() (*cache.Query, error) {
 		query.Status = append(query.Status, f)
 	}
 
+	for _, resolution := range lsResolutionQuery {
+		f, err := cache.ResolutionFilter(resolution)
+		if err != nil {
+			return nil, err
+		}
+		query.Resolution = append(query.Resolution, f)
+	}
+
 	for _, title := range lsTitleQuery {
 		f := cache.TitleFilter(title)
 		query.Title = append(query.Title, f)
@@ -133,15 +209,46 @@ func lsQueryFromFlags() (*cache.Query, error) {
 		query.Label = append(query.Label, f)
 	}
 
+	for _, component := range lsComponentQuery {
+		f := cache.ComponentFilter(component)
+		query.Component = append(query.Component, f)
+	}
+
+	for _, assignee := range lsAssigneeQuery {
+		f := cache.AssigneeFilter(assignee)
+		query.Assignee = append(query.Assignee, f)
+	}
+
+	for _, parent := range lsParentQuery {
+		f := cache.ParentFilter(parent)
+		query.Parent = append(query.Parent, f)
+	}
+
 	for _, no := range lsNoQuery {
 		switch no {
 		case "label":
 			query.NoFilters = append(query.NoFilters, cache.NoLabelFilter())
+		case "component":
+			query.NoFilters = append(query.NoFilters, cache.NoComponentFilter())
+		case "assignee":
+			query.NoFilters = append(query.NoFilters, cache.NoAssigneeFilter())
+		case "resolution":
+			query.NoFilters = append(query.NoFilters, cache.NoResolutionFilter())
+		case "parent":
+			query.NoFilters = append(query.NoFilters, cache.NoParentFilter())
 		default:
 			return nil, fmt.Errorf("unknown \"no\" filter %s", no)
 		}
 	}
 
+	if lsDuplicateQuery != "" {
+		f, err := cache.DuplicateFilter(lsDuplicateQuery)
+		if err != nil {
+			return nil, err
+		}
+		query.Duplicate = append(query.Duplicate, f)
+	}
+
 	switch lsSortBy {
 	case "id":
 		query.OrderBy = cache.OrderById
@@ -149,17 +256,25 @@ func lsQueryFromFlags() (*cache.Query, error) {
 		query.OrderBy = cache.OrderByCreation
 	case "edit":
 		query.OrderBy = cache.OrderByEdit
+	case "votes":
+		query.OrderBy = cache.OrderByVotes
 	default:
 		return nil, fmt.Errorf("unknown sort flag %s", lsSortBy)
 	}
 
-	switch lsSortDirection {
+	// the most voted bugs first, unless asked otherwise
+	direction := lsSortDirection
+	if lsSortBy == "votes" && !cmd.Flags().Changed("direction") {
+		direction = "desc"
+	}
+
+	switch direction {
 	case "asc":
 		query.OrderDirection = cache.OrderAscending
 	case "desc":
 		query.OrderDirection = cache.OrderDescending
 	default:
-		return nil, fmt.Errorf("unknown sort direction %s", lsSortDirection)
+		return nil, fmt.Errorf("unknown sort direction %s", direction)
 	}
 
 	return query, nil
@@ -170,12 +285,26 @@ var lsCmd = &cobra.Command{
 	Short: "List bugs.",
 	Long: `Display a summary of each bugs.
 
-You can pass an additional query to filter and order the list. This query can be expressed either with a simple query language or with flags.`,
+You can pass an additional query to filter and order the list. This query can be expressed either with a simple query language or with flags.
+
+With --remote, the bugs of the remote are listed without being merged. They are still fetched in full, as "git bug pull" would: an excerpt is compiled from the whole history of a bug, not from its last commit. Only the objects missing locally are downloaded, and a later pull doesn't download them again.`,
 	Example: `List open bugs sorted by last edition with a query:
 git bug ls status:open sort:edit-desc
 
+List the open bugs of a component:
+git bug ls status:open component:frontend
+
 List closed bugs sorted by creation with flags:
 git bug ls --status closed --by creation
+
+List the most voted open bugs:
+git bug ls status:open sort:votes
+
+List the bugs marked as duplicate:
+git bug ls duplicate:yes
+
+List the open bugs of a remote, without merging them:
+git bug ls --remote origin status:open
 `,
 	PreRunE: loadRepo,
 	RunE:    runLsBug,
@@ -188,20 +317,33 @@ func init() {
 
 	lsCmd.Flags().StringSliceVarP(&lsStatusQuery, "status", "s", nil,
 		"Filter by status. Valid values are [open,closed]")
+	lsCmd.Flags().StringSliceVarP(&lsResolutionQuery, "resolution", "R", nil,
+		"Filter by resolution. Valid values are [fixed,wontfix,duplicate,invalid,works-for-me]")
 	lsCmd.Flags().StringSliceVarP(&lsAuthorQuery, "author", "a", nil,
-		"Filter by author")
+		"Filter by author, \"me\" for the user identity")
 	lsCmd.Flags().StringSliceVarP(&lsParticipantQuery, "participant", "p", nil,
-		"Filter by participant")
+		"Filter by participant, \"me\" for the user identity")
 	lsCmd.Flags().StringSliceVarP(&lsActorQuery, "actor", "A", nil,
-		"Filter by actor")
+		"Filter by actor, \"me\" for the user identity")
 	lsCmd.Flags().StringSliceVarP(&lsLabelQuery, "label", "l", nil,
 		"Filter by label")
+	_ = lsCmd.Flags().SetAnnotation("label", cobra.BashCompCustom, []string{"__git-bug_get_labels"})
+	lsCmd.Flags().StringSliceVarP(&lsComponentQuery, "component", "c", nil,
+		"Filter by component")
+	lsCmd.Flags().StringSliceVarP(&lsAssigneeQuery, "assignee", "", nil,
+		"Filter by assignee, \"me\" for the user identity")
 	lsCmd.Flags().StringSliceVarP(&lsTitleQuery, "title", "t", nil,
 		"Filter by title")
+	lsCmd.Flags().StringSliceVarP(&lsParentQuery, "parent", "P", nil,
+		"Filter by parent, to list the sub-tasks of a bug")
 	lsCmd.Flags().StringSliceVarP(&lsNoQuery, "no", "n", nil,
-		"Filter by absence of something. Valid values are [label]")
+		"Filter by absence of something. Valid values are [label,component,assignee,resolution,parent]")
+	lsCmd.Flags().StringVarP(&lsDuplicateQuery, "duplicate", "D", "",
+		"Filter the bugs marked as duplicate, which are hidden by default. Valid values are [yes,no,any]")
 	lsCmd.Flags().StringVarP(&lsSortBy, "by", "b", "creation",
-		"Sort the results by a characteristic. Valid values are [id,creation,edit]")
+		"Sort the results by a characteristic. Valid values are [id,creation,edit,votes]")
 	lsCmd.Flags().StringVarP(&lsSortDirection, "direction", "d", "asc",
-		"Select the sorting direction. Valid values are [asc,desc]")
+		"Select the sorting direction, descending by default for the votes. Valid values are [asc,desc]")
+	lsCmd.Flags().StringVarP(&lsRemote, "remote", "r", "",
+		"Fetch and list the bugs of the given remote instead of the local ones, without merging them")
 }
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	markReadAll    bool
+	markReadUnread bool
+)
+
+func runMarkRead(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	var ids []entity.Id
+
+	switch {
+	case markReadAll && len(args) > 0:
+		return errors.New("no bug can be given with --all")
+
+	case markReadAll:
+		ids = backend.AllBugsIds()
+
+	case len(args) > 1:
+		for _, prefix := range args {
+			b, err := backend.ResolveBugPrefix(prefix)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, b.Id())
+		}
+
+	default:
+		b, _, err := _select.ResolveBug(backend, args)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, b.Id())
+	}
+
+	if markReadUnread {
+		return backend.MarkUnread(ids...)
+	}
+	return backend.MarkRead(ids...)
+}
+
+var markReadCmd = &cobra.Command{
+	Use:   "mark-read [<id>...]",
+	Short: "Mark bugs as read.",
+	Long: `Mark bugs as read, as if you had seen all their operations.
+
+A bug is unread once someone else edit it after you last read it, and is found with "git bug ls is:unread". Displaying a bug mark it as read. The read markers are kept in the repository for each identity, and are never pushed.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runMarkRead,
+}
+
+func init() {
+	RootCmd.AddCommand(markReadCmd)
+
+	markReadCmd.Flags().SortFlags = false
+
+	markReadCmd.Flags().BoolVarP(&markReadAll, "all", "a", false,
+		"Mark all the bugs")
+	markReadCmd.Flags().BoolVarP(&markReadUnread, "unread", "u", false,
+		"Mark the bugs as unread instead")
+}
@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+// the remote of the temporary repository holding the bugs of a mirror given
+// by its url
+const mirrorRemote = "mirror"
+
+var (
+	mirrorQuery  string
+	mirrorOneWay bool
+)
+
+func runMirror(cmd *cobra.Command, args []string) error {
+	var query *cache.Query
+	if mirrorQuery != "" {
+		var err error
+		query, err = cache.ParseQuery(mirrorQuery)
+		if err != nil {
+			return err
+		}
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	other, err := openMirror(backend, args[0])
+	if err != nil {
+		return err
+	}
+	defer other.Close()
+	interrupt.RegisterCleaner(other.Close)
+
+	err = printMirrorResults(backend.MirrorTo(other.RepoCache, query))
+	if err != nil {
+		return err
+	}
+
+	if !mirrorOneWay {
+		err = printMirrorResults(other.MirrorTo(backend, query))
+		if err != nil {
+			return err
+		}
+	}
+
+	return other.publish()
+}
+
+// mirrorRepo is the other repository of a mirror, or a temporary copy of its
+// bugs if given by its url
+type mirrorRepo struct {
+	*cache.RepoCache
+
+	// the temporary repository and the url of the repository, if any
+	dir string
+	url string
+}
+
+// openMirror open the repository at the given path, or a temporary copy of
+// the bugs of the repository at the given url
+func openMirror(backend *cache.RepoCache, pathOrUrl string) (*mirrorRepo, error) {
+	if _, err := os.Stat(pathOrUrl); err == nil {
+		r, err := repository.NewGitRepo(pathOrUrl, bug.Witnesser)
+		if err != nil {
+			return nil, err
+		}
+
+		other, err := cache.NewRepoCache(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return &mirrorRepo{RepoCache: other}, nil
+	}
+
+	dir, err := ioutil.TempDir("", "git-bug-mirror")
+	if err != nil {
+		return nil, err
+	}
+
+	other, err := newMirrorCopy(backend, dir, pathOrUrl)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return &mirrorRepo{RepoCache: other, dir: dir, url: pathOrUrl}, nil
+}
+
+func newMirrorCopy(backend *cache.RepoCache, dir string, url string) (*cache.RepoCache, error) {
+	_, err := repository.InitBareGitRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := repository.NewGitRepo(dir, bug.Witnesser)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.AddRemote(mirrorRemote, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// git need a committer for the mirrored bugs
+	name, err := backend.GetUserName()
+	if err != nil {
+		return nil, err
+	}
+	email, err := backend.GetUserEmail()
+	if err != nil {
+		return nil, err
+	}
+	err = r.LocalConfig().StoreString("user.name", name)
+	if err != nil {
+		return nil, err
+	}
+	err = r.LocalConfig().StoreString("user.email", email)
+	if err != nil {
+		return nil, err
+	}
+
+	other, err := cache.NewRepoCache(r)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Fetching the bugs of %s...\n", url)
+
+	err = other.Pull(mirrorRemote)
+	if err != nil {
+		_ = other.Close()
+		return nil, err
+	}
+
+	return other, nil
+}
+
+// publish push the mirrored bugs to the repository at the url, if any
+func (m *mirrorRepo) publish() error {
+	if m.dir == "" {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Pushing the bugs to %s...\n", m.url)
+
+	_, err := m.Push(mirrorRemote)
+	return err
+}
+
+// Close close the repository, and remove the temporary copy if any
+func (m *mirrorRepo) Close() error {
+	err := m.RepoCache.Close()
+	if m.dir != "" {
+		_ = os.RemoveAll(m.dir)
+	}
+	return err
+}
+
+func printMirrorResults(results []cache.MirrorResult, err error) error {
+	for _, result := range results {
+		for _, warning := range result.Warnings {
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", result.Source.Human(), warning)
+		}
+
+		if result.Status == cache.MirrorStatusNothing {
+			continue
+		}
+
+		fmt.Printf("%s -> %s: %s, %d operations\n",
+			result.Source.Human(),
+			result.Target.Human(),
+			result.Status,
+			result.Operations,
+		)
+	}
+
+	return err
+}
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror <path-or-url>",
+	Short: "Synchronize the bugs with another git-bug repository.",
+	Long: `Synchronize the bugs with another git-bug repository, without bridge.
+
+Unlike a git remote, which share the same bugs, the other repository keeps its own bugs: the bugs are copied in both directions as new bugs, and the operations made since on one side are copied to the other. The id of the original bug and operations are recorded in the "mirror-bug" and "mirror-origin" metadata, so that nothing is copied twice. This suit the fork-based workflows, where a fork track its own bugs and exchange a subset of them with the upstream repository.
+
+The other repository is given as a local path, or as a git url, in which case its bugs are fetched in a temporary repository and the mirrored bugs pushed back.
+
+With a query, only the matching bugs of each side are mirrored. The authors of the bugs are copied to the other repository with their identity.`,
+	Example: `git bug mirror ../upstream
+git bug mirror --one-way --query "label:public" git@github.com:MichaelMure/git-bug.git`,
+	PreRunE: loadRepo,
+	RunE:    runMirror,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(mirrorCmd)
+	mirrorCmd.Flags().SortFlags = false
+
+	mirrorCmd.Flags().StringVarP(&mirrorQuery, "query", "q", "",
+		"Only mirror the bugs matching the query")
+	mirrorCmd.Flags().BoolVar(&mirrorOneWay, "one-way", false,
+		"Only copy the bugs of this repository to the other one")
+}
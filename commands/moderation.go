@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runModeration(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	for _, entry := range backend.BlocklistEntries() {
+		if !entry.Blocked {
+			continue
+		}
+
+		name := "<unknown identity>"
+		i, err := backend.ResolveIdentityExcerpt(entry.IdentityId)
+		if err == nil {
+			name = i.DisplayName()
+		}
+
+		fmt.Printf("%s %s", colors.Cyan(entry.IdentityId.Human()), name)
+		if entry.Reason != "" {
+			fmt.Printf(" (%s)", entry.Reason)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+var moderationCmd = &cobra.Command{
+	Use:   "moderation",
+	Short: "List the blocked identities.",
+	Long: `List the blocked identities.
+
+The operations of a blocked identity are ignored and the bugs it created are hidden. The blocklist is shared with the remotes on push and pull.
+
+If the "git-bug.maintainers" configuration of the repository is set, only the maintainers can change the blocklist, and only their changes are honored.
+
+The changes are signed with the signer configured with "git bug user signer", whose key must be registered on the identity. The changes of a remote are only merged if some maintainers are configured, and if signed by one of them: without maintainers, the blocklist of the remotes is ignored.`,
+	PreRunE: loadRepo,
+	RunE:    runModeration,
+}
+
+func init() {
+	RootCmd.AddCommand(moderationCmd)
+	moderationCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	moderationBlockReason string
+)
+
+func runModerationBlock(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	i, err := backend.ResolveIdentityPrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	err = backend.BlockIdentity(i.Id(), moderationBlockReason)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s is now blocked\n", i.Id().Human(), i.DisplayName())
+
+	if !backend.IsBlocklistSigned() {
+		fmt.Println("Some changes of the blocklist are not signed, the other repositories will ignore them. See \"git bug user signer\".")
+	}
+
+	return nil
+}
+
+var moderationBlockCmd = &cobra.Command{
+	Use:     "block <user-id>",
+	Short:   "Block an identity, ignoring its operations and hiding its bugs.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runModerationBlock,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	moderationCmd.AddCommand(moderationBlockCmd)
+	moderationBlockCmd.Flags().SortFlags = false
+
+	moderationBlockCmd.Flags().StringVarP(&moderationBlockReason, "reason", "r", "",
+		"Record why the identity is blocked")
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runModerationUnblock(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	i, err := backend.ResolveIdentityPrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	err = backend.UnblockIdentity(i.Id())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s is now unblocked\n", i.Id().Human(), i.DisplayName())
+
+	if !backend.IsBlocklistSigned() {
+		fmt.Println("Some changes of the blocklist are not signed, the other repositories will ignore them. See \"git bug user signer\".")
+	}
+
+	return nil
+}
+
+var moderationUnblockCmd = &cobra.Command{
+	Use:     "unblock <user-id>",
+	Short:   "Unblock an identity.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runModerationUnblock,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	moderationCmd.AddCommand(moderationUnblockCmd)
+	moderationUnblockCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runNotifications(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	me, err := backend.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	query, err := cache.ParseQuery("review:me sort:edit-desc")
+	if err != nil {
+		return err
+	}
+
+	for _, id := range backend.QueryBugs(query) {
+		b, err := backend.ResolveBug(id)
+		if err != nil {
+			return err
+		}
+
+		snap := b.Snapshot()
+
+		for _, r := range snap.ReviewRequests {
+			if r.Answered || r.Reviewer.Id() != me.Id() {
+				continue
+			}
+
+			fmt.Printf("%s %s: %s asked for your input %s\n",
+				colors.Cyan(id.Human()),
+				snap.Title,
+				colors.Magenta(r.Requester.DisplayName()),
+				humanize.Time(r.UnixTime.Time()),
+			)
+			if r.Message != "" {
+				fmt.Printf("  %s\n", r.Message)
+			}
+		}
+	}
+
+	return nil
+}
+
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "List the bugs waiting for your input.",
+	Long: `List the bugs waiting for your input, as your review has been requested with "git bug review request".
+
+A request is answered as soon as you do anything on the bug, as commenting.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runNotifications,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(notificationsCmd)
+
+	notificationsCmd.Flags().SortFlags = false
+}
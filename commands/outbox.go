@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var outboxCmd = &cobra.Command{
+	Use:   "outbox [<remote>]",
+	Short: "List the local changes not published yet.",
+	Long: `List the bugs with local operations that have not been pushed to the remote yet.
+
+What the remote has is known from the last pull or push, so the operations pushed meanwhile by "git push" can still be listed. "git bug sync" publish the outbox.`,
+	PreRunE: loadRepo,
+	RunE:    runOutboxLs,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(outboxCmd)
+
+	outboxCmd.Flags().SortFlags = false
+}
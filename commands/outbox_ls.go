@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runOutboxLs(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	remote, err := backend.DefaultRemote()
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	outbox, err := backend.Outbox(remote)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range outbox {
+		state := fmt.Sprintf("%d %s", len(entry.Operations), plural(len(entry.Operations), "operation", "operations"))
+		if entry.New {
+			state += ", new bug"
+		}
+
+		fmt.Printf("%s %s (%s)\n", colors.Cyan(entry.Id.Human()), entry.Title, state)
+
+		for _, op := range entry.Operations {
+			fmt.Printf("  %s %s %s\n",
+				op.Time().Format("2006-01-02 15:04"),
+				colors.Magenta(op.GetAuthor().DisplayName()),
+				describeOperation(op),
+			)
+		}
+	}
+
+	return nil
+}
+
+var outboxLsCmd = &cobra.Command{
+	Use:     "ls [<remote>]",
+	Short:   "List the local changes not published yet.",
+	PreRunE: loadRepo,
+	RunE:    runOutboxLs,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	outboxCmd.AddCommand(outboxLsCmd)
+
+	outboxLsCmd.Flags().SortFlags = false
+}
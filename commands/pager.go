@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// if true, the output is never sent to the pager, as given with --no-pager
+var rootNoPager bool
+
+// startPager send the standard output of the command to the pager of the
+// user, as git does: the pager configured with GIT_PAGER, core.pager or
+// PAGER, "less" by default. Nothing is done if the output is not a terminal,
+// with --no-pager, or if the pager is empty or "cat".
+//
+// The returned function wait for the pager to exit, and must be called once
+// the output is done.
+func startPager() (func(), error) {
+	noop := func() {}
+
+	if rootNoPager || !terminal.IsTerminal(int(os.Stdout.Fd())) {
+		return noop, nil
+	}
+
+	pager, err := repo.GetCorePager()
+	if err != nil {
+		return nil, fmt.Errorf("unable to detect the git pager: %v", err)
+	}
+	if pager == "" || pager == "cat" {
+		return noop, nil
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	// the pager is a shell command, as "less -S"
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = r
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// as git, less quit if the output fit in the terminal and keep the
+	// colors, unless configured otherwise
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+
+	if err := cmd.Start(); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		// without pager, the output go to the terminal
+		_, _ = fmt.Fprintf(os.Stderr, "unable to start the pager %s: %v\n", pager, err)
+		return noop, nil
+	}
+	_ = r.Close()
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	return func() {
+		os.Stdout = stdout
+		_ = w.Close()
+		_ = cmd.Wait()
+	}, nil
+}
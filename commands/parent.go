@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runParent(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	snap := b.Snapshot()
+
+	if snap.Parent != "" {
+		fmt.Println(formatBugId(backend, snap.Parent))
+	}
+
+	return nil
+}
+
+var parentCmd = &cobra.Command{
+	Use:   "parent [<id>]",
+	Short: "Display or change the parent of a sub-task.",
+	Long: `Display or change the parent of a sub-task.
+
+A bug can be split in sub-tasks, each of them being a bug with the first one as parent. The sub-tasks of a bug are listed with "git bug ls parent:<id>", and "git bug show" display how many of them are done.`,
+	PreRunE: loadRepo,
+	RunE:    runParent,
+}
+
+func init() {
+	RootCmd.AddCommand(parentCmd)
+
+	parentCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runParentRm(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	op, err := b.RemoveParent()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is not a sub-task of %s anymore\n", b.Id().Human(), op.Was.Human())
+
+	return b.Commit()
+}
+
+var parentRmCmd = &cobra.Command{
+	Use:     "rm [<id>]",
+	Short:   "Make a sub-task a bug on its own.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runParentRm,
+}
+
+func init() {
+	parentCmd.AddCommand(parentRmCmd)
+
+	parentRmCmd.Flags().SortFlags = false
+
+	addAsFlag(parentRmCmd)
+}
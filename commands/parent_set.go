@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runParentSet(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return errors.New("a single parent bug is required")
+	}
+
+	parent, err := backend.ResolveBugPrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = b.SetParent(parent)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now a sub-task of %s\n", b.Id().Human(), parent.Id().Human())
+
+	return b.Commit()
+}
+
+var parentSetCmd = &cobra.Command{
+	Use:     "set [<id>] <parent>",
+	Short:   "Make a bug a sub-task of another bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runParentSet,
+}
+
+func init() {
+	parentCmd.AddCommand(parentSetCmd)
+
+	parentSetCmd.Flags().SortFlags = false
+
+	addAsFlag(parentSetCmd)
+}
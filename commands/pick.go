@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var pickSelect bool
+
+func runPick(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, err := _select.PickBug(backend, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	if pickSelect {
+		err = _select.Select(backend, b.Id())
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println(b.Id())
+
+	return nil
+}
+
+var pickCmd = &cobra.Command{
+	Use:   "pick [<query>]",
+	Short: "Pick a bug interactively and print its id.",
+	Example: `git bug show $(git bug pick)
+git bug pick --select crash
+`,
+	Long: `Pick a bug interactively with a fuzzy search on the ids and the titles of the bugs, and print its id.
+
+The picker is drawn on the terminal, so the id can be given to another command. The search start with the query given, if any. The arrows, ctrl+n and ctrl+p move the selection, enter pick the selected bug, escape and ctrl+c cancel.
+
+The commands needing a bug also offer to pick one when given none and no bug is selected, if the input is a terminal.
+`,
+	PreRunE: loadRepo,
+	RunE:    runPick,
+}
+
+func init() {
+	RootCmd.AddCommand(pickCmd)
+
+	pickCmd.Flags().SortFlags = false
+
+	pickCmd.Flags().BoolVarP(&pickSelect, "select", "s", false,
+		"Select the picked bug for implicit use in future commands")
+}
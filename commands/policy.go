@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runPolicy(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	policies, err := backend.Policies()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range policies {
+		rule := "forbid " + p.On
+		if p.Require != "" {
+			rule = fmt.Sprintf("require a %s to %s", p.Require, p.On)
+		}
+		if p.Label != "" {
+			rule += " on " + p.Label
+		}
+
+		fmt.Printf("%s\t%s", colors.Cyan(p.Name), rule)
+		if p.Message != "" {
+			fmt.Printf("\t%s", p.Message)
+		}
+		fmt.Println()
+	}
+
+	hook, err := backend.PolicyHook()
+	if err != nil {
+		return err
+	}
+	if hook != "" {
+		fmt.Printf("%s\t%s\n", colors.Cyan("hook"), hook)
+	}
+
+	lint, err := backend.Lint()
+	if err != nil {
+		return err
+	}
+	if lint.MaxTitleLength > 0 {
+		fmt.Printf("%s\ttitles of at most %d characters\n", colors.Cyan("lint"), lint.MaxTitleLength)
+	}
+	if len(lint.ForbiddenWords) > 0 {
+		fmt.Printf("%s\tforbid the words %s\n", colors.Cyan("lint"), strings.Join(lint.ForbiddenWords, ", "))
+	}
+	if len(lint.RequiredSections) > 0 {
+		fmt.Printf("%s\trequire the sections %s\n", colors.Cyan("lint"), strings.Join(lint.RequiredSections, ", "))
+	}
+
+	return nil
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "List the policies the changes of the bugs must follow.",
+	Long: `List the policies the changes of the bugs must follow.
+
+The policies are rules checked before committing the changes of the user, configured with git. A rule forbid a change, or only allow it along a comment, optionally only on the bugs having a given label:
+
+  git config git-bug.policies "blockers reopen"
+  git config git-bug.policy.blockers.on close
+  git config git-bug.policy.blockers.label release-blocker
+  git config git-bug.policy.blockers.message "release blockers are closed by the release manager"
+  git config git-bug.policy.reopen.on open
+  git config git-bug.policy.reopen.require comment
+
+The changes are create, title, comment, edit-comment, open, close, label, component, assignee, duplicate, parent, checklist and vote.
+
+An executable can check the changes as well, with "git config git-bug.policy-hook <path>". It is run from the root of the working tree, with the bug as it would be after the changes and the new operations given as JSON on its standard input, as {"bug": <snapshot>, "operations": [<operation>, ...]}. The changes are refused if it exits with a non-zero status, its output being the error. The id of the bug, empty for a new one, is in the GIT_BUG_ID environment variable.
+
+The titles and the messages can be linted as well, with a max length of the titles, forbidden words, and headings the description of a new bug must have, separated by commas:
+
+  git config git-bug.lint.max-title-length 80
+  git config git-bug.lint.forbidden-words "asap urgent"
+  git config git-bug.lint.required-sections "Steps to reproduce, Expected behavior"
+
+The content lint is skipped with --no-verify on the commands writing titles or messages.
+
+The changes imported by the bridges or pulled from a remote are not checked.`,
+	PreRunE: loadRepo,
+	RunE:    runPolicy,
+}
+
+func init() {
+	RootCmd.AddCommand(policyCmd)
+
+	policyCmd.Flags().SortFlags = false
+}
@@ -3,11 +3,15 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/i18n"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 )
 
@@ -16,50 +20,232 @@ func runPull(cmd *cobra.Command, args []string) error {
 		return errors.New("Only pulling from one remote at a time is supported")
 	}
 
-	remote := "origin"
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	remote, err := backend.DefaultRemote()
+	if err != nil {
+		return err
+	}
 	if len(args) == 1 {
 		remote = args[0]
 	}
 
-	backend, err := cache.NewRepoCache(repo)
+	// the identities of the directory are merged first, so that the bugs
+	// of the remote can refer to them
+	directory, err := backend.IdentityDirectory()
 	if err != nil {
 		return err
 	}
-	defer backend.Close()
-	interrupt.RegisterCleaner(backend.Close)
+	if directory != "" && directory != remote {
+		if !pullQuiet {
+			i18n.Println("Pulling identities from the identity directory ...")
+		}
 
-	fmt.Println("Fetching remote ...")
+		err = backend.PullIdentities(directory)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !pullQuiet {
+		i18n.Println("Fetching remote ...")
+	}
 
 	stdout, err := backend.Fetch(remote)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(stdout)
+	if !pullQuiet {
+		fmt.Println(stdout)
+		i18n.Println("Merging data ...")
+	}
+
+	return mergeAndReport(backend, remote, pullQuiet)
+}
 
-	fmt.Println("Merging data ...")
+// mergeAndReport merge the fetched bugs and identities of a remote, and print
+// a summary of the changes. When quiet, only the errors are printed.
+func mergeAndReport(backend *cache.RepoCache, remote string, quiet bool) error {
+	// the excerpts before the merge, to tell what changed
+	before := make(map[entity.Id]*cache.BugExcerpt)
+	for _, id := range backend.AllBugsIds() {
+		excerpt, err := backend.ResolveBugExcerpt(id)
+		if err != nil {
+			return err
+		}
+		before[id] = excerpt
+	}
+
+	var newBugs, updatedBugs, removedBugs []entity.Id
+	var pendingBugs []*bug.Bug
+	identities := 0
 
 	for result := range backend.MergeAll(remote) {
 		if result.Err != nil {
 			fmt.Println(result.Err)
+			continue
 		}
 
-		if result.Status != entity.MergeStatusNothing {
+		switch result.Status {
+		case entity.MergeStatusInvalid:
 			fmt.Printf("%s: %s\n", result.Id.Human(), result)
+		case entity.MergeStatusNew, entity.MergeStatusUpdated:
+			if _, ok := result.Entity.(*bug.Bug); !ok {
+				identities++
+				continue
+			}
+			if result.Status == entity.MergeStatusNew {
+				newBugs = append(newBugs, result.Id)
+			} else {
+				updatedBugs = append(updatedBugs, result.Id)
+			}
+		case entity.MergeStatusRemoved:
+			removedBugs = append(removedBugs, result.Id)
+		case entity.MergeStatusPending:
+			pendingBugs = append(pendingBugs, result.Entity.(*bug.Bug))
+		}
+	}
+
+	if quiet {
+		return nil
+	}
+
+	if len(newBugs)+len(updatedBugs)+len(removedBugs)+len(pendingBugs)+identities == 0 {
+		i18n.Println("Already up to date.")
+		return nil
+	}
+
+	if len(newBugs) > 0 {
+		fmt.Printf("%d new %s:\n", len(newBugs), plural(len(newBugs), "bug", "bugs"))
+		for _, id := range newBugs {
+			excerpt, err := backend.ResolveBugExcerpt(id)
+			if err != nil {
+				// the bug has been hidden by the moderation
+				continue
+			}
+			fmt.Printf("  %s %s\n", colors.Cyan(id.Human()), excerpt.Title)
+		}
+	}
+
+	if len(updatedBugs) > 0 {
+		fmt.Printf("%d %s updated:\n", len(updatedBugs), plural(len(updatedBugs), "bug", "bugs"))
+		for _, id := range updatedBugs {
+			excerpt, err := backend.ResolveBugExcerpt(id)
+			if err != nil {
+				continue
+			}
+			changes := describeExcerptChanges(before[id], excerpt)
+			if changes == "" {
+				fmt.Printf("  %s %s\n", colors.Cyan(id.Human()), excerpt.Title)
+			} else {
+				fmt.Printf("  %s %s: %s\n", colors.Cyan(id.Human()), excerpt.Title, changes)
+			}
+		}
+	}
+
+	if len(removedBugs) > 0 {
+		fmt.Printf("%d %s removed:\n", len(removedBugs), plural(len(removedBugs), "bug", "bugs"))
+		for _, id := range removedBugs {
+			if excerpt, ok := before[id]; ok {
+				fmt.Printf("  %s %s\n", colors.Cyan(id.Human()), excerpt.Title)
+			} else {
+				fmt.Printf("  %s\n", colors.Cyan(id.Human()))
+			}
+		}
+	}
+
+	if len(pendingBugs) > 0 {
+		fmt.Printf("%d %s held for review, see \"git bug review list\":\n", len(pendingBugs), plural(len(pendingBugs), "bug", "bugs"))
+		for _, b := range pendingBugs {
+			fmt.Printf("  %s %s\n", colors.Cyan(b.Id().Human()), b.Compile().Title)
 		}
 	}
 
+	if identities > 0 {
+		fmt.Printf("%d %s updated\n", identities, plural(identities, "identity", "identities"))
+	}
+
 	return nil
 }
 
+// describeExcerptChanges give a short summary of what changed between two
+// excerpts of the same bug
+func describeExcerptChanges(before, after *cache.BugExcerpt) string {
+	if before == nil {
+		return ""
+	}
+
+	var changes []string
+
+	if before.Title != after.Title {
+		changes = append(changes, "title changed")
+	}
+
+	if before.Status != after.Status {
+		changes = append(changes, after.Status.Action())
+	}
+
+	for _, label := range after.Labels {
+		if !hasLabel(before.Labels, label) {
+			changes = append(changes, "+"+label.String())
+		}
+	}
+	for _, label := range before.Labels {
+		if !hasLabel(after.Labels, label) {
+			changes = append(changes, "-"+label.String())
+		}
+	}
+
+	if after.LenComments > before.LenComments {
+		count := after.LenComments - before.LenComments
+		changes = append(changes, fmt.Sprintf("%d new %s", count, plural(count, "comment", "comments")))
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+func hasLabel(labels []bug.Label, label bug.Label) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func plural(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+var (
+	pullQuiet bool
+)
+
 // showCmd defines the "push" subcommand.
 var pullCmd = &cobra.Command{
-	Use:     "pull [<remote>]",
-	Short:   "Pull bugs update from a git remote.",
+	Use:   "pull [<remote>]",
+	Short: "Pull bugs update from a git remote.",
+	Long: `Pull bugs update from a git remote.
+
+Once merged, a summary of the new, updated and removed bugs is displayed, along with the number of updated identities.`,
 	PreRunE: loadRepo,
 	RunE:    runPull,
 }
 
 func init() {
 	RootCmd.AddCommand(pullCmd)
+
+	pullCmd.Flags().SortFlags = false
+
+	pullCmd.Flags().BoolVarP(&pullQuiet, "quiet", "q", false,
+		"Only display the errors")
 }
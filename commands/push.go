@@ -14,11 +14,6 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return errors.New("Only pushing to one remote at a time is supported")
 	}
 
-	remote := "origin"
-	if len(args) == 1 {
-		remote = args[0]
-	}
-
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
 		return err
@@ -26,6 +21,14 @@ func runPush(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	remote, err := backend.DefaultRemote()
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
 	stdout, err := backend.Push(remote)
 	if err != nil {
 		return err
@@ -38,8 +41,11 @@ func runPush(cmd *cobra.Command, args []string) error {
 
 // showCmd defines the "push" subcommand.
 var pushCmd = &cobra.Command{
-	Use:     "push [<remote>]",
-	Short:   "Push bugs update to a git remote.",
+	Use:   "push [<remote>]",
+	Short: "Push bugs update to a git remote.",
+	Long: `Push bugs update to a git remote.
+
+With "git config git-bug.numbering true", the bugs get a sequential number when pushed for the first time, so that they can be referred to as #123 in a conversation and in the commands, as "git bug show '#123'" (the quotes avoid a shell comment). The numbers are shared with the remote: a number already published is never reallocated, and the ids remain the canonical references.`,
 	PreRunE: loadRepo,
 	RunE:    runPush,
 }
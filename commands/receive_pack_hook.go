@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/moderation"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+const requireSignedConfigKey = "git-bug.receive-pack.require-signed"
+
+type pushedRef struct {
+	kind string
+	id   entity.Id
+	// the name of the refs not holding an entity
+	name string
+	old  git.Hash
+	new  git.Hash
+}
+
+func runReceivePackHook(cmd *cobra.Command, args []string) error {
+	requireSigned, err := repo.LocalConfig().ReadBool(requireSignedConfigKey)
+	if err != nil && err != repository.ErrNoConfigEntry {
+		return err
+	}
+
+	var refs []pushedRef
+	identityHeads := make(map[entity.Id]git.Hash)
+
+	// each line is "<old sha> <new sha> <ref>"
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		old, new, ref := git.Hash(fields[0]), git.Hash(fields[1]), fields[2]
+
+		// deleting a ref, nothing to validate
+		if new == zeroHash {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(ref, "refs/bugs/"):
+			refs = append(refs, pushedRef{
+				kind: "bug",
+				id:   entity.Id(strings.TrimPrefix(ref, "refs/bugs/")),
+				old:  old,
+				new:  new,
+			})
+		case strings.HasPrefix(ref, "refs/identities/"):
+			id := entity.Id(strings.TrimPrefix(ref, "refs/identities/"))
+			identityHeads[id] = new
+			refs = append(refs, pushedRef{kind: "identity", id: id, old: old, new: new})
+		case strings.HasPrefix(ref, "refs/tombstones/bugs/"):
+			refs = append(refs, pushedRef{
+				kind: "tombstone",
+				id:   entity.Id(strings.TrimPrefix(ref, "refs/tombstones/bugs/")),
+				old:  old,
+				new:  new,
+			})
+		case strings.HasPrefix(ref, "refs/moderation/"):
+			refs = append(refs, pushedRef{
+				kind: "moderation",
+				name: strings.TrimPrefix(ref, "refs/moderation/"),
+				old:  old,
+				new:  new,
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// the bugs, tombstones and blocklist can reference the identities pushed
+	// along
+	resolver := identity.NewPushedResolver(repo, identityHeads)
+
+	failed := 0
+
+	for _, ref := range refs {
+		old := ref.old
+		if old == zeroHash {
+			old = ""
+		}
+
+		var err error
+
+		switch ref.kind {
+		case "bug":
+			err = bug.CheckPushedBug(repo, resolver, ref.id, old, ref.new, requireSigned)
+		case "identity":
+			err = identity.CheckPushed(repo, ref.id, ref.new)
+		case "tombstone":
+			err = bug.CheckPushedTombstone(repo, resolver, ref.id, old, ref.new)
+		case "moderation":
+			if ref.name != "blocklist" {
+				err = fmt.Errorf("unknown moderation ref")
+				break
+			}
+			err = moderation.CheckPushed(repo, resolver, ref.new)
+		}
+
+		if err != nil {
+			failed++
+			name := ref.name
+			if name == "" {
+				name = ref.id.Human()
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s %s: %s\n", ref.kind, name, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of the %d refs pushed are invalid, push rejected", failed, len(refs))
+	}
+
+	return nil
+}
+
+var receivePackHookCmd = &cobra.Command{
+	Use:   "receive-pack-hook",
+	Short: "Validate the bugs, identities, tombstones and blocklist pushed to a server repository.",
+	Long: `Validate the bugs, identities, tombstones and blocklist pushed to a repository, as listed by git on the standard input of a pre-receive hook, and reject the push if any of them is invalid.
+
+This command is meant to be run as the pre-receive hook of a repository hosted on a self-hosted git server, as installed by "git bug hook install --server". The operations and identity versions are checked against the JSON schemas and validated, and must not be dated in the future. The bugs can reference the identities pushed along. The new operations must not have a bad signature.
+
+A tombstone must be signed by its author, who must be allowed to remove the bug by the removal policy of this repository, and can't be replaced. The changes of the blocklist must be signed by a maintainer of this repository.
+
+If "git-bug.receive-pack.require-signed" is set to true in the git config of the repository, the new operations of a bug must also be signed with a key of their author, as done with a signer configured with "git bug user signer". The identities are not signed, so this doesn't apply to them.`,
+	PreRunE: loadRepo,
+	RunE:    runReceivePackHook,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(receivePackHookCmd)
+}
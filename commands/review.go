@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runReview(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range b.Snapshot().ReviewRequests {
+		state := colors.Yellow("pending")
+		if r.Answered {
+			state = colors.Green("answered")
+		}
+
+		fmt.Printf("%s %s, asked by %s %s\n",
+			state,
+			colors.Magenta(r.Reviewer.DisplayName()),
+			r.Requester.DisplayName(),
+			humanize.Time(r.UnixTime.Time()),
+		)
+		if r.Message != "" {
+			fmt.Printf("  %s\n", r.Message)
+		}
+	}
+
+	return nil
+}
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [<id>]",
+	Short: "Display or request the reviews of a bug.",
+	Long: `Display or request the reviews of a bug.
+
+A review request ask identities for their input on a bug. It is answered as soon as the reviewer do anything on the bug, as commenting. The bugs waiting for your input are listed by "git bug notifications", or with the query "review:me".
+
+The incoming changes coming from untrusted remotes are reviewed with "git bug review list", "git bug review accept" and "git bug review reject".`,
+	PreRunE: loadRepo,
+	RunE:    runReview,
+}
+
+func init() {
+	RootCmd.AddCommand(reviewCmd)
+
+	reviewCmd.Flags().SortFlags = false
+}
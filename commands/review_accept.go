@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	reviewAcceptRemote string
+)
+
+func runReviewAccept(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	pending, err := backend.ResolvePendingPrefix(args[0], reviewAcceptRemote)
+	if err != nil {
+		return err
+	}
+
+	result, err := backend.AcceptPending(pending)
+	if err != nil {
+		return err
+	}
+
+	if result.Status == entity.MergeStatusInvalid {
+		return fmt.Errorf("%s: %s", result.Id.Human(), result)
+	}
+
+	fmt.Printf("%s: %s\n", result.Id.Human(), result)
+
+	return nil
+}
+
+var reviewAcceptCmd = &cobra.Command{
+	Use:     "accept <id>",
+	Short:   "Merge the incoming changes of a bug held for review.",
+	Long:    `Merge the incoming changes of a bug held for review, as "git bug pull" would have done with a trusted remote.`,
+	Example: `git bug review accept 3ae8`,
+	PreRunE: loadRepo,
+	RunE:    runReviewAccept,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	reviewCmd.AddCommand(reviewAcceptCmd)
+	reviewAcceptCmd.Flags().SortFlags = false
+
+	reviewAcceptCmd.Flags().StringVarP(&reviewAcceptRemote, "remote", "r", "",
+		"The remote the changes come from, if the bug is pending from several remotes")
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runReviewList(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	pendings, err := backend.PendingBugs()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pendings {
+		b, ops, err := backend.ReadPendingBug(p)
+		if err != nil {
+			fmt.Printf("%s %s: %s\n", colors.Cyan(p.Id.Human()), colors.Yellow(p.Remote), err)
+			continue
+		}
+
+		fmt.Printf("%s %s %s, %d new %s\n",
+			colors.Cyan(p.Id.Human()),
+			colors.Yellow(p.Remote),
+			b.Compile().Title,
+			len(ops),
+			plural(len(ops), "operation", "operations"),
+		)
+	}
+
+	return nil
+}
+
+var reviewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the incoming changes held for review.",
+	Long: `List the incoming changes held for review.
+
+The bugs pulled from the remotes listed in "git-bug.untrusted-remotes", separated by commas or spaces, are not merged: the bugs bringing new operations are held in a pending area, as well as the bundles applied with "git bug bundle apply --review". Accept them with "git bug review accept", or drop them with "git bug review reject".`,
+	Example: `git config git-bug.untrusted-remotes contributors
+git bug pull contributors
+git bug review list`,
+	PreRunE: loadRepo,
+	RunE:    runReviewList,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	reviewCmd.AddCommand(reviewListCmd)
+
+	reviewListCmd.Flags().SortFlags = false
+}
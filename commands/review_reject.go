@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	reviewRejectRemote string
+)
+
+func runReviewReject(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	pending, err := backend.ResolvePendingPrefix(args[0], reviewRejectRemote)
+	if err != nil {
+		return err
+	}
+
+	err = backend.RejectPending(pending)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: rejected\n", pending.Id.Human())
+
+	return nil
+}
+
+var reviewRejectCmd = &cobra.Command{
+	Use:   "reject <id>",
+	Short: "Drop the incoming changes of a bug held for review.",
+	Long: `Drop the incoming changes of a bug held for review.
+
+The rejected changes are not held again when pulling, until the remote bring new changes on the bug.`,
+	Example: `git bug review reject 3ae8`,
+	PreRunE: loadRepo,
+	RunE:    runReviewReject,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	reviewCmd.AddCommand(reviewRejectCmd)
+	reviewRejectCmd.Flags().SortFlags = false
+
+	reviewRejectCmd.Flags().StringVarP(&reviewRejectRemote, "remote", "r", "",
+		"The remote the changes come from, if the bug is pending from several remotes")
+}
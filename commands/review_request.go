@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	reviewRequestMessage string
+)
+
+func runReviewRequest(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, args, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	reviewers, err := resolveIdentities(backend, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.RequestReview(reviewers, reviewRequestMessage)
+	if err != nil {
+		return err
+	}
+
+	return b.Commit()
+}
+
+var reviewRequestCmd = &cobra.Command{
+	Use:     "request [<id>] <user-id>[...]",
+	Short:   "Ask identities for their input on a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runReviewRequest,
+}
+
+func init() {
+	reviewCmd.AddCommand(reviewRequestCmd)
+
+	reviewRequestCmd.Flags().SortFlags = false
+
+	reviewRequestCmd.Flags().StringVarP(&reviewRequestMessage, "message", "m", "",
+		"What is asked to the reviewers")
+	addAsFlag(reviewRequestCmd)
+}
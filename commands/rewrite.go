@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var rewriteActions = map[string]bug.RewriteAction{
+	"p": bug.RewritePick, "pick": bug.RewritePick,
+	"e": bug.RewriteEdit, "edit": bug.RewriteEdit,
+	"s": bug.RewriteSquash, "squash": bug.RewriteSquash,
+	"d": bug.RewriteDrop, "drop": bug.RewriteDrop,
+}
+
+func runRewrite(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	ops, err := b.RewritableOperations()
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		fmt.Println("No operation to rewrite, the published operations and the creation of the bug can't be rewritten.")
+		return nil
+	}
+
+	var plan strings.Builder
+	for _, op := range ops {
+		fmt.Fprintf(&plan, "pick %s %s\n", op.Id().Human(), rewriteSummary(op))
+	}
+
+	lines, err := input.RewritePlanEditorInput(repo, b.Id().Human(), plan.String())
+	if err == input.ErrEmptyPlan {
+		fmt.Println("Empty plan, aborting.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	steps, err := parseRewritePlan(lines, ops)
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == len(ops) && !rewriteChanges(steps) {
+		fmt.Println("No change, aborting.")
+		return nil
+	}
+
+	for i, step := range steps {
+		if step.Action != bug.RewriteEdit {
+			continue
+		}
+		steps[i].Text, err = rewriteEditText(findRewriteOperation(ops, step.Operation))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = b.Rewrite(steps)
+	if err != nil {
+		return err
+	}
+
+	left, err := b.RewritableOperations()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rewritten, %d %s not published yet.\n",
+		len(left), plural(len(left), "operation", "operations"))
+
+	return nil
+}
+
+// rewriteSummary describe an operation on one line of the plan
+func rewriteSummary(op bug.Operation) string {
+	var text string
+	switch op := op.(type) {
+	case *bug.AddCommentOperation:
+		text = op.Message
+	case *bug.EditCommentOperation:
+		text = op.Message
+	case *bug.RequestReviewOperation:
+		text = op.Message
+	}
+
+	summary := describeOperation(op)
+	if text == "" {
+		return summary
+	}
+
+	text = strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+	if runes := []rune(text); len(runes) > 50 {
+		text = string(runes[:50]) + "…"
+	}
+	return fmt.Sprintf("%s: %s", summary, text)
+}
+
+func parseRewritePlan(lines []string, ops []bug.Operation) ([]bug.RewriteStep, error) {
+	steps := make([]bug.RewriteStep, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid line \"%s\", expected a command and an operation", line)
+		}
+
+		action, ok := rewriteActions[fields[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown command \"%s\"", fields[0])
+		}
+
+		op, err := resolveRewriteOperation(ops, fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, bug.RewriteStep{Action: action, Operation: op.Id()})
+	}
+
+	return steps, nil
+}
+
+func resolveRewriteOperation(ops []bug.Operation, prefix string) (bug.Operation, error) {
+	var matching []bug.Operation
+	for _, op := range ops {
+		if op.Id().HasPrefix(prefix) {
+			matching = append(matching, op)
+		}
+	}
+
+	switch len(matching) {
+	case 0:
+		return nil, fmt.Errorf("operation %s not found in the operations to rewrite", prefix)
+	case 1:
+		return matching[0], nil
+	default:
+		ids := make([]entity.Id, len(matching))
+		for i, op := range matching {
+			ids[i] = op.Id()
+		}
+		return nil, bug.NewErrMultipleMatchOp(ids)
+	}
+}
+
+func findRewriteOperation(ops []bug.Operation, id entity.Id) bug.Operation {
+	for _, op := range ops {
+		if op.Id() == id {
+			return op
+		}
+	}
+	return nil
+}
+
+// rewriteChanges tell if the steps change anything
+func rewriteChanges(steps []bug.RewriteStep) bool {
+	for _, step := range steps {
+		if step.Action != bug.RewritePick {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteEditText ask the new text of an operation
+func rewriteEditText(op bug.Operation) (string, error) {
+	switch op := op.(type) {
+	case *bug.AddCommentOperation:
+		return input.BugCommentEditorInput(repo, op.Message)
+	case *bug.EditCommentOperation:
+		return input.BugCommentEditorInput(repo, op.Message)
+	case *bug.RequestReviewOperation:
+		return input.BugCommentEditorInput(repo, op.Message)
+	case *bug.SetTitleOperation:
+		return input.BugTitleEditorInput(repo, op.Title)
+	case *bug.AddChecklistItemOperation:
+		return input.BugTitleEditorInput(repo, op.Text)
+	default:
+		return "", fmt.Errorf("operation %s has no text to edit", op.Id().Human())
+	}
+}
+
+var rewriteCmd = &cobra.Command{
+	Use:   "rewrite [<id>]",
+	Short: "Edit, squash or drop the operations of a bug not published yet.",
+	Long: `Edit, squash or drop the operations of a bug not published yet.
+
+As with an interactive rebase, the operations no remote has yet are listed in the editor, each with a command: pick to keep it, edit to change its text, squash to merge a comment or a title change into the previous one, drop to remove it. The operations are then written again in a single commit on top of the published ones, so a typo in a comment can be fixed without an edition showing in the history.
+
+What the remotes have is known from the last pull or push, so pull first if the operations may have been published meanwhile. The first commit of a bug, defining its id, can't be rewritten.`,
+	Example: `git bug rewrite 2f1e3a5`,
+	PreRunE: loadRepo,
+	RunE:    runRewrite,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(rewriteCmd)
+
+	rewriteCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	rmReason string
+	rmForce  bool
+)
+
+func runRm(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, err := backend.ResolveBugPrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !rmForce {
+		snap := b.Snapshot()
+		fmt.Printf("%s %s\n", b.Id().Human(), snap.Title)
+		fmt.Println("The bug will be removed from every repository it propagates to. This can't be undone.")
+
+		confirmed, err := promptYesNo("Remove this bug? [y/N]")
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	tombstone, err := backend.RemoveBug(b.Id(), rmReason)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: bug removed\n", b.Id().Human())
+
+	if !tombstone.IsSigned() {
+		fmt.Println("No signer is configured, the other repositories will refuse the removal. See \"git bug user signer\".")
+	}
+
+	return nil
+}
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove a bug.",
+	Long: `Remove a bug.
+
+A tombstone is written in place of the bug. It get pushed like the bugs, and every repository merging it drop the bug as well.
+
+A bug can only be removed by its author, or by one of the maintainers listed in the "git-bug.maintainers" configuration of the repository. The same policy is applied when merging the tombstones of a remote.
+
+The tombstone is signed with the signer configured with "git bug user signer", whose key must be registered on the identity removing the bug. The tombstones of a remote are only merged if signed by their author, so an unsigned removal only applies to the local repository.`,
+	Example: `git bug rm 2f1 --reason spam
+
+git config git-bug.maintainers "<identity id>,<identity id>"`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runRm,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(rmCmd)
+
+	rmCmd.Flags().SortFlags = false
+
+	rmCmd.Flags().StringVarP(&rmReason, "reason", "r", "",
+		"Record why the bug is removed")
+	rmCmd.Flags().BoolVarP(&rmForce, "force", "f", false,
+		"Don't ask for a confirmation")
+
+	addAsFlag(rmCmd)
+}
@@ -4,19 +4,44 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/i18n"
 )
 
 const rootCommandName = "git-bug"
 
+// if true, the user identity is created from the git config when missing,
+// without asking for a confirmation
+const autoIdentityConfigKey = "git-bug.auto-identity"
+
 // package scoped var to hold the repo after the PreRun execution
 var repo repository.ClockedRepo
 
+// package scoped var to hold the identity given with the --as flag, if any
+var asIdentity string
+
+// package scoped var to hold the --no-verify flag
+var noVerify bool
+
+// the language of the messages given with the --lang flag, if any
+var rootLang string
+
+// the color mode given with the --color flag
+var rootColor string
+
+// the prefix of the git config keys overriding the colors of the elements of
+// the output, as git-bug.color.status
+const colorConfigPrefix = "git-bug.color."
+
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Use:   rootCommandName,
@@ -27,6 +52,15 @@ git-bug use git objects to store the bug tracking separated from the files
 history. As bugs are regular git objects, they can be pushed and pulled from/to
 the same git remote your are already using to collaborate with other peoples.
 
+The colors of the output can be overridden in the git config, for the elements
+id, status, author, title, muted and warning, as git-bug.color.status, with up
+to two colors (the foreground then the background) and attributes among bold,
+dim, italic, ul, blink and reverse, as "bold green" or "normal black".
+
+In a terminal, the output of ls, show and events is sent to the pager
+configured for git, with GIT_PAGER, core.pager or PAGER, as git does. Use
+--no-pager, or an empty pager, to disable it.
+
 `,
 
 	// For the root command, force the execution of the PreRun
@@ -38,18 +72,72 @@ the same git remote your are already using to collaborate with other peoples.
 		}
 	},
 
+	// select the language of the messages and the colors before running any
+	// command
+	PersistentPreRunE: rootPreRun,
+
+	// push the changes if the automatic sync is enabled
+	PersistentPostRun: autoSyncAfter,
+
 	SilenceUsage:      true,
 	DisableAutoGenTag: true,
 
 	// Custom bash code to connect the git completion for "git bug" to the
-	// git-bug completion for "git-bug"
+	// git-bug completion for "git-bug", and to complete the labels with the
+	// ones already used, the most used first
 	BashCompletionFunction: `
 _git_bug() {
     __start_git-bug "$@"
 }
+
+__git-bug_get_labels() {
+    local labels
+    if labels=$(GIT_BUG_AUTO_SYNC=1 git-bug ls-label --usage 2>/dev/null | cut -f1); then
+        COMPREPLY=( $(compgen -W "${labels}" -- "$cur") )
+    fi
+}
+
+__git-bug_custom_func() {
+    case ${last_command} in
+        git-bug_label_add | git-bug_label_rm | git-bug_label_rename | git-bug_label_merge)
+            __git-bug_get_labels
+            return
+            ;;
+    esac
+}
 `,
 }
 
+func init() {
+	RootCmd.PersistentFlags().StringVar(&rootLang, "lang", "",
+		fmt.Sprintf("The language of the messages, as \"fr\". By default, the language is taken from the %s environment variable or the locale. Supported languages are %s",
+			i18n.LangEnv, strings.Join(i18n.Languages(), ", ")))
+	RootCmd.PersistentFlags().StringVar(&rootColor, "color", colors.ModeAuto,
+		fmt.Sprintf("Color the output: always, never or auto, for a terminal only and unless the %s environment variable is set",
+			colors.NoColorEnv))
+	RootCmd.PersistentFlags().BoolVar(&rootNoPager, "no-pager", false,
+		"Don't send the output of ls, show and events to the pager")
+}
+
+func rootPreRun(cmd *cobra.Command, args []string) error {
+	if err := setLanguage(cmd, args); err != nil {
+		return err
+	}
+
+	return colors.SetMode(rootColor)
+}
+
+// setLanguage select the language of the messages from the --lang flag, or
+// the environment. A detected language not supported falls back to English.
+func setLanguage(cmd *cobra.Command, args []string) error {
+	if rootLang != "" {
+		return i18n.SetLanguage(rootLang)
+	}
+
+	_ = i18n.SetLanguage(i18n.Detect())
+	return nil
+}
+
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -72,6 +160,37 @@ func loadRepo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := loadColorConfig(); err != nil {
+		return err
+	}
+
+	if !skipAutoSync(cmd) {
+		// working offline is fine, the command goes on with the local data
+		if err := autoSyncPullIfStale(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "automatic pull failed: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// loadColorConfig apply the overrides of the colors of the git config, the
+// local ones taking precedence over the global ones
+func loadColorConfig() error {
+	for _, config := range []repository.Config{repo.GlobalConfig(), repo.LocalConfig()} {
+		values, err := config.ReadAll(colorConfigPrefix)
+		if err != nil {
+			return err
+		}
+
+		for key, spec := range values {
+			err := colors.Override(strings.TrimPrefix(key, colorConfigPrefix), spec)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %v", key, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -84,10 +203,126 @@ func loadRepoEnsureUser(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	_, err = identity.GetUserIdentity(repo)
+	autoSyncPushNeeded = true
+
+	return ensureUser()
+}
+
+// ensureUser check that the operations can be authored by an identity,
+// creating the user identity if needed and allowed
+func ensureUser() error {
+	// the operations will be authored by another identity
+	if asIdentity != "" {
+		return nil
+	}
+
+	_, err := identity.GetUserIdentity(repo)
+	if err == identity.ErrNoIdentitySet {
+		return autoIdentity(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// autoIdentity create the user identity from the git config, if allowed by the
+// configuration or confirmed by the user. Otherwise, the original error is
+// returned.
+func autoIdentity(noIdentityErr error) error {
+	name, err := repo.GetUserName()
+	if err != nil || name == "" {
+		return noIdentityErr
+	}
+
+	email, err := repo.GetUserEmail()
+	if err != nil || email == "" {
+		return noIdentityErr
+	}
+
+	auto, err := readAutoIdentityConfig()
+	if err != nil {
+		return err
+	}
+
+	if !auto {
+		// no way to ask
+		if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+			return noIdentityErr
+		}
+
+		confirmed, err := promptYesNo(fmt.Sprintf(
+			"No identity is set. Create one as %s <%s> from the git config? [y/N]", name, email))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return noIdentityErr
+		}
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	id, err := backend.NewIdentityFromGitUser()
 	if err != nil {
 		return err
 	}
 
+	err = backend.SetUserIdentity(id)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Created the identity %s %s\n", id.Id().Human(), id.DisplayName())
+
+	return nil
+}
+
+// readAutoIdentityConfig read the auto identity setting from the repository
+// config first, then from the global git config
+func readAutoIdentityConfig() (bool, error) {
+	auto, err := repo.LocalConfig().ReadBool(autoIdentityConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		auto, err = repo.GlobalConfig().ReadBool(autoIdentityConfigKey)
+	}
+	if err == repository.ErrNoConfigEntry {
+		return false, nil
+	}
+	return auto, err
+}
+
+// addAsFlag add the --as flag to a command, to author its operations with a
+// given identity instead of the user identity
+func addAsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&asIdentity, "as", "",
+		"Author the changes with the given identity instead of the user identity")
+}
+
+// addNoVerifyFlag add the --no-verify flag to a command writing titles or
+// messages, to skip the content lint configured in the repository
+func addNoVerifyFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noVerify, "no-verify", false,
+		"Skip the content lint of the titles and the messages")
+}
+
+// useAsIdentity make the identity given with the --as flag, if any, the user
+// identity of the cache
+func useAsIdentity(backend *cache.RepoCache) error {
+	if asIdentity == "" {
+		return nil
+	}
+
+	i, err := backend.ResolveIdentityPrefix(asIdentity)
+	if err != nil {
+		return err
+	}
+
+	backend.UseIdentity(i)
+
 	return nil
 }
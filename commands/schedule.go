@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runSchedule(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	schedules, err := backend.Schedules()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, s := range schedules {
+		next := "never"
+		if t, ok := s.Next(now); ok {
+			next = t.Format("2006-01-02 15:04")
+		}
+
+		fmt.Printf("%s\t%s\t%s\t%s",
+			colors.Cyan(s.Name),
+			s.Cron,
+			next,
+			s.Title,
+		)
+		if len(s.Labels) > 0 {
+			fmt.Printf("\t[%s]", strings.Join(s.Labels, ", "))
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "List the schedules creating bugs periodically.",
+	Long: `List the schedules creating bugs periodically, with the next time they create a bug.
+
+The schedules are configured with git, with a cron expression made of the minute, the hour, the day of the month, the month and the day of the week, or a shortcut as @daily, @weekly or @monthly:
+
+  git config git-bug.schedules "credentials"
+  git config git-bug.schedule.credentials.cron "0 9 1 * *"
+  git config git-bug.schedule.credentials.title "Rotate the credentials"
+  git config git-bug.schedule.credentials.message "See the runbook."
+  git config git-bug.schedule.credentials.labels "chore"
+
+The bugs are created by "git bug schedule run" or "git bug daemon".`,
+	PreRunE: loadRepo,
+	RunE:    runSchedule,
+}
+
+func init() {
+	RootCmd.AddCommand(scheduleCmd)
+
+	scheduleCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runScheduleRun(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	return runSchedules(backend)
+}
+
+// runSchedules create the bugs of the schedules due now and report them
+func runSchedules(backend *cache.RepoCache) error {
+	created, err := backend.RunSchedules(time.Now())
+	for _, b := range created {
+		fmt.Printf("%s created: %s\n", colors.Cyan(b.Id().Human()), b.Snapshot().Title)
+	}
+	return err
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Create the bugs of the schedules due now.",
+	Long: `Create the bugs of the schedules due now.
+
+For each schedule, a bug is created for its last occurrence if it doesn't exist yet, in this repository or in a clone it has been pulled from. The missed occurrences before that are not caught up.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runScheduleRun,
+}
+
+func init() {
+	scheduleCmd.AddCommand(scheduleRunCmd)
+}
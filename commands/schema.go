@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/schema"
+)
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		for _, name := range schema.Names() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	s, err := schema.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(s)
+
+	return nil
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [<name>]",
+	Short: "Display the JSON Schema of a format.",
+	Long: `Display the JSON Schema of a format read or written by git-bug, or list the available schemas if none is given.
+
+The "snapshot" schema describe the output of "git bug show --json", the "operation" schema the operations output by "git bug events --json" and stored in git, and the "identity" schema the versions of the identities stored in git.
+
+The bugs and identities pulled from a remote are validated against those schemas before being merged.`,
+	Example: `git bug schema snapshot > snapshot.json`,
+	RunE:    runSchema,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	RootCmd.AddCommand(schemaCmd)
+}
@@ -8,19 +8,26 @@ import (
 	"path"
 
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/input"
 	"github.com/MichaelMure/git-bug/repository"
 )
 
 const selectFile = "select"
 
 var ErrNoValidId = errors.New("you must provide a bug id or use the \"select\" command first")
+var ErrNoBugPicked = errors.New("no bug picked")
+
+// the max number of bugs offered by PickBug for a search
+const pickLimit = 100
 
 // ResolveBug first try to resolve a bug using the first argument of the command
-// line. If it fails, it fallback to the select mechanism.
+// line. If it fails, it fallback to the select mechanism, then to picking the
+// bug interactively if the input is a terminal.
 //
 // Returns:
 // - the bug if any
@@ -52,7 +59,7 @@ func ResolveBug(repo *cache.RepoCache, args []string) (*cache.BugCache, []string
 		if err != nil {
 			return nil, nil, err
 		}
-		return nil, nil, ErrNoValidId
+		return pickOrFail(repo, args)
 	}
 
 	// another error when reading the bug
@@ -66,7 +73,54 @@ func ResolveBug(repo *cache.RepoCache, args []string) (*cache.BugCache, []string
 	}
 
 	// no selected bug and no valid first argument
-	return nil, nil, ErrNoValidId
+	return pickOrFail(repo, args)
+}
+
+// pickOrFail let the user pick the bug, if the input is a terminal
+func pickOrFail(repo *cache.RepoCache, args []string) (*cache.BugCache, []string, error) {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, nil, ErrNoValidId
+	}
+
+	b, err := PickBug(repo, "")
+	if err == ErrNoBugPicked || err == input.ErrNoTerminal {
+		return nil, nil, ErrNoValidId
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b, args, nil
+}
+
+// PickBug let the user choose a bug in the terminal with a fuzzy search on
+// the ids and the titles of the bugs, starting with query. ErrNoBugPicked is
+// returned if the user cancel.
+func PickBug(repo *cache.RepoCache, query string) (*cache.BugCache, error) {
+	search := func(query string) []input.PickerItem {
+		var items []input.PickerItem
+		for _, id := range repo.SearchBugs(query, pickLimit) {
+			excerpt, err := repo.ResolveBugExcerpt(id)
+			if err != nil {
+				continue
+			}
+			items = append(items, input.PickerItem{
+				Key:   id.String(),
+				Label: fmt.Sprintf("%s %-6s %s", id.Human(), excerpt.Status, excerpt.Title),
+			})
+		}
+		return items
+	}
+
+	key, err := input.Pick("bug: ", query, search)
+	if err == input.ErrPickCanceled {
+		return nil, ErrNoBugPicked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.ResolveBug(entity.Id(key))
 }
 
 // Select will select a bug for future use
@@ -124,6 +178,16 @@ func selected(repo *cache.RepoCache) (*cache.BugCache, error) {
 	}
 
 	b, err := repo.ResolveBug(id)
+	if err == bug.ErrBugNotExist {
+		// the selected bug has been removed
+		_ = f.Close()
+		err = os.Remove(selectPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "error while removing the select file of a missing bug")
+		}
+
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1,19 +1,29 @@
 package commands
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/numbering"
 	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/i18n"
 	"github.com/MichaelMure/git-bug/util/interrupt"
+	"github.com/MichaelMure/git-bug/util/timestamp"
 	"github.com/spf13/cobra"
 )
 
 var (
 	showFieldsQuery string
+	showFormat      string
+	showJson        bool
 )
 
 func runShowBug(cmd *cobra.Command, args []string) error {
@@ -31,78 +41,170 @@ func runShowBug(cmd *cobra.Command, args []string) error {
 
 	snapshot := b.Snapshot()
 
-	if len(snapshot.Comments) == 0 {
+	if len(snapshot.Comments) == 0 && !b.HasUnknownOperations() {
 		return errors.New("invalid bug: no comment")
 	}
 
-	firstComment := snapshot.Comments[0]
+	// the user has now seen the bug
+	isSet, err := backend.IsUserIdentitySet()
+	if err != nil {
+		return err
+	}
+	if isSet {
+		err = backend.MarkRead(b.Id())
+		if err != nil {
+			return err
+		}
+	}
+
+	format := showFormat
+	if showJson {
+		format = "json"
+	}
+	if format != "default" && format != "json" {
+		return fmt.Errorf("unknown format %s", format)
+	}
+
+	stopPager, err := startPager()
+	if err != nil {
+		return err
+	}
+	defer stopPager()
 
 	if showFieldsQuery != "" {
-		switch showFieldsQuery {
-		case "author":
-			fmt.Printf("%s\n", firstComment.Author.DisplayName())
-		case "authorEmail":
-			fmt.Printf("%s\n", firstComment.Author.Email())
-		case "createTime":
-			fmt.Printf("%s\n", firstComment.FormatTime())
-		case "humanId":
-			fmt.Printf("%s\n", snapshot.Id().Human())
-		case "id":
-			fmt.Printf("%s\n", snapshot.Id())
-		case "labels":
-			for _, l := range snapshot.Labels {
-				fmt.Printf("%s\n", l.String())
-			}
-		case "actors":
-			for _, a := range snapshot.Actors {
-				fmt.Printf("%s\n", a.DisplayName())
-			}
-		case "participants":
-			for _, p := range snapshot.Participants {
-				fmt.Printf("%s\n", p.DisplayName())
-			}
-		case "shortId":
-			fmt.Printf("%s\n", snapshot.Id().Human())
-		case "status":
-			fmt.Printf("%s\n", snapshot.Status)
-		case "title":
-			fmt.Printf("%s\n", snapshot.Title)
-		default:
-			return fmt.Errorf("\nUnsupported field: %s\n", showFieldsQuery)
+		value, err := showField(backend, snapshot, showFieldsQuery)
+		if err != nil {
+			return err
 		}
+		return printShowField(value, format)
+	}
 
+	if format == "json" {
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
 		return nil
 	}
 
+	// the creation of the bug is unknown if written by a newer version of
+	// git-bug
+	firstComment := showFirstComment(snapshot)
+
 	// Header
+	idFmt := colors.Id(snapshot.Id().Human())
+	if number, ok := backend.BugNumber(snapshot.Id()); ok {
+		idFmt += " " + colors.Id(numbering.Format(number))
+	}
+
 	fmt.Printf("[%s] %s %s\n\n",
-		colors.Yellow(snapshot.Status),
-		colors.Cyan(snapshot.Id().Human()),
+		colors.Status(snapshot.Status),
+		idFmt,
 		snapshot.Title,
 	)
 
-	fmt.Printf("%s opened this issue %s\n\n",
-		colors.Magenta(firstComment.Author.DisplayName()),
+	i18n.Printf("%s opened this issue %s\n\n",
+		colors.Author(firstComment.Author.DisplayName()),
 		firstComment.FormatTimeRel(),
 	)
 
+	if b.HasUnknownOperations() {
+		fmt.Printf("%s\n\n", colors.Warning(i18n.Sprintf("This bug has operations unknown to this version of git-bug, update git-bug to see and edit them.")))
+	}
+
 	// Labels
 	var labels = make([]string, len(snapshot.Labels))
 	for i := range snapshot.Labels {
 		labels[i] = string(snapshot.Labels[i])
 	}
 
-	fmt.Printf("labels: %s\n",
+	i18n.Printf("labels: %s\n",
 		strings.Join(labels, ", "),
 	)
 
+	if snapshot.Resolution != bug.NoResolution {
+		i18n.Printf("resolution: %s\n", snapshot.Resolution)
+	}
+
+	if snapshot.Component != "" {
+		i18n.Printf("component: %s\n", snapshot.Component)
+	}
+
+	if len(snapshot.Fields) > 0 {
+		i18n.Printf("fields: %s\n", strings.Join(formatFields(snapshot.Fields), ", "))
+	}
+
+	// Assignees
+	if len(snapshot.Assignees) > 0 {
+		var assignees = make([]string, len(snapshot.Assignees))
+		for i := range snapshot.Assignees {
+			assignees[i] = snapshot.Assignees[i].DisplayName()
+		}
+
+		i18n.Printf("assignees: %s\n",
+			strings.Join(assignees, ", "),
+		)
+	}
+
+	// Duplicates
+	if snapshot.DuplicateOf != "" {
+		i18n.Printf("duplicate of: %s\n", formatBugId(backend, snapshot.DuplicateOf))
+	}
+
+	duplicates := backend.DuplicatesOf(snapshot.Id())
+	if len(duplicates) > 0 {
+		var formatted = make([]string, len(duplicates))
+		for i, id := range duplicates {
+			formatted[i] = formatBugId(backend, id)
+		}
+
+		i18n.Printf("duplicates: %s\n",
+			strings.Join(formatted, ", "),
+		)
+	}
+
+	// Hierarchy
+	if snapshot.Parent != "" {
+		i18n.Printf("parent: %s\n", formatBugId(backend, snapshot.Parent))
+	}
+
+	excerpt, err := backend.ResolveBugExcerpt(snapshot.Id())
+	if err == nil && excerpt.Children > 0 {
+		i18n.Printf("sub-tasks: %d/%d done\n", excerpt.ChildrenClosed, excerpt.Children)
+		printChildren(backend, snapshot.Id(), "  ", map[entity.Id]bool{snapshot.Id(): true})
+	}
+
+	// Votes
+	if len(snapshot.Voters) > 0 {
+		i18n.Printf("votes: %d\n", len(snapshot.Voters))
+	}
+
+	// Reviews
+	if pending := snapshot.PendingReviewers(); len(pending) > 0 {
+		names := make([]string, len(pending))
+		for i, reviewer := range pending {
+			names[i] = reviewer.DisplayName()
+		}
+		i18n.Printf("waiting for: %s\n", strings.Join(names, ", "))
+	}
+
+	// Checklist
+	if len(snapshot.Checklist) > 0 {
+		checked, total := snapshot.ChecklistProgress()
+		i18n.Printf("checklist: %d/%d done\n", checked, total)
+		for i, item := range snapshot.Checklist {
+			fmt.Printf("  %s\n", formatChecklistItem(i, item))
+		}
+	}
+
 	// Actors
 	var actors = make([]string, len(snapshot.Actors))
 	for i := range snapshot.Actors {
 		actors[i] = snapshot.Actors[i].DisplayName()
 	}
 
-	fmt.Printf("actors: %s\n",
+	i18n.Printf("actors: %s\n",
 		strings.Join(actors, ", "),
 	)
 
@@ -112,40 +214,257 @@ func runShowBug(cmd *cobra.Command, args []string) error {
 		participants[i] = snapshot.Participants[i].DisplayName()
 	}
 
-	fmt.Printf("participants: %s\n\n",
+	i18n.Printf("participants: %s\n\n",
 		strings.Join(participants, ", "),
 	)
 
-	// Comments
-	indent := "  "
+	// References to other repositories
+	references := snapshot.References()
+	if len(references) > 0 {
+		i18n.Println("references:")
+		for _, ref := range references {
+			fmt.Printf("  %s\n", formatReference(backend, ref))
+		}
+		fmt.Println()
+	}
 
+	// Comments, replies under the comment they reply to
+	index := make(map[entity.Id]int, len(snapshot.Comments))
 	for i, comment := range snapshot.Comments {
-		var message string
-		fmt.Printf("%s#%d %s <%s>\n\n",
-			indent,
-			i,
-			comment.Author.DisplayName(),
-			comment.Author.Email(),
-		)
+		index[comment.Id()] = i
+	}
+
+	var printThreads func(threads []*bug.CommentThread, depth int)
+	printThreads = func(threads []*bug.CommentThread, depth int) {
+		indent := strings.Repeat("  ", depth+1)
+
+		for _, thread := range threads {
+			comment := thread.Comment
 
-		if comment.Message == "" {
-			message = colors.GreyBold("No description provided.")
-		} else {
-			message = comment.Message
+			var reply string
+			if depth > 0 {
+				reply = i18n.Sprintf(" in reply to #%d", index[comment.ReplyTo])
+			}
+
+			fmt.Printf("%s#%d %s %s <%s>%s\n\n",
+				indent,
+				index[comment.Id()],
+				colors.Id(comment.Id().Human()),
+				comment.Author.DisplayName(),
+				comment.Author.Email(),
+				reply,
+			)
+
+			var message string
+			if comment.Message == "" {
+				message = colors.Muted(i18n.Sprintf("No description provided."))
+			} else {
+				message = strings.Replace(comment.Message, "\n", "\n"+indent, -1)
+			}
+
+			fmt.Printf("%s%s\n\n\n",
+				indent,
+				message,
+			)
+
+			printThreads(thread.Replies, depth+1)
 		}
+	}
 
-		fmt.Printf("%s%s\n\n\n",
-			indent,
-			message,
-		)
+	printThreads(snapshot.Threads(), 0)
+
+	return nil
+}
+
+// the fields of a bug that can be selected with --field
+var showFields = []string{
+	"author", "authorEmail", "createTime", "editTime", "humanId", "id", "number",
+	"labels", "shortId", "status", "title", "description", "comments",
+	"actors", "participants", "component", "assignees", "resolution",
+	"parent", "duplicateOf", "checklist", "votes", "fields",
+}
+
+// showFirstComment return the description of the bug. The creation of the bug
+// is unknown if written by a newer version of git-bug
+func showFirstComment(snapshot *bug.Snapshot) bug.Comment {
+	if len(snapshot.Comments) > 0 {
+		return snapshot.Comments[0]
+	}
+	return bug.Comment{
+		Author:   snapshot.Author,
+		UnixTime: timestamp.Timestamp(snapshot.CreatedAt.Unix()),
+	}
+}
+
+// showField return the value of a field of a bug, a string, a list of strings
+// or a number
+func showField(backend *cache.RepoCache, snapshot *bug.Snapshot, field string) (interface{}, error) {
+	firstComment := showFirstComment(snapshot)
+
+	switch field {
+	case "author":
+		return firstComment.Author.DisplayName(), nil
+	case "authorEmail":
+		return firstComment.Author.Email(), nil
+	case "createTime":
+		return firstComment.FormatTime(), nil
+	case "editTime":
+		return snapshot.LastEditTime().Format("Mon Jan 2 15:04:05 2006 -0700"), nil
+	case "humanId", "shortId":
+		return snapshot.Id().Human(), nil
+	case "id":
+		return snapshot.Id().String(), nil
+	case "number":
+		// a bug gets a number when pushed, if the numbering is enabled
+		number, ok := backend.BugNumber(snapshot.Id())
+		if !ok {
+			return "", nil
+		}
+		return number, nil
+	case "title":
+		return snapshot.Title, nil
+	case "description":
+		return firstComment.Message, nil
+	case "comments":
+		return len(snapshot.Comments) - 1, nil
+	case "status":
+		return snapshot.Status.String(), nil
+	case "resolution":
+		return snapshot.Resolution.String(), nil
+	case "component":
+		return snapshot.Component, nil
+	case "parent":
+		return snapshot.Parent.String(), nil
+	case "duplicateOf":
+		return snapshot.DuplicateOf.String(), nil
+	case "votes":
+		return len(snapshot.Voters), nil
+	case "labels":
+		result := make([]string, len(snapshot.Labels))
+		for i, l := range snapshot.Labels {
+			result[i] = l.String()
+		}
+		return result, nil
+	case "assignees":
+		return displayNames(snapshot.Assignees), nil
+	case "actors":
+		return displayNames(snapshot.Actors), nil
+	case "participants":
+		return displayNames(snapshot.Participants), nil
+	case "fields":
+		return formatFields(snapshot.Fields), nil
+	case "checklist":
+		result := make([]string, len(snapshot.Checklist))
+		for i, item := range snapshot.Checklist {
+			result[i] = formatChecklistItem(i, item)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported field %s, valid fields are %s",
+			field, strings.Join(showFields, ", "))
+	}
+}
+
+// printShowField print the value of a field, a line per item of a list in the
+// default format, or as JSON
+func printShowField(value interface{}, format string) error {
+	if format == "json" {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	switch value := value.(type) {
+	case []string:
+		for _, item := range value {
+			fmt.Println(item)
+		}
+	case string:
+		// an empty value prints nothing, as an unset parent
+		if value != "" {
+			fmt.Println(value)
+		}
+	default:
+		fmt.Println(value)
 	}
 
 	return nil
 }
 
+func displayNames(identities []identity.Interface) []string {
+	result := make([]string, len(identities))
+	for i, id := range identities {
+		result[i] = id.DisplayName()
+	}
+	return result
+}
+
+// formatBugId describe a local bug with its id and title, if available
+func formatBugId(backend *cache.RepoCache, id entity.Id) string {
+	excerpt, err := backend.ResolveBugExcerpt(id)
+	if err != nil {
+		return fmt.Sprintf("%s %s", colors.Id(id.Human()), colors.Muted("(unavailable)"))
+	}
+
+	return fmt.Sprintf("%s %s", colors.Id(excerpt.Id.Human()), excerpt.Title)
+}
+
+// printChildren print the tree of the sub-tasks of a bug, with their status
+func printChildren(backend *cache.RepoCache, id entity.Id, indent string, seen map[entity.Id]bool) {
+	for _, child := range backend.ChildrenOf(id) {
+		// a cycle can be made by concurrent edits
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+
+		excerpt, err := backend.ResolveBugExcerpt(child)
+		if err != nil {
+			continue
+		}
+
+		fmt.Printf("%s[%s] %s\n", indent, colors.Status(excerpt.Status), formatBugId(backend, child))
+		printChildren(backend, child, indent+"  ", seen)
+	}
+}
+
+// formatReference describe a reference to another repository's bug, with
+// its title and status if that bug is available locally
+func formatReference(backend *cache.RepoCache, ref bug.Reference) string {
+	resolved, err := backend.ResolveReference(ref)
+	if err != nil {
+		return fmt.Sprintf("%s %s", ref, colors.Muted("(unavailable)"))
+	}
+
+	return fmt.Sprintf("%s [%s] %s %s",
+		ref,
+		colors.Status(resolved.Excerpt.Status),
+		colors.Id(resolved.Excerpt.Id.Human()),
+		resolved.Excerpt.Title,
+	)
+}
+
+// formatFields format the custom fields of a bug as name=value, sorted by name
+func formatFields(fields map[string]string) []string {
+	result := make([]string, 0, len(fields))
+	for name, value := range fields {
+		result = append(result, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(result)
+	return result
+}
+
 var showCmd = &cobra.Command{
-	Use:     "show [<id>]",
-	Short:   "Display the details of a bug.",
+	Use:   "show [<id>]",
+	Short: "Display the details of a bug.",
+	Long: `Display the details of a bug.
+
+A bug of another repository can be referenced in a comment with the remote URL of that repository, followed by '#' and the id of the bug. Such a bug is displayed if that repository is a remote of this one, or a local clone declared with "git config git-bug.linked-repository.<name> <path>".
+
+A single field can be selected with --field, to use it in a script without parsing the output, as "git bug show --field status". A field with several values, as the labels, is output a value per line, or as a JSON array with --format json.`,
 	PreRunE: loadRepo,
 	RunE:    runShowBug,
 }
@@ -153,5 +472,9 @@ var showCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(showCmd)
 	showCmd.Flags().StringVarP(&showFieldsQuery, "field", "f", "",
-		"Select field to display. Valid values are [author,authorEmail,createTime,humanId,id,labels,shortId,status,title,actors,participants]")
+		"Select field to display. Valid values are ["+strings.Join(showFields, ",")+"]")
+	showCmd.Flags().StringVar(&showFormat, "format", "default",
+		"Format of the output. Valid values are [default,json]. A bug in JSON has the format described by \"git bug schema snapshot\", a field is a JSON string, number or array")
+	showCmd.Flags().BoolVar(&showJson, "json", false,
+		"Output as JSON, as --format json")
 }
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	staleDryRun bool
+)
+
+func runStale(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	changes, err := backend.RunStale(time.Now(), staleDryRun)
+
+	for _, change := range changes {
+		var action string
+		switch change.Action {
+		case cache.StaleMarked:
+			action = "marked as stale"
+		case cache.StaleClosed:
+			action = "closed"
+		case cache.StaleCleared:
+			action = "not stale anymore"
+		}
+		fmt.Printf("%s %s: %s\n", colors.Cyan(change.Id.Human()), action, change.Title)
+	}
+
+	return err
+}
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "Label and close the bugs without activity.",
+	Long: `Label the open bugs without activity for a number of days as stale, with a comment, and optionally close them if nothing happen after.
+
+The label of a stale bug with a new activity is removed. This is meant to be run periodically, from cron or a CI job, and configured with git:
+
+  git config git-bug.stale.days 60
+  git config git-bug.stale.close-days 7
+  git config git-bug.stale.label "stale"
+  git config git-bug.stale.exempt-labels "pinned security"
+  git config git-bug.stale.message "This bug had no activity for 60 days."
+  git config git-bug.stale.close-message "Closed for lack of activity."
+
+Only "git-bug.stale.days" is required. Without "git-bug.stale.close-days", the stale bugs are never closed.`,
+	PreRunE: loadRepo,
+	RunE:    runStale,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(staleCmd)
+
+	staleCmd.Flags().SortFlags = false
+
+	staleCmd.Flags().BoolVarP(&staleDryRun, "dry-run", "n", false,
+		"Only display the changes, without doing them")
+}
@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:     "stats",
+	Short:   "Show statistics about the bugs.",
+	PreRunE: loadRepo,
+	RunE:    runStatsReopened,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().SortFlags = false
+
+	statsCmd.Flags().IntVarP(&statsReopenedMinimum, "minimum", "m", 2,
+		"Only show the bugs reopened at least this number of times")
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+
+	text "github.com/MichaelMure/go-term-text"
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	statsReopenedMinimum int
+)
+
+func runStatsReopened(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	for _, id := range backend.FrequentlyReopened(statsReopenedMinimum) {
+		excerpt, err := backend.ResolveBugExcerpt(id)
+		if err != nil {
+			return err
+		}
+
+		titleFmt := text.LeftPadMaxLine(excerpt.Title, 50, 0)
+
+		fmt.Printf("%s %s\t%s\treopened %d times\n",
+			colors.Cyan(excerpt.Id.Human()),
+			colors.Yellow(excerpt.Status),
+			titleFmt,
+			excerpt.ReopenCount,
+		)
+	}
+
+	return nil
+}
+
+var statsReopenedCmd = &cobra.Command{
+	Use:   "reopened",
+	Short: "List the bugs that have been reopened the most.",
+	Long: `List the bugs that have been reopened the most, which often point to recurring regressions.
+
+The bugs are ordered by number of reopening. The same bugs can be queried with the "reopened" qualifier, as in "git bug ls reopened:>1".`,
+	PreRunE: loadRepo,
+	RunE:    runStatsReopened,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	statsCmd.AddCommand(statsReopenedCmd)
+
+	statsReopenedCmd.Flags().SortFlags = false
+
+	statsReopenedCmd.Flags().IntVarP(&statsReopenedMinimum, "minimum", "m", 2,
+		"Only show the bugs reopened at least this number of times")
+}
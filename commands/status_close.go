@@ -1,12 +1,18 @@
 package commands
 
 import (
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/commands/select"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 	"github.com/spf13/cobra"
 )
 
+var (
+	closeReason  string
+	closeMessage string
+)
+
 func runStatusClose(cmd *cobra.Command, args []string) error {
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
@@ -15,26 +21,60 @@ func runStatusClose(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	if noVerify {
+		backend.DisableLint()
+	}
+
+	resolution := bug.NoResolution
+	if closeReason != "" {
+		resolution, err = bug.ResolutionFromString(closeReason)
+		if err != nil {
+			return err
+		}
+	}
+
 	b, args, err := _select.ResolveBug(backend, args)
 	if err != nil {
 		return err
 	}
 
-	_, err = b.Close()
+	_, err = b.CloseWithResolution(resolution)
 	if err != nil {
 		return err
 	}
 
+	if closeMessage != "" {
+		_, err = b.AddComment(closeMessage)
+		if err != nil {
+			return err
+		}
+	}
+
 	return b.Commit()
 }
 
 var closeCmd = &cobra.Command{
 	Use:     "close [<id>]",
 	Short:   "Mark a bug as closed.",
+	Example: `git bug status close --reason wontfix`,
 	PreRunE: loadRepoEnsureUser,
 	RunE:    runStatusClose,
 }
 
 func init() {
 	statusCmd.AddCommand(closeCmd)
+
+	closeCmd.Flags().SortFlags = false
+
+	closeCmd.Flags().StringVarP(&closeReason, "reason", "r", "",
+		"Tell why the bug is closed. Valid values are [fixed,wontfix,duplicate,invalid,works-for-me]")
+	closeCmd.Flags().StringVarP(&closeMessage, "message", "m", "",
+		"Add a comment along the closing")
+	addAsFlag(closeCmd)
+	addNoVerifyFlag(closeCmd)
 }
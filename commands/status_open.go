@@ -7,6 +7,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	openMessage string
+)
+
 func runStatusOpen(cmd *cobra.Command, args []string) error {
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
@@ -15,6 +19,15 @@ func runStatusOpen(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	if noVerify {
+		backend.DisableLint()
+	}
+
 	b, args, err := _select.ResolveBug(backend, args)
 	if err != nil {
 		return err
@@ -25,6 +38,13 @@ func runStatusOpen(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if openMessage != "" {
+		_, err = b.AddComment(openMessage)
+		if err != nil {
+			return err
+		}
+	}
+
 	return b.Commit()
 }
 
@@ -37,4 +57,11 @@ var openCmd = &cobra.Command{
 
 func init() {
 	statusCmd.AddCommand(openCmd)
+
+	openCmd.Flags().SortFlags = false
+
+	openCmd.Flags().StringVarP(&openMessage, "message", "m", "",
+		"Add a comment telling why the bug is open again")
+	addAsFlag(openCmd)
+	addNoVerifyFlag(openCmd)
 }
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return errors.New("Only syncing with one remote at a time is supported")
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	remote, err := backend.DefaultRemote()
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	// the remote changes are merged first, for the push to not be rejected
+	_, err = backend.Fetch(remote)
+	if err != nil {
+		return err
+	}
+
+	err = mergeAndReport(backend, remote, false)
+	if err != nil {
+		return err
+	}
+
+	outbox, err := backend.Outbox(remote)
+	if err != nil {
+		return err
+	}
+
+	// the tombstones, identities, blocklist and numbers have no outbox, so
+	// the push always happen
+	_, err = backend.Push(remote)
+	if err != nil {
+		return err
+	}
+
+	if len(outbox) == 0 {
+		fmt.Println("No bug operation to publish.")
+		return nil
+	}
+
+	operations := 0
+	for _, entry := range outbox {
+		operations += len(entry.Operations)
+	}
+
+	fmt.Printf("Published %d %s on %d %s.\n",
+		operations, plural(operations, "operation", "operations"),
+		len(outbox), plural(len(outbox), "bug", "bugs"))
+
+	return nil
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [<remote>]",
+	Short: "Pull the remote changes and publish the local ones.",
+	Long: `Pull and merge the bugs of a remote, then push the local changes.
+
+Both the merged and the published changes are summarized: the published operations of the bugs are the ones listed by "git bug outbox". The tombstones, identities, blocklist and bug numbers are pushed as well.`,
+	PreRunE: loadRepo,
+	RunE:    runSync,
+}
+
+func init() {
+	RootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().SortFlags = false
+}
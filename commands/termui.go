@@ -1,12 +1,16 @@
 package commands
 
 import (
+	"os"
+
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/termui"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 	"github.com/spf13/cobra"
 )
 
+var termUIAccessible bool
+
 func runTermUI(cmd *cobra.Command, args []string) error {
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
@@ -15,6 +19,10 @@ func runTermUI(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	if termUIAccessible || os.Getenv(termui.AccessibleEnv) != "" {
+		return termui.RunAccessible(backend, os.Stdin, os.Stdout)
+	}
+
 	return termui.Run(backend)
 }
 
@@ -22,10 +30,18 @@ var termUICmd = &cobra.Command{
 	Use:     "termui",
 	Aliases: []string{"tui"},
 	Short:   "Launch the terminal UI.",
+	Long: `Launch the terminal UI.
+
+With --accessible, or with the GIT_BUG_ACCESSIBLE environment variable set, the terminal UI is a linear reading of the bugs for the screen readers: commands are typed line by line, and the answers are plain text without box drawing, colors or screen redraws. Type help for the commands.`,
 	PreRunE: loadRepoEnsureUser,
 	RunE:    runTermUI,
 }
 
 func init() {
 	RootCmd.AddCommand(termUICmd)
+
+	termUICmd.Flags().SortFlags = false
+
+	termUICmd.Flags().BoolVar(&termUIAccessible, "accessible", false,
+		"Use the accessible mode, for the screen readers")
 }
@@ -1,11 +1,10 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/commands/select"
 	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/util/i18n"
 	"github.com/MichaelMure/git-bug/util/interrupt"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +21,15 @@ func runTitleEdit(cmd *cobra.Command, args []string) error {
 	defer backend.Close()
 	interrupt.RegisterCleaner(backend.Close)
 
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	if noVerify {
+		backend.DisableLint()
+	}
+
 	b, args, err := _select.ResolveBug(backend, args)
 	if err != nil {
 		return err
@@ -32,7 +40,7 @@ func runTitleEdit(cmd *cobra.Command, args []string) error {
 	if titleEditTitle == "" {
 		titleEditTitle, err = input.BugTitleEditorInput(repo, snap.Title)
 		if err == input.ErrEmptyTitle {
-			fmt.Println("Empty title, aborting.")
+			i18n.Println("Empty title, aborting.")
 			return nil
 		}
 		if err != nil {
@@ -41,7 +49,7 @@ func runTitleEdit(cmd *cobra.Command, args []string) error {
 	}
 
 	if titleEditTitle == snap.Title {
-		fmt.Println("No change, aborting.")
+		i18n.Println("No change, aborting.")
 	}
 
 	_, err = b.SetTitle(titleEditTitle)
@@ -67,4 +75,7 @@ func init() {
 	titleEditCmd.Flags().StringVarP(&titleEditTitle, "title", "t", "",
 		"Provide a title to describe the issue",
 	)
+
+	addAsFlag(titleEditCmd)
+	addNoVerifyFlag(titleEditCmd)
 }
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage the API tokens of the web UI server.",
+	Long: `Manage the API tokens of the web UI server.
+
+When the web UI server listen on an address reachable from other machines, an API token is required to modify the repository through the GraphQL API. The token is given in the "Authorization: Bearer <token>" header of the requests.
+
+Only a hash of the tokens is stored, in the git config of the repository.`,
+	PreRunE: loadRepo,
+	RunE:    runTokenLs,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(tokenCmd)
+
+	tokenCmd.Flags().SortFlags = false
+}
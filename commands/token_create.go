@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/graphql/auth"
+)
+
+var (
+	tokenCreateName string
+)
+
+func runTokenCreate(cmd *cobra.Command, args []string) error {
+	token, value, err := auth.Create(repo, tokenCreateName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("token %s created\n", token.Id.Human())
+	fmt.Println("This is the only time the token value is displayed, store it safely:")
+	fmt.Println(value)
+
+	return nil
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a new API token.",
+	PreRunE: loadRepo,
+	RunE:    runTokenCreate,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenCreateCmd)
+
+	tokenCreateCmd.Flags().SortFlags = false
+
+	tokenCreateCmd.Flags().StringVarP(&tokenCreateName, "name", "n", "",
+		"A name to remember what the token is used for",
+	)
+}
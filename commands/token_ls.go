@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/graphql/auth"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+func runTokenLs(cmd *cobra.Command, args []string) error {
+	tokens, err := auth.List(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		fmt.Printf("%s %s %s\n",
+			colors.Cyan(token.Id.Human()),
+			token.CreateTime.Format("2006-01-02 15:04"),
+			token.Name,
+		)
+	}
+
+	return nil
+}
+
+var tokenLsCmd = &cobra.Command{
+	Use:     "ls",
+	Short:   "List the API tokens.",
+	PreRunE: loadRepo,
+	RunE:    runTokenLs,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenLsCmd)
+
+	tokenLsCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/graphql/auth"
+)
+
+func runTokenRevoke(cmd *cobra.Command, args []string) error {
+	token, err := auth.LoadWithPrefix(repo, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = auth.Revoke(repo, token.Id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("token %s revoked\n", token.Id.Human())
+	return nil
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:     "revoke <id>",
+	Short:   "Revoke an API token.",
+	PreRunE: loadRepo,
+	RunE:    runTokenRevoke,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenRevokeCmd)
+}
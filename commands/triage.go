@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+// the bugs waiting for a triage, oldest first
+const triageQuery = "status:open no:label no:assignee sort:creation-asc"
+
+// maximum number of lines of the description displayed
+const triageMessageLines = 10
+
+func runTriage(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	query, err := cache.ParseQuery(triageQuery)
+	if err != nil {
+		return err
+	}
+
+	ids := backend.QueryBugs(query)
+
+	if len(ids) == 0 {
+		fmt.Println("No bug to triage")
+		return nil
+	}
+
+	triaged := 0
+
+	err = backend.Transaction(func() error {
+		for i, id := range ids {
+			b, err := backend.ResolveBug(id)
+			if err != nil {
+				return err
+			}
+
+			// a bug edited with a newer version of git-bug can't be triaged
+			if b.HasUnknownOperations() {
+				continue
+			}
+
+			fmt.Printf("\n(%d/%d) ", i+1, len(ids))
+			printTriageBug(b.Snapshot())
+
+			done, quit, err := triageBug(backend, b)
+			if err != nil {
+				return err
+			}
+
+			err = b.CommitAsNeeded()
+			if err != nil {
+				return err
+			}
+
+			if done {
+				triaged++
+			}
+			if quit {
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d bug(s) triaged\n", triaged)
+
+	return nil
+}
+
+func printTriageBug(snap *bug.Snapshot) {
+	fmt.Printf("%s %s\n", colors.Cyan(snap.Id().Human()), snap.Title)
+
+	first := snap.Comments[0]
+	fmt.Printf("%s opened this issue %s\n\n",
+		colors.Magenta(first.Author.DisplayName()),
+		first.FormatTimeRel(),
+	)
+
+	message := first.Message
+	if message == "" {
+		message = colors.GreyBold("No description provided.")
+	}
+
+	lines := strings.Split(message, "\n")
+	if len(lines) > triageMessageLines {
+		lines = append(lines[:triageMessageLines], colors.GreyBold("[...]"))
+	}
+
+	for _, line := range lines {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+}
+
+// triageBug apply the actions chosen by the user on a bug, until moving to
+// the next one. It tell if the bug has been triaged and if the user want to
+// stop.
+func triageBug(backend *cache.RepoCache, b *cache.BugCache) (done bool, quit bool, err error) {
+	for {
+		key, err := input.PromptKey("[l]abel, [a]ssign, [c]lose, [s]kip, [q]uit", "lacsq")
+		if err != nil {
+			return done, false, err
+		}
+
+		switch key {
+		case 'l':
+			labels, err := input.Prompt("Labels, separated by spaces", "labels")
+			if err != nil {
+				return done, false, err
+			}
+
+			changes, _, err := b.ChangeLabels(strings.Fields(labels), nil)
+			for _, change := range changes {
+				fmt.Println(change)
+			}
+			if err != nil {
+				fmt.Println(colors.Red(err.Error()))
+				continue
+			}
+			done = true
+
+		case 'a':
+			prefixes, err := input.Prompt("Identities to assign, separated by spaces", "identities")
+			if err != nil {
+				return done, false, err
+			}
+
+			assignees, err := resolveIdentities(backend, strings.Fields(prefixes))
+			if err == nil {
+				_, err = b.ChangeAssignees(assignees, nil)
+			}
+			if err != nil {
+				fmt.Println(colors.Red(err.Error()))
+				continue
+			}
+			for _, assignee := range assignees {
+				fmt.Printf("assigned to %s\n", assignee.DisplayName())
+			}
+			done = true
+
+		case 'c':
+			_, err := b.Close()
+			if err != nil {
+				return done, false, err
+			}
+			fmt.Println("bug closed")
+			return true, false, nil
+
+		case 's':
+			return done, false, nil
+
+		case 'q':
+			return done, true, nil
+		}
+	}
+}
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Triage interactively the bugs without label nor assignee.",
+	Long: `Triage interactively the bugs without label nor assignee.
+
+The open bugs without label nor assignee are presented one at a time, oldest first. A single key label, assign or close the bug, or skip to the next one. The changes are written once moving to the next bug.`,
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runTriage,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(triageCmd)
+
+	triageCmd.Flags().SortFlags = false
+
+	addAsFlag(triageCmd)
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/MichaelMure/git-bug/util/interrupt"
 )
 
+var (
+	userAdoptGlobal bool
+)
+
 func runUserAdopt(cmd *cobra.Command, args []string) error {
 	backend, err := cache.NewRepoCache(repo)
 	if err != nil {
@@ -25,6 +29,17 @@ func runUserAdopt(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if userAdoptGlobal {
+		err = backend.SetGlobalUserIdentity(i)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "Your default identity is now: %s\n", i.DisplayName())
+
+		return nil
+	}
+
 	err = backend.SetUserIdentity(i)
 	if err != nil {
 		return err
@@ -36,8 +51,13 @@ func runUserAdopt(cmd *cobra.Command, args []string) error {
 }
 
 var userAdoptCmd = &cobra.Command{
-	Use:     "adopt <user-id>",
-	Short:   "Adopt an existing identity as your own.",
+	Use:   "adopt <user-id>",
+	Short: "Adopt an existing identity as your own.",
+	Long: `Adopt an existing identity as your own.
+
+The identity is adopted for the current repository only. With --global, it become the default identity of all the repositories where it exist and where no identity has been adopted.
+
+To author a single change with another identity, use the --as flag of the command.`,
 	PreRunE: loadRepo,
 	RunE:    runUserAdopt,
 	Args:    cobra.ExactArgs(1),
@@ -46,4 +66,7 @@ var userAdoptCmd = &cobra.Command{
 func init() {
 	userCmd.AddCommand(userAdoptCmd)
 	userAdoptCmd.Flags().SortFlags = false
+
+	userAdoptCmd.Flags().BoolVarP(&userAdoptGlobal, "global", "g", false,
+		"Adopt the identity as the default for all the repositories")
 }
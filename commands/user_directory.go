@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runUserDirectory(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	if len(args) == 1 {
+		return backend.SetIdentityDirectory(args[0])
+	}
+
+	remote, err := backend.IdentityDirectory()
+	if err != nil {
+		return err
+	}
+
+	if remote == "" {
+		fmt.Println("No identity directory configured")
+		return nil
+	}
+
+	fmt.Println(remote)
+
+	return nil
+}
+
+var userDirectoryCmd = &cobra.Command{
+	Use:   "directory [<remote>]",
+	Short: "Display or set the git remote used as the identity directory.",
+	Long: `Display or set the git remote used as the identity directory.
+
+An identity directory is a git repository holding only identities, maintained by an organization and shared by all its repositories. Once configured, the identities are pulled from it by "git bug pull" in addition to the regular remote, and "git bug user pull" and "git bug user push" use it by default.`,
+	Example: `git remote add identities git@example.com:org/identities.git
+git bug user directory identities
+git bug user pull`,
+	PreRunE: loadRepo,
+	RunE:    runUserDirectory,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	userCmd.AddCommand(userDirectoryCmd)
+	userDirectoryCmd.Flags().SortFlags = false
+}
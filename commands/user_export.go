@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	userExportAll    bool
+	userExportOutput string
+)
+
+func runUserExport(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	var ids []entity.Id
+
+	switch {
+	case userExportAll:
+		ids = backend.AllIdentityIds()
+	case len(args) > 0:
+		for _, prefix := range args {
+			i, err := backend.ResolveIdentityPrefix(prefix)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, i.Id())
+		}
+	default:
+		i, err := backend.GetUserIdentity()
+		if err != nil {
+			return err
+		}
+		ids = append(ids, i.Id())
+	}
+
+	bundle, err := backend.ExportIdentities(ids)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if userExportOutput == "" || userExportOutput == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	err = ioutil.WriteFile(userExportOutput, append(data, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "%d identities exported to %s\n", len(ids), userExportOutput)
+
+	return nil
+}
+
+var userExportCmd = &cobra.Command{
+	Use:   "export [<user-id>...]",
+	Short: "Export identities in a portable JSON bundle.",
+	Long: `Export identities in a portable JSON bundle.
+
+The bundle holds the full history of the identities, including their keys, and can be imported in another repository with "git bug user import", to move an identity to a new machine or to distribute the identities of a team without a common git remote. The identities keep their id once imported.
+
+Without argument, your identity is exported.`,
+	Example: `git bug user export -o me.json
+git bug user export --all > team.json`,
+	PreRunE: loadRepo,
+	RunE:    runUserExport,
+}
+
+func init() {
+	userCmd.AddCommand(userExportCmd)
+	userExportCmd.Flags().SortFlags = false
+
+	userExportCmd.Flags().BoolVarP(&userExportAll, "all", "a", false,
+		"Export all the identities of the repository")
+	userExportCmd.Flags().StringVarP(&userExportOutput, "output", "o", "",
+		"Write the bundle to a file instead of the standard output")
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runUserImport(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+
+	if len(args) == 0 || args[0] == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	var bundle identity.Bundle
+	err = json.Unmarshal(data, &bundle)
+	if err != nil {
+		return fmt.Errorf("invalid identity bundle: %v", err)
+	}
+
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	results, err := backend.ImportIdentities(&bundle)
+
+	invalid := 0
+	for _, result := range results {
+		if result.Status == entity.MergeStatusInvalid {
+			invalid++
+		}
+
+		name := result.Id.Human()
+		if result.Entity != nil {
+			name = fmt.Sprintf("%s %s", name, result.Entity.(*identity.Identity).DisplayName())
+		}
+		fmt.Printf("%s: %s\n", name, result)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d identities could not be imported", invalid)
+	}
+
+	return nil
+}
+
+var userImportCmd = &cobra.Command{
+	Use:   "import [<file>]",
+	Short: "Import identities from a JSON bundle.",
+	Long: `Import identities from a JSON bundle created with "git bug user export".
+
+Without file, or with "-", the bundle is read from the standard input. As when pulling from a remote, an identity already known is only updated with the newer versions of the bundle.
+
+To use an imported identity as your own, adopt it with "git bug user adopt".`,
+	Example: `git bug user import me.json
+git bug user adopt <user-id>`,
+	PreRunE: loadRepo,
+	RunE:    runUserImport,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	userCmd.AddCommand(userImportCmd)
+	userImportCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/pairing"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	userPairHost           string
+	userPairPort           int
+	userPairTimeout        time.Duration
+	userPairWithSigningKey bool
+)
+
+// the git config holding the reference of the key signing the commits
+const signingKeyConfigKey = "user.signingkey"
+
+func runUserPair(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	if len(args) == 1 {
+		return joinUserPair(backend, args[0])
+	}
+
+	return offerUserPair(backend)
+}
+
+// offerUserPair offer the user identity to the first machine joining with the
+// pairing code
+func offerUserPair(backend *cache.RepoCache) error {
+	user, err := backend.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := backend.ExportIdentities([]entity.Id{user.Id()})
+	if err != nil {
+		return err
+	}
+
+	payload := &pairing.Payload{
+		Bundle: bundle,
+		UserId: user.Id(),
+	}
+
+	if userPairWithSigningKey {
+		payload.SigningKey, err = readSigningKey()
+		if err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(userPairPort)))
+	if err != nil {
+		return err
+	}
+
+	host := userPairHost
+	if host == "" {
+		host = pairingHost()
+	}
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return err
+	}
+
+	code, err := pairing.NewCode(net.JoinHostPort(host, port))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pairing code for %s, valid for %s:\n\n", user.DisplayName(), userPairTimeout)
+	fmt.Printf("    %s\n\n", code)
+	fmt.Println("On the other machine, in the repository, run:")
+	fmt.Printf("\n    git bug user pair %s\n\n", code)
+	fmt.Println("Waiting for the other machine...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), userPairTimeout)
+	defer cancel()
+
+	peer, err := pairing.Offer(ctx, listener, code, payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Identity transferred to %s\n", peer)
+
+	return nil
+}
+
+// joinUserPair import and adopt the identity offered with the pairing code
+func joinUserPair(backend *cache.RepoCache, s string) error {
+	code, err := pairing.ParseCode(s)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	payload, err := pairing.Join(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	results, err := backend.ImportIdentities(payload.Bundle)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Status == entity.MergeStatusInvalid {
+			return fmt.Errorf("the identity %s can't be imported: %s", result.Id.Human(), result.Reason)
+		}
+	}
+
+	user, err := backend.ResolveIdentity(payload.UserId)
+	if err != nil {
+		return err
+	}
+
+	err = backend.SetUserIdentity(user)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Your identity is now: %s\n", user.DisplayName())
+
+	if payload.SigningKey != "" {
+		err = repo.LocalConfig().StoreString(signingKeyConfigKey, payload.SigningKey)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "The commits are signed with the key %s, make sure it is installed on this machine\n", payload.SigningKey)
+	}
+
+	return nil
+}
+
+// readSigningKey read the reference of the key signing the commits, from the
+// repository or the global git config
+func readSigningKey() (string, error) {
+	for _, config := range []repository.Config{repo.LocalConfig(), repo.GlobalConfig()} {
+		key, err := config.ReadString(signingKeyConfigKey)
+		if err == repository.ErrNoConfigEntry {
+			continue
+		}
+		return key, err
+	}
+
+	return "", fmt.Errorf("no signing key configured in %s", signingKeyConfigKey)
+}
+
+// pairingHost return an address of the machine reachable from the local
+// network, if any
+func pairingHost() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "127.0.0.1"
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+		if ipNet.IP.IsGlobalUnicast() {
+			return ipNet.IP.String()
+		}
+	}
+
+	return "127.0.0.1"
+}
+
+var userPairCmd = &cobra.Command{
+	Use:   "pair [<code>]",
+	Short: "Transfer your identity to another machine.",
+	Long: `Transfer your identity to another machine.
+
+Without argument, your identity is offered on the network with a short-lived pairing code. On the other machine, run "git bug user pair <code>" in the repository to receive the identity, with its full history, and adopt it.
+
+The pairing code hold the address of the machine and a random secret. The secret is not sent over the network: the identity is encrypted with a key derived from it. As anyone with the code can receive the identity, keep it private.
+
+With --with-signing-key, the reference of the key signing the commits, as configured in "user.signingkey", is transferred as well. The key itself has to be installed separately.`,
+	Example: `git bug user pair
+git bug user pair 192.168.1.10:43121/abcd-efgh-ijkl-mnop`,
+	PreRunE: loadRepo,
+	RunE:    runUserPair,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	userCmd.AddCommand(userPairCmd)
+	userPairCmd.Flags().SortFlags = false
+
+	userPairCmd.Flags().StringVar(&userPairHost, "host", "",
+		"Address of this machine given in the pairing code (default is detected)")
+	userPairCmd.Flags().IntVarP(&userPairPort, "port", "p", 0,
+		"Port to listen to (default is random)")
+	userPairCmd.Flags().DurationVar(&userPairTimeout, "timeout", 5*time.Minute,
+		"Validity of the pairing code")
+	userPairCmd.Flags().BoolVar(&userPairWithSigningKey, "with-signing-key", false,
+		"Transfer the reference of the key signing the commits as well")
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runUserPull(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	remote, err := identityRemote(backend, args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Fetching identities ...")
+
+	stdout, err := backend.FetchIdentities(remote)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(stdout)
+
+	fmt.Println("Merging identities ...")
+
+	for result := range backend.MergeIdentities(remote) {
+		if result.Err != nil {
+			fmt.Println(result.Err)
+		}
+
+		if result.Status != entity.MergeStatusNothing {
+			fmt.Printf("%s: %s\n", result.Id.Human(), result)
+		}
+	}
+
+	return nil
+}
+
+// identityRemote return the remote to exchange the identities with: the one
+// given as argument, or the identity directory, or the default remote
+func identityRemote(backend *cache.RepoCache, args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+
+	remote, err := backend.IdentityDirectory()
+	if err != nil || remote != "" {
+		return remote, err
+	}
+
+	return backend.DefaultRemote()
+}
+
+var userPullCmd = &cobra.Command{
+	Use:   "pull [<remote>]",
+	Short: "Pull only the identities from a git remote.",
+	Long: `Pull only the identities from a git remote.
+
+Without a remote given, the identity directory configured with "git bug user directory" is used, then the default remote.`,
+	PreRunE: loadRepo,
+	RunE:    runUserPull,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	userCmd.AddCommand(userPullCmd)
+	userPullCmd.Flags().SortFlags = false
+}
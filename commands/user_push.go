@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runUserPush(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	remote, err := identityRemote(backend, args)
+	if err != nil {
+		return err
+	}
+
+	stdout, err := backend.PushIdentities(remote)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(stdout)
+
+	return nil
+}
+
+var userPushCmd = &cobra.Command{
+	Use:   "push [<remote>]",
+	Short: "Push only the identities to a git remote.",
+	Long: `Push only the identities to a git remote.
+
+Without a remote given, the identity directory configured with "git bug user directory" is used, then the default remote.`,
+	PreRunE: loadRepo,
+	RunE:    runUserPush,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	userCmd.AddCommand(userPushCmd)
+	userPushCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+	"github.com/MichaelMure/git-bug/util/timestamp"
+)
+
+// the bridges store the login of the imported identities under "<bridge>-login"
+const bridgeLoginMetadataSuffix = "-login"
+
+func runUserShow(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	id, err := backend.ResolveIdentityPrefix(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", colors.Cyan(id.Id().Human()), id.DisplayName())
+	fmt.Printf("Id: %s\n", id.Id())
+
+	fmt.Println("Versions:")
+	for i, version := range id.Versions() {
+		fmt.Printf("  #%d %s (lamport %d)\n", i+1,
+			version.UnixTime().Time().Format("Mon Jan 2 15:04:05 2006 -0700"),
+			version.Time())
+		fmt.Printf("    Name: %s\n", version.Name())
+		if version.Email() != "" {
+			fmt.Printf("    Email: %s\n", version.Email())
+		}
+		if version.Login() != "" {
+			fmt.Printf("    Login: %s\n", version.Login())
+		}
+		if version.AvatarUrl() != "" {
+			fmt.Printf("    Avatar: %s\n", version.AvatarUrl())
+		}
+		for _, key := range version.Keys() {
+			if format := key.Format(); format != "" {
+				fmt.Printf("    Key: %s (%s)\n", key.Fingerprint, format)
+			} else {
+				fmt.Printf("    Key: %s\n", key.Fingerprint)
+			}
+		}
+	}
+
+	metadata := id.ImmutableMetadata()
+	var bridges []string
+	for key := range metadata {
+		if strings.HasSuffix(key, bridgeLoginMetadataSuffix) {
+			bridges = append(bridges, strings.TrimSuffix(key, bridgeLoginMetadataSuffix))
+		}
+	}
+	sort.Strings(bridges)
+
+	if len(bridges) > 0 {
+		fmt.Println("Bridge logins:")
+		for _, bridge := range bridges {
+			fmt.Printf("    %s: %s\n", bridge, metadata[bridge+bridgeLoginMetadataSuffix])
+		}
+	}
+
+	activity := backend.IdentityActivity(id.Id())
+
+	fmt.Println("Activity:")
+	fmt.Printf("    Authored bugs: %d\n", len(activity.Authored))
+	fmt.Printf("    Commented bugs: %d\n", len(activity.Commented))
+	fmt.Printf("    Involved in bugs: %d\n", activity.Involved)
+	if activity.Involved > 0 {
+		fmt.Printf("    Last activity: %s\n",
+			timestamp.Timestamp(activity.LastActivityUnixTime).Time().Format("Mon Jan 2 15:04:05 2006 -0700"))
+	}
+
+	return nil
+}
+
+var userShowCmd = &cobra.Command{
+	Use:     "show <user-id>",
+	Short:   "Display the history and the activity of an identity.",
+	PreRunE: loadRepo,
+	RunE:    runUserShow,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	userCmd.AddCommand(userShowCmd)
+	userShowCmd.Flags().SortFlags = false
+}
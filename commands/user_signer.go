@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var (
+	userSignerGlobal bool
+)
+
+func runUserSigner(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	user, err := backend.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return showUserSigner(user)
+	}
+
+	signer, err := identity.NewSigner(args[0])
+	if err != nil {
+		return err
+	}
+
+	// the key has to be reachable to sign anything
+	key, err := signer.Key()
+	if err != nil {
+		return err
+	}
+
+	err = identity.SetSigner(repo, signer, userSignerGlobal)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "The operations are now signed with %s\n", signer.Spec())
+
+	if identity.HasKey(user.Keys(), key.Fingerprint) {
+		return nil
+	}
+
+	err = user.Mutate(func(m identity.Mutator) identity.Mutator {
+		m.Keys = append(m.Keys, key)
+		return m
+	})
+	if err != nil {
+		return err
+	}
+
+	err = user.Commit()
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Key %s added to %s\n", key.Fingerprint, user.DisplayName())
+
+	return nil
+}
+
+func showUserSigner(user *cache.IdentityCache) error {
+	signer, err := identity.ConfiguredSigner(repo)
+	if err != nil {
+		return err
+	}
+	if signer == nil {
+		fmt.Println("no signer configured, the operations are not signed")
+		return nil
+	}
+
+	fmt.Println(signer.Spec())
+
+	key, err := signer.Key()
+	if err != nil {
+		return err
+	}
+
+	if !identity.HasKey(user.Keys(), key.Fingerprint) {
+		_, _ = fmt.Fprintf(os.Stderr, "The key %s is not registered on %s, its signatures won't be trusted\n", key.Fingerprint, user.DisplayName())
+	}
+
+	return nil
+}
+
+var userSignerCmd = &cobra.Command{
+	Use:   "signer [<gpg:key-id>|<ssh:fingerprint>|<ssh:key>]",
+	Short: "Show or configure the key signing your operations.",
+	Long: `Show or configure the key signing your operations.
+
+The operations are signed by an agent holding the private key: gpg-agent with "gpg:<key-id>", or ssh-agent with "ssh:<fingerprint>", as given by "ssh-add -l". The key can live on a hardware token, as a smartcard or a security key: only its reference is stored in the git config.
+
+With "ssh:<key>", the operations are signed with ssh-keygen, as git does with gpg.format=ssh. The key is given as in user.signingkey: a path to a key file, or "key::<public key>" for a key held in the ssh-agent. Without signer configured, the key signing the commits is used if git sign with ssh.
+
+The public key is added to your identity if missing, so that the signatures can be checked by others with "git bug audit".
+
+The signer is configured for the current repository only. With --global, it become the default signer of all the repositories.`,
+	Example: `git bug user signer ssh:SHA256:Xxu5bgXeR0jn1yPfBWVAE4Ko7yAM4b8MRzL9hMVM2JQ
+git bug user signer ssh:~/.ssh/id_ed25519
+git bug user signer --global gpg:0x4AEE18F83AFDEB23`,
+	PreRunE: loadRepo,
+	RunE:    runUserSigner,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	userCmd.AddCommand(userSignerCmd)
+	userSignerCmd.Flags().SortFlags = false
+
+	userSignerCmd.Flags().BoolVarP(&userSignerGlobal, "global", "g", false,
+		"Configure the signer of all the repositories")
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runVote(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	snap := b.Snapshot()
+
+	fmt.Printf("%d votes\n", len(snap.Voters))
+	for _, voter := range snap.Voters {
+		fmt.Println(voter.DisplayName())
+	}
+
+	return nil
+}
+
+var voteCmd = &cobra.Command{
+	Use:   "vote [<id>]",
+	Short: "Display or change the votes of a bug.",
+	Long: `Display or change the votes of a bug.
+
+Each identity can vote once for a bug, to tell it want it fixed as well. The most wanted bugs are listed first with "git bug ls sort:votes" or "git bug ls --by votes".`,
+	PreRunE: loadRepo,
+	RunE:    runVote,
+}
+
+func init() {
+	RootCmd.AddCommand(voteCmd)
+
+	voteCmd.Flags().SortFlags = false
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runVoteAdd(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.Vote()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d votes\n", len(b.Snapshot().Voters))
+
+	return b.Commit()
+}
+
+var voteAddCmd = &cobra.Command{
+	Use:     "add [<id>]",
+	Short:   "Vote for a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runVoteAdd,
+}
+
+func init() {
+	voteCmd.AddCommand(voteAddCmd)
+
+	voteAddCmd.Flags().SortFlags = false
+
+	addAsFlag(voteAddCmd)
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+func runVoteRm(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	interrupt.RegisterCleaner(backend.Close)
+
+	err = useAsIdentity(backend)
+	if err != nil {
+		return err
+	}
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.RetractVote()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d votes\n", len(b.Snapshot().Voters))
+
+	return b.Commit()
+}
+
+var voteRmCmd = &cobra.Command{
+	Use:     "rm [<id>]",
+	Short:   "Retract your vote for a bug.",
+	PreRunE: loadRepoEnsureUser,
+	RunE:    runVoteRm,
+}
+
+func init() {
+	voteCmd.AddCommand(voteRmCmd)
+
+	voteRmCmd.Flags().SortFlags = false
+
+	addAsFlag(voteRmCmd)
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	_select "github.com/MichaelMure/git-bug/commands/select"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+var (
+	watchInterval time.Duration
+)
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	backend, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return err
+	}
+
+	b, _, err := _select.ResolveBug(backend, args)
+	if err != nil {
+		_ = backend.Close()
+		return err
+	}
+
+	id := b.Id()
+	seen := make(map[entity.Id]bool)
+	for _, op := range b.Snapshot().Operations {
+		seen[op.Id()] = true
+	}
+
+	// the cache is released, to not prevent the other git-bug commands from
+	// running while watching
+	err = backend.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("watching %s %s\n", colors.Cyan(id.Human()), colors.Bold(b.Snapshot().Title))
+
+	for {
+		time.Sleep(watchInterval)
+
+		watched, err := bug.ReadLocalBug(repo, id)
+		if err == bug.ErrBugNotExist {
+			return fmt.Errorf("the bug %s has been removed", id.Human())
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, op := range watched.Compile().Operations {
+			if seen[op.Id()] {
+				continue
+			}
+			seen[op.Id()] = true
+
+			fmt.Printf("%s %s %s %s\n",
+				colors.Cyan(op.Id().Human()),
+				op.Time().Format("2006-01-02 15:04"),
+				colors.Magenta(op.GetAuthor().DisplayName()),
+				describeOperation(op),
+			)
+		}
+	}
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [<id>]",
+	Short: "Display the new operations of a bug as they arrive.",
+	Long: `Display the new operations of a bug as they arrive, until interrupted.
+
+The operations are displayed as soon as they are written in the local repository, whether by another git-bug command, a pull or a bridge.`,
+	PreRunE: loadRepo,
+	RunE:    runWatch,
+}
+
+func init() {
+	RootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().SortFlags = false
+
+	watchCmd.Flags().DurationVarP(&watchInterval, "interval", "i", 2*time.Second,
+		"How often the repository is checked for new operations")
+}
@@ -1,36 +1,267 @@
 package commands
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/99designs/gqlgen/graphql/playground"
-	"github.com/gorilla/mux"
 	"github.com/phayes/freeport"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/spf13/cobra"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/graphql"
+	"github.com/MichaelMure/git-bug/graphql/middleware"
+	"github.com/MichaelMure/git-bug/identity"
 	"github.com/MichaelMure/git-bug/repository"
-	"github.com/MichaelMure/git-bug/util/git"
-	"github.com/MichaelMure/git-bug/webui"
+	"github.com/MichaelMure/git-bug/webui/server"
 )
 
 var (
-	webUIPort   int
-	webUIOpen   bool
-	webUINoOpen bool
+	webUIHost         string
+	webUIPort         int
+	webUIOpen         bool
+	webUINoOpen       bool
+	webUINoPlayground bool
+	webUIReadOnly     bool
+	webUIRepos        []string
+	webUIRepoFile     string
+	webUIRateLimit    int
+	webUIMaxBodySize  int
+	webUILogRequests  bool
+
+	// the repositories served together, if any, see loadReposWebUI
+	webUIServed []graphql.NamedRepo
 )
 
-const webUIOpenConfigKey = "git-bug.webui.open"
+const (
+	webUIOpenConfigKey       = "git-bug.webui.open"
+	webUIPlaygroundConfigKey = "git-bug.webui.playground"
+	webUIRateLimitConfigKey  = "git-bug.webui.rate-limit"
+	webUIMaxBodyConfigKey    = "git-bug.webui.max-body-size"
+	webUILogConfigKey        = "git-bug.webui.log-requests"
+)
+
+const (
+	// the requests per second allowed to each client IP by default
+	defaultWebUIRateLimit = 20
+	// the max size of the request bodies by default, in MB (the github limit
+	// of the uploaded files)
+	defaultWebUIMaxBodySize = 100
+)
+
+// the file in which a running web UI record its address, in each repository
+// served, for "git bug browse"
+const webUIAddressFile = "webui-address"
+
+// webUIAddress is the address of a running web UI
+type webUIAddress struct {
+	Url string `json:"url"`
+	// the name of the repository in the routes, if several are served
+	Repo string `json:"repo,omitempty"`
+}
+
+func webUIAddressPath(repo repository.Repo) string {
+	return filepath.Join(repo.GetPath(), "git-bug", webUIAddressFile)
+}
+
+// writeWebUIAddresses record the address of the web UI in the repositories
+// served
+func writeWebUIAddresses(served []graphql.NamedRepo, url string) error {
+	for _, r := range served {
+		data, err := json.Marshal(webUIAddress{Url: url, Repo: r.Name})
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(webUIAddressPath(r.Repo)), 0755)
+		if err != nil {
+			return err
+		}
+
+		err = ioutil.WriteFile(webUIAddressPath(r.Repo), data, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func removeWebUIAddresses(served []graphql.NamedRepo) {
+	for _, r := range served {
+		_ = os.Remove(webUIAddressPath(r.Repo))
+	}
+}
+
+// loadRepoWebUI load the repository and check the user identity, unless the
+// web UI is served read-only. A bare repository without user identity, as on
+// a server, is served read-only.
+func loadRepoWebUI(cmd *cobra.Command, args []string) error {
+	if len(webUIRepos) > 0 || webUIRepoFile != "" {
+		return loadReposWebUI()
+	}
+
+	err := loadRepo(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if !webUIReadOnly && asIdentity == "" {
+		bare, err := repo.LocalConfig().ReadBool("core.bare")
+		if err != nil && err != repository.ErrNoConfigEntry {
+			return err
+		}
+		set, err := identity.IsUserIdentitySet(repo)
+		if err != nil {
+			return err
+		}
+		webUIReadOnly = bare && !set
+	}
+
+	if webUIReadOnly {
+		return nil
+	}
+
+	autoSyncPushNeeded = true
+
+	return ensureUser()
+}
+
+// loadReposWebUI open the repositories given with --repo and --repo-file to
+// serve them together. As for a single repository, they are served read-only
+// if they are all bare and without user identity, otherwise they all need a
+// user identity.
+func loadReposWebUI() error {
+	specs := webUIRepos
+	if webUIRepoFile != "" {
+		fileSpecs, err := readRepoFile(webUIRepoFile)
+		if err != nil {
+			return err
+		}
+		specs = append(specs, fileSpecs...)
+	}
+
+	if len(specs) == 0 {
+		return fmt.Errorf("no repository to serve in %s", webUIRepoFile)
+	}
+
+	readOnly := true
+
+	for _, spec := range specs {
+		name, path := parseRepoSpec(spec)
+
+		for _, served := range webUIServed {
+			if served.Name == name {
+				return fmt.Errorf("two repositories are named %s, name them with \"<name>=<path>\"", name)
+			}
+		}
+
+		r, err := repository.NewGitRepo(path, bug.Witnesser)
+		if err == repository.ErrNotARepo {
+			return fmt.Errorf("%s is not a git repository", path)
+		}
+		if err != nil {
+			return err
+		}
+
+		bare, err := r.LocalConfig().ReadBool("core.bare")
+		if err != nil && err != repository.ErrNoConfigEntry {
+			return err
+		}
+		set, err := identity.IsUserIdentitySet(r)
+		if err != nil {
+			return err
+		}
+		readOnly = readOnly && bare && !set
+
+		webUIServed = append(webUIServed, graphql.NamedRepo{Name: name, Repo: r})
+	}
+
+	webUIReadOnly = webUIReadOnly || readOnly
+
+	if !webUIReadOnly {
+		for _, served := range webUIServed {
+			repo = served.Repo
+			if err := ensureUser(); err != nil {
+				return fmt.Errorf("repository %s: %v", served.Name, err)
+			}
+		}
+	}
+
+	// the git config of the web UI is read from the first repository
+	repo = webUIServed[0].Repo
+
+	return nil
+}
+
+// parseRepoSpec parse a repository given as "[<name>=]<path>". Without
+// name, the repository is named after its directory.
+func parseRepoSpec(spec string) (name string, path string) {
+	if i := strings.Index(spec, "="); i > 0 {
+		return spec[:i], spec[i+1:]
+	}
+
+	return repoDirName(spec), spec
+}
+
+// repoDirName return the name of a repository after its directory
+func repoDirName(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+
+	// a bare repository is usually in "<name>.git"
+	return strings.TrimSuffix(filepath.Base(abs), ".git")
+}
+
+// readRepoFile read a file listing the repositories to serve, one
+// "[<name>=]<path>" per line. The empty lines and the lines starting with
+// "#" are ignored, the relative paths are relative to the file.
+func readRepoFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var specs []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, repoPath := "", line
+		if i := strings.Index(line, "="); i > 0 {
+			name, repoPath = line[:i], line[i+1:]
+		}
+		if !filepath.IsAbs(repoPath) {
+			repoPath = filepath.Join(filepath.Dir(path), repoPath)
+		}
+		if name != "" {
+			repoPath = name + "=" + repoPath
+		}
+
+		specs = append(specs, repoPath)
+	}
+
+	return specs, scanner.Err()
+}
 
 func runWebUI(cmd *cobra.Command, args []string) error {
 	if webUIPort == 0 {
@@ -41,31 +272,59 @@ func runWebUI(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	addr := fmt.Sprintf("127.0.0.1:%d", webUIPort)
+	addr := net.JoinHostPort(webUIHost, strconv.Itoa(webUIPort))
 	webUiAddr := fmt.Sprintf("http://%s", addr)
 
-	router := mux.NewRouter()
+	// outside of the local machine, an API token is required to modify the
+	// repository
+	tokenRequired := !isLoopback(webUIHost)
+
+	// the repositories to serve, and the name to designate them
+	served := webUIServed
+	if len(served) == 0 {
+		served = []graphql.NamedRepo{{Repo: repo}}
+	}
 
-	graphqlHandler, err := graphql.NewHandler(repo)
+	err := readWebUILimits(cmd)
 	if err != nil {
 		return err
 	}
 
-	assetsHandler := &fileSystemWithDefault{
-		FileSystem:  webui.WebUIAssets,
-		defaultFile: "index.html",
+	configPlayground, err := repo.LocalConfig().ReadBool(webUIPlaygroundConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		// default to true
+		configPlayground = true
+	} else if err != nil {
+		return err
+	}
+
+	playgroundEnabled := configPlayground && !webUINoPlayground
+
+	// the max size of the request bodies, in bytes
+	maxBodySize := int64(webUIMaxBodySize) * 1000 * 1000
+
+	webUIHandler, err := server.NewHandler(server.Options{
+		Repos:         served,
+		TokenRequired: tokenRequired,
+		ReadOnly:      webUIReadOnly,
+		Playground:    playgroundEnabled,
+		MaxUploadSize: maxBodySize,
+	})
+	if err != nil {
+		return err
 	}
 
-	// Routes
-	router.Path("/playground").Handler(playground.Handler("git-bug", "/graphql"))
-	router.Path("/graphql").Handler(graphqlHandler)
-	router.Path("/gitfile/{hash}").Handler(newGitFileHandler(repo))
-	router.Path("/upload").Methods("POST").Handler(newGitUploadFileHandler(repo))
-	router.PathPrefix("/").Handler(http.FileServer(assetsHandler))
+	handler := middleware.MaxBodySize(maxBodySize)(webUIHandler)
+	if webUIRateLimit > 0 {
+		handler = middleware.RateLimit(float64(webUIRateLimit))(handler)
+	}
+	if webUILogRequests {
+		handler = middleware.Logging(log.New(os.Stderr, "", log.LstdFlags))(handler)
+	}
 
 	srv := &http.Server{
 		Addr:    addr,
-		Handler: router,
+		Handler: handler,
 	}
 
 	done := make(chan bool)
@@ -87,7 +346,7 @@ func runWebUI(cmd *cobra.Command, args []string) error {
 		}
 
 		// Teardown
-		err := graphqlHandler.Close()
+		err := webUIHandler.Close()
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -97,9 +356,29 @@ func runWebUI(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Web UI: %s\n", webUiAddr)
 	fmt.Printf("Graphql API: http://%s/graphql\n", addr)
-	fmt.Printf("Graphql Playground: http://%s/playground\n", addr)
+	fmt.Printf("Graphql API documentation: http://%s/graphql/docs\n", addr)
+	if playgroundEnabled {
+		fmt.Printf("Graphql Playground: http://%s/playground\n", addr)
+	}
+	for _, r := range webUIServed {
+		fmt.Printf("Repository %s: %s\n", r.Name, r.Repo.GetPath())
+	}
+	if webUIRateLimit > 0 {
+		fmt.Printf("Requests limited to %d per second per client\n", webUIRateLimit)
+	}
+	if webUIReadOnly {
+		fmt.Println("The repository is served read-only")
+	} else if tokenRequired {
+		fmt.Println("An API token is required to modify the repository, see \"git bug token\"")
+	}
 	fmt.Println("Press Ctrl+c to quit")
 
+	err = writeWebUIAddresses(served, webUiAddr)
+	if err != nil {
+		return err
+	}
+	defer removeWebUIAddresses(served)
+
 	configOpen, err := repo.LocalConfig().ReadBool(webUIOpenConfigKey)
 	if err == repository.ErrNoConfigEntry {
 		// default to true
@@ -128,117 +407,59 @@ func runWebUI(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// implement a http.FileSystem that will serve a default file when the looked up
-// file doesn't exist. Useful for Single-Page App that implement routing client
-// side, where the server has to return the root index.html file for every route.
-type fileSystemWithDefault struct {
-	http.FileSystem
-	defaultFile string
-}
-
-func (fswd *fileSystemWithDefault) Open(name string) (http.File, error) {
-	f, err := fswd.FileSystem.Open(name)
-	if os.IsNotExist(err) {
-		return fswd.FileSystem.Open(fswd.defaultFile)
-	}
-	return f, err
-}
-
-// implement a http.Handler that will read and server git blob.
-type gitFileHandler struct {
-	repo repository.Repo
-}
-
-func newGitFileHandler(repo repository.Repo) http.Handler {
-	return &gitFileHandler{
-		repo: repo,
-	}
-}
-
-func (gfh *gitFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	hash := git.Hash(mux.Vars(r)["hash"])
-
-	if !hash.IsValid() {
-		http.Error(rw, "invalid git hash", http.StatusBadRequest)
-		return
+// readWebUILimits read the limits of the http server and the logging of the
+// requests from the git config, unless given with a flag
+func readWebUILimits(cmd *cobra.Command) error {
+	ints := []struct {
+		flag  string
+		key   string
+		value *int
+	}{
+		{"rate-limit", webUIRateLimitConfigKey, &webUIRateLimit},
+		{"max-body-size", webUIMaxBodyConfigKey, &webUIMaxBodySize},
 	}
 
-	// TODO: this mean that the whole file will he buffered in memory
-	// This can be a problem for big files. There might be a way around
-	// that by implementing a io.ReadSeeker that would read and discard
-	// data when a seek is called.
-	data, err := gfh.repo.ReadData(git.Hash(hash))
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	http.ServeContent(rw, r, "", time.Now(), bytes.NewReader(data))
-}
-
-// implement a http.Handler that will accept and store content into git blob.
-type gitUploadFileHandler struct {
-	repo repository.Repo
-}
-
-func newGitUploadFileHandler(repo repository.Repo) http.Handler {
-	return &gitUploadFileHandler{
-		repo: repo,
-	}
-}
-
-func (gufh *gitUploadFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	// 100MB (github limit)
-	var maxUploadSize int64 = 100 * 1000 * 1000
-	r.Body = http.MaxBytesReader(rw, r.Body, maxUploadSize)
-	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
-		http.Error(rw, "file too big (100MB max)", http.StatusBadRequest)
-		return
-	}
-
-	file, _, err := r.FormFile("uploadfile")
-	if err != nil {
-		http.Error(rw, "invalid file", http.StatusBadRequest)
-		return
-	}
-	defer file.Close()
-	fileBytes, err := ioutil.ReadAll(file)
-	if err != nil {
-		http.Error(rw, "invalid file", http.StatusBadRequest)
-		return
+	for _, i := range ints {
+		if cmd.Flags().Changed(i.flag) {
+			continue
+		}
+		raw, err := repo.LocalConfig().ReadString(i.key)
+		if err == repository.ErrNoConfigEntry {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		*i.value, err = strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", i.key, err)
+		}
 	}
 
-	filetype := http.DetectContentType(fileBytes)
-	if filetype != "image/jpeg" && filetype != "image/jpg" &&
-		filetype != "image/gif" && filetype != "image/png" {
-		http.Error(rw, "invalid file type", http.StatusBadRequest)
-		return
+	if !cmd.Flags().Changed("log-requests") {
+		logRequests, err := repo.LocalConfig().ReadBool(webUILogConfigKey)
+		if err != nil && err != repository.ErrNoConfigEntry {
+			return err
+		}
+		webUILogRequests = logRequests
 	}
 
-	hash, err := gufh.repo.StoreData(fileBytes)
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
-		return
+	if webUIRateLimit < 0 {
+		return fmt.Errorf("invalid rate limit %d", webUIRateLimit)
 	}
-
-	type response struct {
-		Hash string `json:"hash"`
+	if webUIMaxBodySize <= 0 {
+		return fmt.Errorf("invalid max body size %d", webUIMaxBodySize)
 	}
 
-	resp := response{Hash: string(hash)}
-
-	js, err := json.Marshal(resp)
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	return nil
+}
 
-	rw.Header().Set("Content-Type", "application/json")
-	_, err = rw.Write(js)
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
-		return
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
 
 var webUICmd = &cobra.Command{
@@ -246,10 +467,23 @@ var webUICmd = &cobra.Command{
 	Short: "Launch the web UI.",
 	Long: `Launch the web UI.
 
+When listening on an address reachable from other machines, the GraphQL mutations and the file uploads require an API token, given in the "Authorization: Bearer <token>" header. The tokens are managed with "git bug token".
+
+With "--read-only", the mutations and the file uploads are rejected and no user identity is needed. This is the default for a bare repository without user identity, to serve the web UI from the repository of a server.
+
+With "--repo" or "--repo-file", several repositories are served together instead of the current one, in a combined view. Each repository is named after its directory, or with "<name>=<path>", and its bugs are designated as "<name>/<id>". An API token only grant the access to the repository it was created in, and the git config below is read from the first one.
+
+To keep a misbehaving client from degrading the server for everyone, the requests of each client IP are limited to a number per second, with a burst of five seconds of requests, and the size of the request bodies is limited, uploaded files included. With "--log-requests", each request is logged on the standard error with its client IP, status and latency.
+
 Available git config:
   git-bug.webui.open [bool]: control the automatic opening of the web UI in the default browser
+  git-bug.webui.accent-color [#rrggbb]: the accent color of the web UI for this repository
+  git-bug.webui.playground [bool]: control the serving of the GraphQL playground (default to true)
+  git-bug.webui.rate-limit [int]: the requests per second allowed to each client IP, 0 to disable the limit (default to 20)
+  git-bug.webui.max-body-size [int]: the max size of the request bodies, in MB (default to 100)
+  git-bug.webui.log-requests [bool]: log each request on the standard error
 `,
-	PreRunE: loadRepoEnsureUser,
+	PreRunE: loadRepoWebUI,
 	RunE:    runWebUI,
 }
 
@@ -260,6 +494,14 @@ func init() {
 
 	webUICmd.Flags().BoolVar(&webUIOpen, "open", false, "Automatically open the web UI in the default browser")
 	webUICmd.Flags().BoolVar(&webUINoOpen, "no-open", false, "Prevent the automatic opening of the web UI in the default browser")
+	webUICmd.Flags().StringVar(&webUIHost, "host", "127.0.0.1", "Network address to listen to")
 	webUICmd.Flags().IntVarP(&webUIPort, "port", "p", 0, "Port to listen to (default is random)")
+	webUICmd.Flags().BoolVar(&webUINoPlayground, "no-playground", false, "Don't serve the GraphQL playground")
+	webUICmd.Flags().BoolVar(&webUIReadOnly, "read-only", false, "Reject the modifications of the repository, no user identity is needed")
+	webUICmd.Flags().StringArrayVar(&webUIRepos, "repo", nil, "Serve a repository given as [<name>=]<path> instead of the current one. Can be repeated to serve several repositories")
+	webUICmd.Flags().StringVar(&webUIRepoFile, "repo-file", "", "Serve the repositories listed in a file, one [<name>=]<path> per line")
+	webUICmd.Flags().IntVar(&webUIRateLimit, "rate-limit", defaultWebUIRateLimit, "Requests per second allowed to each client IP, 0 to disable the limit")
+	webUICmd.Flags().IntVar(&webUIMaxBodySize, "max-body-size", defaultWebUIMaxBodySize, "Max size of the request bodies, in MB")
+	webUICmd.Flags().BoolVar(&webUILogRequests, "log-requests", false, "Log each request on the standard error")
 
 }
@@ -8,17 +8,29 @@ import (
 type ErrMultipleMatch struct {
 	entityType string
 	Matching   []Id
+	// a description of each candidate, as the title of a bug, if known
+	Descriptions map[Id]string
 }
 
 func NewErrMultipleMatch(entityType string, matching []Id) *ErrMultipleMatch {
 	return &ErrMultipleMatch{entityType: entityType, Matching: matching}
 }
 
+// Describe add a description to the candidates of the error, to help choosing
+// between them
+func (e *ErrMultipleMatch) Describe(descriptions map[Id]string) *ErrMultipleMatch {
+	e.Descriptions = descriptions
+	return e
+}
+
 func (e ErrMultipleMatch) Error() string {
 	matching := make([]string, len(e.Matching))
 
 	for i, match := range e.Matching {
 		matching[i] = match.String()
+		if description := e.Descriptions[match]; description != "" {
+			matching[i] += " " + description
+		}
 	}
 
 	return fmt.Sprintf("Multiple matching %s found:\n%s",
@@ -30,3 +42,24 @@ func IsErrMultipleMatch(err error) bool {
 	_, ok := err.(*ErrMultipleMatch)
 	return ok
 }
+
+// ErrIncompleteHistory is returned when the first commits of an entity are
+// missing in the repository, as it happens in a shallow clone
+type ErrIncompleteHistory struct {
+	entityType string
+	Id         Id
+}
+
+func NewErrIncompleteHistory(entityType string, id Id) *ErrIncompleteHistory {
+	return &ErrIncompleteHistory{entityType: entityType, Id: id}
+}
+
+func (e ErrIncompleteHistory) Error() string {
+	return fmt.Sprintf("the history of the %s %s is incomplete, as in a shallow clone: run \"git bug pull\" to fetch it entirely",
+		e.entityType, e.Id.Human())
+}
+
+func IsErrIncompleteHistory(err error) bool {
+	_, ok := err.(*ErrIncompleteHistory)
+	return ok
+}
@@ -3,6 +3,7 @@ package entity
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -28,6 +29,43 @@ func (i Id) Human() string {
 	return fmt.Sprintf(format, i)
 }
 
+// Short return the identifier shortened to the given length, at least the
+// length of Human
+func (i Id) Short(length int) string {
+	if length < humanIdLength {
+		length = humanIdLength
+	}
+	if length >= len(i) {
+		return string(i)
+	}
+	return string(i)[:length]
+}
+
+// UniquePrefixLength return the minimal length of the prefixes of the given
+// identifiers for them to be unambiguous, at least the length of Human
+func UniquePrefixLength(ids []Id) int {
+	sorted := make([]string, len(ids))
+	for i, id := range ids {
+		sorted[i] = string(id)
+	}
+	sort.Strings(sorted)
+
+	length := humanIdLength
+	for i := 1; i < len(sorted); i++ {
+		// the longest common prefix is between neighbours
+		common := 0
+		for common < len(sorted[i]) && common < len(sorted[i-1]) &&
+			sorted[i][common] == sorted[i-1][common] {
+			common++
+		}
+		if common+1 > length {
+			length = common + 1
+		}
+	}
+
+	return length
+}
+
 func (i Id) HasPrefix(prefix string) bool {
 	return strings.HasPrefix(string(i), prefix)
 }
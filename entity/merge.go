@@ -14,6 +14,8 @@ const (
 	MergeStatusUpdated
 	MergeStatusNothing
 	MergeStatusError
+	MergeStatusRemoved
+	MergeStatusPending
 )
 
 type MergeResult struct {
@@ -26,7 +28,7 @@ type MergeResult struct {
 	// Only set for invalid status
 	Reason string
 
-	// Not set for invalid status
+	// Not set for invalid and removed status
 	Entity Interface
 }
 
@@ -42,6 +44,10 @@ func (mr MergeResult) String() string {
 		return "nothing to do"
 	case MergeStatusError:
 		return fmt.Sprintf("merge error on %s: %s", mr.Id, mr.Err.Error())
+	case MergeStatusRemoved:
+		return "removed"
+	case MergeStatusPending:
+		return "pending review"
 	default:
 		panic("unknown merge status")
 	}
@@ -60,7 +66,7 @@ func NewMergeStatus(status MergeStatus, id Id, entity Interface) MergeResult {
 		Id:     id,
 		Status: status,
 
-		// Entity is not set for an invalid merge result
+		// Entity is not set for an invalid or removed merge result
 		Entity: entity,
 	}
 }
@@ -0,0 +1,331 @@
+// Package export contains the exporters of the git-bug data to other formats,
+// for the tools that can't read it natively.
+package export
+
+import (
+	"database/sql"
+	"fmt"
+
+	// register the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// sqliteVersion is the version of the tables layout. A database with a
+// different version is rebuilt from scratch.
+const sqliteVersion = 2
+
+var sqliteTables = []string{
+	`CREATE TABLE IF NOT EXISTS meta (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS identities (
+		id TEXT PRIMARY KEY,
+		human_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL,
+		login TEXT NOT NULL,
+		avatar_url TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS bugs (
+		id TEXT PRIMARY KEY,
+		human_id TEXT NOT NULL,
+		title TEXT NOT NULL,
+		status TEXT NOT NULL,
+		resolution TEXT NOT NULL,
+		component TEXT NOT NULL,
+		author_id TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		edited_at INTEGER NOT NULL,
+		create_lamport INTEGER NOT NULL,
+		edit_lamport INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS operations (
+		id TEXT PRIMARY KEY,
+		bug_id TEXT NOT NULL REFERENCES bugs(id),
+		position INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		author_id TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS comments (
+		id TEXT PRIMARY KEY,
+		bug_id TEXT NOT NULL REFERENCES bugs(id),
+		position INTEGER NOT NULL,
+		author_id TEXT NOT NULL,
+		message TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS labels (
+		bug_id TEXT NOT NULL REFERENCES bugs(id),
+		name TEXT NOT NULL,
+		PRIMARY KEY (bug_id, name)
+	)`,
+	`CREATE INDEX IF NOT EXISTS operations_bug ON operations (bug_id)`,
+	`CREATE INDEX IF NOT EXISTS comments_bug ON comments (bug_id)`,
+}
+
+func operationTypeName(op bug.Operation) string {
+	switch op.(type) {
+	case *bug.CreateOperation:
+		return "create"
+	case *bug.SetTitleOperation:
+		return "set_title"
+	case *bug.AddCommentOperation:
+		return "add_comment"
+	case *bug.SetStatusOperation:
+		return "set_status"
+	case *bug.LabelChangeOperation:
+		return "label_change"
+	case *bug.EditCommentOperation:
+		return "edit_comment"
+	case *bug.NoOpOperation:
+		return "noop"
+	case *bug.SetMetadataOperation:
+		return "set_metadata"
+	case *bug.SetComponentOperation:
+		return "set_component"
+	case *bug.AssigneeChangeOperation:
+		return "assignee_change"
+	case *bug.MarkDuplicateOperation:
+		return "mark_duplicate"
+	case *bug.SetParentOperation:
+		return "set_parent"
+	case *bug.RemoveParentOperation:
+		return "remove_parent"
+	case *bug.AddChecklistItemOperation:
+		return "add_checklist_item"
+	case *bug.CheckItemOperation:
+		return "check_item"
+	case *bug.VoteOperation:
+		return "vote"
+	case *bug.RequestReviewOperation:
+		return "request_review"
+	case *bug.CustomOperation:
+		return "custom"
+	default:
+		return "unknown"
+	}
+}
+
+// SQLiteStats tell what changed in the database during an export
+type SQLiteStats struct {
+	Updated int
+	Removed int
+	Total   int
+}
+
+// SQLite mirror the bugs and identities of the repository in a SQLite
+// database, creating it if needed. When the database has been created by a
+// previous export, only the bugs edited since are written again.
+func SQLite(repo *cache.RepoCache, path string) (SQLiteStats, error) {
+	var stats SQLiteStats
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return stats, err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return stats, err
+	}
+	defer func() {
+		// no-op if committed
+		_ = tx.Rollback()
+	}()
+
+	err = prepareSQLite(tx)
+	if err != nil {
+		return stats, err
+	}
+
+	err = exportSQLiteIdentities(tx, repo)
+	if err != nil {
+		return stats, err
+	}
+
+	exported, err := exportedBugs(tx)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, id := range repo.AllBugsIds() {
+		excerpt, err := repo.ResolveBugExcerpt(id)
+		if err != nil {
+			return stats, err
+		}
+
+		editTime, ok := exported[id]
+		delete(exported, id)
+		stats.Total++
+
+		if ok && editTime == excerpt.EditLamportTime {
+			continue
+		}
+
+		b, err := repo.ResolveBug(id)
+		if err != nil {
+			return stats, err
+		}
+
+		err = removeSQLiteBug(tx, id)
+		if err != nil {
+			return stats, err
+		}
+
+		err = exportSQLiteBug(tx, excerpt, b.Snapshot())
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Updated++
+	}
+
+	// the remaining bugs don't exist anymore
+	for id := range exported {
+		err = removeSQLiteBug(tx, id)
+		if err != nil {
+			return stats, err
+		}
+		stats.Removed++
+	}
+
+	return stats, tx.Commit()
+}
+
+// prepareSQLite create the tables, or drop them first if they have been
+// created with another layout
+func prepareSQLite(tx *sql.Tx) error {
+	var version int
+	err := tx.QueryRow(`SELECT value FROM meta WHERE key = 'version'`).Scan(&version)
+	if err == nil && version != sqliteVersion {
+		for _, table := range []string{"meta", "identities", "bugs", "operations", "comments", "labels"} {
+			_, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, query := range sqliteTables {
+		_, err := tx.Exec(query)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('version', ?)`, sqliteVersion)
+	return err
+}
+
+func exportSQLiteIdentities(tx *sql.Tx, repo *cache.RepoCache) error {
+	_, err := tx.Exec(`DELETE FROM identities`)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range repo.AllIdentityIds() {
+		i, err := repo.ResolveIdentity(id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT INTO identities (id, human_id, name, email, login, avatar_url) VALUES (?, ?, ?, ?, ?, ?)`,
+			i.Id().String(), i.Id().Human(), i.Name(), i.Email(), i.Login(), i.AvatarUrl())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportedBugs return the bugs already in the database, with the edit time
+// they had when exported
+func exportedBugs(tx *sql.Tx) (map[entity.Id]lamport.Time, error) {
+	rows, err := tx.Query(`SELECT id, edit_lamport FROM bugs`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[entity.Id]lamport.Time)
+
+	for rows.Next() {
+		var id string
+		var editTime uint64
+		err := rows.Scan(&id, &editTime)
+		if err != nil {
+			return nil, err
+		}
+		result[entity.Id(id)] = lamport.Time(editTime)
+	}
+
+	return result, rows.Err()
+}
+
+func removeSQLiteBug(tx *sql.Tx, id entity.Id) error {
+	for _, table := range []string{"labels", "comments", "operations"} {
+		_, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE bug_id = ?", table), id.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(`DELETE FROM bugs WHERE id = ?`, id.String())
+	return err
+}
+
+func exportSQLiteBug(tx *sql.Tx, excerpt *cache.BugExcerpt, snap *bug.Snapshot) error {
+	_, err := tx.Exec(`INSERT INTO bugs (id, human_id, title, status, resolution, component, author_id, created_at, edited_at, create_lamport, edit_lamport) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		snap.Id().String(),
+		snap.Id().Human(),
+		snap.Title,
+		snap.Status.String(),
+		snap.Resolution.String(),
+		snap.Component,
+		snap.Author.Id().String(),
+		snap.CreatedAt.Unix(),
+		snap.LastEditUnix(),
+		uint64(excerpt.CreateLamportTime),
+		uint64(excerpt.EditLamportTime),
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, op := range snap.Operations {
+		_, err := tx.Exec(`INSERT INTO operations (id, bug_id, position, type, author_id, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			op.Id().String(), snap.Id().String(), i, operationTypeName(op),
+			op.GetAuthor().Id().String(), op.GetUnixTime())
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, comment := range snap.Comments {
+		_, err := tx.Exec(`INSERT INTO comments (id, bug_id, position, author_id, message, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			comment.Id().String(), snap.Id().String(), i,
+			comment.Author.Id().String(), comment.Message, int64(comment.UnixTime))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, label := range snap.Labels {
+		_, err := tx.Exec(`INSERT INTO labels (bug_id, name) VALUES (?, ?)`,
+			snap.Id().String(), label.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestSQLite(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	dir, err := ioutil.TempDir("", "git-bug-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "bugs.db")
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+
+	rene, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b1, _, err := backend.NewBug("first", "message")
+	require.NoError(t, err)
+	_, _, err = b1.ChangeLabels([]string{"bug", "ui"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+
+	b2, _, err := backend.NewBug("second", "message")
+	require.NoError(t, err)
+
+	stats, err := SQLite(backend, path)
+	require.NoError(t, err)
+	require.Equal(t, SQLiteStats{Updated: 2, Total: 2}, stats)
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	count := func(query string, args ...interface{}) int {
+		var n int
+		require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+		return n
+	}
+
+	require.Equal(t, 2, count(`SELECT COUNT(*) FROM bugs`))
+	require.Equal(t, 3, count(`SELECT COUNT(*) FROM operations`))
+	require.Equal(t, 2, count(`SELECT COUNT(*) FROM comments`))
+	require.Equal(t, 2, count(`SELECT COUNT(*) FROM labels WHERE bug_id = ?`, b1.Id().String()))
+	require.Equal(t, 1, count(`SELECT COUNT(*) FROM identities WHERE name = ?`, "René Descartes"))
+
+	// only the edited bug is written again, the removed one is dropped
+	_, err = b1.AddComment("comment")
+	require.NoError(t, err)
+	require.NoError(t, b1.Commit())
+	_, err = backend.RemoveBug(b2.Id(), "duplicate")
+	require.NoError(t, err)
+
+	stats, err = SQLite(backend, path)
+	require.NoError(t, err)
+	require.Equal(t, SQLiteStats{Updated: 1, Removed: 1, Total: 1}, stats)
+
+	require.Equal(t, 1, count(`SELECT COUNT(*) FROM bugs`))
+	require.Equal(t, 2, count(`SELECT COUNT(*) FROM comments`))
+
+	stats, err = SQLite(backend, path)
+	require.NoError(t, err)
+	require.Equal(t, SQLiteStats{Total: 1}, stats)
+}
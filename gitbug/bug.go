@@ -0,0 +1,97 @@
+package gitbug
+
+import (
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+// Bug is the state of a bug at the time it has been read
+type Bug struct {
+	// the full id of the bug
+	Id string
+	// the short version of the id, as displayed by git-bug
+	HumanId string
+
+	Title string
+	// "open" or "closed"
+	Status string
+	// the reason of closing, as "fixed" or "duplicate", if any
+	Resolution string
+	Labels     []string
+	Component  string
+	Author     Person
+	Assignees  []Person
+	CreatedAt  time.Time
+	EditedAt   time.Time
+	// the first comment is the description of the bug
+	Comments []Comment
+}
+
+// Person is the author of a bug or of a comment, or an assignee
+type Person struct {
+	// the id of the identity, empty for the legacy authors stored in the bugs
+	Id    string
+	Name  string
+	Email string
+	// the login on an external bug tracker the identity was imported from,
+	// if any
+	Login string
+}
+
+// Comment is a message on a bug
+type Comment struct {
+	Id        string
+	Author    Person
+	Message   string
+	CreatedAt time.Time
+}
+
+func newBug(snap *bug.Snapshot) Bug {
+	b := Bug{
+		Id:         snap.Id().String(),
+		HumanId:    snap.Id().Human(),
+		Title:      snap.Title,
+		Status:     snap.Status.String(),
+		Resolution: snap.Resolution.String(),
+		Component:  snap.Component,
+		Author:     newPerson(snap.Author),
+		CreatedAt:  snap.CreatedAt,
+		EditedAt:   snap.LastEditTime(),
+	}
+
+	for _, label := range snap.Labels {
+		b.Labels = append(b.Labels, label.String())
+	}
+
+	for _, assignee := range snap.Assignees {
+		b.Assignees = append(b.Assignees, newPerson(assignee))
+	}
+
+	for _, comment := range snap.Comments {
+		b.Comments = append(b.Comments, Comment{
+			Id:        comment.Id().String(),
+			Author:    newPerson(comment.Author),
+			Message:   comment.Message,
+			CreatedAt: comment.UnixTime.Time(),
+		})
+	}
+
+	return b
+}
+
+func newPerson(i identity.Interface) Person {
+	p := Person{
+		Name:  i.Name(),
+		Email: i.Email(),
+		Login: i.Login(),
+	}
+
+	// the legacy bare identities are not stored on their own
+	if _, ok := i.(*identity.Bare); !ok {
+		p.Id = i.Id().String()
+	}
+
+	return p
+}
@@ -0,0 +1,155 @@
+// Package gitbug is the stable API to embed git-bug in other Go programs.
+//
+// It hides the internal packages of git-bug, whose API change often, behind
+// a small facade: a Repo is opened on a git repository, to list, query,
+// create and comment the bugs. The bugs are given as plain values that
+// don't change once returned.
+//
+//	repo, err := gitbug.OpenRepo(".")
+//	if err != nil {
+//		return err
+//	}
+//	defer repo.Close()
+//
+//	bugs, err := repo.Query("status:open sort:edit")
+//
+// The operations creating data are authored by the user identity of the
+// repository, as set with "git bug user adopt" or "git bug user create".
+package gitbug
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var (
+	// ErrBugNotFound is returned when no bug match the given id
+	ErrBugNotFound = errors.New("bug not found")
+	// ErrAmbiguousId is returned when an id prefix match several bugs
+	ErrAmbiguousId = errors.New("the id prefix match several bugs")
+	// ErrNoUser is returned when data is created without user identity set
+	// in the repository
+	ErrNoUser = errors.New("no user identity is set in the repository")
+)
+
+// Repo is a git repository opened to read and write its bugs. A Repo hold a
+// lock on the git-bug data of the repository until closed.
+//
+// A Repo is safe for concurrent use.
+type Repo struct {
+	cache *cache.RepoCache
+}
+
+// OpenRepo open the git repository containing the given path
+func OpenRepo(path string) (*Repo, error) {
+	repo, err := repository.NewGitRepo(path, bug.Witnesser)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := cache.NewRepoCache(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repo{cache: c}, nil
+}
+
+// Close release the repository. The Repo must not be used afterward.
+func (r *Repo) Close() error {
+	return r.cache.Close()
+}
+
+// ListBugs return all the bugs of the repository, the most recently created
+// first
+func (r *Repo) ListBugs() ([]Bug, error) {
+	return r.Query("")
+}
+
+// Query return the bugs matching a query, in the order asked by the query or
+// the most recently created first. The query language is the one of
+// "git bug ls", for example "status:open label:bug sort:edit".
+func (r *Repo) Query(query string) ([]Bug, error) {
+	q, err := cache.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := r.cache.QueryBugs(q)
+	bugs := make([]Bug, 0, len(ids))
+
+	for _, id := range ids {
+		b, err := r.cache.ResolveBug(id)
+		if err != nil {
+			return nil, err
+		}
+		bugs = append(bugs, newBug(b.Snapshot()))
+	}
+
+	return bugs, nil
+}
+
+// Bug return the bug with the given id, or id prefix
+func (r *Repo) Bug(id string) (Bug, error) {
+	b, err := r.resolve(id)
+	if err != nil {
+		return Bug{}, err
+	}
+
+	return newBug(b.Snapshot()), nil
+}
+
+// NewBug create a new bug with a title and a first message, authored by the
+// user identity
+func (r *Repo) NewBug(title string, message string) (Bug, error) {
+	b, _, err := r.cache.NewBug(strings.TrimSpace(title), message)
+	if err == identity.ErrNoIdentitySet {
+		return Bug{}, ErrNoUser
+	}
+	if err != nil {
+		return Bug{}, err
+	}
+
+	return newBug(b.Snapshot()), nil
+}
+
+// Comment add a comment to the bug with the given id, or id prefix, authored
+// by the user identity, and return the updated bug
+func (r *Repo) Comment(id string, message string) (Bug, error) {
+	b, err := r.resolve(id)
+	if err != nil {
+		return Bug{}, err
+	}
+
+	_, err = b.AddComment(message)
+	if err == identity.ErrNoIdentitySet {
+		return Bug{}, ErrNoUser
+	}
+	if err != nil {
+		return Bug{}, err
+	}
+
+	err = b.Commit()
+	if err != nil {
+		return Bug{}, err
+	}
+
+	return newBug(b.Snapshot()), nil
+}
+
+func (r *Repo) resolve(id string) (*cache.BugCache, error) {
+	b, err := r.cache.ResolveBugPrefix(id)
+	if err == bug.ErrBugNotExist {
+		return nil, ErrBugNotFound
+	}
+	if entity.IsErrMultipleMatch(err) {
+		return nil, ErrAmbiguousId
+	}
+	return b, err
+}
@@ -0,0 +1,70 @@
+package gitbug
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestRepo(t *testing.T) {
+	gitRepo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, gitRepo)
+
+	repo, err := OpenRepo(filepath.Dir(gitRepo.GetPath()))
+	require.NoError(t, err)
+
+	_, err = repo.NewBug("title", "message")
+	require.Equal(t, ErrNoUser, err)
+
+	// the user identity is set with the internal packages, as git-bug does
+	// when running "git bug user create"
+	rene, err := repo.cache.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	err = repo.cache.SetUserIdentity(rene)
+	require.NoError(t, err)
+
+	created, err := repo.NewBug(" title ", "message")
+	require.NoError(t, err)
+	require.Equal(t, "title", created.Title)
+	require.Equal(t, "open", created.Status)
+	require.Equal(t, "René Descartes", created.Author.Name)
+	require.Equal(t, rene.Id().String(), created.Author.Id)
+	require.Len(t, created.Comments, 1)
+
+	_, err = repo.NewBug("other", "message")
+	require.NoError(t, err)
+
+	commented, err := repo.Comment(created.HumanId, "comment")
+	require.NoError(t, err)
+	require.Len(t, commented.Comments, 2)
+	require.Equal(t, "comment", commented.Comments[1].Message)
+
+	_, err = repo.Comment("ffffffffff", "comment")
+	require.Equal(t, ErrBugNotFound, err)
+
+	bugs, err := repo.ListBugs()
+	require.NoError(t, err)
+	require.Len(t, bugs, 2)
+
+	bugs, err = repo.Query("title:other")
+	require.NoError(t, err)
+	require.Len(t, bugs, 1)
+	require.Equal(t, "other", bugs[0].Title)
+
+	_, err = repo.Query("nope:nope")
+	require.Error(t, err)
+
+	require.NoError(t, repo.Close())
+
+	// the data is in the repository
+	repo, err = OpenRepo(filepath.Dir(gitRepo.GetPath()))
+	require.NoError(t, err)
+	defer repo.Close()
+
+	b, err := repo.Bug(created.Id)
+	require.NoError(t, err)
+	require.Equal(t, commented, b)
+}
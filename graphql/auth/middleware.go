@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+var ErrTokenRequired = errors.New("an API token is required to modify the repository")
+var ErrReadOnly = errors.New("the repository is served read-only")
+var ErrTokenOtherRepo = errors.New("the API token doesn't grant access to this repository")
+
+type contextKey int
+
+const (
+	tokenContextKey contextKey = iota
+	requiredContextKey
+	readOnlyContextKey
+)
+
+// Middleware return a http middleware authenticating the requests with the
+// API token given in the "Authorization: Bearer <token>" header. A request
+// with an invalid token is rejected. If required is true, the requests
+// without token are accepted but can't modify the repository, see CanMutate.
+func Middleware(repo repository.RepoConfig, required bool) func(http.Handler) http.Handler {
+	return MultiRepoMiddleware(map[string]repository.RepoConfig{"": repo}, required)
+}
+
+// MultiRepoMiddleware is the same as Middleware for a server of several
+// repositories, by name. A token is accepted if it belongs to any of them,
+// but only grant the access to the repositories it belongs to, see
+// CanMutateRepo.
+func MultiRepoMiddleware(repos map[string]repository.RepoConfig, required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if required {
+				ctx = context.WithValue(ctx, requiredContextKey, true)
+			}
+
+			header := r.Header.Get("Authorization")
+			if header != "" {
+				value := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+
+				tokens := make(map[string]*Token)
+				for name, repo := range repos {
+					token, err := Authenticate(repo, value)
+					if err == ErrTokenNotExist {
+						continue
+					}
+					if err != nil {
+						http.Error(rw, err.Error(), http.StatusInternalServerError)
+						return
+					}
+					tokens[name] = token
+				}
+				if len(tokens) == 0 {
+					http.Error(rw, "invalid API token", http.StatusUnauthorized)
+					return
+				}
+
+				ctx = context.WithValue(ctx, tokenContextKey, tokens)
+			}
+
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
+
+// ReadOnlyMiddleware return a http middleware marking all the requests as
+// not allowed to modify the repository, see CanMutate.
+func ReadOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), readOnlyContextKey, true)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+// TokenFromContext return the API token used for a request, if any and if
+// it belongs to the named repository
+func TokenFromContext(ctx context.Context, repo string) (*Token, bool) {
+	tokens, ok := ctx.Value(tokenContextKey).(map[string]*Token)
+	if !ok {
+		return nil, false
+	}
+	token, ok := tokens[repo]
+	return token, ok
+}
+
+// CanMutate tell if a request is allowed to modify the repositories, that is
+// if they are not served read-only and the request carry a valid API token
+// when one is required. The repository modified must also be checked with
+// CanMutateRepo.
+func CanMutate(ctx context.Context) error {
+	if readOnly, _ := ctx.Value(readOnlyContextKey).(bool); readOnly {
+		return ErrReadOnly
+	}
+
+	required, _ := ctx.Value(requiredContextKey).(bool)
+	if !required {
+		return nil
+	}
+
+	if _, ok := ctx.Value(tokenContextKey).(map[string]*Token); !ok {
+		return ErrTokenRequired
+	}
+
+	return nil
+}
+
+// CanMutateRepo tell if a request is allowed to modify the named repository,
+// as CanMutate. The API token of the request, if any, must belong to this
+// repository, even if no token is required.
+func CanMutateRepo(ctx context.Context, repo string) error {
+	if err := CanMutate(ctx); err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Value(tokenContextKey).(map[string]*Token); !ok {
+		return nil
+	}
+
+	if _, ok := TokenFromContext(ctx, repo); !ok {
+		return ErrTokenOtherRepo
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package auth contains the API tokens granting access to the GraphQL and
+// HTTP API, and the http middleware checking them.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// The tokens are stored in the local config of the repository. Only a hash
+// of the token value is stored, its id.
+const (
+	configKeyPrefix     = "git-bug.api-token"
+	configKeyName       = "name"
+	configKeyCreateTime = "createtime"
+)
+
+// prefix of the token values, to make them easy to recognize
+const tokenValuePrefix = "gbt_"
+
+var ErrTokenNotExist = errors.New("API token doesn't exist")
+
+func NewErrMultipleMatchToken(matching []entity.Id) *entity.ErrMultipleMatch {
+	return entity.NewErrMultipleMatch("API token", matching)
+}
+
+// Token is an API token, as stored in the repository
+type Token struct {
+	// the hash of the token value
+	Id         entity.Id
+	Name       string
+	CreateTime time.Time
+}
+
+func hashValue(value string) entity.Id {
+	return entity.Id(fmt.Sprintf("%x", sha256.Sum256([]byte(value))))
+}
+
+// Create generate a new API token and store it in the repository. The value
+// of the token is returned and can't be retrieved later.
+func Create(repo repository.RepoConfig, name string) (*Token, string, error) {
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	value := tokenValuePrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	token := &Token{
+		Id:         hashValue(value),
+		Name:       name,
+		CreateTime: time.Now(),
+	}
+
+	prefix := fmt.Sprintf("%s.%s.", configKeyPrefix, token.Id)
+
+	err = repo.LocalConfig().StoreString(prefix+configKeyName, token.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = repo.LocalConfig().StoreTimestamp(prefix+configKeyCreateTime, token.CreateTime)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return token, value, nil
+}
+
+// List load all the API tokens of the repository, sorted by creation time
+func List(repo repository.RepoConfig) ([]*Token, error) {
+	rawConfigs, err := repo.LocalConfig().ReadAll(configKeyPrefix + ".")
+	if err != nil {
+		return nil, err
+	}
+
+	re := regexp.MustCompile(`^` + regexp.QuoteMeta(configKeyPrefix) + `\.([^.]+)\.([^.]+)$`)
+
+	mapped := make(map[entity.Id]*Token)
+
+	for key, val := range rawConfigs {
+		res := re.FindStringSubmatch(key)
+		if res == nil {
+			continue
+		}
+
+		id := entity.Id(res[1])
+		token, ok := mapped[id]
+		if !ok {
+			token = &Token{Id: id}
+			mapped[id] = token
+		}
+
+		switch res[2] {
+		case configKeyName:
+			token.Name = val
+		case configKeyCreateTime:
+			token.CreateTime, err = repository.ParseTimestamp(val)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tokens := make([]*Token, 0, len(mapped))
+	for _, token := range mapped {
+		tokens = append(tokens, token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreateTime.Before(tokens[j].CreateTime)
+	})
+
+	return tokens, nil
+}
+
+// LoadWithPrefix load an API token from its id prefix
+func LoadWithPrefix(repo repository.RepoConfig, prefix string) (*Token, error) {
+	tokens, err := List(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []*Token
+
+	for _, token := range tokens {
+		if token.Id.HasPrefix(prefix) {
+			matching = append(matching, token)
+		}
+	}
+
+	if len(matching) > 1 {
+		ids := make([]entity.Id, len(matching))
+		for i, token := range matching {
+			ids[i] = token.Id
+		}
+		return nil, NewErrMultipleMatchToken(ids)
+	}
+
+	if len(matching) == 0 {
+		return nil, ErrTokenNotExist
+	}
+
+	return matching[0], nil
+}
+
+// Authenticate return the API token matching a token value
+func Authenticate(repo repository.RepoConfig, value string) (*Token, error) {
+	tokens, err := List(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	id := hashValue(value)
+
+	for _, token := range tokens {
+		if token.Id == id {
+			return token, nil
+		}
+	}
+
+	return nil, ErrTokenNotExist
+}
+
+// Revoke remove an API token from the repository
+func Revoke(repo repository.RepoConfig, id entity.Id) error {
+	keyPrefix := fmt.Sprintf("%s.%s", configKeyPrefix, id)
+	return repo.LocalConfig().RemoveAll(keyPrefix)
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestToken(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	token, value, err := Create(repo, "ci")
+	require.NoError(t, err)
+	require.Equal(t, "ci", token.Name)
+
+	// only the hash is stored
+	configs, err := repo.LocalConfig().ReadAll(configKeyPrefix + ".")
+	require.NoError(t, err)
+	for _, val := range configs {
+		require.NotContains(t, val, value)
+	}
+
+	tokens, err := List(repo)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, token.Id, tokens[0].Id)
+	require.Equal(t, "ci", tokens[0].Name)
+
+	authenticated, err := Authenticate(repo, value)
+	require.NoError(t, err)
+	require.Equal(t, token.Id, authenticated.Id)
+
+	_, err = Authenticate(repo, "gbt_invalid")
+	require.Equal(t, ErrTokenNotExist, err)
+
+	loaded, err := LoadWithPrefix(repo, token.Id.String()[:8])
+	require.NoError(t, err)
+	require.Equal(t, token.Id, loaded.Id)
+
+	err = Revoke(repo, token.Id)
+	require.NoError(t, err)
+
+	_, err = Authenticate(repo, value)
+	require.Equal(t, ErrTokenNotExist, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	_, value, err := Create(repo, "")
+	require.NoError(t, err)
+
+	var canMutate error
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		canMutate = CanMutate(r.Context())
+	})
+
+	serve := func(required bool, header string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/graphql", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		Middleware(repo, required)(next).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve(false, ""))
+	require.NoError(t, canMutate)
+
+	require.Equal(t, http.StatusOK, serve(true, ""))
+	require.Equal(t, ErrTokenRequired, canMutate)
+
+	require.Equal(t, http.StatusOK, serve(true, "Bearer "+value))
+	require.NoError(t, canMutate)
+
+	require.Equal(t, http.StatusUnauthorized, serve(true, "Bearer gbt_invalid"))
+
+	require.NoError(t, CanMutate(context.Background()))
+}
+
+func TestMultiRepoMiddleware(t *testing.T) {
+	repoA := repository.NewMockRepoForTest()
+	repoB := repository.NewMockRepoForTest()
+
+	_, valueA, err := Create(repoA, "")
+	require.NoError(t, err)
+
+	repos := map[string]repository.RepoConfig{"a": repoA, "b": repoB}
+
+	var canMutateA, canMutateB error
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		canMutateA = CanMutateRepo(r.Context(), "a")
+		canMutateB = CanMutateRepo(r.Context(), "b")
+	})
+
+	serve := func(required bool, header string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/graphql", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		MultiRepoMiddleware(repos, required)(next).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// the token of a repository doesn't grant access to the others
+	require.Equal(t, http.StatusOK, serve(true, "Bearer "+valueA))
+	require.NoError(t, canMutateA)
+	require.Equal(t, ErrTokenOtherRepo, canMutateB)
+
+	require.Equal(t, http.StatusOK, serve(false, "Bearer "+valueA))
+	require.NoError(t, canMutateA)
+	require.Equal(t, ErrTokenOtherRepo, canMutateB)
+
+	require.Equal(t, http.StatusOK, serve(true, ""))
+	require.Equal(t, ErrTokenRequired, canMutateA)
+	require.Equal(t, ErrTokenRequired, canMutateB)
+
+	require.Equal(t, http.StatusOK, serve(false, ""))
+	require.NoError(t, canMutateA)
+	require.NoError(t, canMutateB)
+}
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	_, value, err := Create(repo, "")
+	require.NoError(t, err)
+
+	var canMutate error
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		canMutate = CanMutate(r.Context())
+	})
+
+	// even with a valid token
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer "+value)
+	Middleware(repo, true)(ReadOnlyMiddleware(next)).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, ErrReadOnly, canMutate)
+}
@@ -1,6 +1,9 @@
 package connections
 
-import "github.com/MichaelMure/git-bug/entity"
+import (
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+)
 
 // LazyBugEdge is a special relay edge used to implement a lazy loading connection
 type LazyBugEdge struct {
@@ -23,3 +26,15 @@ type LazyIdentityEdge struct {
 func (lbe LazyIdentityEdge) GetCursor() string {
 	return lbe.Cursor
 }
+
+// LazyRepoBugEdge is a special relay edge used to implement a lazy loading
+// connection of the bugs of several repositories
+type LazyRepoBugEdge struct {
+	cache.RepoBug
+	Cursor string
+}
+
+// GetCursor return the cursor of a LazyRepoBugEdge
+func (lbe LazyRepoBugEdge) GetCursor() string {
+	return lbe.Cursor
+}
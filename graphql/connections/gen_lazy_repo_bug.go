@@ -0,0 +1,113 @@
+// This file was automatically generated by genny.
+// Any changes will be lost if this file is regenerated.
+// see https://github.com/cheekybits/genny
+
+package connections
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/graphql/models"
+)
+
+// CacheRepoBugEdgeMaker define a function that take a cache.RepoBug and an offset and
+// create an Edge.
+type LazyRepoBugEdgeMaker func(value cache.RepoBug, offset int) Edge
+
+// LazyRepoBugConMaker define a function that create a models.BugConnection
+type LazyRepoBugConMaker func(
+	edges []*LazyRepoBugEdge,
+	nodes []cache.RepoBug,
+	info *models.PageInfo,
+	totalCount int) (*models.BugConnection, error)
+
+// LazyRepoBugCon will paginate a source according to the input of a relay connection
+func LazyRepoBugCon(source []cache.RepoBug, edgeMaker LazyRepoBugEdgeMaker, conMaker LazyRepoBugConMaker, input models.ConnectionInput) (*models.BugConnection, error) {
+	var nodes []cache.RepoBug
+	var edges []*LazyRepoBugEdge
+	var cursors []string
+	var pageInfo = &models.PageInfo{}
+	var totalCount = len(source)
+
+	emptyCon, _ := conMaker(edges, nodes, pageInfo, 0)
+
+	offset := 0
+
+	if input.After != nil {
+		for i, value := range source {
+			edge := edgeMaker(value, i)
+			if edge.GetCursor() == *input.After {
+				// remove all previous element including the "after" one
+				source = source[i+1:]
+				offset = i + 1
+				pageInfo.HasPreviousPage = true
+				break
+			}
+		}
+	}
+
+	if input.Before != nil {
+		for i, value := range source {
+			edge := edgeMaker(value, i+offset)
+
+			if edge.GetCursor() == *input.Before {
+				// remove all after element including the "before" one
+				pageInfo.HasNextPage = true
+				break
+			}
+
+			e := edge.(LazyRepoBugEdge)
+			edges = append(edges, &e)
+			cursors = append(cursors, edge.GetCursor())
+			nodes = append(nodes, value)
+		}
+	} else {
+		edges = make([]*LazyRepoBugEdge, len(source))
+		cursors = make([]string, len(source))
+		nodes = source
+
+		for i, value := range source {
+			edge := edgeMaker(value, i+offset)
+			e := edge.(LazyRepoBugEdge)
+			edges[i] = &e
+			cursors[i] = edge.GetCursor()
+		}
+	}
+
+	if input.First != nil {
+		if *input.First < 0 {
+			return emptyCon, fmt.Errorf("first less than zero")
+		}
+
+		if len(edges) > *input.First {
+			// Slice result to be of length first by removing edges from the end
+			edges = edges[:*input.First]
+			cursors = cursors[:*input.First]
+			nodes = nodes[:*input.First]
+			pageInfo.HasNextPage = true
+		}
+	}
+
+	if input.Last != nil {
+		if *input.Last < 0 {
+			return emptyCon, fmt.Errorf("last less than zero")
+		}
+
+		if len(edges) > *input.Last {
+			// Slice result to be of length last by removing edges from the start
+			edges = edges[len(edges)-*input.Last:]
+			cursors = cursors[len(cursors)-*input.Last:]
+			nodes = nodes[len(nodes)-*input.Last:]
+			pageInfo.HasPreviousPage = true
+		}
+	}
+
+	// Fill up pageInfo cursors
+	if len(cursors) > 0 {
+		pageInfo.StartCursor = cursors[0]
+		pageInfo.EndCursor = cursors[len(cursors)-1]
+	}
+
+	return conMaker(edges, nodes, pageInfo, totalCount)
+}
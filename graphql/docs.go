@@ -0,0 +1,190 @@
+package graphql
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/ast"
+
+	"github.com/MichaelMure/git-bug/graphql/graph"
+)
+
+// NewDocsHandler return a http handler serving a HTML documentation of the
+// GraphQL schema, generated from the schema itself and its descriptions.
+func NewDocsHandler() (http.Handler, error) {
+	schema := graph.NewExecutableSchema(graph.Config{}).Schema()
+
+	var buf bytes.Buffer
+	err := docsTemplate.Execute(&buf, newSchemaDocs(schema))
+	if err != nil {
+		return nil, err
+	}
+
+	page := buf.Bytes()
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = rw.Write(page)
+	}), nil
+}
+
+type schemaDocs struct {
+	Query    *ast.Definition
+	Mutation *ast.Definition
+	Sections []docsSection
+}
+
+type docsSection struct {
+	Title string
+	Types []*ast.Definition
+}
+
+func newSchemaDocs(schema *ast.Schema) schemaDocs {
+	docs := schemaDocs{
+		Query:    schema.Query,
+		Mutation: schema.Mutation,
+	}
+
+	byKind := make(map[ast.DefinitionKind][]*ast.Definition)
+
+	for _, def := range schema.Types {
+		if def.BuiltIn || strings.HasPrefix(def.Name, "__") {
+			continue
+		}
+		if def == schema.Query || def == schema.Mutation {
+			continue
+		}
+		byKind[def.Kind] = append(byKind[def.Kind], def)
+	}
+
+	sections := []struct {
+		kind  ast.DefinitionKind
+		title string
+	}{
+		{ast.Object, "Objects"},
+		{ast.Interface, "Interfaces"},
+		{ast.Union, "Unions"},
+		{ast.InputObject, "Input objects"},
+		{ast.Enum, "Enums"},
+		{ast.Scalar, "Scalars"},
+	}
+
+	for _, section := range sections {
+		types := byKind[section.kind]
+		if len(types) == 0 {
+			continue
+		}
+		sort.Slice(types, func(i, j int) bool {
+			return types[i].Name < types[j].Name
+		})
+		docs.Sections = append(docs.Sections, docsSection{
+			Title: section.title,
+			Types: types,
+		})
+	}
+
+	return docs
+}
+
+// typeRef render a type reference like GraphQL does ("[Bug!]!"), with the
+// named type linking to its documentation
+func typeRef(t *ast.Type) template.HTML {
+	var inner string
+	if t.Elem != nil {
+		inner = "[" + string(typeRef(t.Elem)) + "]"
+	} else {
+		name := template.HTMLEscapeString(t.NamedType)
+		inner = `<a href="#` + name + `">` + name + `</a>`
+	}
+	if t.NonNull {
+		inner += "!"
+	}
+	return template.HTML(inner)
+}
+
+var docsTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"typeRef": typeRef,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>git-bug GraphQL API</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 0 auto; padding: 1em; color: #333; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 0.2em; margin-top: 2em; }
+h3 { margin-top: 1.5em; }
+a { color: #0366d6; text-decoration: none; }
+code, .field { font-family: monospace; }
+.kind { color: #888; font-size: 0.8em; font-weight: normal; }
+.description { white-space: pre-wrap; }
+dt { margin-top: 0.6em; }
+dd { margin-left: 2em; color: #555; }
+</style>
+</head>
+<body>
+<h1>git-bug GraphQL API</h1>
+<p>The API is served at <a href="../graphql"><code>graphql</code></a>, next to this page.</p>
+<ul>
+{{- if .Query}}<li><a href="#{{.Query.Name}}">{{.Query.Name}}</a></li>{{end}}
+{{- if .Mutation}}<li><a href="#{{.Mutation.Name}}">{{.Mutation.Name}}</a></li>{{end}}
+{{- range .Sections}}<li>{{.Title}}: {{range $i, $t := .Types}}{{if $i}}, {{end}}<a href="#{{$t.Name}}">{{$t.Name}}</a>{{end}}</li>{{end}}
+</ul>
+{{- if .Query}}
+<h2>Query</h2>
+{{template "type" .Query}}
+{{- end}}
+{{- if .Mutation}}
+<h2>Mutation</h2>
+{{template "type" .Mutation}}
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- range .Types}}
+{{template "type" .}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+{{define "type"}}
+<h3 id="{{.Name}}">{{.Name}} <span class="kind">{{.Kind}}</span></h3>
+{{- if .Description}}
+<p class="description">{{.Description}}</p>
+{{- end}}
+{{- if .Interfaces}}
+<p>Implements: {{range $i, $name := .Interfaces}}{{if $i}}, {{end}}<a href="#{{$name}}">{{$name}}</a>{{end}}</p>
+{{- end}}
+{{- if .Types}}
+<p>Possible types: {{range $i, $name := .Types}}{{if $i}}, {{end}}<a href="#{{$name}}">{{$name}}</a>{{end}}</p>
+{{- end}}
+{{- if .Fields}}
+<dl>
+{{- range .Fields}}
+{{- if not (eq (printf "%.2s" .Name) "__")}}
+<dt class="field">{{.Name}}
+{{- if .Arguments}}({{range $i, $arg := .Arguments}}{{if $i}}, {{end}}{{$arg.Name}}: {{typeRef $arg.Type}}{{if $arg.DefaultValue}} = {{$arg.DefaultValue.String}}{{end}}{{end}}){{end}}: {{typeRef .Type}}
+{{- if .DefaultValue}} = {{.DefaultValue.String}}{{end}}</dt>
+{{- if .Description}}
+<dd class="description">{{.Description}}</dd>
+{{- end}}
+{{- range .Arguments}}{{if .Description}}
+<dd><code>{{.Name}}</code>: {{.Description}}</dd>
+{{- end}}{{end}}
+{{- end}}
+{{- end}}
+</dl>
+{{- end}}
+{{- if .EnumValues}}
+<dl>
+{{- range .EnumValues}}
+<dt class="field">{{.Name}}</dt>
+{{- if .Description}}
+<dd class="description">{{.Description}}</dd>
+{{- end}}
+{{- end}}
+</dl>
+{{- end}}
+{{- end}}
+`))
@@ -0,0 +1,27 @@
+package graphql
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsHandler(t *testing.T) {
+	handler, err := NewDocsHandler()
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/graphql/docs", nil))
+
+	require.Equal(t, 200, rec.Code)
+
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	require.Contains(t, string(body), `<h3 id="Repository">Repository`)
+	require.Contains(t, string(body), `<a href="#Bug">Bug</a>`)
+	require.Contains(t, string(body), `allBugs`)
+	require.NotContains(t, string(body), `__Schema`)
+}
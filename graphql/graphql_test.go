@@ -1,9 +1,11 @@
 package graphql
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/99designs/gqlgen/client"
+	"github.com/stretchr/testify/require"
 
 	"github.com/MichaelMure/git-bug/graphql/models"
 	"github.com/MichaelMure/git-bug/misc/random_bugs"
@@ -213,3 +215,364 @@ func TestQueries(t *testing.T) {
 
 	c.MustPost(query, &resp)
 }
+
+func TestExpectedOperationCount(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	handler, err := NewHandler(repo)
+	require.NoError(t, err)
+
+	backend, err := handler.DefaultRepo()
+	require.NoError(t, err)
+
+	rene, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b, _, err := backend.NewBug("title", "message")
+	require.NoError(t, err)
+
+	c := client.New(handler)
+
+	query := `
+      mutation($prefix: String!, $count: Int) {
+        addComment(input: {prefix: $prefix, message: "comment", expectedOperationCount: $count}) {
+          bug { id }
+        }
+      }`
+
+	var resp struct {
+		AddComment struct{ Bug struct{ Id string } }
+	}
+
+	err = c.Post(query, &resp, client.Var("prefix", b.Id().String()), client.Var("count", 1))
+	require.NoError(t, err)
+
+	// the bug has two operations now
+	err = c.Post(query, &resp, client.Var("prefix", b.Id().String()), client.Var("count", 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "CONFLICT")
+	require.Len(t, b.Snapshot().Operations, 2)
+}
+
+func TestCommentThreads(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	handler, err := NewHandler(repo)
+	require.NoError(t, err)
+
+	backend, err := handler.DefaultRepo()
+	require.NoError(t, err)
+
+	rene, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b, create, err := backend.NewBug("title", "message")
+	require.NoError(t, err)
+
+	c := client.New(handler)
+
+	mutation := `
+      mutation($prefix: String!, $replyTo: String) {
+        addComment(input: {prefix: $prefix, message: "reply", replyTo: $replyTo}) {
+          operation { replyTo }
+        }
+      }`
+
+	var mutationResp struct {
+		AddComment struct{ Operation struct{ ReplyTo *string } }
+	}
+
+	err = c.Post(mutation, &mutationResp,
+		client.Var("prefix", b.Id().String()), client.Var("replyTo", create.Id().Human()))
+	require.NoError(t, err)
+	require.NotNil(t, mutationResp.AddComment.Operation.ReplyTo)
+	require.Equal(t, create.Id().String(), *mutationResp.AddComment.Operation.ReplyTo)
+
+	err = c.Post(mutation, &mutationResp,
+		client.Var("prefix", b.Id().String()), client.Var("replyTo", "ffffffff"))
+	require.Error(t, err)
+
+	query := `
+      query($prefix: String!) {
+        repository {
+          bug(prefix: $prefix) {
+            threads {
+              comment { id replyTo }
+              replies { comment { message replyTo } }
+            }
+          }
+        }
+      }`
+
+	var resp struct {
+		Repository struct {
+			Bug struct {
+				Threads []struct {
+					Comment struct {
+						Id      string
+						ReplyTo *string
+					}
+					Replies []struct {
+						Comment struct {
+							Message string
+							ReplyTo *string
+						}
+					}
+				}
+			}
+		}
+	}
+
+	c.MustPost(query, &resp, client.Var("prefix", b.Id().String()))
+
+	threads := resp.Repository.Bug.Threads
+	require.Len(t, threads, 1)
+	require.Equal(t, create.Id().String(), threads[0].Comment.Id)
+	require.Nil(t, threads[0].Comment.ReplyTo)
+	require.Len(t, threads[0].Replies, 1)
+	require.Equal(t, "reply", threads[0].Replies[0].Comment.Message)
+}
+
+func TestIdentityActivity(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	handler, err := NewHandler(repo)
+	require.NoError(t, err)
+
+	backend, err := handler.DefaultRepo()
+	require.NoError(t, err)
+
+	rene, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b, _, err := backend.NewBug("title", "message")
+	require.NoError(t, err)
+	_, err = b.AddComment("comment")
+	require.NoError(t, err)
+
+	c := client.New(handler)
+
+	query := `
+      query($prefix: String!) {
+        repository {
+          identityActivity(prefix: $prefix) {
+            identity { displayName }
+            authoredBugs(first: 10) { totalCount nodes { title } }
+            commentedBugs { totalCount }
+            involvedCount
+            lastActivity
+            recentOperations(first: 1) {
+              bug { humanId }
+              operation { __typename }
+            }
+          }
+        }
+      }`
+
+	var resp struct {
+		Repository struct {
+			IdentityActivity struct {
+				Identity     struct{ DisplayName string }
+				AuthoredBugs struct {
+					TotalCount int
+					Nodes      []struct{ Title string }
+				}
+				CommentedBugs    struct{ TotalCount int }
+				InvolvedCount    int
+				LastActivity     *string
+				RecentOperations []struct {
+					Bug       struct{ HumanId string }
+					Operation struct {
+						Typename string `json:"__typename"`
+					}
+				}
+			}
+		}
+	}
+
+	c.MustPost(query, &resp, client.Var("prefix", rene.Id().String()))
+
+	activity := resp.Repository.IdentityActivity
+	require.Equal(t, "René Descartes", activity.Identity.DisplayName)
+	require.Equal(t, 1, activity.AuthoredBugs.TotalCount)
+	require.Equal(t, "title", activity.AuthoredBugs.Nodes[0].Title)
+	require.Equal(t, 0, activity.CommentedBugs.TotalCount)
+	require.Equal(t, 1, activity.InvolvedCount)
+	require.NotNil(t, activity.LastActivity)
+	require.Len(t, activity.RecentOperations, 1)
+	require.Equal(t, b.Id().Human(), activity.RecentOperations[0].Bug.HumanId)
+}
+
+func TestLabelRegistry(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	handler, err := NewHandler(repo)
+	require.NoError(t, err)
+
+	backend, err := handler.DefaultRepo()
+	require.NoError(t, err)
+
+	rene, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	b, _, err := backend.NewBug("title", "message")
+	require.NoError(t, err)
+	_, _, err = b.ChangeLabels([]string{"ui"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	c := client.New(handler)
+
+	var created struct {
+		CreateLabel struct {
+			Label struct {
+				Label struct {
+					Name  string
+					Color struct{ R, G, B int }
+				}
+				Registered bool
+				Count      int
+			}
+		}
+	}
+	c.MustPost(`mutation {
+        createLabel(input: {name: "frontend", color: "#0000ff"}) {
+          label { label { name color { R G B } } registered count }
+        }
+      }`, &created)
+	require.Equal(t, "frontend", created.CreateLabel.Label.Label.Name)
+	require.Equal(t, 255, created.CreateLabel.Label.Label.Color.B)
+	require.True(t, created.CreateLabel.Label.Registered)
+	require.Equal(t, 0, created.CreateLabel.Label.Count)
+
+	var merged struct {
+		MergeLabel struct {
+			Label struct{ Count int }
+			Bugs  []struct {
+				Labels []struct {
+					Name  string
+					Color struct{ B int }
+				}
+			}
+		}
+	}
+	c.MustPost(`mutation {
+        mergeLabel(input: {name: "ui", into: "frontend"}) {
+          label { count }
+          bugs { labels { name color { B } } }
+        }
+      }`, &merged)
+	require.Equal(t, 1, merged.MergeLabel.Label.Count)
+	require.Len(t, merged.MergeLabel.Bugs, 1)
+	require.Equal(t, "frontend", merged.MergeLabel.Bugs[0].Labels[0].Name)
+	// the color of the label registry apply everywhere
+	require.Equal(t, 255, merged.MergeLabel.Bugs[0].Labels[0].Color.B)
+
+	var labels struct {
+		Repository struct {
+			Labels []struct {
+				Label      struct{ Name string }
+				Registered bool
+				Count      int
+			}
+		}
+	}
+	c.MustPost(`query { repository { labels { label { name } registered count } } }`, &labels)
+	require.Len(t, labels.Repository.Labels, 1)
+	require.Equal(t, "frontend", labels.Repository.Labels[0].Label.Name)
+	require.Equal(t, 1, labels.Repository.Labels[0].Count)
+
+	err = c.Post(`mutation { renameLabel(input: {name: "frontend", newName: ""}) { label { count } } }`, &struct{}{})
+	require.Error(t, err)
+}
+
+func TestMultiRepo(t *testing.T) {
+	repoA := repository.CreateTestRepo(false)
+	repoB := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repoA, repoB)
+
+	random_bugs.FillRepoWithSeed(repoA, 10, 42)
+	random_bugs.FillRepoWithSeed(repoB, 5, 43)
+
+	handler, err := NewMultiRepoHandler([]NamedRepo{
+		{Name: "a", Repo: repoA},
+		{Name: "b", Repo: repoB},
+	})
+	require.NoError(t, err)
+	defer handler.Close()
+
+	c := client.New(handler)
+
+	var resp struct {
+		Repositories []struct{ Name string }
+		AllBugs      struct {
+			TotalCount int
+			Nodes      []struct {
+				QualifiedId string
+				Repository  struct{ Name string }
+			}
+		}
+		Repository *struct{ Name string }
+	}
+
+	err = c.Post(`
+      query {
+        repositories { name }
+        allBugs(first: 20) {
+          totalCount
+          nodes {
+            qualifiedId
+            repository { name }
+          }
+        }
+        repository { name }
+      }`, &resp)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Repositories, 2)
+	require.Equal(t, "a", resp.Repositories[0].Name)
+	require.Equal(t, "b", resp.Repositories[1].Name)
+	require.Equal(t, 15, resp.AllBugs.TotalCount)
+	require.Len(t, resp.AllBugs.Nodes, 15)
+	// there is no default repository
+	require.Nil(t, resp.Repository)
+
+	node := resp.AllBugs.Nodes[0]
+	require.True(t, strings.HasPrefix(node.QualifiedId, node.Repository.Name+"/"))
+
+	var bugResp struct {
+		Bug struct {
+			QualifiedId string
+		}
+	}
+
+	err = c.Post(`
+      query($id: String!) {
+        bug(qualifiedId: $id) { qualifiedId }
+      }`, &bugResp, client.Var("id", node.QualifiedId))
+	require.NoError(t, err)
+	require.Equal(t, node.QualifiedId, bugResp.Bug.QualifiedId)
+
+	var searchResp struct {
+		Search []struct {
+			QualifiedId string
+		}
+	}
+
+	// the search cover all the repositories, the id match first
+	err = c.Post(`
+      query($text: String!) {
+        search(text: $text, first: 3) { qualifiedId }
+      }`, &searchResp, client.Var("text", strings.TrimPrefix(node.QualifiedId, node.Repository.Name+"/")))
+	require.NoError(t, err)
+	require.NotEmpty(t, searchResp.Search)
+	require.Equal(t, node.QualifiedId, searchResp.Search[0].QualifiedId)
+}
@@ -4,10 +4,14 @@
 package graphql
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/vektah/gqlparser/ast"
 
+	"github.com/MichaelMure/git-bug/graphql/auth"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/resolvers"
 	"github.com/MichaelMure/git-bug/repository"
@@ -29,11 +33,56 @@ func NewHandler(repo repository.ClockedRepo) (Handler, error) {
 		return Handler{}, err
 	}
 
+	return h.serve(), nil
+}
+
+// NamedRepo is a repository served under a name by a multi-repository
+// Handler
+type NamedRepo struct {
+	Name string
+	Repo repository.ClockedRepo
+}
+
+// NewMultiRepoHandler create a Handler serving several repositories, each
+// under its name
+func NewMultiRepoHandler(repos []NamedRepo) (Handler, error) {
+	h := Handler{
+		RootResolver: resolvers.NewRootResolver(),
+	}
+
+	for _, r := range repos {
+		err := h.RootResolver.RegisterRepository(r.Name, r.Repo)
+		if err != nil {
+			_ = h.RootResolver.Close()
+			return Handler{}, err
+		}
+	}
+
+	return h.serve(), nil
+}
+
+func (h Handler) serve() Handler {
 	config := graph.Config{
 		Resolvers: h.RootResolver,
 	}
 
-	h.Handler = handler.NewDefaultServer(graph.NewExecutableSchema(config))
+	srv := handler.NewDefaultServer(graph.NewExecutableSchema(config))
+	srv.AroundOperations(checkMutationToken)
+
+	h.Handler = srv
+
+	return h
+}
+
+// checkMutationToken reject the mutations made without the API token
+// required by the http server, if any
+func checkMutationToken(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	op := graphql.GetOperationContext(ctx).Operation
+	if op != nil && op.Operation == ast.Mutation {
+		if err := auth.CanMutate(ctx); err != nil {
+			return graphql.OneShot(graphql.ErrorResponse(ctx, err.Error()))
+		}
+	}
 
-	return h, nil
+	return next(ctx)
 }
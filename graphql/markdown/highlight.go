@@ -0,0 +1,218 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// language describe the lexical elements of a language, enough to highlight
+// the keywords, the comments, the strings and the numbers of a code block
+type language struct {
+	keywords map[string]bool
+	// the prefixes of the comments running to the end of the line
+	lineComments []string
+	// the delimiters of the comments spanning several lines, if any
+	blockComment [2]string
+	// the quotes of the strings
+	quotes string
+}
+
+func newLanguage(keywords string, lineComments []string, blockComment [2]string, quotes string) *language {
+	l := &language{
+		keywords:     make(map[string]bool),
+		lineComments: lineComments,
+		blockComment: blockComment,
+		quotes:       quotes,
+	}
+	for _, keyword := range strings.Fields(keywords) {
+		l.keywords[keyword] = true
+	}
+	return l
+}
+
+var cBlockComment = [2]string{"/*", "*/"}
+
+var (
+	golang = newLanguage(`break case chan const continue default defer else fallthrough for func go goto if
+		import interface map package range return select struct switch type var true false nil iota`,
+		[]string{"//"}, cBlockComment, "\"'`")
+	javascript = newLanguage(`async await break case catch class const continue debugger default delete do else
+		export extends finally for from function if import in instanceof interface let new of return static super
+		switch this throw try type typeof var void while with yield true false null undefined`,
+		[]string{"//"}, cBlockComment, "\"'`")
+	python = newLanguage(`and as assert async await break class continue def del elif else except finally for
+		from global if import in is lambda nonlocal not or pass raise return try while with yield True False None`,
+		[]string{"#"}, [2]string{}, "\"'")
+	shell = newLanguage(`if then else elif fi case esac for while until do done in function return local
+		export exit`,
+		[]string{"#"}, [2]string{}, "\"'")
+	clang = newLanguage(`auto break case char const continue default do double else enum extern float for goto
+		if inline int long register return short signed sizeof static struct switch typedef union unsigned void
+		volatile while class namespace public private protected template typename virtual new delete true false
+		nullptr NULL`,
+		[]string{"//"}, cBlockComment, "\"'")
+	java = newLanguage(`abstract boolean break byte case catch char class const continue default do double
+		else enum extends final finally float for if implements import instanceof int interface long native new
+		package private protected public return short static super switch synchronized this throw throws try void
+		volatile while true false null`,
+		[]string{"//"}, cBlockComment, "\"'")
+	rust = newLanguage(`as async await break const continue crate dyn else enum extern fn for if impl in let
+		loop match mod move mut pub ref return self Self static struct super trait type unsafe use where while
+		true false`,
+		[]string{"//"}, cBlockComment, "\"")
+	json = newLanguage(`true false null`, nil, [2]string{}, "\"")
+	sql  = newLanguage(`select from where and or not insert into values update set delete create table drop
+		alter index join left right inner outer on group by order having limit as null is in like
+		SELECT FROM WHERE AND OR NOT INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE DROP ALTER INDEX JOIN LEFT
+		RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT AS NULL IS IN LIKE`,
+		[]string{"--"}, cBlockComment, "'\"")
+)
+
+// the languages highlighted, by the names used in the info string of the
+// code blocks
+var languages = map[string]*language{
+	"go":         golang,
+	"golang":     golang,
+	"js":         javascript,
+	"javascript": javascript,
+	"jsx":        javascript,
+	"ts":         javascript,
+	"typescript": javascript,
+	"tsx":        javascript,
+	"py":         python,
+	"python":     python,
+	"sh":         shell,
+	"bash":       shell,
+	"shell":      shell,
+	"zsh":        shell,
+	"c":          clang,
+	"h":          clang,
+	"cpp":        clang,
+	"c++":        clang,
+	"java":       java,
+	"kotlin":     java,
+	"rust":       rust,
+	"rs":         rust,
+	"json":       json,
+	"sql":        sql,
+}
+
+// highlight return the code as escaped HTML, with the tokens of the language,
+// if known, wrapped in a span of the hl-keyword, hl-comment, hl-string or
+// hl-number class
+func highlight(lang string, code string) string {
+	l, ok := languages[lang]
+	if !ok {
+		return html.EscapeString(code)
+	}
+
+	var b strings.Builder
+	span := func(class string, token string) {
+		b.WriteString(`<span class="hl-`)
+		b.WriteString(class)
+		b.WriteString(`">`)
+		b.WriteString(html.EscapeString(token))
+		b.WriteString("</span>")
+	}
+
+	for i := 0; i < len(code); {
+		rest := code[i:]
+
+		if n := l.comment(rest); n > 0 {
+			span("comment", rest[:n])
+			i += n
+			continue
+		}
+
+		if strings.IndexByte(l.quotes, rest[0]) >= 0 {
+			n := stringLength(rest)
+			span("string", rest[:n])
+			i += n
+			continue
+		}
+
+		r := rune(rest[0])
+		if unicode.IsDigit(r) {
+			n := wordLength(rest)
+			span("number", rest[:n])
+			i += n
+			continue
+		}
+
+		if isWordStart(r) {
+			n := wordLength(rest)
+			if l.keywords[rest[:n]] {
+				span("keyword", rest[:n])
+			} else {
+				b.WriteString(html.EscapeString(rest[:n]))
+			}
+			i += n
+			continue
+		}
+
+		b.WriteString(html.EscapeString(rest[:1]))
+		i++
+	}
+
+	return b.String()
+}
+
+// comment return the length of the comment starting the text, 0 if none
+func (l *language) comment(text string) int {
+	for _, prefix := range l.lineComments {
+		if strings.HasPrefix(text, prefix) {
+			if end := strings.IndexByte(text, '\n'); end >= 0 {
+				return end
+			}
+			return len(text)
+		}
+	}
+
+	if l.blockComment[0] != "" && strings.HasPrefix(text, l.blockComment[0]) {
+		start := len(l.blockComment[0])
+		if end := strings.Index(text[start:], l.blockComment[1]); end >= 0 {
+			return start + end + len(l.blockComment[1])
+		}
+		return len(text)
+	}
+
+	return 0
+}
+
+// stringLength return the length of the string literal starting the text, up
+// to the closing quote, the end of the line for an unterminated one, or the
+// end of the text
+func stringLength(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			// only the backquoted strings span several lines
+			if quote != '`' {
+				return i
+			}
+		}
+	}
+	return len(text)
+}
+
+func isWordStart(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// wordLength return the length of the identifier or number starting the text
+func wordLength(text string) int {
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if !(c == '_' || c == '.' && unicode.IsDigit(rune(text[0])) ||
+			c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return i
+		}
+	}
+	return len(text)
+}
@@ -0,0 +1,133 @@
+// Package markdown render the markdown of the bugs and comments to HTML for
+// the web UI, sanitized to be safe to inject in a page.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+// the schemes of the links and images rendered, the others being rendered as
+// plain text
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// ToHTML render a markdown text to HTML. The raw HTML of the text is escaped
+// rather than rendered, the links and images only keep the destinations
+// without a scheme or with a web one, and the code blocks of a known language
+// are highlighted with the hl-* classes.
+func ToHTML(source string) string {
+	r := &renderer{
+		HTMLRenderer: blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+			Flags: blackfriday.NofollowLinks | blackfriday.NoreferrerLinks |
+				blackfriday.HrefTargetBlank | blackfriday.UseXHTML,
+		}),
+	}
+
+	// blackfriday expect \n line endings
+	source = strings.Replace(source, "\r\n", "\n", -1)
+
+	out := blackfriday.Run([]byte(source),
+		blackfriday.WithExtensions(blackfriday.CommonExtensions|blackfriday.HardLineBreak),
+		blackfriday.WithRenderer(r),
+	)
+	return string(out)
+}
+
+// renderer is the HTML renderer of blackfriday, sanitizing what it can't
+type renderer struct {
+	*blackfriday.HTMLRenderer
+}
+
+func (r *renderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	switch node.Type {
+	case blackfriday.HTMLBlock:
+		_, _ = io.WriteString(w, "<p>")
+		_, _ = io.WriteString(w, html.EscapeString(strings.TrimSpace(string(node.Literal))))
+		_, _ = io.WriteString(w, "</p>\n")
+		return blackfriday.GoToNext
+
+	case blackfriday.HTMLSpan:
+		_, _ = io.WriteString(w, html.EscapeString(string(node.Literal)))
+		return blackfriday.GoToNext
+
+	case blackfriday.Link:
+		if !isAllowedDestination(node.LinkData.Destination) {
+			// only the text of the link is rendered
+			return blackfriday.GoToNext
+		}
+
+	case blackfriday.Image:
+		if !isAllowedDestination(node.LinkData.Destination) {
+			if entering {
+				// the alternative text, rendered as text
+				for child := node.FirstChild; child != nil; child = child.Next {
+					_, _ = io.WriteString(w, html.EscapeString(string(child.Literal)))
+				}
+			}
+			return blackfriday.SkipChildren
+		}
+		if entering {
+			// as on the forges, an image links to itself
+			_, _ = io.WriteString(w, `<a href="`)
+			_, _ = io.WriteString(w, html.EscapeString(string(node.LinkData.Destination)))
+			_, _ = io.WriteString(w, `" target="_blank" rel="nofollow noreferrer">`)
+			return r.HTMLRenderer.RenderNode(w, node, entering)
+		}
+		status := r.HTMLRenderer.RenderNode(w, node, entering)
+		_, _ = io.WriteString(w, "</a>")
+		return status
+
+	case blackfriday.CodeBlock:
+		lang := codeLanguage(node.Info)
+		var buf bytes.Buffer
+		buf.WriteString("<pre><code")
+		if lang != "" {
+			buf.WriteString(` class="language-`)
+			buf.WriteString(lang)
+			buf.WriteString(`"`)
+		}
+		buf.WriteString(">")
+		buf.WriteString(highlight(lang, string(node.Literal)))
+		buf.WriteString("</code></pre>\n")
+		_, _ = w.Write(buf.Bytes())
+		return blackfriday.GoToNext
+	}
+
+	return r.HTMLRenderer.RenderNode(w, node, entering)
+}
+
+// isAllowedDestination tell if a link or image destination can be rendered:
+// a relative one, or one with an allowed scheme
+func isAllowedDestination(dest []byte) bool {
+	u, err := url.Parse(strings.TrimSpace(string(dest)))
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" || allowedSchemes[strings.ToLower(u.Scheme)]
+}
+
+// codeLanguage return the language of a code block from its info string, as
+// a lowercase identifier safe to use in a class name
+func codeLanguage(info []byte) string {
+	fields := strings.Fields(string(info))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	lang := strings.ToLower(fields[0])
+	for _, r := range lang {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '+' || r == '-' || r == '#') {
+			return ""
+		}
+	}
+	return lang
+}
@@ -0,0 +1,58 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHTML(t *testing.T) {
+	out := ToHTML("# Title\n\nsome *emphasis* and a [link](https://git-bug.io)")
+	require.Contains(t, out, "<h1>Title</h1>")
+	require.Contains(t, out, "<em>emphasis</em>")
+	require.Contains(t, out, `<a href="https://git-bug.io" target="_blank" rel="nofollow noreferrer">link</a>`)
+}
+
+func TestToHTMLSanitize(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"html block", "<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"html span", "text <img src=x onerror=alert(1)> text", "text &lt;img src=x onerror=alert(1)&gt; text"},
+		{"javascript link", "[click](javascript:alert%281%29)", "<p>click</p>"},
+		{"data link", "[click](data:text/html;base64,PHNjcmlwdD4=)", "<p>click</p>"},
+		{"javascript image", "![alt](javascript:alert%281%29)", "<p>alt</p>"},
+		{"quote in destination", `[click](https://a.b/"onmouseover="x)`, `href="https://a.b/&quot;onmouseover=&quot;x"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := ToHTML(c.source)
+			assert.Contains(t, out, c.expected)
+			assert.NotContains(t, out, "<script")
+			assert.NotContains(t, out, "javascript:")
+			assert.NotContains(t, out, "<img src=x")
+		})
+	}
+}
+
+func TestToHTMLImage(t *testing.T) {
+	out := ToHTML("![screenshot](/gitfile/default/abcd)")
+	require.Contains(t, out, `<a href="/gitfile/default/abcd" target="_blank" rel="nofollow noreferrer"><img src="/gitfile/default/abcd" alt="screenshot" /></a>`)
+}
+
+func TestToHTMLHighlight(t *testing.T) {
+	out := ToHTML("```go\n// add\nfunc add(a int) string { return \"<b>\" + 1 }\n```")
+	require.Contains(t, out, `<pre><code class="language-go">`)
+	require.Contains(t, out, `<span class="hl-comment">// add</span>`)
+	require.Contains(t, out, `<span class="hl-keyword">func</span> add(a int)`)
+	require.Contains(t, out, `<span class="hl-string">&#34;&lt;b&gt;&#34;</span>`)
+	require.Contains(t, out, `<span class="hl-number">1</span>`)
+
+	// an unknown language is only escaped, and its name sanitized
+	out = ToHTML("```\"><script>\n<b>\n```")
+	require.Contains(t, out, "<pre><code>&lt;b&gt;\n</code></pre>")
+}
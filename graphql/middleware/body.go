@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// MaxBodySize return a http middleware limiting the size of the request
+// bodies. A request announcing a bigger body is rejected with "413 Request
+// Entity Too Large", reading past the limit fail otherwise.
+func MaxBodySize(size int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > size {
+				msg := fmt.Sprintf("request body too big (%d bytes max)", size)
+				http.Error(rw, msg, http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(rw, r.Body, size)
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Logging return a http middleware logging each request, once served, with
+// the client IP, the response status and size, and the latency
+func Logging(logger *log.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			logger.Printf("%s %s %s %d %dB %s",
+				clientIP(r), r.Method, r.URL.RequestURI(),
+				recorder.status, recorder.size, time.Since(start).Round(time.Microsecond))
+		})
+	}
+}
+
+// statusRecorder is a http.ResponseWriter recording the status and the size
+// of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(data []byte) (int, error) {
+	n, err := sr.ResponseWriter.Write(data)
+	sr.size += n
+	return n, err
+}
+
+// Flush implement http.Flusher, for the streamed responses
+func (sr *statusRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implement http.Hijacker, for the GraphQL subscriptions over
+// websocket
+func (sr *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := sr.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("the response can't be hijacked")
+	}
+	sr.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rl := newRateLimiter(2, func() time.Time { return now })
+
+	handler := rl.middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = remoteAddr
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	// a burst of five seconds of requests is allowed
+	for i := 0; i < 10; i++ {
+		require.Equal(t, http.StatusOK, serve("10.0.0.1:1234").Code)
+	}
+	rw := serve("10.0.0.1:1235")
+	require.Equal(t, http.StatusTooManyRequests, rw.Code)
+	require.Equal(t, "1", rw.Header().Get("Retry-After"))
+
+	// the other clients are not limited
+	require.Equal(t, http.StatusOK, serve("10.0.0.2:1234").Code)
+
+	// the bucket is refilled over time
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusOK, serve("10.0.0.1:1234").Code)
+	require.Equal(t, http.StatusOK, serve("10.0.0.1:1234").Code)
+	require.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1:1234").Code)
+
+	// the clients not seen for a while are forgotten
+	now = now.Add(forgetAfter)
+	require.Equal(t, http.StatusOK, serve("10.0.0.1:1234").Code)
+	require.Len(t, rl.buckets, 1)
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	handler := Logging(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		http.Error(rw, "not found", http.StatusNotFound)
+	}))
+
+	r := httptest.NewRequest("GET", "/bug/abc?q=1", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.True(t, strings.HasPrefix(buf.String(), "10.0.0.1 GET /bug/abc?q=1 404 10B "))
+}
+
+func TestMaxBodySize(t *testing.T) {
+	handler := MaxBodySize(5)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+		}
+	}))
+
+	serve := func(body string, contentLength int64) int {
+		r := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+		r.ContentLength = contentLength
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("small", 5))
+	require.Equal(t, http.StatusRequestEntityTooLarge, serve("too big", 7))
+	// a body without announced size is cut at the limit
+	require.Equal(t, http.StatusBadRequest, serve("too big", -1))
+}
@@ -0,0 +1,116 @@
+// Package middleware contains the http middlewares protecting the server of
+// the web UI and the GraphQL API from the misbehaving clients, and logging
+// the requests.
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// the number of seconds of requests a client can make at once
+const burstSeconds = 5
+
+// a client not seen for this long has a full bucket again, and is forgotten
+const forgetAfter = burstSeconds * time.Second
+
+// a token bucket of a client
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// rateLimiter limit the requests of each client IP with a token bucket,
+// refilled at a fixed rate
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// RateLimit return a http middleware limiting the requests of each client IP
+// to a number per second. A burst of a few seconds of requests is allowed,
+// the requests over the limit are rejected with "429 Too Many Requests".
+func RateLimit(perSecond float64) func(http.Handler) http.Handler {
+	return newRateLimiter(perSecond, time.Now).middleware
+}
+
+func newRateLimiter(perSecond float64, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		rate:    perSecond,
+		burst:   math.Max(perSecond*burstSeconds, 1),
+		buckets: make(map[string]*bucket),
+		now:     now,
+	}
+}
+
+func (rl *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		wait := rl.take(clientIP(r))
+		if wait > 0 {
+			rw.Header().Set("Retry-After", fmt.Sprintf("%d", int(math.Ceil(wait.Seconds()))))
+			http.Error(rw, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// take consume a token of the bucket of a client, and return zero if the
+// request is allowed, or how long to wait before the next one otherwise
+func (rl *rateLimiter) take(ip string) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	b, ok := rl.buckets[ip]
+	if !ok {
+		b = &bucket{tokens: rl.burst, lastSeen: now}
+		rl.buckets[ip] = b
+	}
+
+	b.tokens = math.Min(rl.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*rl.rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+	}
+
+	b.tokens--
+	return 0
+}
+
+// sweep forget the clients not seen for a while, for the buckets not to
+// accumulate
+func (rl *rateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < forgetAfter {
+		return
+	}
+	rl.lastSweep = now
+
+	for ip, b := range rl.buckets {
+		if now.Sub(b.lastSeen) >= forgetAfter {
+			delete(rl.buckets, ip)
+		}
+	}
+}
+
+// clientIP return the IP of the client of a request. The headers set by the
+// proxies are not trusted, as any client can set them.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/util/git"
 )
 
@@ -16,6 +17,28 @@ type Authored interface {
 	IsAuthored()
 }
 
+type AddChecklistItemInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The bug ID's prefix.
+	Prefix string `json:"prefix"`
+	// The task to add to the checklist.
+	Text string `json:"text"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
+}
+
+type AddChecklistItemPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The affected bug.
+	Bug BugWrapper `json:"bug"`
+	// The resulting operation.
+	Operation *bug.AddChecklistItemOperation `json:"operation"`
+}
+
 type AddCommentInput struct {
 	// A unique identifier for the client performing the mutation.
 	ClientMutationID *string `json:"clientMutationId"`
@@ -27,6 +50,10 @@ type AddCommentInput struct {
 	Message string `json:"message"`
 	// The collection of file's hash required for the first message.
 	Files []git.Hash `json:"files"`
+	// The comment ID's prefix this comment reply to, if any.
+	ReplyTo *string `json:"replyTo"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
 }
 
 type AddCommentPayload struct {
@@ -38,6 +65,54 @@ type AddCommentPayload struct {
 	Operation *bug.AddCommentOperation `json:"operation"`
 }
 
+type BatchAddCommentInput struct {
+	// The message of the comment.
+	Message string `json:"message"`
+	// The collection of file's hash required for the message.
+	Files []git.Hash `json:"files"`
+}
+
+type BatchChangeLabelInput struct {
+	// The list of label to add.
+	Added []string `json:"added"`
+	// The list of label to remove.
+	Removed []string `json:"removed"`
+}
+
+type BatchEditInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The bug ID's prefix.
+	Prefix string `json:"prefix"`
+	// The edits to apply, in order.
+	Operations []*BatchOperationInput `json:"operations"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
+}
+
+type BatchEditPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The affected bug.
+	Bug BugWrapper `json:"bug"`
+	// The resulting operations, in order.
+	Operations []bug.Operation `json:"operations"`
+}
+
+// An edit of a bug in a batch. Exactly one of the fields has to be set.
+type BatchOperationInput struct {
+	// Add a comment.
+	AddComment *BatchAddCommentInput `json:"addComment"`
+	// Add or remove a set of label.
+	ChangeLabels *BatchChangeLabelInput `json:"changeLabels"`
+	// Change the status.
+	SetStatus *Status `json:"setStatus"`
+	// Change the title.
+	SetTitle *string `json:"setTitle"`
+}
+
 // The connection type for Bug.
 type BugConnection struct {
 	// A list of edges.
@@ -57,6 +132,35 @@ type BugEdge struct {
 	Node BugWrapper `json:"node"`
 }
 
+// A custom field of a bug, as a version
+type BugField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type BugFieldInput struct {
+	// The name of the field, as "version".
+	Name string `json:"name"`
+	// The value of the field.
+	Value string `json:"value"`
+}
+
+// A reference to a bug of another repository.
+type BugReference struct {
+	// The git remote URL of the repository holding the bug.
+	RepoURL string `json:"repoUrl"`
+	// The identifier of the referenced bug, possibly truncated.
+	BugID string `json:"bugId"`
+	// The human version (truncated) identifier of the referenced bug.
+	HumanID string `json:"humanId"`
+	// True if the referenced bug is shared with this repository through a remote.
+	Local bool `json:"local"`
+	// The title of the referenced bug, if its repository is available locally.
+	Title *string `json:"title"`
+	// The status of the referenced bug, if its repository is available locally.
+	Status *Status `json:"status"`
+}
+
 type ChangeLabelInput struct {
 	// A unique identifier for the client performing the mutation.
 	ClientMutationID *string `json:"clientMutationId"`
@@ -68,6 +172,8 @@ type ChangeLabelInput struct {
 	Added []string `json:"added"`
 	// The list of label to remove.
 	Removed []string `json:"Removed"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
 }
 
 type ChangeLabelPayload struct {
@@ -81,6 +187,30 @@ type ChangeLabelPayload struct {
 	Results []*bug.LabelChangeResult `json:"results"`
 }
 
+type CheckItemInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The bug ID's prefix.
+	Prefix string `json:"prefix"`
+	// The identifier of the checklist item.
+	Target string `json:"target"`
+	// Check the item if true, uncheck it otherwise.
+	Checked bool `json:"checked"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
+}
+
+type CheckItemPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The affected bug.
+	Bug BugWrapper `json:"bug"`
+	// The resulting operation.
+	Operation *bug.CheckItemOperation `json:"operation"`
+}
+
 type CloseBugInput struct {
 	// A unique identifier for the client performing the mutation.
 	ClientMutationID *string `json:"clientMutationId"`
@@ -88,6 +218,10 @@ type CloseBugInput struct {
 	RepoRef *string `json:"repoRef"`
 	// The bug ID's prefix.
 	Prefix string `json:"prefix"`
+	// The reason the bug is closed for, if any.
+	Resolution *Resolution `json:"resolution"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
 }
 
 type CloseBugPayload struct {
@@ -111,6 +245,31 @@ type CommentEdge struct {
 	Node   *bug.Comment `json:"node"`
 }
 
+type CreateLabelInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The label to create.
+	Name string `json:"name"`
+	// The color of the label, as "#rrggbb". If not set, a color is computed from the label.
+	Color *string `json:"color"`
+}
+
+type CreateLabelPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The created label.
+	Label *cache.LabelEntry `json:"label"`
+}
+
+type FormAnswerInput struct {
+	// The identifier of the field of the form.
+	ID string `json:"id"`
+	// The answer, "true" or "false" for a checkbox.
+	Value string `json:"value"`
+}
+
 type IdentityConnection struct {
 	Edges      []*IdentityEdge   `json:"edges"`
 	Nodes      []IdentityWrapper `json:"nodes"`
@@ -123,6 +282,12 @@ type IdentityEdge struct {
 	Node   IdentityWrapper `json:"node"`
 }
 
+// An operation made by an identity, with the bug it has been made on
+type IdentityOperation struct {
+	Bug       BugWrapper    `json:"bug"`
+	Operation bug.Operation `json:"operation"`
+}
+
 type LabelConnection struct {
 	Edges      []*LabelEdge `json:"edges"`
 	Nodes      []bug.Label  `json:"nodes"`
@@ -135,6 +300,48 @@ type LabelEdge struct {
 	Node   bug.Label `json:"node"`
 }
 
+type MarkDuplicateInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The bug ID's prefix.
+	Prefix string `json:"prefix"`
+	// The canonical bug ID's prefix.
+	CanonicalPrefix string `json:"canonicalPrefix"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
+}
+
+type MarkDuplicatePayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The affected bug.
+	Bug BugWrapper `json:"bug"`
+	// The resulting operation.
+	Operation *bug.MarkDuplicateOperation `json:"operation"`
+}
+
+type MergeLabelInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The label to merge.
+	Name string `json:"name"`
+	// The label to merge into.
+	Into string `json:"into"`
+}
+
+type MergeLabelPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The label merged into.
+	Label *cache.LabelEntry `json:"label"`
+	// The bugs that had the merged label.
+	Bugs []BugWrapper `json:"bugs"`
+}
+
 type NewBugInput struct {
 	// A unique identifier for the client performing the mutation.
 	ClientMutationID *string `json:"clientMutationId"`
@@ -146,6 +353,14 @@ type NewBugInput struct {
 	Message string `json:"message"`
 	// The collection of file's hash required for the first message.
 	Files []git.Hash `json:"files"`
+	// The labels of the new bug.
+	Labels []string `json:"labels"`
+	// The custom fields of the new bug.
+	Fields []*BugFieldInput `json:"fields"`
+	// The name of the form the bug is reported with, if any. The message is then made of the answers.
+	Form *string `json:"form"`
+	// The answers to the questions of the form.
+	Answers []*FormAnswerInput `json:"answers"`
 }
 
 type NewBugPayload struct {
@@ -164,6 +379,8 @@ type OpenBugInput struct {
 	RepoRef *string `json:"repoRef"`
 	// The bug ID's prefix.
 	Prefix string `json:"prefix"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
 }
 
 type OpenBugPayload struct {
@@ -201,6 +418,68 @@ type PageInfo struct {
 	EndCursor string `json:"endCursor"`
 }
 
+type RenameLabelInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The label to rename.
+	Name string `json:"name"`
+	// The new name of the label, not used already.
+	NewName string `json:"newName"`
+}
+
+type RenameLabelPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The renamed label.
+	Label *cache.LabelEntry `json:"label"`
+	// The bugs the label has been renamed on.
+	Bugs []BugWrapper `json:"bugs"`
+}
+
+type RequestReviewInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The bug ID's prefix.
+	Prefix string `json:"prefix"`
+	// The ID's prefixes of the identities whose input is requested.
+	Reviewers []string `json:"reviewers"`
+	// The message of the request, if any.
+	Message *string `json:"message"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
+}
+
+type RequestReviewPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The affected bug.
+	Bug BugWrapper `json:"bug"`
+	// The resulting operation.
+	Operation *bug.RequestReviewOperation `json:"operation"`
+}
+
+type SetLabelColorInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The label to change.
+	Name string `json:"name"`
+	// The color of the label, as "#rrggbb".
+	Color string `json:"color"`
+}
+
+type SetLabelColorPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The changed label.
+	Label *cache.LabelEntry `json:"label"`
+}
+
 type SetTitleInput struct {
 	// A unique identifier for the client performing the mutation.
 	ClientMutationID *string `json:"clientMutationId"`
@@ -210,6 +489,8 @@ type SetTitleInput struct {
 	Prefix string `json:"prefix"`
 	// The new title.
 	Title string `json:"title"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
 }
 
 type SetTitlePayload struct {
@@ -235,6 +516,77 @@ type TimelineItemEdge struct {
 	Node   bug.TimelineItem `json:"node"`
 }
 
+type VoteInput struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// "The name of the repository. If not set, the default repository is used.
+	RepoRef *string `json:"repoRef"`
+	// The bug ID's prefix.
+	Prefix string `json:"prefix"`
+	// If true, retract the vote of the user instead of voting.
+	Retract *bool `json:"retract"`
+	// If set, the mutation is refused with a CONFLICT error when the bug doesn't have this number of operations anymore, that is when it has been edited concurrently.
+	ExpectedOperationCount *int `json:"expectedOperationCount"`
+}
+
+type VotePayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationID *string `json:"clientMutationId"`
+	// The affected bug.
+	Bug BugWrapper `json:"bug"`
+	// The resulting operation.
+	Operation *bug.VoteOperation `json:"operation"`
+}
+
+type FormFieldType string
+
+const (
+	// A single line of text.
+	FormFieldTypeInput FormFieldType = "INPUT"
+	// A text of several lines, as markdown.
+	FormFieldTypeTextarea FormFieldType = "TEXTAREA"
+	// One of the options of the field.
+	FormFieldTypeDropdown FormFieldType = "DROPDOWN"
+	// A confirmation.
+	FormFieldTypeCheckbox FormFieldType = "CHECKBOX"
+)
+
+var AllFormFieldType = []FormFieldType{
+	FormFieldTypeInput,
+	FormFieldTypeTextarea,
+	FormFieldTypeDropdown,
+	FormFieldTypeCheckbox,
+}
+
+func (e FormFieldType) IsValid() bool {
+	switch e {
+	case FormFieldTypeInput, FormFieldTypeTextarea, FormFieldTypeDropdown, FormFieldTypeCheckbox:
+		return true
+	}
+	return false
+}
+
+func (e FormFieldType) String() string {
+	return string(e)
+}
+
+func (e *FormFieldType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FormFieldType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FormFieldType", str)
+	}
+	return nil
+}
+
+func (e FormFieldType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type LabelChangeStatus string
 
 const (
@@ -282,6 +634,54 @@ func (e LabelChangeStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// The reason a bug has been closed for
+type Resolution string
+
+const (
+	ResolutionFixed      Resolution = "FIXED"
+	ResolutionWontfix    Resolution = "WONTFIX"
+	ResolutionDuplicate  Resolution = "DUPLICATE"
+	ResolutionInvalid    Resolution = "INVALID"
+	ResolutionWorksForMe Resolution = "WORKS_FOR_ME"
+)
+
+var AllResolution = []Resolution{
+	ResolutionFixed,
+	ResolutionWontfix,
+	ResolutionDuplicate,
+	ResolutionInvalid,
+	ResolutionWorksForMe,
+}
+
+func (e Resolution) IsValid() bool {
+	switch e {
+	case ResolutionFixed, ResolutionWontfix, ResolutionDuplicate, ResolutionInvalid, ResolutionWorksForMe:
+		return true
+	}
+	return false
+}
+
+func (e Resolution) String() string {
+	return string(e)
+}
+
+func (e *Resolution) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Resolution(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Resolution", str)
+	}
+	return nil
+}
+
+func (e Resolution) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type Status string
 
 const (
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -16,15 +17,32 @@ type BugWrapper interface {
 	Id() entity.Id
 	LastEdit() time.Time
 	Status() bug.Status
+	Resolution() bug.Resolution
 	Title() string
 	Comments() ([]bug.Comment, error)
+	Threads() ([]*bug.CommentThread, error)
 	Labels() []bug.Label
+	Component() string
+	Assignees() ([]IdentityWrapper, error)
 	Author() (IdentityWrapper, error)
 	Actors() ([]IdentityWrapper, error)
 	Participants() ([]IdentityWrapper, error)
 	CreatedAt() time.Time
 	Timeline() ([]bug.TimelineItem, error)
 	Operations() ([]bug.Operation, error)
+	References() ([]bug.Reference, error)
+	DuplicateOf() (BugWrapper, error)
+	Duplicates() ([]BugWrapper, error)
+	Parent() (BugWrapper, error)
+	Children() ([]BugWrapper, error)
+	ChildrenClosed() (int, error)
+	Checklist() ([]bug.ChecklistItem, error)
+	Votes() int
+	Voters() ([]IdentityWrapper, error)
+	ReviewRequests() ([]bug.ReviewRequest, error)
+	Fields() ([]*BugField, error)
+	// Repo return the repository holding the bug
+	Repo() *cache.RepoCache
 
 	IsAuthored()
 }
@@ -88,6 +106,10 @@ func (lb *lazyBug) Status() bug.Status {
 	return lb.excerpt.Status
 }
 
+func (lb *lazyBug) Resolution() bug.Resolution {
+	return lb.excerpt.Resolution
+}
+
 func (lb *lazyBug) Title() string {
 	return lb.excerpt.Title
 }
@@ -100,10 +122,74 @@ func (lb *lazyBug) Comments() ([]bug.Comment, error) {
 	return lb.snap.Comments, nil
 }
 
+func (lb *lazyBug) Threads() ([]*bug.CommentThread, error) {
+	err := lb.load()
+	if err != nil {
+		return nil, err
+	}
+	return lb.snap.Threads(), nil
+}
+
+func (lb *lazyBug) Checklist() ([]bug.ChecklistItem, error) {
+	err := lb.load()
+	if err != nil {
+		return nil, err
+	}
+	return lb.snap.Checklist, nil
+}
+
+func (lb *lazyBug) Votes() int {
+	return lb.excerpt.Votes
+}
+
+func (lb *lazyBug) Fields() ([]*BugField, error) {
+	err := lb.load()
+	if err != nil {
+		return nil, err
+	}
+	return bugFields(lb.snap.Fields), nil
+}
+
+func (lb *lazyBug) Voters() ([]IdentityWrapper, error) {
+	err := lb.load()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]IdentityWrapper, len(lb.snap.Voters))
+	for i, voter := range lb.snap.Voters {
+		res[i] = NewLoadedIdentity(voter)
+	}
+	return res, nil
+}
+
+func (lb *lazyBug) ReviewRequests() ([]bug.ReviewRequest, error) {
+	err := lb.load()
+	if err != nil {
+		return nil, err
+	}
+	return lb.snap.ReviewRequests, nil
+}
+
 func (lb *lazyBug) Labels() []bug.Label {
 	return lb.excerpt.Labels
 }
 
+func (lb *lazyBug) Component() string {
+	return lb.excerpt.Component
+}
+
+func (lb *lazyBug) Assignees() ([]IdentityWrapper, error) {
+	result := make([]IdentityWrapper, len(lb.excerpt.Assignees))
+	for i, assigneeId := range lb.excerpt.Assignees {
+		assignee, err := lb.identity(assigneeId)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = assignee
+	}
+	return result, nil
+}
+
 func (lb *lazyBug) Author() (IdentityWrapper, error) {
 	return lb.identity(lb.excerpt.AuthorId)
 }
@@ -152,14 +238,79 @@ func (lb *lazyBug) Operations() ([]bug.Operation, error) {
 	return lb.snap.Operations, nil
 }
 
+func (lb *lazyBug) References() ([]bug.Reference, error) {
+	err := lb.load()
+	if err != nil {
+		return nil, err
+	}
+	return lb.snap.References(), nil
+}
+
+func (lb *lazyBug) DuplicateOf() (BugWrapper, error) {
+	return relatedBug(lb.cache, lb.excerpt.DuplicateOf)
+}
+
+func (lb *lazyBug) Duplicates() ([]BugWrapper, error) {
+	return lazyBugs(lb.cache, lb.cache.DuplicatesOf(lb.excerpt.Id))
+}
+
+func (lb *lazyBug) Parent() (BugWrapper, error) {
+	return relatedBug(lb.cache, lb.excerpt.Parent)
+}
+
+func (lb *lazyBug) Children() ([]BugWrapper, error) {
+	return lazyBugs(lb.cache, lb.cache.ChildrenOf(lb.excerpt.Id))
+}
+
+func (lb *lazyBug) ChildrenClosed() (int, error) {
+	return lb.excerpt.ChildrenClosed, nil
+}
+
+func (lb *lazyBug) Repo() *cache.RepoCache {
+	return lb.cache
+}
+
+// relatedBug return the bug a bug refer to, as the canonical bug of a
+// duplicate or the parent of a sub-task, if any and if available locally
+func relatedBug(cache *cache.RepoCache, id entity.Id) (BugWrapper, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	excerpt, err := cache.ResolveBugExcerpt(id)
+	if err == bug.ErrBugNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLazyBug(cache, excerpt), nil
+}
+
+func lazyBugs(cache *cache.RepoCache, ids []entity.Id) ([]BugWrapper, error) {
+	result := make([]BugWrapper, len(ids))
+	for i, id := range ids {
+		excerpt, err := cache.ResolveBugExcerpt(id)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = NewLazyBug(cache, excerpt)
+	}
+
+	return result, nil
+}
+
 var _ BugWrapper = &loadedBug{}
 
 type loadedBug struct {
 	*bug.Snapshot
+
+	cache *cache.RepoCache
 }
 
-func NewLoadedBug(snap *bug.Snapshot) *loadedBug {
-	return &loadedBug{Snapshot: snap}
+func NewLoadedBug(cache *cache.RepoCache, snap *bug.Snapshot) *loadedBug {
+	return &loadedBug{Snapshot: snap, cache: cache}
 }
 
 func (l *loadedBug) LastEdit() time.Time {
@@ -170,6 +321,10 @@ func (l *loadedBug) Status() bug.Status {
 	return l.Snapshot.Status
 }
 
+func (l *loadedBug) Resolution() bug.Resolution {
+	return l.Snapshot.Resolution
+}
+
 func (l *loadedBug) Title() string {
 	return l.Snapshot.Title
 }
@@ -178,10 +333,50 @@ func (l *loadedBug) Comments() ([]bug.Comment, error) {
 	return l.Snapshot.Comments, nil
 }
 
+func (l *loadedBug) Threads() ([]*bug.CommentThread, error) {
+	return l.Snapshot.Threads(), nil
+}
+
+func (l *loadedBug) Checklist() ([]bug.ChecklistItem, error) {
+	return l.Snapshot.Checklist, nil
+}
+
+func (l *loadedBug) Votes() int {
+	return len(l.Snapshot.Voters)
+}
+
+func (l *loadedBug) Fields() ([]*BugField, error) {
+	return bugFields(l.Snapshot.Fields), nil
+}
+
+func (l *loadedBug) Voters() ([]IdentityWrapper, error) {
+	res := make([]IdentityWrapper, len(l.Snapshot.Voters))
+	for i, voter := range l.Snapshot.Voters {
+		res[i] = NewLoadedIdentity(voter)
+	}
+	return res, nil
+}
+
+func (l *loadedBug) ReviewRequests() ([]bug.ReviewRequest, error) {
+	return l.Snapshot.ReviewRequests, nil
+}
+
 func (l *loadedBug) Labels() []bug.Label {
 	return l.Snapshot.Labels
 }
 
+func (l *loadedBug) Component() string {
+	return l.Snapshot.Component
+}
+
+func (l *loadedBug) Assignees() ([]IdentityWrapper, error) {
+	res := make([]IdentityWrapper, len(l.Snapshot.Assignees))
+	for i, assignee := range l.Snapshot.Assignees {
+		res[i] = NewLoadedIdentity(assignee)
+	}
+	return res, nil
+}
+
 func (l *loadedBug) Author() (IdentityWrapper, error) {
 	return NewLoadedIdentity(l.Snapshot.Author), nil
 }
@@ -213,3 +408,46 @@ func (l *loadedBug) Timeline() ([]bug.TimelineItem, error) {
 func (l *loadedBug) Operations() ([]bug.Operation, error) {
 	return l.Snapshot.Operations, nil
 }
+
+func (l *loadedBug) References() ([]bug.Reference, error) {
+	return l.Snapshot.References(), nil
+}
+
+func (l *loadedBug) DuplicateOf() (BugWrapper, error) {
+	return relatedBug(l.cache, l.Snapshot.DuplicateOf)
+}
+
+func (l *loadedBug) Duplicates() ([]BugWrapper, error) {
+	return lazyBugs(l.cache, l.cache.DuplicatesOf(l.Snapshot.Id()))
+}
+
+func (l *loadedBug) Parent() (BugWrapper, error) {
+	return relatedBug(l.cache, l.Snapshot.Parent)
+}
+
+func (l *loadedBug) Children() ([]BugWrapper, error) {
+	return lazyBugs(l.cache, l.cache.ChildrenOf(l.Snapshot.Id()))
+}
+
+// ChildrenClosed read the number of closed sub-tasks from the cache, as the
+// snapshot of a bug doesn't know its sub-tasks
+func (l *loadedBug) ChildrenClosed() (int, error) {
+	excerpt, err := l.cache.ResolveBugExcerpt(l.Snapshot.Id())
+	if err != nil {
+		return 0, err
+	}
+	return excerpt.ChildrenClosed, nil
+}
+
+func (l *loadedBug) Repo() *cache.RepoCache {
+	return l.cache
+}
+
+func bugFields(fields map[string]string) []*BugField {
+	res := make([]*BugField, 0, len(fields))
+	for name, value := range fields {
+		res = append(res, &BugField{Name: name, Value: value})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
@@ -21,3 +21,11 @@ type RepositoryMutation struct {
 	Cache *cache.MultiRepoCache
 	Repo  *cache.RepoCache
 }
+
+// IdentityActivity is the participation of an identity in the bugs of a
+// repository
+type IdentityActivity struct {
+	Repo     *cache.RepoCache
+	Identity IdentityWrapper
+	Activity cache.IdentityActivity
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/graphql/connections"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/models"
@@ -11,7 +12,9 @@ import (
 
 var _ graph.BugResolver = &bugResolver{}
 
-type bugResolver struct{}
+type bugResolver struct {
+	cache *cache.MultiRepoCache
+}
 
 func (bugResolver) ID(_ context.Context, obj models.BugWrapper) (string, error) {
 	return obj.Id().String(), nil
@@ -21,10 +24,25 @@ func (bugResolver) HumanID(_ context.Context, obj models.BugWrapper) (string, er
 	return obj.Id().Human(), nil
 }
 
+func (bugResolver) QualifiedID(_ context.Context, obj models.BugWrapper) (string, error) {
+	return cache.QualifiedBugId(obj.Repo(), obj.Id()), nil
+}
+
+func (r bugResolver) Repository(_ context.Context, obj models.BugWrapper) (*models.Repository, error) {
+	return &models.Repository{
+		Cache: r.cache,
+		Repo:  obj.Repo(),
+	}, nil
+}
+
 func (bugResolver) Status(_ context.Context, obj models.BugWrapper) (models.Status, error) {
 	return convertStatus(obj.Status())
 }
 
+func (bugResolver) Resolution(_ context.Context, obj models.BugWrapper) (*models.Resolution, error) {
+	return convertResolution(obj.Resolution())
+}
+
 func (bugResolver) Comments(_ context.Context, obj models.BugWrapper, after *string, before *string, first *int, last *int) (*models.CommentConnection, error) {
 	input := models.ConnectionInput{
 		Before: before,
@@ -188,3 +206,39 @@ func (bugResolver) Participants(_ context.Context, obj models.BugWrapper, after
 
 	return connections.IdentityCon(participants, edger, conMaker, input)
 }
+
+func (r bugResolver) References(_ context.Context, obj models.BugWrapper) ([]*models.BugReference, error) {
+	refs, err := obj.References()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.BugReference, len(refs))
+
+	for i, ref := range refs {
+		result[i] = &models.BugReference{
+			RepoURL: ref.RepoUrl,
+			BugID:   ref.BugId,
+			HumanID: ref.HumanId(),
+		}
+
+		resolved, err := r.cache.ResolveReference(ref)
+		if err == cache.ErrUnresolvedReference || err == bug.ErrBugNotExist {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := convertStatus(resolved.Excerpt.Status)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i].Local = resolved.Local
+		result[i].Title = &resolved.Excerpt.Title
+		result[i].Status = &status
+	}
+
+	return result, nil
+}
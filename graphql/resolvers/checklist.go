@@ -0,0 +1,27 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/graphql/graph"
+	"github.com/MichaelMure/git-bug/graphql/models"
+)
+
+var _ graph.ChecklistItemResolver = &checklistItemResolver{}
+
+type checklistItemResolver struct{}
+
+func (checklistItemResolver) ID(_ context.Context, obj *bug.ChecklistItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (checklistItemResolver) Author(_ context.Context, obj *bug.ChecklistItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (checklistItemResolver) CreatedAt(_ context.Context, obj *bug.ChecklistItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/graphql/graph"
+	"github.com/MichaelMure/git-bug/graphql/markdown"
 	"github.com/MichaelMure/git-bug/graphql/models"
 )
 
@@ -15,3 +17,24 @@ type commentResolver struct{}
 func (c commentResolver) Author(_ context.Context, obj *bug.Comment) (models.IdentityWrapper, error) {
 	return models.NewLoadedIdentity(obj.Author), nil
 }
+
+func (c commentResolver) ID(_ context.Context, obj *bug.Comment) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (c commentResolver) MessageHTML(_ context.Context, obj *bug.Comment) (string, error) {
+	return markdown.ToHTML(obj.Message), nil
+}
+
+func (c commentResolver) ReplyTo(_ context.Context, obj *bug.Comment) (*string, error) {
+	return replyTo(obj.ReplyTo), nil
+}
+
+// replyTo return the id of the comment replied to, or nil if it's not a reply
+func replyTo(id entity.Id) *string {
+	if id == "" {
+		return nil
+	}
+	str := id.String()
+	return &str
+}
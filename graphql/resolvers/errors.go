@@ -0,0 +1,24 @@
+package resolvers
+
+import "fmt"
+
+// ErrConflict is returned when a mutation is refused because the bug has
+// been modified since the client last read it. The client is expected to
+// refresh its state before trying again.
+type ErrConflict struct {
+	Expected int
+	Actual   int
+}
+
+func (e ErrConflict) Error() string {
+	return fmt.Sprintf("the bug has been modified concurrently: expected %d operations, found %d", e.Expected, e.Actual)
+}
+
+// Extensions implement graphql.ExtendedError, to expose the conflict to the
+// clients in the extensions of the GraphQL error
+func (e ErrConflict) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":           "CONFLICT",
+		"operationCount": e.Actual,
+	}
+}
@@ -0,0 +1,29 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/graphql/graph"
+	"github.com/MichaelMure/git-bug/graphql/models"
+)
+
+var _ graph.FormFieldResolver = &formFieldResolver{}
+
+type formFieldResolver struct{}
+
+var formFieldTypes = map[bug.FormFieldType]models.FormFieldType{
+	bug.FormInput:    models.FormFieldTypeInput,
+	bug.FormTextarea: models.FormFieldTypeTextarea,
+	bug.FormDropdown: models.FormFieldTypeDropdown,
+	bug.FormCheckbox: models.FormFieldTypeCheckbox,
+}
+
+func (formFieldResolver) Type(_ context.Context, obj *bug.FormField) (models.FormFieldType, error) {
+	t, ok := formFieldTypes[obj.Type]
+	if !ok {
+		return "", fmt.Errorf("unknown form field type")
+	}
+	return t, nil
+}
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"time"
 
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/models"
@@ -19,3 +20,67 @@ func (r identityResolver) HumanID(ctx context.Context, obj models.IdentityWrappe
 	return obj.Id().Human(), nil
 
 }
+
+var _ graph.IdentityActivityResolver = &identityActivityResolver{}
+
+type identityActivityResolver struct{}
+
+func (identityActivityResolver) AuthoredBugs(_ context.Context, obj *models.IdentityActivity, after *string, before *string, first *int, last *int) (*models.BugConnection, error) {
+	input := models.ConnectionInput{
+		Before: before,
+		After:  after,
+		First:  first,
+		Last:   last,
+	}
+
+	return lazyBugConnection(obj.Repo, obj.Activity.Authored, input)
+}
+
+func (identityActivityResolver) CommentedBugs(_ context.Context, obj *models.IdentityActivity, after *string, before *string, first *int, last *int) (*models.BugConnection, error) {
+	input := models.ConnectionInput{
+		Before: before,
+		After:  after,
+		First:  first,
+		Last:   last,
+	}
+
+	return lazyBugConnection(obj.Repo, obj.Activity.Commented, input)
+}
+
+func (identityActivityResolver) InvolvedCount(_ context.Context, obj *models.IdentityActivity) (int, error) {
+	return obj.Activity.Involved, nil
+}
+
+func (identityActivityResolver) LastActivity(_ context.Context, obj *models.IdentityActivity) (*time.Time, error) {
+	if obj.Activity.LastActivityUnixTime == 0 {
+		return nil, nil
+	}
+	t := time.Unix(obj.Activity.LastActivityUnixTime, 0)
+	return &t, nil
+}
+
+func (identityActivityResolver) RecentOperations(_ context.Context, obj *models.IdentityActivity, first *int) ([]*models.IdentityOperation, error) {
+	limit := 0
+	if first != nil {
+		limit = *first
+	}
+
+	ops, err := obj.Repo.RecentOperations(obj.Identity.Id(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.IdentityOperation, len(ops))
+	for i, op := range ops {
+		excerpt, err := obj.Repo.ResolveBugExcerpt(op.BugId)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = &models.IdentityOperation{
+			Bug:       models.NewLazyBug(obj.Repo, excerpt),
+			Operation: op.Operation,
+		}
+	}
+
+	return result, nil
+}
@@ -5,24 +5,49 @@ import (
 	"fmt"
 	"image/color"
 
+	"github.com/99designs/gqlgen/graphql"
+
 	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/models"
 )
 
 var _ graph.LabelResolver = &labelResolver{}
 
-type labelResolver struct{}
+type labelResolver struct {
+	cache *cache.MultiRepoCache
+}
 
 func (labelResolver) Name(ctx context.Context, obj *bug.Label) (string, error) {
 	return obj.String(), nil
 }
 
-func (labelResolver) Color(ctx context.Context, obj *bug.Label) (*color.RGBA, error) {
-	rgba := obj.Color().RGBA()
+func (r labelResolver) Color(ctx context.Context, obj *bug.Label) (*color.RGBA, error) {
+	repo, err := r.repoOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rgba := repo.LabelColor(*obj).RGBA()
 	return &rgba, nil
 }
 
+// repoOf return the repository the label is resolved for, found in the
+// results of the parent fields, as a label doesn't know its repository
+func (r labelResolver) repoOf(ctx context.Context) (*cache.RepoCache, error) {
+	for fc := graphql.GetFieldContext(ctx); fc != nil; fc = fc.Parent {
+		switch result := fc.Result.(type) {
+		case models.BugWrapper:
+			return result.Repo(), nil
+		case *models.Repository:
+			return result.Repo, nil
+		}
+	}
+
+	return r.cache.DefaultRepo()
+}
+
 var _ graph.LabelChangeResultResolver = &labelChangeResultResolver{}
 
 type labelChangeResultResolver struct{}
@@ -2,9 +2,13 @@ package resolvers
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/graphql/auth"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/models"
 )
@@ -13,52 +17,120 @@ var _ graph.MutationResolver = &mutationResolver{}
 
 type mutationResolver struct {
 	cache *cache.MultiRepoCache
+	lock  *sync.Mutex
 }
 
-func (r mutationResolver) getRepo(ref *string) (*cache.RepoCache, error) {
+func (r mutationResolver) getRepo(ctx context.Context, ref *string) (*cache.RepoCache, error) {
+	var repo *cache.RepoCache
+	var err error
+
 	if ref != nil {
-		return r.cache.ResolveRepo(*ref)
+		repo, err = r.cache.ResolveRepo(*ref)
+	} else {
+		repo, err = r.cache.DefaultRepo()
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return r.cache.DefaultRepo()
+	// the API token of the request must belong to this repository
+	if err := auth.CanMutateRepo(ctx, repo.Name()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
 }
 
-func (r mutationResolver) getBug(repoRef *string, bugPrefix string) (*cache.BugCache, error) {
-	repo, err := r.getRepo(repoRef)
+// getBug resolve the bug to modify. If the client gave the number of
+// operations it expect the bug to have, the bug must not have been modified
+// in the meantime.
+func (r mutationResolver) getBug(ctx context.Context, repoRef *string, bugPrefix string, expectedOperationCount *int) (*cache.RepoCache, *cache.BugCache, error) {
+	repo, err := r.getRepo(ctx, repoRef)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return repo.ResolveBugPrefix(bugPrefix)
+	b, err := repo.ResolveBugPrefix(bugPrefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if expectedOperationCount != nil {
+		count := len(b.Snapshot().Operations)
+		if count != *expectedOperationCount {
+			return nil, nil, ErrConflict{Expected: *expectedOperationCount, Actual: count}
+		}
+	}
+
+	return repo, b, nil
 }
 
-func (r mutationResolver) NewBug(_ context.Context, input models.NewBugInput) (*models.NewBugPayload, error) {
-	repo, err := r.getRepo(input.RepoRef)
+func (r mutationResolver) NewBug(ctx context.Context, input models.NewBugInput) (*models.NewBugPayload, error) {
+	repo, err := r.getRepo(ctx, input.RepoRef)
 	if err != nil {
 		return nil, err
 	}
 
-	b, op, err := repo.NewBugWithFiles(input.Title, input.Message, input.Files)
-	if err != nil {
-		return nil, err
+	fields := make(map[string]string, len(input.Fields))
+	for _, field := range input.Fields {
+		fields[field.Name] = field.Value
+	}
+
+	var b *cache.BugCache
+	var op *bug.CreateOperation
+	if input.Form != nil {
+		form, err := repo.ResolveForm(*input.Form)
+		if err != nil {
+			return nil, err
+		}
+
+		answers := make(map[string]string, len(input.Answers))
+		for _, answer := range input.Answers {
+			answers[answer.ID] = answer.Value
+		}
+
+		b, op, err = repo.NewBugFromForm(input.Title, form, answers, input.Labels, fields)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		b, op, err = repo.NewBugWithFields(input.Title, input.Message, input.Labels, fields, input.Files)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &models.NewBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
 
-func (r mutationResolver) AddComment(_ context.Context, input models.AddCommentInput) (*models.AddCommentPayload, error) {
-	b, err := r.getBug(input.RepoRef, input.Prefix)
+func (r mutationResolver) AddComment(ctx context.Context, input models.AddCommentInput) (*models.AddCommentPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
 	if err != nil {
 		return nil, err
 	}
 
-	op, err := b.AddCommentWithFiles(input.Message, input.Files)
-	if err != nil {
-		return nil, err
+	var op *bug.AddCommentOperation
+	if input.ReplyTo != nil {
+		comment, err := b.Snapshot().ResolveCommentPrefix(*input.ReplyTo)
+		if err != nil {
+			return nil, err
+		}
+		op, err = b.AddCommentReply(comment.Id(), input.Message, input.Files)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		op, err = b.AddCommentWithFiles(input.Message, input.Files)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	err = b.Commit()
@@ -68,13 +140,16 @@ func (r mutationResolver) AddComment(_ context.Context, input models.AddCommentI
 
 	return &models.AddCommentPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
 
-func (r mutationResolver) ChangeLabels(_ context.Context, input *models.ChangeLabelInput) (*models.ChangeLabelPayload, error) {
-	b, err := r.getBug(input.RepoRef, input.Prefix)
+func (r mutationResolver) ChangeLabels(ctx context.Context, input *models.ChangeLabelInput) (*models.ChangeLabelPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
 	if err != nil {
 		return nil, err
 	}
@@ -96,14 +171,17 @@ func (r mutationResolver) ChangeLabels(_ context.Context, input *models.ChangeLa
 
 	return &models.ChangeLabelPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 		Results:          resultsPtr,
 	}, nil
 }
 
-func (r mutationResolver) OpenBug(_ context.Context, input models.OpenBugInput) (*models.OpenBugPayload, error) {
-	b, err := r.getBug(input.RepoRef, input.Prefix)
+func (r mutationResolver) OpenBug(ctx context.Context, input models.OpenBugInput) (*models.OpenBugPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
 	if err != nil {
 		return nil, err
 	}
@@ -120,18 +198,26 @@ func (r mutationResolver) OpenBug(_ context.Context, input models.OpenBugInput)
 
 	return &models.OpenBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
 
-func (r mutationResolver) CloseBug(_ context.Context, input models.CloseBugInput) (*models.CloseBugPayload, error) {
-	b, err := r.getBug(input.RepoRef, input.Prefix)
+func (r mutationResolver) CloseBug(ctx context.Context, input models.CloseBugInput) (*models.CloseBugPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	resolution, err := parseResolution(input.Resolution)
 	if err != nil {
 		return nil, err
 	}
 
-	op, err := b.Close()
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := b.CloseWithResolution(resolution)
 	if err != nil {
 		return nil, err
 	}
@@ -143,13 +229,169 @@ func (r mutationResolver) CloseBug(_ context.Context, input models.CloseBugInput
 
 	return &models.CloseBugPayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
+		Operation:        op,
+	}, nil
+}
+
+func (r mutationResolver) MarkDuplicate(ctx context.Context, input models.MarkDuplicateInput) (*models.MarkDuplicatePayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	canonical, err := repo.ResolveBugPrefix(input.CanonicalPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := b.MarkDuplicate(canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MarkDuplicatePayload{
+		ClientMutationID: input.ClientMutationID,
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
+		Operation:        op,
+	}, nil
+}
+
+func (r mutationResolver) AddChecklistItem(ctx context.Context, input models.AddChecklistItemInput) (*models.AddChecklistItemPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := b.AddChecklistItem(input.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AddChecklistItemPayload{
+		ClientMutationID: input.ClientMutationID,
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
+		Operation:        op,
+	}, nil
+}
+
+func (r mutationResolver) CheckItem(ctx context.Context, input models.CheckItemInput) (*models.CheckItemPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := b.CheckItem(entity.Id(input.Target), input.Checked)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CheckItemPayload{
+		ClientMutationID: input.ClientMutationID,
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
+		Operation:        op,
+	}, nil
+}
+
+func (r mutationResolver) Vote(ctx context.Context, input models.VoteInput) (*models.VotePayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var op *bug.VoteOperation
+	if input.Retract != nil && *input.Retract {
+		op, err = b.RetractVote()
+	} else {
+		op, err = b.Vote()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VotePayload{
+		ClientMutationID: input.ClientMutationID,
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
+		Operation:        op,
+	}, nil
+}
+
+func (r mutationResolver) RequestReview(ctx context.Context, input models.RequestReviewInput) (*models.RequestReviewPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]*cache.IdentityCache, len(input.Reviewers))
+	for i, prefix := range input.Reviewers {
+		reviewers[i], err = repo.ResolveIdentityPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var message string
+	if input.Message != nil {
+		message = *input.Message
+	}
+
+	op, err := b.RequestReview(reviewers, message)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RequestReviewPayload{
+		ClientMutationID: input.ClientMutationID,
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
 
-func (r mutationResolver) SetTitle(_ context.Context, input models.SetTitleInput) (*models.SetTitlePayload, error) {
-	b, err := r.getBug(input.RepoRef, input.Prefix)
+func (r mutationResolver) SetTitle(ctx context.Context, input models.SetTitleInput) (*models.SetTitlePayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +408,224 @@ func (r mutationResolver) SetTitle(_ context.Context, input models.SetTitleInput
 
 	return &models.SetTitlePayload{
 		ClientMutationID: input.ClientMutationID,
-		Bug:              models.NewLoadedBug(b.Snapshot()),
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
 		Operation:        op,
 	}, nil
 }
+
+func (r mutationResolver) BatchEdit(ctx context.Context, input models.BatchEditInput) (*models.BatchEditPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, b, err := r.getBug(ctx, input.RepoRef, input.Prefix, input.ExpectedOperationCount)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]bug.Operation, 0, len(input.Operations))
+
+	err = b.Batch(func() error {
+		for i, edit := range input.Operations {
+			op, err := applyBatchOperation(b, edit)
+			if err != nil {
+				return fmt.Errorf("operation %d: %v", i, err)
+			}
+			ops = append(ops, op)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.CommitAsNeeded()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BatchEditPayload{
+		ClientMutationID: input.ClientMutationID,
+		Bug:              models.NewLoadedBug(repo, b.Snapshot()),
+		Operations:       ops,
+	}, nil
+}
+
+func (r mutationResolver) CreateLabel(ctx context.Context, input models.CreateLabelInput) (*models.CreateLabelPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, err := r.getRepo(ctx, input.RepoRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var lc *bug.LabelColor
+	if input.Color != nil {
+		parsed, err := bug.ParseLabelColor(*input.Color)
+		if err != nil {
+			return nil, err
+		}
+		lc = &parsed
+	}
+
+	err = repo.CreateLabel(bug.Label(input.Name), lc)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := repo.LabelEntry(bug.Label(input.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CreateLabelPayload{
+		ClientMutationID: input.ClientMutationID,
+		Label:            entry,
+	}, nil
+}
+
+func (r mutationResolver) SetLabelColor(ctx context.Context, input models.SetLabelColorInput) (*models.SetLabelColorPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, err := r.getRepo(ctx, input.RepoRef)
+	if err != nil {
+		return nil, err
+	}
+
+	lc, err := bug.ParseLabelColor(input.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.SetLabelColor(bug.Label(input.Name), lc)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := repo.LabelEntry(bug.Label(input.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SetLabelColorPayload{
+		ClientMutationID: input.ClientMutationID,
+		Label:            entry,
+	}, nil
+}
+
+func (r mutationResolver) RenameLabel(ctx context.Context, input models.RenameLabelInput) (*models.RenameLabelPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, err := r.getRepo(ctx, input.RepoRef)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := repo.RenameLabel(bug.Label(input.Name), bug.Label(input.NewName))
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := repo.LabelEntry(bug.Label(input.NewName))
+	if err != nil {
+		return nil, err
+	}
+
+	bugs, err := changedBugs(repo, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RenameLabelPayload{
+		ClientMutationID: input.ClientMutationID,
+		Label:            entry,
+		Bugs:             bugs,
+	}, nil
+}
+
+func (r mutationResolver) MergeLabel(ctx context.Context, input models.MergeLabelInput) (*models.MergeLabelPayload, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repo, err := r.getRepo(ctx, input.RepoRef)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := repo.MergeLabel(bug.Label(input.Name), bug.Label(input.Into))
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := repo.LabelEntry(bug.Label(input.Into))
+	if err != nil {
+		return nil, err
+	}
+
+	bugs, err := changedBugs(repo, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MergeLabelPayload{
+		ClientMutationID: input.ClientMutationID,
+		Label:            entry,
+		Bugs:             bugs,
+	}, nil
+}
+
+// changedBugs return the bugs changed by a mutation on several bugs
+func changedBugs(repo *cache.RepoCache, ids []entity.Id) ([]models.BugWrapper, error) {
+	result := make([]models.BugWrapper, len(ids))
+
+	for i, id := range ids {
+		excerpt, err := repo.ResolveBugExcerpt(id)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = models.NewLazyBug(repo, excerpt)
+	}
+
+	return result, nil
+}
+
+func applyBatchOperation(b *cache.BugCache, edit *models.BatchOperationInput) (bug.Operation, error) {
+	set := 0
+	for _, isSet := range []bool{
+		edit.AddComment != nil,
+		edit.ChangeLabels != nil,
+		edit.SetStatus != nil,
+		edit.SetTitle != nil,
+	} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("exactly one edit has to be set")
+	}
+
+	switch {
+	case edit.AddComment != nil:
+		return b.AddCommentWithFiles(edit.AddComment.Message, edit.AddComment.Files)
+
+	case edit.ChangeLabels != nil:
+		_, op, err := b.ChangeLabels(edit.ChangeLabels.Added, edit.ChangeLabels.Removed)
+		return op, err
+
+	case edit.SetStatus != nil:
+		switch *edit.SetStatus {
+		case models.StatusOpen:
+			return b.Open()
+		case models.StatusClosed:
+			return b.Close()
+		default:
+			return nil, fmt.Errorf("unknown status %s", *edit.SetStatus)
+		}
+
+	default:
+		return b.SetTitle(*edit.SetTitle)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/models"
+	"github.com/MichaelMure/git-bug/identity"
 )
 
 var _ graph.CreateOperationResolver = createOperationResolver{}
@@ -35,6 +36,10 @@ func (addCommentOperationResolver) ID(_ context.Context, obj *bug.AddCommentOper
 	return obj.Id().String(), nil
 }
 
+func (addCommentOperationResolver) ReplyTo(_ context.Context, obj *bug.AddCommentOperation) (*string, error) {
+	return replyTo(obj.ReplyTo), nil
+}
+
 func (addCommentOperationResolver) Author(_ context.Context, obj *bug.AddCommentOperation) (models.IdentityWrapper, error) {
 	return models.NewLoadedIdentity(obj.Author), nil
 }
@@ -103,6 +108,10 @@ func (setStatusOperationResolver) Status(_ context.Context, obj *bug.SetStatusOp
 	return convertStatus(obj.Status)
 }
 
+func (setStatusOperationResolver) Resolution(_ context.Context, obj *bug.SetStatusOperation) (*models.Resolution, error) {
+	return convertResolution(obj.Resolution)
+}
+
 var _ graph.SetTitleOperationResolver = setTitleOperationResolver{}
 
 type setTitleOperationResolver struct{}
@@ -130,3 +139,278 @@ func convertStatus(status bug.Status) (models.Status, error) {
 
 	return "", fmt.Errorf("unknown status")
 }
+
+var resolutions = map[bug.Resolution]models.Resolution{
+	bug.FixedResolution:      models.ResolutionFixed,
+	bug.WontFixResolution:    models.ResolutionWontfix,
+	bug.DuplicateResolution:  models.ResolutionDuplicate,
+	bug.InvalidResolution:    models.ResolutionInvalid,
+	bug.WorksForMeResolution: models.ResolutionWorksForMe,
+}
+
+// convertResolution return nil for a bug without resolution
+func convertResolution(resolution bug.Resolution) (*models.Resolution, error) {
+	if resolution == bug.NoResolution {
+		return nil, nil
+	}
+
+	converted, ok := resolutions[resolution]
+	if !ok {
+		return nil, fmt.Errorf("unknown resolution")
+	}
+
+	return &converted, nil
+}
+
+func parseResolution(resolution *models.Resolution) (bug.Resolution, error) {
+	if resolution == nil {
+		return bug.NoResolution, nil
+	}
+
+	for res, converted := range resolutions {
+		if converted == *resolution {
+			return res, nil
+		}
+	}
+
+	return bug.NoResolution, fmt.Errorf("unknown resolution %s", *resolution)
+}
+
+var _ graph.SetComponentOperationResolver = setComponentOperationResolver{}
+
+type setComponentOperationResolver struct{}
+
+func (setComponentOperationResolver) ID(_ context.Context, obj *bug.SetComponentOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (setComponentOperationResolver) Author(_ context.Context, obj *bug.SetComponentOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (setComponentOperationResolver) Date(_ context.Context, obj *bug.SetComponentOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+var _ graph.AssigneeChangeOperationResolver = assigneeChangeOperationResolver{}
+
+type assigneeChangeOperationResolver struct{}
+
+func (assigneeChangeOperationResolver) ID(_ context.Context, obj *bug.AssigneeChangeOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (assigneeChangeOperationResolver) Author(_ context.Context, obj *bug.AssigneeChangeOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (assigneeChangeOperationResolver) Date(_ context.Context, obj *bug.AssigneeChangeOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (assigneeChangeOperationResolver) Added(_ context.Context, obj *bug.AssigneeChangeOperation) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Added), nil
+}
+
+func (assigneeChangeOperationResolver) Removed(_ context.Context, obj *bug.AssigneeChangeOperation) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Removed), nil
+}
+
+var _ graph.MarkDuplicateOperationResolver = markDuplicateOperationResolver{}
+
+type markDuplicateOperationResolver struct{}
+
+func (markDuplicateOperationResolver) ID(_ context.Context, obj *bug.MarkDuplicateOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (markDuplicateOperationResolver) Author(_ context.Context, obj *bug.MarkDuplicateOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (markDuplicateOperationResolver) Date(_ context.Context, obj *bug.MarkDuplicateOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (markDuplicateOperationResolver) Target(_ context.Context, obj *bug.MarkDuplicateOperation) (string, error) {
+	return obj.Target.String(), nil
+}
+
+var _ graph.SetParentOperationResolver = setParentOperationResolver{}
+
+type setParentOperationResolver struct{}
+
+func (setParentOperationResolver) ID(_ context.Context, obj *bug.SetParentOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (setParentOperationResolver) Author(_ context.Context, obj *bug.SetParentOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (setParentOperationResolver) Date(_ context.Context, obj *bug.SetParentOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (setParentOperationResolver) Parent(_ context.Context, obj *bug.SetParentOperation) (string, error) {
+	return obj.Parent.String(), nil
+}
+
+var _ graph.RemoveParentOperationResolver = removeParentOperationResolver{}
+
+type removeParentOperationResolver struct{}
+
+func (removeParentOperationResolver) ID(_ context.Context, obj *bug.RemoveParentOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (removeParentOperationResolver) Author(_ context.Context, obj *bug.RemoveParentOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (removeParentOperationResolver) Date(_ context.Context, obj *bug.RemoveParentOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (removeParentOperationResolver) Was(_ context.Context, obj *bug.RemoveParentOperation) (string, error) {
+	return obj.Was.String(), nil
+}
+
+var _ graph.AddChecklistItemOperationResolver = addChecklistItemOperationResolver{}
+
+type addChecklistItemOperationResolver struct{}
+
+func (addChecklistItemOperationResolver) ID(_ context.Context, obj *bug.AddChecklistItemOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (addChecklistItemOperationResolver) Author(_ context.Context, obj *bug.AddChecklistItemOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (addChecklistItemOperationResolver) Date(_ context.Context, obj *bug.AddChecklistItemOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+var _ graph.CheckItemOperationResolver = checkItemOperationResolver{}
+
+type checkItemOperationResolver struct{}
+
+func (checkItemOperationResolver) ID(_ context.Context, obj *bug.CheckItemOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (checkItemOperationResolver) Author(_ context.Context, obj *bug.CheckItemOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (checkItemOperationResolver) Date(_ context.Context, obj *bug.CheckItemOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (checkItemOperationResolver) Target(_ context.Context, obj *bug.CheckItemOperation) (string, error) {
+	return obj.Target.String(), nil
+}
+
+var _ graph.VoteOperationResolver = voteOperationResolver{}
+
+type voteOperationResolver struct{}
+
+func (voteOperationResolver) ID(_ context.Context, obj *bug.VoteOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (voteOperationResolver) Author(_ context.Context, obj *bug.VoteOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (voteOperationResolver) Date(_ context.Context, obj *bug.VoteOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+var _ graph.RequestReviewOperationResolver = requestReviewOperationResolver{}
+
+type requestReviewOperationResolver struct{}
+
+func (requestReviewOperationResolver) ID(_ context.Context, obj *bug.RequestReviewOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (requestReviewOperationResolver) Author(_ context.Context, obj *bug.RequestReviewOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (requestReviewOperationResolver) Date(_ context.Context, obj *bug.RequestReviewOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (requestReviewOperationResolver) Reviewers(_ context.Context, obj *bug.RequestReviewOperation) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Reviewers), nil
+}
+
+var _ graph.CustomOperationResolver = customOperationResolver{}
+
+type customOperationResolver struct{}
+
+func (customOperationResolver) ID(_ context.Context, obj *bug.CustomOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (customOperationResolver) Author(_ context.Context, obj *bug.CustomOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (customOperationResolver) Date(_ context.Context, obj *bug.CustomOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (customOperationResolver) Registered(_ context.Context, obj *bug.CustomOperation) (bool, error) {
+	return obj.IsRegistered(), nil
+}
+
+func (customOperationResolver) Data(_ context.Context, obj *bug.CustomOperation) (string, error) {
+	data, err := obj.Data()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+var _ graph.UnknownOperationResolver = unknownOperationResolver{}
+
+type unknownOperationResolver struct{}
+
+func (unknownOperationResolver) ID(_ context.Context, obj *bug.UnknownOperation) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (unknownOperationResolver) Author(_ context.Context, obj *bug.UnknownOperation) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (unknownOperationResolver) Date(_ context.Context, obj *bug.UnknownOperation) (*time.Time, error) {
+	t := obj.Time()
+	return &t, nil
+}
+
+func (unknownOperationResolver) FormatVersion(_ context.Context, obj *bug.UnknownOperation) (int, error) {
+	return int(obj.FormatVersion), nil
+}
+
+func loadedIdentities(identities []identity.Interface) []models.IdentityWrapper {
+	result := make([]models.IdentityWrapper, len(identities))
+	for i, id := range identities {
+		result[i] = models.NewLoadedIdentity(id)
+	}
+	return result
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/graphql/connections"
 	"github.com/MichaelMure/git-bug/graphql/graph"
+	"github.com/MichaelMure/git-bug/graphql/markdown"
 	"github.com/MichaelMure/git-bug/graphql/models"
 )
 
@@ -32,6 +34,10 @@ func (r rootQueryResolver) Repository(_ context.Context, ref *string) (*models.R
 	var err error
 
 	if ref == nil {
+		// several repositories served together have no default one
+		if len(r.cache.AllRepos()) > 1 {
+			return nil, nil
+		}
 		repo, err = r.cache.DefaultRepo()
 	} else {
 		repo, err = r.cache.ResolveRepo(*ref)
@@ -46,3 +52,111 @@ func (r rootQueryResolver) Repository(_ context.Context, ref *string) (*models.R
 		Repo:  repo,
 	}, nil
 }
+
+func (r rootQueryResolver) Repositories(_ context.Context) ([]*models.Repository, error) {
+	repos := r.cache.AllRepos()
+
+	result := make([]*models.Repository, len(repos))
+	for i, repo := range repos {
+		result[i] = &models.Repository{
+			Cache: r.cache,
+			Repo:  repo,
+		}
+	}
+
+	return result, nil
+}
+
+func (r rootQueryResolver) AllBugs(_ context.Context, after *string, before *string, first *int, last *int, queryStr *string) (*models.BugConnection, error) {
+	input := models.ConnectionInput{
+		Before: before,
+		After:  after,
+		First:  first,
+		Last:   last,
+	}
+
+	var query *cache.Query
+	if queryStr != nil {
+		query2, err := cache.ParseQuery(*queryStr)
+		if err != nil {
+			return nil, err
+		}
+		query = query2
+	} else {
+		query = cache.NewQuery()
+	}
+
+	// Simply pass the bugs of all the repositories to the pagination algorithm
+	source := r.cache.QueryBugs(query)
+
+	// The edger create a custom edge holding just the repository and the id
+	edger := func(repoBug cache.RepoBug, offset int) connections.Edge {
+		return connections.LazyRepoBugEdge{
+			RepoBug: repoBug,
+			Cursor:  connections.OffsetToCursor(offset),
+		}
+	}
+
+	// The conMaker will finally load the bugs from their repository to replace the selected edges
+	conMaker := func(lazyBugEdges []*connections.LazyRepoBugEdge, lazyNode []cache.RepoBug, info *models.PageInfo, totalCount int) (*models.BugConnection, error) {
+		edges := make([]*models.BugEdge, len(lazyBugEdges))
+		nodes := make([]models.BugWrapper, len(lazyBugEdges))
+
+		for i, lazyBugEdge := range lazyBugEdges {
+			excerpt, err := lazyBugEdge.Repo.ResolveBugExcerpt(lazyBugEdge.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			b := models.NewLazyBug(lazyBugEdge.Repo, excerpt)
+
+			edges[i] = &models.BugEdge{
+				Cursor: lazyBugEdge.Cursor,
+				Node:   b,
+			}
+			nodes[i] = b
+		}
+
+		return &models.BugConnection{
+			Edges:      edges,
+			Nodes:      nodes,
+			PageInfo:   info,
+			TotalCount: totalCount,
+		}, nil
+	}
+
+	return connections.LazyRepoBugCon(source, edger, conMaker, input)
+}
+
+func (r rootQueryResolver) Bug(_ context.Context, qualifiedId string) (models.BugWrapper, error) {
+	repo, excerpt, err := r.cache.ResolveBugExcerptQualified(qualifiedId)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewLazyBug(repo, excerpt), nil
+}
+
+func (r rootQueryResolver) Search(_ context.Context, text string, first *int) ([]models.BugWrapper, error) {
+	limit := 0
+	if first != nil {
+		limit = *first
+	}
+
+	repoBugs := r.cache.SearchBugsPrefix(text, limit)
+
+	result := make([]models.BugWrapper, len(repoBugs))
+	for i, repoBug := range repoBugs {
+		excerpt, err := repoBug.Repo.ResolveBugExcerpt(repoBug.Id)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = models.NewLazyBug(repoBug.Repo, excerpt)
+	}
+
+	return result, nil
+}
+
+func (r rootQueryResolver) RenderMarkdown(_ context.Context, source string) (string, error) {
+	return markdown.ToHTML(source), nil
+}
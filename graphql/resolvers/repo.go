@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"image/color"
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
@@ -9,8 +10,13 @@ import (
 	"github.com/MichaelMure/git-bug/graphql/connections"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 	"github.com/MichaelMure/git-bug/graphql/models"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
 )
 
+// the accent color of the web UI, as "#rrggbb"
+const accentColorConfigKey = "git-bug.webui.accent-color"
+
 var _ graph.RepositoryResolver = &repoResolver{}
 
 type repoResolver struct{}
@@ -42,6 +48,12 @@ func (repoResolver) AllBugs(_ context.Context, obj *models.Repository, after *st
 	// Simply pass a []string with the ids to the pagination algorithm
 	source := obj.Repo.QueryBugs(query)
 
+	return lazyBugConnection(obj.Repo, source, input)
+}
+
+// lazyBugConnection paginate the bugs with the given ids, loading them only
+// for the selected edges
+func lazyBugConnection(repo *cache.RepoCache, source []entity.Id, input models.ConnectionInput) (*models.BugConnection, error) {
 	// The edger create a custom edge holding just the id
 	edger := func(id entity.Id, offset int) connections.Edge {
 		return connections.LazyBugEdge{
@@ -56,12 +68,12 @@ func (repoResolver) AllBugs(_ context.Context, obj *models.Repository, after *st
 		nodes := make([]models.BugWrapper, len(lazyBugEdges))
 
 		for i, lazyBugEdge := range lazyBugEdges {
-			excerpt, err := obj.Repo.ResolveBugExcerpt(lazyBugEdge.Id)
+			excerpt, err := repo.ResolveBugExcerpt(lazyBugEdge.Id)
 			if err != nil {
 				return nil, err
 			}
 
-			b := models.NewLazyBug(obj.Repo, excerpt)
+			b := models.NewLazyBug(repo, excerpt)
 
 			edges[i] = &models.BugEdge{
 				Cursor: lazyBugEdge.Cursor,
@@ -90,6 +102,26 @@ func (repoResolver) Bug(_ context.Context, obj *models.Repository, prefix string
 	return models.NewLazyBug(obj.Repo, excerpt), nil
 }
 
+func (repoResolver) SearchBugs(_ context.Context, obj *models.Repository, text string, first *int) ([]models.BugWrapper, error) {
+	limit := 0
+	if first != nil {
+		limit = *first
+	}
+
+	ids := obj.Repo.SearchBugs(text, limit)
+
+	result := make([]models.BugWrapper, len(ids))
+	for i, id := range ids {
+		excerpt, err := obj.Repo.ResolveBugExcerpt(id)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = models.NewLazyBug(obj.Repo, excerpt)
+	}
+
+	return result, nil
+}
+
 func (repoResolver) AllIdentities(_ context.Context, obj *models.Repository, after *string, before *string, first *int, last *int) (*models.IdentityConnection, error) {
 	input := models.ConnectionInput{
 		Before: before,
@@ -149,8 +181,25 @@ func (repoResolver) Identity(_ context.Context, obj *models.Repository, prefix s
 	return models.NewLazyIdentity(obj.Repo, excerpt), nil
 }
 
+func (repoResolver) IdentityActivity(_ context.Context, obj *models.Repository, prefix string) (*models.IdentityActivity, error) {
+	excerpt, err := obj.Repo.ResolveIdentityExcerptPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IdentityActivity{
+		Repo:     obj.Repo,
+		Identity: models.NewLazyIdentity(obj.Repo, excerpt),
+		Activity: obj.Repo.IdentityActivity(excerpt.Id),
+	}, nil
+}
+
 func (repoResolver) UserIdentity(_ context.Context, obj *models.Repository) (models.IdentityWrapper, error) {
 	excerpt, err := obj.Repo.GetUserIdentityExcerpt()
+	// a repository served read-only can have no user identity
+	if err == identity.ErrNoIdentitySet {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -184,3 +233,62 @@ func (repoResolver) ValidLabels(_ context.Context, obj *models.Repository, after
 
 	return connections.LabelCon(obj.Repo.ValidLabels(), edger, conMaker, input)
 }
+
+func (repoResolver) LabelsUsage(_ context.Context, obj *models.Repository) ([]*cache.LabelUsage, error) {
+	usages := obj.Repo.LabelsUsage()
+
+	result := make([]*cache.LabelUsage, len(usages))
+	for i := range usages {
+		result[i] = &usages[i]
+	}
+
+	return result, nil
+}
+
+func (repoResolver) Labels(_ context.Context, obj *models.Repository) ([]*cache.LabelEntry, error) {
+	entries, err := obj.Repo.LabelEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*cache.LabelEntry, len(entries))
+	for i := range entries {
+		result[i] = &entries[i]
+	}
+
+	return result, nil
+}
+
+func (repoResolver) AccentColor(_ context.Context, obj *models.Repository) (*color.RGBA, error) {
+	raw, err := obj.Repo.LocalConfig().ReadString(accentColorConfigKey)
+	if err == repository.ErrNoConfigEntry {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHexColor(raw)
+}
+
+func parseHexColor(raw string) (*color.RGBA, error) {
+	lc, err := bug.ParseLabelColor(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	rgba := lc.RGBA()
+	return &rgba, nil
+}
+
+func (repoResolver) Requirements(_ context.Context, obj *models.Repository) (*bug.Requirements, error) {
+	requirements, err := obj.Repo.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	return &requirements, nil
+}
+
+func (repoResolver) Forms(_ context.Context, obj *models.Repository) ([]*bug.Form, error) {
+	return obj.Repo.Forms()
+}
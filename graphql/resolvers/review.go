@@ -0,0 +1,31 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/graphql/graph"
+	"github.com/MichaelMure/git-bug/graphql/models"
+)
+
+var _ graph.ReviewRequestResolver = &reviewRequestResolver{}
+
+type reviewRequestResolver struct{}
+
+func (reviewRequestResolver) ID(_ context.Context, obj *bug.ReviewRequest) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (reviewRequestResolver) Author(_ context.Context, obj *bug.ReviewRequest) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Requester), nil
+}
+
+func (reviewRequestResolver) Reviewer(_ context.Context, obj *bug.ReviewRequest) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Reviewer), nil
+}
+
+func (reviewRequestResolver) CreatedAt(_ context.Context, obj *bug.ReviewRequest) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
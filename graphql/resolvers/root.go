@@ -2,6 +2,8 @@
 package resolvers
 
 import (
+	"sync"
+
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/graphql/graph"
 )
@@ -10,11 +12,16 @@ var _ graph.ResolverRoot = &RootResolver{}
 
 type RootResolver struct {
 	cache.MultiRepoCache
+
+	// serialize the mutations, for their preconditions to hold until they
+	// are applied
+	mutationLock *sync.Mutex
 }
 
 func NewRootResolver() *RootResolver {
 	return &RootResolver{
 		MultiRepoCache: cache.NewMultiRepoCache(),
+		mutationLock:   &sync.Mutex{},
 	}
 }
 
@@ -27,6 +34,7 @@ func (r RootResolver) Query() graph.QueryResolver {
 func (r RootResolver) Mutation() graph.MutationResolver {
 	return &mutationResolver{
 		cache: &r.MultiRepoCache,
+		lock:  r.mutationLock,
 	}
 }
 
@@ -34,26 +42,46 @@ func (RootResolver) Repository() graph.RepositoryResolver {
 	return &repoResolver{}
 }
 
-func (RootResolver) Bug() graph.BugResolver {
-	return &bugResolver{}
+func (r RootResolver) Bug() graph.BugResolver {
+	return &bugResolver{
+		cache: &r.MultiRepoCache,
+	}
 }
 
 func (RootResolver) Color() graph.ColorResolver {
 	return &colorResolver{}
 }
 
+func (r RootResolver) ChecklistItem() graph.ChecklistItemResolver {
+	return &checklistItemResolver{}
+}
+
+func (r RootResolver) ReviewRequest() graph.ReviewRequestResolver {
+	return &reviewRequestResolver{}
+}
+
+func (RootResolver) FormField() graph.FormFieldResolver {
+	return &formFieldResolver{}
+}
+
 func (r RootResolver) Comment() graph.CommentResolver {
 	return &commentResolver{}
 }
 
-func (RootResolver) Label() graph.LabelResolver {
-	return &labelResolver{}
+func (r RootResolver) Label() graph.LabelResolver {
+	return &labelResolver{
+		cache: &r.MultiRepoCache,
+	}
 }
 
 func (r RootResolver) Identity() graph.IdentityResolver {
 	return &identityResolver{}
 }
 
+func (RootResolver) IdentityActivity() graph.IdentityActivityResolver {
+	return &identityActivityResolver{}
+}
+
 func (RootResolver) CommentHistoryStep() graph.CommentHistoryStepResolver {
 	return &commentHistoryStepResolver{}
 }
@@ -78,6 +106,46 @@ func (r RootResolver) SetTitleTimelineItem() graph.SetTitleTimelineItemResolver
 	return &setTitleTimelineItem{}
 }
 
+func (r RootResolver) SetComponentTimelineItem() graph.SetComponentTimelineItemResolver {
+	return &setComponentTimelineItem{}
+}
+
+func (r RootResolver) AssigneeChangeTimelineItem() graph.AssigneeChangeTimelineItemResolver {
+	return &assigneeChangeTimelineItem{}
+}
+
+func (r RootResolver) MarkDuplicateTimelineItem() graph.MarkDuplicateTimelineItemResolver {
+	return &markDuplicateTimelineItem{}
+}
+
+func (r RootResolver) SetParentTimelineItem() graph.SetParentTimelineItemResolver {
+	return &setParentTimelineItem{}
+}
+
+func (r RootResolver) RemoveParentTimelineItem() graph.RemoveParentTimelineItemResolver {
+	return &removeParentTimelineItem{}
+}
+
+func (r RootResolver) AddChecklistItemTimelineItem() graph.AddChecklistItemTimelineItemResolver {
+	return &addChecklistItemTimelineItem{}
+}
+
+func (r RootResolver) CheckItemTimelineItem() graph.CheckItemTimelineItemResolver {
+	return &checkItemTimelineItem{}
+}
+
+func (r RootResolver) VoteTimelineItem() graph.VoteTimelineItemResolver {
+	return &voteTimelineItem{}
+}
+
+func (r RootResolver) RequestReviewTimelineItem() graph.RequestReviewTimelineItemResolver {
+	return &requestReviewTimelineItem{}
+}
+
+func (r RootResolver) UnknownTimelineItem() graph.UnknownTimelineItemResolver {
+	return &unknownTimelineItem{}
+}
+
 func (RootResolver) CreateOperation() graph.CreateOperationResolver {
 	return &createOperationResolver{}
 }
@@ -102,6 +170,50 @@ func (RootResolver) SetTitleOperation() graph.SetTitleOperationResolver {
 	return &setTitleOperationResolver{}
 }
 
+func (RootResolver) SetComponentOperation() graph.SetComponentOperationResolver {
+	return &setComponentOperationResolver{}
+}
+
+func (RootResolver) AssigneeChangeOperation() graph.AssigneeChangeOperationResolver {
+	return &assigneeChangeOperationResolver{}
+}
+
+func (RootResolver) MarkDuplicateOperation() graph.MarkDuplicateOperationResolver {
+	return &markDuplicateOperationResolver{}
+}
+
+func (RootResolver) SetParentOperation() graph.SetParentOperationResolver {
+	return &setParentOperationResolver{}
+}
+
+func (RootResolver) RemoveParentOperation() graph.RemoveParentOperationResolver {
+	return &removeParentOperationResolver{}
+}
+
+func (RootResolver) AddChecklistItemOperation() graph.AddChecklistItemOperationResolver {
+	return &addChecklistItemOperationResolver{}
+}
+
+func (RootResolver) CheckItemOperation() graph.CheckItemOperationResolver {
+	return &checkItemOperationResolver{}
+}
+
+func (RootResolver) VoteOperation() graph.VoteOperationResolver {
+	return &voteOperationResolver{}
+}
+
+func (RootResolver) RequestReviewOperation() graph.RequestReviewOperationResolver {
+	return &requestReviewOperationResolver{}
+}
+
+func (RootResolver) CustomOperation() graph.CustomOperationResolver {
+	return &customOperationResolver{}
+}
+
+func (RootResolver) UnknownOperation() graph.UnknownOperationResolver {
+	return &unknownOperationResolver{}
+}
+
 func (r RootResolver) LabelChangeResult() graph.LabelChangeResultResolver {
 	return &labelChangeResultResolver{}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/graphql/graph"
+	"github.com/MichaelMure/git-bug/graphql/markdown"
 	"github.com/MichaelMure/git-bug/graphql/models"
 )
 
@@ -26,6 +27,14 @@ func (addCommentTimelineItemResolver) ID(_ context.Context, obj *bug.AddCommentT
 	return obj.Id().String(), nil
 }
 
+func (addCommentTimelineItemResolver) MessageHTML(_ context.Context, obj *bug.AddCommentTimelineItem) (string, error) {
+	return markdown.ToHTML(obj.Message), nil
+}
+
+func (addCommentTimelineItemResolver) ReplyTo(_ context.Context, obj *bug.AddCommentTimelineItem) (*string, error) {
+	return replyTo(obj.ReplyTo), nil
+}
+
 func (addCommentTimelineItemResolver) Author(_ context.Context, obj *bug.AddCommentTimelineItem) (models.IdentityWrapper, error) {
 	return models.NewLoadedIdentity(obj.Author), nil
 }
@@ -48,6 +57,10 @@ func (createTimelineItemResolver) ID(_ context.Context, obj *bug.CreateTimelineI
 	return obj.Id().String(), nil
 }
 
+func (createTimelineItemResolver) MessageHTML(_ context.Context, obj *bug.CreateTimelineItem) (string, error) {
+	return markdown.ToHTML(obj.Message), nil
+}
+
 func (r createTimelineItemResolver) Author(_ context.Context, obj *bug.CreateTimelineItem) (models.IdentityWrapper, error) {
 	return models.NewLoadedIdentity(obj.Author), nil
 }
@@ -100,6 +113,10 @@ func (setStatusTimelineItem) Status(_ context.Context, obj *bug.SetStatusTimelin
 	return convertStatus(obj.Status)
 }
 
+func (setStatusTimelineItem) Resolution(_ context.Context, obj *bug.SetStatusTimelineItem) (*models.Resolution, error) {
+	return convertResolution(obj.Resolution)
+}
+
 var _ graph.SetTitleTimelineItemResolver = setTitleTimelineItem{}
 
 type setTitleTimelineItem struct{}
@@ -116,3 +133,205 @@ func (setTitleTimelineItem) Date(_ context.Context, obj *bug.SetTitleTimelineIte
 	t := obj.UnixTime.Time()
 	return &t, nil
 }
+
+var _ graph.SetComponentTimelineItemResolver = setComponentTimelineItem{}
+
+type setComponentTimelineItem struct{}
+
+func (setComponentTimelineItem) ID(_ context.Context, obj *bug.SetComponentTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (setComponentTimelineItem) Author(_ context.Context, obj *bug.SetComponentTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (setComponentTimelineItem) Date(_ context.Context, obj *bug.SetComponentTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+var _ graph.AssigneeChangeTimelineItemResolver = assigneeChangeTimelineItem{}
+
+type assigneeChangeTimelineItem struct{}
+
+func (assigneeChangeTimelineItem) ID(_ context.Context, obj *bug.AssigneeChangeTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (assigneeChangeTimelineItem) Author(_ context.Context, obj *bug.AssigneeChangeTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (assigneeChangeTimelineItem) Date(_ context.Context, obj *bug.AssigneeChangeTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+func (assigneeChangeTimelineItem) Added(_ context.Context, obj *bug.AssigneeChangeTimelineItem) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Added), nil
+}
+
+func (assigneeChangeTimelineItem) Removed(_ context.Context, obj *bug.AssigneeChangeTimelineItem) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Removed), nil
+}
+
+var _ graph.MarkDuplicateTimelineItemResolver = markDuplicateTimelineItem{}
+
+type markDuplicateTimelineItem struct{}
+
+func (markDuplicateTimelineItem) ID(_ context.Context, obj *bug.MarkDuplicateTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (markDuplicateTimelineItem) Author(_ context.Context, obj *bug.MarkDuplicateTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (markDuplicateTimelineItem) Date(_ context.Context, obj *bug.MarkDuplicateTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+func (markDuplicateTimelineItem) Target(_ context.Context, obj *bug.MarkDuplicateTimelineItem) (string, error) {
+	return obj.Target.String(), nil
+}
+
+var _ graph.SetParentTimelineItemResolver = setParentTimelineItem{}
+
+type setParentTimelineItem struct{}
+
+func (setParentTimelineItem) ID(_ context.Context, obj *bug.SetParentTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (setParentTimelineItem) Author(_ context.Context, obj *bug.SetParentTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (setParentTimelineItem) Date(_ context.Context, obj *bug.SetParentTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+func (setParentTimelineItem) Parent(_ context.Context, obj *bug.SetParentTimelineItem) (string, error) {
+	return obj.Parent.String(), nil
+}
+
+var _ graph.RemoveParentTimelineItemResolver = removeParentTimelineItem{}
+
+type removeParentTimelineItem struct{}
+
+func (removeParentTimelineItem) ID(_ context.Context, obj *bug.RemoveParentTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (removeParentTimelineItem) Author(_ context.Context, obj *bug.RemoveParentTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (removeParentTimelineItem) Date(_ context.Context, obj *bug.RemoveParentTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+func (removeParentTimelineItem) Was(_ context.Context, obj *bug.RemoveParentTimelineItem) (string, error) {
+	return obj.Was.String(), nil
+}
+
+var _ graph.AddChecklistItemTimelineItemResolver = addChecklistItemTimelineItem{}
+
+type addChecklistItemTimelineItem struct{}
+
+func (addChecklistItemTimelineItem) ID(_ context.Context, obj *bug.AddChecklistItemTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (addChecklistItemTimelineItem) Author(_ context.Context, obj *bug.AddChecklistItemTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (addChecklistItemTimelineItem) Date(_ context.Context, obj *bug.AddChecklistItemTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+var _ graph.CheckItemTimelineItemResolver = checkItemTimelineItem{}
+
+type checkItemTimelineItem struct{}
+
+func (checkItemTimelineItem) ID(_ context.Context, obj *bug.CheckItemTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (checkItemTimelineItem) Author(_ context.Context, obj *bug.CheckItemTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (checkItemTimelineItem) Date(_ context.Context, obj *bug.CheckItemTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+func (checkItemTimelineItem) Target(_ context.Context, obj *bug.CheckItemTimelineItem) (string, error) {
+	return obj.Target.String(), nil
+}
+
+var _ graph.VoteTimelineItemResolver = voteTimelineItem{}
+
+type voteTimelineItem struct{}
+
+func (voteTimelineItem) ID(_ context.Context, obj *bug.VoteTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (voteTimelineItem) Author(_ context.Context, obj *bug.VoteTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (voteTimelineItem) Date(_ context.Context, obj *bug.VoteTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+var _ graph.RequestReviewTimelineItemResolver = requestReviewTimelineItem{}
+
+type requestReviewTimelineItem struct{}
+
+func (requestReviewTimelineItem) ID(_ context.Context, obj *bug.RequestReviewTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (requestReviewTimelineItem) Author(_ context.Context, obj *bug.RequestReviewTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (requestReviewTimelineItem) Date(_ context.Context, obj *bug.RequestReviewTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+func (requestReviewTimelineItem) Reviewers(_ context.Context, obj *bug.RequestReviewTimelineItem) ([]models.IdentityWrapper, error) {
+	return loadedIdentities(obj.Reviewers), nil
+}
+
+var _ graph.UnknownTimelineItemResolver = unknownTimelineItem{}
+
+type unknownTimelineItem struct{}
+
+func (unknownTimelineItem) ID(_ context.Context, obj *bug.UnknownTimelineItem) (string, error) {
+	return obj.Id().String(), nil
+}
+
+func (unknownTimelineItem) Author(_ context.Context, obj *bug.UnknownTimelineItem) (models.IdentityWrapper, error) {
+	return models.NewLoadedIdentity(obj.Author), nil
+}
+
+func (unknownTimelineItem) Date(_ context.Context, obj *bug.UnknownTimelineItem) (*time.Time, error) {
+	t := obj.UnixTime.Time()
+	return &t, nil
+}
+
+func (unknownTimelineItem) FormatVersion(_ context.Context, obj *bug.UnknownTimelineItem) (int, error) {
+	return int(obj.FormatVersion), nil
+}
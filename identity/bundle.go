@@ -0,0 +1,190 @@
+package identity
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+const bundleFormatVersion = 1
+
+// Bundle is a portable export of identities with their full history, to move
+// them to another repository without a common git remote.
+//
+// As the id of an identity is the hash of its first commit, the git commits
+// are exported as is, so that the identities keep their id once imported.
+type Bundle struct {
+	// Additional field to version the data
+	FormatVersion uint              `json:"version"`
+	Identities    []BundledIdentity `json:"identities"`
+}
+
+// BundledIdentity is an identity in a Bundle
+type BundledIdentity struct {
+	Id       entity.Id        `json:"id"`
+	Versions []BundledVersion `json:"versions"`
+}
+
+// BundledVersion is a version of an identity, with the git commit storing it
+type BundledVersion struct {
+	Hash git.Hash `json:"hash"`
+	// the raw git commit
+	Commit []byte `json:"commit"`
+	// the serialized version, as stored in git
+	Version []byte `json:"data"`
+}
+
+// ExportBundle create a Bundle holding the given local identities
+func ExportBundle(repo repository.Repo, ids []entity.Id) (*Bundle, error) {
+	bundle := &Bundle{FormatVersion: bundleFormatVersion}
+
+	for _, id := range ids {
+		i, err := ReadLocal(repo, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "identity %s", id.Human())
+		}
+
+		bundled := BundledIdentity{Id: i.Id()}
+
+		for _, v := range i.versions {
+			commit, err := repo.ReadCommitData(v.commitHash)
+			if err != nil {
+				return nil, err
+			}
+
+			entries, err := repo.ListEntries(v.commitHash)
+			if err != nil {
+				return nil, errors.Wrap(err, "can't list git tree entries")
+			}
+			if len(entries) != 1 || entries[0].Name != versionEntryName {
+				return nil, fmt.Errorf("invalid identity data at hash %s", v.commitHash)
+			}
+
+			data, err := repo.ReadData(entries[0].Hash)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read git blob data")
+			}
+
+			bundled.Versions = append(bundled.Versions, BundledVersion{
+				Hash:    v.commitHash,
+				Commit:  commit,
+				Version: data,
+			})
+		}
+
+		bundle.Identities = append(bundle.Identities, bundled)
+	}
+
+	return bundle, nil
+}
+
+// ImportBundle store the identities of a Bundle in the repository. As for the
+// identities of a remote, an identity already known is only updated if the
+// bundle hold a fast-forward of it.
+func ImportBundle(repo repository.ClockedRepo, bundle *Bundle) ([]entity.MergeResult, error) {
+	if bundle.FormatVersion != bundleFormatVersion {
+		return nil, fmt.Errorf("unknown identity bundle format version %v", bundle.FormatVersion)
+	}
+
+	results := make([]entity.MergeResult, 0, len(bundle.Identities))
+
+	for _, bundled := range bundle.Identities {
+		result, err := importBundled(repo, bundled)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func importBundled(repo repository.ClockedRepo, bundled BundledIdentity) (entity.MergeResult, error) {
+	id := bundled.Id
+
+	if err := id.Validate(); err != nil {
+		return entity.NewMergeInvalidStatus(id, errors.Wrap(err, "invalid id").Error()), nil
+	}
+
+	if len(bundled.Versions) == 0 {
+		return entity.NewMergeInvalidStatus(id, "no version"), nil
+	}
+
+	for _, v := range bundled.Versions {
+		blobHash, err := repo.StoreData(v.Version)
+		if err != nil {
+			return entity.MergeResult{}, err
+		}
+
+		treeHash, err := repo.StoreTree([]repository.TreeEntry{
+			{ObjectType: repository.Blob, Hash: blobHash, Name: versionEntryName},
+		})
+		if err != nil {
+			return entity.MergeResult{}, err
+		}
+
+		commitHash, err := repo.StoreCommitData(v.Commit)
+		if err != nil {
+			return entity.NewMergeInvalidStatus(id, errors.Wrap(err, "invalid commit").Error()), nil
+		}
+
+		if commitHash != v.Hash {
+			return entity.NewMergeInvalidStatus(id, fmt.Sprintf("altered commit %s", v.Hash)), nil
+		}
+
+		commitTree, err := repo.GetTreeHash(commitHash)
+		if err != nil {
+			return entity.MergeResult{}, err
+		}
+		if commitTree != treeHash {
+			return entity.NewMergeInvalidStatus(id, fmt.Sprintf("altered data at hash %s", v.Hash)), nil
+		}
+	}
+
+	last := bundled.Versions[len(bundled.Versions)-1].Hash
+
+	// the data of a bundle is checked as the one of a remote
+	imported, err := readRev(repo, id, string(last), true)
+	if err != nil {
+		return entity.NewMergeInvalidStatus(id, errors.Wrap(err, "identity is not readable").Error()), nil
+	}
+
+	if err := imported.Validate(); err != nil {
+		return entity.NewMergeInvalidStatus(id, errors.Wrap(err, "identity is invalid").Error()), nil
+	}
+
+	localRef := identityRefPattern + id.String()
+	localExist, err := repo.RefExist(localRef)
+	if err != nil {
+		return entity.MergeResult{}, err
+	}
+
+	if !localExist {
+		err = repo.UpdateRef(localRef, last)
+		if err != nil {
+			return entity.MergeResult{}, err
+		}
+		return entity.NewMergeStatus(entity.MergeStatusNew, id, imported), nil
+	}
+
+	local, err := read(repo, localRef, false)
+	if err != nil {
+		return entity.MergeResult{}, errors.Wrap(err, "local identity is not readable")
+	}
+
+	before := local.lastCommit
+
+	_, err = local.Merge(repo, imported)
+	if err != nil {
+		return entity.NewMergeInvalidStatus(id, errors.Wrap(err, "merge failed").Error()), nil
+	}
+
+	if local.lastCommit != before {
+		return entity.NewMergeStatus(entity.MergeStatusUpdated, id, local), nil
+	}
+	return entity.NewMergeStatus(entity.MergeStatusNothing, id, local), nil
+}
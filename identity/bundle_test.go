@@ -0,0 +1,84 @@
+package identity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBundle(t *testing.T) {
+	repoA := repository.CreateTestRepo(false)
+	repoB := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repoA, repoB)
+
+	identity1 := NewIdentity("name1", "email1")
+	require.NoError(t, identity1.Commit(repoA))
+	identity1.addVersionForTest(&Version{
+		name:  "name1b",
+		email: "email1b",
+		keys:  []*Key{{Fingerprint: "fingerprint", PubKey: "key"}},
+		nonce: makeNonce(20),
+	})
+	require.NoError(t, identity1.Commit(repoA))
+
+	identity2 := NewIdentity("name2", "email2")
+	require.NoError(t, identity2.Commit(repoA))
+
+	bundle, err := ExportBundle(repoA, []entity.Id{identity1.Id(), identity2.Id()})
+	require.NoError(t, err)
+
+	// the bundle go through its JSON serialization
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	require.NoError(t, err)
+	var decoded Bundle
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	results, err := ImportBundle(repoB, &decoded)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, entity.MergeStatusNew, results[0].Status)
+	require.Equal(t, entity.MergeStatusNew, results[1].Status)
+
+	// the identities keep their id and history
+	imported, err := ReadLocal(repoB, identity1.Id())
+	require.NoError(t, err)
+	require.Len(t, imported.Versions(), 2)
+	require.Equal(t, "name1b", imported.Name())
+	require.Equal(t, identity1.Keys(), imported.Keys())
+
+	// importing again does nothing
+	results, err = ImportBundle(repoB, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, entity.MergeStatusNothing, results[0].Status)
+
+	// a newer version is a fast-forward
+	identity2.addVersionForTest(&Version{
+		name:  "name2b",
+		email: "email2b",
+		nonce: makeNonce(20),
+	})
+	require.NoError(t, identity2.Commit(repoA))
+	bundle, err = ExportBundle(repoA, []entity.Id{identity2.Id()})
+	require.NoError(t, err)
+	results, err = ImportBundle(repoB, bundle)
+	require.NoError(t, err)
+	require.Equal(t, entity.MergeStatusUpdated, results[0].Status)
+
+	imported, err = ReadLocal(repoB, identity2.Id())
+	require.NoError(t, err)
+	require.Equal(t, "name2b", imported.Name())
+
+	// an altered version is rejected
+	bundle.Identities[0].Versions[1].Version = []byte(`{"version":1,"name":"evil"}`)
+	results, err = ImportBundle(repoB, bundle)
+	require.NoError(t, err)
+	require.Equal(t, entity.MergeStatusInvalid, results[0].Status)
+
+	bundle.FormatVersion = 42
+	_, err = ImportBundle(repoB, bundle)
+	require.Error(t, err)
+}
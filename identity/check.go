@@ -0,0 +1,61 @@
+package identity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// maxClockSkew is how far in the future the timestamp of a version can be
+// before being considered bogus
+const maxClockSkew = 24 * time.Hour
+
+// CheckLocal verify a local identity before sharing it: the versions are
+// checked against the JSON schema and validated, and must not be dated in
+// the future.
+//
+// The lamport times of the versions are not compared to the local clock, as
+// pulling an identity doesn't witness them.
+func CheckLocal(repo repository.Repo, id entity.Id) error {
+	ref := fmt.Sprintf("%s%s", identityRefPattern, id)
+	i, err := read(repo, ref, true)
+	if err != nil {
+		return err
+	}
+
+	return check(i)
+}
+
+// CheckPushed verify an identity pushed to this repository, before its ref
+// is updated to the new head. The same checks as CheckLocal apply.
+func CheckPushed(repo repository.Repo, id entity.Id, head git.Hash) error {
+	if err := id.Validate(); err != nil {
+		return err
+	}
+
+	i, err := readRev(repo, id, head.String(), true)
+	if err != nil {
+		return err
+	}
+
+	return check(i)
+}
+
+func check(i *Identity) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	limit := time.Now().Add(maxClockSkew)
+
+	for _, v := range i.versions {
+		if time.Unix(v.unixTime, 0).After(limit) {
+			return fmt.Errorf("version %s is dated in the future", v.commitHash)
+		}
+	}
+
+	return nil
+}
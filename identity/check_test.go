@@ -0,0 +1,40 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+func TestCheckLocal(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	identity := NewIdentity("René Descartes", "rene@descartes.fr")
+	err := identity.Commit(repo)
+	require.NoError(t, err)
+
+	require.NoError(t, CheckLocal(repo, identity.Id()))
+
+	require.Error(t, CheckLocal(repo, "0123456789012345678901234567890123456789"))
+}
+
+func TestCheckPushed(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	identity := NewIdentity("René Descartes", "rene@descartes.fr")
+	err := identity.Commit(repo)
+	require.NoError(t, err)
+
+	require.NoError(t, CheckPushed(repo, identity.Id(), identity.lastCommit))
+
+	resolver := NewPushedResolver(repo, map[entity.Id]git.Hash{
+		identity.Id(): identity.lastCommit,
+	})
+	resolved, err := resolver.ResolveIdentity(identity.Id())
+	require.NoError(t, err)
+	require.Equal(t, identity.Name(), resolved.Name())
+}
@@ -22,8 +22,9 @@ func NewErrMultipleMatch(matching []entity.Id) *entity.ErrMultipleMatch {
 func UnmarshalJSON(raw json.RawMessage) (Interface, error) {
 	aux := &IdentityStub{}
 
-	// First try to decode and load as a normal Identity
-	err := json.Unmarshal(raw, &aux)
+	// First try to decode and load as a normal Identity. A null identity
+	// leave aux as it is.
+	err := json.Unmarshal(raw, aux)
 	if err == nil && aux.Id() != "" {
 		return aux, nil
 	}
@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Fixtures return the serialized data of a Version, by file name. The data is
+// the same on every run, to be compared with the golden files of the tests and
+// catch the changes of the format between versions.
+func Fixtures() (map[string][]byte, error) {
+	v := &Version{
+		time:      3,
+		unixTime:  1577836800,
+		name:      "René Descartes",
+		email:     "rene@descartes.fr",
+		login:     "rene",
+		avatarURL: "https://example.com/rene.png",
+		keys: []*Key{
+			{
+				Fingerprint: "fingerprint",
+				PubKey:      "pubkey",
+			},
+		},
+		nonce: []byte("fixture nonce"),
+		metadata: map[string]string{
+			"origin": "fixture",
+		},
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		fmt.Sprintf("identity-version-v%d.json", formatVersion): data,
+	}, nil
+}
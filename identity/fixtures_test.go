@@ -0,0 +1,61 @@
+package identity
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateFixtures = flag.Bool("update", false, "update the golden files of the fixtures")
+
+const fixturesDir = "testdata/fixtures"
+
+func TestFixtures(t *testing.T) {
+	fixtures, err := Fixtures()
+	require.NoError(t, err)
+
+	for name, data := range fixtures {
+		path := filepath.Join(fixturesDir, name)
+
+		if *updateFixtures {
+			require.NoError(t, ioutil.WriteFile(path, data, 0644))
+		}
+
+		golden, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(golden), string(data),
+			"the format of %s changed, run the tests with -update if it is intended", name)
+	}
+}
+
+// TestFixturesDecode check that the golden files of every format version can
+// still be read
+func TestFixturesDecode(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(fixturesDir, "identity-version-*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+
+		var version Version
+		require.NoError(t, json.Unmarshal(data, &version), path)
+		assert.Equal(t, isNewerFormat(data), version.newerFormat, path)
+		assert.NoError(t, version.Validate(), path)
+
+		if version.newerFormat {
+			continue
+		}
+
+		// the current format is written back as is
+		again, err := json.Marshal(&version)
+		require.NoError(t, err)
+		assert.Equal(t, string(data), string(again), path)
+	}
+}
@@ -0,0 +1,36 @@
+package identity
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzVersion check that any data from a remote is either decoded or refused,
+// without panicking
+func FuzzVersion(f *testing.F) {
+	paths, _ := filepath.Glob(filepath.Join(fixturesDir, "*.json"))
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = isNewerFormat(data)
+
+		var version Version
+		if err := json.Unmarshal(data, &version); err != nil {
+			return
+		}
+
+		_ = version.Validate()
+
+		if _, err := json.Marshal(&version); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
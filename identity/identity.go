@@ -13,6 +13,7 @@ import (
 
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/schema"
 	"github.com/MichaelMure/git-bug/util/git"
 	"github.com/MichaelMure/git-bug/util/lamport"
 	"github.com/MichaelMure/git-bug/util/timestamp"
@@ -23,12 +24,21 @@ const identityRemoteRefPattern = "refs/remotes/%s/identities/"
 const versionEntryName = "version"
 const identityConfigKey = "git-bug.identity"
 
+const anonymousName = "Anonymous"
+const anonymousMetadataKey = "anonymous"
+
 var ErrNonFastForwardMerge = errors.New("non fast-forward identity merge")
 var ErrNoIdentitySet = errors.New("No identity is set.\n" +
 	"To interact with bugs, an identity first needs to be created using " +
-	"\"git bug user create\"")
+	"\"git bug init\" or \"git bug user create\".\n" +
+	"Alternatively, set \"git-bug.auto-identity\" to true in the git config " +
+	"to create it from user.name and user.email when needed.")
 var ErrMultipleIdentitiesSet = errors.New("multiple user identities set")
 
+// ErrNewerFormat is returned when editing an identity holding versions written
+// by a newer version of git-bug
+var ErrNewerFormat = errors.New("the identity has been edited with a newer version of git-bug, update git-bug to edit it")
+
 var _ Interface = &Identity{}
 var _ entity.Interface = &Identity{}
 
@@ -71,6 +81,38 @@ func NewIdentityFull(name string, email string, login string, avatarUrl string)
 	}
 }
 
+// NewAnonymousIdentity create a throwaway identity, without name or email.
+// The identifier is made unique by the nonce, and the identity is flagged as
+// anonymous in its metadata.
+func NewAnonymousIdentity() *Identity {
+	nonce := makeNonce(32)
+
+	i := &Identity{
+		id: entity.UnsetId,
+		versions: []*Version{
+			{
+				name:  anonymousName,
+				login: fmt.Sprintf("anonymous-%x", nonce[:4]),
+				nonce: nonce,
+			},
+		},
+	}
+
+	i.SetMetadata(anonymousMetadataKey, "true")
+
+	return i
+}
+
+// IsAnonymous tell if an identity has been created with NewAnonymousIdentity
+func IsAnonymous(i Interface) bool {
+	switch i := i.(type) {
+	case *Identity:
+		return i.ImmutableMetadata()[anonymousMetadataKey] == "true"
+	default:
+		return false
+	}
+}
+
 // MarshalJSON will only serialize the id
 func (i *Identity) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&IdentityStub{
@@ -88,17 +130,19 @@ func (i *Identity) UnmarshalJSON(data []byte) error {
 // ReadLocal load a local Identity from the identities data available in git
 func ReadLocal(repo repository.Repo, id entity.Id) (*Identity, error) {
 	ref := fmt.Sprintf("%s%s", identityRefPattern, id)
-	return read(repo, ref)
+	return read(repo, ref, false)
 }
 
 // ReadRemote load a remote Identity from the identities data available in git
 func ReadRemote(repo repository.Repo, remote string, id string) (*Identity, error) {
 	ref := fmt.Sprintf(identityRemoteRefPattern, remote) + id
-	return read(repo, ref)
+	return read(repo, ref, true)
 }
 
-// read will load and parse an identity from git
-func read(repo repository.Repo, ref string) (*Identity, error) {
+// read will load and parse an identity from git. If validateSchema is true,
+// the versions are checked against the JSON schema, as done for the ones
+// coming from a remote.
+func read(repo repository.Repo, ref string, validateSchema bool) (*Identity, error) {
 	refSplit := strings.Split(ref, "/")
 	id := entity.Id(refSplit[len(refSplit)-1])
 
@@ -106,13 +150,24 @@ func read(repo repository.Repo, ref string) (*Identity, error) {
 		return nil, errors.Wrap(err, "invalid ref")
 	}
 
-	hashes, err := repo.ListCommits(ref)
+	return readRev(repo, id, ref, validateSchema)
+}
+
+// readRev will load and parse an identity from the chain of commits ending at
+// rev, either a ref or a commit hash
+func readRev(repo repository.Repo, id entity.Id, rev string, validateSchema bool) (*Identity, error) {
+	hashes, err := repo.ListCommits(rev)
 
 	// TODO: this is not perfect, it might be a command invoke error
 	if err != nil {
 		return nil, ErrIdentityNotExist
 	}
 
+	// the id is the hash of the first commit, missing in a shallow clone
+	if len(hashes) > 0 && string(hashes[0]) != id.String() {
+		return nil, entity.NewErrIncompleteHistory("identity", id)
+	}
+
 	i := &Identity{
 		id: id,
 	}
@@ -138,6 +193,13 @@ func read(repo repository.Repo, ref string) (*Identity, error) {
 			return nil, errors.Wrap(err, "failed to read git blob data")
 		}
 
+		// the schema of a newer format is not known
+		if validateSchema && !isNewerFormat(data) {
+			if err := schema.Validate(schema.Identity, data); err != nil {
+				return nil, errors.Wrapf(err, "invalid Identity version at hash %s", hash)
+			}
+		}
+
 		var version Version
 		err = json.Unmarshal(data, &version)
 
@@ -155,6 +217,27 @@ func read(repo repository.Repo, ref string) (*Identity, error) {
 	return i, nil
 }
 
+// ListLocalIds list all the available local identity ids
+func ListLocalIds(repo repository.Repo) ([]entity.Id, error) {
+	refs, err := repo.ListRefs(identityRefPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return refsToIds(refs), nil
+}
+
+func refsToIds(refs []string) []entity.Id {
+	ids := make([]entity.Id, len(refs))
+
+	for i, ref := range refs {
+		split := strings.Split(ref, "/")
+		ids[i] = entity.Id(split[len(split)-1])
+	}
+
+	return ids
+}
+
 type StreamedIdentity struct {
 	Identity *Identity
 	Err      error
@@ -162,17 +245,17 @@ type StreamedIdentity struct {
 
 // ReadAllLocalIdentities read and parse all local Identity
 func ReadAllLocalIdentities(repo repository.ClockedRepo) <-chan StreamedIdentity {
-	return readAllIdentities(repo, identityRefPattern)
+	return readAllIdentities(repo, identityRefPattern, false)
 }
 
 // ReadAllRemoteIdentities read and parse all remote Identity for a given remote
 func ReadAllRemoteIdentities(repo repository.ClockedRepo, remote string) <-chan StreamedIdentity {
 	refPrefix := fmt.Sprintf(identityRemoteRefPattern, remote)
-	return readAllIdentities(repo, refPrefix)
+	return readAllIdentities(repo, refPrefix, true)
 }
 
 // Read and parse all available bug with a given ref prefix
-func readAllIdentities(repo repository.ClockedRepo, refPrefix string) <-chan StreamedIdentity {
+func readAllIdentities(repo repository.ClockedRepo, refPrefix string, validateSchema bool) <-chan StreamedIdentity {
 	out := make(chan StreamedIdentity)
 
 	go func() {
@@ -185,7 +268,7 @@ func readAllIdentities(repo repository.ClockedRepo, refPrefix string) <-chan Str
 		}
 
 		for _, ref := range refs {
-			b, err := read(repo, ref)
+			b, err := read(repo, ref, validateSchema)
 
 			if err != nil {
 				out <- StreamedIdentity{Err: err}
@@ -226,6 +309,13 @@ func SetUserIdentity(repo repository.RepoConfig, identity *Identity) error {
 	return repo.LocalConfig().StoreString(identityConfigKey, identity.Id().String())
 }
 
+// SetGlobalUserIdentity store the user identity's id in the global git config.
+// It is used as a default in the repositories where no identity is set and
+// where this identity exist.
+func SetGlobalUserIdentity(repo repository.RepoConfig, identity *Identity) error {
+	return repo.GlobalConfig().StoreString(identityConfigKey, identity.Id().String())
+}
+
 // GetUserIdentity read the current user identity, set with a git config entry
 func GetUserIdentity(repo repository.Repo) (*Identity, error) {
 	id, err := GetUserIdentityId(repo)
@@ -245,8 +335,33 @@ func GetUserIdentity(repo repository.Repo) (*Identity, error) {
 	return i, nil
 }
 
+// GetUserIdentityId read the current user identity's id, from the repository
+// git config first, then from the global git config if that identity exist in
+// the repository
 func GetUserIdentityId(repo repository.Repo) (entity.Id, error) {
-	configs, err := repo.LocalConfig().ReadAll(identityConfigKey)
+	id, err := readUserIdentityId(repo.LocalConfig())
+	if err != ErrNoIdentitySet {
+		return id, err
+	}
+
+	id, err = readUserIdentityId(repo.GlobalConfig())
+	if err != nil {
+		return entity.UnsetId, err
+	}
+
+	exist, err := repo.RefExist(identityRefPattern + id.String())
+	if err != nil {
+		return entity.UnsetId, err
+	}
+	if !exist {
+		return entity.UnsetId, ErrNoIdentitySet
+	}
+
+	return id, nil
+}
+
+func readUserIdentityId(config repository.Config) (entity.Id, error) {
+	configs, err := config.ReadAll(identityConfigKey)
 	if err != nil {
 		return entity.UnsetId, err
 	}
@@ -273,12 +388,15 @@ func GetUserIdentityId(repo repository.Repo) (entity.Id, error) {
 
 // IsUserIdentitySet say if the user has set his identity
 func IsUserIdentitySet(repo repository.Repo) (bool, error) {
-	configs, err := repo.LocalConfig().ReadAll(identityConfigKey)
-	if err != nil {
+	_, err := GetUserIdentityId(repo)
+	switch err {
+	case nil:
+		return true, nil
+	case ErrNoIdentitySet, ErrMultipleIdentitiesSet:
+		return false, nil
+	default:
 		return false, err
 	}
-
-	return len(configs) == 1, nil
 }
 
 type Mutator struct {
@@ -320,6 +438,10 @@ func (i *Identity) Commit(repo repository.ClockedRepo) error {
 		return fmt.Errorf("can't commit an identity with no pending version")
 	}
 
+	if i.HasNewerFormat() {
+		return ErrNewerFormat
+	}
+
 	if err := i.Validate(); err != nil {
 		return errors.Wrap(err, "can't commit an identity with invalid data")
 	}
@@ -391,6 +513,17 @@ func (i *Identity) CommitAsNeeded(repo repository.ClockedRepo) error {
 	return i.Commit(repo)
 }
 
+// HasNewerFormat tell if the identity hold versions in a newer format than the
+// supported one, in which case it can't be edited
+func (i *Identity) HasNewerFormat() bool {
+	for _, v := range i.versions {
+		if v.newerFormat {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *Identity) NeedCommit() bool {
 	for _, v := range i.versions {
 		if v.commitHash == "" {
@@ -529,6 +662,12 @@ func (i *Identity) Keys() []*Key {
 	return i.lastVersion().keys
 }
 
+// Versions return all the versions of the identity, from the oldest to the
+// most recent
+func (i *Identity) Versions() []*Version {
+	return i.versions
+}
+
 // ValidKeysAtTime return the set of keys valid at a given lamport time
 func (i *Identity) ValidKeysAtTime(time lamport.Time) []*Key {
 	var result []*Key
@@ -23,6 +23,44 @@ func Push(repo repository.Repo, remote string) (string, error) {
 	return repo.PushRefs(remote, identityRefPattern+"*")
 }
 
+// PruneRemote remove the remote-tracking references of the identities that
+// don't exist anymore on the remote, and return the corresponding ids.
+// This does not change the local identities state.
+func PruneRemote(repo repository.Repo, remote string) ([]entity.Id, error) {
+	upstreamRefs, err := repo.ListRemoteRefs(remote, identityRefPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := make(map[entity.Id]struct{}, len(upstreamRefs))
+	for _, id := range refsToIds(upstreamRefs) {
+		upstream[id] = struct{}{}
+	}
+
+	remoteRefSpec := fmt.Sprintf(identityRemoteRefPattern, remote)
+	trackingRefs, err := repo.ListRefs(remoteRefSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []entity.Id
+	for _, ref := range trackingRefs {
+		refSplit := strings.Split(ref, "/")
+		id := entity.Id(refSplit[len(refSplit)-1])
+		if _, ok := upstream[id]; ok {
+			continue
+		}
+
+		err = repo.RemoveRef(ref)
+		if err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, id)
+	}
+
+	return pruned, nil
+}
+
 // Pull will do a Fetch + MergeAll
 // This function will return an error if a merge fail
 func Pull(repo repository.ClockedRepo, remote string) error {
@@ -67,7 +105,7 @@ func MergeAll(repo repository.ClockedRepo, remote string) <-chan entity.MergeRes
 				continue
 			}
 
-			remoteIdentity, err := read(repo, remoteRef)
+			remoteIdentity, err := read(repo, remoteRef, true)
 
 			if err != nil {
 				out <- entity.NewMergeInvalidStatus(id, errors.Wrap(err, "remote identity is not readable").Error())
@@ -101,7 +139,7 @@ func MergeAll(repo repository.ClockedRepo, remote string) <-chan entity.MergeRes
 				continue
 			}
 
-			localIdentity, err := read(repo, localRef)
+			localIdentity, err := read(repo, localRef, false)
 
 			if err != nil {
 				out <- entity.NewMergeError(errors.Wrap(err, "local identity is not readable"), id)
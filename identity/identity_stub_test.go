@@ -24,3 +24,9 @@ func TestIdentityStubSerialize(t *testing.T) {
 
 	assert.Equal(t, before, &after)
 }
+
+func TestUnmarshalNullIdentity(t *testing.T) {
+	// a null identity from a remote is refused, without panicking
+	_, err := UnmarshalJSON(json.RawMessage("null"))
+	assert.Error(t, err)
+}
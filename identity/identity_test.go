@@ -252,3 +252,56 @@ func TestJSON(t *testing.T) {
 	i, err = ReadLocal(mockRepo, i.Id())
 	assert.NoError(t, err)
 }
+
+func TestAnonymousIdentity(t *testing.T) {
+	mockRepo := repository.NewMockRepoForTest()
+
+	anon1 := NewAnonymousIdentity()
+	anon2 := NewAnonymousIdentity()
+
+	assert.Nil(t, anon1.Validate())
+	assert.Empty(t, anon1.Email())
+	assert.True(t, IsAnonymous(anon1))
+
+	assert.Nil(t, anon1.Commit(mockRepo))
+	assert.Nil(t, anon2.Commit(mockRepo))
+	assert.NotEqual(t, anon1.Id(), anon2.Id())
+
+	loaded, err := ReadLocal(mockRepo, anon1.Id())
+	assert.NoError(t, err)
+	assert.True(t, IsAnonymous(loaded))
+
+	assert.False(t, IsAnonymous(NewIdentity("René Descartes", "rene@descartes.fr")))
+	assert.False(t, IsAnonymous(NewBare("René Descartes", "rene@descartes.fr")))
+}
+
+func TestUserIdentity(t *testing.T) {
+	mockRepo := repository.NewMockRepoForTest()
+
+	work := NewIdentity("René Descartes", "rene@work.fr")
+	personal := NewIdentity("René Descartes", "rene@descartes.fr")
+	assert.Nil(t, work.Commit(mockRepo))
+	assert.Nil(t, personal.Commit(mockRepo))
+
+	set, err := IsUserIdentitySet(mockRepo)
+	assert.NoError(t, err)
+	assert.False(t, set)
+
+	// the global default is used when none is set for the repository
+	assert.NoError(t, SetGlobalUserIdentity(mockRepo, personal))
+	id, err := GetUserIdentityId(mockRepo)
+	assert.NoError(t, err)
+	assert.Equal(t, personal.Id(), id)
+
+	// the repository scoped identity has precedence
+	assert.NoError(t, SetUserIdentity(mockRepo, work))
+	id, err = GetUserIdentityId(mockRepo)
+	assert.NoError(t, err)
+	assert.Equal(t, work.Id(), id)
+
+	// a global default that doesn't exist in the repository is ignored
+	otherRepo := repository.NewMockRepoForTest()
+	assert.NoError(t, SetGlobalUserIdentity(otherRepo, personal))
+	_, err = GetUserIdentityId(otherRepo)
+	assert.Equal(t, ErrNoIdentitySet, err)
+}
@@ -1,9 +1,35 @@
 package identity
 
+import "strings"
+
+// The formats of keys, named as in git's gpg.format
+const (
+	KeyFormatOpenPGP = "openpgp"
+	KeyFormatSSH     = "ssh"
+)
+
 type Key struct {
-	// The GPG fingerprint of the key
+	// The GPG fingerprint of the key, or the SHA256 fingerprint of an SSH key
+	// as given by "ssh-add -l"
 	Fingerprint string `json:"fingerprint"`
-	PubKey      string `json:"pub_key"`
+	// The armored OpenPGP public key, or the SSH public key in the
+	// authorized_keys format
+	PubKey string `json:"pub_key"`
+}
+
+// Format return the format of the key, KeyFormatOpenPGP or KeyFormatSSH, or
+// an empty string if unknown
+func (k *Key) Format() string {
+	switch {
+	case strings.HasPrefix(k.PubKey, "-----BEGIN PGP PUBLIC KEY BLOCK-----"):
+		return KeyFormatOpenPGP
+	case strings.HasPrefix(k.PubKey, "ssh-"),
+		strings.HasPrefix(k.PubKey, "ecdsa-"),
+		strings.HasPrefix(k.PubKey, "sk-"):
+		return KeyFormatSSH
+	default:
+		return ""
+	}
 }
 
 func (k *Key) Validate() error {
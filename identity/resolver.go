@@ -3,6 +3,7 @@ package identity
 import (
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
 )
 
 // Resolver define the interface of an Identity resolver, able to load
@@ -23,3 +24,40 @@ func NewSimpleResolver(repo repository.Repo) *SimpleResolver {
 func (r *SimpleResolver) ResolveIdentity(id entity.Id) (Interface, error) {
 	return ReadLocal(r.repo, id)
 }
+
+// RemoteResolver is a Resolver loading Identities from a Repo, falling back
+// to the identities fetched from a remote when they are not available locally.
+type RemoteResolver struct {
+	repo   repository.Repo
+	remote string
+}
+
+func NewRemoteResolver(repo repository.Repo, remote string) *RemoteResolver {
+	return &RemoteResolver{repo: repo, remote: remote}
+}
+
+func (r *RemoteResolver) ResolveIdentity(id entity.Id) (Interface, error) {
+	i, err := ReadLocal(r.repo, id)
+	if err == ErrIdentityNotExist {
+		return ReadRemote(r.repo, r.remote, id.String())
+	}
+	return i, err
+}
+
+// PushedResolver is a Resolver loading Identities being pushed to a Repo, from
+// the new head of their ref, falling back to the ones already available.
+type PushedResolver struct {
+	repo  repository.Repo
+	heads map[entity.Id]git.Hash
+}
+
+func NewPushedResolver(repo repository.Repo, heads map[entity.Id]git.Hash) *PushedResolver {
+	return &PushedResolver{repo: repo, heads: heads}
+}
+
+func (r *PushedResolver) ResolveIdentity(id entity.Id) (Interface, error) {
+	if head, ok := r.heads[id]; ok {
+		return readRev(r.repo, id, head.String(), true)
+	}
+	return ReadLocal(r.repo, id)
+}
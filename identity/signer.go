@@ -0,0 +1,197 @@
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// the git config holding the Signer of the operations, as "gpg:<key-id>",
+// "ssh:<fingerprint>" or "ssh:<key>"
+const signerConfigKey = "git-bug.signer"
+
+// the git configs of the signature of the commits, used when no Signer is
+// configured and git sign with ssh
+const (
+	gitSigningFormatConfigKey = "gpg.format"
+	gitSigningKeyConfigKey    = "user.signingkey"
+)
+
+// ErrBadSignature is returned when a signature doesn't match the data or the
+// key
+var ErrBadSignature = errors.New("bad signature")
+
+// ErrUnknownSigningKey is returned when the data is signed with a key the
+// identity doesn't hold
+var ErrUnknownSigningKey = errors.New("signed with a key unknown to the identity")
+
+const (
+	SignerGPG = "gpg"
+	SignerSSH = "ssh"
+)
+
+// Signer sign data with a private key held outside of git-bug, by an agent as
+// gpg-agent or ssh-agent, possibly on a hardware token. Only a reference to
+// the key is configured: the private key never need to be readable by
+// git-bug or stored in the git config.
+type Signer interface {
+	// Spec return the reference of the key, as "gpg:<key-id>",
+	// "ssh:<fingerprint>" or "ssh:<key>"
+	Spec() string
+
+	// Key return the public key of the signer, to register on an identity
+	Key() (*Key, error)
+
+	// Sign return a detached signature of the data
+	Sign(data []byte) ([]byte, error)
+}
+
+// NewSigner return the Signer using the key given as "gpg:<key-id>", signing
+// with gpg and its agent, "ssh:<fingerprint>", signing with the ssh-agent, or
+// "ssh:<key>", signing with ssh-keygen and a key given as in git's
+// user.signingkey: a path to a key file, or "key::<public key>".
+func NewSigner(spec string) (Signer, error) {
+	i := strings.Index(spec, ":")
+	if i < 0 || i == len(spec)-1 {
+		return nil, fmt.Errorf("invalid signer %s, expected gpg:<key-id>, ssh:<fingerprint> or ssh:<key>", spec)
+	}
+
+	kind, ref := spec[:i], spec[i+1:]
+
+	switch kind {
+	case SignerGPG:
+		return &gpgSigner{keyId: ref}, nil
+	case SignerSSH:
+		if strings.HasPrefix(ref, "SHA256:") {
+			return newSSHAgentSigner(ref)
+		}
+		return &sshKeygenSigner{key: ref}, nil
+	default:
+		return nil, fmt.Errorf("unknown signer %s, expected gpg or ssh", kind)
+	}
+}
+
+// ReadSignerSpec return the Signer configured in the repository, or in the
+// global git config, or an empty string if there is none. Without Signer, the
+// key signing the commits is used if git sign with ssh (gpg.format=ssh).
+func ReadSignerSpec(repo repository.RepoConfig) (string, error) {
+	spec, err := readConfig(repo, signerConfigKey)
+	if err != nil || spec != "" {
+		return spec, err
+	}
+
+	format, err := readConfig(repo, gitSigningFormatConfigKey)
+	if err != nil || format != SignerSSH {
+		return "", err
+	}
+
+	key, err := readConfig(repo, gitSigningKeyConfigKey)
+	if err != nil || key == "" {
+		return "", err
+	}
+
+	return SignerSSH + ":" + key, nil
+}
+
+// readConfig read a value in the repository, or in the global git config,
+// or return an empty string if there is none
+func readConfig(repo repository.RepoConfig, key string) (string, error) {
+	for _, config := range []repository.Config{repo.LocalConfig(), repo.GlobalConfig()} {
+		value, err := config.ReadString(key)
+		if err == repository.ErrNoConfigEntry {
+			continue
+		}
+		return value, err
+	}
+
+	return "", nil
+}
+
+// ConfiguredSigner return the Signer configured for the repository, or nil if
+// the operations are not signed
+func ConfiguredSigner(repo repository.RepoConfig) (Signer, error) {
+	spec, err := ReadSignerSpec(repo)
+	if err != nil || spec == "" {
+		return nil, err
+	}
+
+	return NewSigner(spec)
+}
+
+// SetSigner configure the Signer of the repository, or the global one
+func SetSigner(repo repository.RepoConfig, signer Signer, global bool) error {
+	if global {
+		return repo.GlobalConfig().StoreString(signerConfigKey, signer.Spec())
+	}
+	return repo.LocalConfig().StoreString(signerConfigKey, signer.Spec())
+}
+
+// VerifySignature check a signature made by a Signer, with its public key
+func VerifySignature(key *Key, data []byte, signature []byte) error {
+	switch key.Format() {
+	case KeyFormatOpenPGP:
+		return verifyGPG(key, data, signature)
+	case KeyFormatSSH:
+		return verifySSH(key, data, signature)
+	default:
+		return fmt.Errorf("unknown key format")
+	}
+}
+
+// HasKey tell if an identity hold a key with the given fingerprint
+func HasKey(keys []*Key, fingerprint string) bool {
+	for _, key := range keys {
+		if key.Fingerprint == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// DataSignature is a detached signature of some data stored in git, along with
+// the public key that made it
+type DataSignature struct {
+	Key       *Key   `json:"key"`
+	Signature []byte `json:"signature"`
+}
+
+// SignData sign the data with the Signer configured in the repository, or
+// return nil if there is none
+func SignData(repo repository.RepoConfig, data []byte) (*DataSignature, error) {
+	signer, err := ConfiguredSigner(repo)
+	if err != nil || signer == nil {
+		return nil, err
+	}
+
+	key, err := signer.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataSignature{Key: key, Signature: signature}, nil
+}
+
+// Verify check that the signature match the data, and has been made with one
+// of the given keys
+func (s *DataSignature) Verify(data []byte, keys []*Key) error {
+	if s.Key == nil {
+		return ErrBadSignature
+	}
+
+	if err := VerifySignature(s.Key, data, s.Signature); err != nil {
+		return err
+	}
+
+	if !HasKey(keys, s.Key.Fingerprint) {
+		return ErrUnknownSigningKey
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package identity
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gpgSigner sign with gpg, which use its agent to access the private key,
+// possibly on a smartcard
+type gpgSigner struct {
+	keyId string
+}
+
+func (s *gpgSigner) Spec() string {
+	return SignerGPG + ":" + s.keyId
+}
+
+func (s *gpgSigner) Key() (*Key, error) {
+	listing, err := runGPG(nil, "--batch", "--with-colons", "--fingerprint", s.keyId)
+	if err != nil {
+		return nil, fmt.Errorf("gpg key %s not found: %v", s.keyId, err)
+	}
+
+	// the first fingerprint is the one of the primary key
+	var fingerprint string
+	for _, line := range strings.Split(string(listing), "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) > 9 && fields[0] == "fpr" {
+			fingerprint = fields[9]
+			break
+		}
+	}
+	if fingerprint == "" {
+		return nil, fmt.Errorf("gpg key %s not found", s.keyId)
+	}
+
+	pubKey, err := runGPG(nil, "--batch", "--armor", "--export", fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		Fingerprint: fingerprint,
+		PubKey:      strings.TrimSpace(string(pubKey)),
+	}, nil
+}
+
+func (s *gpgSigner) Sign(data []byte) ([]byte, error) {
+	return runGPG(bytes.NewReader(data), "--batch", "--detach-sign", "--armor", "--local-user", s.keyId)
+}
+
+// verifyGPG check a signature with gpg, in a throwaway home holding only the
+// public key, so that the keys of the user don't interfere
+func verifyGPG(key *Key, data []byte, signature []byte) error {
+	home, err := ioutil.TempDir("", "git-bug-gpg")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(home)
+
+	_, err = runGPG(strings.NewReader(key.PubKey), "--homedir", home, "--batch", "--import")
+	if err != nil {
+		return fmt.Errorf("invalid gpg key: %v", err)
+	}
+
+	signaturePath := filepath.Join(home, "signature.asc")
+	err = ioutil.WriteFile(signaturePath, signature, 0600)
+	if err != nil {
+		return err
+	}
+
+	// the data is given on the standard input
+	status, err := runGPG(bytes.NewReader(data), "--homedir", home, "--batch",
+		"--status-fd", "1", "--verify", signaturePath, "-")
+	if err != nil {
+		return ErrBadSignature
+	}
+
+	for _, line := range strings.Split(string(status), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "VALIDSIG" {
+			continue
+		}
+		// the signing key, possibly a sub-key, and the primary key
+		for _, field := range fields[2:] {
+			if field == key.Fingerprint {
+				return nil
+			}
+		}
+	}
+
+	return ErrBadSignature
+}
+
+func runGPG(stdin io.Reader, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("gpg", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
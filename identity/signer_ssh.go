@@ -0,0 +1,286 @@
+package identity
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// sshAgentSigner sign with a key of the ssh-agent, possibly on a hardware token
+type sshAgentSigner struct {
+	agent       agent.Agent
+	fingerprint string
+}
+
+func newSSHAgentSigner(fingerprint string) (*sshAgentSigner, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, fmt.Errorf("no ssh-agent running, SSH_AUTH_SOCK is not set")
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("can't reach the ssh-agent: %v", err)
+	}
+
+	return &sshAgentSigner{
+		agent:       agent.NewClient(conn),
+		fingerprint: fingerprint,
+	}, nil
+}
+
+func (s *sshAgentSigner) Spec() string {
+	return SignerSSH + ":" + s.fingerprint
+}
+
+// publicKey return the key of the agent, without parsing it so that the key
+// types unknown to the ssh library are supported
+func (s *sshAgentSigner) publicKey() (*agent.Key, error) {
+	keys, err := s.agent.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if sshFingerprint(key.Blob) == s.fingerprint {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ssh key %s not found in the ssh-agent", s.fingerprint)
+}
+
+func (s *sshAgentSigner) Key() (*Key, error) {
+	pub, err := s.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		Fingerprint: sshFingerprint(pub.Blob),
+		PubKey:      strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))),
+	}, nil
+}
+
+func (s *sshAgentSigner) Sign(data []byte) ([]byte, error) {
+	pub, err := s.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := sshSigSignedMessage(sshSigHash, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var signature *ssh.Signature
+
+	// SSHSIG forbid the SHA-1 signatures of the RSA keys
+	if extended, ok := s.agent.(agent.ExtendedAgent); ok && pub.Type() == ssh.KeyAlgoRSA {
+		signature, err = extended.SignWithFlags(pub, message, agent.SignatureFlagRsaSha512)
+	} else {
+		signature, err = s.agent.Sign(pub, message)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return armorSSHSig(pub.Blob, signature), nil
+}
+
+// sshKeygenSigner sign with ssh-keygen, as git does with gpg.format=ssh. The
+// key is a path to a private or public key, in which case the private key is
+// found in the ssh-agent, or a public key given as "key::<public key>".
+type sshKeygenSigner struct {
+	key string
+}
+
+func (s *sshKeygenSigner) Spec() string {
+	return SignerSSH + ":" + s.key
+}
+
+func (s *sshKeygenSigner) literal() (string, bool) {
+	if strings.HasPrefix(s.key, "key::") {
+		return strings.TrimPrefix(s.key, "key::"), true
+	}
+	return "", false
+}
+
+func (s *sshKeygenSigner) path() string {
+	if strings.HasPrefix(s.key, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, s.key[2:])
+		}
+	}
+	return s.key
+}
+
+func (s *sshKeygenSigner) authorizedKey() (string, error) {
+	if literal, ok := s.literal(); ok {
+		return literal, nil
+	}
+
+	path := s.path()
+
+	// the public key next to the private one
+	for _, candidate := range []string{path + ".pub", path} {
+		raw, err := ioutil.ReadFile(candidate)
+		if err != nil {
+			continue
+		}
+		if _, err := sshPublicKeyBlob(string(raw)); err == nil {
+			return strings.TrimSpace(string(raw)), nil
+		}
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command("ssh-keygen", "-y", "-f", path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("can't read the ssh key %s: %v", s.key, err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (s *sshKeygenSigner) Key() (*Key, error) {
+	authorizedKey, err := s.authorizedKey()
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := sshPublicKeyBlob(authorizedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// drop the comment, which is not part of the key
+	fields := strings.Fields(authorizedKey)
+
+	return &Key{
+		Fingerprint: sshFingerprint(blob),
+		PubKey:      fields[0] + " " + fields[1],
+	}, nil
+}
+
+func (s *sshKeygenSigner) Sign(data []byte) ([]byte, error) {
+	path := s.path()
+
+	// ssh-keygen need a file, even for a key held in the agent
+	if literal, ok := s.literal(); ok {
+		dir, err := ioutil.TempDir("", "git-bug-ssh")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		path = filepath.Join(dir, "key.pub")
+		err = ioutil.WriteFile(path, []byte(literal+"\n"), 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("ssh-keygen", "-Y", "sign", "-n", sshSigNamespace, "-f", path)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func verifySSH(key *Key, data []byte, signature []byte) error {
+	keyBlob, err := sshPublicKeyBlob(key.PubKey)
+	if err != nil {
+		return err
+	}
+
+	if sshFingerprint(keyBlob) != key.Fingerprint {
+		return ErrBadSignature
+	}
+
+	sig, err := unarmorSSHSig(signature)
+	if err != nil {
+		return ErrBadSignature
+	}
+	if sig.Namespace != sshSigNamespace || !bytes.Equal(sig.PublicKey, keyBlob) {
+		return ErrBadSignature
+	}
+
+	pub, err := ssh.ParsePublicKey(keyBlob)
+	if err != nil {
+		// a key type unknown to the ssh library, as the ones of the hardware
+		// tokens, is checked by ssh-keygen
+		return verifySSHKeygen(key, data, signature)
+	}
+
+	message, err := sshSigSignedMessage(sig.HashAlgorithm, data)
+	if err != nil {
+		return ErrBadSignature
+	}
+
+	var inner ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &inner); err != nil {
+		return ErrBadSignature
+	}
+
+	if err := pub.Verify(message, &inner); err != nil {
+		return ErrBadSignature
+	}
+
+	return nil
+}
+
+func verifySSHKeygen(key *Key, data []byte, signature []byte) error {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return fmt.Errorf("can't check the signature of the ssh key: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "git-bug-ssh")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	err = ioutil.WriteFile(allowedSigners, []byte(sshSigNamespace+" "+key.PubKey+"\n"), 0600)
+	if err != nil {
+		return err
+	}
+
+	signaturePath := filepath.Join(dir, "signature")
+	err = ioutil.WriteFile(signaturePath, signature, 0600)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("ssh-keygen", "-Y", "verify", "-f", allowedSigners,
+		"-I", sshSigNamespace, "-n", sshSigNamespace, "-s", signaturePath)
+	cmd.Stdin = bytes.NewReader(data)
+
+	if err := cmd.Run(); err != nil {
+		return ErrBadSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package identity
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestNewSigner(t *testing.T) {
+	signer, err := NewSigner("gpg:ABCD1234")
+	require.NoError(t, err)
+	require.Equal(t, "gpg:ABCD1234", signer.Spec())
+
+	signer, err = NewSigner("ssh:~/.ssh/id_ed25519")
+	require.NoError(t, err)
+	require.Equal(t, "ssh:~/.ssh/id_ed25519", signer.Spec())
+
+	for _, spec := range []string{"", "gpg", "gpg:", "pgp:ABCD1234"} {
+		_, err := NewSigner(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestReadSignerSpec(t *testing.T) {
+	repo := repository.NewMockRepoForTest()
+
+	spec, err := ReadSignerSpec(repo)
+	require.NoError(t, err)
+	require.Empty(t, spec)
+
+	// the key signing the commits, if git sign with ssh
+	require.NoError(t, repo.LocalConfig().StoreString(gitSigningKeyConfigKey, "~/.ssh/id_ed25519"))
+	spec, err = ReadSignerSpec(repo)
+	require.NoError(t, err)
+	require.Empty(t, spec)
+
+	require.NoError(t, repo.LocalConfig().StoreString(gitSigningFormatConfigKey, "ssh"))
+	spec, err = ReadSignerSpec(repo)
+	require.NoError(t, err)
+	require.Equal(t, "ssh:~/.ssh/id_ed25519", spec)
+
+	require.NoError(t, repo.LocalConfig().StoreString(signerConfigKey, "gpg:ABCD1234"))
+	spec, err = ReadSignerSpec(repo)
+	require.NoError(t, err)
+	require.Equal(t, "gpg:ABCD1234", spec)
+}
+
+func TestSSHAgentSigner(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("ed25519", func(t *testing.T) { testSSHAgentSigner(t, ed25519Key) })
+	t.Run("rsa", func(t *testing.T) { testSSHAgentSigner(t, rsaKey) })
+}
+
+func testSSHAgentSigner(t *testing.T, private interface{}) {
+
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: private}))
+
+	signerPub, err := ssh.NewSignerFromKey(private)
+	require.NoError(t, err)
+	fingerprint := ssh.FingerprintSHA256(signerPub.PublicKey())
+
+	signer := &sshAgentSigner{agent: keyring, fingerprint: fingerprint}
+	require.Equal(t, "ssh:"+fingerprint, signer.Spec())
+
+	key, err := signer.Key()
+	require.NoError(t, err)
+	require.Equal(t, fingerprint, key.Fingerprint)
+	require.Equal(t, KeyFormatSSH, key.Format())
+
+	signature, err := signer.Sign([]byte("data"))
+	require.NoError(t, err)
+
+	require.NoError(t, VerifySignature(key, []byte("data"), signature))
+	require.Equal(t, ErrBadSignature, VerifySignature(key, []byte("altered"), signature))
+
+	// the signature is the one of ssh-keygen
+	checkSSHKeygen(t, key, []byte("data"), signature)
+
+	// a key missing in the agent
+	missing := &sshAgentSigner{agent: keyring, fingerprint: "SHA256:missing"}
+	_, err = missing.Sign([]byte("data"))
+	require.Error(t, err)
+}
+
+func TestSSHKeygenSigner(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not available")
+	}
+
+	dir, err := ioutil.TempDir("", "git-bug-ssh-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "rene@descartes.fr", "-f", path).Run())
+
+	fingerprint, err := exec.Command("ssh-keygen", "-l", "-f", path+".pub").Output()
+	require.NoError(t, err)
+
+	signer, err := NewSigner("ssh:" + path)
+	require.NoError(t, err)
+
+	key, err := signer.Key()
+	require.NoError(t, err)
+	require.Equal(t, strings.Fields(string(fingerprint))[1], key.Fingerprint)
+	require.Equal(t, KeyFormatSSH, key.Format())
+	require.NotContains(t, key.PubKey, "rene@descartes.fr")
+
+	signature, err := signer.Sign([]byte("data"))
+	require.NoError(t, err)
+
+	require.NoError(t, VerifySignature(key, []byte("data"), signature))
+	require.Equal(t, ErrBadSignature, VerifySignature(key, []byte("altered"), signature))
+
+	// the key of another identity
+	other := &Key{Fingerprint: key.Fingerprint, PubKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB7+y3cmHqqKkJ5UD8VZ0HBcRvv5+F1itLK/EsLshzNp"}
+	require.Equal(t, ErrBadSignature, VerifySignature(other, []byte("data"), signature))
+}
+
+// checkSSHKeygen check a signature with ssh-keygen, if available
+func checkSSHKeygen(t *testing.T, key *Key, data []byte, signature []byte) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return
+	}
+
+	dir, err := ioutil.TempDir("", "git-bug-ssh-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	signaturePath := filepath.Join(dir, "signature")
+	require.NoError(t, ioutil.WriteFile(signaturePath, signature, 0600))
+
+	cmd := exec.Command("ssh-keygen", "-Y", "check-novalidate", "-n", "git-bug", "-s", signaturePath)
+	cmd.Stdin = bytes.NewReader(data)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestGPGSigner(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not available")
+	}
+
+	home, err := ioutil.TempDir("", "git-bug-gpg-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	require.NoError(t, os.Setenv("GNUPGHOME", home))
+	defer os.Unsetenv("GNUPGHOME")
+	defer exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+
+	_, err = runGPG(nil, "--batch", "--passphrase", "", "--quick-gen-key", "René Descartes <rene@descartes.fr>", "ed25519", "sign", "never")
+	require.NoError(t, err)
+
+	signer, err := NewSigner("gpg:rene@descartes.fr")
+	require.NoError(t, err)
+
+	key, err := signer.Key()
+	require.NoError(t, err)
+	require.Len(t, key.Fingerprint, 40)
+	require.True(t, strings.HasPrefix(key.PubKey, "-----BEGIN PGP PUBLIC KEY BLOCK-----"))
+
+	signature, err := signer.Sign([]byte("data"))
+	require.NoError(t, err)
+
+	require.NoError(t, VerifySignature(key, []byte("data"), signature))
+	require.Equal(t, ErrBadSignature, VerifySignature(key, []byte("altered"), signature))
+}
@@ -0,0 +1,81 @@
+package identity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// This is intended for testing only
+
+var testAgent struct {
+	once    sync.Once
+	keyring agent.Agent
+	err     error
+}
+
+// NewTestSigner return a Signer with a new key, held by an in-process
+// ssh-agent started on the first call and set in SSH_AUTH_SOCK
+func NewTestSigner() (Signer, error) {
+	testAgent.once.Do(func() {
+		testAgent.keyring, testAgent.err = startTestAgent()
+	})
+	if testAgent.err != nil {
+		return nil, testAgent.err
+	}
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	err = testAgent.keyring.Add(agent.AddedKey{PrivateKey: private})
+	if err != nil {
+		return nil, err
+	}
+
+	sshSigner, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSigner(SignerSSH + ":" + ssh.FingerprintSHA256(sshSigner.PublicKey()))
+}
+
+func startTestAgent() (agent.Agent, error) {
+	dir, err := ioutil.TempDir("", "git-bug-agent")
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "agent.sock"))
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := agent.NewKeyring()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _ = agent.ServeAgent(keyring, conn) }()
+		}
+	}()
+
+	err = os.Setenv("SSH_AUTH_SOCK", listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+
+	return keyring, nil
+}
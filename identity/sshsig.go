@@ -0,0 +1,149 @@
+package identity
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// The signatures made with ssh keys follow the SSHSIG format of OpenSSH, as
+// the ones of git with gpg.format=ssh, and can be checked with
+// "ssh-keygen -Y verify -n git-bug".
+const (
+	sshSigMagic      = "SSHSIG"
+	sshSigVersion    = 1
+	sshSigNamespace  = "git-bug"
+	sshSigHash       = "sha512"
+	sshSigArmorBegin = "-----BEGIN SSH SIGNATURE-----"
+	sshSigArmorEnd   = "-----END SSH SIGNATURE-----"
+)
+
+type sshSigBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+type sshSigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+func sshSigHasher(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown hash algorithm %s", algorithm)
+	}
+}
+
+// sshSigSignedMessage return the data actually signed by the ssh key
+func sshSigSignedMessage(algorithm string, data []byte) ([]byte, error) {
+	h, err := sshSigHasher(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(data)
+
+	signed := ssh.Marshal(sshSigSignedData{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: algorithm,
+		Hash:          h.Sum(nil),
+	})
+
+	return append([]byte(sshSigMagic), signed...), nil
+}
+
+// armorSSHSig encode a signature made over sshSigSignedMessage in the
+// armored SSHSIG format
+func armorSSHSig(publicKey []byte, signature *ssh.Signature) []byte {
+	blob := ssh.Marshal(sshSigBlob{
+		Version:       sshSigVersion,
+		PublicKey:     publicKey,
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHash,
+		Signature:     ssh.Marshal(signature),
+	})
+
+	encoded := base64.StdEncoding.EncodeToString(append([]byte(sshSigMagic), blob...))
+
+	var buf bytes.Buffer
+	buf.WriteString(sshSigArmorBegin)
+	buf.WriteString("\n")
+	for len(encoded) > 70 {
+		buf.WriteString(encoded[:70])
+		buf.WriteString("\n")
+		encoded = encoded[70:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\n")
+	buf.WriteString(sshSigArmorEnd)
+	buf.WriteString("\n")
+
+	return buf.Bytes()
+}
+
+func unarmorSSHSig(armored []byte) (*sshSigBlob, error) {
+	text := strings.TrimSpace(string(armored))
+	if !strings.HasPrefix(text, sshSigArmorBegin) || !strings.HasSuffix(text, sshSigArmorEnd) {
+		return nil, fmt.Errorf("not an ssh signature")
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, sshSigArmorBegin), sshSigArmorEnd)
+
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(raw, []byte(sshSigMagic)) {
+		return nil, fmt.Errorf("not an ssh signature")
+	}
+
+	var blob sshSigBlob
+	err = ssh.Unmarshal(raw[len(sshSigMagic):], &blob)
+	if err != nil {
+		return nil, err
+	}
+	if blob.Version != sshSigVersion {
+		return nil, fmt.Errorf("unknown ssh signature version %d", blob.Version)
+	}
+
+	return &blob, nil
+}
+
+// sshPublicKeyBlob return the wire format of a public key in the
+// authorized_keys format, without requiring its type to be known by the ssh
+// library, as for hardware keys
+func sshPublicKeyBlob(authorizedKey string) ([]byte, error) {
+	fields := strings.Fields(authorizedKey)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid ssh key")
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh key: %v", err)
+	}
+
+	return blob, nil
+}
+
+// sshFingerprint return the SHA256 fingerprint of a public key, as given by
+// "ssh-add -l"
+func sshFingerprint(blob []byte) string {
+	sum := sha256.Sum256(blob)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/MichaelMure/git-bug/util/git"
 	"github.com/MichaelMure/git-bug/util/lamport"
 	"github.com/MichaelMure/git-bug/util/text"
+	"github.com/MichaelMure/git-bug/util/timestamp"
 	"github.com/pkg/errors"
 )
 
@@ -43,6 +44,9 @@ type Version struct {
 
 	// Not serialized
 	commitHash git.Hash
+	// Not serialized. Tell if the version is in a newer format than the
+	// supported one, read as far as possible.
+	newerFormat bool
 }
 
 type VersionJSON struct {
@@ -94,14 +98,23 @@ func (v *Version) MarshalJSON() ([]byte, error) {
 func (v *Version) UnmarshalJSON(data []byte) error {
 	var aux VersionJSON
 
-	if err := json.Unmarshal(data, &aux); err != nil {
+	err := json.Unmarshal(data, &aux)
+
+	// a newer format is read as far as possible, skipping the fields that
+	// changed of type
+	if _, ok := err.(*json.UnmarshalTypeError); ok && aux.FormatVersion > formatVersion {
+		err = nil
+	}
+	if err != nil {
 		return err
 	}
 
-	if aux.FormatVersion != formatVersion {
+	if aux.FormatVersion == 0 {
 		return fmt.Errorf("unknown format version %v", aux.FormatVersion)
 	}
 
+	v.newerFormat = aux.FormatVersion > formatVersion
+
 	v.time = aux.Time
 	v.unixTime = aux.UnixTime
 	v.name = aux.Name
@@ -124,6 +137,11 @@ func (v *Version) Validate() error {
 		return fmt.Errorf("lamport time not set")
 	}
 
+	// the data of a newer format can't be checked
+	if v.newerFormat {
+		return nil
+	}
+
 	if text.Empty(v.name) && text.Empty(v.login) {
 		return fmt.Errorf("either name or login should be set")
 	}
@@ -169,6 +187,16 @@ func (v *Version) Validate() error {
 	return nil
 }
 
+// isNewerFormat tell if a serialized Version is in a newer format than the
+// supported one
+func isNewerFormat(data []byte) bool {
+	var aux struct {
+		FormatVersion uint `json:"version"`
+	}
+	_ = json.Unmarshal(data, &aux)
+	return aux.FormatVersion > formatVersion
+}
+
 // Write will serialize and store the Version as a git blob and return
 // its hash
 func (v *Version) Write(repo repository.Repo) (git.Hash, error) {
@@ -202,6 +230,41 @@ func makeNonce(len int) []byte {
 	return result
 }
 
+// Time return the lamport time at which this version become effective
+func (v *Version) Time() lamport.Time {
+	return v.time
+}
+
+// UnixTime return the timestamp at which this version become effective
+func (v *Version) UnixTime() timestamp.Timestamp {
+	return timestamp.Timestamp(v.unixTime)
+}
+
+// Name return the name of this version
+func (v *Version) Name() string {
+	return v.name
+}
+
+// Email return the email of this version
+func (v *Version) Email() string {
+	return v.email
+}
+
+// Login return the login of this version
+func (v *Version) Login() string {
+	return v.login
+}
+
+// AvatarUrl return the avatar URL of this version
+func (v *Version) AvatarUrl() string {
+	return v.avatarURL
+}
+
+// Keys return the keys valid from this version onward
+func (v *Version) Keys() []*Key {
+	return v.keys
+}
+
 // SetMetadata store arbitrary metadata about a version or an Identity in general
 // If the Version has been commit to git already, it won't be overwritten.
 func (v *Version) SetMetadata(key string, value string) {
@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/schema"
 )
 
 func TestVersionSerialize(t *testing.T) {
@@ -39,3 +42,43 @@ func TestVersionSerialize(t *testing.T) {
 
 	assert.Equal(t, before, &after)
 }
+
+func TestVersionSchema(t *testing.T) {
+	version := &Version{
+		name:     "name",
+		email:    "email",
+		unixTime: 1234,
+		keys: []*Key{
+			{
+				Fingerprint: "fingerprint1",
+				PubKey:      "pubkey1",
+			},
+		},
+		nonce: makeNonce(20),
+		time:  3,
+	}
+
+	data, err := json.Marshal(version)
+	require.NoError(t, err)
+	require.NoError(t, schema.Validate(schema.Identity, data))
+
+	// neither a name nor a login
+	err = schema.Validate(schema.Identity, []byte(`{"version":1,"time":3,"unix_time":1234,"email":"email"}`))
+	require.Error(t, err)
+}
+
+func TestVersionNewerFormat(t *testing.T) {
+	data := []byte(`{"version":2,"time":3,"unix_time":1234,"name":"name","pub_keys":{"changed":true}}`)
+
+	// the schema of a newer format is not known
+	require.True(t, isNewerFormat(data))
+
+	var version Version
+	require.NoError(t, json.Unmarshal(data, &version))
+	assert.True(t, version.newerFormat)
+	assert.Equal(t, "name", version.name)
+	assert.NoError(t, version.Validate())
+
+	err := json.Unmarshal([]byte(`{"version":0,"time":3,"unix_time":1234,"name":"name"}`), &Version{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+// Package importer contains the importers of the results of other tools,
+// like the vulnerability scanners or the test reports, into bugs.
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// The metadata of the bugs created from a finding. The key identify the
+// finding across the scans, to not create the same bug twice.
+const (
+	metaKeyFinding  = "git-bug-finding"
+	metaKeyTool     = "git-bug-finding-tool"
+	metaKeyRule     = "git-bug-finding-rule"
+	metaKeyPackage  = "git-bug-finding-package"
+	metaKeySeverity = "git-bug-finding-severity"
+)
+
+// the prefix of the label holding the severity of a finding
+const severityLabelPrefix = "severity:"
+
+// Severity is how serious a finding is, as "critical", "high", "medium" or
+// "low"
+type Severity string
+
+const (
+	SeverityNone     Severity = ""
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+)
+
+// severityFromScore give the severity of a CVSS score
+func severityFromScore(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityNone
+	}
+}
+
+// Finding is a problem reported by a tool, to be tracked in a bug
+type Finding struct {
+	// the stable identifier of the finding across the scans
+	Key  string
+	Tool string
+	// the id of the rule or of the vulnerability
+	RuleId string
+	// the affected package, if any
+	Package  string
+	Severity Severity
+	Title    string
+	Message  string
+	Labels   []string
+}
+
+// labels return the labels of the bug of the finding
+func (f Finding) labels() []string {
+	labels := append([]string{}, f.Labels...)
+	if f.Severity != SeverityNone {
+		labels = append(labels, severityLabelPrefix+string(f.Severity))
+	}
+	return labels
+}
+
+func (f Finding) metadata() map[string]string {
+	meta := map[string]string{
+		metaKeyFinding: f.Key,
+		metaKeyTool:    f.Tool,
+		metaKeyRule:    f.RuleId,
+	}
+	if f.Package != "" {
+		meta[metaKeyPackage] = f.Package
+	}
+	if f.Severity != SeverityNone {
+		meta[metaKeySeverity] = string(f.Severity)
+	}
+	return meta
+}
+
+// Stats is the result of an import
+type Stats struct {
+	// the findings without a bug yet
+	Created int
+	// the findings of a bug closed as fixed, or without resolution
+	Reopened int
+	// the findings of an open bug whose severity changed
+	Updated int
+	// the findings already tracked, or of a bug closed as won't fix, invalid
+	// or the like
+	Unchanged int
+}
+
+// Import create a bug for each new finding, authored by the user identity.
+// A finding already tracked reopen its bug if it has been closed as fixed,
+// and update its severity label if it changed. The bugs closed with another
+// resolution, as "wontfix", are left alone.
+func Import(repo *cache.RepoCache, findings []Finding) (Stats, error) {
+	var stats Stats
+
+	if _, err := repo.GetUserIdentity(); err != nil {
+		return stats, err
+	}
+
+	// the same finding can be reported multiple times in the same scan, as
+	// in multiple locations
+	seen := make(map[string]bool)
+
+	err := repo.Transaction(func() error {
+		for _, f := range findings {
+			if seen[f.Key] {
+				continue
+			}
+			seen[f.Key] = true
+
+			b, err := repo.ResolveBugCreateMetadata(metaKeyFinding, f.Key)
+			if err == bug.ErrBugNotExist {
+				if err := create(repo, f); err != nil {
+					return err
+				}
+				stats.Created++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			reopened, updated, err := update(b, f)
+			if err != nil {
+				return err
+			}
+			switch {
+			case reopened:
+				stats.Reopened++
+			case updated:
+				stats.Updated++
+			default:
+				stats.Unchanged++
+			}
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
+func create(repo *cache.RepoCache, f Finding) error {
+	author, err := repo.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	b, _, err := repo.NewBugRaw(author, time.Now().Unix(), f.Title, f.Message, nil, f.metadata())
+	if err != nil {
+		return fmt.Errorf("finding %s: %v", f.Key, err)
+	}
+
+	labels := f.labels()
+	if len(labels) == 0 {
+		return nil
+	}
+
+	_, _, err = b.ChangeLabels(labels, nil)
+	if err != nil {
+		return fmt.Errorf("finding %s: %v", f.Key, err)
+	}
+	return b.Commit()
+}
+
+func update(b *cache.BugCache, f Finding) (reopened bool, updated bool, err error) {
+	snap := b.Snapshot()
+
+	var messages []string
+
+	if snap.Status == bug.ClosedStatus {
+		if snap.Resolution != bug.NoResolution && snap.Resolution != bug.FixedResolution {
+			return false, false, nil
+		}
+
+		_, err = b.Open()
+		if err != nil {
+			return false, false, err
+		}
+		reopened = true
+		messages = append(messages, fmt.Sprintf("This finding has been reported again by %s.", f.Tool))
+	}
+
+	if f.Severity != SeverityNone {
+		label := severityLabelPrefix + string(f.Severity)
+
+		has := false
+		var removed []string
+		for _, l := range snap.Labels {
+			switch {
+			case l.String() == label:
+				has = true
+			case strings.HasPrefix(l.String(), severityLabelPrefix):
+				removed = append(removed, l.String())
+			}
+		}
+
+		if !has || len(removed) > 0 {
+			_, _, err = b.ChangeLabels([]string{label}, removed)
+			if err != nil {
+				return false, false, err
+			}
+			updated = true
+			messages = append(messages, fmt.Sprintf("The severity is now %s.", f.Severity))
+		}
+	}
+
+	if len(messages) == 0 {
+		return false, false, nil
+	}
+
+	_, err = b.AddComment(strings.Join(messages, " "))
+	if err != nil {
+		return false, false, err
+	}
+
+	return reopened, updated, b.Commit()
+}
@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestImport(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	findings := []Finding{
+		{Key: "a", Tool: "tool", RuleId: "rule-a", Severity: SeverityHigh, Title: "a", Message: "a", Labels: []string{"security"}},
+		{Key: "b", Tool: "tool", RuleId: "rule-b", Package: "pkg", Title: "b", Message: "b"},
+		// reported twice in the same scan
+		{Key: "a", Tool: "tool", RuleId: "rule-a", Severity: SeverityHigh, Title: "a", Message: "a"},
+	}
+
+	// the user identity is the author of the bugs
+	_, err = Import(backend, findings)
+	require.Error(t, err)
+
+	rene, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	stats, err := Import(backend, findings)
+	require.NoError(t, err)
+	require.Equal(t, Stats{Created: 2}, stats)
+
+	a, err := backend.ResolveBugCreateMetadata(metaKeyFinding, "a")
+	require.NoError(t, err)
+	require.Equal(t, []bug.Label{"security", "severity:high"}, a.Snapshot().Labels)
+	rule, _ := a.Snapshot().Operations[0].GetMetadata(metaKeyRule)
+	require.Equal(t, "rule-a", rule)
+
+	b, err := backend.ResolveBugCreateMetadata(metaKeyFinding, "b")
+	require.NoError(t, err)
+	require.Empty(t, b.Snapshot().Labels)
+	pkg, _ := b.Snapshot().Operations[0].GetMetadata(metaKeyPackage)
+	require.Equal(t, "pkg", pkg)
+
+	// the same scan again
+	stats, err = Import(backend, findings)
+	require.NoError(t, err)
+	require.Equal(t, Stats{Unchanged: 2}, stats)
+
+	// a fixed finding recurring, another one dismissed, and a new severity
+	_, err = a.CloseWithResolution(bug.FixedResolution)
+	require.NoError(t, err)
+	require.NoError(t, a.Commit())
+	_, err = b.CloseWithResolution(bug.WontFixResolution)
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	stats, err = Import(backend, []Finding{
+		{Key: "a", Tool: "tool", RuleId: "rule-a", Severity: SeverityCritical, Title: "a", Message: "a"},
+		{Key: "b", Tool: "tool", RuleId: "rule-b", Title: "b", Message: "b"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Stats{Reopened: 1, Unchanged: 1}, stats)
+
+	snap := a.Snapshot()
+	require.Equal(t, bug.OpenStatus, snap.Status)
+	require.Equal(t, []bug.Label{"security", "severity:critical"}, snap.Labels)
+	require.Equal(t, "This finding has been reported again by tool. The severity is now critical.",
+		snap.Comments[len(snap.Comments)-1].Message)
+
+	require.Equal(t, bug.ClosedStatus, b.Snapshot().Status)
+}
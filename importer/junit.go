@@ -0,0 +1,306 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+)
+
+// The metadata of the bugs of the failing tests, to find them on the next
+// reports
+const (
+	metaKeyTest = "git-bug-ci-test"
+)
+
+// the label of the bugs of the failing tests
+const failingTestLabel = "failing-test"
+
+// how much of the output of a failing test is kept in its bug
+const maxTestOutput = 8000
+
+// TestStatus is the result of a test
+type TestStatus int
+
+const (
+	TestPassed TestStatus = iota
+	TestFailed
+	TestSkipped
+)
+
+// TestResult is the result of a test in a report
+type TestResult struct {
+	// the stable identifier of the test, as "package.Class.name"
+	Key    string
+	Status TestStatus
+	// the reason of the failure, and the output of the test
+	Message string
+	Output  string
+}
+
+type junitCase struct {
+	Name      string `xml:"name,attr"`
+	Classname string `xml:"classname,attr"`
+
+	Failure *junitFailure `xml:"failure"`
+	Error   *junitFailure `xml:"error"`
+	Skipped *struct{}     `xml:"skipped"`
+	Stdout  string        `xml:"system-out"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// a <testsuite>, or the root <testsuites>
+type junitSuite struct {
+	XMLName xml.Name
+	Name    string       `xml:"name,attr"`
+	Suites  []junitSuite `xml:"testsuite"`
+	Cases   []junitCase  `xml:"testcase"`
+}
+
+// ParseJUnit read a test report in the JUnit XML format. A test is
+// identified by its class name and its name, or by the name of its suite
+// when the class name is missing.
+func ParseJUnit(r io.Reader) ([]TestResult, error) {
+	var root junitSuite
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid JUnit report: %v", err)
+	}
+
+	if root.XMLName.Local != "testsuites" && root.XMLName.Local != "testsuite" {
+		return nil, fmt.Errorf("invalid JUnit report: unexpected element <%s>", root.XMLName.Local)
+	}
+
+	var result []TestResult
+	if err := junitResults(&result, root, ""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func junitResults(result *[]TestResult, suite junitSuite, parent string) error {
+	name := suite.Name
+	if name == "" {
+		name = parent
+	}
+
+	for _, c := range suite.Cases {
+		if c.Name == "" {
+			return fmt.Errorf("invalid JUnit report: test case without a name in %s", name)
+		}
+
+		class := c.Classname
+		if class == "" {
+			class = name
+		}
+
+		res := TestResult{Key: c.Name, Status: TestPassed}
+		if class != "" {
+			res.Key = class + "." + c.Name
+		}
+
+		failure := c.Failure
+		if failure == nil {
+			failure = c.Error
+		}
+
+		switch {
+		case failure != nil:
+			res.Status = TestFailed
+			res.Message = strings.TrimSpace(failure.Message)
+			if res.Message == "" {
+				res.Message = strings.TrimSpace(failure.Type)
+			}
+			res.Output = strings.TrimSpace(failure.Text)
+			if res.Output == "" {
+				res.Output = strings.TrimSpace(c.Stdout)
+			}
+		case c.Skipped != nil:
+			res.Status = TestSkipped
+		}
+
+		*result = append(*result, res)
+	}
+
+	for _, sub := range suite.Suites {
+		if err := junitResults(result, sub, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TestStats is the result of a test report
+type TestStats struct {
+	// the failing tests without a bug yet
+	Opened int
+	// the failing tests of a bug closed as fixed, or without resolution
+	Reopened int
+	// the passing tests of an open bug
+	Closed int
+	// the failing tests already tracked, or of a bug closed as won't fix or
+	// the like
+	Unchanged int
+}
+
+// ReportTests open a bug for each failing test, authored by the user
+// identity, and close as fixed the bugs of the tests passing again. The bug
+// of a test failing again is reopened if it has been closed as fixed, with
+// a comment. A test failing in one of the reports is failing, even if
+// another one had it passing. The build, if given, is mentioned in the bugs
+// and comments, as a link to the CI job.
+func ReportTests(repo *cache.RepoCache, results []TestResult, build string) (TestStats, error) {
+	var stats TestStats
+
+	if _, err := repo.GetUserIdentity(); err != nil {
+		return stats, err
+	}
+
+	// the worst result of each test, in order of appearance
+	var keys []string
+	merged := make(map[string]TestResult)
+	for _, res := range results {
+		previous, ok := merged[res.Key]
+		switch {
+		case !ok:
+			keys = append(keys, res.Key)
+			merged[res.Key] = res
+		case previous.Status == TestFailed:
+			// a failure is kept
+		case res.Status != TestSkipped:
+			merged[res.Key] = res
+		}
+	}
+
+	suffix := ""
+	if build != "" {
+		suffix = fmt.Sprintf("\n\nBuild: %s", build)
+	}
+
+	err := repo.Transaction(func() error {
+		for _, key := range keys {
+			res := merged[key]
+			if res.Status == TestSkipped {
+				continue
+			}
+
+			b, err := repo.ResolveBugCreateMetadata(metaKeyTest, key)
+			if err != nil && err != bug.ErrBugNotExist {
+				return err
+			}
+
+			switch {
+			case err == bug.ErrBugNotExist && res.Status == TestFailed:
+				if err := openTestBug(repo, res, suffix); err != nil {
+					return err
+				}
+				stats.Opened++
+
+			case err == bug.ErrBugNotExist:
+				// passing, and never failed
+
+			case res.Status == TestFailed:
+				snap := b.Snapshot()
+				if snap.Status != bug.ClosedStatus ||
+					(snap.Resolution != bug.NoResolution && snap.Resolution != bug.FixedResolution) {
+					stats.Unchanged++
+					continue
+				}
+
+				_, err = b.Open()
+				if err != nil {
+					return err
+				}
+				_, err = b.AddComment(testFailureMessage("The test fails again.", res) + suffix)
+				if err != nil {
+					return err
+				}
+				if err := b.Commit(); err != nil {
+					return err
+				}
+				stats.Reopened++
+
+			default:
+				if b.Snapshot().Status != bug.OpenStatus {
+					continue
+				}
+
+				_, err = b.AddComment("The test passes again." + suffix)
+				if err != nil {
+					return err
+				}
+				_, err = b.CloseWithResolution(bug.FixedResolution)
+				if err != nil {
+					return err
+				}
+				if err := b.Commit(); err != nil {
+					return err
+				}
+				stats.Closed++
+			}
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
+func openTestBug(repo *cache.RepoCache, res TestResult, suffix string) error {
+	author, err := repo.GetUserIdentity()
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("Test %s is failing", res.Key)
+	message := testFailureMessage(fmt.Sprintf("The test %s fails.", res.Key), res) + suffix
+
+	b, _, err := repo.NewBugRaw(author, time.Now().Unix(), title, message, nil, map[string]string{
+		metaKeyTest: res.Key,
+	})
+	if err != nil {
+		return fmt.Errorf("test %s: %v", res.Key, err)
+	}
+
+	_, _, err = b.ChangeLabels([]string{failingTestLabel}, nil)
+	if err != nil {
+		return fmt.Errorf("test %s: %v", res.Key, err)
+	}
+	return b.Commit()
+}
+
+func testFailureMessage(intro string, res TestResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(intro)
+
+	if res.Message != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(res.Message)
+	}
+
+	if res.Output != "" {
+		output := res.Output
+		if len(output) > maxTestOutput {
+			cut := maxTestOutput
+			for cut > 0 && !utf8.RuneStart(output[cut]) {
+				cut--
+			}
+			output = output[:cut] + "\n[...]"
+		}
+		sb.WriteString("\n\n```\n")
+		sb.WriteString(output)
+		sb.WriteString("\n```")
+	}
+
+	return sb.String()
+}
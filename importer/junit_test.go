@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const junitSample = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="math" tests="3">
+    <testcase classname="math.Add" name="positive"/>
+    <testcase classname="math.Add" name="overflow">
+      <failure message="expected 0, got 1" type="AssertionError">add_test.go:12</failure>
+    </testcase>
+    <testcase name="slow">
+      <skipped/>
+    </testcase>
+    <testsuite name="nested">
+      <testcase name="crash">
+        <error type="panic"></error>
+        <system-out>runtime error</system-out>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+
+func TestParseJUnit(t *testing.T) {
+	results, err := ParseJUnit(strings.NewReader(junitSample))
+	require.NoError(t, err)
+
+	require.Equal(t, []TestResult{
+		{Key: "math.Add.positive", Status: TestPassed},
+		{Key: "math.Add.overflow", Status: TestFailed, Message: "expected 0, got 1", Output: "add_test.go:12"},
+		{Key: "math.slow", Status: TestSkipped},
+		{Key: "nested.crash", Status: TestFailed, Message: "panic", Output: "runtime error"},
+	}, results)
+
+	results, err = ParseJUnit(strings.NewReader(`<testsuite name="s"><testcase name="t"/></testsuite>`))
+	require.NoError(t, err)
+	require.Equal(t, []TestResult{{Key: "s.t", Status: TestPassed}}, results)
+
+	_, err = ParseJUnit(strings.NewReader(`<html></html>`))
+	require.Error(t, err)
+
+	_, err = ParseJUnit(strings.NewReader(`<testsuite><testcase/></testsuite>`))
+	require.Error(t, err)
+}
+
+func TestReportTests(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	rene, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(rene))
+
+	stats, err := ReportTests(backend, []TestResult{
+		{Key: "a", Status: TestFailed, Message: "boom"},
+		{Key: "b", Status: TestPassed},
+		{Key: "c", Status: TestSkipped},
+		// failing in a report, passing in another one
+		{Key: "d", Status: TestFailed},
+		{Key: "d", Status: TestPassed},
+	}, "https://ci/1")
+	require.NoError(t, err)
+	require.Equal(t, TestStats{Opened: 2}, stats)
+
+	a, err := backend.ResolveBugCreateMetadata(metaKeyTest, "a")
+	require.NoError(t, err)
+	snap := a.Snapshot()
+	require.Equal(t, "Test a is failing", snap.Title)
+	require.Equal(t, "The test a fails.\n\nboom\n\nBuild: https://ci/1", snap.Comments[0].Message)
+	require.Equal(t, []bug.Label{failingTestLabel}, snap.Labels)
+
+	_, err = backend.ResolveBugCreateMetadata(metaKeyTest, "b")
+	require.Equal(t, bug.ErrBugNotExist, err)
+
+	// a rerun doesn't open another bug
+	stats, err = ReportTests(backend, []TestResult{{Key: "a", Status: TestFailed}}, "")
+	require.NoError(t, err)
+	require.Equal(t, TestStats{Unchanged: 1}, stats)
+
+	// passing again
+	stats, err = ReportTests(backend, []TestResult{
+		{Key: "a", Status: TestPassed},
+		{Key: "d", Status: TestSkipped},
+	}, "")
+	require.NoError(t, err)
+	require.Equal(t, TestStats{Closed: 1}, stats)
+
+	snap = a.Snapshot()
+	require.Equal(t, bug.ClosedStatus, snap.Status)
+	require.Equal(t, bug.FixedResolution, snap.Resolution)
+	require.Equal(t, "The test passes again.", snap.Comments[len(snap.Comments)-1].Message)
+
+	// and failing again
+	stats, err = ReportTests(backend, []TestResult{{Key: "a", Status: TestFailed, Output: "trace"}}, "")
+	require.NoError(t, err)
+	require.Equal(t, TestStats{Reopened: 1}, stats)
+
+	snap = a.Snapshot()
+	require.Equal(t, bug.OpenStatus, snap.Status)
+	require.Equal(t, "The test fails again.\n\n```\ntrace\n```", snap.Comments[len(snap.Comments)-1].Message)
+	require.Len(t, backend.AllBugsIds(), 2)
+}
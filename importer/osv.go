@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvVulnerability struct {
+	Id       string   `json:"id"`
+	Summary  string   `json:"summary"`
+	Details  string   `json:"details"`
+	Aliases  []string `json:"aliases"`
+	Affected []struct {
+		Package osvPackage `json:"package"`
+	} `json:"affected"`
+	References []struct {
+		Type string `json:"type"`
+		Url  string `json:"url"`
+	} `json:"references"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// the output of osv-scanner
+type osvScannerOutput struct {
+	Results []struct {
+		Source struct {
+			Path string `json:"path"`
+		} `json:"source"`
+		Packages []struct {
+			Package         osvPackage         `json:"package"`
+			Vulnerabilities []osvVulnerability `json:"vulnerabilities"`
+		} `json:"packages"`
+	} `json:"results"`
+}
+
+// ParseOSV read vulnerabilities in the OSV format, either as the output of
+// osv-scanner, or as OSV entries, alone or in an array. A finding is
+// identified by its vulnerability and its package, so the same
+// vulnerability in two packages make two findings.
+func ParseOSV(r io.Reader) ([]Finding, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '[' {
+		var vulns []osvVulnerability
+		if err := json.Unmarshal(data, &vulns); err != nil {
+			return nil, fmt.Errorf("invalid OSV: %v", err)
+		}
+		return osvEntries(vulns)
+	}
+
+	var probe struct {
+		Id      string          `json:"id"`
+		Results json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid OSV: %v", err)
+	}
+
+	switch {
+	case probe.Results != nil:
+		var output osvScannerOutput
+		if err := json.Unmarshal(data, &output); err != nil {
+			return nil, fmt.Errorf("invalid OSV: %v", err)
+		}
+		return osvScannerFindings(output)
+
+	case probe.Id != "":
+		var vuln osvVulnerability
+		if err := json.Unmarshal(data, &vuln); err != nil {
+			return nil, fmt.Errorf("invalid OSV: %v", err)
+		}
+		return osvEntries([]osvVulnerability{vuln})
+
+	default:
+		return nil, fmt.Errorf("invalid OSV: neither an osv-scanner output nor an OSV entry")
+	}
+}
+
+func osvScannerFindings(output osvScannerOutput) ([]Finding, error) {
+	var result []Finding
+
+	for _, res := range output.Results {
+		for _, pkg := range res.Packages {
+			for _, vuln := range pkg.Vulnerabilities {
+				if vuln.Id == "" {
+					return nil, fmt.Errorf("invalid OSV: vulnerability without id in %s", pkg.Package.Name)
+				}
+				result = append(result, osvFinding("osv-scanner", vuln, pkg.Package, res.Source.Path))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func osvEntries(vulns []osvVulnerability) ([]Finding, error) {
+	var result []Finding
+
+	for _, vuln := range vulns {
+		if vuln.Id == "" {
+			return nil, fmt.Errorf("invalid OSV: entry without id")
+		}
+		if len(vuln.Affected) == 0 {
+			result = append(result, osvFinding("osv", vuln, osvPackage{}, ""))
+		}
+		for _, affected := range vuln.Affected {
+			result = append(result, osvFinding("osv", vuln, affected.Package, ""))
+		}
+	}
+
+	return result, nil
+}
+
+func osvFinding(tool string, vuln osvVulnerability, pkg osvPackage, source string) Finding {
+	var pkgName string
+	if pkg.Name != "" {
+		pkgName = pkg.Name
+		if pkg.Ecosystem != "" {
+			pkgName = pkg.Ecosystem + "/" + pkg.Name
+		}
+	}
+
+	title := vuln.Id
+	if pkg.Name != "" {
+		title = fmt.Sprintf("%s in %s", title, pkg.Name)
+	}
+	if summary := strings.TrimSpace(vuln.Summary); summary != "" {
+		title = fmt.Sprintf("%s: %s", title, summary)
+	}
+
+	return Finding{
+		Key:      fmt.Sprintf("osv:%s:%s", vuln.Id, pkgName),
+		Tool:     tool,
+		RuleId:   vuln.Id,
+		Package:  pkgName,
+		Severity: osvSeverity(vuln.DatabaseSpecific.Severity),
+		Title:    title,
+		Message:  osvBody(vuln, pkg, source),
+		Labels:   []string{"security"},
+	}
+}
+
+func osvSeverity(severity string) Severity {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return SeverityCritical
+	case "high":
+		return SeverityHigh
+	case "moderate", "medium":
+		return SeverityMedium
+	case "low":
+		return SeverityLow
+	default:
+		return SeverityNone
+	}
+}
+
+func osvBody(vuln osvVulnerability, pkg osvPackage, source string) string {
+	var sb strings.Builder
+
+	if details := strings.TrimSpace(vuln.Details); details != "" {
+		sb.WriteString(details)
+		sb.WriteString("\n\n")
+	}
+
+	fmt.Fprintf(&sb, "Vulnerability: %s\n", vuln.Id)
+	if len(vuln.Aliases) > 0 {
+		fmt.Fprintf(&sb, "Aliases: %s\n", strings.Join(vuln.Aliases, ", "))
+	}
+	if pkg.Name != "" {
+		fmt.Fprintf(&sb, "Package: %s", pkg.Name)
+		if pkg.Version != "" {
+			fmt.Fprintf(&sb, " %s", pkg.Version)
+		}
+		if pkg.Ecosystem != "" {
+			fmt.Fprintf(&sb, " (%s)", pkg.Ecosystem)
+		}
+		sb.WriteString("\n")
+	}
+	if source != "" {
+		fmt.Fprintf(&sb, "Source: %s\n", source)
+	}
+	for _, ref := range vuln.References {
+		if ref.Type == "ADVISORY" || ref.Type == "WEB" {
+			fmt.Fprintf(&sb, "Reference: %s\n", ref.Url)
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const osvScannerSample = `{
+  "results": [{
+    "source": {"path": "/src/go.mod", "type": "lockfile"},
+    "packages": [{
+      "package": {"name": "golang.org/x/text", "version": "0.3.5", "ecosystem": "Go"},
+      "vulnerabilities": [{
+        "id": "GO-2021-0113",
+        "summary": "Out-of-bounds read in golang.org/x/text/language",
+        "aliases": ["CVE-2021-38561"],
+        "database_specific": {"severity": "HIGH"}
+      }]
+    }]
+  }]
+}`
+
+func TestParseOSV(t *testing.T) {
+	findings, err := ParseOSV(strings.NewReader(osvScannerSample))
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+
+	require.Equal(t, "osv:GO-2021-0113:Go/golang.org/x/text", findings[0].Key)
+	require.Equal(t, "osv-scanner", findings[0].Tool)
+	require.Equal(t, "GO-2021-0113", findings[0].RuleId)
+	require.Equal(t, "Go/golang.org/x/text", findings[0].Package)
+	require.Equal(t, SeverityHigh, findings[0].Severity)
+	require.Equal(t, "GO-2021-0113 in golang.org/x/text: Out-of-bounds read in golang.org/x/text/language", findings[0].Title)
+	require.Contains(t, findings[0].Message, "Aliases: CVE-2021-38561")
+	require.Contains(t, findings[0].Message, "Package: golang.org/x/text 0.3.5 (Go)")
+	require.Equal(t, []string{"security"}, findings[0].Labels)
+
+	// a single entry, affecting two packages
+	findings, err = ParseOSV(strings.NewReader(`{
+		"id": "GHSA-xxxx",
+		"affected": [
+			{"package": {"name": "left-pad", "ecosystem": "npm"}},
+			{"package": {"name": "right-pad", "ecosystem": "npm"}}
+		],
+		"database_specific": {"severity": "MODERATE"}
+	}`))
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	require.Equal(t, "osv:GHSA-xxxx:npm/left-pad", findings[0].Key)
+	require.Equal(t, "osv:GHSA-xxxx:npm/right-pad", findings[1].Key)
+	require.Equal(t, SeverityMedium, findings[1].Severity)
+
+	// an array of entries
+	findings, err = ParseOSV(strings.NewReader(`[{"id": "A"}, {"id": "B"}]`))
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	require.Equal(t, SeverityNone, findings[0].Severity)
+
+	_, err = ParseOSV(strings.NewReader(`{"foo": "bar"}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	FullDescription  sarifMessage `json:"fullDescription"`
+	HelpUri          string       `json:"helpUri"`
+
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+
+	Properties struct {
+		Tags             []string `json:"tags"`
+		SecuritySeverity string   `json:"security-severity"`
+	} `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			Uri string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine int `json:"startLine"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+type sarifResult struct {
+	RuleId              string            `json:"ruleId"`
+	RuleIndex           *int              `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLog struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name  string      `json:"name"`
+				Rules []sarifRule `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []sarifResult `json:"results"`
+	} `json:"runs"`
+}
+
+// ParseSARIF read the results of a static analysis tool in the SARIF format.
+// A finding is identified by its tool, its rule and its file, or by its
+// fingerprint when the tool give one. The line is not part of the key, as
+// it move with the edits of the file.
+func ParseSARIF(r io.Reader) ([]Finding, error) {
+	var log sarifLog
+	if err := json.NewDecoder(r).Decode(&log); err != nil {
+		return nil, fmt.Errorf("invalid SARIF: %v", err)
+	}
+	if len(log.Runs) == 0 && log.Version == "" {
+		return nil, fmt.Errorf("invalid SARIF: no runs")
+	}
+
+	var result []Finding
+
+	for _, run := range log.Runs {
+		tool := run.Tool.Driver.Name
+		if tool == "" {
+			return nil, fmt.Errorf("invalid SARIF: missing the tool name")
+		}
+
+		rules := make(map[string]sarifRule)
+		for _, rule := range run.Tool.Driver.Rules {
+			rules[rule.Id] = rule
+		}
+
+		for _, res := range run.Results {
+			ruleId := res.RuleId
+			if ruleId == "" && res.RuleIndex != nil && *res.RuleIndex < len(run.Tool.Driver.Rules) {
+				ruleId = run.Tool.Driver.Rules[*res.RuleIndex].Id
+			}
+			if ruleId == "" {
+				return nil, fmt.Errorf("invalid SARIF: result without a rule in %s", tool)
+			}
+			rule := rules[ruleId]
+
+			var file string
+			line := 0
+			if len(res.Locations) > 0 {
+				file = res.Locations[0].PhysicalLocation.ArtifactLocation.Uri
+				line = res.Locations[0].PhysicalLocation.Region.StartLine
+			}
+
+			f := Finding{
+				Key:      sarifKey(tool, ruleId, file, res.PartialFingerprints),
+				Tool:     tool,
+				RuleId:   ruleId,
+				Severity: sarifSeverity(rule, res),
+				Title:    sarifTitle(rule, ruleId, file),
+				Message:  sarifBody(tool, rule, ruleId, res, file, line),
+				Labels:   []string{"scan:" + strings.ToLower(tool)},
+			}
+
+			for _, tag := range rule.Properties.Tags {
+				if strings.EqualFold(tag, "security") {
+					f.Labels = append(f.Labels, "security")
+					break
+				}
+			}
+
+			result = append(result, f)
+		}
+	}
+
+	return result, nil
+}
+
+func sarifKey(tool string, ruleId string, file string, fingerprints map[string]string) string {
+	if len(fingerprints) > 0 {
+		names := make([]string, 0, len(fingerprints))
+		for name := range fingerprints {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Sprintf("sarif:%s:%s:%s", tool, ruleId, fingerprints[names[0]])
+	}
+	return fmt.Sprintf("sarif:%s:%s:%s", tool, ruleId, file)
+}
+
+// sarifSeverity give the severity of a result, from the security severity
+// of its rule if any, or from its level
+func sarifSeverity(rule sarifRule, res sarifResult) Severity {
+	if score, err := strconv.ParseFloat(rule.Properties.SecuritySeverity, 64); err == nil {
+		return severityFromScore(score)
+	}
+
+	level := res.Level
+	if level == "" {
+		level = rule.DefaultConfiguration.Level
+	}
+
+	switch level {
+	case "error":
+		return SeverityHigh
+	case "warning", "":
+		// warning is the default level of SARIF
+		return SeverityMedium
+	case "note":
+		return SeverityLow
+	default:
+		return SeverityNone
+	}
+}
+
+func sarifTitle(rule sarifRule, ruleId string, file string) string {
+	title := rule.ShortDescription.Text
+	if title == "" {
+		title = ruleId
+	}
+	title = strings.TrimSuffix(strings.TrimSpace(title), ".")
+
+	if file != "" {
+		title = fmt.Sprintf("%s in %s", title, file)
+	}
+	return title
+}
+
+func sarifBody(tool string, rule sarifRule, ruleId string, res sarifResult, file string, line int) string {
+	var sb strings.Builder
+
+	sb.WriteString(strings.TrimSpace(res.Message.Text))
+	sb.WriteString("\n\n")
+
+	fmt.Fprintf(&sb, "Tool: %s\n", tool)
+	fmt.Fprintf(&sb, "Rule: %s\n", ruleId)
+	if file != "" {
+		if line > 0 {
+			fmt.Fprintf(&sb, "Location: %s:%d\n", file, line)
+		} else {
+			fmt.Fprintf(&sb, "Location: %s\n", file)
+		}
+	}
+	if rule.HelpUri != "" {
+		fmt.Fprintf(&sb, "Help: %s\n", rule.HelpUri)
+	}
+	if rule.FullDescription.Text != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimSpace(rule.FullDescription.Text))
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
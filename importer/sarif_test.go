@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sarifSample = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {
+      "name": "CodeQL",
+      "rules": [
+        {
+          "id": "go/sql-injection",
+          "shortDescription": {"text": "Database query built from user-controlled sources."},
+          "properties": {"tags": ["security"], "security-severity": "8.8"}
+        },
+        {
+          "id": "go/unused",
+          "defaultConfiguration": {"level": "note"}
+        }
+      ]
+    }},
+    "results": [
+      {
+        "ruleId": "go/sql-injection",
+        "message": {"text": "This query depends on a user-provided value."},
+        "locations": [{"physicalLocation": {
+          "artifactLocation": {"uri": "db/query.go"},
+          "region": {"startLine": 42}
+        }}]
+      },
+      {
+        "ruleIndex": 1,
+        "message": {"text": "Unused variable."},
+        "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}}}],
+        "partialFingerprints": {"primaryLocationLineHash": "abc123"}
+      }
+    ]
+  }]
+}`
+
+func TestParseSARIF(t *testing.T) {
+	findings, err := ParseSARIF(strings.NewReader(sarifSample))
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	require.Equal(t, "sarif:CodeQL:go/sql-injection:db/query.go", findings[0].Key)
+	require.Equal(t, "CodeQL", findings[0].Tool)
+	require.Equal(t, "go/sql-injection", findings[0].RuleId)
+	require.Equal(t, SeverityHigh, findings[0].Severity)
+	require.Equal(t, "Database query built from user-controlled sources in db/query.go", findings[0].Title)
+	require.Contains(t, findings[0].Message, "Location: db/query.go:42")
+	require.Equal(t, []string{"scan:codeql", "security"}, findings[0].Labels)
+
+	require.Equal(t, "sarif:CodeQL:go/unused:abc123", findings[1].Key)
+	require.Equal(t, "go/unused", findings[1].RuleId)
+	require.Equal(t, SeverityLow, findings[1].Severity)
+	require.Equal(t, "go/unused in main.go", findings[1].Title)
+	require.Equal(t, []string{"scan:codeql"}, findings[1].Labels)
+
+	_, err = ParseSARIF(strings.NewReader(`{}`))
+	require.Error(t, err)
+
+	_, err = ParseSARIF(strings.NewReader(`{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{}]}]}`))
+	require.Error(t, err)
+}
@@ -236,6 +236,77 @@ func QueryEditorInput(repo repository.RepoCommon, preQuery string) (string, erro
 	return "", nil
 }
 
+// ErrEmptyPlan is returned when the rewrite plan has no step left
+var ErrEmptyPlan = errors.New("empty rewrite plan")
+
+const rewriteTemplate = `%s
+# Rewrite of the operations of the bug %s not published yet.
+#
+# Commands:
+# p, pick <operation> = keep the operation
+# e, edit <operation> = edit the text of the operation
+# s, squash <operation> = merge the comment or title change into the previous one
+# d, drop <operation> = remove the operation
+#
+# The lines can't be reordered, and removing a line drops the operation.
+# Lines starting with '#' will be ignored, and an empty plan aborts the rewrite.
+`
+
+// RewritePlanEditorInput will open the default editor in the terminal with the
+// plan of a rewrite for the user to edit. The lines of the plan are returned,
+// without the comments and the empty lines.
+func RewritePlanEditorInput(repo repository.RepoCommon, bugId string, prePlan string) ([]string, error) {
+	template := fmt.Sprintf(rewriteTemplate, prePlan, bugId)
+	raw, err := launchEditorWithTemplate(repo, messageFilename, template)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []string
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		plan = append(plan, trimmed)
+	}
+
+	if len(plan) == 0 {
+		return nil, ErrEmptyPlan
+	}
+
+	return plan, nil
+}
+
+const formAnswerTemplate = `
+# %s
+#
+# Please enter the answer. Lines starting with '#' will be ignored,
+# and an empty answer leaves the question unanswered.
+`
+
+// FormAnswerEditorInput will open the default editor in the terminal for the
+// user to answer a question of a form on several lines. An empty answer is
+// not an error.
+func FormAnswerEditorInput(repo repository.RepoCommon, question string) (string, error) {
+	template := fmt.Sprintf(formAnswerTemplate, question)
+	raw, err := launchEditorWithTemplate(repo, messageFilename, template)
+
+	if err != nil {
+		return "", err
+	}
+
+	answer, err := processComment(raw)
+	if err == ErrEmptyMessage {
+		return "", nil
+	}
+	return answer, err
+}
+
 // launchEditorWithTemplate will launch an editor as launchEditor do, but with a
 // provided template.
 func launchEditorWithTemplate(repo repository.RepoCommon, fileName string, template string) (string, error) {
@@ -0,0 +1,225 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/MichaelMure/git-bug/util/interrupt"
+)
+
+var ErrPickCanceled = errors.New("nothing picked")
+var ErrNoTerminal = errors.New("no terminal to pick from")
+
+// the max number of items displayed at once by Pick
+const pickerHeight = 10
+
+// PickerItem is an item to choose with Pick
+type PickerItem struct {
+	// the value returned when the item is picked
+	Key string
+	// the text displayed
+	Label string
+}
+
+// Pick let the user choose an item in the terminal, among the items matching
+// the text typed so far as returned by search, best match first. The key of
+// the picked item is returned, or ErrPickCanceled.
+//
+// The picker is drawn on the terminal itself rather than on the standard
+// output, so that the standard output can carry the result:
+//
+//	git bug show $(git bug pick)
+//
+// The arrows, ctrl+n and ctrl+p move the selection, enter pick the selected
+// item, escape and ctrl+c cancel.
+func Pick(prompt string, query string, search func(query string) []PickerItem) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", ErrNoTerminal
+	}
+	defer tty.Close()
+
+	fd := int(tty.Fd())
+	if !terminal.IsTerminal(fd) {
+		return "", ErrNoTerminal
+	}
+
+	width, _, err := terminal.GetSize(fd)
+	if err != nil {
+		width = 80
+	}
+
+	termState, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	defer terminal.Restore(fd, termState)
+
+	cancel := interrupt.RegisterCleaner(func() error {
+		return terminal.Restore(fd, termState)
+	})
+	defer cancel()
+
+	p := newPicker(prompt, query, search)
+
+	// erase the picker once done
+	defer fmt.Fprint(tty, "\r\x1b[J")
+
+	buf := make([]byte, 64)
+	for {
+		_, _ = fmt.Fprint(tty, p.render(width))
+
+		n, err := tty.Read(buf)
+		if err != nil {
+			return "", err
+		}
+
+		key, err := p.handle(buf[:n])
+		if err != nil || key != "" {
+			return key, err
+		}
+	}
+}
+
+// picker is the state of Pick: the text typed and the matching items
+type picker struct {
+	prompt   string
+	query    []rune
+	search   func(query string) []PickerItem
+	items    []PickerItem
+	selected int
+}
+
+func newPicker(prompt string, query string, search func(query string) []PickerItem) *picker {
+	p := &picker{
+		prompt: prompt,
+		query:  []rune(query),
+		search: search,
+	}
+	p.update()
+	return p
+}
+
+func (p *picker) update() {
+	p.items = p.search(string(p.query))
+	p.selected = 0
+}
+
+func (p *picker) move(delta int) {
+	p.selected += delta
+	if p.selected >= len(p.items) {
+		p.selected = len(p.items) - 1
+	}
+	if p.selected < 0 {
+		p.selected = 0
+	}
+}
+
+// handle process the keys read from the terminal, and return the key of the
+// picked item once picked
+func (p *picker) handle(input []byte) (string, error) {
+	// escape sequences
+	if len(input) > 0 && input[0] == 27 {
+		if len(input) == 1 {
+			return "", ErrPickCanceled
+		}
+		if len(input) >= 3 && (input[1] == '[' || input[1] == 'O') {
+			switch input[2] {
+			case 'A':
+				p.move(-1)
+			case 'B':
+				p.move(1)
+			}
+		}
+		return "", nil
+	}
+
+	for len(input) > 0 {
+		r, size := utf8.DecodeRune(input)
+		input = input[size:]
+
+		switch {
+		case r == 3: // ctrl+c
+			return "", ErrPickCanceled
+		case r == '\r' || r == '\n':
+			if len(p.items) > 0 {
+				return p.items[p.selected].Key, nil
+			}
+		case r == 127 || r == 8: // backspace
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+				p.update()
+			}
+		case r == 21: // ctrl+u
+			p.query = nil
+			p.update()
+		case r == 14: // ctrl+n
+			p.move(1)
+		case r == 16: // ctrl+p
+			p.move(-1)
+		case unicode.IsPrint(r):
+			p.query = append(p.query, r)
+			p.update()
+		}
+	}
+
+	return "", nil
+}
+
+// render draw the picker from the current line, and leave the cursor after
+// the text typed
+func (p *picker) render(width int) string {
+	var b strings.Builder
+
+	// clear the previous rendering
+	b.WriteString("\r\x1b[J")
+	b.WriteString(p.prompt)
+	b.WriteString(string(p.query))
+
+	// scroll the items to keep the selected one visible
+	start := 0
+	if p.selected >= pickerHeight {
+		start = p.selected - pickerHeight + 1
+	}
+	end := start + pickerHeight
+	if end > len(p.items) {
+		end = len(p.items)
+	}
+
+	lines := end - start
+	for i := start; i < end; i++ {
+		label := truncate(p.items[i].Label, width-3)
+		if i == p.selected {
+			b.WriteString("\r\n\x1b[7m> " + label + "\x1b[0m")
+		} else {
+			b.WriteString("\r\n  " + label)
+		}
+	}
+	if len(p.items) == 0 {
+		b.WriteString("\r\n  no match")
+		lines = 1
+	}
+
+	// back to the prompt line
+	b.WriteString(fmt.Sprintf("\x1b[%dA\r", lines))
+	column := utf8.RuneCountInString(p.prompt) + len(p.query)
+	if column > 0 {
+		b.WriteString(fmt.Sprintf("\x1b[%dC", column))
+	}
+
+	return b.String()
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
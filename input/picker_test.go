@@ -0,0 +1,74 @@
+package input
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPicker(t *testing.T) {
+	items := []PickerItem{
+		{Key: "1", Label: "crash when pushing"},
+		{Key: "2", Label: "add cookies"},
+		{Key: "3", Label: "crash at start"},
+	}
+	search := func(query string) []PickerItem {
+		var result []PickerItem
+		for _, item := range items {
+			if strings.Contains(item.Label, query) {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+
+	p := newPicker("bug: ", "", search)
+	require.Len(t, p.items, 3)
+
+	key, err := p.handle([]byte("crash"))
+	require.NoError(t, err)
+	require.Empty(t, key)
+	require.Len(t, p.items, 2)
+
+	// down arrow, then enter
+	_, err = p.handle([]byte("\x1b[B"))
+	require.NoError(t, err)
+	key, err = p.handle([]byte("\r"))
+	require.NoError(t, err)
+	require.Equal(t, "3", key)
+
+	// the selection stay within the items
+	_, _ = p.handle([]byte{16, 16})
+	require.Equal(t, 0, p.selected)
+
+	// no match, enter does nothing
+	_, _ = p.handle([]byte("zz"))
+	require.Empty(t, p.items)
+	key, err = p.handle([]byte("\r"))
+	require.NoError(t, err)
+	require.Empty(t, key)
+
+	// backspace and ctrl+u
+	_, _ = p.handle([]byte{127, 127})
+	require.Equal(t, "crash", string(p.query))
+	_, _ = p.handle([]byte{21})
+	require.Len(t, p.items, 3)
+
+	_, err = p.handle([]byte{27})
+	require.Equal(t, ErrPickCanceled, err)
+	_, err = p.handle([]byte{3})
+	require.Equal(t, ErrPickCanceled, err)
+}
+
+func TestPickerRender(t *testing.T) {
+	p := newPicker("bug: ", "ab", func(query string) []PickerItem {
+		return []PickerItem{{Key: "1", Label: "a very long label"}}
+	})
+
+	out := p.render(10)
+	require.Contains(t, out, "bug: ab")
+	require.Contains(t, out, "> a very…")
+	// back on the prompt, after the text typed
+	require.True(t, strings.HasSuffix(out, "\x1b[1A\r\x1b[7C"))
+}
@@ -158,6 +158,84 @@ func PromptChoice(prompt string, choices []string) (int, error) {
 	}
 }
 
+// PromptKey is a prompt answered with a single key among the given ones,
+// without having to press enter when the input is a terminal.
+func PromptKey(prompt string, keys string) (rune, error) {
+	for {
+		_, _ = fmt.Fprintf(os.Stderr, "%s: ", prompt)
+
+		key, err := readKey()
+		if key != 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "%c", key)
+		}
+		_, _ = fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return 0, err
+		}
+
+		if key != 0 && strings.ContainsRune(keys, key) {
+			return key, nil
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "invalid input")
+	}
+}
+
+// readKey read a single key from a terminal in raw mode, or the first
+// character of a line otherwise
+func readKey() (rune, error) {
+	fd := int(syscall.Stdin)
+
+	if !terminal.IsTerminal(fd) {
+		// read byte by byte to not consume the input beyond the line
+		var line []byte
+		buf := make([]byte, 1)
+		for {
+			_, err := os.Stdin.Read(buf)
+			if err != nil {
+				return 0, err
+			}
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		trimmed := strings.TrimSpace(string(line))
+		if trimmed == "" {
+			return 0, nil
+		}
+		return []rune(trimmed)[0], nil
+	}
+
+	termState, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return 0, err
+	}
+
+	cancel := interrupt.RegisterCleaner(func() error {
+		return terminal.Restore(fd, termState)
+	})
+	defer cancel()
+
+	buf := make([]byte, 1)
+	_, err = os.Stdin.Read(buf)
+
+	errRestore := terminal.Restore(fd, termState)
+	if err != nil {
+		return 0, err
+	}
+	if errRestore != nil {
+		return 0, errRestore
+	}
+
+	// ctrl-c is not delivered as a signal in raw mode
+	if buf[0] == 3 {
+		return 0, fmt.Errorf("interrupted")
+	}
+
+	return rune(buf[0]), nil
+}
+
 func PromptURLWithRemote(prompt, name string, validRemotes []string, validators ...PromptValidator) (string, error) {
 	if len(validRemotes) == 0 {
 		return Prompt(prompt, name, validators...)
@@ -0,0 +1,326 @@
+package random_bugs
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/icrowley/fake"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// PopulateOptions describe the synthetic data generated by Populate
+type PopulateOptions struct {
+	BugNumber      int
+	IdentityNumber int
+	// the maximum number of comments of a bug, most bugs having a few
+	MaxComments int
+	// the generated histories span this duration, up to now
+	Period time.Duration
+	Seed   int64
+	// Progress is called, if set, after each committed bug
+	Progress func(done int)
+}
+
+func DefaultPopulateOptions() PopulateOptions {
+	return PopulateOptions{
+		BugNumber:      1000,
+		IdentityNumber: 50,
+		MaxComments:    50,
+		Period:         2 * 365 * 24 * time.Hour,
+		Seed:           time.Now().UnixNano(),
+	}
+}
+
+// the labels and components of a typical project, the first ones being the
+// most used
+var (
+	populateLabels = []string{
+		"bug", "enhancement", "question", "documentation", "ui", "performance",
+		"regression", "good first issue", "help wanted", "security", "wontfix",
+	}
+	populateComponents = []string{
+		"core", "cache", "cli", "webui", "termui", "bridge", "docs",
+	}
+	populateResolutions = []bug.Resolution{
+		bug.FixedResolution, bug.FixedResolution, bug.FixedResolution,
+		bug.WontFixResolution, bug.InvalidResolution, bug.WorksForMeResolution,
+	}
+)
+
+// Populate commit in the repository realistic random identities and bugs,
+// with histories of comments, labels, assignees, votes, checklists and status
+// changes spread over a period of time up to now. The content is the same for a
+// given seed.
+func Populate(repo repository.ClockedRepo, opts PopulateOptions) error {
+	if opts.BugNumber < 0 || opts.IdentityNumber <= 0 || opts.MaxComments < 0 {
+		return fmt.Errorf("invalid options")
+	}
+
+	rand.Seed(opts.Seed)
+	fake.Seed(opts.Seed)
+
+	persons = make([]identity.Interface, opts.IdentityNumber)
+	for i := range persons {
+		p := person()
+		if err := p.Commit(repo); err != nil {
+			return err
+		}
+		persons[i] = p
+	}
+
+	now := time.Now().Unix()
+	start := now - int64(opts.Period/time.Second)
+
+	for i := 0; i < opts.BugNumber; i++ {
+		// the creations are spread regularly over the period, in order
+		created := start + int64(i)*(now-start)/int64(opts.BugNumber)
+
+		b, err := populateBug(created, now, opts.MaxComments)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Commit(repo); err != nil {
+			return err
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(i + 1)
+		}
+	}
+
+	return nil
+}
+
+// bugHistory generate the operations of a bug, with strictly increasing times
+// to avoid the collisions of the operations ids
+type bugHistory struct {
+	b         *bug.Bug
+	timestamp int64
+	end       int64
+
+	author    identity.Interface
+	labels    []string
+	assignees []identity.Interface
+	voters    map[identity.Interface]bool
+	items     []entity.Id
+	closed    bool
+}
+
+// next return the time of the next operation, some minutes to some days
+// later, without going past the end of the period if possible
+func (h *bugHistory) next() int64 {
+	delay := int64(60 + rand.Intn(3600))
+	if rand.Intn(4) == 0 {
+		delay += int64(rand.Intn(7 * 24 * 3600))
+	}
+	if h.timestamp+delay > h.end {
+		delay = 1
+	}
+	h.timestamp += delay
+	return h.timestamp
+}
+
+func populateBug(created int64, end int64, maxComments int) (*bug.Bug, error) {
+	h := &bugHistory{
+		timestamp: created,
+		end:       end,
+		author:    randomPerson(),
+		voters:    make(map[identity.Interface]bool),
+	}
+
+	b, _, err := bug.Create(h.author, created, fake.Sentence(), paragraphs())
+	if err != nil {
+		return nil, err
+	}
+	h.b = b
+
+	// most bugs are triaged right away
+	if rand.Intn(10) < 7 {
+		if err := h.changeLabels(); err != nil {
+			return nil, err
+		}
+	}
+	if rand.Intn(2) == 0 {
+		if err := h.setComponent(); err != nil {
+			return nil, err
+		}
+	}
+
+	// most bugs have a few comments, some have a lot
+	nComments := 0
+	if maxComments > 0 {
+		nComments = rand.Intn(rand.Intn(maxComments) + 1)
+	}
+
+	events := []func() error{
+		h.changeLabels,
+		h.changeAssignees,
+		h.vote,
+		h.vote,
+		h.addChecklistItem,
+		h.checkItem,
+		h.setTitle,
+		h.editCreateComment,
+		h.toggleStatus,
+	}
+
+	for j := 0; j < nComments; j++ {
+		if err := h.comment(); err != nil {
+			return nil, err
+		}
+		if rand.Intn(3) == 0 {
+			if err := events[rand.Intn(len(events))](); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// most old bugs end up closed
+	if !h.closed && rand.Intn(10) < 6 {
+		if err := h.close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// participant return the author of the bug half of the time, someone else
+// otherwise
+func (h *bugHistory) participant() identity.Interface {
+	if rand.Intn(2) == 0 {
+		return h.author
+	}
+	return randomPerson()
+}
+
+func (h *bugHistory) comment() error {
+	_, err := bug.AddComment(h.b, h.participant(), h.next(), paragraphs())
+	return err
+}
+
+func (h *bugHistory) setTitle() error {
+	_, err := bug.SetTitle(h.b, h.participant(), h.next(), fake.Sentence())
+	return err
+}
+
+func (h *bugHistory) editCreateComment() error {
+	_, err := bug.EditCreateComment(h.b, h.author, h.next(), paragraphs())
+	return err
+}
+
+func (h *bugHistory) setComponent() error {
+	component := populateComponents[rand.Intn(len(populateComponents))]
+	_, err := bug.SetComponent(h.b, randomPerson(), h.next(), component)
+	return err
+}
+
+func (h *bugHistory) changeLabels() error {
+	var removed []string
+	if len(h.labels) > 0 && rand.Intn(3) == 0 {
+		index := rand.Intn(len(h.labels))
+		removed = append(removed, h.labels[index])
+		h.labels = append(h.labels[:index], h.labels[index+1:]...)
+	}
+
+	var added []string
+	for n := rand.Intn(3); n > 0; n-- {
+		// favor the first labels of the list
+		label := populateLabels[rand.Intn(rand.Intn(len(populateLabels))+1)]
+		if containsLabel(h.labels, label) || containsLabel(added, label) || containsLabel(removed, label) {
+			continue
+		}
+		added = append(added, label)
+	}
+	h.labels = append(h.labels, added...)
+
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	_, _, err := bug.ChangeLabels(h.b, randomPerson(), h.next(), added, removed)
+	return err
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *bugHistory) changeAssignees() error {
+	var added, removed []identity.Interface
+	if len(h.assignees) > 0 && rand.Intn(2) == 0 {
+		removed = h.assignees
+		h.assignees = nil
+	} else {
+		assignee := randomPerson()
+		for _, a := range h.assignees {
+			if a == assignee {
+				return nil
+			}
+		}
+		added = []identity.Interface{assignee}
+		h.assignees = append(h.assignees, assignee)
+	}
+
+	_, err := bug.ChangeAssignees(h.b, randomPerson(), h.next(), added, removed)
+	return err
+}
+
+func (h *bugHistory) vote() error {
+	voter := randomPerson()
+	retract := h.voters[voter]
+	h.voters[voter] = !retract
+
+	_, err := bug.Vote(h.b, voter, h.next(), retract)
+	return err
+}
+
+func (h *bugHistory) addChecklistItem() error {
+	op, err := bug.AddChecklistItem(h.b, h.participant(), h.next(), fake.Sentence())
+	if err != nil {
+		return err
+	}
+	h.items = append(h.items, op.Id())
+	return nil
+}
+
+func (h *bugHistory) checkItem() error {
+	if len(h.items) == 0 {
+		return h.addChecklistItem()
+	}
+
+	index := rand.Intn(len(h.items))
+	_, err := bug.CheckItem(h.b, h.participant(), h.next(), h.items[index], true)
+	if err != nil {
+		return err
+	}
+	h.items = append(h.items[:index], h.items[index+1:]...)
+	return nil
+}
+
+func (h *bugHistory) toggleStatus() error {
+	if h.closed {
+		h.closed = false
+		_, err := bug.Open(h.b, h.participant(), h.next())
+		return err
+	}
+	return h.close()
+}
+
+func (h *bugHistory) close() error {
+	h.closed = true
+	resolution := populateResolutions[rand.Intn(len(populateResolutions))]
+	_, err := bug.CloseWithResolution(h.b, h.participant(), h.next(), resolution)
+	return err
+}
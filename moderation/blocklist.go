@@ -0,0 +1,437 @@
+// Package moderation contains the moderation data model, that is a blocklist
+// of identities shared between the repositories, and the related functions.
+package moderation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+const moderationRefPattern = "refs/moderation/"
+const moderationRemoteRefPattern = "refs/remotes/%s/moderation/"
+
+const blocklistRef = moderationRefPattern + "blocklist"
+const blocklistRemoteRefPattern = moderationRemoteRefPattern + "blocklist"
+
+const blocklistEntryName = "blocklist"
+
+const formatVersion = 1
+
+// Entry is the moderation state of a single identity
+type Entry struct {
+	// the moderated identity
+	IdentityId entity.Id `json:"identity"`
+	Blocked    bool      `json:"blocked"`
+	// the identity that changed the state
+	AuthorId entity.Id `json:"author"`
+	// the edit lamport time of the change, giving the keys of the author
+	// valid to sign it
+	EditTime lamport.Time `json:"edit_time,omitempty"`
+	UnixTime int64        `json:"timestamp"`
+	Reason   string       `json:"reason,omitempty"`
+
+	// the signature of the entry by its author, if signed
+	Signature *identity.DataSignature `json:"signature,omitempty"`
+}
+
+// signedData return the serialized entry covered by its signature
+func (e Entry) signedData() ([]byte, error) {
+	e.Signature = nil
+	return json.Marshal(e)
+}
+
+// Validate check if the Entry data is valid
+func (e Entry) Validate() error {
+	if err := e.IdentityId.Validate(); err != nil {
+		return errors.Wrap(err, "invalid identity id")
+	}
+
+	if err := e.AuthorId.Validate(); err != nil {
+		return errors.Wrap(err, "invalid author id")
+	}
+
+	if e.UnixTime <= 0 {
+		return fmt.Errorf("time not set")
+	}
+
+	return nil
+}
+
+// Blocklist hold the identities whose operations are ignored when compiling
+// the bugs. It is stored in git as a chain of commits, each holding the full
+// state, and propagate with the bugs.
+//
+// When the repository configure some maintainers, only the entries written by
+// them are honored. The entries of a remote are only merged if some
+// maintainers are configured, and if signed by their author.
+type Blocklist struct {
+	entries    map[entity.Id]Entry
+	lastCommit git.Hash
+
+	// the authors of the entries changed since the last commit, to sign
+	changed map[entity.Id]identity.Interface
+
+	// nil if no maintainers are configured
+	maintainers map[entity.Id]struct{}
+}
+
+// Read load the local blocklist. An empty blocklist is returned if none exist.
+func Read(repo repository.Repo) (*Blocklist, error) {
+	return read(repo, blocklistRef)
+}
+
+// ReadRemote load the blocklist of a remote, as of the last fetch
+func ReadRemote(repo repository.Repo, remote string) (*Blocklist, error) {
+	return read(repo, fmt.Sprintf(blocklistRemoteRefPattern, remote))
+}
+
+func read(repo repository.Repo, ref string) (*Blocklist, error) {
+	maintainers, err := bug.ReadMaintainers(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Blocklist{
+		entries: make(map[entity.Id]Entry),
+		changed: make(map[entity.Id]identity.Interface),
+	}
+
+	if len(maintainers) > 0 {
+		b.maintainers = make(map[entity.Id]struct{}, len(maintainers))
+		for _, id := range maintainers {
+			b.maintainers[id] = struct{}{}
+		}
+	}
+
+	exist, err := repo.RefExist(ref)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return b, nil
+	}
+
+	hashes, err := repo.ListCommits(ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) == 0 {
+		return b, nil
+	}
+
+	b.lastCommit = hashes[len(hashes)-1]
+
+	entries, err := readEntries(repo, b.lastCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		b.entries[e.IdentityId] = e
+	}
+
+	return b, nil
+}
+
+func readEntries(repo repository.Repo, commit git.Hash) ([]Entry, error) {
+	treeHash, err := repo.GetTreeHash(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	treeEntries, err := repo.ListEntries(treeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, treeEntry := range treeEntries {
+		if treeEntry.Name != blocklistEntryName {
+			continue
+		}
+
+		data, err := repo.ReadData(treeEntry.Hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read git blob data")
+		}
+
+		aux := struct {
+			Version uint    `json:"version"`
+			Entries []Entry `json:"entries"`
+		}{}
+
+		err = json.Unmarshal(data, &aux)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode the blocklist")
+		}
+
+		if aux.Version != formatVersion {
+			return nil, fmt.Errorf("unknown blocklist format version %v", aux.Version)
+		}
+
+		for _, e := range aux.Entries {
+			if err := e.Validate(); err != nil {
+				return nil, errors.Wrap(err, "invalid blocklist entry")
+			}
+		}
+
+		return aux.Entries, nil
+	}
+
+	return nil, fmt.Errorf("no blocklist entry in the git tree")
+}
+
+// LastCommit return the hash of the git commit holding the current state, if
+// any. It change each time the blocklist is updated.
+func (b *Blocklist) LastCommit() git.Hash {
+	return b.lastCommit
+}
+
+// IsBlocked tell if the operations of the given identity should be ignored
+func (b *Blocklist) IsBlocked(id entity.Id) bool {
+	e, ok := b.entries[id]
+	return ok && e.Blocked && b.trusted(e.AuthorId)
+}
+
+// Entries return all the honored entries, sorted by time
+func (b *Blocklist) Entries() []Entry {
+	result := make([]Entry, 0, len(b.entries))
+	for _, e := range b.entries {
+		if b.trusted(e.AuthorId) {
+			result = append(result, e)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UnixTime < result[j].UnixTime
+	})
+
+	return result
+}
+
+// Blocked return the ids of the honored blocked identities
+func (b *Blocklist) Blocked() []entity.Id {
+	var result []entity.Id
+	for _, e := range b.Entries() {
+		if e.Blocked {
+			result = append(result, e.IdentityId)
+		}
+	}
+	return result
+}
+
+func (b *Blocklist) trusted(author entity.Id) bool {
+	if b.maintainers == nil {
+		return true
+	}
+	_, ok := b.maintainers[author]
+	return ok
+}
+
+// verify check that an entry coming from elsewhere is written by a
+// maintainer, and signed by it with a key valid at the time of the change
+func (b *Blocklist) verify(e Entry, resolver identity.Resolver) error {
+	if b.maintainers == nil {
+		return fmt.Errorf("no maintainers are configured")
+	}
+	if !b.trusted(e.AuthorId) {
+		return fmt.Errorf("%s is not a maintainer", e.AuthorId.Human())
+	}
+
+	if e.Signature == nil {
+		return fmt.Errorf("the entry of %s is not signed", e.IdentityId.Human())
+	}
+
+	author, err := resolver.ResolveIdentity(e.AuthorId)
+	if err != nil {
+		return errors.Wrap(err, "unknown author")
+	}
+
+	data, err := e.signedData()
+	if err != nil {
+		return err
+	}
+
+	return e.Signature.Verify(data, author.ValidKeysAtTime(e.EditTime))
+}
+
+// CheckPolicy return an error if the given identity is not allowed to change
+// the blocklist. When some maintainers are configured, only them can.
+func (b *Blocklist) CheckPolicy(author entity.Id) error {
+	if !b.trusted(author) {
+		return fmt.Errorf("%s is not a maintainer", author.Human())
+	}
+	return nil
+}
+
+// Block add an identity to the blocklist
+func (b *Blocklist) Block(author identity.Interface, unixTime int64, id entity.Id, reason string) error {
+	return b.set(author, Entry{
+		IdentityId: id,
+		Blocked:    true,
+		AuthorId:   author.Id(),
+		UnixTime:   unixTime,
+		Reason:     reason,
+	})
+}
+
+// Unblock remove an identity from the blocklist
+func (b *Blocklist) Unblock(author identity.Interface, unixTime int64, id entity.Id) error {
+	if !b.IsBlocked(id) {
+		return fmt.Errorf("%s is not blocked", id.Human())
+	}
+
+	return b.set(author, Entry{
+		IdentityId: id,
+		Blocked:    false,
+		AuthorId:   author.Id(),
+		UnixTime:   unixTime,
+	})
+}
+
+func (b *Blocklist) set(author identity.Interface, e Entry) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	if err := b.CheckPolicy(e.AuthorId); err != nil {
+		return err
+	}
+
+	b.entries[e.IdentityId] = e
+	b.changed[e.IdentityId] = author
+	return nil
+}
+
+// Commit write the current state of the blocklist in git, on top of the
+// previous one. The changed entries are signed with the Signer configured in
+// the repository, if any. Unsigned entries are only honored locally.
+func (b *Blocklist) Commit(repo repository.ClockedRepo) error {
+	if len(b.changed) > 0 {
+		err := b.sign(repo)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := b.commit(repo, b.lastCommit)
+	if err != nil {
+		return err
+	}
+
+	b.changed = make(map[entity.Id]identity.Interface)
+	return nil
+}
+
+// sign date and sign the changed entries
+func (b *Blocklist) sign(repo repository.ClockedRepo) error {
+	// the changes are dated after the last version of their authors, for the
+	// current keys of the authors to be the valid ones
+	for _, author := range b.changed {
+		err := repo.WitnessEdit(author.LastModificationLamport())
+		if err != nil {
+			return err
+		}
+	}
+
+	editTime, err := repo.EditTimeIncrement()
+	if err != nil {
+		return err
+	}
+
+	for id, author := range b.changed {
+		e := b.entries[id]
+		e.EditTime = editTime
+
+		data, err := e.signedData()
+		if err != nil {
+			return err
+		}
+
+		e.Signature, err = identity.SignData(repo, data)
+		if err != nil {
+			return errors.Wrap(err, "can't sign the blocklist")
+		}
+		if e.Signature != nil && !identity.HasKey(author.ValidKeysAtTime(editTime), e.Signature.Key.Fingerprint) {
+			return fmt.Errorf("the key of the signer is not a key of %s", author.DisplayName())
+		}
+
+		b.entries[id] = e
+	}
+
+	return nil
+}
+
+// IsSigned tell if all the honored entries are signed, and so can be merged
+// by the other repositories
+func (b *Blocklist) IsSigned() bool {
+	for _, e := range b.Entries() {
+		if e.Signature == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Blocklist) commit(repo repository.Repo, parent git.Hash) error {
+	entries := make([]Entry, 0, len(b.entries))
+	for _, e := range b.entries {
+		entries = append(entries, e)
+	}
+
+	// keep the serialization stable
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].IdentityId < entries[j].IdentityId
+	})
+
+	data, err := json.Marshal(struct {
+		Version uint    `json:"version"`
+		Entries []Entry `json:"entries"`
+	}{
+		Version: formatVersion,
+		Entries: entries,
+	})
+	if err != nil {
+		return err
+	}
+
+	blobHash, err := repo.StoreData(data)
+	if err != nil {
+		return err
+	}
+
+	treeHash, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blobHash, Name: blocklistEntryName},
+	})
+	if err != nil {
+		return err
+	}
+
+	var commitHash git.Hash
+	if parent != "" {
+		commitHash, err = repo.StoreCommitWithParent(treeHash, parent)
+	} else {
+		commitHash, err = repo.StoreCommit(treeHash)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = repo.UpdateRef(blocklistRef, commitHash)
+	if err != nil {
+		return err
+	}
+
+	b.lastCommit = commitHash
+
+	return nil
+}
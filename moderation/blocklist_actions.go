@@ -0,0 +1,132 @@
+package moderation
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// Fetch retrieve the blocklist of a remote
+// This does not change the local blocklist
+func Fetch(repo repository.Repo, remote string) (string, error) {
+	remoteRefSpec := fmt.Sprintf(moderationRemoteRefPattern, remote)
+	fetchRefSpec := fmt.Sprintf("%s*:%s*", moderationRefPattern, remoteRefSpec)
+
+	return repo.FetchRefs(remote, fetchRefSpec)
+}
+
+// Push update the blocklist of a remote with the local one
+func Push(repo repository.Repo, remote string) (string, error) {
+	return repo.PushRefs(remote, moderationRefPattern+"*")
+}
+
+// Merge merge the blocklist of a remote into the local one, and return true if
+// the local blocklist changed.
+//
+// The entries of the remote are only merged if some maintainers are
+// configured, and if written and signed by one of them, as anyone able to push
+// could otherwise block anyone. Without maintainers, nothing is merged.
+//
+//   - if the remote blocklist is behind, nothing is changed
+//   - if the local blocklist is behind and all the new entries are valid, it
+//     is updated to match (fast-forward)
+//   - otherwise, the most recent valid entry of each identity is kept and the
+//     result is committed on top of the remote, so that it can be pushed
+func Merge(repo repository.Repo, remote string) (bool, error) {
+	remoteRef := fmt.Sprintf(blocklistRemoteRefPattern, remote)
+
+	remoteExist, err := repo.RefExist(remoteRef)
+	if err != nil || !remoteExist {
+		return false, err
+	}
+
+	local, err := Read(repo)
+	if err != nil {
+		return false, err
+	}
+
+	if local.maintainers == nil {
+		return false, nil
+	}
+
+	other, err := ReadRemote(repo, remote)
+	if err != nil {
+		return false, err
+	}
+
+	if local.lastCommit == other.lastCommit {
+		return false, nil
+	}
+
+	fastForward := local.lastCommit == ""
+
+	if local.lastCommit != "" {
+		ancestor, err := repo.FindCommonAncestor(local.lastCommit, other.lastCommit)
+		if err != nil {
+			return false, err
+		}
+
+		// the remote is behind
+		if ancestor == other.lastCommit {
+			return false, nil
+		}
+
+		fastForward = ancestor == local.lastCommit
+	}
+
+	resolver := identity.NewRemoteResolver(repo, remote)
+
+	for id, e := range other.entries {
+		current, ok := local.entries[id]
+		if ok && reflect.DeepEqual(current, e) {
+			continue
+		}
+		if ok && e.UnixTime <= current.UnixTime {
+			// the local entry is more recent
+			fastForward = false
+			continue
+		}
+		if local.verify(e, resolver) != nil {
+			fastForward = false
+			continue
+		}
+		local.entries[id] = e
+	}
+
+	if fastForward {
+		return true, repo.UpdateRef(blocklistRef, other.lastCommit)
+	}
+
+	return true, local.commit(repo, other.lastCommit)
+}
+
+// CheckPushed verify the blocklist pushed to this repository, before its ref
+// is updated to the new head: the new or changed entries must be written and
+// signed by a maintainer of this repository. The identities are loaded with
+// the given resolver, to find the ones pushed along.
+func CheckPushed(repo repository.Repo, resolver identity.Resolver, head git.Hash) error {
+	local, err := Read(repo)
+	if err != nil {
+		return err
+	}
+
+	entries, err := readEntries(repo, head)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		current, ok := local.entries[e.IdentityId]
+		if ok && reflect.DeepEqual(current, e) {
+			continue
+		}
+		if err := local.verify(e, resolver); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/identity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestBlocklistCommitRead(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, rene.Commit(repo))
+	spammer := identity.NewIdentity("Spammer", "spam@spam.com")
+	require.NoError(t, spammer.Commit(repo))
+
+	b, err := Read(repo)
+	require.NoError(t, err)
+	require.False(t, b.IsBlocked(spammer.Id()))
+	require.Empty(t, b.LastCommit())
+
+	require.NoError(t, b.Block(rene, 1, spammer.Id(), "spam"))
+	require.NoError(t, b.Commit(repo))
+
+	b, err = Read(repo)
+	require.NoError(t, err)
+	require.True(t, b.IsBlocked(spammer.Id()))
+	require.Len(t, b.Entries(), 1)
+	require.Equal(t, "spam", b.Entries()[0].Reason)
+
+	// only the maintainers are honored if configured
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.maintainers", spammer.Id().String()))
+
+	b, err = Read(repo)
+	require.NoError(t, err)
+	require.False(t, b.IsBlocked(spammer.Id()))
+	require.Error(t, b.Block(rene, 2, spammer.Id(), ""))
+
+	require.NoError(t, repo.LocalConfig().StoreString("git-bug.maintainers", rene.Id().String()))
+
+	b, err = Read(repo)
+	require.NoError(t, err)
+	require.True(t, b.IsBlocked(spammer.Id()))
+
+	require.NoError(t, b.Unblock(rene, 3, spammer.Id()))
+	require.NoError(t, b.Commit(repo))
+
+	b, err = Read(repo)
+	require.NoError(t, err)
+	require.False(t, b.IsBlocked(spammer.Id()))
+	require.Error(t, b.Unblock(rene, 4, spammer.Id()))
+}
+
+func TestBlocklistMerge(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	// the entries are signed with a key of rene, from both repositories
+	signer, err := identity.NewTestSigner()
+	require.NoError(t, err)
+	key, err := signer.Key()
+	require.NoError(t, err)
+
+	rene := identity.NewIdentity("René Descartes", "rene@descartes.fr")
+	rene.Mutate(func(m identity.Mutator) identity.Mutator {
+		m.Keys = append(m.Keys, key)
+		return m
+	})
+	spammer1 := identity.NewIdentity("Spammer 1", "spam1@spam.com")
+	spammer2 := identity.NewIdentity("Spammer 2", "spam2@spam.com")
+	for _, i := range []*identity.Identity{rene, spammer1, spammer2} {
+		require.NoError(t, i.Commit(repoA))
+	}
+	_, err = identity.Push(repoA, "origin")
+	require.NoError(t, err)
+	_, err = identity.Fetch(repoB, "origin")
+	require.NoError(t, err)
+
+	for _, repo := range []repository.ClockedRepo{repoA, repoB} {
+		require.NoError(t, identity.SetSigner(repo, signer, false))
+	}
+
+	blA, err := Read(repoA)
+	require.NoError(t, err)
+	require.NoError(t, blA.Block(rene, 1, spammer1.Id(), ""))
+	require.NoError(t, blA.Commit(repoA))
+	require.True(t, blA.IsSigned())
+
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	// nothing to merge yet
+	for _, repo := range []repository.Repo{repoA, repoB} {
+		_, err = Fetch(repo, "origin")
+		require.NoError(t, err)
+	}
+	updated, err := Merge(repoA, "origin")
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	// without maintainers, the remote entries are not honored
+	updated, err = Merge(repoB, "origin")
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	for _, repo := range []repository.Repo{repoA, repoB} {
+		require.NoError(t, repo.LocalConfig().StoreString("git-bug.maintainers", rene.Id().String()))
+	}
+
+	// new blocklist
+	updated, err = Merge(repoB, "origin")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	blB, err := Read(repoB)
+	require.NoError(t, err)
+	require.True(t, blB.IsBlocked(spammer1.Id()))
+
+	// concurrent edition
+	require.NoError(t, blA.Block(rene, 2, spammer2.Id(), ""))
+	require.NoError(t, blA.Commit(repoA))
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	require.NoError(t, blB.Unblock(rene, 3, spammer1.Id()))
+	require.NoError(t, blB.Commit(repoB))
+
+	_, err = Fetch(repoB, "origin")
+	require.NoError(t, err)
+	updated, err = Merge(repoB, "origin")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	blB, err = Read(repoB)
+	require.NoError(t, err)
+	require.False(t, blB.IsBlocked(spammer1.Id()))
+	require.True(t, blB.IsBlocked(spammer2.Id()))
+
+	// the merged blocklist is on top of the remote one, and can be pushed
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+
+	_, err = Fetch(repoA, "origin")
+	require.NoError(t, err)
+	updated, err = Merge(repoA, "origin")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	blA, err = Read(repoA)
+	require.NoError(t, err)
+	require.Equal(t, blB.LastCommit(), blA.LastCommit())
+	require.False(t, blA.IsBlocked(spammer1.Id()))
+	require.True(t, blA.IsBlocked(spammer2.Id()))
+	merged := blA.LastCommit()
+
+	// an entry claiming to be from rene, but unsigned, is not merged
+	require.NoError(t, repoB.LocalConfig().RemoveAll("git-bug.signer"))
+	require.NoError(t, blB.Block(rene, 4, spammer1.Id(), ""))
+	require.NoError(t, blB.Commit(repoB))
+	require.False(t, blB.IsSigned())
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+
+	_, err = Fetch(repoA, "origin")
+	require.NoError(t, err)
+	_, err = Merge(repoA, "origin")
+	require.NoError(t, err)
+
+	blA, err = Read(repoA)
+	require.NoError(t, err)
+	require.False(t, blA.IsBlocked(spammer1.Id()))
+
+	// the pushed entries are checked the same way
+	require.Error(t, CheckPushed(repoA, identity.NewSimpleResolver(repoA), blB.LastCommit()))
+	require.NoError(t, CheckPushed(repoB, identity.NewRemoteResolver(repoB, "origin"), merged))
+}
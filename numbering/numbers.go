@@ -0,0 +1,301 @@
+// Package numbering contains the sequential numbers of the bugs, an optional
+// human-friendly alias of their id shared between the repositories, so that a
+// bug can be referred to as #123 in a conversation.
+package numbering
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+const numbersRefPattern = "refs/numbers/"
+const numbersRemoteRefPattern = "refs/remotes/%s/numbers/"
+
+const bugNumbersRef = numbersRefPattern + "bugs"
+const bugNumbersRemoteRefPattern = numbersRemoteRefPattern + "bugs"
+
+const numbersEntryName = "numbers"
+
+const formatVersion = 1
+
+// Prefix is the prefix of a number in a reference to a bug, as #123
+const Prefix = "#"
+
+// Entry is the number allocated to a bug
+type Entry struct {
+	Number int       `json:"number"`
+	Id     entity.Id `json:"id"`
+}
+
+// Validate check if the Entry data is valid
+func (e Entry) Validate() error {
+	if e.Number <= 0 {
+		return fmt.Errorf("invalid number %d", e.Number)
+	}
+
+	if err := e.Id.Validate(); err != nil {
+		return errors.Wrap(err, "invalid bug id")
+	}
+
+	return nil
+}
+
+// Numbers is the mapping between the sequential numbers and the ids of the
+// bugs. It is stored in git as a chain of commits, each holding the full
+// mapping, and propagate with the bugs. The hashes remain the canonical ids:
+// the numbers are allocated when pushing, so that a number is never reused
+// once published.
+type Numbers struct {
+	byNumber   map[int]entity.Id
+	byId       map[entity.Id]int
+	lastCommit git.Hash
+}
+
+func newNumbers() *Numbers {
+	return &Numbers{
+		byNumber: make(map[int]entity.Id),
+		byId:     make(map[entity.Id]int),
+	}
+}
+
+// Read load the local numbers. An empty mapping is returned if none exist.
+func Read(repo repository.Repo) (*Numbers, error) {
+	return read(repo, bugNumbersRef)
+}
+
+// ReadRemote load the numbers of a remote, as of the last fetch
+func ReadRemote(repo repository.Repo, remote string) (*Numbers, error) {
+	return read(repo, fmt.Sprintf(bugNumbersRemoteRefPattern, remote))
+}
+
+func read(repo repository.Repo, ref string) (*Numbers, error) {
+	n := newNumbers()
+
+	exist, err := repo.RefExist(ref)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return n, nil
+	}
+
+	hashes, err := repo.ListCommits(ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) == 0 {
+		return n, nil
+	}
+
+	n.lastCommit = hashes[len(hashes)-1]
+
+	entries, err := readEntries(repo, n.lastCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if _, ok := n.byNumber[e.Number]; ok {
+			return nil, fmt.Errorf("number %d is allocated twice", e.Number)
+		}
+		if _, ok := n.byId[e.Id]; ok {
+			return nil, fmt.Errorf("bug %s has two numbers", e.Id.Human())
+		}
+		n.byNumber[e.Number] = e.Id
+		n.byId[e.Id] = e.Number
+	}
+
+	return n, nil
+}
+
+func readEntries(repo repository.Repo, commit git.Hash) ([]Entry, error) {
+	treeHash, err := repo.GetTreeHash(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	treeEntries, err := repo.ListEntries(treeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, treeEntry := range treeEntries {
+		if treeEntry.Name != numbersEntryName {
+			continue
+		}
+
+		data, err := repo.ReadData(treeEntry.Hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read git blob data")
+		}
+
+		aux := struct {
+			Version uint    `json:"version"`
+			Entries []Entry `json:"entries"`
+		}{}
+
+		err = json.Unmarshal(data, &aux)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode the numbers")
+		}
+
+		if aux.Version != formatVersion {
+			return nil, fmt.Errorf("unknown numbers format version %v", aux.Version)
+		}
+
+		for _, e := range aux.Entries {
+			if err := e.Validate(); err != nil {
+				return nil, errors.Wrap(err, "invalid numbers entry")
+			}
+		}
+
+		return aux.Entries, nil
+	}
+
+	return nil, fmt.Errorf("no numbers entry in the git tree")
+}
+
+// Parse read a number given as "#123". It returns false if the reference is
+// not a number, as an id prefix.
+func Parse(ref string) (int, bool) {
+	if !strings.HasPrefix(ref, Prefix) {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(ref, Prefix))
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// Format return the reference of a number, as "#123"
+func Format(number int) string {
+	return Prefix + strconv.Itoa(number)
+}
+
+// LastCommit return the hash of the git commit holding the current state, if
+// any. It change each time numbers are allocated.
+func (n *Numbers) LastCommit() git.Hash {
+	return n.lastCommit
+}
+
+// Len return the count of allocated numbers
+func (n *Numbers) Len() int {
+	return len(n.byNumber)
+}
+
+// Number return the number of a bug, if allocated
+func (n *Numbers) Number(id entity.Id) (int, bool) {
+	number, ok := n.byId[id]
+	return number, ok
+}
+
+// Id return the bug of a number, if allocated
+func (n *Numbers) Id(number int) (entity.Id, bool) {
+	id, ok := n.byNumber[number]
+	return id, ok
+}
+
+// Entries return all the allocated numbers, sorted
+func (n *Numbers) Entries() []Entry {
+	result := make([]Entry, 0, len(n.byNumber))
+	for number, id := range n.byNumber {
+		result = append(result, Entry{Number: number, Id: id})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Number < result[j].Number
+	})
+
+	return result
+}
+
+// Allocate give the next numbers to the bugs without one, in the given order,
+// and return the count of allocated numbers
+func (n *Numbers) Allocate(ids []entity.Id) int {
+	next := n.next()
+	count := 0
+
+	for _, id := range ids {
+		if _, ok := n.byId[id]; ok {
+			continue
+		}
+		n.byNumber[next] = id
+		n.byId[id] = next
+		next++
+		count++
+	}
+
+	return count
+}
+
+func (n *Numbers) next() int {
+	max := 0
+	for number := range n.byNumber {
+		if number > max {
+			max = number
+		}
+	}
+	return max + 1
+}
+
+// Commit write the current state of the numbers in git, on top of the previous
+// one
+func (n *Numbers) Commit(repo repository.Repo) error {
+	return n.commit(repo, n.lastCommit)
+}
+
+func (n *Numbers) commit(repo repository.Repo, parent git.Hash) error {
+	data, err := json.Marshal(struct {
+		Version uint    `json:"version"`
+		Entries []Entry `json:"entries"`
+	}{
+		Version: formatVersion,
+		Entries: n.Entries(),
+	})
+	if err != nil {
+		return err
+	}
+
+	blobHash, err := repo.StoreData(data)
+	if err != nil {
+		return err
+	}
+
+	treeHash, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blobHash, Name: numbersEntryName},
+	})
+	if err != nil {
+		return err
+	}
+
+	var commitHash git.Hash
+	if parent != "" {
+		commitHash, err = repo.StoreCommitWithParent(treeHash, parent)
+	} else {
+		commitHash, err = repo.StoreCommit(treeHash)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = repo.UpdateRef(bugNumbersRef, commitHash)
+	if err != nil {
+		return err
+	}
+
+	n.lastCommit = commitHash
+
+	return nil
+}
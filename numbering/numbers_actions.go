@@ -0,0 +1,95 @@
+package numbering
+
+import (
+	"fmt"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+// Fetch retrieve the numbers of a remote
+// This does not change the local numbers
+func Fetch(repo repository.Repo, remote string) (string, error) {
+	remoteRefSpec := fmt.Sprintf(numbersRemoteRefPattern, remote)
+	fetchRefSpec := fmt.Sprintf("%s*:%s*", numbersRefPattern, remoteRefSpec)
+
+	return repo.FetchRefs(remote, fetchRefSpec)
+}
+
+// Push update the numbers of a remote with the local ones
+func Push(repo repository.Repo, remote string) (string, error) {
+	return repo.PushRefs(remote, numbersRefPattern+"*")
+}
+
+// Merge merge the numbers of a remote into the local ones, and return true if
+// the local numbers changed.
+//
+//   - if the local numbers are behind, they are updated to match (fast-forward)
+//   - if the remote numbers are behind, nothing is changed
+//   - if both have changed, the numbers of the remote win, as they have been
+//     published first: a bug numbered on both sides keeps the number of the
+//     remote, and a local number already taken by the remote is allocated again
+//     after the ones of the remote. The result is committed on top of the
+//     remote, so that it can be pushed.
+func Merge(repo repository.Repo, remote string) (bool, error) {
+	remoteRef := fmt.Sprintf(bugNumbersRemoteRefPattern, remote)
+
+	remoteExist, err := repo.RefExist(remoteRef)
+	if err != nil || !remoteExist {
+		return false, err
+	}
+
+	localExist, err := repo.RefExist(bugNumbersRef)
+	if err != nil {
+		return false, err
+	}
+
+	if !localExist {
+		return true, repo.CopyRef(remoteRef, bugNumbersRef)
+	}
+
+	local, err := Read(repo)
+	if err != nil {
+		return false, err
+	}
+
+	other, err := ReadRemote(repo, remote)
+	if err != nil {
+		return false, err
+	}
+
+	if local.lastCommit == other.lastCommit {
+		return false, nil
+	}
+
+	ancestor, err := repo.FindCommonAncestor(local.lastCommit, other.lastCommit)
+	if err != nil {
+		return false, err
+	}
+
+	// the remote is behind
+	if ancestor == other.lastCommit {
+		return false, nil
+	}
+
+	// fast-forward
+	if ancestor == local.lastCommit {
+		return true, repo.UpdateRef(bugNumbersRef, other.lastCommit)
+	}
+
+	var conflicting []entity.Id
+	for _, e := range local.Entries() {
+		if _, ok := other.byId[e.Id]; ok {
+			continue
+		}
+		if _, ok := other.byNumber[e.Number]; ok {
+			conflicting = append(conflicting, e.Id)
+			continue
+		}
+		other.byNumber[e.Number] = e.Id
+		other.byId[e.Id] = e.Number
+	}
+	other.Allocate(conflicting)
+
+	return true, other.commit(repo, other.lastCommit)
+}
@@ -0,0 +1,126 @@
+package numbering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+const (
+	id1 = entity.Id("1111111111111111111111111111111111111111111111111111111111111111")
+	id2 = entity.Id("2222222222222222222222222222222222222222222222222222222222222222")
+	id3 = entity.Id("3333333333333333333333333333333333333333333333333333333333333333")
+	id4 = entity.Id("4444444444444444444444444444444444444444444444444444444444444444")
+)
+
+func TestParse(t *testing.T) {
+	n, ok := Parse("#12")
+	require.True(t, ok)
+	require.Equal(t, 12, n)
+	require.Equal(t, "#12", Format(12))
+
+	for _, ref := range []string{"12", "#", "#0", "#-1", "#abc", "abc"} {
+		_, ok := Parse(ref)
+		require.False(t, ok, ref)
+	}
+}
+
+func TestNumbersCommitRead(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	n, err := Read(repo)
+	require.NoError(t, err)
+	require.Equal(t, 0, n.Len())
+	require.Empty(t, n.LastCommit())
+
+	require.Equal(t, 2, n.Allocate([]entity.Id{id1, id2}))
+	require.Equal(t, 1, n.Allocate([]entity.Id{id2, id3}))
+	require.NoError(t, n.Commit(repo))
+
+	n, err = Read(repo)
+	require.NoError(t, err)
+	require.Equal(t, 3, n.Len())
+
+	number, ok := n.Number(id3)
+	require.True(t, ok)
+	require.Equal(t, 3, number)
+
+	id, ok := n.Id(1)
+	require.True(t, ok)
+	require.Equal(t, id1, id)
+
+	_, ok = n.Id(4)
+	require.False(t, ok)
+}
+
+func TestNumbersMerge(t *testing.T) {
+	repoA, repoB, remote := repository.SetupReposAndRemote(t)
+	defer repository.CleanupTestRepos(t, repoA, repoB, remote)
+
+	nA, err := Read(repoA)
+	require.NoError(t, err)
+	nA.Allocate([]entity.Id{id1})
+	require.NoError(t, nA.Commit(repoA))
+
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	// B get the numbers of A (fast-forward)
+	_, err = Fetch(repoB, "origin")
+	require.NoError(t, err)
+	updated, err := Merge(repoB, "origin")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	// both allocate the number 2 to different bugs
+	nA, err = Read(repoA)
+	require.NoError(t, err)
+	nA.Allocate([]entity.Id{id2})
+	require.NoError(t, nA.Commit(repoA))
+	_, err = Push(repoA, "origin")
+	require.NoError(t, err)
+
+	nB, err := Read(repoB)
+	require.NoError(t, err)
+	require.Equal(t, 1, nB.Len())
+	nB.Allocate([]entity.Id{id3, id4})
+	require.NoError(t, nB.Commit(repoB))
+
+	_, err = Fetch(repoB, "origin")
+	require.NoError(t, err)
+	updated, err = Merge(repoB, "origin")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	// the published numbers win, the local ones are allocated again
+	nB, err = Read(repoB)
+	require.NoError(t, err)
+	require.Equal(t, []Entry{
+		{Number: 1, Id: id1},
+		{Number: 2, Id: id2},
+		{Number: 3, Id: id4},
+		{Number: 4, Id: id3},
+	}, nB.Entries())
+
+	// the result can be pushed, and A fast-forward
+	_, err = Push(repoB, "origin")
+	require.NoError(t, err)
+	_, err = Fetch(repoA, "origin")
+	require.NoError(t, err)
+	updated, err = Merge(repoA, "origin")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	nA, err = Read(repoA)
+	require.NoError(t, err)
+	require.Equal(t, nB.Entries(), nA.Entries())
+
+	// nothing to merge anymore
+	updated, err = Merge(repoA, "origin")
+	require.NoError(t, err)
+	require.False(t, updated)
+}
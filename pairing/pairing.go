@@ -0,0 +1,145 @@
+// Package pairing transfers an identity to another machine over the network,
+// so that the same identity can be used on several devices.
+//
+// The machine holding the identity offers it with a short-lived pairing code,
+// holding its address and a random secret. The other machine joins with the
+// code. The secret is never sent: the identity is encrypted with a key derived
+// from it, and another key derived from it authenticates the peer.
+package pairing
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+const formatVersion = 1
+
+// the length of the secret, in bytes
+const secretLength = 10
+
+// the secret is given in groups of characters, for readability
+const groupLength = 4
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Payload is what is transferred to the joining machine
+type Payload struct {
+	// Additional field to version the data
+	FormatVersion uint `json:"version"`
+
+	// the identity and its full history
+	Bundle *identity.Bundle `json:"bundle"`
+	// the identity to adopt, in the bundle
+	UserId entity.Id `json:"user"`
+
+	// the reference to the key to sign the commits with, as in the
+	// "user.signingkey" git config. The key itself has to be installed
+	// separately.
+	SigningKey string `json:"signing_key,omitempty"`
+}
+
+// Code is a pairing code, given to the joining machine
+type Code struct {
+	// the address where the identity is offered, as host:port
+	Address string
+	secret  []byte
+}
+
+// NewCode generate a new pairing code, with a random secret, for the given
+// address
+func NewCode(address string) (Code, error) {
+	secret := make([]byte, secretLength)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return Code{}, err
+	}
+
+	return Code{Address: address, secret: secret}, nil
+}
+
+// ParseCode read a pairing code, as given by Code.String
+func ParseCode(s string) (Code, error) {
+	i := strings.LastIndex(s, "/")
+	if i <= 0 {
+		return Code{}, fmt.Errorf("invalid pairing code, expected <host>:<port>/<secret>")
+	}
+
+	address := s[:i]
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return Code{}, fmt.Errorf("invalid address in the pairing code: %v", err)
+	}
+
+	raw := strings.ToUpper(strings.Replace(s[i+1:], "-", "", -1))
+	secret, err := encoding.DecodeString(raw)
+	if err != nil || len(secret) != secretLength {
+		return Code{}, fmt.Errorf("invalid secret in the pairing code")
+	}
+
+	return Code{Address: address, secret: secret}, nil
+}
+
+// String return the code to give to the joining machine, as
+// <host>:<port>/<secret>
+func (c Code) String() string {
+	raw := strings.ToLower(encoding.EncodeToString(c.secret))
+
+	var groups []string
+	for len(raw) > groupLength {
+		groups = append(groups, raw[:groupLength])
+		raw = raw[groupLength:]
+	}
+	groups = append(groups, raw)
+
+	return c.Address + "/" + strings.Join(groups, "-")
+}
+
+// keys derive from the secret the key encrypting the payload, and the token
+// authenticating the joining machine
+func (c Code) keys() (key *[32]byte, token []byte, err error) {
+	derived, err := scrypt.Key(c.secret, []byte("git-bug pairing"), 1<<15, 8, 1, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key = new([32]byte)
+	copy(key[:], derived[:32])
+
+	return key, derived[32:], nil
+}
+
+// seal encrypt the data with the key, prefixed by the random nonce
+func seal(key *[32]byte, data []byte) ([]byte, error) {
+	var nonce [24]byte
+	_, err := rand.Read(nonce[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return secretbox.Seal(nonce[:], data, &nonce, key), nil
+}
+
+// open decrypt data encrypted by seal
+func open(key *[32]byte, sealed []byte) ([]byte, error) {
+	if len(sealed) < 24 {
+		return nil, fmt.Errorf("invalid encrypted data")
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], sealed[:24])
+
+	data, ok := secretbox.Open(nil, sealed[24:], &nonce, key)
+	if !ok {
+		return nil, fmt.Errorf("the data can't be decrypted, check the pairing code")
+	}
+
+	return data, nil
+}
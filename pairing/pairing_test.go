@@ -0,0 +1,89 @@
+package pairing
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/identity"
+)
+
+func TestCode(t *testing.T) {
+	code, err := NewCode("192.168.1.10:4567")
+	require.NoError(t, err)
+
+	s := code.String()
+	require.Regexp(t, `^192\.168\.1\.10:4567/[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`, s)
+
+	parsed, err := ParseCode(s)
+	require.NoError(t, err)
+	require.Equal(t, code, parsed)
+
+	// the secret can be typed in upper case, without the dashes
+	parsed, err = ParseCode("192.168.1.10:4567/" + "ABCDEFGHIJKLMNOP")
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.10:4567/abcd-efgh-ijkl-mnop", parsed.String())
+
+	for _, invalid := range []string{"", "abcd-efgh", "192.168.1.10/abcd-efgh-ijkl-mnop", "192.168.1.10:4567/abcd", "192.168.1.10:4567/abcd-efgh-ijkl-mno1"} {
+		_, err := ParseCode(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestOfferJoin(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	code, err := NewCode(listener.Addr().String())
+	require.NoError(t, err)
+
+	payload := &Payload{
+		Bundle:     &identity.Bundle{FormatVersion: 1},
+		UserId:     entity.Id("1111111111111111111111111111111111111111111111111111111111111111"),
+		SigningKey: "ABCD1234",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	offered := make(chan error, 1)
+	go func() {
+		_, err := Offer(ctx, listener, code, payload)
+		offered <- err
+	}()
+
+	// a wrong code is refused, without ending the offer
+	wrong, err := NewCode(code.Address)
+	require.NoError(t, err)
+	_, err = Join(ctx, wrong)
+	require.Error(t, err)
+
+	received, err := Join(ctx, code)
+	require.NoError(t, err)
+	require.Equal(t, payload.UserId, received.UserId)
+	require.Equal(t, "ABCD1234", received.SigningKey)
+
+	require.NoError(t, <-offered)
+
+	// the offer is over
+	_, err = Join(ctx, code)
+	require.Error(t, err)
+}
+
+func TestOfferExpire(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	code, err := NewCode(listener.Addr().String())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = Offer(ctx, listener, code, &Payload{Bundle: &identity.Bundle{}})
+	require.Equal(t, ErrExpired, err)
+}
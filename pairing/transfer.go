@@ -0,0 +1,152 @@
+package pairing
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const pairingPath = "/pair"
+
+// the header holding the token authenticating the joining machine
+const tokenHeader = "X-Git-Bug-Pairing"
+
+// the maximum size of an encrypted payload
+const maxPayloadSize = 10 * 1000 * 1000
+
+// ErrExpired is returned when no machine joined before the expiration of the
+// pairing code
+var ErrExpired = errors.New("the pairing code expired")
+
+// Offer serve the payload on the listener to the first machine joining with the
+// code, until the context is done. It return the address of the machine which
+// joined.
+func Offer(ctx context.Context, listener net.Listener, code Code, payload *Payload) (string, error) {
+	key, token, err := code.keys()
+	if err != nil {
+		return "", err
+	}
+
+	payload.FormatVersion = formatVersion
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := seal(key, data)
+	if err != nil {
+		return "", err
+	}
+
+	expected := []byte(hex.EncodeToString(token))
+
+	var once sync.Once
+	joined := make(chan string, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(pairingPath, func(rw http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(tokenHeader)), expected) != 1 {
+			http.Error(rw, "invalid pairing code", http.StatusForbidden)
+			return
+		}
+
+		served := false
+		once.Do(func() {
+			rw.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = rw.Write(sealed)
+			served = true
+			joined <- r.RemoteAddr
+		})
+
+		if !served {
+			http.Error(rw, "the identity has already been transferred", http.StatusGone)
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	select {
+	case peer := <-joined:
+		// let the response complete
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+		return peer, nil
+
+	case err := <-serveErr:
+		return "", err
+
+	case <-ctx.Done():
+		_ = srv.Close()
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", ErrExpired
+		}
+		return "", ctx.Err()
+	}
+}
+
+// Join retrieve the payload offered with the code
+func Join(ctx context.Context, code Code) (*Payload, error) {
+	key, token, err := code.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+code.Address+pairingPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(tokenHeader, hex.EncodeToString(token))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't reach the machine offering the identity")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPayloadSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pairing refused: %s", strings.TrimSpace(string(body)))
+	}
+
+	data, err := open(key, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload Payload
+	err = json.Unmarshal(data, &payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid pairing data")
+	}
+
+	if payload.FormatVersion != formatVersion {
+		return nil, fmt.Errorf("unknown pairing format version %v", payload.FormatVersion)
+	}
+
+	if payload.Bundle == nil {
+		return nil, fmt.Errorf("no identity in the pairing data")
+	}
+
+	return &payload, nil
+}
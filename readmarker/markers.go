@@ -0,0 +1,171 @@
+// Package readmarker contains the read markers of a user, telling which
+// operations of the bugs the user has seen, and the related functions.
+package readmarker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+// the markers are local to the repository: this ref is never pushed nor
+// fetched
+const readMarkersRefPattern = "refs/read-markers/"
+
+const markersEntryName = "markers"
+
+const formatVersion = 1
+
+// Markers hold, for each bug the user has read, the edit time of the bug at
+// the time. The operations added after that are unread.
+//
+// They are stored in git under a ref per identity, as a single commit holding
+// the full state.
+type Markers struct {
+	identityId entity.Id
+	times      map[entity.Id]lamport.Time
+}
+
+// Read load the read markers of an identity. Empty markers are returned if
+// none exist.
+func Read(repo repository.Repo, identityId entity.Id) (*Markers, error) {
+	m := &Markers{
+		identityId: identityId,
+		times:      make(map[entity.Id]lamport.Time),
+	}
+
+	ref := readMarkersRefPattern + identityId.String()
+
+	exist, err := repo.RefExist(ref)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return m, nil
+	}
+
+	hashes, err := repo.ListCommits(ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) == 0 {
+		return m, nil
+	}
+
+	treeHash, err := repo.GetTreeHash(hashes[len(hashes)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	treeEntries, err := repo.ListEntries(treeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, treeEntry := range treeEntries {
+		if treeEntry.Name != markersEntryName {
+			continue
+		}
+
+		data, err := repo.ReadData(treeEntry.Hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read git blob data")
+		}
+
+		aux := struct {
+			Version uint                       `json:"version"`
+			Markers map[entity.Id]lamport.Time `json:"markers"`
+		}{}
+
+		err = json.Unmarshal(data, &aux)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode the read markers")
+		}
+
+		if aux.Version != formatVersion {
+			return nil, fmt.Errorf("unknown read markers format version %v", aux.Version)
+		}
+
+		for id, t := range aux.Markers {
+			if err := id.Validate(); err != nil {
+				return nil, errors.Wrap(err, "invalid read marker")
+			}
+			m.times[id] = t
+		}
+
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("no read markers entry in the git tree")
+}
+
+// IdentityId return the id of the identity the markers belong to
+func (m *Markers) IdentityId() entity.Id {
+	return m.identityId
+}
+
+// IsUnread tell if a bug has been edited since the identity read it, or
+// has never been read
+func (m *Markers) IsUnread(bugId entity.Id, editTime lamport.Time) bool {
+	t, ok := m.times[bugId]
+	return !ok || t < editTime
+}
+
+// MarkRead record that the identity has seen the operations of a bug up to
+// the given edit time, and return true if the markers changed
+func (m *Markers) MarkRead(bugId entity.Id, editTime lamport.Time) bool {
+	if t, ok := m.times[bugId]; ok && t >= editTime {
+		return false
+	}
+	m.times[bugId] = editTime
+	return true
+}
+
+// MarkUnread forget that the identity has read a bug, and return true if the
+// markers changed
+func (m *Markers) MarkUnread(bugId entity.Id) bool {
+	if _, ok := m.times[bugId]; !ok {
+		return false
+	}
+	delete(m.times, bugId)
+	return true
+}
+
+// Commit write the markers in git
+func (m *Markers) Commit(repo repository.Repo) error {
+	data, err := json.Marshal(struct {
+		Version uint                       `json:"version"`
+		Markers map[entity.Id]lamport.Time `json:"markers"`
+	}{
+		Version: formatVersion,
+		Markers: m.times,
+	})
+	if err != nil {
+		return err
+	}
+
+	blobHash, err := repo.StoreData(data)
+	if err != nil {
+		return err
+	}
+
+	treeHash, err := repo.StoreTree([]repository.TreeEntry{
+		{ObjectType: repository.Blob, Hash: blobHash, Name: markersEntryName},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the history of the markers is of no use, a single commit is kept
+	commitHash, err := repo.StoreCommit(treeHash)
+	if err != nil {
+		return err
+	}
+
+	return repo.UpdateRef(readMarkersRefPattern+m.identityId.String(), commitHash)
+}
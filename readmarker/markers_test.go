@@ -0,0 +1,49 @@
+package readmarker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestMarkersCommitRead(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	rene := entity.Id("d9b1c4d848b7d4ff934d1e7e22b6835ea8d4e1d6e8a5e5aeb4b08d7f83cdd6d8")
+	isaac := entity.Id("2d1d9e0d438c73f8b9c8f4a9e0c9a31c0a7ed1b1e4d0bd3a8c5b6c97d2f1a8e3")
+	bug1 := entity.Id("a1f9e5c3e2fa0d4b3c7d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f9a0b1c2d3")
+	bug2 := entity.Id("b2e8d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5")
+
+	m, err := Read(repo, rene)
+	require.NoError(t, err)
+	require.True(t, m.IsUnread(bug1, 1))
+
+	require.True(t, m.MarkRead(bug1, 3))
+	require.True(t, m.MarkRead(bug2, 5))
+	// a marker doesn't go back in time
+	require.False(t, m.MarkRead(bug2, 4))
+	require.NoError(t, m.Commit(repo))
+
+	m, err = Read(repo, rene)
+	require.NoError(t, err)
+	require.False(t, m.IsUnread(bug1, 3))
+	require.True(t, m.IsUnread(bug1, 4))
+	require.False(t, m.IsUnread(bug2, 5))
+
+	require.True(t, m.MarkUnread(bug1))
+	require.False(t, m.MarkUnread(bug1))
+	require.NoError(t, m.Commit(repo))
+
+	m, err = Read(repo, rene)
+	require.NoError(t, err)
+	require.True(t, m.IsUnread(bug1, 3))
+
+	// the markers are per identity
+	other, err := Read(repo, isaac)
+	require.NoError(t, err)
+	require.True(t, other.IsUnread(bug2, 5))
+}
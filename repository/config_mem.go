@@ -3,12 +3,15 @@ package repository
 import (
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 var _ Config = &MemConfig{}
 
+// MemConfig is a Config held in memory, safe for concurrent use
 type MemConfig struct {
+	mu     sync.RWMutex
 	config map[string]string
 }
 
@@ -19,6 +22,9 @@ func NewMemConfig() *MemConfig {
 }
 
 func (mc *MemConfig) StoreString(key, value string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	mc.config[key] = value
 	return nil
 }
@@ -32,6 +38,9 @@ func (mc *MemConfig) StoreTimestamp(key string, value time.Time) error {
 }
 
 func (mc *MemConfig) ReadAll(keyPrefix string) (map[string]string, error) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
 	result := make(map[string]string)
 	for key, val := range mc.config {
 		if strings.HasPrefix(key, keyPrefix) {
@@ -42,6 +51,9 @@ func (mc *MemConfig) ReadAll(keyPrefix string) (map[string]string, error) {
 }
 
 func (mc *MemConfig) ReadString(key string) (string, error) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
 	// unlike git, the mock can only store one value for the same key
 	val, ok := mc.config[key]
 	if !ok {
@@ -52,10 +64,9 @@ func (mc *MemConfig) ReadString(key string) (string, error) {
 }
 
 func (mc *MemConfig) ReadBool(key string) (bool, error) {
-	// unlike git, the mock can only store one value for the same key
-	val, ok := mc.config[key]
-	if !ok {
-		return false, ErrNoConfigEntry
+	val, err := mc.ReadString(key)
+	if err != nil {
+		return false, err
 	}
 
 	return strconv.ParseBool(val)
@@ -77,6 +88,9 @@ func (mc *MemConfig) ReadTimestamp(key string) (time.Time, error) {
 
 // RmConfigs remove all key/value pair matching the key prefix
 func (mc *MemConfig) RemoveAll(keyPrefix string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	for key := range mc.config {
 		if strings.HasPrefix(key, keyPrefix) {
 			delete(mc.config, key)
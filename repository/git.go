@@ -5,8 +5,10 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -97,9 +99,13 @@ func NewGitRepo(path string, witnesser Witnesser) (*GitRepo, error) {
 	repo := &GitRepo{Path: path}
 
 	// Check the repo and retrieve the root path
-	stdout, err := repo.runGitCommand("rev-parse", "--git-dir")
+	// In a linked worktree, the git dir only hold what is specific to the
+	// worktree: the refs, the clocks and the cache live in the common dir,
+	// shared by all the worktrees. For a submodule, both are the git dir of
+	// the submodule, in the modules directory of the superproject.
+	stdout, err := repo.runGitCommand("rev-parse", "--git-common-dir")
 
-	// Now dir is fetched with "git rev-parse --git-dir". May be it can
+	// Now dir is fetched with "git rev-parse --git-common-dir". May be it can
 	// still return nothing in some cases. Then empty stdout check is
 	// kept.
 	if err != nil || stdout == "" {
@@ -107,6 +113,11 @@ func NewGitRepo(path string, witnesser Witnesser) (*GitRepo, error) {
 	}
 
 	// Fix the path to be sure we are at the root
+	// git answer relatively to the given path, which is fine as long as it is
+	// the working directory
+	if !filepath.IsAbs(stdout) && !isWorkingDir(path) {
+		stdout = filepath.Join(path, stdout)
+	}
 	repo.Path = stdout
 
 	err = repo.LoadClocks()
@@ -134,6 +145,48 @@ func NewGitRepo(path string, witnesser Witnesser) (*GitRepo, error) {
 	return repo, nil
 }
 
+// ListRefsAt list the refs matching a refspec of the git repository whose
+// root is at the given path, bare or not, without loading the repository as
+// NewGitRepo does. A path inside a repository gives ErrNotARepo.
+func ListRefsAt(path string, refspec string) ([]string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &GitRepo{Path: abs}
+
+	stdout, err := repo.runGitCommand("rev-parse", "--git-common-dir")
+	if err != nil || stdout == "" {
+		return nil, ErrNotARepo
+	}
+
+	if !filepath.IsAbs(stdout) {
+		stdout = filepath.Join(abs, stdout)
+	}
+	gitDir := filepath.Clean(stdout)
+
+	if gitDir != abs && filepath.Dir(gitDir) != abs {
+		return nil, ErrNotARepo
+	}
+
+	return repo.ListRefs(refspec)
+}
+
+func isWorkingDir(path string) bool {
+	wd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	return abs == wd
+}
+
 // InitGitRepo create a new empty git repo at the given path
 func InitGitRepo(path string) (*GitRepo, error) {
 	repo := &GitRepo{Path: path + "/.git"}
@@ -186,6 +239,12 @@ func (repo *GitRepo) GetCoreEditor() (string, error) {
 	return repo.runGitCommand("var", "GIT_EDITOR")
 }
 
+// GetCorePager returns the pager that the user has configured for git,
+// with GIT_PAGER, core.pager or PAGER
+func (repo *GitRepo) GetCorePager() (string, error) {
+	return repo.runGitCommand("var", "GIT_PAGER")
+}
+
 // GetRemotes returns the configured remotes repositories.
 func (repo *GitRepo) GetRemotes() (map[string]string, error) {
 	stdout, err := repo.runGitCommand("remote", "--verbose")
@@ -213,7 +272,16 @@ func (repo *GitRepo) GetRemotes() (map[string]string, error) {
 
 // FetchRefs fetch git refs from a remote
 func (repo *GitRepo) FetchRefs(remote, refSpec string) (string, error) {
-	stdout, err := repo.runGitCommand("fetch", remote, refSpec)
+	args := []string{"fetch", remote, refSpec}
+
+	// in a shallow clone, git would fetch the refs shallow as well, leaving
+	// out the first commits that give the ids of the entities
+	shallow, err := repo.runGitCommand("rev-parse", "--is-shallow-repository")
+	if err == nil && shallow == "true" {
+		args = append(args, "--depth=2147483647")
+	}
+
+	stdout, err := repo.runGitCommand(args...)
 
 	if err != nil {
 		return stdout, fmt.Errorf("failed to fetch from the remote '%s': %v", remote, err)
@@ -291,6 +359,32 @@ func (repo *GitRepo) StoreCommitWithParent(treeHash git.Hash, parent git.Hash) (
 	return git.Hash(stdout), nil
 }
 
+// ReadCommitData return the raw content of a Git commit
+func (repo *GitRepo) ReadCommitData(hash git.Hash) ([]byte, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	err := repo.runGitCommandWithIO(nil, &stdout, &stderr, "cat-file", "commit", string(hash))
+
+	if err != nil {
+		return nil, fmt.Errorf("unknown commit %s", hash)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// StoreCommitData will store a Git commit from its raw content
+func (repo *GitRepo) StoreCommitData(data []byte) (git.Hash, error) {
+	stdout, err := repo.runGitCommandWithStdin(bytes.NewReader(data),
+		"hash-object", "-t", "commit", "--stdin", "-w")
+
+	if err != nil {
+		return "", err
+	}
+
+	return git.Hash(stdout), nil
+}
+
 // UpdateRef will create or update a Git reference
 func (repo *GitRepo) UpdateRef(ref string, hash git.Hash) error {
 	_, err := repo.runGitCommand("update-ref", ref, string(hash))
@@ -298,6 +392,22 @@ func (repo *GitRepo) UpdateRef(ref string, hash git.Hash) error {
 	return err
 }
 
+// UpdateRefs will create or update multiple Git references at once
+func (repo *GitRepo) UpdateRefs(refs map[string]git.Hash) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	for ref, hash := range refs {
+		buffer.WriteString(fmt.Sprintf("update %s %s\n", ref, hash))
+	}
+
+	_, err := repo.runGitCommandWithStdin(&buffer, "update-ref", "--stdin")
+
+	return err
+}
+
 // ListRefs will return a list of Git ref matching the given refspec
 func (repo *GitRepo) ListRefs(refspec string) ([]string, error) {
 	stdout, err := repo.runGitCommand("for-each-ref", "--format=%(refname)", refspec)
@@ -315,6 +425,40 @@ func (repo *GitRepo) ListRefs(refspec string) ([]string, error) {
 	return split, nil
 }
 
+// RemoveRef will remove a Git reference
+func (repo *GitRepo) RemoveRef(ref string) error {
+	_, err := repo.runGitCommand("update-ref", "-d", ref)
+
+	return err
+}
+
+// ListRemoteRefs will return the list of Git ref of a remote matching the
+// given prefix, directly from the remote
+func (repo *GitRepo) ListRemoteRefs(remote string, refPrefix string) ([]string, error) {
+	stdout, err := repo.runGitCommand("ls-remote", "--refs", remote, refPrefix+"*")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the references of the remote '%s': %v", remote, err)
+	}
+
+	if stdout == "" {
+		return []string{}, nil
+	}
+
+	lines := strings.Split(stdout, "\n")
+	refs := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		elements := strings.Fields(line)
+		if len(elements) != 2 {
+			return nil, fmt.Errorf("git ls-remote: unexpected output format: %s", line)
+		}
+		refs = append(refs, elements[1])
+	}
+
+	return refs, nil
+}
+
 // RefExist will check if a reference exist in Git
 func (repo *GitRepo) RefExist(ref string) (bool, error) {
 	stdout, err := repo.runGitCommand("for-each-ref", ref)
@@ -385,6 +529,25 @@ func (repo *GitRepo) GetTreeHash(commit git.Hash) (git.Hash, error) {
 	return git.Hash(stdout), nil
 }
 
+// ReadCommitSignature return the GPG signature of a commit, with its validity
+func (repo *GitRepo) ReadCommitSignature(commit git.Hash) (CommitSignature, error) {
+	stdout, err := repo.runGitCommand("show", "-s", "--format=%G?%x00%GS%x00%GK", string(commit))
+	if err != nil {
+		return CommitSignature{}, err
+	}
+
+	fields := strings.SplitN(stdout, "\x00", 3)
+	for len(fields) < 3 {
+		fields = append(fields, "")
+	}
+
+	return CommitSignature{
+		Status: signatureStatusFromGit(fields[0]),
+		Signer: fields[1],
+		Key:    fields[2],
+	}, nil
+}
+
 // AddRemote add a new remote to the repository
 // Not in the interface because it's only used for testing
 func (repo *GitRepo) AddRemote(name string, url string) error {
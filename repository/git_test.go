@@ -2,9 +2,14 @@
 package repository
 
 import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfig(t *testing.T) {
@@ -65,3 +70,111 @@ func TestConfig(t *testing.T) {
 	err = repo.LocalConfig().RemoveAll("section.key")
 	assert.Error(t, err)
 }
+
+// runGit run a git command in the given directory, for the setups that the
+// repository package doesn't create
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func noopWitnesser(repo ClockedRepo) error {
+	return nil
+}
+
+func TestWorktree(t *testing.T) {
+	repo := CreateTestRepo(false)
+	defer CleanupTestRepos(t, repo)
+
+	root := filepath.Dir(repo.GetPath())
+	runGit(t, root, "commit", "--allow-empty", "-m", "initial")
+
+	worktree, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(worktree)
+	runGit(t, root, "worktree", "add", worktree)
+
+	wtRepo, err := NewGitRepo(worktree, noopWitnesser)
+	require.NoError(t, err)
+
+	// the worktree share the refs, the clocks and the cache of the main repo
+	expected, err := filepath.EvalSymlinks(repo.GetPath())
+	require.NoError(t, err)
+	actual, err := filepath.EvalSymlinks(wtRepo.GetPath())
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	hash, err := wtRepo.StoreData([]byte("data"))
+	require.NoError(t, err)
+	tree, err := wtRepo.StoreTree([]TreeEntry{{ObjectType: Blob, Hash: hash, Name: "file"}})
+	require.NoError(t, err)
+	commit, err := wtRepo.StoreCommit(tree)
+	require.NoError(t, err)
+	err = wtRepo.UpdateRef("refs/bugs/test", commit)
+	require.NoError(t, err)
+
+	exist, err := repo.RefExist("refs/bugs/test")
+	require.NoError(t, err)
+	assert.True(t, exist)
+}
+
+func TestSubmodule(t *testing.T) {
+	sub := CreateTestRepo(false)
+	super := CreateTestRepo(false)
+	defer CleanupTestRepos(t, sub, super)
+
+	subRoot := filepath.Dir(sub.GetPath())
+	superRoot := filepath.Dir(super.GetPath())
+	runGit(t, subRoot, "commit", "--allow-empty", "-m", "initial")
+	runGit(t, superRoot, "-c", "protocol.file.allow=always", "submodule", "add", subRoot, "sub")
+
+	subRepo, err := NewGitRepo(filepath.Join(superRoot, "sub"), noopWitnesser)
+	require.NoError(t, err)
+
+	// the git dir of the submodule is in the superproject
+	expected, err := filepath.EvalSymlinks(filepath.Join(super.GetPath(), "modules", "sub"))
+	require.NoError(t, err)
+	actual, err := filepath.EvalSymlinks(subRepo.GetPath())
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	_, err = os.Stat(filepath.Join(subRepo.GetPath(), createClockFile))
+	assert.NoError(t, err)
+}
+
+func TestListRefsAt(t *testing.T) {
+	repo := CreateTestRepo(false)
+	bare := CreateTestRepo(true)
+	defer CleanupTestRepos(t, repo, bare)
+
+	root := filepath.Dir(repo.GetPath())
+
+	refs, err := ListRefsAt(root, "refs/bugs/")
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+
+	hash, err := repo.StoreData([]byte("data"))
+	require.NoError(t, err)
+	tree, err := repo.StoreTree([]TreeEntry{{ObjectType: Blob, Hash: hash, Name: "file"}})
+	require.NoError(t, err)
+	commit, err := repo.StoreCommit(tree)
+	require.NoError(t, err)
+	err = repo.UpdateRef("refs/bugs/test", commit)
+	require.NoError(t, err)
+
+	refs, err = ListRefsAt(root, "refs/bugs/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/bugs/test"}, refs)
+
+	refs, err = ListRefsAt(bare.GetPath(), "refs/bugs/")
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+
+	// a directory inside a repository is not a repository on its own
+	sub := filepath.Join(root, "sub")
+	require.NoError(t, os.Mkdir(sub, 0755))
+	_, err = ListRefsAt(sub, "refs/bugs/")
+	assert.Equal(t, ErrNotARepo, err)
+}
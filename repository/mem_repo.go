@@ -0,0 +1,693 @@
+package repository
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/MichaelMure/git-bug/util/git"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+var _ ClockedRepo = &MemRepo{}
+
+// MemRepo is a repository entirely held in memory, with the same semantic
+// as a git repository for the objects, the references and the clocks. The
+// remotes are other MemRepo.
+//
+// MemRepo is safe for concurrent use.
+type MemRepo struct {
+	path         string
+	config       *MemConfig
+	globalConfig *MemConfig
+
+	mu      sync.RWMutex
+	blobs   map[git.Hash][]byte
+	trees   map[git.Hash]string
+	commits map[git.Hash]commit
+	refs    map[string]git.Hash
+	remotes map[string]memRemote
+
+	createClock lamport.Clock
+	editClock   lamport.Clock
+}
+
+type commit struct {
+	treeHash git.Hash
+	parent   git.Hash
+}
+
+type memRemote struct {
+	url  string
+	repo *MemRepo
+}
+
+// NewMemRepo create an empty in-memory repository
+func NewMemRepo() *MemRepo {
+	return &MemRepo{
+		path:         "~/memRepo/",
+		config:       NewMemConfig(),
+		globalConfig: NewMemConfig(),
+		blobs:        make(map[git.Hash][]byte),
+		trees:        make(map[git.Hash]string),
+		commits:      make(map[git.Hash]commit),
+		refs:         make(map[string]git.Hash),
+		remotes:      make(map[string]memRemote),
+		createClock:  lamport.NewClock(),
+		editClock:    lamport.NewClock(),
+	}
+}
+
+// AddRemote add a remote that can't be reached, as the remotes of a git
+// repository that are only listed
+func (r *MemRepo) AddRemote(name string, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remotes[name] = memRemote{url: url}
+	return nil
+}
+
+// AddMemRemote add another in-memory repository as a remote, to fetch from
+// and push to
+func (r *MemRepo) AddMemRemote(name string, remote *MemRepo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remotes[name] = memRemote{url: "mem://" + name, repo: remote}
+}
+
+// LocalConfig give access to the repository scoped configuration
+func (r *MemRepo) LocalConfig() Config {
+	return r.config
+}
+
+// GlobalConfig give access to the git global configuration
+func (r *MemRepo) GlobalConfig() Config {
+	return r.globalConfig
+}
+
+// GetPath returns the path to the repo. As nothing is stored on disk, this
+// is a fake path.
+func (r *MemRepo) GetPath() string {
+	return r.path
+}
+
+// GetUserName returns the name the the user has used to configure git
+func (r *MemRepo) GetUserName() (string, error) {
+	return r.readConfigString("user.name")
+}
+
+// GetUserEmail returns the email address that the user has used to configure git.
+func (r *MemRepo) GetUserEmail() (string, error) {
+	return r.readConfigString("user.email")
+}
+
+// GetCoreEditor returns the name of the editor that the user has used to configure git.
+func (r *MemRepo) GetCoreEditor() (string, error) {
+	editor, err := r.readConfigString("core.editor")
+	if err == ErrNoConfigEntry {
+		return "vi", nil
+	}
+	return editor, err
+}
+
+// GetCorePager returns the pager that the user has configured for git.
+func (r *MemRepo) GetCorePager() (string, error) {
+	pager, err := r.readConfigString("core.pager")
+	if err == ErrNoConfigEntry {
+		return "less", nil
+	}
+	return pager, err
+}
+
+// readConfigString read a value from the local config, falling back to the
+// global config, as git does
+func (r *MemRepo) readConfigString(key string) (string, error) {
+	val, err := r.config.ReadString(key)
+	if err == ErrNoConfigEntry {
+		return r.globalConfig.ReadString(key)
+	}
+	return val, err
+}
+
+// GetRemotes returns the configured remotes repositories.
+func (r *MemRepo) GetRemotes() (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	remotes := make(map[string]string, len(r.remotes))
+	for name, remote := range r.remotes {
+		remotes[name] = remote.url
+	}
+
+	return remotes, nil
+}
+
+func (r *MemRepo) remote(name string) (*MemRepo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	remote, ok := r.remotes[name]
+	if !ok {
+		return nil, fmt.Errorf("'%s' does not appear to be a git repository", name)
+	}
+	if remote.repo == nil {
+		return nil, fmt.Errorf("could not read from the remote '%s'", name)
+	}
+
+	return remote.repo, nil
+}
+
+// FetchRefs fetch git refs from a remote
+func (r *MemRepo) FetchRefs(remote string, refSpec string) (string, error) {
+	remoteRepo, err := r.remote(remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch from the remote '%s': %v", remote, err)
+	}
+
+	out, err := transferRefs(remoteRepo, r, refSpec)
+	if err != nil {
+		return out, fmt.Errorf("failed to fetch from the remote '%s': %v", remote, err)
+	}
+
+	return out, nil
+}
+
+// PushRefs push git refs to a remote
+func (r *MemRepo) PushRefs(remote string, refSpec string) (string, error) {
+	remoteRepo, err := r.remote(remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to push to the remote '%s': %v", remote, err)
+	}
+
+	out, err := transferRefs(r, remoteRepo, refSpec)
+	if err != nil {
+		return out, fmt.Errorf("failed to push to the remote '%s': %v", remote, err)
+	}
+
+	return out, nil
+}
+
+// transferRefs copy the references matching a refspec, and the objects they
+// reach, from a repository to another. As with git, a reference is only
+// updated if the change is a fast-forward, unless the refspec start with "+".
+func transferRefs(from *MemRepo, to *MemRepo, refSpec string) (string, error) {
+	force := strings.HasPrefix(refSpec, "+")
+	refSpec = strings.TrimPrefix(refSpec, "+")
+
+	src, dst := refSpec, refSpec
+	if i := strings.Index(refSpec, ":"); i >= 0 {
+		src, dst = refSpec[:i], refSpec[i+1:]
+	}
+
+	from.mu.RLock()
+	updates := make(map[string]git.Hash)
+	if strings.HasSuffix(src, "*") {
+		srcPrefix := strings.TrimSuffix(src, "*")
+		dstPrefix := strings.TrimSuffix(dst, "*")
+		for ref, hash := range from.refs {
+			if strings.HasPrefix(ref, srcPrefix) {
+				updates[dstPrefix+strings.TrimPrefix(ref, srcPrefix)] = hash
+			}
+		}
+	} else if hash, ok := from.refs[src]; ok {
+		updates[dst] = hash
+	} else {
+		from.mu.RUnlock()
+		return "", fmt.Errorf("couldn't find remote ref %s", src)
+	}
+	from.mu.RUnlock()
+
+	var out strings.Builder
+	var rejected []string
+
+	for _, ref := range sortedKeys(updates) {
+		hash := updates[ref]
+
+		err := from.copyObjects(to, hash)
+		if err != nil {
+			return out.String(), err
+		}
+
+		to.mu.Lock()
+		old, exist := to.refs[ref]
+		switch {
+		case exist && old == hash:
+		case exist && !force && !to.isAncestor(old, hash):
+			rejected = append(rejected, ref)
+			fmt.Fprintf(&out, " ! [rejected] %s (non-fast-forward)\n", ref)
+		default:
+			to.refs[ref] = hash
+			fmt.Fprintf(&out, " * %s\n", ref)
+		}
+		to.mu.Unlock()
+	}
+
+	if len(rejected) > 0 {
+		return out.String(), fmt.Errorf("failed to update some refs: %s", strings.Join(rejected, ", "))
+	}
+
+	return out.String(), nil
+}
+
+// copyObjects copy a commit with its history, and the trees and blobs they
+// reach, into another repository
+func (r *MemRepo) copyObjects(to *MemRepo, hash git.Hash) error {
+	// collect the objects first, to never hold the locks of both repositories
+	objects := NewMemRepo()
+
+	r.mu.RLock()
+	for hash != "" {
+		c, ok := r.commits[hash]
+		if !ok {
+			r.mu.RUnlock()
+			return fmt.Errorf("missing commit %s", hash)
+		}
+
+		err := r.copyTree(objects, c.treeHash)
+		if err != nil {
+			r.mu.RUnlock()
+			return err
+		}
+
+		objects.commits[hash] = c
+		hash = c.parent
+	}
+	r.mu.RUnlock()
+
+	to.mu.Lock()
+	defer to.mu.Unlock()
+
+	for hash, blob := range objects.blobs {
+		to.blobs[hash] = blob
+	}
+	for hash, tree := range objects.trees {
+		to.trees[hash] = tree
+	}
+	for hash, c := range objects.commits {
+		to.commits[hash] = c
+	}
+
+	return nil
+}
+
+// copyTree copy a tree and its content into another repository
+// the lock of r must be held, objects must not be shared
+func (r *MemRepo) copyTree(objects *MemRepo, hash git.Hash) error {
+	data, ok := r.trees[hash]
+	if !ok {
+		return fmt.Errorf("missing tree %s", hash)
+	}
+
+	entries, err := readTreeEntries(data)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch entry.ObjectType {
+		case Blob:
+			blob, ok := r.blobs[entry.Hash]
+			if !ok {
+				return fmt.Errorf("missing blob %s", entry.Hash)
+			}
+			objects.blobs[entry.Hash] = blob
+		case Tree:
+			err := r.copyTree(objects, entry.Hash)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	objects.trees[hash] = data
+	return nil
+}
+
+// isAncestor tell if a commit is in the history of another
+// the lock must be held
+func (r *MemRepo) isAncestor(ancestor git.Hash, hash git.Hash) bool {
+	for hash != "" {
+		if hash == ancestor {
+			return true
+		}
+		hash = r.commits[hash].parent
+	}
+	return false
+}
+
+// StoreData will store arbitrary data and return the corresponding hash
+func (r *MemRepo) StoreData(data []byte) (git.Hash, error) {
+	rawHash := sha1.Sum(data)
+	hash := git.Hash(fmt.Sprintf("%x", rawHash))
+
+	r.mu.Lock()
+	r.blobs[hash] = data
+	r.mu.Unlock()
+
+	return hash, nil
+}
+
+// ReadData will attempt to read arbitrary data from the given hash
+func (r *MemRepo) ReadData(hash git.Hash) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, ok := r.blobs[hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash")
+	}
+
+	return data, nil
+}
+
+// StoreTree will store a mapping key-->Hash as a Git tree
+func (r *MemRepo) StoreTree(entries []TreeEntry) (git.Hash, error) {
+	buffer := prepareTreeEntries(entries)
+	rawHash := sha1.Sum(buffer.Bytes())
+	hash := git.Hash(fmt.Sprintf("%x", rawHash))
+
+	r.mu.Lock()
+	r.trees[hash] = buffer.String()
+	r.mu.Unlock()
+
+	return hash, nil
+}
+
+// StoreCommit will store a Git commit with the given Git tree
+func (r *MemRepo) StoreCommit(treeHash git.Hash) (git.Hash, error) {
+	return r.StoreCommitWithParent(treeHash, "")
+}
+
+// StoreCommitWithParent will store a Git commit with the given Git tree
+func (r *MemRepo) StoreCommitWithParent(treeHash git.Hash, parent git.Hash) (git.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.trees[treeHash]; !ok {
+		return "", fmt.Errorf("unknown tree %s", treeHash)
+	}
+	if _, ok := r.commits[parent]; parent != "" && !ok {
+		return "", fmt.Errorf("unknown parent commit %s", parent)
+	}
+
+	rawHash := sha1.Sum([]byte(treeHash + parent))
+	hash := git.Hash(fmt.Sprintf("%x", rawHash))
+	r.commits[hash] = commit{
+		treeHash: treeHash,
+		parent:   parent,
+	}
+
+	return hash, nil
+}
+
+// ReadCommitData return the content of a commit. The commits of an in-memory
+// repository only hold their tree and parent.
+func (r *MemRepo) ReadCommitData(hash git.Hash) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.commits[hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown commit %s", hash)
+	}
+
+	data := fmt.Sprintf("tree %s\n", c.treeHash)
+	if c.parent != "" {
+		data += fmt.Sprintf("parent %s\n", c.parent)
+	}
+
+	return []byte(data), nil
+}
+
+// StoreCommitData will store a commit from its content, as returned by
+// ReadCommitData
+func (r *MemRepo) StoreCommitData(data []byte) (git.Hash, error) {
+	var treeHash, parent git.Hash
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		switch {
+		case strings.HasPrefix(line, "tree "):
+			treeHash = git.Hash(strings.TrimPrefix(line, "tree "))
+		case strings.HasPrefix(line, "parent "):
+			parent = git.Hash(strings.TrimPrefix(line, "parent "))
+		default:
+			return "", fmt.Errorf("invalid commit data")
+		}
+	}
+
+	return r.StoreCommitWithParent(treeHash, parent)
+}
+
+// UpdateRef will create or update a Git reference
+func (r *MemRepo) UpdateRef(ref string, hash git.Hash) error {
+	return r.UpdateRefs(map[string]git.Hash{ref: hash})
+}
+
+// UpdateRefs will create or update multiple Git references at once
+func (r *MemRepo) UpdateRefs(refs map[string]git.Hash) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// all or nothing, as a git transaction
+	for ref, hash := range refs {
+		if _, ok := r.commits[hash]; !ok {
+			return fmt.Errorf("cannot update ref '%s': unknown commit %s", ref, hash)
+		}
+	}
+
+	for ref, hash := range refs {
+		r.refs[ref] = hash
+	}
+
+	return nil
+}
+
+// ListRefs will return a list of Git ref matching the given refspec
+func (r *MemRepo) ListRefs(refspec string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return matchingRefs(r.refs, refspec), nil
+}
+
+// matchingRefs return the references matching a prefix, sorted as git does
+func matchingRefs(refs map[string]git.Hash, prefix string) []string {
+	keys := []string{}
+
+	for k := range refs {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedKeys(refs map[string]git.Hash) []string {
+	return matchingRefs(refs, "")
+}
+
+// RemoveRef will remove a Git reference
+func (r *MemRepo) RemoveRef(ref string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.refs, ref)
+	return nil
+}
+
+// ListRemoteRefs will return the list of Git ref of a remote matching the
+// given prefix, directly from the remote
+func (r *MemRepo) ListRemoteRefs(remote string, refPrefix string) ([]string, error) {
+	remoteRepo, err := r.remote(remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the references of the remote '%s': %v", remote, err)
+	}
+
+	return remoteRepo.ListRefs(refPrefix)
+}
+
+// RefExist will check if a reference exist in Git
+func (r *MemRepo) RefExist(ref string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exist := r.refs[ref]
+	return exist, nil
+}
+
+// CopyRef will create a new reference with the same value as another one
+func (r *MemRepo) CopyRef(source string, dest string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash, exist := r.refs[source]
+	if !exist {
+		return fmt.Errorf("unknown ref %s", source)
+	}
+
+	r.refs[dest] = hash
+	return nil
+}
+
+// resolve return the commit pointed by a reference, or the commit itself
+// the lock must be held
+func (r *MemRepo) resolve(refOrHash string) (git.Hash, error) {
+	if hash, ok := r.refs[refOrHash]; ok {
+		return hash, nil
+	}
+
+	// Git will understand a commit hash as well
+	if _, ok := r.commits[git.Hash(refOrHash)]; ok {
+		return git.Hash(refOrHash), nil
+	}
+
+	return "", fmt.Errorf("unknown revision %s", refOrHash)
+}
+
+// ListCommits will return the list of tree hashes of a ref, in chronological order
+func (r *MemRepo) ListCommits(ref string) ([]git.Hash, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hash, err := r.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []git.Hash
+	for hash != "" {
+		hashes = append([]git.Hash{hash}, hashes...)
+		hash = r.commits[hash].parent
+	}
+
+	return hashes, nil
+}
+
+// ListEntries will return the list of entries in a Git tree
+func (r *MemRepo) ListEntries(hash git.Hash) ([]TreeEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, ok := r.trees[hash]
+
+	if !ok {
+		// Git will understand a commit hash to reach a tree
+		commit, ok := r.commits[hash]
+
+		if !ok {
+			return nil, fmt.Errorf("unknown hash")
+		}
+
+		data, ok = r.trees[commit.treeHash]
+
+		if !ok {
+			return nil, fmt.Errorf("unknown hash")
+		}
+	}
+
+	return readTreeEntries(data)
+}
+
+// FindCommonAncestor will return the last common ancestor of two chain of commit
+func (r *MemRepo) FindCommonAncestor(hash1 git.Hash, hash2 git.Hash) (git.Hash, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ancestors := make(map[git.Hash]struct{})
+	for hash := hash1; hash != ""; hash = r.commits[hash].parent {
+		if _, ok := r.commits[hash]; !ok {
+			return "", fmt.Errorf("unknown commit %s", hash)
+		}
+		ancestors[hash] = struct{}{}
+	}
+
+	for hash := hash2; hash != ""; hash = r.commits[hash].parent {
+		if _, ok := r.commits[hash]; !ok {
+			return "", fmt.Errorf("unknown commit %s", hash)
+		}
+		if _, ok := ancestors[hash]; ok {
+			return hash, nil
+		}
+	}
+
+	return "", fmt.Errorf("no common ancestor between %s and %s", hash1, hash2)
+}
+
+// GetTreeHash return the git tree hash referenced in a commit
+func (r *MemRepo) GetTreeHash(commit git.Hash) (git.Hash, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.commits[commit]
+	if !ok {
+		return "", fmt.Errorf("unknown commit %s", commit)
+	}
+
+	return c.treeHash, nil
+}
+
+// ReadCommitSignature return the GPG signature of a commit. The commits of an
+// in-memory repository are never signed.
+func (r *MemRepo) ReadCommitSignature(commit git.Hash) (CommitSignature, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.commits[commit]; !ok {
+		return CommitSignature{}, fmt.Errorf("unknown commit %s", commit)
+	}
+
+	return CommitSignature{Status: SignatureNone}, nil
+}
+
+// LoadClocks read the clocks values from the on-disk repo. The clocks of an
+// in-memory repository only live in memory.
+func (r *MemRepo) LoadClocks() error {
+	return nil
+}
+
+// WriteClocks write the clocks values into the repo. The clocks of an
+// in-memory repository only live in memory.
+func (r *MemRepo) WriteClocks() error {
+	return nil
+}
+
+// CreateTime return the current value of the creation clock
+func (r *MemRepo) CreateTime() lamport.Time {
+	return r.createClock.Time()
+}
+
+// CreateTimeIncrement increment the creation clock and return the new value.
+func (r *MemRepo) CreateTimeIncrement() (lamport.Time, error) {
+	return r.createClock.Increment(), nil
+}
+
+// EditTime return the current value of the edit clock
+func (r *MemRepo) EditTime() lamport.Time {
+	return r.editClock.Time()
+}
+
+// EditTimeIncrement increment the edit clock and return the new value.
+func (r *MemRepo) EditTimeIncrement() (lamport.Time, error) {
+	return r.editClock.Increment(), nil
+}
+
+// WitnessCreate witness another create time and increment the corresponding
+// clock if needed.
+func (r *MemRepo) WitnessCreate(time lamport.Time) error {
+	r.createClock.Witness(time)
+	return nil
+}
+
+// WitnessEdit witness another edition time and increment the corresponding
+// clock if needed.
+func (r *MemRepo) WitnessEdit(time lamport.Time) error {
+	r.editClock.Witness(time)
+	return nil
+}
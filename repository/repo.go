@@ -38,6 +38,10 @@ type RepoCommon interface {
 	// GetCoreEditor returns the name of the editor that the user has used to configure git.
 	GetCoreEditor() (string, error)
 
+	// GetCorePager returns the pager that the user has configured for git,
+	// with GIT_PAGER, core.pager or PAGER
+	GetCorePager() (string, error)
+
 	// GetRemotes returns the configured remotes repositories.
 	GetRemotes() (map[string]string, error)
 }
@@ -68,12 +72,30 @@ type Repo interface {
 	// StoreCommit will store a Git commit with the given Git tree
 	StoreCommitWithParent(treeHash git.Hash, parent git.Hash) (git.Hash, error)
 
+	// ReadCommitData return the raw content of a Git commit, to store it as
+	// is in another repository with StoreCommitData
+	ReadCommitData(hash git.Hash) ([]byte, error)
+
+	// StoreCommitData will store a Git commit from its raw content, as
+	// returned by ReadCommitData, keeping its hash
+	StoreCommitData(data []byte) (git.Hash, error)
+
 	// UpdateRef will create or update a Git reference
 	UpdateRef(ref string, hash git.Hash) error
 
+	// UpdateRefs will create or update multiple Git references at once
+	UpdateRefs(refs map[string]git.Hash) error
+
 	// ListRefs will return a list of Git ref matching the given refspec
 	ListRefs(refspec string) ([]string, error)
 
+	// RemoveRef will remove a Git reference
+	RemoveRef(ref string) error
+
+	// ListRemoteRefs will return the list of Git ref of a remote matching the
+	// given prefix, directly from the remote
+	ListRemoteRefs(remote string, refPrefix string) ([]string, error)
+
 	// RefExist will check if a reference exist in Git
 	RefExist(ref string) (bool, error)
 
@@ -91,6 +113,10 @@ type Repo interface {
 
 	// GetTreeHash return the git tree hash referenced in a commit
 	GetTreeHash(commit git.Hash) (git.Hash, error)
+
+	// ReadCommitSignature return the GPG signature of a commit, with its
+	// validity
+	ReadCommitSignature(commit git.Hash) (CommitSignature, error)
 }
 
 // ClockedRepo is a Repo that also has Lamport clocks
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/util/git"
+	"github.com/MichaelMure/git-bug/util/lamport"
+)
+
+func TestGitRepo(t *testing.T) {
+	repo := CreateTestRepo(false)
+	remote := CreateTestRepo(true)
+	defer CleanupTestRepos(t, repo, remote)
+
+	err := repo.AddRemote("origin", "file://"+remote.GetPath())
+	require.NoError(t, err)
+
+	testRepo(t, repo, remote)
+}
+
+func TestMemRepo(t *testing.T) {
+	repo := NewMemRepo()
+	remote := NewMemRepo()
+	repo.AddMemRemote("origin", remote)
+
+	testRepo(t, repo, remote)
+}
+
+// testRepo check the behavior of a Repo implementation, with another one
+// as its "origin" remote, to make sure the in-memory repository behave as git
+func testRepo(t *testing.T, repo ClockedRepo, remote ClockedRepo) {
+	// blobs and trees
+	blobHash, err := repo.StoreData([]byte("data"))
+	require.NoError(t, err)
+
+	data, err := repo.ReadData(blobHash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	_, err = repo.ReadData(git.Hash("0123456789012345678901234567890123456789"))
+	assert.Error(t, err)
+
+	subTreeHash, err := repo.StoreTree([]TreeEntry{
+		{ObjectType: Blob, Hash: blobHash, Name: "file"},
+	})
+	require.NoError(t, err)
+
+	treeHash1, err := repo.StoreTree([]TreeEntry{
+		{ObjectType: Blob, Hash: blobHash, Name: "blob"},
+		{ObjectType: Tree, Hash: subTreeHash, Name: "tree"},
+	})
+	require.NoError(t, err)
+
+	entries, err := repo.ListEntries(treeHash1)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	blobHash2, err := repo.StoreData([]byte("data2"))
+	require.NoError(t, err)
+	treeHash2, err := repo.StoreTree([]TreeEntry{
+		{ObjectType: Blob, Hash: blobHash2, Name: "blob"},
+	})
+	require.NoError(t, err)
+
+	// commits
+	commit1, err := repo.StoreCommit(treeHash1)
+	require.NoError(t, err)
+	commit2, err := repo.StoreCommitWithParent(treeHash2, commit1)
+	require.NoError(t, err)
+	commit3, err := repo.StoreCommitWithParent(treeHash1, commit1)
+	require.NoError(t, err)
+
+	tree, err := repo.GetTreeHash(commit2)
+	require.NoError(t, err)
+	assert.Equal(t, treeHash2, tree)
+
+	// a commit lead to its tree
+	entries, err = repo.ListEntries(commit2)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	ancestor, err := repo.FindCommonAncestor(commit2, commit3)
+	require.NoError(t, err)
+	assert.Equal(t, commit1, ancestor)
+
+	// refs
+	err = repo.UpdateRef("refs/bugs/b", commit2)
+	require.NoError(t, err)
+	err = repo.UpdateRefs(map[string]git.Hash{
+		"refs/bugs/a":       commit3,
+		"refs/identities/i": commit1,
+	})
+	require.NoError(t, err)
+
+	refs, err := repo.ListRefs("refs/bugs/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/bugs/a", "refs/bugs/b"}, refs)
+
+	refs, err = repo.ListRefs("refs/nothing/")
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+
+	commits, err := repo.ListCommits("refs/bugs/b")
+	require.NoError(t, err)
+	assert.Equal(t, []git.Hash{commit1, commit2}, commits)
+
+	// Git will understand a commit hash as well
+	commits, err = repo.ListCommits(string(commit3))
+	require.NoError(t, err)
+	assert.Equal(t, []git.Hash{commit1, commit3}, commits)
+
+	err = repo.CopyRef("refs/bugs/a", "refs/bugs/c")
+	require.NoError(t, err)
+	exist, err := repo.RefExist("refs/bugs/c")
+	require.NoError(t, err)
+	assert.True(t, exist)
+
+	err = repo.RemoveRef("refs/bugs/c")
+	require.NoError(t, err)
+	exist, err = repo.RefExist("refs/bugs/c")
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	// remotes
+	_, err = repo.PushRefs("origin", "refs/bugs/*")
+	require.NoError(t, err)
+
+	refs, err = repo.ListRemoteRefs("origin", "refs/bugs/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/bugs/a", "refs/bugs/b"}, refs)
+
+	// the objects are available in the remote
+	commits, err = remote.ListCommits("refs/bugs/b")
+	require.NoError(t, err)
+	assert.Equal(t, []git.Hash{commit1, commit2}, commits)
+	entries, err = remote.ListEntries(subTreeHash)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	data, err = remote.ReadData(blobHash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	// a push that is not a fast-forward is rejected
+	err = repo.UpdateRef("refs/bugs/b", commit3)
+	require.NoError(t, err)
+	_, err = repo.PushRefs("origin", "refs/bugs/*")
+	assert.Error(t, err)
+
+	_, err = repo.FetchRefs("origin", "refs/bugs/*:refs/remotes/origin/bugs/*")
+	require.NoError(t, err)
+	refs, err = repo.ListRefs("refs/remotes/origin/bugs/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/remotes/origin/bugs/a", "refs/remotes/origin/bugs/b"}, refs)
+	commits, err = repo.ListCommits("refs/remotes/origin/bugs/b")
+	require.NoError(t, err)
+	assert.Equal(t, []git.Hash{commit1, commit2}, commits)
+
+	// a forced update doesn't need to be a fast-forward
+	_, err = repo.FetchRefs("origin", "+refs/bugs/b:refs/remotes/origin/bugs/a")
+	require.NoError(t, err)
+	commits, err = repo.ListCommits("refs/remotes/origin/bugs/a")
+	require.NoError(t, err)
+	assert.Equal(t, []git.Hash{commit1, commit2}, commits)
+
+	// a commit copied with its raw data keep its hash
+	remoteBlob, err := remote.StoreData([]byte("data2"))
+	require.NoError(t, err)
+	_, err = remote.StoreTree([]TreeEntry{
+		{ObjectType: Blob, Hash: remoteBlob, Name: "blob"},
+	})
+	require.NoError(t, err)
+	commitData, err := repo.ReadCommitData(commit2)
+	require.NoError(t, err)
+	copied, err := remote.StoreCommitData(commitData)
+	require.NoError(t, err)
+	assert.Equal(t, commit2, copied)
+	_, err = repo.ReadCommitData(git.Hash("0123456789012345678901234567890123456789"))
+	assert.Error(t, err)
+
+	// clocks
+	createTime := repo.CreateTime()
+	time, err := repo.CreateTimeIncrement()
+	require.NoError(t, err)
+	assert.Equal(t, createTime, time)
+	assert.Equal(t, createTime+1, repo.CreateTime())
+
+	err = repo.WitnessEdit(42)
+	require.NoError(t, err)
+	assert.Equal(t, lamport.Time(43), repo.EditTime())
+}
@@ -0,0 +1,48 @@
+package repository
+
+// SignatureStatus is the validity of the GPG signature of a commit, as
+// checked by git
+type SignatureStatus string
+
+const (
+	SignatureNone SignatureStatus = "none"
+	SignatureGood SignatureStatus = "good"
+	SignatureBad  SignatureStatus = "bad"
+	// a good signature, from a key whose validity is unknown
+	SignatureUnknownValidity SignatureStatus = "unknown-validity"
+	SignatureExpired         SignatureStatus = "expired"
+	SignatureExpiredKey      SignatureStatus = "expired-key"
+	SignatureRevokedKey      SignatureStatus = "revoked-key"
+	// the signature can't be checked, usually as the key is missing
+	SignatureUnverifiable SignatureStatus = "unverifiable"
+)
+
+// CommitSignature is the GPG signature of a commit
+type CommitSignature struct {
+	Status SignatureStatus `json:"status"`
+	// the signer and the fingerprint of the key, empty when not signed
+	Signer string `json:"signer,omitempty"`
+	Key    string `json:"key,omitempty"`
+}
+
+// signatureStatusFromGit read the status given by git with the "%G?" format
+func signatureStatusFromGit(code string) SignatureStatus {
+	switch code {
+	case "G":
+		return SignatureGood
+	case "B":
+		return SignatureBad
+	case "U":
+		return SignatureUnknownValidity
+	case "X":
+		return SignatureExpired
+	case "Y":
+		return SignatureExpiredKey
+	case "R":
+		return SignatureRevokedKey
+	case "E":
+		return SignatureUnverifiable
+	default:
+		return SignatureNone
+	}
+}
@@ -0,0 +1,51 @@
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	fmt.Println("Packing JSON schemas ...")
+
+	files, err := filepath.Glob("*.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(files)
+
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by gen_schemas.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package schema\n\n")
+	buf.WriteString("var schemas = map[string]string{\n")
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		name := strings.TrimSuffix(file, filepath.Ext(file))
+
+		if bytes.ContainsRune(data, '`') {
+			_, _ = fmt.Fprintf(&buf, "\t%q: %q,\n", name, data)
+		} else {
+			_, _ = fmt.Fprintf(&buf, "\t%q: `%s`,\n", name, data)
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	err = ioutil.WriteFile("schemas_gen.go", buf.Bytes(), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,107 @@
+//go:generate go run gen_schemas.go
+
+// Package schema hold the JSON Schemas describing the formats git-bug read
+// and write, for the third-party tools working with them and to validate
+// the data coming from elsewhere.
+//
+// The schemas are written in the .json files of this package, and embedded
+// in the binary with go generate.
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// Snapshot is the schema of the compiled state of a bug
+	Snapshot = "snapshot"
+	// Operation is the schema of an edit operation of a bug
+	Operation = "operation"
+	// Identity is the schema of a version of an identity
+	Identity = "identity"
+)
+
+var (
+	compiledMu sync.Mutex
+	compiled   = make(map[string]*gojsonschema.Schema)
+)
+
+// ValidationError is returned when a document doesn't conform to a schema
+type ValidationError struct {
+	Schema string
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Schema, strings.Join(e.Errors, ", "))
+}
+
+// Names return the names of the available schemas, sorted
+func Names() []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get return a schema as a JSON document
+func Get(name string) (string, error) {
+	schema, ok := schemas[name]
+	if !ok {
+		return "", fmt.Errorf("unknown schema %s", name)
+	}
+	return schema, nil
+}
+
+// Validate check that a JSON document conform to a schema
+func Validate(name string, data []byte) error {
+	schema, err := compile(name)
+	if err != nil {
+		return err
+	}
+
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return err
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	verr := &ValidationError{Schema: name}
+	for _, e := range result.Errors() {
+		verr.Errors = append(verr.Errors, e.String())
+	}
+	return verr
+}
+
+// compile parse a schema once for all
+func compile(name string) (*gojsonschema.Schema, error) {
+	compiledMu.Lock()
+	defer compiledMu.Unlock()
+
+	if schema, ok := compiled[name]; ok {
+		return schema, nil
+	}
+
+	raw, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	compiled[name] = schema
+	return schema, nil
+}
@@ -0,0 +1,30 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemas(t *testing.T) {
+	require.Equal(t, []string{Identity, Operation, Snapshot}, Names())
+
+	for _, name := range Names() {
+		_, err := compile(name)
+		require.NoError(t, err, name)
+	}
+
+	_, err := Get("unknown")
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	err := Validate(Operation, []byte(`{"type":7,"author":{"name":"René"},"timestamp":1}`))
+	require.NoError(t, err)
+
+	err = Validate(Operation, []byte(`{"type":7,"timestamp":1}`))
+	require.IsType(t, &ValidationError{}, err)
+
+	err = Validate(Operation, []byte(`not json`))
+	require.Error(t, err)
+}
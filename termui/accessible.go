@@ -0,0 +1,540 @@
+package termui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/entity"
+	"github.com/MichaelMure/git-bug/input"
+	"github.com/MichaelMure/git-bug/util/i18n"
+	"github.com/MichaelMure/git-bug/util/text"
+)
+
+// AccessibleEnv is the environment variable enabling the accessible mode of
+// the termui, as the --accessible flag
+const AccessibleEnv = "GIT_BUG_ACCESSIBLE"
+
+const accessibleHelp = `Commands of the list of bugs:
+  list                read the bugs matching the query
+  next, n             read the next bug
+  previous, p         read the previous bug
+  search, s <query>   change the query, as "status:open label:bug"
+  open, o [number]    open the current bug, or the bug of the given number
+  new [title]         create a bug, with an editor without title
+  pull, push          synchronize with the default remote
+  quit, q             quit
+
+Commands of an open bug:
+  read, r             read the whole bug
+  next, n             read the next comment
+  previous, p         read the previous comment
+  comment [message]   add a comment, with an editor without message
+  title [title]       change the title, with an editor without title
+  close, reopen       change the status
+  label <+add|-rm>... add or remove labels, as "label +bug -wontfix"
+  check, uncheck <n>  check or uncheck the item n of the checklist
+  back, q             return to the list of bugs
+
+help, ? reads this help.
+`
+
+// accessibleUI is a linear rendering of the termui for the screen readers: it
+// reads commands line by line and answers with plain text, without box
+// drawing, colors or screen redraws. Every change of state is announced.
+type accessibleUI struct {
+	repo *cache.RepoCache
+	in   *bufio.Scanner
+	out  io.Writer
+
+	queryStr string
+	query    *cache.Query
+	ids      []entity.Id
+	// the position in the list of bugs, -1 before the first one
+	cursor int
+
+	// the open bug, if any, and the position in its comments
+	bug     *cache.BugCache
+	comment int
+}
+
+// RunAccessible launch the accessible mode of the termui, reading the commands
+// from in and writing the answers to out
+func RunAccessible(repo *cache.RepoCache, in io.Reader, out io.Writer) error {
+	query, err := cache.ParseQuery(defaultQuery)
+	if err != nil {
+		return err
+	}
+
+	au := &accessibleUI{
+		repo:     repo,
+		in:       bufio.NewScanner(in),
+		out:      out,
+		queryStr: defaultQuery,
+		query:    query,
+	}
+
+	au.say("git-bug, accessible mode. Type help for the commands.")
+	au.refresh()
+
+	for {
+		_, _ = fmt.Fprint(au.out, "> ")
+
+		if !au.in.Scan() {
+			// end of input, as quit
+			_, _ = fmt.Fprintln(au.out)
+			return au.in.Err()
+		}
+
+		fields := strings.Fields(au.in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		command, args := fields[0], fields[1:]
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(au.in.Text()), command))
+
+		var quit bool
+		if au.bug != nil {
+			err = au.bugCommand(command, args, rest)
+		} else {
+			quit, err = au.listCommand(command, args, rest)
+		}
+		if err != nil {
+			au.say("Error: %s", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+func (au *accessibleUI) say(format string, args ...interface{}) {
+	_, _ = i18n.Fprintf(au.out, format+"\n", args...)
+}
+
+func (au *accessibleUI) listCommand(command string, args []string, rest string) (bool, error) {
+	switch command {
+	case "help", "?":
+		_, _ = fmt.Fprint(au.out, i18n.Sprintf(accessibleHelp))
+
+	case "list", "l":
+		au.refresh()
+		for i, id := range au.ids {
+			if err := au.readExcerpt(i, id); err != nil {
+				return false, err
+			}
+		}
+
+	case "next", "n":
+		if au.cursor+1 >= len(au.ids) {
+			au.say("End of the list.")
+			return false, nil
+		}
+		au.cursor++
+		return false, au.readExcerpt(au.cursor, au.ids[au.cursor])
+
+	case "previous", "p":
+		if au.cursor <= 0 {
+			au.say("Start of the list.")
+			return false, nil
+		}
+		au.cursor--
+		return false, au.readExcerpt(au.cursor, au.ids[au.cursor])
+
+	case "search", "s":
+		query, err := cache.ParseQuery(rest)
+		if err != nil {
+			return false, err
+		}
+		au.queryStr = rest
+		au.query = query
+		au.refresh()
+
+	case "open", "o":
+		index := au.cursor
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > len(au.ids) {
+				return false, fmt.Errorf("no bug number %s", args[0])
+			}
+			index = n - 1
+		}
+		if index < 0 || index >= len(au.ids) {
+			return false, fmt.Errorf("no bug selected, use next or open with a number")
+		}
+		au.cursor = index
+		return false, au.open(au.ids[index])
+
+	case "new":
+		return false, au.newBug(rest)
+
+	case "pull":
+		return false, au.pull()
+
+	case "push":
+		return false, au.push()
+
+	case "quit", "q":
+		au.say("Bye.")
+		return true, nil
+
+	default:
+		return false, fmt.Errorf("unknown command %s, type help for the commands", command)
+	}
+
+	return false, nil
+}
+
+func (au *accessibleUI) bugCommand(command string, args []string, rest string) error {
+	snap := au.bug.Snapshot()
+
+	switch command {
+	case "help", "?":
+		_, _ = fmt.Fprint(au.out, i18n.Sprintf(accessibleHelp))
+
+	case "read", "r":
+		au.readBug()
+
+	case "next", "n":
+		if au.comment+1 >= len(snap.Comments) {
+			au.say("No more comments.")
+			return nil
+		}
+		au.comment++
+		au.readComment(snap, au.comment)
+
+	case "previous", "p":
+		if au.comment <= 0 {
+			au.say("This is the description.")
+			return nil
+		}
+		au.comment--
+		au.readComment(snap, au.comment)
+
+	case "comment":
+		message := rest
+		if message == "" {
+			var err error
+			message, err = input.BugCommentEditorInput(au.repo, "")
+			if err == input.ErrEmptyMessage {
+				au.say("Empty message, aborting.")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		_, err := au.bug.AddComment(message)
+		if err != nil {
+			return err
+		}
+		if err := au.bug.CommitAsNeeded(); err != nil {
+			return err
+		}
+		au.say("Comment %d added.", len(au.bug.Snapshot().Comments)-1)
+
+	case "title":
+		title := rest
+		if title == "" {
+			var err error
+			title, err = input.BugTitleEditorInput(au.repo, snap.Title)
+			if err == input.ErrEmptyTitle {
+				au.say("Empty title, aborting.")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if title == snap.Title {
+			au.say("No change, aborting.")
+			return nil
+		}
+		_, err := au.bug.SetTitle(title)
+		if err != nil {
+			return err
+		}
+		if err := au.bug.CommitAsNeeded(); err != nil {
+			return err
+		}
+		au.say("Title changed to %s.", title)
+
+	case "close", "reopen":
+		status := bug.ClosedStatus
+		if command == "reopen" {
+			status = bug.OpenStatus
+		}
+		if snap.Status == status {
+			au.say("The bug is already %s.", status)
+			return nil
+		}
+		var err error
+		if status == bug.ClosedStatus {
+			_, err = au.bug.Close()
+		} else {
+			_, err = au.bug.Open()
+		}
+		if err != nil {
+			return err
+		}
+		if err := au.bug.CommitAsNeeded(); err != nil {
+			return err
+		}
+		au.say("Bug %s is now %s.", au.bug.Id().Human(), status)
+
+	case "label":
+		var added, removed []string
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "-") {
+				removed = append(removed, strings.TrimPrefix(arg, "-"))
+			} else {
+				added = append(added, strings.TrimPrefix(arg, "+"))
+			}
+		}
+		if len(added) == 0 && len(removed) == 0 {
+			return fmt.Errorf("no label given, as label +bug -wontfix")
+		}
+		results, _, err := au.bug.ChangeLabels(added, removed)
+		if err != nil {
+			return err
+		}
+		if err := au.bug.CommitAsNeeded(); err != nil {
+			return err
+		}
+		for _, result := range results {
+			au.say("%s.", result)
+		}
+
+	case "check", "uncheck":
+		if len(args) != 1 {
+			return fmt.Errorf("expected the number of an item of the checklist")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > len(snap.Checklist) {
+			return fmt.Errorf("no item number %s in the checklist", args[0])
+		}
+		item := snap.Checklist[n-1]
+		_, err = au.bug.CheckItem(item.Id(), command == "check")
+		if err != nil {
+			return err
+		}
+		if err := au.bug.CommitAsNeeded(); err != nil {
+			return err
+		}
+		if command == "check" {
+			au.say("Item %d checked: %s.", n, item.Text)
+		} else {
+			au.say("Item %d unchecked: %s.", n, item.Text)
+		}
+
+	case "back", "q", "quit":
+		au.bug = nil
+		au.say("Back to the list of bugs.")
+		au.refresh()
+
+	default:
+		return fmt.Errorf("unknown command %s, type help for the commands", command)
+	}
+
+	return nil
+}
+
+// refresh run the query again and announce the number of bugs
+func (au *accessibleUI) refresh() {
+	au.ids = au.repo.QueryBugs(au.query)
+	au.cursor = -1
+	au.say("%d bug(s) match %s.", len(au.ids), au.queryStr)
+}
+
+func (au *accessibleUI) readExcerpt(index int, id entity.Id) error {
+	excerpt, err := au.repo.ResolveBugExcerpt(id)
+	if err != nil {
+		return err
+	}
+
+	var author string
+	if excerpt.AuthorId != "" {
+		a, err := au.repo.ResolveIdentityExcerpt(excerpt.AuthorId)
+		if err != nil {
+			return err
+		}
+		author = a.DisplayName()
+	} else {
+		author = excerpt.LegacyAuthor.DisplayName()
+	}
+
+	var details []string
+	if au.repo.IsUnread(excerpt) {
+		details = append(details, i18n.Sprintf("unread"))
+	}
+	details = append(details, i18n.Sprintf("by %s", author))
+	if len(excerpt.Labels) > 0 {
+		labels := make([]string, len(excerpt.Labels))
+		for i, l := range excerpt.Labels {
+			labels[i] = string(l)
+		}
+		details = append(details, i18n.Sprintf("labels %s", strings.Join(labels, ", ")))
+	}
+	details = append(details, i18n.Sprintf("%d comment(s)", excerpt.LenComments-1))
+
+	au.say("%d. Bug %s, %s: %s. %s.", index+1, excerpt.Id.Human(), excerpt.Status,
+		excerpt.Title, strings.Join(details, ", "))
+	return nil
+}
+
+func (au *accessibleUI) open(id entity.Id) error {
+	b, err := au.repo.ResolveBug(id)
+	if err != nil {
+		return err
+	}
+	if err := au.repo.MarkRead(id); err != nil {
+		return err
+	}
+
+	au.bug = b
+	au.comment = 0
+	au.readBug()
+	return nil
+}
+
+// readBug read the header of the open bug, and its description
+func (au *accessibleUI) readBug() {
+	snap := au.bug.Snapshot()
+
+	au.say("Bug %s, %s: %s.", snap.Id().Human(), snap.Status, snap.Title)
+	au.say("Opened by %s on %s.", snap.Author.DisplayName(), snap.CreatedAt.Format(timeLayout))
+
+	if len(snap.Labels) > 0 {
+		labels := make([]string, len(snap.Labels))
+		for i, l := range snap.Labels {
+			labels[i] = string(l)
+		}
+		au.say("Labels: %s.", strings.Join(labels, ", "))
+	}
+
+	if len(snap.Assignees) > 0 {
+		names := make([]string, len(snap.Assignees))
+		for i, a := range snap.Assignees {
+			names[i] = a.DisplayName()
+		}
+		au.say("Assigned to %s.", strings.Join(names, ", "))
+	}
+
+	for i, item := range snap.Checklist {
+		state := i18n.Sprintf("not done")
+		if item.Checked {
+			state = i18n.Sprintf("done")
+		}
+		au.say("Checklist item %d, %s: %s.", i+1, state, item.Text)
+	}
+
+	au.say("%d comment(s) after the description.", len(snap.Comments)-1)
+
+	au.comment = 0
+	au.readComment(snap, 0)
+}
+
+func (au *accessibleUI) readComment(snap *bug.Snapshot, index int) {
+	comment := snap.Comments[index]
+
+	if index == 0 {
+		au.say("Description:")
+	} else {
+		reply := ""
+		if comment.ReplyTo != "" {
+			for i, c := range snap.Comments {
+				if c.Id() == comment.ReplyTo {
+					reply = i18n.Sprintf(", in reply to comment %d", i)
+				}
+			}
+		}
+		au.say("Comment %d of %d, by %s on %s%s:", index, len(snap.Comments)-1,
+			comment.Author.DisplayName(), comment.FormatTime(), reply)
+	}
+
+	message, _ := text.Cleanup(comment.Message)
+	if message == "" {
+		message = i18n.Sprintf("No description provided.")
+	}
+	_, _ = fmt.Fprintln(au.out, message)
+}
+
+func (au *accessibleUI) newBug(title string) error {
+	message := ""
+	if title == "" {
+		var err error
+		title, message, err = input.BugCreateEditorInput(au.repo, "", "")
+		if err == input.ErrEmptyTitle {
+			au.say("Empty title, aborting.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	b, _, err := au.repo.NewBug(title, message)
+	if err != nil {
+		return err
+	}
+
+	au.say("Bug %s created.", b.Id().Human())
+	au.bug = b
+	au.comment = 0
+	au.readBug()
+	return nil
+}
+
+func (au *accessibleUI) pull() error {
+	remote, err := au.repo.DefaultRemote()
+	if err != nil {
+		return err
+	}
+
+	au.say("Pulling from %s ...", remote)
+
+	stdout, err := au.repo.Fetch(remote)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(au.out, stdout)
+
+	changed := 0
+	for result := range au.repo.MergeAll(remote) {
+		if result.Err != nil {
+			au.say("Error: %s", result.Err)
+			continue
+		}
+		if result.Status == entity.MergeStatusNothing {
+			continue
+		}
+		changed++
+		au.say("%s: %s", result.Id.Human(), result)
+	}
+
+	au.say("Pull done, %d change(s).", changed)
+	au.refresh()
+	return nil
+}
+
+func (au *accessibleUI) push() error {
+	remote, err := au.repo.DefaultRemote()
+	if err != nil {
+		return err
+	}
+
+	au.say("Pushing to %s ...", remote)
+
+	stdout, err := au.repo.Push(remote)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(au.out, stdout)
+
+	au.say("Push done.")
+	return nil
+}
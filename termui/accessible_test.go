@@ -0,0 +1,85 @@
+package termui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestRunAccessible(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	backend, err := cache.NewRepoCache(repo)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	iden, err := backend.NewIdentity("René Descartes", "rene@descartes.fr")
+	require.NoError(t, err)
+	require.NoError(t, backend.SetUserIdentity(iden))
+
+	b, _, err := backend.NewBug("crash on start", "it crashes")
+	require.NoError(t, err)
+	_, err = b.AddChecklistItem("reproduce")
+	require.NoError(t, err)
+	require.NoError(t, b.Commit())
+
+	in := strings.Join([]string{
+		"next",
+		"next",
+		"open",
+		"comment it still crashes",
+		"n",
+		"label +bug",
+		"check 1",
+		"close",
+		"title crash on startup",
+		"unknown",
+		"back",
+		"search status:closed",
+		"list",
+		"q",
+	}, "\n")
+
+	var out bytes.Buffer
+	require.NoError(t, RunAccessible(backend, strings.NewReader(in), &out))
+
+	output := out.String()
+	for _, expected := range []string{
+		"1 bug(s) match status:open.",
+		"1. Bug " + b.Id().Human() + ", open: crash on start. by René Descartes, 0 comment(s).",
+		"End of the list.",
+		"Checklist item 1, not done: reproduce.",
+		"Description:\nit crashes\n",
+		"Comment 1 added.",
+		"Comment 1 of 1, by René Descartes on ",
+		"label bug added.",
+		"Item 1 checked: reproduce.",
+		"Bug " + b.Id().Human() + " is now closed.",
+		"Title changed to crash on startup.",
+		"Error: unknown command unknown",
+		"Back to the list of bugs.",
+		"0 bug(s) match status:open.",
+		"1 bug(s) match status:closed.",
+		"Bye.",
+	} {
+		require.Contains(t, output, expected)
+	}
+
+	// no color or box drawing
+	require.NotContains(t, output, "\x1b[")
+	require.NotContains(t, output, "─")
+
+	snap := b.Snapshot()
+	require.Equal(t, bug.ClosedStatus, snap.Status)
+	require.Equal(t, "crash on startup", snap.Title)
+	require.Len(t, snap.Comments, 2)
+	require.True(t, snap.Checklist[0].Checked)
+	require.False(t, b.NeedCommit())
+}
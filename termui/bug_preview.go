@@ -0,0 +1,109 @@
+package termui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MichaelMure/go-term-text"
+	"github.com/awesome-gocui/gocui"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/i18n"
+)
+
+const bugPreviewView = "bugPreviewView"
+
+// The layout preferences are stored in the global git config, to be shared
+// by all the repositories
+const (
+	splitConfigKey      = "git-bug.termui.split"
+	splitRatioConfigKey = "git-bug.termui.split-ratio"
+)
+
+const (
+	defaultSplitRatio = 50
+	minSplitRatio     = 20
+	maxSplitRatio     = 80
+	splitRatioStep    = 5
+)
+
+// layoutPrefs are the user preferences for the layout of the bug table
+type layoutPrefs struct {
+	// display a preview of the selected bug next to the table
+	split bool
+	// percentage of the width given to the table in the split view
+	splitRatio int
+}
+
+func loadLayoutPrefs(config repository.Config) layoutPrefs {
+	prefs := layoutPrefs{splitRatio: defaultSplitRatio}
+
+	split, err := config.ReadBool(splitConfigKey)
+	if err == nil {
+		prefs.split = split
+	}
+
+	raw, err := config.ReadString(splitRatioConfigKey)
+	if err == nil {
+		ratio, err := strconv.Atoi(raw)
+		if err == nil {
+			prefs.splitRatio = clampSplitRatio(ratio)
+		}
+	}
+
+	return prefs
+}
+
+func (lp layoutPrefs) store(config repository.Config) error {
+	err := config.StoreBool(splitConfigKey, lp.split)
+	if err != nil {
+		return err
+	}
+
+	return config.StoreString(splitRatioConfigKey, strconv.Itoa(lp.splitRatio))
+}
+
+func clampSplitRatio(ratio int) int {
+	return maxInt(minSplitRatio, minInt(maxSplitRatio, ratio))
+}
+
+// renderBugPreview render a condensed version of a bug, to be displayed in a
+// narrow view
+func renderBugPreview(v *gocui.View, snap *bug.Snapshot, maxX int) {
+	title, _ := text.Wrap(colors.Title(snap.Title), maxX)
+	_, _ = fmt.Fprintf(v, "%s\n", title)
+
+	_, _ = i18n.Fprintf(v, "[%s] %s %s opened this issue on %s\n",
+		colors.Status(snap.Status),
+		colors.Id(snap.Id().Human()),
+		colors.Author(snap.Author.DisplayName()),
+		snap.CreatedAt.Format(timeLayout),
+	)
+
+	if len(snap.Labels) > 0 {
+		labels := make([]string, len(snap.Labels))
+		for i, l := range snap.Labels {
+			lc256 := l.Color().Term256()
+			labels[i] = colors.Wrap(lc256.Escape(), lc256.Unescape(), "◼ ") + l.String()
+		}
+		_, _ = fmt.Fprintf(v, "%s\n", strings.Join(labels, " "))
+	}
+
+	for _, comment := range snap.Comments {
+		_, _ = fmt.Fprintf(v, "\n%s on %s\n",
+			colors.Author(comment.Author.DisplayName()),
+			comment.UnixTime.Time().Format(timeLayout),
+		)
+
+		message := comment.Message
+		if message == "" {
+			message = emptyMessagePlaceholder()
+		}
+
+		message, _ = renderMarkdown(message, maxX, 2)
+		_, _ = fmt.Fprintf(v, "%s\n", message)
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"github.com/awesome-gocui/gocui"
 	"github.com/dustin/go-humanize"
 
+	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/i18n"
 )
 
 const bugTableView = "bugTableView"
@@ -20,7 +22,6 @@ const bugTableHeaderView = "bugTableHeaderView"
 const bugTableFooterView = "bugTableFooterView"
 const bugTableInstructionView = "bugTableInstructionView"
 
-const defaultRemote = "origin"
 const defaultQuery = "status:open"
 
 type bugTable struct {
@@ -31,6 +32,9 @@ type bugTable struct {
 	excerpts     []*cache.BugExcerpt
 	pageCursor   int
 	selectCursor int
+	// bugs marked for a batch action
+	marked map[entity.Id]bool
+	prefs  layoutPrefs
 }
 
 func newBugTable(c *cache.RepoCache) *bugTable {
@@ -45,6 +49,8 @@ func newBugTable(c *cache.RepoCache) *bugTable {
 		queryStr:     defaultQuery,
 		pageCursor:   0,
 		selectCursor: 0,
+		marked:       make(map[entity.Id]bool),
+		prefs:        loadLayoutPrefs(c.GlobalConfig()),
 	}
 }
 
@@ -56,7 +62,13 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 		return nil
 	}
 
-	v, err := g.SetView(bugTableHeaderView, -1, -1, maxX, 3, 0)
+	// in the split view, the table only take the left part of the screen
+	tableX := maxX
+	if bt.prefs.split {
+		tableX = maxX * bt.prefs.splitRatio / 100
+	}
+
+	v, err := g.SetView(bugTableHeaderView, -1, -1, tableX, 3, 0)
 
 	if err != nil {
 		if !gocui.IsUnknownView(err) {
@@ -67,9 +79,9 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 	}
 
 	v.Clear()
-	bt.renderHeader(v, maxX)
+	bt.renderHeader(v, tableX)
 
-	v, err = g.SetView(bugTableView, -1, 1, maxX, maxY-3, 0)
+	v, err = g.SetView(bugTableView, -1, 1, tableX, maxY-3, 0)
 
 	if err != nil {
 		if !gocui.IsUnknownView(err) {
@@ -93,9 +105,9 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 	}
 
 	v.Clear()
-	bt.render(v, maxX)
+	bt.render(v, tableX)
 
-	v, err = g.SetView(bugTableFooterView, -1, maxY-4, maxX, maxY, 0)
+	v, err = g.SetView(bugTableFooterView, -1, maxY-4, tableX, maxY, 0)
 
 	if err != nil {
 		if !gocui.IsUnknownView(err) {
@@ -106,7 +118,12 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 	}
 
 	v.Clear()
-	bt.renderFooter(v, maxX)
+	bt.renderFooter(v, tableX)
+
+	err = bt.layoutPreview(g, tableX, maxX, maxY)
+	if err != nil {
+		return err
+	}
 
 	v, err = g.SetView(bugTableInstructionView, -1, maxY-2, maxX, maxY, 0)
 
@@ -118,7 +135,7 @@ func (bt *bugTable) layout(g *gocui.Gui) error {
 		v.Frame = false
 		v.BgColor = gocui.ColorBlue
 
-		_, _ = fmt.Fprintf(v, "[q] Quit [s] Search [←↓↑→,hjkl] Navigation [↵] Open bug [n] New bug [i] Pull [o] Push")
+		_, _ = i18n.Fprintf(v, "[q] Quit [s] Search [←↓↑→,hjkl] Navigation [↵] Open bug [n] New bug [i] Pull [o] Push [space] Mark [C] Close [O] Open [L] Label [v] Split view [<>] Resize")
 	}
 
 	_, err = g.SetCurrentView(bugTableView)
@@ -207,6 +224,40 @@ func (bt *bugTable) keybindings(g *gocui.Gui) error {
 		return err
 	}
 
+	// Split view
+	if err := g.SetKeybinding(bugTableView, 'v', gocui.ModNone,
+		bt.toggleSplit); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding(bugTableView, '<', gocui.ModNone,
+		bt.shrinkSplit); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding(bugTableView, '>', gocui.ModNone,
+		bt.growSplit); err != nil {
+		return err
+	}
+
+	// Mark
+	if err := g.SetKeybinding(bugTableView, gocui.KeySpace, gocui.ModNone,
+		bt.toggleMark); err != nil {
+		return err
+	}
+
+	// Batch actions
+	if err := g.SetKeybinding(bugTableView, 'C', gocui.ModNone,
+		bt.closeMarked); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding(bugTableView, 'O', gocui.ModNone,
+		bt.openMarked); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding(bugTableView, 'L', gocui.ModNone,
+		bt.labelMarked); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -223,6 +274,73 @@ func (bt *bugTable) disable(g *gocui.Gui) error {
 	if err := g.DeleteView(bugTableInstructionView); err != nil && !gocui.IsUnknownView(err) {
 		return err
 	}
+	if err := g.DeleteView(bugPreviewView); err != nil && !gocui.IsUnknownView(err) {
+		return err
+	}
+	return nil
+}
+
+// layoutPreview display the selected bug on the right of the table in the
+// split view
+func (bt *bugTable) layoutPreview(g *gocui.Gui, x0 int, maxX int, maxY int) error {
+	if !bt.prefs.split || bt.selectCursor >= bt.getTableLength() {
+		err := g.DeleteView(bugPreviewView)
+		if err != nil && !gocui.IsUnknownView(err) {
+			return err
+		}
+		return nil
+	}
+
+	v, err := g.SetView(bugPreviewView, x0, 1, maxX-1, maxY-3, 0)
+
+	if err != nil {
+		if !gocui.IsUnknownView(err) {
+			return err
+		}
+
+		v.Frame = true
+		v.Wrap = false
+	}
+
+	b, err := bt.repo.ResolveBug(bt.excerpts[bt.selectCursor].Id)
+	if err != nil {
+		return err
+	}
+
+	width, _ := v.Size()
+
+	v.Clear()
+	renderBugPreview(v, b.Snapshot(), width)
+
+	return nil
+}
+
+func (bt *bugTable) toggleSplit(g *gocui.Gui, v *gocui.View) error {
+	bt.prefs.split = !bt.prefs.split
+	return bt.storePrefs()
+}
+
+func (bt *bugTable) shrinkSplit(g *gocui.Gui, v *gocui.View) error {
+	return bt.resizeSplit(-splitRatioStep)
+}
+
+func (bt *bugTable) growSplit(g *gocui.Gui, v *gocui.View) error {
+	return bt.resizeSplit(splitRatioStep)
+}
+
+func (bt *bugTable) resizeSplit(delta int) error {
+	if !bt.prefs.split {
+		return nil
+	}
+	bt.prefs.splitRatio = clampSplitRatio(bt.prefs.splitRatio + delta)
+	return bt.storePrefs()
+}
+
+func (bt *bugTable) storePrefs() error {
+	err := bt.prefs.store(bt.repo.GlobalConfig())
+	if err != nil {
+		ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
+	}
 	return nil
 }
 
@@ -267,10 +385,11 @@ func (bt *bugTable) getTableLength() int {
 
 func (bt *bugTable) getColumnWidths(maxX int) map[string]int {
 	m := make(map[string]int)
+	m["mark"] = 2
 	m["id"] = 9
 	m["status"] = 7
 
-	left := maxX - 5 - m["id"] - m["status"]
+	left := maxX - 6 - m["mark"] - m["id"] - m["status"]
 
 	m["comments"] = 10
 	left -= m["comments"]
@@ -298,9 +417,7 @@ func (bt *bugTable) render(v *gocui.View, maxX int) {
 		var labelsTxt strings.Builder
 		for _, l := range excerpt.Labels {
 			lc256 := l.Color().Term256()
-			labelsTxt.WriteString(lc256.Escape())
-			labelsTxt.WriteString(" ◼")
-			labelsTxt.WriteString(lc256.Unescape())
+			labelsTxt.WriteString(colors.Wrap(lc256.Escape(), lc256.Unescape(), " ◼"))
 		}
 
 		var authorDisplayName string
@@ -316,20 +433,28 @@ func (bt *bugTable) render(v *gocui.View, maxX int) {
 
 		lastEditTime := time.Unix(excerpt.EditUnixTime, 0)
 
+		mark := text.LeftPadMaxLine("", columnWidths["mark"], 0)
+		if bt.marked[excerpt.Id] {
+			mark = text.LeftPadMaxLine("✔", columnWidths["mark"], 1)
+		}
 		id := text.LeftPadMaxLine(excerpt.Id.Human(), columnWidths["id"], 1)
 		status := text.LeftPadMaxLine(excerpt.Status.String(), columnWidths["status"], 1)
 		labels := text.TruncateMax(labelsTxt.String(), minInt(columnWidths["title"]-2, 10))
 		title := text.LeftPadMaxLine(excerpt.Title, columnWidths["title"]-text.Len(labels), 1)
+		if bt.repo.IsUnread(excerpt) {
+			title = colors.Bold(title)
+		}
 		author := text.LeftPadMaxLine(authorDisplayName, columnWidths["author"], 1)
 		comments := text.LeftPadMaxLine(summaryTxt, columnWidths["comments"], 1)
 		lastEdit := text.LeftPadMaxLine(humanize.Time(lastEditTime), columnWidths["lastEdit"], 1)
 
-		_, _ = fmt.Fprintf(v, "%s %s %s%s %s %s %s\n",
-			colors.Cyan(id),
-			colors.Yellow(status),
+		_, _ = fmt.Fprintf(v, "%s %s %s %s%s %s %s %s\n",
+			colors.Green(mark),
+			colors.Id(id),
+			colors.Status(status),
 			title,
 			labels,
-			colors.Magenta(author),
+			colors.Author(author),
 			comments,
 			lastEdit,
 		)
@@ -341,6 +466,7 @@ func (bt *bugTable) render(v *gocui.View, maxX int) {
 func (bt *bugTable) renderHeader(v *gocui.View, maxX int) {
 	columnWidths := bt.getColumnWidths(maxX)
 
+	mark := text.LeftPadMaxLine("", columnWidths["mark"], 0)
 	id := text.LeftPadMaxLine("ID", columnWidths["id"], 1)
 	status := text.LeftPadMaxLine("STATUS", columnWidths["status"], 1)
 	title := text.LeftPadMaxLine("TITLE", columnWidths["title"], 1)
@@ -349,11 +475,14 @@ func (bt *bugTable) renderHeader(v *gocui.View, maxX int) {
 	lastEdit := text.LeftPadMaxLine("LAST EDIT", columnWidths["lastEdit"], 1)
 
 	_, _ = fmt.Fprintf(v, "\n")
-	_, _ = fmt.Fprintf(v, "%s %s %s %s %s %s\n", id, status, title, author, comments, lastEdit)
+	_, _ = fmt.Fprintf(v, "%s %s %s %s %s %s %s\n", mark, id, status, title, author, comments, lastEdit)
 }
 
 func (bt *bugTable) renderFooter(v *gocui.View, maxX int) {
 	_, _ = fmt.Fprintf(v, " \nShowing %d of %d bugs", len(bt.excerpts), len(bt.allIds))
+	if len(bt.marked) > 0 {
+		_, _ = fmt.Fprintf(v, ", %d marked", len(bt.marked))
+	}
 }
 
 func (bt *bugTable) cursorDown(g *gocui.Gui, v *gocui.View) error {
@@ -441,15 +570,24 @@ func (bt *bugTable) openBug(g *gocui.Gui, v *gocui.View) error {
 	if err != nil {
 		return err
 	}
+	err = bt.repo.MarkRead(id)
+	if err != nil {
+		return err
+	}
 	ui.showBug.SetBug(b)
 	return ui.activateWindow(ui.showBug)
 }
 
 func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
-	ui.msgPopup.Activate("Pull from remote "+defaultRemote, "...")
+	remote, err := bt.repo.DefaultRemote()
+	if err != nil {
+		return err
+	}
+
+	ui.msgPopup.Activate("Pull from remote "+remote, "...")
 
 	go func() {
-		stdout, err := bt.repo.Fetch(defaultRemote)
+		stdout, err := bt.repo.Fetch(remote)
 
 		if err != nil {
 			g.Update(func(gui *gocui.Gui) error {
@@ -466,7 +604,7 @@ func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
 		var buffer bytes.Buffer
 		beginLine := ""
 
-		for result := range bt.repo.MergeAll(defaultRemote) {
+		for result := range bt.repo.MergeAll(remote) {
 			if result.Status == entity.MergeStatusNothing {
 				continue
 			}
@@ -478,7 +616,7 @@ func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
 				})
 			} else {
 				_, _ = fmt.Fprintf(&buffer, "%s%s: %s",
-					beginLine, colors.Cyan(result.Entity.Id().Human()), result,
+					beginLine, colors.Id(result.Id.Human()), result,
 				)
 
 				beginLine = "\n"
@@ -503,11 +641,15 @@ func (bt *bugTable) pull(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (bt *bugTable) push(g *gocui.Gui, v *gocui.View) error {
-	ui.msgPopup.Activate("Push to remote "+defaultRemote, "...")
+	remote, err := bt.repo.DefaultRemote()
+	if err != nil {
+		return err
+	}
+
+	ui.msgPopup.Activate("Push to remote "+remote, "...")
 
 	go func() {
-		// TODO: make the remote configurable
-		stdout, err := bt.repo.Push(defaultRemote)
+		stdout, err := bt.repo.Push(remote)
 
 		if err != nil {
 			g.Update(func(gui *gocui.Gui) error {
@@ -528,3 +670,142 @@ func (bt *bugTable) push(g *gocui.Gui, v *gocui.View) error {
 func (bt *bugTable) changeQuery(g *gocui.Gui, v *gocui.View) error {
 	return editQueryWithEditor(bt)
 }
+
+func (bt *bugTable) toggleMark(g *gocui.Gui, v *gocui.View) error {
+	if bt.selectCursor >= bt.getTableLength() {
+		return nil
+	}
+
+	id := bt.excerpts[bt.selectCursor].Id
+	if bt.marked[id] {
+		delete(bt.marked, id)
+	} else {
+		bt.marked[id] = true
+	}
+
+	return bt.cursorDown(g, v)
+}
+
+// markedIds return the bugs marked for a batch action, or the selected bug
+// if none are
+func (bt *bugTable) markedIds() []entity.Id {
+	var result []entity.Id
+
+	// keep the order of the table
+	for _, id := range bt.allIds {
+		if bt.marked[id] {
+			result = append(result, id)
+		}
+	}
+
+	if len(result) == 0 && bt.selectCursor < bt.getTableLength() {
+		result = append(result, bt.excerpts[bt.selectCursor].Id)
+	}
+
+	return result
+}
+
+// applyMarked apply an action to the marked bugs, commit the operations and
+// report the result. A bug for which the action doesn't change anything is
+// skipped.
+func (bt *bugTable) applyMarked(done string, action func(b *cache.BugCache) (bool, error)) error {
+	ids := bt.markedIds()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	changed := 0
+	var failed error
+
+	err := bt.repo.Transaction(func() error {
+		for _, id := range ids {
+			b, err := bt.repo.ResolveBug(id)
+			if err != nil {
+				return err
+			}
+
+			ok, err := action(b)
+			if err != nil {
+				failed = fmt.Errorf("%s: %v", id.Human(), err)
+				return nil
+			}
+
+			if !ok {
+				continue
+			}
+
+			err = b.CommitAsNeeded()
+			if err != nil {
+				return err
+			}
+
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if failed != nil {
+		ui.msgPopup.Activate(msgPopupErrorTitle, failed.Error())
+		return nil
+	}
+
+	bt.marked = make(map[entity.Id]bool)
+
+	ui.msgPopup.Activate("Batch action", fmt.Sprintf("%d bug(s) %s, %d unchanged", changed, done, len(ids)-changed))
+
+	return nil
+}
+
+func (bt *bugTable) closeMarked(g *gocui.Gui, v *gocui.View) error {
+	return bt.applyMarked("closed", func(b *cache.BugCache) (bool, error) {
+		if b.Snapshot().Status == bug.ClosedStatus {
+			return false, nil
+		}
+		_, err := b.Close()
+		return err == nil, err
+	})
+}
+
+func (bt *bugTable) openMarked(g *gocui.Gui, v *gocui.View) error {
+	return bt.applyMarked("opened", func(b *cache.BugCache) (bool, error) {
+		if b.Snapshot().Status == bug.OpenStatus {
+			return false, nil
+		}
+		_, err := b.Open()
+		return err == nil, err
+	})
+}
+
+func (bt *bugTable) labelMarked(g *gocui.Gui, v *gocui.View) error {
+	c := ui.inputPopup.Activate("Labels to add")
+
+	go func() {
+		input := <-c
+
+		labels := strings.Fields(input)
+		if len(labels) == 0 {
+			return
+		}
+
+		g.Update(func(gui *gocui.Gui) error {
+			return bt.applyMarked("labeled", func(b *cache.BugCache) (bool, error) {
+				var missing []string
+				for _, label := range labels {
+					if !b.Snapshot().HasLabel(bug.Label(label)) {
+						missing = append(missing, label)
+					}
+				}
+				if len(missing) == 0 {
+					return false, nil
+				}
+				_, _, err := b.ChangeLabels(missing, nil)
+				return err == nil, err
+			})
+		})
+	}()
+
+	return nil
+}
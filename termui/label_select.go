@@ -8,6 +8,8 @@ import (
 
 	"github.com/MichaelMure/git-bug/bug"
 	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/i18n"
 )
 
 const labelSelectView = "labelSelectView"
@@ -30,7 +32,12 @@ func newLabelSelect() *labelSelect {
 func (ls *labelSelect) SetBug(cache *cache.RepoCache, bug *cache.BugCache) {
 	ls.cache = cache
 	ls.bug = bug
-	ls.labels = cache.ValidLabels()
+
+	// the most used labels first
+	ls.labels = nil
+	for _, usage := range cache.LabelsUsage() {
+		ls.labels = append(ls.labels, usage.Label)
+	}
 
 	// Find which labels are currently applied to the bug
 	bugLabels := bug.Snapshot().Labels
@@ -131,7 +138,7 @@ func (ls *labelSelect) layout(g *gocui.Gui) error {
 
 		lc := label.Color()
 		lc256 := lc.Term256()
-		labelStr := lc256.Escape() + "◼ " + lc256.Unescape() + label.String()
+		labelStr := colors.Wrap(lc256.Escape(), lc256.Unescape(), "◼ ") + label.String()
 		fmt.Fprint(v, selectBox, labelStr)
 
 		y0 += 2
@@ -147,7 +154,7 @@ func (ls *labelSelect) layout(g *gocui.Gui) error {
 		v.BgColor = gocui.ColorBlue
 	}
 	v.Clear()
-	fmt.Fprint(v, "[q] Save and close [↓↑,jk] Nav [a] Add item")
+	_, _ = i18n.Fprintf(v, "[q] Save and close [↓↑,jk] Nav [a] Add item")
 	if _, err = g.SetViewOnTop(labelSelectInstructionsView); err != nil {
 		return err
 	}
@@ -247,11 +254,16 @@ func (ls *labelSelect) addItem(g *gocui.Gui, v *gocui.View) error {
 		}
 
 		// Add new label, make it selected, and focus
+		suggestion, typo := ls.cache.SuggestLabel(bug.Label(input))
 		ls.labels = append(ls.labels, bug.Label(input))
 		ls.labelSelect = append(ls.labelSelect, true)
 		ls.selected = len(ls.labels) - 1
 
 		g.Update(func(g *gocui.Gui) error {
+			if typo {
+				ui.msgPopup.Activate("New label",
+					fmt.Sprintf("%s is a new label, did you mean %s?", input, suggestion))
+			}
 			return nil
 		})
 	}()
@@ -0,0 +1,208 @@
+package termui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelMure/go-term-text"
+	"github.com/russross/blackfriday/v2"
+
+	"github.com/MichaelMure/git-bug/util/colors"
+)
+
+const codeBlockIndent = "    "
+
+// renderMarkdown render a markdown text for the terminal, wrapped at the
+// given width and padded on the left. Like on the common forges, line breaks
+// within a paragraph are kept. Formatting is rendered with terminal
+// attributes, code blocks are indented and the links are gathered as
+// footnotes at the end of the text.
+func renderMarkdown(source string, width int, leftPad int) (string, int) {
+	md := blackfriday.New(blackfriday.WithExtensions(blackfriday.CommonExtensions))
+	root := md.Parse([]byte(source))
+
+	r := &markdownRenderer{width: width}
+
+	pad := strings.Repeat(" ", leftPad)
+	lines := r.blocks(root.FirstChild, pad, false)
+
+	if len(r.links) > 0 {
+		lines = append(lines, "")
+		for i, link := range r.links {
+			footnote, _ := text.Wrap(fmt.Sprintf("[%d]: %s", i+1, link), width-leftPad)
+			lines = append(lines, prefixLines(footnote, pad)...)
+		}
+	}
+
+	return strings.Join(lines, "\n"), len(lines)
+}
+
+type markdownRenderer struct {
+	width int
+	// the destination of the links, in order of appearance
+	links []string
+}
+
+// blocks render a sequence of sibling blocks, separated by an empty line
+// unless tight
+func (r *markdownRenderer) blocks(first *blackfriday.Node, prefix string, tight bool) []string {
+	var lines []string
+
+	for node := first; node != nil; node = node.Next {
+		if len(lines) > 0 && !tight {
+			lines = append(lines, strings.TrimRight(prefix, " "))
+		}
+		lines = append(lines, r.block(node, prefix)...)
+	}
+
+	return lines
+}
+
+func (r *markdownRenderer) block(node *blackfriday.Node, prefix string) []string {
+	switch node.Type {
+	case blackfriday.Paragraph:
+		return r.wrap(r.inlines(node.FirstChild), prefix)
+
+	case blackfriday.Heading:
+		marker := strings.Repeat("#", node.Level) + " "
+		return r.wrap(colors.Bold(marker+r.inlines(node.FirstChild)), prefix)
+
+	case blackfriday.CodeBlock:
+		code := strings.TrimRight(string(node.Literal), "\n")
+		var lines []string
+		for _, line := range strings.Split(code, "\n") {
+			lines = append(lines, prefix+codeBlockIndent+colors.Green(line))
+		}
+		return lines
+
+	case blackfriday.BlockQuote:
+		return r.blocks(node.FirstChild, prefix+"┃ ", false)
+
+	case blackfriday.List:
+		return r.list(node, prefix)
+
+	case blackfriday.HorizontalRule:
+		return []string{prefix + strings.Repeat("─", maxInt(r.width-text.Len(prefix), 1))}
+
+	case blackfriday.Table:
+		return r.table(node, prefix)
+
+	case blackfriday.HTMLBlock:
+		return prefixLines(strings.TrimRight(string(node.Literal), "\n"), prefix)
+
+	default:
+		return r.wrap(r.inline(node), prefix)
+	}
+}
+
+func (r *markdownRenderer) list(node *blackfriday.Node, prefix string) []string {
+	var lines []string
+
+	ordered := node.ListFlags&blackfriday.ListTypeOrdered != 0
+
+	i := 1
+	for item := node.FirstChild; item != nil; item = item.Next {
+		marker := "• "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", i)
+		}
+
+		indent := strings.Repeat(" ", text.Len(marker))
+		itemLines := r.blocks(item.FirstChild, prefix+indent, node.Tight)
+
+		if len(itemLines) > 0 {
+			itemLines[0] = prefix + marker + strings.TrimPrefix(itemLines[0], prefix+indent)
+		}
+
+		if len(lines) > 0 && !node.Tight {
+			lines = append(lines, strings.TrimRight(prefix, " "))
+		}
+		lines = append(lines, itemLines...)
+
+		i++
+	}
+
+	return lines
+}
+
+func (r *markdownRenderer) table(node *blackfriday.Node, prefix string) []string {
+	var lines []string
+
+	for section := node.FirstChild; section != nil; section = section.Next {
+		for row := section.FirstChild; row != nil; row = row.Next {
+			var cells []string
+			for cell := row.FirstChild; cell != nil; cell = cell.Next {
+				content := r.inlines(cell.FirstChild)
+				if cell.IsHeader {
+					content = colors.Bold(content)
+				}
+				cells = append(cells, content)
+			}
+			lines = append(lines, prefix+strings.Join(cells, " │ "))
+		}
+	}
+
+	return lines
+}
+
+func (r *markdownRenderer) inlines(first *blackfriday.Node) string {
+	var b strings.Builder
+	for node := first; node != nil; node = node.Next {
+		b.WriteString(r.inline(node))
+	}
+	return b.String()
+}
+
+func (r *markdownRenderer) inline(node *blackfriday.Node) string {
+	switch node.Type {
+	case blackfriday.Text, blackfriday.HTMLSpan:
+		return string(node.Literal)
+
+	case blackfriday.Softbreak:
+		return " "
+
+	case blackfriday.Hardbreak:
+		return "\n"
+
+	case blackfriday.Emph:
+		return colors.Italic(r.inlines(node.FirstChild))
+
+	case blackfriday.Strong:
+		return colors.Bold(r.inlines(node.FirstChild))
+
+	case blackfriday.Code:
+		return colors.Green(string(node.Literal))
+
+	case blackfriday.Link:
+		content := r.inlines(node.FirstChild)
+		destination := string(node.Destination)
+
+		// an autolink already display its destination
+		if content == destination || strings.TrimPrefix(destination, "mailto:") == content {
+			return colors.Blue(content)
+		}
+
+		r.links = append(r.links, destination)
+		return fmt.Sprintf("%s[%d]", colors.Blue(content), len(r.links))
+
+	case blackfriday.Image:
+		r.links = append(r.links, string(node.Destination))
+		return fmt.Sprintf("[image: %s][%d]", r.inlines(node.FirstChild), len(r.links))
+
+	default:
+		return r.inlines(node.FirstChild)
+	}
+}
+
+func (r *markdownRenderer) wrap(content string, prefix string) []string {
+	wrapped, _ := text.Wrap(content, maxInt(r.width-text.Len(prefix), 1))
+	return prefixLines(wrapped, prefix)
+}
+
+func prefixLines(content string, prefix string) []string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return lines
+}
@@ -0,0 +1,71 @@
+package termui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	color.NoColor = true
+
+	tests := []struct {
+		name     string
+		source   string
+		width    int
+		expected string
+	}{
+		{
+			name:     "paragraph",
+			width:    80,
+			source:   "some\n*formatted* **text**",
+			expected: "  some\n  formatted text",
+		},
+		{
+			name:     "heading",
+			width:    80,
+			source:   "## Title\n\ncontent",
+			expected: "  ## Title\n\n  content",
+		},
+		{
+			name:     "code block",
+			width:    80,
+			source:   "run:\n\n```\ngit bug ls\n```",
+			expected: "  run:\n\n      git bug ls",
+		},
+		{
+			name:     "link",
+			width:    80,
+			source:   "see [the doc](https://example.com/doc) or https://example.com",
+			expected: "  see the doc[1] or https://example.com\n\n  [1]: https://example.com/doc",
+		},
+		{
+			name:     "list",
+			width:    80,
+			source:   "- one\n- two\n\n1. first\n2. second",
+			expected: "  • one\n  • two\n\n  1. first\n  2. second",
+		},
+		{
+			name:     "quote",
+			width:    80,
+			source:   "> quoted",
+			expected: "  ┃ quoted",
+		},
+		{
+			name:     "wrap",
+			width:    10,
+			source:   "a long line to wrap",
+			expected: "  a long\n  line to\n  wrap",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			content, lines := renderMarkdown(tc.source, tc.width, 2)
+			require.Equal(t, tc.expected, content)
+			require.Equal(t, strings.Count(tc.expected, "\n")+1, lines)
+		})
+	}
+}
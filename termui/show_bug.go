@@ -12,6 +12,7 @@ import (
 	"github.com/MichaelMure/git-bug/cache"
 	"github.com/MichaelMure/git-bug/entity"
 	"github.com/MichaelMure/git-bug/util/colors"
+	"github.com/MichaelMure/git-bug/util/i18n"
 )
 
 const showBugView = "showBugView"
@@ -19,8 +20,15 @@ const showBugSidebarView = "showBugSidebarView"
 const showBugInstructionView = "showBugInstructionView"
 const showBugHeaderView = "showBugHeaderView"
 
+// the views of the checklist items are named after this prefix and the id
+// of the item
+const checklistItemViewPrefix = "checklistItem-"
+
 const timeLayout = "Jan 2 2006"
 
+// the indentation of a reply to a comment, for each level
+const threadIndent = 4
+
 type showBug struct {
 	cache              *cache.RepoCache
 	bug                *cache.BugCache
@@ -30,6 +38,8 @@ type showBug struct {
 	selected           string
 	isOnSide           bool
 	scroll             int
+	// display the messages as written instead of rendering the markdown
+	raw bool
 }
 
 func newShowBug(cache *cache.RepoCache) *showBug {
@@ -96,7 +106,7 @@ func (sb *showBug) layout(g *gocui.Gui) error {
 	}
 
 	v.Clear()
-	_, _ = fmt.Fprintf(v, "[q] Save and return [←↓↑→,hjkl] Navigation [o] Toggle open/close [e] Edit [c] Comment [t] Change title")
+	_, _ = i18n.Fprintf(v, "[q] Save and return [←↓↑→,hjkl] Navigation [o] Toggle open/close [e] Edit [c] Comment [t] Change title [a] Add checklist item [x] Check/uncheck [r] Toggle raw/markdown")
 
 	_, err = g.SetViewOnTop(showBugInstructionView)
 	if err != nil {
@@ -185,6 +195,22 @@ func (sb *showBug) keybindings(g *gocui.Gui) error {
 		return err
 	}
 
+	// Raw / markdown
+	if err := g.SetKeybinding(showBugView, 'r', gocui.ModNone,
+		sb.toggleRaw); err != nil {
+		return err
+	}
+
+	// Checklist
+	if err := g.SetKeybinding(showBugView, 'a', gocui.ModNone,
+		sb.addChecklistItem); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding(showBugView, 'x', gocui.ModNone,
+		sb.toggleChecklistItem); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -211,17 +237,64 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 
 	edited := ""
 	if createTimelineItem.Edited() {
-		edited = " (edited)"
+		edited = i18n.Sprintf(" (edited)")
 	}
 
-	bugHeader := fmt.Sprintf("[%s] %s\n\n[%s] %s opened this bug on %s%s",
-		colors.Cyan(snap.Id().Human()),
-		colors.Bold(snap.Title),
-		colors.Yellow(snap.Status),
-		colors.Magenta(snap.Author.DisplayName()),
+	bugHeader := i18n.Sprintf("[%s] %s\n\n[%s] %s opened this bug on %s%s",
+		colors.Id(snap.Id().Human()),
+		colors.Title(snap.Title),
+		colors.Status(snap.Status),
+		colors.Author(snap.Author.DisplayName()),
 		snap.CreatedAt.Format(timeLayout),
 		edited,
 	)
+
+	if len(snap.Voters) > 0 {
+		bugHeader += i18n.Sprintf("\n\n%d votes", len(snap.Voters))
+	}
+
+	if pending := snap.PendingReviewers(); len(pending) > 0 {
+		names := make([]string, len(pending))
+		for i, reviewer := range pending {
+			names[i] = reviewer.DisplayName()
+		}
+		bugHeader += i18n.Sprintf("\n\nwaiting for the input of %s", strings.Join(names, ", "))
+	}
+
+	if snap.DuplicateOf != "" {
+		bugHeader += i18n.Sprintf("\n\nduplicate of %s", sb.formatBugId(snap.DuplicateOf))
+	}
+
+	duplicates := sb.cache.DuplicatesOf(snap.Id())
+	if len(duplicates) > 0 {
+		formatted := make([]string, len(duplicates))
+		for i, id := range duplicates {
+			formatted[i] = sb.formatBugId(id)
+		}
+		bugHeader += i18n.Sprintf("\n\nduplicates: %s", strings.Join(formatted, ", "))
+	}
+
+	if snap.Parent != "" {
+		bugHeader += i18n.Sprintf("\n\nsub-task of %s", sb.formatBugId(snap.Parent))
+	}
+
+	children := sb.cache.ChildrenOf(snap.Id())
+	if len(children) > 0 {
+		done := 0
+		formatted := make([]string, len(children))
+		for i, id := range children {
+			status := "?"
+			if excerpt, err := sb.cache.ResolveBugExcerpt(id); err == nil {
+				status = excerpt.Status.String()
+				if excerpt.Status == bug.ClosedStatus {
+					done++
+				}
+			}
+			formatted[i] = fmt.Sprintf("  [%s] %s", colors.Status(status), sb.formatBugId(id))
+		}
+		bugHeader += i18n.Sprintf("\n\nsub-tasks (%d/%d done):\n%s", done, len(children), strings.Join(formatted, "\n"))
+	}
+
 	bugHeader, lines := text.Wrap(bugHeader, maxX)
 
 	v, err := sb.createOpView(g, showBugHeaderView, x0, y0, maxX+1, lines, false)
@@ -232,7 +305,37 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 	_, _ = fmt.Fprint(v, bugHeader)
 	y0 += lines + 1
 
-	for _, op := range snap.Timeline {
+	if len(snap.Checklist) > 0 {
+		checked, total := snap.ChecklistProgress()
+		title := i18n.Sprintf("checklist (%d/%d done):", checked, total)
+
+		v, err := sb.createOpView(g, checklistItemViewPrefix, x0, y0, maxX+1, 1, false)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(v, title)
+		y0 += 2
+
+		for _, item := range snap.Checklist {
+			box := "[ ]"
+			if item.Checked {
+				box = colors.Green("[x]")
+			}
+			content, lines := text.WrapLeftPadded(fmt.Sprintf("%s %s", box, item.Text), maxX-1, 2)
+
+			v, err := sb.createOpView(g, checklistItemViewPrefix+item.Id().String(), x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 1
+		}
+
+		y0 += 1
+	}
+
+	for _, item := range snap.ThreadedTimeline() {
+		op := item.TimelineItem
 		viewName := op.Id().String()
 
 		// TODO: me might skip the rendering of blocks that are outside of the view
@@ -247,7 +350,7 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 			if op.MessageIsEmpty() {
 				content, lines = text.WrapLeftPadded(emptyMessagePlaceholder(), maxX-1, 4)
 			} else {
-				content, lines = text.WrapLeftPadded(op.Message, maxX-1, 4)
+				content, lines = sb.renderMessage(op.Message, maxX-1, 4)
 			}
 
 			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
@@ -260,23 +363,34 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 		case *bug.AddCommentTimelineItem:
 			edited := ""
 			if op.Edited() {
-				edited = " (edited)"
+				edited = i18n.Sprintf(" (edited)")
 			}
 
+			// the replies are indented under the comment they reply to
+			pad := threadIndent * item.Depth
+
 			var message string
 			if op.MessageIsEmpty() {
-				message, _ = text.WrapLeftPadded(emptyMessagePlaceholder(), maxX-1, 4)
+				message, _ = text.WrapLeftPadded(emptyMessagePlaceholder(), maxX-1, 4+pad)
 			} else {
-				message, _ = text.WrapLeftPadded(op.Message, maxX-1, 4)
+				message, _ = sb.renderMessage(op.Message, maxX-1, 4+pad)
+			}
+
+			action := "commented"
+			if item.Depth > 0 {
+				action = "replied"
 			}
 
-			content := fmt.Sprintf("%s commented on %s%s\n\n%s",
-				colors.Magenta(op.Author.DisplayName()),
+			header := fmt.Sprintf("%s%s %s on %s%s",
+				strings.Repeat(" ", pad),
+				colors.Author(op.Author.DisplayName()),
+				action,
 				op.CreatedAt.Time().Format(timeLayout),
 				edited,
-				message,
 			)
-			content, lines = text.Wrap(content, maxX)
+			header, _ = text.Wrap(header, maxX)
+
+			content, lines := text.Wrap(header+"\n\n"+message, maxX)
 
 			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
 			if err != nil {
@@ -287,8 +401,8 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 
 		case *bug.SetTitleTimelineItem:
 			content := fmt.Sprintf("%s changed the title to %s on %s",
-				colors.Magenta(op.Author.DisplayName()),
-				colors.Bold(op.Title),
+				colors.Author(op.Author.DisplayName()),
+				colors.Title(op.Title),
 				op.UnixTime.Time().Format(timeLayout),
 			)
 			content, lines := text.Wrap(content, maxX)
@@ -301,9 +415,204 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 			y0 += lines + 2
 
 		case *bug.SetStatusTimelineItem:
+			action := colors.Bold(op.Status.Action())
+			if op.Resolution != bug.NoResolution {
+				action = fmt.Sprintf("%s as %s", action, colors.Bold(op.Resolution.String()))
+			}
 			content := fmt.Sprintf("%s %s the bug on %s",
-				colors.Magenta(op.Author.DisplayName()),
-				colors.Bold(op.Status.Action()),
+				colors.Author(op.Author.DisplayName()),
+				action,
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.MarkDuplicateTimelineItem:
+			content := fmt.Sprintf("%s marked the bug as duplicate of %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				sb.formatBugId(op.Target),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.SetParentTimelineItem:
+			content := fmt.Sprintf("%s made the bug a sub-task of %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				sb.formatBugId(op.Parent),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.RemoveParentTimelineItem:
+			content := fmt.Sprintf("%s removed the bug from the sub-tasks of %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				sb.formatBugId(op.Was),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.AddChecklistItemTimelineItem:
+			content := fmt.Sprintf("%s added %s to the checklist on %s",
+				colors.Author(op.Author.DisplayName()),
+				colors.Bold(op.Text),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.CheckItemTimelineItem:
+			action := "checked"
+			if !op.Checked {
+				action = "unchecked"
+			}
+			content := fmt.Sprintf("%s %s %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				action,
+				colors.Bold(op.Text),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.VoteTimelineItem:
+			action := "voted for the bug"
+			if op.Retract {
+				action = "retracted their vote"
+			}
+			content := fmt.Sprintf("%s %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				action,
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.RequestReviewTimelineItem:
+			names := make([]string, len(op.Reviewers))
+			for i, reviewer := range op.Reviewers {
+				names[i] = reviewer.DisplayName()
+			}
+			content := fmt.Sprintf("%s requested the review of %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				colors.Bold(strings.Join(names, ", ")),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			if op.Message != "" {
+				content += "\n\n" + op.Message
+			}
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.UnknownTimelineItem:
+			content := fmt.Sprintf("%s did an operation unknown to this version of git-bug on %s",
+				colors.Author(op.Author.DisplayName()),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.SetComponentTimelineItem:
+			content := fmt.Sprintf("%s moved the bug to the component %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				colors.Bold(op.Component),
+				op.UnixTime.Time().Format(timeLayout),
+			)
+			content, lines := text.Wrap(content, maxX)
+
+			v, err := sb.createOpView(g, viewName, x0, y0, maxX+1, lines, true)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(v, content)
+			y0 += lines + 2
+
+		case *bug.AssigneeChangeTimelineItem:
+			var added []string
+			for _, assignee := range op.Added {
+				added = append(added, colors.Bold(assignee.DisplayName()))
+			}
+
+			var removed []string
+			for _, assignee := range op.Removed {
+				removed = append(removed, colors.Bold(assignee.DisplayName()))
+			}
+
+			var action bytes.Buffer
+
+			if len(added) > 0 {
+				action.WriteString("assigned ")
+				action.WriteString(strings.Join(added, ", "))
+
+				if len(removed) > 0 {
+					action.WriteString(" and ")
+				}
+			}
+
+			if len(removed) > 0 {
+				action.WriteString("unassigned ")
+				action.WriteString(strings.Join(removed, ", "))
+			}
+
+			content := fmt.Sprintf("%s %s on %s",
+				colors.Author(op.Author.DisplayName()),
+				action.String(),
 				op.UnixTime.Time().Format(timeLayout),
 			)
 			content, lines := text.Wrap(content, maxX)
@@ -349,7 +658,7 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 			}
 
 			content := fmt.Sprintf("%s %s on %s",
-				colors.Magenta(op.Author.DisplayName()),
+				colors.Author(op.Author.DisplayName()),
 				action.String(),
 				op.UnixTime.Time().Format(timeLayout),
 			)
@@ -367,9 +676,18 @@ func (sb *showBug) renderMain(g *gocui.Gui, mainView *gocui.View) error {
 	return nil
 }
 
+// renderMessage render the message of a comment, as markdown unless the raw
+// display is toggled
+func (sb *showBug) renderMessage(message string, maxX int, leftPad int) (string, int) {
+	if sb.raw {
+		return text.WrapLeftPadded(message, maxX, leftPad)
+	}
+	return renderMarkdown(message, maxX, leftPad)
+}
+
 // emptyMessagePlaceholder return a formatted placeholder for an empty message
 func emptyMessagePlaceholder() string {
-	return colors.GreyBold("No description provided.")
+	return colors.Muted("No description provided.")
 }
 
 func (sb *showBug) createOpView(g *gocui.Gui, name string, x0 int, y0 int, maxX int, height int, selectable bool) (*gocui.View, error) {
@@ -409,6 +727,15 @@ func (sb *showBug) createSideView(g *gocui.Gui, name string, x0 int, y0 int, max
 	return v, nil
 }
 
+// formatBugId describe a bug with its id and title, if available locally
+func (sb *showBug) formatBugId(id entity.Id) string {
+	excerpt, err := sb.cache.ResolveBugExcerpt(id)
+	if err != nil {
+		return colors.Id(id.Human())
+	}
+	return fmt.Sprintf("%s %s", colors.Id(id.Human()), colors.Title(excerpt.Title))
+}
+
 func (sb *showBug) renderSidebar(g *gocui.Gui, sideView *gocui.View) error {
 	maxX, _ := sideView.Size()
 	x0, y0, _, _, _ := g.ViewPosition(sideView.Name())
@@ -422,7 +749,7 @@ func (sb *showBug) renderSidebar(g *gocui.Gui, sideView *gocui.View) error {
 	for i, l := range snap.Labels {
 		lc := l.Color()
 		lc256 := lc.Term256()
-		labelStr[i] = lc256.Escape() + "◼ " + lc256.Unescape() + l.String()
+		labelStr[i] = colors.Wrap(lc256.Escape(), lc256.Unescape(), "◼ ") + l.String()
 	}
 
 	labels := strings.Join(labelStr, "\n")
@@ -608,6 +935,11 @@ func (sb *showBug) comment(g *gocui.Gui, v *gocui.View) error {
 	return addCommentWithEditor(sb.bug)
 }
 
+func (sb *showBug) toggleRaw(g *gocui.Gui, v *gocui.View) error {
+	sb.raw = !sb.raw
+	return nil
+}
+
 func (sb *showBug) setTitle(g *gocui.Gui, v *gocui.View) error {
 	return setTitleWithEditor(sb.bug)
 }
@@ -636,6 +968,10 @@ func (sb *showBug) edit(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
+	if strings.HasPrefix(sb.selected, checklistItemViewPrefix) {
+		return sb.toggleChecklistItem(g, v)
+	}
+
 	op, err := snap.SearchTimelineItem(entity.Id(sb.selected))
 	if err != nil {
 		return err
@@ -658,3 +994,41 @@ func (sb *showBug) editLabels(g *gocui.Gui, snap *bug.Snapshot) error {
 	ui.labelSelect.SetBug(sb.cache, sb.bug)
 	return ui.activateWindow(ui.labelSelect)
 }
+
+func (sb *showBug) addChecklistItem(g *gocui.Gui, v *gocui.View) error {
+	c := ui.inputPopup.Activate("Add a checklist item")
+
+	go func() {
+		input := strings.TrimSpace(<-c)
+		if input == "" {
+			return
+		}
+
+		g.Update(func(g *gocui.Gui) error {
+			_, err := sb.bug.AddChecklistItem(input)
+			if err != nil {
+				ui.msgPopup.Activate(msgPopupErrorTitle, err.Error())
+			}
+			return nil
+		})
+	}()
+
+	return nil
+}
+
+func (sb *showBug) toggleChecklistItem(g *gocui.Gui, v *gocui.View) error {
+	if sb.isOnSide || !strings.HasPrefix(sb.selected, checklistItemViewPrefix) {
+		ui.msgPopup.Activate(msgPopupErrorTitle, "Select a checklist item to check or uncheck it.")
+		return nil
+	}
+
+	id := entity.Id(strings.TrimPrefix(sb.selected, checklistItemViewPrefix))
+
+	item, err := sb.bug.Snapshot().SearchChecklistItem(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = sb.bug.CheckItem(id, !item.Checked)
+	return err
+}
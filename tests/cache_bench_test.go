@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/MichaelMure/git-bug/bug"
+	"github.com/MichaelMure/git-bug/cache"
+	"github.com/MichaelMure/git-bug/misc/random_bugs"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func benchmarkBuildCache(bugNumber int, t *testing.B) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	random_bugs.FillRepoWithSeed(repo, bugNumber, 42)
+	t.ResetTimer()
+
+	for n := 0; n < t.N; n++ {
+		t.StopTimer()
+		if err := cache.RemoveCacheFiles(repo); err != nil {
+			t.Fatal(err)
+		}
+		t.StartTimer()
+
+		c, err := cache.NewRepoCache(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.StopTimer()
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+		t.StartTimer()
+	}
+}
+
+func BenchmarkBuildCache5(b *testing.B)   { benchmarkBuildCache(5, b) }
+func BenchmarkBuildCache25(b *testing.B)  { benchmarkBuildCache(25, b) }
+func BenchmarkBuildCache150(b *testing.B) { benchmarkBuildCache(150, b) }
+
+func benchmarkQueryBugs(bugNumber int, t *testing.B) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	random_bugs.FillRepoWithSeed(repo, bugNumber, 42)
+
+	c, err := cache.NewRepoCache(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	query, err := cache.ParseQuery("status:open label:bug no:assignee sort:edit-asc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.ResetTimer()
+
+	for n := 0; n < t.N; n++ {
+		c.QueryBugs(query)
+	}
+}
+
+func BenchmarkQueryBugs5(b *testing.B)   { benchmarkQueryBugs(5, b) }
+func BenchmarkQueryBugs25(b *testing.B)  { benchmarkQueryBugs(25, b) }
+func BenchmarkQueryBugs150(b *testing.B) { benchmarkQueryBugs(150, b) }
+
+func benchmarkReplayBugs(bugNumber int, t *testing.B) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	random_bugs.FillRepoWithSeed(repo, bugNumber, 42)
+
+	var bugs []*bug.Bug
+	for b := range bug.ReadAllLocalBugs(repo) {
+		if b.Err != nil {
+			t.Fatal(b.Err)
+		}
+		bugs = append(bugs, b.Bug)
+	}
+	t.ResetTimer()
+
+	for n := 0; n < t.N; n++ {
+		for _, b := range bugs {
+			b.Compile()
+		}
+	}
+}
+
+func BenchmarkReplayBugs5(b *testing.B)   { benchmarkReplayBugs(5, b) }
+func BenchmarkReplayBugs25(b *testing.B)  { benchmarkReplayBugs(25, b) }
+func BenchmarkReplayBugs150(b *testing.B) { benchmarkReplayBugs(150, b) }
@@ -14,11 +14,17 @@ func TestReadBugs(t *testing.T) {
 
 	random_bugs.FillRepoWithSeed(repo, 15, 42)
 
+	count := 0
 	bugs := bug.ReadAllLocalBugs(repo)
 	for b := range bugs {
 		if b.Err != nil {
 			t.Fatal(b.Err)
 		}
+		count++
+	}
+
+	if count != 15 {
+		t.Fatalf("expected 15 bugs, got %d", count)
 	}
 }
 
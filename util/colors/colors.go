@@ -4,6 +4,7 @@ import "github.com/fatih/color"
 
 var (
 	Bold       = color.New(color.Bold).SprintFunc()
+	Italic     = color.New(color.Italic).SprintFunc()
 	Black      = color.New(color.FgBlack).SprintFunc()
 	BlackBg    = color.New(color.BgBlack, color.FgWhite).SprintFunc()
 	White      = color.New(color.FgWhite).SprintFunc()
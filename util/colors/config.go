@@ -0,0 +1,207 @@
+package colors
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// NoColorEnv is the environment variable disabling the colors when set, see
+// https://no-color.org
+const NoColorEnv = "NO_COLOR"
+
+// The modes of the colors: auto colors the output of a terminal only, unless
+// NO_COLOR is set
+const (
+	ModeAuto   = "auto"
+	ModeAlways = "always"
+	ModeNever  = "never"
+)
+
+// Element is a part of the output of ls, show and the termui whose color can
+// be overridden, as with git-bug.color.status="bold green" in the git config
+type Element string
+
+const (
+	ElementId      Element = "id"
+	ElementStatus  Element = "status"
+	ElementAuthor  Element = "author"
+	ElementTitle   Element = "title"
+	ElementMuted   Element = "muted"
+	ElementWarning Element = "warning"
+)
+
+var defaults = map[Element][]color.Attribute{
+	ElementId:      {color.FgCyan},
+	ElementStatus:  {color.FgYellow},
+	ElementAuthor:  {color.FgMagenta},
+	ElementTitle:   {color.Bold},
+	ElementMuted:   {color.BgBlack, color.Bold},
+	ElementWarning: {color.FgRed},
+}
+
+// the detection of the terminal, done by the color package when loaded
+var terminalNoColor = color.NoColor
+
+var (
+	mu        sync.RWMutex
+	overrides = make(map[Element][]color.Attribute)
+)
+
+// The colors of the elements, with their overrides
+var (
+	Id      = element(ElementId)
+	Status  = element(ElementStatus)
+	Author  = element(ElementAuthor)
+	Title   = element(ElementTitle)
+	Muted   = element(ElementMuted)
+	Warning = element(ElementWarning)
+)
+
+func element(e Element) func(a ...interface{}) string {
+	return func(a ...interface{}) string {
+		mu.RLock()
+		attrs, ok := overrides[e]
+		mu.RUnlock()
+		if !ok {
+			attrs = defaults[e]
+		}
+		if len(attrs) == 0 {
+			return fmt.Sprint(a...)
+		}
+		return color.New(attrs...).Sprint(a...)
+	}
+}
+
+// Elements return the elements whose color can be overridden
+func Elements() []Element {
+	result := make([]Element, 0, len(defaults))
+	for e := range defaults {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// SetMode enable or disable the colors, with ModeAuto, ModeAlways or
+// ModeNever. An empty mode is ModeAuto.
+func SetMode(mode string) error {
+	switch mode {
+	case ModeAlways:
+		color.NoColor = false
+	case ModeNever:
+		color.NoColor = true
+	case ModeAuto, "":
+		color.NoColor = terminalNoColor || os.Getenv(NoColorEnv) != ""
+	default:
+		return fmt.Errorf("invalid color mode %q, expected %s, %s or %s",
+			mode, ModeAuto, ModeAlways, ModeNever)
+	}
+	return nil
+}
+
+// Enabled tell if the output is colored
+func Enabled() bool {
+	return !color.NoColor
+}
+
+// Override change the color of an element, with a specification as in the
+// git config: "normal" or up to two colors, the foreground then the
+// background, and attributes, as "bold red black" or "ul blue".
+func Override(name string, spec string) error {
+	e := Element(strings.ToLower(name))
+	if _, ok := defaults[e]; !ok {
+		var names []string
+		for _, e := range Elements() {
+			names = append(names, string(e))
+		}
+		return fmt.Errorf("unknown color element %s, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	attrs, err := parseSpec(spec)
+	if err != nil {
+		return fmt.Errorf("invalid color of %s: %v", name, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	overrides[e] = attrs
+	return nil
+}
+
+// ResetOverrides drop the overrides of the colors of the elements
+func ResetOverrides() {
+	mu.Lock()
+	defer mu.Unlock()
+	overrides = make(map[Element][]color.Attribute)
+}
+
+var colorNames = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+var attributeNames = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"dim":       color.Faint,
+	"italic":    color.Italic,
+	"ul":        color.Underline,
+	"underline": color.Underline,
+	"blink":     color.BlinkSlow,
+	"reverse":   color.ReverseVideo,
+}
+
+func parseSpec(spec string) ([]color.Attribute, error) {
+	var result []color.Attribute
+	colorCount := 0
+
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		if word == "normal" {
+			// the first color is the foreground, normal keeps the default
+			colorCount++
+			continue
+		}
+
+		if attr, ok := attributeNames[word]; ok {
+			result = append(result, attr)
+			continue
+		}
+
+		c, ok := colorNames[word]
+		if !ok {
+			return nil, fmt.Errorf("unknown color or attribute %q", word)
+		}
+
+		switch colorCount {
+		case 0:
+			result = append(result, c)
+		case 1:
+			// the background attributes are offset from the foreground ones
+			result = append(result, c-color.FgBlack+color.BgBlack)
+		default:
+			return nil, fmt.Errorf("more than two colors in %q", spec)
+		}
+		colorCount++
+	}
+
+	return result, nil
+}
+
+// Wrap surround a text with escape sequences, as a color of a label, when the
+// output is colored
+func Wrap(escape string, unescape string, s string) string {
+	if color.NoColor {
+		return s
+	}
+	return escape + s + unescape
+}
@@ -0,0 +1,55 @@
+package colors
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetMode(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+
+	require.NoError(t, SetMode(ModeAlways))
+	require.True(t, Enabled())
+	require.Equal(t, "\x1b[36mabcd\x1b[0m", Id("abcd"))
+
+	require.NoError(t, SetMode(ModeNever))
+	require.False(t, Enabled())
+	require.Equal(t, "abcd", Id("abcd"))
+	require.Equal(t, "◼", Wrap("\x1b[38;5;1m", "\x1b[0m", "◼"))
+
+	require.NoError(t, os.Setenv(NoColorEnv, "1"))
+	defer os.Unsetenv(NoColorEnv)
+	require.NoError(t, SetMode(ModeAuto))
+	require.False(t, Enabled())
+
+	require.Error(t, SetMode("sometimes"))
+}
+
+func TestOverride(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	defer ResetOverrides()
+
+	color.NoColor = false
+
+	require.NoError(t, Override("status", "bold green black"))
+	require.Equal(t, "\x1b[1;32;40mopen\x1b[0m", Status("open"))
+
+	require.NoError(t, Override("Status", "normal blue"))
+	require.Equal(t, "\x1b[44mopen\x1b[0m", Status("open"))
+
+	require.NoError(t, Override("status", "normal"))
+	require.Equal(t, "open", Status("open"))
+
+	// the other elements keep their default
+	require.Equal(t, "\x1b[35mrene\x1b[0m", Author("rene"))
+
+	require.Error(t, Override("nothing", "red"))
+	require.Error(t, Override("status", "purple"))
+	require.Error(t, Override("status", "red green blue"))
+
+	ResetOverrides()
+	require.Equal(t, "\x1b[33mopen\x1b[0m", Status("open"))
+}
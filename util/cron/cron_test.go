@@ -0,0 +1,56 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+		require.NoError(t, err)
+		return d
+	}
+
+	cases := []struct {
+		spec string
+		at   string
+		prev string
+		next string
+	}{
+		{"@monthly", "2020-03-15 10:30", "2020-03-01 00:00", "2020-04-01 00:00"},
+		{"0 9 1 * *", "2020-03-01 09:00", "2020-03-01 09:00", "2020-04-01 09:00"},
+		{"*/15 * * * *", "2020-03-01 09:07", "2020-03-01 09:00", "2020-03-01 09:15"},
+		{"30 8 * * 1-5", "2020-03-07 12:00", "2020-03-06 08:30", "2020-03-09 08:30"},
+		{"0 0 * * 7", "2020-03-04 00:00", "2020-03-01 00:00", "2020-03-08 00:00"},
+		{"0 0 1,15 * *", "2020-03-10 00:00", "2020-03-01 00:00", "2020-03-15 00:00"},
+		{"0 12 29 2 *", "2021-01-01 00:00", "2020-02-29 12:00", "2024-02-29 12:00"},
+		// the day of the month or the day of the week
+		{"0 0 13 * 5", "2020-03-10 00:00", "2020-03-06 00:00", "2020-03-13 00:00"},
+	}
+
+	for _, c := range cases {
+		s, err := Parse(c.spec)
+		require.NoError(t, err, c.spec)
+
+		prev, ok := s.Prev(date(c.at))
+		require.True(t, ok, c.spec)
+		require.Equal(t, date(c.prev), prev, c.spec)
+
+		next, ok := s.Next(date(c.at))
+		require.True(t, ok, c.spec)
+		require.Equal(t, date(c.next), next, c.spec)
+	}
+
+	never, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+	_, ok := never.Next(date("2020-01-01 00:00"))
+	require.False(t, ok)
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := Parse(spec)
+		require.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,73 @@
+package i18n
+
+import "golang.org/x/text/language"
+
+func init() {
+	register(language.French, map[string]string{
+		// commands
+		"%s created\n":             "%s créé\n",
+		"Empty title, aborting.":   "Titre vide, abandon.",
+		"Empty message, aborting.": "Message vide, abandon.",
+		"No change, aborting.":     "Aucun changement, abandon.",
+		"component %s, from the paths mentioned in the description\n": "composant %s, d'après les chemins mentionnés dans la description\n",
+		"%s is required\n":                                   "%s est obligatoire\n",
+		"label %s, required by the repository\n":             "étiquette %s, exigée par le dépôt\n",
+		"Pulling identities from the identity directory ...": "Récupération des identités depuis l'annuaire ...",
+		"Fetching remote ...":                                "Récupération du dépôt distant ...",
+		"Merging data ...":                                   "Fusion des données ...",
+		"Already up to date.":                                "Déjà à jour.",
+
+		// show
+		"%s opened this issue %s\n\n": "%s a ouvert ce bug %s\n\n",
+		"This bug has operations unknown to this version of git-bug, update git-bug to see and edit them.": "Ce bug a des opérations inconnues de cette version de git-bug, mettez git-bug à jour pour les voir et les modifier.",
+		"labels: %s\n":             "étiquettes : %s\n",
+		"resolution: %s\n":         "résolution : %s\n",
+		"component: %s\n":          "composant : %s\n",
+		"fields: %s\n":             "champs : %s\n",
+		"assignees: %s\n":          "assignés : %s\n",
+		"duplicate of: %s\n":       "doublon de : %s\n",
+		"duplicates: %s\n":         "doublons : %s\n",
+		"parent: %s\n":             "parent : %s\n",
+		"sub-tasks: %d/%d done\n":  "sous-tâches : %d/%d faites\n",
+		"votes: %d\n":              "votes : %d\n",
+		"waiting for: %s\n":        "en attente de : %s\n",
+		"checklist: %d/%d done\n":  "liste de contrôle : %d/%d faits\n",
+		"actors: %s\n":             "acteurs : %s\n",
+		"participants: %s\n\n":     "participants : %s\n\n",
+		"references:":              "références :",
+		" in reply to #%d":         " en réponse à #%d",
+		"No description provided.": "Aucune description fournie.",
+
+		// termui
+		"[q] Quit [s] Search [←↓↑→,hjkl] Navigation [↵] Open bug [n] New bug [i] Pull [o] Push [space] Mark [C] Close [O] Open [L] Label [v] Split view [<>] Resize": "[q] Quitter [s] Rechercher [←↓↑→,hjkl] Navigation [↵] Ouvrir le bug [n] Nouveau bug [i] Pull [o] Push [espace] Marquer [C] Fermer [O] Ouvrir [L] Étiquette [v] Vue partagée [<>] Redimensionner",
+		"[q] Save and close [↓↑,jk] Nav [a] Add item": "[q] Enregistrer et fermer [↓↑,jk] Navigation [a] Ajouter",
+		"[q] Save and return [←↓↑→,hjkl] Navigation [o] Toggle open/close [e] Edit [c] Comment [t] Change title [a] Add checklist item [x] Check/uncheck [r] Toggle raw/markdown": "[q] Enregistrer et revenir [←↓↑→,hjkl] Navigation [o] Ouvrir/fermer [e] Modifier [c] Commenter [t] Changer le titre [a] Ajouter un élément [x] Cocher/décocher [r] Texte brut/markdown",
+		"[%s] %s %s opened this issue on %s\n":       "[%s] %s %s a ouvert ce bug le %s\n",
+		"[%s] %s\n\n[%s] %s opened this bug on %s%s": "[%s] %s\n\n[%s] %s a ouvert ce bug le %s%s",
+		" (edited)":                       " (modifié)",
+		"\n\n%d votes":                    "\n\n%d votes",
+		"\n\nwaiting for the input of %s": "\n\nen attente de l'avis de %s",
+		"\n\nduplicate of %s":             "\n\ndoublon de %s",
+		"\n\nduplicates: %s":              "\n\ndoublons : %s",
+		"\n\nsub-task of %s":              "\n\nsous-tâche de %s",
+		"\n\nsub-tasks (%d/%d done):\n%s": "\n\nsous-tâches (%d/%d faites) :\n%s",
+		"checklist (%d/%d done):":         "liste de contrôle (%d/%d faits) :",
+
+		// termui, accessible mode
+		"git-bug, accessible mode. Type help for the commands.\n": "git-bug, mode accessible. Tapez help pour la liste des commandes.\n",
+		"%d bug(s) match %s.\n":                                   "%d bug(s) correspondent à %s.\n",
+		"End of the list.\n":                                      "Fin de la liste.\n",
+		"Start of the list.\n":                                    "Début de la liste.\n",
+		"No more comments.\n":                                     "Plus de commentaires.\n",
+		"This is the description.\n":                              "Ceci est la description.\n",
+		"Comment %d added.\n":                                     "Commentaire %d ajouté.\n",
+		"Title changed to %s.\n":                                  "Titre changé en %s.\n",
+		"Bug %s is now %s.\n":                                     "Le bug %s est maintenant %s.\n",
+		"Item %d checked: %s.\n":                                  "Élément %d coché : %s.\n",
+		"Item %d unchecked: %s.\n":                                "Élément %d décoché : %s.\n",
+		"Back to the list of bugs.\n":                             "Retour à la liste des bugs.\n",
+		"Bug %s created.\n":                                       "Bug %s créé.\n",
+		"Error: %s\n":                                             "Erreur : %s\n",
+		"Bye.\n":                                                  "Au revoir.\n",
+	})
+}
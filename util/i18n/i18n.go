@@ -0,0 +1,138 @@
+// Package i18n translate the messages of the user interfaces of git-bug.
+//
+// The messages are written in English in the code, and are the keys of the
+// catalogs of translations: a message without translation in the current
+// language is printed as is.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// LangEnv is the environment variable overriding the language detected from
+// the locale
+const LangEnv = "GIT_BUG_LANG"
+
+// the catalogs of translations, by language, registered by the catalog_*.go
+// files
+var catalogs = map[language.Tag]map[string]string{}
+
+var (
+	mu      sync.RWMutex
+	current = language.English
+	// nil for English, the messages being formatted as is
+	printer *message.Printer
+	builder = catalog.NewBuilder(catalog.Fallback(language.English))
+)
+
+func register(tag language.Tag, messages map[string]string) {
+	catalogs[tag] = messages
+	for key, msg := range messages {
+		if err := builder.SetString(tag, key, msg); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Languages return the supported languages, English first
+func Languages() []string {
+	var result []string
+	for tag := range catalogs {
+		result = append(result, tag.String())
+	}
+	sort.Strings(result)
+	return append([]string{language.English.String()}, result...)
+}
+
+// Detect return the language of the user, from GIT_BUG_LANG or the locale of
+// the environment, as "fr" for "fr_FR.UTF-8". It's empty if none is set.
+func Detect() string {
+	for _, env := range []string{LangEnv, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		// the C locale is the absence of locale
+		if value == "" || value == "C" || value == "POSIX" {
+			continue
+		}
+		value = strings.SplitN(value, ".", 2)[0]
+		value = strings.SplitN(value, "@", 2)[0]
+		return strings.Replace(value, "_", "-", -1)
+	}
+	return ""
+}
+
+// SetLanguage select the language of the messages, as "fr" or "fr-CA". An
+// empty language select English.
+func SetLanguage(lang string) error {
+	tag := language.English
+
+	if lang != "" {
+		parsed, err := language.Parse(lang)
+		if err != nil {
+			return fmt.Errorf("invalid language %q", lang)
+		}
+
+		supported := []language.Tag{language.English}
+		for t := range catalogs {
+			supported = append(supported, t)
+		}
+		matched, _, confidence := language.NewMatcher(supported).Match(parsed)
+		if confidence == language.No {
+			return fmt.Errorf("unsupported language %s, supported languages are %s",
+				lang, strings.Join(Languages(), ", "))
+		}
+		// the matcher may return a variant of a supported tag
+		base, _ := matched.Base()
+		tag = language.Make(base.String())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = tag
+	printer = nil
+	if tag != language.English {
+		printer = message.NewPrinter(tag, message.Catalog(builder))
+	}
+	return nil
+}
+
+// Language return the language of the messages
+func Language() language.Tag {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Sprintf translate and format a message, as fmt.Sprintf
+func Sprintf(format string, args ...interface{}) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if printer == nil {
+		return fmt.Sprintf(format, args...)
+	}
+	return printer.Sprintf(format, args...)
+}
+
+// Printf translate and print a message on the standard output, as fmt.Printf
+func Printf(format string, args ...interface{}) {
+	_, _ = fmt.Print(Sprintf(format, args...))
+}
+
+// Fprintf translate and write a message, as fmt.Fprintf
+func Fprintf(w io.Writer, format string, args ...interface{}) (int, error) {
+	return io.WriteString(w, Sprintf(format, args...))
+}
+
+// Println translate a message without argument and print it on the standard
+// output with a new line
+func Println(msg string) {
+	_, _ = fmt.Println(Sprintf(msg))
+}
@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestDetect(t *testing.T) {
+	for _, env := range []string{LangEnv, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		old, ok := os.LookupEnv(env)
+		if ok {
+			defer os.Setenv(env, old)
+		} else {
+			defer os.Unsetenv(env)
+		}
+		require.NoError(t, os.Unsetenv(env))
+	}
+
+	require.Equal(t, "", Detect())
+
+	require.NoError(t, os.Setenv("LANG", "C"))
+	require.Equal(t, "", Detect())
+
+	require.NoError(t, os.Setenv("LANG", "fr_FR.UTF-8"))
+	require.Equal(t, "fr-FR", Detect())
+
+	require.NoError(t, os.Setenv("LC_ALL", "de_DE@euro"))
+	require.Equal(t, "de-DE", Detect())
+
+	require.NoError(t, os.Setenv(LangEnv, "en"))
+	require.Equal(t, "en", Detect())
+}
+
+func TestSetLanguage(t *testing.T) {
+	defer SetLanguage("")
+
+	require.NoError(t, SetLanguage("fr"))
+	require.Equal(t, language.French, Language())
+	require.Equal(t, "Titre vide, abandon.", Sprintf("Empty title, aborting."))
+	require.Equal(t, "abcd créé\n", Sprintf("%s created\n", "abcd"))
+	// a message without translation falls back to English
+	require.Equal(t, "untranslated 3", Sprintf("untranslated %d", 3))
+
+	require.NoError(t, SetLanguage("fr-FR"))
+	require.Equal(t, language.French, Language())
+
+	require.Error(t, SetLanguage("de"))
+	require.Error(t, SetLanguage("not a language"))
+	require.Equal(t, language.French, Language())
+
+	require.NoError(t, SetLanguage(""))
+	require.Equal(t, language.English, Language())
+	require.Equal(t, "Empty title, aborting.", Sprintf("Empty title, aborting."))
+}
+
+func TestCatalogs(t *testing.T) {
+	require.Equal(t, []string{"en", "fr"}, Languages())
+
+	for tag, messages := range catalogs {
+		for key, msg := range messages {
+			require.Equal(t, strings.Count(key, "%"), strings.Count(msg, "%"),
+				"the %s translation of %q doesn't have the same verbs", tag, key)
+		}
+	}
+}
@@ -5,11 +5,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
+// Persisted is a Lamport clock persisted in a file. As the Clock, it's safe
+// for concurrent use.
 type Persisted struct {
 	Clock
 	filePath string
+
+	// serialize the writes of the file
+	mu sync.Mutex
 }
 
 // NewPersisted create a new persisted Lamport clock
@@ -79,6 +85,11 @@ func (c *Persisted) read() error {
 }
 
 func (c *Persisted) Write() error {
-	data := []byte(fmt.Sprintf("%d", c.counter))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// read the value once the lock held, for a concurrent write to not
+	// persist an older value
+	data := []byte(fmt.Sprintf("%d", c.Time()))
 	return ioutil.WriteFile(c.filePath, data, 0644)
 }
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bytes"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+const indexFile = "/index.html"
+
+// assetsHandler is a http.Handler serving the files of the web UI, and
+// index.html for any other path, as the web UI is a Single-Page App
+// implementing its routing client side.
+//
+// The prefix the web UI is served at is given to it with a <base> element
+// added to index.html, for its routes and its requests to the API.
+type assetsHandler struct {
+	fs     http.FileSystem
+	prefix string
+}
+
+func newAssetsHandler(fs http.FileSystem, prefix string) http.Handler {
+	return &assetsHandler{
+		fs:     fs,
+		prefix: prefix,
+	}
+}
+
+func (ah *assetsHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + strings.TrimPrefix(r.URL.Path, ah.prefix))
+
+	if name != indexFile {
+		f, err := ah.fs.Open(name)
+		if err != nil && !os.IsNotExist(err) {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err == nil {
+			defer f.Close()
+
+			stat, err := f.Stat()
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !stat.IsDir() {
+				http.ServeContent(rw, r, stat.Name(), stat.ModTime(), f)
+				return
+			}
+		}
+	}
+
+	ah.serveIndex(rw)
+}
+
+func (ah *assetsHandler) serveIndex(rw http.ResponseWriter) {
+	f, err := ah.fs.Open(indexFile)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	base := []byte(`<base href="` + html.EscapeString(ah.prefix) + `">`)
+	if i := bytes.Index(data, []byte("<head>")); i >= 0 {
+		i += len("<head>")
+		data = append(data[:i:i], append(base, data[i:]...)...)
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// the page depend on the prefix, it must not be cached as a file
+	rw.Header().Set("Cache-Control", "no-cache")
+	_, _ = rw.Write(data)
+}
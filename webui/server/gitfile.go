@@ -0,0 +1,140 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MichaelMure/git-bug/graphql/auth"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/util/git"
+)
+
+// implement a http.Handler that will read and server git blob, from any of
+// the repositories served.
+type gitFileHandler struct {
+	repos map[string]repository.Repo
+}
+
+func newGitFileHandler(repos map[string]repository.Repo) http.Handler {
+	return &gitFileHandler{
+		repos: repos,
+	}
+}
+
+func (gfh *gitFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	hash := git.Hash(mux.Vars(r)["hash"])
+
+	if !hash.IsValid() {
+		http.Error(rw, "invalid git hash", http.StatusBadRequest)
+		return
+	}
+
+	// TODO: this mean that the whole file will he buffered in memory
+	// This can be a problem for big files. There might be a way around
+	// that by implementing a io.ReadSeeker that would read and discard
+	// data when a seek is called.
+	var data []byte
+	var err error
+	for _, repo := range gfh.repos {
+		data, err = repo.ReadData(git.Hash(hash))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.ServeContent(rw, r, "", time.Now(), bytes.NewReader(data))
+}
+
+// implement a http.Handler that will accept and store content into git blob,
+// in the repository named by the "repo" form value if several are served.
+type gitUploadFileHandler struct {
+	repos map[string]repository.Repo
+	// the max size of the uploaded files, in bytes
+	maxSize int64
+}
+
+func newGitUploadFileHandler(repos map[string]repository.Repo, maxSize int64) http.Handler {
+	return &gitUploadFileHandler{
+		repos:   repos,
+		maxSize: maxSize,
+	}
+}
+
+func (gufh *gitUploadFileHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if err := auth.CanMutate(r.Context()); err != nil {
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(rw, r.Body, gufh.maxSize)
+	if err := r.ParseMultipartForm(gufh.maxSize); err != nil {
+		msg := fmt.Sprintf("file too big (%dMB max)", gufh.maxSize/1000/1000)
+		http.Error(rw, msg, http.StatusBadRequest)
+		return
+	}
+
+	repo, ok := gufh.repos[r.FormValue("repo")]
+	if !ok {
+		http.Error(rw, "unknown repository", http.StatusBadRequest)
+		return
+	}
+
+	if err := auth.CanMutateRepo(r.Context(), r.FormValue("repo")); err != nil {
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	file, _, err := r.FormFile("uploadfile")
+	if err != nil {
+		http.Error(rw, "invalid file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	fileBytes, err := ioutil.ReadAll(file)
+	if err != nil {
+		http.Error(rw, "invalid file", http.StatusBadRequest)
+		return
+	}
+
+	filetype := http.DetectContentType(fileBytes)
+	if filetype != "image/jpeg" && filetype != "image/jpg" &&
+		filetype != "image/gif" && filetype != "image/png" {
+		http.Error(rw, "invalid file type", http.StatusBadRequest)
+		return
+	}
+
+	hash, err := repo.StoreData(fileBytes)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type response struct {
+		Hash string `json:"hash"`
+	}
+
+	resp := response{Hash: string(hash)}
+
+	js, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	_, err = rw.Write(js)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
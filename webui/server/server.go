@@ -0,0 +1,154 @@
+// Package server contains the http handler serving the web UI and the
+// GraphQL API, as "git bug webui" does, to mount git-bug in another Go http
+// server, with its own middlewares:
+//
+//	h, err := server.NewHandler(server.Options{
+//		Repos:  []graphql.NamedRepo{{Repo: repo}},
+//		Prefix: "/bugs/",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer h.Close()
+//
+//	mux.Handle("/bugs/", h)
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/mux"
+
+	"github.com/MichaelMure/git-bug/graphql"
+	"github.com/MichaelMure/git-bug/graphql/auth"
+	"github.com/MichaelMure/git-bug/repository"
+	"github.com/MichaelMure/git-bug/webui"
+)
+
+// 100MB (github limit)
+const defaultMaxUploadSize = 100 * 1000 * 1000
+
+// Options configure a Handler
+type Options struct {
+	// The repositories served. A single repository can be served without
+	// name, several repositories are served together, each under its name.
+	Repos []graphql.NamedRepo
+
+	// The path the handler is mounted at, with a trailing slash, as
+	// "/bugs/". The requests are routed with their full path, so the prefix
+	// must not be stripped. Default to "/".
+	Prefix string
+
+	// Require an API token to modify the repositories, as outside of the
+	// local machine
+	TokenRequired bool
+
+	// Reject all the modifications of the repositories
+	ReadOnly bool
+
+	// Serve the GraphQL playground, at <prefix>playground
+	Playground bool
+
+	// The max size of the uploaded files, in bytes. Default to 100MB.
+	MaxUploadSize int64
+}
+
+// Handler is the http handler of the web UI and the GraphQL API. It holds
+// the cache of the repositories, and must be closed.
+type Handler struct {
+	http.Handler
+
+	graphql graphql.Handler
+	// the middleware authenticating the requests
+	authenticate func(http.Handler) http.Handler
+}
+
+// NewHandler create a Handler serving the web UI and the GraphQL API of the
+// repositories
+//
+// The routes are, under the prefix:
+//
+//	graphql          the GraphQL API
+//	graphql/docs     the documentation of the GraphQL API
+//	playground       the GraphQL playground, if enabled
+//	gitfile/{hash}   the files stored in the repositories
+//	upload           the upload of a file in a repository
+//	anything else    the web UI
+func NewHandler(opts Options) (*Handler, error) {
+	prefix := opts.Prefix
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	maxUploadSize := opts.MaxUploadSize
+	if maxUploadSize <= 0 {
+		maxUploadSize = defaultMaxUploadSize
+	}
+
+	configs := make(map[string]repository.RepoConfig, len(opts.Repos))
+	repos := make(map[string]repository.Repo, len(opts.Repos))
+	for _, r := range opts.Repos {
+		configs[r.Name] = r.Repo
+		repos[r.Name] = r.Repo
+	}
+
+	var graphqlHandler graphql.Handler
+	var err error
+	if len(opts.Repos) == 1 && opts.Repos[0].Name == "" {
+		graphqlHandler, err = graphql.NewHandler(opts.Repos[0].Repo)
+	} else {
+		graphqlHandler, err = graphql.NewMultiRepoHandler(opts.Repos)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	docsHandler, err := graphql.NewDocsHandler()
+	if err != nil {
+		_ = graphqlHandler.Close()
+		return nil, err
+	}
+
+	authMiddleware := auth.MultiRepoMiddleware(configs, opts.TokenRequired)
+	authenticate := authMiddleware
+	if opts.ReadOnly {
+		authenticate = func(next http.Handler) http.Handler {
+			return authMiddleware(auth.ReadOnlyMiddleware(next))
+		}
+	}
+
+	router := mux.NewRouter()
+	router.Use(authenticate)
+
+	// Routes
+	if opts.Playground {
+		router.Path(prefix + "playground").Handler(playground.Handler("git-bug", prefix+"graphql"))
+	}
+	router.Path(prefix + "graphql/docs").Handler(docsHandler)
+	router.Path(prefix + "graphql").Handler(graphqlHandler)
+	router.Path(prefix + "gitfile/{hash}").Handler(newGitFileHandler(repos))
+	router.Path(prefix + "upload").Methods("POST").Handler(newGitUploadFileHandler(repos, maxUploadSize))
+	router.PathPrefix(prefix).Handler(newAssetsHandler(webui.WebUIAssets, prefix))
+
+	return &Handler{
+		Handler:      router,
+		graphql:      graphqlHandler,
+		authenticate: authenticate,
+	}, nil
+}
+
+// GraphQL return the http handler of the GraphQL API alone, authenticating
+// the requests as the Handler does, to mount it at any path
+func (h *Handler) GraphQL() http.Handler {
+	return h.authenticate(h.graphql)
+}
+
+// Close release the cache of the repositories
+func (h *Handler) Close() error {
+	return h.graphql.Close()
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MichaelMure/git-bug/graphql"
+	"github.com/MichaelMure/git-bug/misc/random_bugs"
+	"github.com/MichaelMure/git-bug/repository"
+)
+
+func TestHandlerPrefix(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	random_bugs.FillRepoWithSeed(repo, 3, 42)
+
+	h, err := NewHandler(Options{
+		Repos:  []graphql.NamedRepo{{Repo: repo}},
+		Prefix: "/bugs",
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	// mounted in another server
+	mux := http.NewServeMux()
+	mux.Handle("/bugs/", h)
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		http.Error(rw, "not git-bug", http.StatusTeapot)
+	})
+
+	var resp struct {
+		Repository struct {
+			AllBugs struct{ TotalCount int }
+		}
+	}
+	c := client.New(mux, client.Path("/bugs/graphql"))
+	c.MustPost(`query { repository { allBugs { totalCount } } }`, &resp)
+	require.Equal(t, 3, resp.Repository.AllBugs.TotalCount)
+
+	// the routes of the web UI serve index.html, with the prefix
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, httptest.NewRequest("GET", "/bugs/bug/abcdef", nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Contains(t, rw.Body.String(), `<base href="/bugs/">`)
+
+	rw = httptest.NewRecorder()
+	mux.ServeHTTP(rw, httptest.NewRequest("GET", "/graphql", nil))
+	require.Equal(t, http.StatusTeapot, rw.Code)
+
+	// the GraphQL API can be mounted alone
+	c = client.New(h.GraphQL())
+	c.MustPost(`query { repository { allBugs { totalCount } } }`, &resp)
+	require.Equal(t, 3, resp.Repository.AllBugs.TotalCount)
+}
+
+func TestHandlerReadOnly(t *testing.T) {
+	repo := repository.CreateTestRepo(false)
+	defer repository.CleanupTestRepos(t, repo)
+
+	h, err := NewHandler(Options{
+		Repos:    []graphql.NamedRepo{{Repo: repo}},
+		ReadOnly: true,
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	var resp struct{}
+	err = client.New(h, client.Path("/graphql")).Post(`mutation { newBug(input: {title: "title", message: "message"}) { bug { id } } }`, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "read-only")
+}
+
+func TestAssetsHandler(t *testing.T) {
+	fs := http.Dir("testdata")
+	handler := newAssetsHandler(fs, "/bugs/")
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+		return rw
+	}
+
+	rw := serve("/bugs/static/app.js")
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "console.log('app')\n", rw.Body.String())
+
+	for _, path := range []string{"/bugs/", "/bugs/index.html", "/bugs/static/", "/bugs/bug/abc"} {
+		rw = serve(path)
+		require.Equal(t, http.StatusOK, rw.Code, path)
+		require.Equal(t, "<html><head><base href=\"/bugs/\"><title>index</title></head></html>\n", rw.Body.String(), path)
+	}
+}
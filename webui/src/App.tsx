@@ -3,7 +3,11 @@ import { Route, Switch } from 'react-router';
 
 import Layout from './layout';
 import BugPage from './pages/bug';
+import LabelsPage from './pages/labels';
 import ListPage from './pages/list';
+import NewBugPage from './pages/new';
+import UserPage from './pages/user';
+import UsersPage from './pages/users';
 
 export default function App() {
   return (
@@ -11,6 +15,12 @@ export default function App() {
       <Switch>
         <Route path="/" exact component={ListPage} />
         <Route path="/bug/:id" exact component={BugPage} />
+        <Route path="/bug/:repo/:id" exact component={BugPage} />
+        <Route path="/new" exact component={NewBugPage} />
+        <Route path="/new/:repo" exact component={NewBugPage} />
+        <Route path="/labels" exact component={LabelsPage} />
+        <Route path="/users" exact component={UsersPage} />
+        <Route path="/user/:id" exact component={UserPage} />
       </Switch>
     </Layout>
   );
@@ -4,10 +4,11 @@ import {
   InMemoryCache,
 } from 'apollo-cache-inmemory';
 
+import basePath from './basePath';
 import introspectionQueryResultData from './fragmentTypes';
 
 const client = new ApolloClient({
-  uri: '/graphql',
+  uri: basePath + 'graphql',
   cache: new InMemoryCache({
     fragmentMatcher: new IntrospectionFragmentMatcher({
       introspectionQueryResultData,
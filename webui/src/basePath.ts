@@ -0,0 +1,8 @@
+// The path the web UI is served at, with a trailing slash. The server give it
+// with a <base> element, as the web UI can be mounted under a prefix in
+// another server.
+const base = document.querySelector('base');
+
+const basePath = base ? new URL(base.href).pathname : '/';
+
+export default basePath;
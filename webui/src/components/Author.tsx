@@ -1,4 +1,5 @@
 import React from 'react';
+import { Link } from 'react-router-dom';
 
 import MAvatar from '@material-ui/core/Avatar';
 import Tooltip from '@material-ui/core/Tooltip/Tooltip';
@@ -11,15 +12,20 @@ type Props = AuthoredFragment & {
 };
 
 const Author = ({ author, ...props }: Props) => {
+  const name = (
+    <Link
+      to={`/user/${author.humanId}`}
+      style={{ color: 'inherit', textDecoration: 'none' }}
+    >
+      <span {...props}>{author.displayName}</span>
+    </Link>
+  );
+
   if (!author.email) {
-    return <span {...props}>{author.displayName}</span>;
+    return name;
   }
 
-  return (
-    <Tooltip title={author.email}>
-      <span {...props}>{author.displayName}</span>
-    </Tooltip>
-  );
+  return <Tooltip title={author.email}>{name}</Tooltip>;
 };
 
 export const Avatar = ({ author, ...props }: Props) => {
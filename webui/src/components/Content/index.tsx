@@ -1,26 +1,43 @@
 import React from 'react';
-import html from 'remark-html';
-import parse from 'remark-parse';
-import remark2react from 'remark-react';
-import unified from 'unified';
 
-import ImageTag from './ImageTag';
-import PreTag from './PreTag';
+import { makeStyles } from '@material-ui/core/styles';
 
-type Props = { markdown: string };
-const Content: React.FC<Props> = ({ markdown }: Props) => {
-  const processor = unified()
-    .use(parse)
-    .use(html)
-    .use(remark2react, {
-      remarkReactComponents: {
-        img: ImageTag,
-        pre: PreTag,
-      },
-    });
+const useStyles = makeStyles(theme => ({
+  content: {
+    '& img': {
+      maxWidth: '100%',
+    },
+    '& pre': {
+      maxWidth: '100%',
+      overflowX: 'auto',
+    },
+    '& .hl-keyword': {
+      color: theme.palette.type === 'dark' ? '#ff7b72' : '#d73a49',
+    },
+    '& .hl-string': {
+      color: theme.palette.type === 'dark' ? '#a5d6ff' : '#032f62',
+    },
+    '& .hl-number': {
+      color: theme.palette.type === 'dark' ? '#79c0ff' : '#005cc5',
+    },
+    '& .hl-comment': {
+      color: theme.palette.text.secondary,
+      fontStyle: 'italic',
+    },
+  },
+}));
 
-  const contents: React.ReactNode = processor.processSync(markdown).contents;
-  return <>{contents}</>;
+// The HTML is rendered and sanitized by the server, from the markdown of a
+// message.
+type Props = { html: string };
+const Content: React.FC<Props> = ({ html }: Props) => {
+  const classes = useStyles();
+  return (
+    <div
+      className={classes.content}
+      dangerouslySetInnerHTML={{ __html: html }}
+    />
+  );
 };
 
 export default Content;
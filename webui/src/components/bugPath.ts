@@ -0,0 +1,7 @@
+// bugPath return the path of the page of a bug, qualified by the name of its
+// repository when the web UI serve several repositories.
+function bugPath(repo: string | null | undefined, humanId: string): string {
+  return repo ? `/bug/${repo}/${humanId}` : `/bug/${humanId}`;
+}
+
+export default bugPath;
@@ -0,0 +1,28 @@
+// fuzzyMatch tell if all the characters of the pattern appear in order in the
+// text, and how good the match is. Like the search of the backend,
+// consecutive characters or characters starting a word make a better match.
+// A negative score means no match.
+function fuzzyMatch(pattern: string, text: string): number {
+  const p = pattern.toLowerCase();
+  const t = text.toLowerCase();
+
+  let score = 0;
+  let previous = -2;
+  let i = 0;
+
+  for (const c of p) {
+    while (i < t.length && t[i] !== c) i++;
+    if (i >= t.length) return -1;
+
+    score++;
+    if (i === previous + 1) score += 2;
+    if (i === 0 || !/[a-z0-9]/.test(t[i - 1])) score += 3;
+
+    previous = i;
+    i++;
+  }
+
+  return score;
+}
+
+export default fuzzyMatch;
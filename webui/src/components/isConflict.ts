@@ -0,0 +1,11 @@
+import { ApolloError } from 'apollo-boost';
+
+// isConflict tell if a mutation has been refused because the bug has been
+// modified since it was last fetched, see expectedOperationCount.
+function isConflict(error: ApolloError): boolean {
+  return error.graphQLErrors.some(
+    e => e.extensions && e.extensions.code === 'CONFLICT'
+  );
+}
+
+export default isConflict;
@@ -0,0 +1,29 @@
+import { useEffect } from 'react';
+
+// the elements where the keys typed are text and not shortcuts
+const editableTags = ['INPUT', 'TEXTAREA', 'SELECT'];
+
+function isTyping(target: EventTarget | null) {
+  if (!(target instanceof HTMLElement)) return false;
+  return editableTags.includes(target.tagName) || target.isContentEditable;
+}
+
+// useShortcut call the handler when the key is pressed without modifier,
+// unless the user is typing in a text field
+function useShortcut(key: string, handler: () => void, enabled = true) {
+  useEffect(() => {
+    if (!enabled) return;
+
+    const listener = (e: KeyboardEvent) => {
+      if (e.key !== key || e.ctrlKey || e.metaKey || e.altKey) return;
+      if (isTyping(e.target)) return;
+      e.preventDefault();
+      handler();
+    };
+
+    window.addEventListener('keydown', listener);
+    return () => window.removeEventListener('keydown', listener);
+  }, [key, handler, enabled]);
+}
+
+export default useShortcut;
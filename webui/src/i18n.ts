@@ -0,0 +1,30 @@
+// The messages of the web UI are written in English, and are the keys of the
+// catalogs of translations, as for the CLI. A message without translation in
+// the language of the browser is displayed as is.
+const catalogs: Record<string, Record<string, string>> = {
+  fr: {
+    Write: 'Écrire',
+    Preview: 'Aperçu',
+    Comment: 'Commenter',
+    'Leave a comment': 'Laisser un commentaire',
+    'This bug has been modified in the meantime and has been refreshed. Review the changes and submit again.':
+      'Ce bug a été modifié entre-temps et a été rafraîchi. Vérifiez les changements et envoyez à nouveau.',
+    'New bug': 'Nouveau bug',
+    Title: 'Titre',
+    Form: 'Formulaire',
+    'No form': 'Aucun formulaire',
+    Description: 'Description',
+    Submit: 'Envoyer',
+  },
+};
+
+// the base language of the browser, as 'fr' for 'fr-FR'
+export const language = (navigator.language || 'en').split('-')[0];
+
+// t translate a message in the language of the browser
+function t(message: string): string {
+  const catalog = catalogs[language];
+  return (catalog && catalog[message]) || message;
+}
+
+export default t;
@@ -3,16 +3,15 @@ import { ApolloProvider } from 'react-apollo';
 import ReactDOM from 'react-dom';
 import { BrowserRouter } from 'react-router-dom';
 
-import ThemeProvider from '@material-ui/styles/ThemeProvider';
-
 import App from './App';
 import apolloClient from './apollo';
-import theme from './theme';
+import basePath from './basePath';
+import ThemeProvider from './layout/ThemeProvider';
 
 ReactDOM.render(
   <ApolloProvider client={apolloClient}>
-    <BrowserRouter>
-      <ThemeProvider theme={theme}>
+    <BrowserRouter basename={basePath}>
+      <ThemeProvider>
         <App />
       </ThemeProvider>
     </BrowserRouter>
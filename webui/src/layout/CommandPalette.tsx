@@ -0,0 +1,180 @@
+import React, { useEffect, useState } from 'react';
+import { useHistory } from 'react-router-dom';
+
+import Dialog from '@material-ui/core/Dialog';
+import InputBase from '@material-ui/core/InputBase';
+import List from '@material-ui/core/List';
+import ListItem from '@material-ui/core/ListItem';
+import ListItemText from '@material-ui/core/ListItemText';
+import { makeStyles } from '@material-ui/core/styles';
+
+import fuzzyMatch from 'src/components/fuzzyMatch';
+
+import { useSearchBugsQuery } from './CommandPalette.generated';
+import { useThemeMode } from './ThemeProvider';
+
+const useStyles = makeStyles(theme => ({
+  dialog: {
+    alignSelf: 'flex-start',
+    marginTop: theme.spacing(10),
+  },
+  input: {
+    ...theme.typography.h6,
+    padding: theme.spacing(1, 2),
+    borderBottom: `1px solid ${theme.palette.divider}`,
+  },
+  empty: {
+    ...theme.typography.body2,
+    padding: theme.spacing(2),
+    color: theme.palette.text.secondary,
+  },
+}));
+
+type Item = {
+  key: string;
+  label: string;
+  detail?: string;
+  run: () => void;
+};
+
+// CommandPalette is opened with ctrl+k or cmd+k, fuzzy-match the actions and
+// search the bugs of all the repositories by the start of their title words
+// or id, as the text is typed
+function CommandPalette() {
+  const classes = useStyles();
+  const history = useHistory();
+  const { setMode } = useThemeMode();
+  const [open, setOpen] = useState(false);
+  const [input, setInput] = useState('');
+  const [selected, setSelected] = useState(0);
+
+  useEffect(() => {
+    const listener = (e: KeyboardEvent) => {
+      if (e.key === 'k' && (e.ctrlKey || e.metaKey)) {
+        e.preventDefault();
+        setOpen(open => !open);
+      }
+    };
+    window.addEventListener('keydown', listener);
+    return () => window.removeEventListener('keydown', listener);
+  }, []);
+
+  const { data } = useSearchBugsQuery({
+    variables: { text: input },
+    skip: !open || input === '',
+  });
+
+  const close = () => {
+    setOpen(false);
+    setInput('');
+    setSelected(0);
+  };
+
+  const actions: Item[] = [
+    {
+      key: 'open-bugs',
+      label: 'Go to the open bugs',
+      run: () => history.push('/?q=status:open'),
+    },
+    {
+      key: 'closed-bugs',
+      label: 'Go to the closed bugs',
+      run: () => history.push('/?q=status:closed'),
+    },
+    {
+      key: 'light-theme',
+      label: 'Switch to the light theme',
+      run: () => setMode('light'),
+    },
+    {
+      key: 'dark-theme',
+      label: 'Switch to the dark theme',
+      run: () => setMode('dark'),
+    },
+    {
+      key: 'system-theme',
+      label: 'Follow the system theme',
+      run: () => setMode('system'),
+    },
+  ];
+
+  const matchingActions = actions
+    .map(action => ({ action, score: fuzzyMatch(input, action.label) }))
+    .filter(({ score }) => score >= 0)
+    .sort((a, b) => b.score - a.score)
+    .map(({ action }) => action);
+
+  const bugs: Item[] = (data?.search || []).map(bug => ({
+    key: bug.id,
+    label: bug.title,
+    detail: `${bug.humanId} ${bug.status.toLowerCase()}`,
+    run: () => history.push('/bug/' + bug.qualifiedId),
+  }));
+
+  // a search is more likely to be about a bug than an action
+  const items = input === '' ? matchingActions : [...bugs, ...matchingActions];
+  const current = Math.min(selected, items.length - 1);
+
+  const runItem = (item: Item) => {
+    close();
+    item.run();
+  };
+
+  const handleKeyDown = (e: React.KeyboardEvent) => {
+    switch (e.key) {
+      case 'ArrowDown':
+        e.preventDefault();
+        setSelected(Math.min(current + 1, items.length - 1));
+        break;
+      case 'ArrowUp':
+        e.preventDefault();
+        setSelected(Math.max(current - 1, 0));
+        break;
+      case 'Enter':
+        e.preventDefault();
+        if (current >= 0) runItem(items[current]);
+        break;
+    }
+  };
+
+  return (
+    <Dialog
+      open={open}
+      onClose={close}
+      fullWidth
+      maxWidth="sm"
+      classes={{ paper: classes.dialog }}
+    >
+      <InputBase
+        autoFocus
+        fullWidth
+        placeholder="Search bugs and actions"
+        className={classes.input}
+        value={input}
+        onChange={(e: any) => {
+          setInput(e.target.value);
+          setSelected(0);
+        }}
+        onKeyDown={handleKeyDown}
+      />
+      {items.length === 0 ? (
+        <div className={classes.empty}>No match</div>
+      ) : (
+        <List dense>
+          {items.map((item, index) => (
+            <ListItem
+              button
+              key={item.key}
+              selected={index === current}
+              onClick={() => runItem(item)}
+            >
+              <ListItemText primary={item.label} secondary={item.detail} />
+            </ListItem>
+          ))}
+        </List>
+      )}
+    </Dialog>
+  );
+}
+
+export default CommandPalette;
@@ -6,6 +6,7 @@ import Toolbar from '@material-ui/core/Toolbar';
 import { makeStyles } from '@material-ui/core/styles';
 
 import CurrentIdentity from './CurrentIdentity';
+import ThemeSwitch from './ThemeSwitch';
 
 const useStyles = makeStyles(theme => ({
   offset: {
@@ -25,6 +26,11 @@ const useStyles = makeStyles(theme => ({
     height: '42px',
     marginRight: theme.spacing(2),
   },
+  link: {
+    color: 'white',
+    textDecoration: 'none',
+    marginRight: theme.spacing(2),
+  },
 }));
 
 function Header() {
@@ -39,6 +45,13 @@ function Header() {
             git-bug
           </Link>
           <div className={classes.filler}></div>
+          <Link to="/labels" className={classes.link}>
+            Labels
+          </Link>
+          <Link to="/users" className={classes.link}>
+            Users
+          </Link>
+          <ThemeSwitch />
           <CurrentIdentity />
         </Toolbar>
       </AppBar>
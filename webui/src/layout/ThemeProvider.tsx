@@ -0,0 +1,62 @@
+import React, { createContext, useContext, useMemo, useState } from 'react';
+
+import useMediaQuery from '@material-ui/core/useMediaQuery';
+import MuiThemeProvider from '@material-ui/styles/ThemeProvider';
+
+import { makeTheme, ThemeMode, themeModes } from '../theme';
+
+import { useAccentColorQuery } from './AccentColor.generated';
+
+// the theme mode is a preference of the user, kept by the browser
+const storageKey = 'git-bug.theme';
+
+function loadMode(): ThemeMode {
+  const stored = window.localStorage.getItem(storageKey);
+  return themeModes.find(mode => mode === stored) || 'system';
+}
+
+type ThemeModeContext = {
+  mode: ThemeMode;
+  setMode: (mode: ThemeMode) => void;
+};
+
+const Context = createContext<ThemeModeContext>({
+  mode: 'system',
+  setMode: () => {},
+});
+
+export const useThemeMode = () => useContext(Context);
+
+function toHex(value: number): string {
+  return value.toString(16).padStart(2, '0');
+}
+
+type Props = { children: React.ReactNode };
+function ThemeProvider({ children }: Props) {
+  const [mode, setModeState] = useState<ThemeMode>(loadMode);
+  const prefersDark = useMediaQuery('(prefers-color-scheme: dark)');
+  const { data } = useAccentColorQuery();
+
+  const setMode = (mode: ThemeMode) => {
+    window.localStorage.setItem(storageKey, mode);
+    setModeState(mode);
+  };
+
+  const type =
+    mode === 'dark' || (mode === 'system' && prefersDark) ? 'dark' : 'light';
+
+  const color = data?.repository?.accentColor;
+  const accent = color
+    ? `#${toHex(color.R)}${toHex(color.G)}${toHex(color.B)}`
+    : undefined;
+
+  const theme = useMemo(() => makeTheme(type, accent), [type, accent]);
+
+  return (
+    <Context.Provider value={{ mode, setMode }}>
+      <MuiThemeProvider theme={theme}>{children}</MuiThemeProvider>
+    </Context.Provider>
+  );
+}
+
+export default ThemeProvider;
@@ -0,0 +1,39 @@
+import React from 'react';
+
+import IconButton from '@material-ui/core/IconButton';
+import Tooltip from '@material-ui/core/Tooltip';
+import Brightness4 from '@material-ui/icons/Brightness4';
+import Brightness7 from '@material-ui/icons/Brightness7';
+import BrightnessAuto from '@material-ui/icons/BrightnessAuto';
+
+import { themeModes } from '../theme';
+
+import { useThemeMode } from './ThemeProvider';
+
+const labels = {
+  light: 'Light theme',
+  dark: 'Dark theme',
+  system: 'System theme',
+};
+
+// ThemeSwitch cycle through the light, dark and system themes
+function ThemeSwitch() {
+  const { mode, setMode } = useThemeMode();
+
+  const next = () => {
+    const index = themeModes.indexOf(mode);
+    setMode(themeModes[(index + 1) % themeModes.length]);
+  };
+
+  return (
+    <Tooltip title={labels[mode]}>
+      <IconButton color="inherit" onClick={next} aria-label={labels[mode]}>
+        {mode === 'light' && <Brightness7 />}
+        {mode === 'dark' && <Brightness4 />}
+        {mode === 'system' && <BrightnessAuto />}
+      </IconButton>
+    </Tooltip>
+  );
+}
+
+export default ThemeSwitch;
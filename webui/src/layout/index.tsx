@@ -2,6 +2,7 @@ import React from 'react';
 
 import CssBaseline from '@material-ui/core/CssBaseline';
 
+import CommandPalette from './CommandPalette';
 import Header from './Header';
 
 type Props = { children: React.ReactNode };
@@ -11,6 +12,7 @@ function Layout({ children }: Props) {
       <CssBaseline />
       <Header />
       {children}
+      <CommandPalette />
     </>
   );
 }
@@ -0,0 +1,40 @@
+import React from 'react';
+
+import { makeStyles } from '@material-ui/core/styles';
+
+import Author from 'src/components/Author';
+import Date from 'src/components/Date';
+
+import { AddChecklistItemFragment } from './AddChecklistItemFragment.generated';
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    ...theme.typography.body2,
+    marginLeft: theme.spacing(1) + 40,
+  },
+  author: {
+    fontWeight: 'bold',
+  },
+  text: {
+    fontWeight: 'bold',
+  },
+}));
+
+type Props = {
+  op: AddChecklistItemFragment;
+};
+
+function AddChecklistItem({ op }: Props) {
+  const classes = useStyles();
+  return (
+    <div className={classes.main}>
+      <Author author={op.author} className={classes.author} />
+      <span> added </span>
+      <span className={classes.text}>{op.text}</span>
+      <span> to the checklist </span>
+      <Date date={op.date} />
+    </div>
+  );
+}
+
+export default AddChecklistItem;
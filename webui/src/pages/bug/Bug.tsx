@@ -1,15 +1,21 @@
-import React from 'react';
+import React, { useRef, useState } from 'react';
+import { Link } from 'react-router-dom';
 
 import Typography from '@material-ui/core/Typography/Typography';
 import { makeStyles } from '@material-ui/core/styles';
 
 import Author from 'src/components/Author';
+import bugPath from 'src/components/bugPath';
 import Date from 'src/components/Date';
 import Label from 'src/components/Label';
+import useShortcut from 'src/components/useShortcut';
 
 import { BugFragment } from './Bug.generated';
+import Checklist from './Checklist';
 import CommentForm from './CommentForm';
+import LabelDialog from './LabelDialog';
 import TimelineQuery from './TimelineQuery';
+import Votes from './Votes';
 
 const useStyles = makeStyles(theme => ({
   main: {
@@ -61,6 +67,17 @@ const useStyles = makeStyles(theme => ({
   noLabel: {
     ...theme.typography.body2,
   },
+  referenceList: {
+    listStyle: 'none',
+    padding: 0,
+    margin: 0,
+  },
+  reference: {
+    ...theme.typography.body2,
+    marginTop: theme.spacing(1),
+    marginBottom: theme.spacing(1),
+    wordBreak: 'break-all',
+  },
   commentForm: {
     marginLeft: 48,
   },
@@ -70,13 +87,42 @@ type Props = {
   bug: BugFragment;
 };
 
+type ReferenceProps = {
+  reference: BugFragment['references'][0];
+  repo?: string | null;
+};
+
+// A bug shared with this repository is linked to its page, a bug of another
+// repository to that repository when it has a web URL.
+function Reference({ reference, repo }: ReferenceProps) {
+  const text = reference.title
+    ? `${reference.humanId} ${reference.title}`
+    : `${reference.repoUrl}#${reference.humanId}`;
+
+  if (reference.local) {
+    return <Link to={bugPath(repo, reference.humanId)}>{text}</Link>;
+  }
+
+  if (reference.repoUrl.match(/^https?:\/\//)) {
+    return <a href={reference.repoUrl.replace(/\.git$/, '')}>{text}</a>;
+  }
+
+  return <span>{text}</span>;
+}
+
 function Bug({ bug }: Props) {
   const classes = useStyles();
+  const commentInput = useRef<HTMLTextAreaElement>(null);
+  const [labelDialog, setLabelDialog] = useState(false);
+
+  useShortcut('c', () => commentInput.current?.focus());
+  useShortcut('l', () => setLabelDialog(true));
+
   return (
     <main className={classes.main}>
       <div className={classes.header}>
         <span className={classes.title}>{bug.title}</span>
-        <span className={classes.id}>{bug.humanId}</span>
+        <span className={classes.id}>{bug.qualifiedId}</span>
 
         <Typography color={'textSecondary'}>
           <Author author={bug.author} />
@@ -87,9 +133,15 @@ function Bug({ bug }: Props) {
 
       <div className={classes.container}>
         <div className={classes.timeline}>
-          <TimelineQuery id={bug.id} />
+          <TimelineQuery id={bug.qualifiedId} />
           <div className={classes.commentForm}>
-            <CommentForm bugId={bug.id} />
+            <CommentForm
+              bugId={bug.id}
+              qualifiedId={bug.qualifiedId}
+              repoRef={bug.repository.name}
+              operationCount={bug.operations.totalCount}
+              inputRef={commentInput}
+            />
           </div>
         </div>
         <div className={classes.sidebar}>
@@ -104,8 +156,97 @@ function Bug({ bug }: Props) {
               </li>
             ))}
           </ul>
+          <Votes
+            bugId={bug.id}
+            qualifiedId={bug.qualifiedId}
+            repoRef={bug.repository.name}
+            operationCount={bug.operations.totalCount}
+            voters={bug.voters}
+          />
+          <Checklist
+            bugId={bug.id}
+            qualifiedId={bug.qualifiedId}
+            repoRef={bug.repository.name}
+            operationCount={bug.operations.totalCount}
+            checklist={bug.checklist}
+          />
+          {bug.duplicateOf && (
+            <>
+              <span className={classes.sidebarTitle}>Duplicate of</span>
+              <ul className={classes.referenceList}>
+                <li className={classes.reference}>
+                  <Link to={'/bug/' + bug.duplicateOf.qualifiedId}>
+                    {`${bug.duplicateOf.humanId} ${bug.duplicateOf.title}`}
+                  </Link>
+                </li>
+              </ul>
+            </>
+          )}
+          {bug.duplicates.length > 0 && (
+            <>
+              <span className={classes.sidebarTitle}>Duplicates</span>
+              <ul className={classes.referenceList}>
+                {bug.duplicates.map(d => (
+                  <li className={classes.reference} key={d.humanId}>
+                    <Link to={'/bug/' + d.qualifiedId}>
+                      {`${d.humanId} ${d.title}`}
+                    </Link>
+                  </li>
+                ))}
+              </ul>
+            </>
+          )}
+          {bug.parent && (
+            <>
+              <span className={classes.sidebarTitle}>Sub-task of</span>
+              <ul className={classes.referenceList}>
+                <li className={classes.reference}>
+                  <Link to={'/bug/' + bug.parent.qualifiedId}>
+                    {`${bug.parent.humanId} ${bug.parent.title}`}
+                  </Link>
+                </li>
+              </ul>
+            </>
+          )}
+          {bug.children.length > 0 && (
+            <>
+              <span className={classes.sidebarTitle}>
+                {`Sub-tasks (${bug.childrenClosed}/${bug.children.length} done)`}
+              </span>
+              <ul className={classes.referenceList}>
+                {bug.children.map(c => (
+                  <li className={classes.reference} key={c.humanId}>
+                    <Link to={'/bug/' + c.qualifiedId}>
+                      {`${c.humanId} ${c.title}`}
+                    </Link>
+                    {` (${c.status.toLowerCase()})`}
+                  </li>
+                ))}
+              </ul>
+            </>
+          )}
+          {bug.references.length > 0 && (
+            <>
+              <span className={classes.sidebarTitle}>References</span>
+              <ul className={classes.referenceList}>
+                {bug.references.map(r => (
+                  <li className={classes.reference} key={r.repoUrl + r.bugId}>
+                    <Reference reference={r} repo={bug.repository.name} />
+                  </li>
+                ))}
+              </ul>
+            </>
+          )}
         </div>
       </div>
+      <LabelDialog
+        bugId={bug.id}
+        qualifiedId={bug.qualifiedId}
+        repoRef={bug.repository.name}
+        operationCount={bug.operations.totalCount}
+        open={labelDialog}
+        onClose={() => setLabelDialog(false)}
+      />
     </main>
   );
 }
@@ -7,17 +7,20 @@ import Bug from './Bug';
 import { useGetBugQuery } from './BugQuery.generated';
 
 type Props = RouteComponentProps<{
+  repo?: string;
   id: string;
 }>;
 
 const BugQuery: React.FC<Props> = ({ match }: Props) => {
+  // the bugs of a named repository are qualified by its name
+  const { repo, id } = match.params;
   const { loading, error, data } = useGetBugQuery({
-    variables: { id: match.params.id },
+    variables: { id: repo ? `${repo}/${id}` : id },
   });
   if (loading) return <CircularProgress />;
   if (error) return <p>Error: {error}</p>;
-  if (!data?.repository?.bug) return <p>404.</p>;
-  return <Bug bug={data.repository.bug} />;
+  if (!data?.bug) return <p>404.</p>;
+  return <Bug bug={data.bug} />;
 };
 
 export default BugQuery;
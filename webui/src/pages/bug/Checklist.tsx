@@ -0,0 +1,171 @@
+import React, { useState } from 'react';
+
+import Checkbox from '@material-ui/core/Checkbox';
+import TextField from '@material-ui/core/TextField';
+import Typography from '@material-ui/core/Typography';
+import { makeStyles } from '@material-ui/core/styles';
+
+import isConflict from 'src/components/isConflict';
+
+import { BugFragment } from './Bug.generated';
+import { GetBugDocument } from './BugQuery.generated';
+import {
+  useAddChecklistItemMutation,
+  useCheckItemMutation,
+} from './Checklist.generated';
+import { TimelineDocument } from './TimelineQuery.generated';
+
+const useStyles = makeStyles(theme => ({
+  title: {
+    fontWeight: 'bold',
+  },
+  list: {
+    listStyle: 'none',
+    padding: 0,
+    margin: theme.spacing(0, 0, 1, 0),
+  },
+  item: {
+    ...theme.typography.body2,
+    display: 'flex',
+    alignItems: 'center',
+  },
+  checkbox: {
+    padding: theme.spacing(0.5),
+  },
+  checked: {
+    textDecoration: 'line-through',
+    color: theme.palette.text.secondary,
+  },
+}));
+
+type Props = {
+  bugId: string;
+  qualifiedId: string;
+  repoRef?: string | null;
+  // the number of operations of the bug when it was fetched, to detect the
+  // concurrent edits
+  operationCount: number;
+  checklist: BugFragment['checklist'];
+};
+
+// Checklist display the tasks of a bug, to check them once done and add new
+// ones
+function Checklist({
+  bugId,
+  qualifiedId,
+  repoRef,
+  operationCount,
+  checklist,
+}: Props) {
+  const classes = useStyles();
+  const [addItem, addState] = useAddChecklistItemMutation();
+  const [checkItem, checkState] = useCheckItemMutation();
+  const [input, setInput] = useState('');
+  const [conflict, setConflict] = useState(false);
+  const loading = addState.loading || checkState.loading;
+
+  const refetchQueries = [
+    {
+      query: TimelineDocument,
+      variables: {
+        id: qualifiedId,
+        first: 100,
+      },
+    },
+    { query: GetBugDocument, variables: { id: qualifiedId } },
+  ];
+
+  const onError = (error: any) => {
+    if (!isConflict(error)) throw error;
+    setConflict(true);
+    return addState.client?.reFetchObservableQueries();
+  };
+
+  const submit = (e: React.FormEvent) => {
+    e.preventDefault();
+
+    const text = input.trim();
+    if (text === '') return;
+
+    addItem({
+      variables: {
+        input: {
+          repoRef,
+          prefix: bugId,
+          text,
+          expectedOperationCount: operationCount,
+        },
+      },
+      refetchQueries,
+      awaitRefetchQueries: true,
+    })
+      .then(() => {
+        setInput('');
+        setConflict(false);
+      })
+      .catch(onError);
+  };
+
+  const toggle = (target: string, checked: boolean) => {
+    checkItem({
+      variables: {
+        input: {
+          repoRef,
+          prefix: bugId,
+          target,
+          checked,
+          expectedOperationCount: operationCount,
+        },
+      },
+      refetchQueries,
+      awaitRefetchQueries: true,
+    })
+      .then(() => setConflict(false))
+      .catch(onError);
+  };
+
+  const done = checklist.filter(item => item.checked).length;
+
+  return (
+    <>
+      <span className={classes.title}>
+        {checklist.length > 0
+          ? `Checklist (${done}/${checklist.length} done)`
+          : 'Checklist'}
+      </span>
+      <ul className={classes.list}>
+        {checklist.map(item => (
+          <li className={classes.item} key={item.id}>
+            <Checkbox
+              className={classes.checkbox}
+              size="small"
+              checked={item.checked}
+              disabled={loading}
+              onChange={(_, checked) => toggle(item.id, checked)}
+            />
+            <span className={item.checked ? classes.checked : undefined}>
+              {item.text}
+            </span>
+          </li>
+        ))}
+      </ul>
+      {conflict && (
+        <Typography variant="body2" color="error">
+          This bug has been modified in the meantime and has been refreshed.
+        </Typography>
+      )}
+      <form onSubmit={submit}>
+        <TextField
+          fullWidth
+          size="small"
+          placeholder="Add an item"
+          value={input}
+          onChange={(e: any) => setInput(e.target.value)}
+          disabled={loading}
+        />
+      </form>
+    </>
+  );
+}
+
+export default Checklist;
@@ -5,11 +5,18 @@ import Paper from '@material-ui/core/Paper';
 import Tab from '@material-ui/core/Tab';
 import Tabs from '@material-ui/core/Tabs';
 import TextField from '@material-ui/core/TextField';
+import Typography from '@material-ui/core/Typography';
 import { makeStyles, Theme } from '@material-ui/core/styles';
 
 import Content from 'src/components/Content';
+import isConflict from 'src/components/isConflict';
+import t from 'src/i18n';
 
-import { useAddCommentMutation } from './CommentForm.generated';
+import { GetBugDocument } from './BugQuery.generated';
+import {
+  useAddCommentMutation,
+  useRenderMarkdownQuery,
+} from './CommentForm.generated';
 import { TimelineDocument } from './TimelineQuery.generated';
 
 type StyleProps = { loading: boolean };
@@ -23,12 +30,16 @@ const useStyles = makeStyles<Theme, StyleProps>(theme => ({
     margin: theme.spacing(2, 0),
   },
   preview: {
-    borderBottom: `solid 3px ${theme.palette.grey['200']}`,
+    borderBottom: `solid 3px ${theme.palette.divider}`,
     minHeight: '5rem',
   },
   actions: {
     display: 'flex',
     justifyContent: 'flex-end',
+    alignItems: 'center',
+  },
+  conflict: {
+    flex: 1,
   },
 }));
 
@@ -58,21 +69,42 @@ const a11yProps = (index: number) => ({
 
 type Props = {
   bugId: string;
+  // the id of the bug qualified by its repository, and the name of that
+  // repository, for a web UI serving several repositories
+  qualifiedId: string;
+  repoRef?: string | null;
+  // the number of operations of the bug when it was fetched, to detect the
+  // concurrent edits
+  operationCount: number;
+  inputRef?: React.Ref<HTMLTextAreaElement>;
 };
 
-function CommentForm({ bugId }: Props) {
-  const [addComment, { loading }] = useAddCommentMutation();
+function CommentForm({
+  bugId,
+  qualifiedId,
+  repoRef,
+  operationCount,
+  inputRef,
+}: Props) {
+  const [addComment, { loading, client }] = useAddCommentMutation();
   const [input, setInput] = useState<string>('');
+  const [conflict, setConflict] = useState(false);
   const [tab, setTab] = useState(0);
   const classes = useStyles({ loading });
   const form = useRef<HTMLFormElement>(null);
+  const { data: preview } = useRenderMarkdownQuery({
+    variables: { markdown: input },
+    skip: tab !== 1,
+  });
 
   const submit = () => {
     addComment({
       variables: {
         input: {
+          repoRef,
           prefix: bugId,
           message: input,
+          expectedOperationCount: operationCount,
         },
       },
       refetchQueries: [
@@ -80,13 +112,24 @@ function CommentForm({ bugId }: Props) {
         {
           query: TimelineDocument,
           variables: {
-            id: bugId,
+            id: qualifiedId,
             first: 100,
           },
         },
+        { query: GetBugDocument, variables: { id: qualifiedId } },
       ],
       awaitRefetchQueries: true,
-    }).then(() => setInput(''));
+    })
+      .then(() => {
+        setInput('');
+        setConflict(false);
+      })
+      .catch(error => {
+        if (!isConflict(error)) throw error;
+        // keep the comment, but show what changed before submitting again
+        setConflict(true);
+        return client?.reFetchObservableQueries();
+      });
   };
 
   const handleSubmit = (e: React.FormEvent<HTMLFormElement>) => {
@@ -105,16 +148,17 @@ function CommentForm({ bugId }: Props) {
     <Paper className={classes.container}>
       <form onSubmit={handleSubmit} ref={form}>
         <Tabs value={tab} onChange={(_, t) => setTab(t)}>
-          <Tab label="Write" {...a11yProps(0)} />
-          <Tab label="Preview" {...a11yProps(1)} />
+          <Tab label={t('Write')} {...a11yProps(0)} />
+          <Tab label={t('Preview')} {...a11yProps(1)} />
         </Tabs>
         <div className={classes.tabContent}>
           <TabPanel value={tab} index={0}>
             <TextField
               onKeyDown={handleKeyDown}
+              inputRef={inputRef}
               fullWidth
-              label="Comment"
-              placeholder="Leave a comment"
+              label={t('Comment')}
+              placeholder={t('Leave a comment')}
               className={classes.textarea}
               multiline
               value={input}
@@ -125,17 +169,24 @@ function CommentForm({ bugId }: Props) {
             />
           </TabPanel>
           <TabPanel value={tab} index={1} className={classes.preview}>
-            <Content markdown={input} />
+            <Content html={preview?.renderMarkdown || ''} />
           </TabPanel>
         </div>
         <div className={classes.actions}>
+          {conflict && (
+            <Typography color="error" className={classes.conflict}>
+              {t(
+                'This bug has been modified in the meantime and has been refreshed. Review the changes and submit again.'
+              )}
+            </Typography>
+          )}
           <Button
             variant="contained"
             color="primary"
             type="submit"
             disabled={loading}
           >
-            Comment
+            {t('Comment')}
           </Button>
         </div>
       </form>
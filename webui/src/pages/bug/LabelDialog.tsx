@@ -0,0 +1,154 @@
+import React, { useState } from 'react';
+
+import Button from '@material-ui/core/Button';
+import Dialog from '@material-ui/core/Dialog';
+import DialogActions from '@material-ui/core/DialogActions';
+import DialogContent from '@material-ui/core/DialogContent';
+import DialogTitle from '@material-ui/core/DialogTitle';
+import DialogContentText from '@material-ui/core/DialogContentText';
+import TextField from '@material-ui/core/TextField';
+import { makeStyles } from '@material-ui/core/styles';
+
+import Label from 'src/components/Label';
+import isConflict from 'src/components/isConflict';
+
+import { GetBugDocument } from './BugQuery.generated';
+import {
+  useAddLabelsMutation,
+  useLabelsUsageQuery,
+} from './LabelDialog.generated';
+
+// the maximum number of labels suggested while typing
+const maxSuggestions = 8;
+
+const useStyles = makeStyles(theme => ({
+  suggestions: {
+    marginTop: theme.spacing(1),
+    minHeight: theme.spacing(3),
+  },
+  suggestion: {
+    cursor: 'pointer',
+    marginRight: theme.spacing(0.5),
+  },
+}));
+
+type Props = {
+  bugId: string;
+  qualifiedId: string;
+  repoRef?: string | null;
+  // the number of operations of the bug when it was fetched, to detect the
+  // concurrent edits
+  operationCount: number;
+  open: boolean;
+  onClose: () => void;
+};
+
+// LabelDialog add labels to a bug, separated by spaces or commas
+function LabelDialog({
+  bugId,
+  qualifiedId,
+  repoRef,
+  operationCount,
+  open,
+  onClose,
+}: Props) {
+  const classes = useStyles();
+  const [addLabels, { loading, client }] = useAddLabelsMutation();
+  const { data } = useLabelsUsageQuery({
+    variables: { ref: repoRef },
+    skip: !open,
+  });
+  const [input, setInput] = useState('');
+  const [conflict, setConflict] = useState(false);
+
+  // suggest the existing labels starting with the word being typed, the
+  // most used first
+  const words = input.split(/[\s,]+/);
+  const current = words[words.length - 1].toLowerCase();
+  const suggestions = (data?.repository?.labelsUsage || [])
+    .map(usage => usage.label)
+    .filter(
+      label =>
+        label.name.toLowerCase().startsWith(current) &&
+        !words.includes(label.name)
+    )
+    .slice(0, maxSuggestions);
+
+  const complete = (name: string) => {
+    setInput(input.slice(0, input.length - current.length) + name + ' ');
+  };
+
+  const submit = (e: React.FormEvent) => {
+    e.preventDefault();
+
+    const labels = input.split(/[\s,]+/).filter(l => l !== '');
+    if (labels.length === 0) return onClose();
+
+    addLabels({
+      variables: {
+        input: {
+          repoRef,
+          prefix: bugId,
+          added: labels,
+          expectedOperationCount: operationCount,
+        },
+      },
+      refetchQueries: [{ query: GetBugDocument, variables: { id: qualifiedId } }],
+      awaitRefetchQueries: true,
+    })
+      .then(() => {
+        setInput('');
+        setConflict(false);
+        onClose();
+      })
+      .catch(error => {
+        if (!isConflict(error)) throw error;
+        setConflict(true);
+        return client?.reFetchObservableQueries();
+      });
+  };
+
+  return (
+    <Dialog open={open} onClose={onClose} fullWidth maxWidth="xs">
+      <form onSubmit={submit}>
+        <DialogTitle>Add labels</DialogTitle>
+        <DialogContent>
+          {conflict && (
+            <DialogContentText color="error">
+              This bug has been modified in the meantime and has been
+              refreshed. Review the changes and submit again.
+            </DialogContentText>
+          )}
+          <TextField
+            autoFocus
+            fullWidth
+            label="Labels"
+            placeholder="bug, ui"
+            value={input}
+            onChange={(e: any) => setInput(e.target.value)}
+            disabled={loading}
+          />
+          <div className={classes.suggestions}>
+            {suggestions.map(label => (
+              <span
+                key={label.name}
+                className={classes.suggestion}
+                onClick={() => complete(label.name)}
+              >
+                <Label label={label} />
+              </span>
+            ))}
+          </div>
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={onClose}>Cancel</Button>
+          <Button type="submit" color="primary" disabled={loading}>
+            Add
+          </Button>
+        </DialogActions>
+      </form>
+    </Dialog>
+  );
+}
+
+export default LabelDialog;
@@ -0,0 +1,47 @@
+import React from 'react';
+import { Link, useParams } from 'react-router-dom';
+
+import { makeStyles } from '@material-ui/core/styles';
+
+import Author from 'src/components/Author';
+import bugPath from 'src/components/bugPath';
+import Date from 'src/components/Date';
+
+import { MarkDuplicateFragment } from './MarkDuplicateFragment.generated';
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    ...theme.typography.body2,
+    marginLeft: theme.spacing(1) + 40,
+  },
+  author: {
+    fontWeight: 'bold',
+  },
+  target: {
+    fontWeight: 'bold',
+  },
+}));
+
+type Props = {
+  op: MarkDuplicateFragment;
+};
+
+function MarkDuplicate({ op }: Props) {
+  const classes = useStyles();
+  // the duplicates are in the repository of the bug displayed
+  const { repo } = useParams<{ repo?: string }>();
+  const humanId = op.target.substring(0, 7);
+  return (
+    <div className={classes.main}>
+      <Author author={op.author} className={classes.author} />
+      <span> marked this bug as duplicate of </span>
+      <Link to={bugPath(repo, humanId)} className={classes.target}>
+        {humanId}
+      </Link>
+      &nbsp;
+      <Date date={op.date} />
+    </div>
+  );
+}
+
+export default MarkDuplicate;
@@ -1,4 +1,6 @@
+import clsx from 'clsx';
 import React from 'react';
+import { Link } from 'react-router-dom';
 
 import Paper from '@material-ui/core/Paper';
 import { makeStyles } from '@material-ui/core/styles';
@@ -25,21 +27,32 @@ const useStyles = makeStyles(theme => ({
     marginLeft: theme.spacing(1),
     minWidth: 0,
   },
+  highlighted: {
+    boxShadow: `0 0 0 2px ${theme.palette.primary.main}`,
+  },
+  permalink: {
+    color: 'inherit',
+    textDecoration: 'none',
+    '&:hover': {
+      textDecoration: 'underline',
+    },
+  },
   header: {
     ...theme.typography.body1,
-    color: '#444',
+    color: theme.palette.text.primary,
     padding: '0.5rem 1rem',
-    borderBottom: '1px solid #ddd',
+    borderBottom: `1px solid ${theme.palette.divider}`,
     display: 'flex',
-    backgroundColor: '#e2f1ff',
+    backgroundColor:
+      theme.palette.type === 'dark' ? theme.palette.grey['800'] : '#e2f1ff',
   },
   title: {
     flex: 1,
   },
   tag: {
     ...theme.typography.button,
-    color: '#888',
-    border: '#ddd solid 1px',
+    color: theme.palette.text.secondary,
+    border: `${theme.palette.divider} solid 1px`,
     padding: '0 0.5rem',
     fontSize: '0.75rem',
     borderRadius: 2,
@@ -53,24 +66,35 @@ const useStyles = makeStyles(theme => ({
 
 type Props = {
   op: AddCommentFragment | CreateFragment;
+  // true for the comment targeted by a permalink
+  highlighted?: boolean;
 };
 
-function Message({ op }: Props) {
+// Message display a comment, with a permalink to it by its id, which is the
+// hash of its operation
+function Message({ op, highlighted }: Props) {
   const classes = useStyles();
   return (
-    <article className={classes.container}>
+    <article id={op.id} className={classes.container}>
       <Avatar author={op.author} className={classes.avatar} />
-      <Paper elevation={1} className={classes.bubble}>
+      <Paper
+        elevation={1}
+        className={clsx(classes.bubble, highlighted && classes.highlighted)}
+      >
         <header className={classes.header}>
           <div className={classes.title}>
             <Author className={classes.author} author={op.author} />
-            <span> commented </span>
-            <Date date={op.createdAt} />
+            <span>
+              {'replyTo' in op && op.replyTo ? ' replied ' : ' commented '}
+            </span>
+            <Link to={{ hash: op.id }} className={classes.permalink}>
+              <Date date={op.createdAt} />
+            </Link>
           </div>
           {op.edited && <div className={classes.tag}>Edited</div>}
         </header>
         <section className={classes.body}>
-          <Content markdown={op.message} />
+          <Content html={op.messageHTML} />
         </section>
       </Paper>
     </article>
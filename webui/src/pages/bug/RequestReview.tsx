@@ -0,0 +1,45 @@
+import React from 'react';
+
+import { makeStyles } from '@material-ui/core/styles';
+
+import Author from 'src/components/Author';
+import Date from 'src/components/Date';
+
+import { RequestReviewFragment } from './RequestReviewFragment.generated';
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    ...theme.typography.body2,
+    marginLeft: theme.spacing(1) + 40,
+  },
+  author: {
+    fontWeight: 'bold',
+  },
+  reviewer: {
+    fontWeight: 'bold',
+  },
+  message: {
+    fontStyle: 'italic',
+  },
+}));
+
+type Props = {
+  op: RequestReviewFragment;
+};
+
+function RequestReview({ op }: Props) {
+  const classes = useStyles();
+  return (
+    <div className={classes.main}>
+      <Author author={op.author} className={classes.author} />
+      <span> requested the review of </span>
+      <span className={classes.reviewer}>
+        {op.reviewers.map(r => r.displayName).join(', ')}
+      </span>{' '}
+      <Date date={op.date} />
+      {op.message && <div className={classes.message}>{op.message}</div>}
+    </div>
+  );
+}
+
+export default RequestReview;
@@ -0,0 +1,47 @@
+import React from 'react';
+import { Link, useParams } from 'react-router-dom';
+
+import { makeStyles } from '@material-ui/core/styles';
+
+import Author from 'src/components/Author';
+import bugPath from 'src/components/bugPath';
+import Date from 'src/components/Date';
+
+import { SetParentFragment } from './SetParentFragment.generated';
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    ...theme.typography.body2,
+    marginLeft: theme.spacing(1) + 40,
+  },
+  author: {
+    fontWeight: 'bold',
+  },
+  target: {
+    fontWeight: 'bold',
+  },
+}));
+
+type Props = {
+  op: SetParentFragment;
+};
+
+function SetParent({ op }: Props) {
+  const classes = useStyles();
+  // the parent is in the repository of the bug displayed
+  const { repo } = useParams<{ repo?: string }>();
+  const humanId = op.parent.substring(0, 7);
+  return (
+    <div className={classes.main}>
+      <Author author={op.author} className={classes.author} />
+      <span> made this bug a sub-task of </span>
+      <Link to={bugPath(repo, humanId)} className={classes.target}>
+        {humanId}
+      </Link>
+      &nbsp;
+      <Date date={op.date} />
+    </div>
+  );
+}
+
+export default SetParent;
@@ -2,7 +2,7 @@ import React from 'react';
 
 import { makeStyles } from '@material-ui/core/styles';
 
-import { Status } from '../../gqlTypes';
+import { Resolution, Status } from '../../gqlTypes';
 import Author from 'src/components/Author';
 import Date from 'src/components/Date';
 
@@ -27,11 +27,19 @@ function SetStatus({ op }: Props) {
   const status = { [Status.Open]: 'reopened', [Status.Closed]: 'closed' }[
     op.status
   ];
+  const resolution = op.resolution && {
+    [Resolution.Fixed]: 'fixed',
+    [Resolution.Wontfix]: "won't fix",
+    [Resolution.Duplicate]: 'duplicate',
+    [Resolution.Invalid]: 'invalid',
+    [Resolution.WorksForMe]: 'works for me',
+  }[op.resolution];
 
   return (
     <div className={classes.main}>
       <Author author={op.author} className={classes.author} />
       <span> {status} this </span>
+      {resolution && <span>as {resolution} </span>}
       <Date date={op.date} />
     </div>
   );
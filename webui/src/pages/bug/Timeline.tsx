@@ -1,12 +1,29 @@
-import React from 'react';
+import React, { useEffect, useState } from 'react';
+import { useLocation } from 'react-router-dom';
 
+import Button from '@material-ui/core/Button';
 import { makeStyles } from '@material-ui/core/styles';
+import ToggleButton from '@material-ui/lab/ToggleButton';
+import ToggleButtonGroup from '@material-ui/lab/ToggleButtonGroup';
 
+import AddChecklistItem from './AddChecklistItem';
+import CheckItem from './CheckItem';
 import LabelChange from './LabelChange';
+import MarkDuplicate from './MarkDuplicate';
 import Message from './Message';
+import RemoveParent from './RemoveParent';
+import RequestReview from './RequestReview';
+import SetParent from './SetParent';
 import SetStatus from './SetStatus';
 import SetTitle from './SetTitle';
 import { TimelineItemFragment } from './TimelineQuery.generated';
+import Unknown from './Unknown';
+import Vote from './Vote';
+
+// a longer timeline is collapsed, showing only its first and last items
+const collapseThreshold = 20;
+const collapsedHead = 5;
+const collapsedTail = 10;
 
 const useStyles = makeStyles(theme => ({
   main: {
@@ -14,34 +31,174 @@ const useStyles = makeStyles(theme => ({
       marginBottom: theme.spacing(2),
     },
   },
+  toolbar: {
+    display: 'flex',
+    justifyContent: 'flex-end',
+  },
+  hidden: {
+    display: 'flex',
+    justifyContent: 'center',
+    borderTop: `1px dashed ${theme.palette.divider}`,
+    borderBottom: `1px dashed ${theme.palette.divider}`,
+  },
 }));
 
+type Filter = 'all' | 'comments';
+
+const isComment = (op: TimelineItemFragment) =>
+  op.__typename === 'CreateTimelineItem' ||
+  op.__typename === 'AddCommentTimelineItem';
+
 type Props = {
   ops: Array<TimelineItemFragment>;
 };
 
+type ThreadedItem = {
+  op: TimelineItemFragment;
+  depth: number;
+};
+
+// Move the replies to a comment right after it and the previous replies to it,
+// with their depth in the thread. A reply to a comment not loaded stays in place.
+function thread(ops: Array<TimelineItemFragment>): Array<ThreadedItem> {
+  const replies = new Map<string, Array<TimelineItemFragment>>();
+  const seen = new Set<string>();
+  const isReply = new Set<TimelineItemFragment>();
+
+  ops.forEach(op => {
+    if (
+      op.__typename === 'AddCommentTimelineItem' &&
+      op.replyTo &&
+      seen.has(op.replyTo)
+    ) {
+      replies.set(op.replyTo, [...(replies.get(op.replyTo) || []), op]);
+      isReply.add(op);
+    }
+    if ('id' in op) {
+      seen.add(op.id);
+    }
+  });
+
+  const result: Array<ThreadedItem> = [];
+  const add = (op: TimelineItemFragment, depth: number) => {
+    result.push({ op, depth });
+    if ('id' in op) {
+      (replies.get(op.id) || []).forEach(reply => add(reply, depth + 1));
+    }
+  };
+  ops.filter(op => !isReply.has(op)).forEach(op => add(op, 0));
+
+  return result;
+}
+
 function Timeline({ ops }: Props) {
   const classes = useStyles();
+  const location = useLocation();
+  const [filter, setFilter] = useState<Filter>('all');
+  const [expanded, setExpanded] = useState(false);
+
+  // the comment targeted by a permalink, as #<operation hash>
+  const target = location.hash.slice(1);
+
+  const items = thread(
+    filter === 'comments' ? ops.filter(isComment) : ops
+  );
+
+  const targetIndex = items.findIndex(
+    ({ op }) => 'id' in op && op.id === target
+  );
+
+  const collapsed =
+    !expanded &&
+    items.length > collapseThreshold &&
+    // never hide the comment targeted by a permalink
+    !(
+      targetIndex >= collapsedHead &&
+      targetIndex < items.length - collapsedTail
+    );
+  const hiddenCount = collapsed
+    ? items.length - collapsedHead - collapsedTail
+    : 0;
+
+  useEffect(() => {
+    if (target === '') return;
+    document.getElementById(target)?.scrollIntoView({ block: 'center' });
+  }, [target, ops.length]);
+
+  const render = ({ op, depth }: ThreadedItem, index: number) => {
+    if (op.__typename === 'CreateTimelineItem') {
+      return <Message key={index} op={op} highlighted={op.id === target} />;
+    }
+
+    if (op.__typename === 'AddCommentTimelineItem') {
+      const message = (
+        <Message key={index} op={op} highlighted={op.id === target} />
+      );
+      if (depth === 0) return message;
+      return (
+        <div key={index} style={{ marginLeft: `${depth * 2}rem` }}>
+          {message}
+        </div>
+      );
+    }
+
+    switch (op.__typename) {
+      case 'LabelChangeTimelineItem':
+        return <LabelChange key={index} op={op} />;
+      case 'SetTitleTimelineItem':
+        return <SetTitle key={index} op={op} />;
+      case 'SetStatusTimelineItem':
+        return <SetStatus key={index} op={op} />;
+      case 'MarkDuplicateTimelineItem':
+        return <MarkDuplicate key={index} op={op} />;
+      case 'SetParentTimelineItem':
+        return <SetParent key={index} op={op} />;
+      case 'RemoveParentTimelineItem':
+        return <RemoveParent key={index} op={op} />;
+      case 'AddChecklistItemTimelineItem':
+        return <AddChecklistItem key={index} op={op} />;
+      case 'CheckItemTimelineItem':
+        return <CheckItem key={index} op={op} />;
+      case 'VoteTimelineItem':
+        return <Vote key={index} op={op} />;
+      case 'RequestReviewTimelineItem':
+        return <RequestReview key={index} op={op} />;
+      case 'UnknownTimelineItem':
+        return <Unknown key={index} op={op} />;
+    }
+
+    console.warn('unsupported operation type ' + op.__typename);
+    return null;
+  };
 
   return (
     <div className={classes.main}>
-      {ops.map((op, index) => {
-        switch (op.__typename) {
-          case 'CreateTimelineItem':
-            return <Message key={index} op={op} />;
-          case 'AddCommentTimelineItem':
-            return <Message key={index} op={op} />;
-          case 'LabelChangeTimelineItem':
-            return <LabelChange key={index} op={op} />;
-          case 'SetTitleTimelineItem':
-            return <SetTitle key={index} op={op} />;
-          case 'SetStatusTimelineItem':
-            return <SetStatus key={index} op={op} />;
-        }
-
-        console.warn('unsupported operation type ' + op.__typename);
-        return null;
-      })}
+      <div className={classes.toolbar}>
+        <ToggleButtonGroup
+          size="small"
+          exclusive
+          value={filter}
+          onChange={(_, value) => value && setFilter(value)}
+        >
+          <ToggleButton value="all">All activity</ToggleButton>
+          <ToggleButton value="comments">Comments only</ToggleButton>
+        </ToggleButtonGroup>
+      </div>
+      {collapsed ? (
+        <>
+          {items.slice(0, collapsedHead).map(render)}
+          <div className={classes.hidden}>
+            <Button onClick={() => setExpanded(true)}>
+              Show {hiddenCount} hidden items
+            </Button>
+          </div>
+          {items
+            .slice(items.length - collapsedTail)
+            .map((item, i) => render(item, items.length - collapsedTail + i))}
+        </>
+      ) : (
+        items.map(render)
+      )}
     </div>
   );
 }
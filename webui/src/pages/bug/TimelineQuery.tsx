@@ -1,26 +1,54 @@
-import React from 'react';
+import React, { useEffect } from 'react';
 
 import CircularProgress from '@material-ui/core/CircularProgress';
 
 import Timeline from './Timeline';
 import { useTimelineQuery } from './TimelineQuery.generated';
 
+const PAGE_SIZE = 100;
+
 type Props = {
   id: string;
 };
 
 const TimelineQuery = ({ id }: Props) => {
-  const { loading, error, data } = useTimelineQuery({
+  const { loading, error, data, fetchMore } = useTimelineQuery({
     variables: {
       id,
-      first: 100,
+      first: PAGE_SIZE,
     },
   });
 
+  // load the whole timeline, for the permalinks to the last comments of a
+  // long history to work
+  const pageInfo = data?.bug?.timeline.pageInfo;
+  useEffect(() => {
+    if (!pageInfo?.hasNextPage) return;
+
+    fetchMore({
+      variables: { id, first: PAGE_SIZE, after: pageInfo.endCursor },
+      updateQuery: (previous, { fetchMoreResult }) => {
+        const next = fetchMoreResult?.bug?.timeline;
+        const prev = previous.bug?.timeline;
+        if (!next || !prev || !previous.bug) return previous;
+        return {
+          ...previous,
+          bug: {
+            ...previous.bug,
+            timeline: {
+              ...next,
+              nodes: [...prev.nodes, ...next.nodes],
+            },
+          },
+        };
+      },
+    });
+  }, [id, pageInfo, fetchMore]);
+
   if (loading) return <CircularProgress />;
   if (error) return <p>Error: {error}</p>;
 
-  const nodes = data?.repository?.bug?.timeline.nodes;
+  const nodes = data?.bug?.timeline.nodes;
   if (!nodes) {
     return null;
   }
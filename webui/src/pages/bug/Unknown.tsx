@@ -0,0 +1,36 @@
+import React from 'react';
+
+import { makeStyles } from '@material-ui/core/styles';
+
+import Author from 'src/components/Author';
+import Date from 'src/components/Date';
+
+import { UnknownFragment } from './UnknownFragment.generated';
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    ...theme.typography.body2,
+    marginLeft: theme.spacing(1) + 40,
+  },
+  author: {
+    fontWeight: 'bold',
+  },
+}));
+
+type Props = {
+  op: UnknownFragment;
+};
+
+function Unknown({ op }: Props) {
+  const classes = useStyles();
+
+  return (
+    <div className={classes.main}>
+      <Author author={op.author} className={classes.author} />
+      <span> did an operation unknown to this version of git-bug </span>
+      <Date date={op.date} />
+    </div>
+  );
+}
+
+export default Unknown;
@@ -0,0 +1,37 @@
+import React from 'react';
+
+import { makeStyles } from '@material-ui/core/styles';
+
+import Author from 'src/components/Author';
+import Date from 'src/components/Date';
+
+import { VoteFragment } from './VoteFragment.generated';
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    ...theme.typography.body2,
+    marginLeft: theme.spacing(1) + 40,
+  },
+  author: {
+    fontWeight: 'bold',
+  },
+}));
+
+type Props = {
+  op: VoteFragment;
+};
+
+function Vote({ op }: Props) {
+  const classes = useStyles();
+  return (
+    <div className={classes.main}>
+      <Author author={op.author} className={classes.author} />
+      <span>
+        {op.retract ? ' retracted their vote ' : ' voted for this bug '}
+      </span>
+      <Date date={op.date} />
+    </div>
+  );
+}
+
+export default Vote;
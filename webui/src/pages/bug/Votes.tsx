@@ -0,0 +1,105 @@
+import React, { useState } from 'react';
+
+import Button from '@material-ui/core/Button';
+import Typography from '@material-ui/core/Typography';
+import { makeStyles } from '@material-ui/core/styles';
+
+import isConflict from 'src/components/isConflict';
+
+import { BugFragment } from './Bug.generated';
+import { GetBugDocument } from './BugQuery.generated';
+import { TimelineDocument } from './TimelineQuery.generated';
+import { useVoteIdentityQuery, useVoteMutation } from './Votes.generated';
+
+const useStyles = makeStyles(theme => ({
+  title: {
+    fontWeight: 'bold',
+  },
+  main: {
+    ...theme.typography.body2,
+    display: 'flex',
+    alignItems: 'center',
+    margin: theme.spacing(1, 0),
+  },
+  count: {
+    marginRight: theme.spacing(1),
+  },
+}));
+
+type Props = {
+  bugId: string;
+  qualifiedId: string;
+  repoRef?: string | null;
+  // the number of operations of the bug when it was fetched, to detect the
+  // concurrent edits
+  operationCount: number;
+  voters: BugFragment['voters'];
+};
+
+// Votes display how many users want a bug fixed, and let the user vote for it
+// or retract their vote
+function Votes({ bugId, qualifiedId, repoRef, operationCount, voters }: Props) {
+  const classes = useStyles();
+  const { data } = useVoteIdentityQuery();
+  const [vote, { loading, client }] = useVoteMutation();
+  const [conflict, setConflict] = useState(false);
+
+  const user = data?.repository?.userIdentity;
+  const voted = !!user && voters.some(v => v.id === user.id);
+
+  const toggle = () => {
+    vote({
+      variables: {
+        input: {
+          repoRef,
+          prefix: bugId,
+          retract: voted,
+          expectedOperationCount: operationCount,
+        },
+      },
+      refetchQueries: [
+        {
+          query: TimelineDocument,
+          variables: {
+            id: qualifiedId,
+            first: 100,
+          },
+        },
+        { query: GetBugDocument, variables: { id: qualifiedId } },
+      ],
+      awaitRefetchQueries: true,
+    })
+      .then(() => setConflict(false))
+      .catch(error => {
+        if (!isConflict(error)) throw error;
+        setConflict(true);
+        return client?.reFetchObservableQueries();
+      });
+  };
+
+  return (
+    <>
+      <span className={classes.title}>Votes</span>
+      <div className={classes.main}>
+        <span
+          className={classes.count}
+          title={voters.map(v => v.displayName).join(', ')}
+        >
+          {voters.length === 1 ? '1 vote' : `${voters.length} votes`}
+        </span>
+        {user && (
+          <Button size="small" disabled={loading} onClick={toggle}>
+            {voted ? 'Retract' : 'Vote'}
+          </Button>
+        )}
+      </div>
+      {conflict && (
+        <Typography variant="body2" color="error">
+          This bug has been modified in the meantime and has been refreshed.
+        </Typography>
+      )}
+    </>
+  );
+}
+
+export default Votes;
@@ -0,0 +1,154 @@
+import React, { useState } from 'react';
+
+import Button from '@material-ui/core/Button';
+import Dialog from '@material-ui/core/Dialog';
+import DialogActions from '@material-ui/core/DialogActions';
+import DialogContent from '@material-ui/core/DialogContent';
+import DialogContentText from '@material-ui/core/DialogContentText';
+import DialogTitle from '@material-ui/core/DialogTitle';
+import MenuItem from '@material-ui/core/MenuItem';
+import TextField from '@material-ui/core/TextField';
+import { makeStyles } from '@material-ui/core/styles';
+
+import {
+  LabelsListDocument,
+  useMergeLabelMutation,
+  useRenameLabelMutation,
+  useSetLabelColorMutation,
+} from './LabelsQuery.generated';
+
+const useStyles = makeStyles(theme => ({
+  field: {
+    display: 'flex',
+    alignItems: 'flex-end',
+    marginBottom: theme.spacing(2),
+    '& > *': {
+      marginRight: theme.spacing(2),
+    },
+  },
+  color: {
+    width: theme.spacing(5),
+    height: theme.spacing(4),
+    padding: 0,
+    border: 'none',
+    background: 'none',
+  },
+}));
+
+type Props = {
+  name: string;
+  // the current color, as "#rrggbb"
+  color: string;
+  // the other labels, to merge into
+  others: string[];
+  onClose: () => void;
+};
+
+// LabelEditDialog rename, recolor or merge a label. Renaming and merging
+// change all the bugs having the label.
+function LabelEditDialog({ name, color, others, onClose }: Props) {
+  const classes = useStyles();
+  const [renameLabel, rename] = useRenameLabelMutation();
+  const [setLabelColor, recolor] = useSetLabelColorMutation();
+  const [mergeLabel, merge] = useMergeLabelMutation();
+  const [newName, setNewName] = useState(name);
+  const [newColor, setNewColor] = useState(color);
+  const [into, setInto] = useState('');
+  const [error, setError] = useState<string | null>(null);
+
+  const loading = rename.loading || recolor.loading || merge.loading;
+  const refetch = {
+    refetchQueries: [{ query: LabelsListDocument }],
+    awaitRefetchQueries: true,
+  };
+
+  const save = async (e: React.FormEvent) => {
+    e.preventDefault();
+
+    try {
+      if (newColor !== color) {
+        await setLabelColor({
+          variables: { input: { name, color: newColor } },
+          ...refetch,
+        });
+      }
+      if (newName.trim() !== name) {
+        await renameLabel({
+          variables: { input: { name, newName: newName.trim() } },
+          ...refetch,
+        });
+      }
+      onClose();
+    } catch (err) {
+      setError(err.message);
+    }
+  };
+
+  const submitMerge = () => {
+    if (into === '') return;
+
+    mergeLabel({ variables: { input: { name, into } }, ...refetch })
+      .then(() => onClose())
+      .catch(err => setError(err.message));
+  };
+
+  return (
+    <Dialog open onClose={onClose} fullWidth maxWidth="xs">
+      <form onSubmit={save}>
+        <DialogTitle>Edit the label {name}</DialogTitle>
+        <DialogContent>
+          {error && (
+            <DialogContentText color="error">{error}</DialogContentText>
+          )}
+          <div className={classes.field}>
+            <TextField
+              autoFocus
+              fullWidth
+              label="Name"
+              value={newName}
+              onChange={(e: any) => setNewName(e.target.value)}
+              disabled={loading}
+            />
+            <input
+              type="color"
+              className={classes.color}
+              value={newColor}
+              onChange={e => setNewColor(e.target.value)}
+              title="Color"
+            />
+          </div>
+          <div className={classes.field}>
+            <TextField
+              select
+              fullWidth
+              label="Merge into"
+              value={into}
+              onChange={(e: any) => setInto(e.target.value)}
+              disabled={loading || others.length === 0}
+            >
+              {others.map(other => (
+                <MenuItem key={other} value={other}>
+                  {other}
+                </MenuItem>
+              ))}
+            </TextField>
+            <Button onClick={submitMerge} disabled={loading || into === ''}>
+              Merge
+            </Button>
+          </div>
+          <DialogContentText>
+            Renaming or merging the label change all the bugs having it.
+          </DialogContentText>
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={onClose}>Cancel</Button>
+          <Button type="submit" color="primary" disabled={loading}>
+            Save
+          </Button>
+        </DialogActions>
+      </form>
+    </Dialog>
+  );
+}
+
+export default LabelEditDialog;
@@ -0,0 +1,161 @@
+import React, { useState } from 'react';
+import { Link } from 'react-router-dom';
+
+import Button from '@material-ui/core/Button';
+import Paper from '@material-ui/core/Paper';
+import TextField from '@material-ui/core/TextField';
+import { makeStyles } from '@material-ui/core/styles';
+
+import Label from 'src/components/Label';
+import { Color } from 'src/gqlTypes';
+
+import LabelEditDialog from './LabelEditDialog';
+import {
+  LabelsListDocument,
+  LabelsListQuery,
+  useCreateLabelMutation,
+} from './LabelsQuery.generated';
+
+type LabelEntry = NonNullable<LabelsListQuery['repository']>['labels'][0];
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    maxWidth: 800,
+    margin: 'auto',
+    marginTop: theme.spacing(4),
+    marginBottom: theme.spacing(4),
+    overflow: 'hidden',
+  },
+  header: {
+    ...theme.typography.h6,
+    padding: theme.spacing(2, 4),
+  },
+  form: {
+    display: 'flex',
+    alignItems: 'flex-end',
+    padding: theme.spacing(0, 4, 2),
+    '& > *': {
+      marginRight: theme.spacing(2),
+    },
+  },
+  color: {
+    width: theme.spacing(5),
+    height: theme.spacing(4),
+    padding: 0,
+    border: 'none',
+    background: 'none',
+  },
+  error: {
+    color: theme.palette.error.main,
+    padding: theme.spacing(0, 4, 2),
+  },
+  row: {
+    display: 'flex',
+    alignItems: 'center',
+    padding: theme.spacing(1, 4),
+    borderTop: `1px solid ${theme.palette.divider}`,
+  },
+  label: {
+    flex: 1,
+  },
+  count: {
+    color: theme.palette.text.secondary,
+    marginRight: theme.spacing(2),
+    textDecoration: 'none',
+  },
+}));
+
+// toHex write a color as "#rrggbb", as expected by the mutations
+export const toHex = (color: Color) =>
+  '#' +
+  [color.R, color.G, color.B]
+    .map(c => c.toString(16).padStart(2, '0'))
+    .join('');
+
+// the query listing the bugs having a label
+const labelQuery = (name: string) =>
+  /[\s"]/.test(name) ? `label:"${name.replace(/"/g, '\\"')}"` : `label:${name}`;
+
+type Props = {
+  labels: LabelEntry[];
+};
+
+function Labels({ labels }: Props) {
+  const classes = useStyles();
+  const [createLabel, { loading }] = useCreateLabelMutation();
+  const [name, setName] = useState('');
+  const [color, setColor] = useState('#9e9e9e');
+  const [error, setError] = useState<string | null>(null);
+  const [editing, setEditing] = useState<LabelEntry | null>(null);
+
+  const create = (e: React.FormEvent) => {
+    e.preventDefault();
+    if (name.trim() === '') return;
+
+    createLabel({
+      variables: { input: { name: name.trim(), color } },
+      refetchQueries: [{ query: LabelsListDocument }],
+      awaitRefetchQueries: true,
+    })
+      .then(() => {
+        setName('');
+        setError(null);
+      })
+      .catch(err => setError(err.message));
+  };
+
+  return (
+    <Paper className={classes.main}>
+      <div className={classes.header}>{labels.length} labels</div>
+      <form className={classes.form} onSubmit={create}>
+        <TextField
+          label="New label"
+          value={name}
+          onChange={(e: any) => setName(e.target.value)}
+          disabled={loading}
+        />
+        <input
+          type="color"
+          className={classes.color}
+          value={color}
+          onChange={e => setColor(e.target.value)}
+          title="Color"
+        />
+        <Button type="submit" color="primary" disabled={loading}>
+          Create
+        </Button>
+      </form>
+      {error && <div className={classes.error}>{error}</div>}
+      {labels.map(entry => (
+        <div key={entry.label.name} className={classes.row}>
+          <div className={classes.label}>
+            <Label label={entry.label} />
+          </div>
+          <Link
+            className={classes.count}
+            to={`/?q=${encodeURIComponent(labelQuery(entry.label.name))}`}
+          >
+            {entry.count === 0
+              ? 'unused'
+              : `${entry.count} bug${entry.count > 1 ? 's' : ''}`}
+          </Link>
+          <Button size="small" onClick={() => setEditing(entry)}>
+            Edit
+          </Button>
+        </div>
+      ))}
+      {editing && (
+        <LabelEditDialog
+          name={editing.label.name}
+          color={toHex(editing.label.color)}
+          others={labels
+            .map(entry => entry.label.name)
+            .filter(other => other !== editing.label.name)}
+          onClose={() => setEditing(null)}
+        />
+      )}
+    </Paper>
+  );
+}
+
+export default Labels;
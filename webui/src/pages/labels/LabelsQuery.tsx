@@ -0,0 +1,17 @@
+import React from 'react';
+
+import CircularProgress from '@material-ui/core/CircularProgress';
+
+import Labels from './Labels';
+import { useLabelsListQuery } from './LabelsQuery.generated';
+
+function LabelsQuery() {
+  const { loading, error, data } = useLabelsListQuery();
+
+  if (loading) return <CircularProgress />;
+  if (error) return <p>Error: {error.message}</p>;
+  if (!data?.repository) return <p>404.</p>;
+  return <Labels labels={data.repository.labels} />;
+}
+
+export default LabelsQuery;
@@ -1,10 +1,11 @@
-import React from 'react';
+import React, { useEffect, useRef } from 'react';
 import { Link } from 'react-router-dom';
 
 import TableCell from '@material-ui/core/TableCell/TableCell';
 import TableRow from '@material-ui/core/TableRow/TableRow';
 import Tooltip from '@material-ui/core/Tooltip/Tooltip';
 import { makeStyles } from '@material-ui/core/styles';
+import ChatBubbleOutline from '@material-ui/icons/ChatBubbleOutline';
 import CheckCircleOutline from '@material-ui/icons/CheckCircleOutline';
 import ErrorOutline from '@material-ui/icons/ErrorOutline';
 
@@ -74,20 +75,42 @@ const useStyles = makeStyles(theme => ({
       display: 'inline-block',
     },
   },
+  comments: {
+    display: 'flex',
+    alignItems: 'center',
+    margin: theme.spacing(0, 2),
+    color: theme.palette.text.secondary,
+    '& > svg': {
+      fontSize: '1rem',
+      marginRight: theme.spacing(0.5),
+    },
+  },
 }));
 
 type Props = {
   bug: BugRowFragment;
+  selected?: boolean;
 };
 
-function BugRow({ bug }: Props) {
+function BugRow({ bug, selected }: Props) {
   const classes = useStyles();
+  const row = useRef<HTMLTableRowElement>(null);
+  // the first comment is the description of the bug
+  const comments = bug.comments.totalCount - 1;
+
+  // keep the row selected with the keyboard visible
+  useEffect(() => {
+    if (selected && row.current) {
+      row.current.scrollIntoView({ block: 'nearest' });
+    }
+  }, [selected]);
+
   return (
-    <TableRow hover>
+    <TableRow hover selected={selected} ref={row}>
       <TableCell className={classes.cell}>
         <BugStatus status={bug.status} className={classes.status} />
         <div className={classes.expand}>
-          <Link to={'bug/' + bug.humanId}>
+          <Link to={'/bug/' + bug.qualifiedId}>
             <div className={classes.expand}>
               <span className={classes.title}>{bug.title}</span>
               {bug.labels.length > 0 && (
@@ -100,11 +123,22 @@ function BugRow({ bug }: Props) {
             </div>
           </Link>
           <div className={classes.details}>
-            {bug.humanId} opened&nbsp;
+            {bug.qualifiedId} opened&nbsp;
             <Date date={bug.createdAt} />
             &nbsp;by {bug.author.displayName}
           </div>
         </div>
+        {comments > 0 && (
+          <Tooltip title="Go to the last comment">
+            <Link
+              to={`/bug/${bug.qualifiedId}#${bug.comments.nodes[0].id}`}
+              className={classes.comments}
+            >
+              <ChatBubbleOutline />
+              {comments}
+            </Link>
+          </Tooltip>
+        )}
       </TableCell>
     </TableRow>
   );
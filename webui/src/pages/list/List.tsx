@@ -1,18 +1,34 @@
-import React from 'react';
+import React, { useState } from 'react';
+import { useHistory } from 'react-router-dom';
 
 import Table from '@material-ui/core/Table/Table';
 import TableBody from '@material-ui/core/TableBody/TableBody';
 
+import useShortcut from 'src/components/useShortcut';
+
 import BugRow from './BugRow';
 import { BugListFragment } from './ListQuery.generated';
 
 type Props = { bugs: BugListFragment };
 function List({ bugs }: Props) {
+  const history = useHistory();
+  // the bug selected with the keyboard, if any
+  const [selected, setSelected] = useState(-1);
+  const count = bugs.edges.length;
+
+  useShortcut('j', () => setSelected(Math.min(selected + 1, count - 1)));
+  useShortcut('k', () => setSelected(Math.max(selected - 1, 0)));
+  useShortcut(
+    'Enter',
+    () => history.push('/bug/' + bugs.edges[selected].node.qualifiedId),
+    selected >= 0 && selected < count
+  );
+
   return (
     <Table>
       <TableBody>
-        {bugs.edges.map(({ cursor, node }) => (
-          <BugRow bug={node} key={cursor} />
+        {bugs.edges.map(({ cursor, node }, index) => (
+          <BugRow bug={node} key={cursor} selected={index === selected} />
         ))}
       </TableBody>
     </Table>
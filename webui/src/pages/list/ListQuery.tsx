@@ -2,6 +2,7 @@ import { ApolloError } from 'apollo-boost';
 import React, { useState, useEffect, useRef } from 'react';
 import { useLocation, useHistory, Link } from 'react-router-dom';
 
+import Button from '@material-ui/core/Button';
 import IconButton from '@material-ui/core/IconButton';
 import InputBase from '@material-ui/core/InputBase';
 import Paper from '@material-ui/core/Paper';
@@ -61,7 +62,7 @@ const useStyles = makeStyles<Theme, StylesProps>(theme => ({
   },
   placeholderRow: {
     padding: theme.spacing(1),
-    borderBottomColor: theme.palette.grey['300'],
+    borderBottomColor: theme.palette.divider,
     borderBottomWidth: '1px',
     borderBottomStyle: 'solid',
     display: 'flex',
@@ -77,7 +78,7 @@ const useStyles = makeStyles<Theme, StylesProps>(theme => ({
     ...theme.typography.h5,
     padding: theme.spacing(8),
     textAlign: 'center',
-    borderBottomColor: theme.palette.grey['300'],
+    borderBottomColor: theme.palette.divider,
     borderBottomWidth: '1px',
     borderBottomStyle: 'solid',
     '& > p': {
@@ -204,8 +205,8 @@ function ListQuery() {
   let nextPage = null;
   let previousPage = null;
   let count = 0;
-  if (!loading && !error && data?.repository?.bugs) {
-    const bugs = data.repository.bugs;
+  if (!loading && !error && data?.bugs) {
+    const bugs = data.bugs;
     count = bugs.totalCount;
     // This computes the URL for the next page
     if (bugs.pageInfo.hasNextPage) {
@@ -251,8 +252,8 @@ function ListQuery() {
     content = <Placeholder count={10} />;
   } else if (error) {
     content = <Error error={error} />;
-  } else if (data?.repository) {
-    const bugs = data.repository.bugs;
+  } else if (data) {
+    const bugs = data.bugs;
 
     if (bugs.totalCount === 0) {
       content = <NoBug />;
@@ -284,6 +285,9 @@ function ListQuery() {
             Search
           </button>
         </form>
+        <Button variant="contained" color="primary" component={Link} to="/new">
+          New bug
+        </Button>
       </header>
       <FilterToolbar query={query} queryLocation={queryLocation} />
       {content}
@@ -0,0 +1,257 @@
+import React, { useState } from 'react';
+import { RouteComponentProps, useHistory } from 'react-router-dom';
+
+import Button from '@material-ui/core/Button';
+import Checkbox from '@material-ui/core/Checkbox';
+import CircularProgress from '@material-ui/core/CircularProgress';
+import FormControlLabel from '@material-ui/core/FormControlLabel';
+import MenuItem from '@material-ui/core/MenuItem';
+import Paper from '@material-ui/core/Paper';
+import TextField from '@material-ui/core/TextField';
+import Typography from '@material-ui/core/Typography';
+import { makeStyles } from '@material-ui/core/styles';
+
+import { FormFieldType } from 'src/gqlTypes';
+import t from 'src/i18n';
+
+import {
+  useNewBugMutation,
+  useNewBugRequirementsQuery,
+} from './NewBug.generated';
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    maxWidth: 800,
+    margin: 'auto',
+    marginTop: theme.spacing(4),
+    padding: theme.spacing(2),
+  },
+  title: {
+    ...theme.typography.h6,
+    margin: theme.spacing(0, 0, 2, 0),
+  },
+  field: {
+    marginBottom: theme.spacing(2),
+  },
+  actions: {
+    display: 'flex',
+    justifyContent: 'flex-end',
+    alignItems: 'center',
+  },
+  error: {
+    flex: 1,
+  },
+}));
+
+type Props = RouteComponentProps<{
+  repo?: string;
+}>;
+
+// A label ending with ':' is a prefix, completed by the user, as 'type:bug'
+const isPrefix = (label: string) => label.endsWith(':');
+
+// NewBugPage is the form to report a new bug, asking for the labels and custom
+// fields the repository requires. With one of the forms of the repository, the
+// description is made of the answers to its questions.
+function NewBugPage({ match }: Props) {
+  const classes = useStyles();
+  const history = useHistory();
+  const { repo } = match.params;
+  const { loading, error, data } = useNewBugRequirementsQuery({
+    variables: { ref: repo },
+  });
+  const [newBug, newBugState] = useNewBugMutation();
+  const [title, setTitle] = useState('');
+  const [message, setMessage] = useState('');
+  const [labels, setLabels] = useState<Record<string, string>>({});
+  const [fields, setFields] = useState<Record<string, string>>({});
+  const [formName, setFormName] = useState('');
+  const [answers, setAnswers] = useState<Record<string, string>>({});
+
+  if (loading) return <CircularProgress />;
+  if (error) return <p>Error: {error}</p>;
+  if (!data?.repository) return <p>404.</p>;
+
+  const requirements = data.repository.requirements;
+  const form = data.repository.forms.find(f => f.name === formName);
+  const prefixes = requirements.labels.filter(isPrefix);
+  const missing =
+    title.trim() === '' ||
+    prefixes.some(p => !labels[p]?.trim()) ||
+    requirements.fields.some(f => !fields[f]?.trim()) ||
+    (form?.fields || []).some(f =>
+      f.type === FormFieldType.Checkbox
+        ? f.required && answers[f.id] !== 'true'
+        : f.required && !answers[f.id]?.trim()
+    );
+
+  const setAnswer = (id: string, value: string) =>
+    setAnswers({ ...answers, [id]: value });
+
+  const submit = (e: React.FormEvent) => {
+    e.preventDefault();
+
+    newBug({
+      variables: {
+        input: {
+          repoRef: data.repository?.name,
+          title,
+          message,
+          labels: requirements.labels.map(l =>
+            isPrefix(l) ? l + labels[l].trim() : l
+          ),
+          fields: requirements.fields.map(name => ({
+            name,
+            value: fields[name],
+          })),
+          form: form?.name,
+          answers: form
+            ? Object.keys(answers).map(id => ({ id, value: answers[id] }))
+            : undefined,
+        },
+      },
+    })
+      .then(result => {
+        const bug = result.data?.newBug.bug;
+        if (bug) history.push('/bug/' + bug.qualifiedId);
+      })
+      // the error is displayed from the state of the mutation
+      .catch(() => {});
+  };
+
+  return (
+    <Paper className={classes.main}>
+      <h1 className={classes.title}>{t('New bug')}</h1>
+      <form onSubmit={submit}>
+        <TextField
+          className={classes.field}
+          fullWidth
+          required
+          label={t('Title')}
+          value={title}
+          onChange={(e: any) => setTitle(e.target.value)}
+          disabled={newBugState.loading}
+        />
+        {data.repository.forms.length > 0 && (
+          <TextField
+            className={classes.field}
+            select
+            fullWidth
+            label={t('Form')}
+            value={formName}
+            onChange={(e: any) => {
+              setFormName(e.target.value);
+              setAnswers({});
+            }}
+            disabled={newBugState.loading}
+          >
+            <MenuItem value="">{t('No form')}</MenuItem>
+            {data.repository.forms.map(f => (
+              <MenuItem key={f.name} value={f.name}>
+                {f.title}
+              </MenuItem>
+            ))}
+          </TextField>
+        )}
+        {form?.description && (
+          <Typography className={classes.field}>{form.description}</Typography>
+        )}
+        {!form && (
+          <TextField
+            className={classes.field}
+            fullWidth
+            multiline
+            rows="6"
+            variant="filled"
+            label={t('Description')}
+            value={message}
+            onChange={(e: any) => setMessage(e.target.value)}
+            disabled={newBugState.loading}
+          />
+        )}
+        {form?.fields.map(f =>
+          f.type === FormFieldType.Checkbox ? (
+            <FormControlLabel
+              className={classes.field}
+              key={f.id}
+              label={f.label + (f.required ? ' *' : '')}
+              control={
+                <Checkbox
+                  checked={answers[f.id] === 'true'}
+                  onChange={e => setAnswer(f.id, String(e.target.checked))}
+                  disabled={newBugState.loading}
+                />
+              }
+            />
+          ) : (
+            <TextField
+              className={classes.field}
+              key={f.id}
+              fullWidth
+              required={f.required}
+              select={f.type === FormFieldType.Dropdown}
+              multiline={f.type === FormFieldType.Textarea}
+              rows={f.type === FormFieldType.Textarea ? '6' : undefined}
+              label={f.label}
+              helperText={f.description}
+              value={answers[f.id] || ''}
+              onChange={(e: any) => setAnswer(f.id, e.target.value)}
+              disabled={newBugState.loading}
+            >
+              {f.options.map(option => (
+                <MenuItem key={option} value={option}>
+                  {option}
+                </MenuItem>
+              ))}
+            </TextField>
+          )
+        )}
+        {prefixes.map(prefix => (
+          <TextField
+            className={classes.field}
+            key={prefix}
+            fullWidth
+            required
+            label={`Label ${prefix}`}
+            value={labels[prefix] || ''}
+            onChange={(e: any) =>
+              setLabels({ ...labels, [prefix]: e.target.value })
+            }
+            disabled={newBugState.loading}
+          />
+        ))}
+        {requirements.fields.map(name => (
+          <TextField
+            className={classes.field}
+            key={name}
+            fullWidth
+            required
+            label={name}
+            value={fields[name] || ''}
+            onChange={(e: any) =>
+              setFields({ ...fields, [name]: e.target.value })
+            }
+            disabled={newBugState.loading}
+          />
+        ))}
+        <div className={classes.actions}>
+          {newBugState.error && (
+            <Typography color="error" className={classes.error}>
+              {newBugState.error.message}
+            </Typography>
+          )}
+          <Button
+            variant="contained"
+            color="primary"
+            type="submit"
+            disabled={newBugState.loading || missing}
+          >
+            {t('Submit')}
+          </Button>
+        </div>
+      </form>
+    </Paper>
+  );
+}
+
+export default NewBugPage;
@@ -0,0 +1,159 @@
+import React from 'react';
+import { Link } from 'react-router-dom';
+
+import Avatar from '@material-ui/core/Avatar';
+import Paper from '@material-ui/core/Paper';
+import Table from '@material-ui/core/Table/Table';
+import TableBody from '@material-ui/core/TableBody/TableBody';
+import { makeStyles } from '@material-ui/core/styles';
+
+import Date from 'src/components/Date';
+
+import BugRow from '../list/BugRow';
+
+import { GetIdentityQuery, IdentityActivityFragment } from './UserQuery.generated';
+
+// the action of an operation, as displayed in the activity of a user
+const actions: { [typename: string]: string } = {
+  CreateOperation: 'opened',
+  SetTitleOperation: 'renamed',
+  AddCommentOperation: 'commented on',
+  EditCommentOperation: 'edited a comment on',
+  SetStatusOperation: 'changed the status of',
+  LabelChangeOperation: 'labeled',
+  SetComponentOperation: 'set the component of',
+  AssigneeChangeOperation: 'changed the assignees of',
+  MarkDuplicateOperation: 'marked as duplicate',
+  SetParentOperation: 'set the parent of',
+  RemoveParentOperation: 'removed the parent of',
+  AddChecklistItemOperation: 'added a checklist item to',
+  CheckItemOperation: 'checked an item of',
+  VoteOperation: 'voted on',
+  RequestReviewOperation: 'requested a review on',
+};
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    maxWidth: 800,
+    margin: 'auto',
+    marginTop: theme.spacing(4),
+    marginBottom: theme.spacing(4),
+    overflow: 'hidden',
+  },
+  header: {
+    display: 'flex',
+    alignItems: 'center',
+    padding: theme.spacing(2, 4),
+  },
+  avatar: {
+    width: theme.spacing(8),
+    height: theme.spacing(8),
+    marginRight: theme.spacing(2),
+  },
+  name: {
+    ...theme.typography.h5,
+  },
+  details: {
+    color: theme.palette.text.secondary,
+    '& > *': {
+      marginRight: theme.spacing(1),
+    },
+  },
+  section: {
+    ...theme.typography.h6,
+    padding: theme.spacing(2, 4, 1),
+  },
+  empty: {
+    padding: theme.spacing(0, 4, 2),
+    color: theme.palette.text.secondary,
+  },
+  operation: {
+    padding: theme.spacing(0.5, 4),
+    color: theme.palette.text.secondary,
+    '& a': {
+      color: theme.palette.text.primary,
+      textDecoration: 'none',
+    },
+  },
+}));
+
+type Identity = NonNullable<
+  NonNullable<GetIdentityQuery['repository']>['identity']
+>;
+
+type Props = {
+  identity: Identity;
+  activity: IdentityActivityFragment;
+};
+
+function User({ identity, activity }: Props) {
+  const classes = useStyles();
+
+  const bugs = (
+    title: string,
+    connection: IdentityActivityFragment['authoredBugs']
+  ) => (
+    <>
+      <div className={classes.section}>
+        {title} ({connection.totalCount})
+      </div>
+      {connection.nodes.length === 0 ? (
+        <div className={classes.empty}>No bug.</div>
+      ) : (
+        <Table>
+          <TableBody>
+            {connection.nodes.map(bug => (
+              <BugRow bug={bug} key={bug.id} />
+            ))}
+          </TableBody>
+        </Table>
+      )}
+    </>
+  );
+
+  return (
+    <Paper className={classes.main}>
+      <div className={classes.header}>
+        <Avatar
+          className={classes.avatar}
+          src={identity.avatarUrl ? identity.avatarUrl : undefined}
+        >
+          {identity.displayName.charAt(0).toUpperCase()}
+        </Avatar>
+        <div>
+          <div className={classes.name}>{identity.displayName}</div>
+          <div className={classes.details}>
+            {identity.login && <span>@{identity.login}</span>}
+            {identity.email && <span>{identity.email}</span>}
+            <span>{identity.humanId}</span>
+          </div>
+          <div className={classes.details}>
+            <span>involved in {activity.involvedCount} bugs</span>
+            {activity.lastActivity && (
+              <span>
+                last active <Date date={activity.lastActivity} />
+              </span>
+            )}
+          </div>
+        </div>
+      </div>
+
+      {bugs('Authored bugs', activity.authoredBugs)}
+      {bugs('Commented bugs', activity.commentedBugs)}
+
+      <div className={classes.section}>Recent activity</div>
+      {activity.recentOperations.length === 0 && (
+        <div className={classes.empty}>No activity.</div>
+      )}
+      {activity.recentOperations.map(({ bug, operation }) => (
+        <div className={classes.operation} key={operation.id}>
+          {actions[operation.__typename] || 'changed'}{' '}
+          <Link to={'/bug/' + bug.qualifiedId}>{bug.title}</Link>{' '}
+          <Date date={operation.date} />
+        </div>
+      ))}
+    </Paper>
+  );
+}
+
+export default User;
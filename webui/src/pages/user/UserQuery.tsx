@@ -0,0 +1,29 @@
+import React from 'react';
+import { RouteComponentProps } from 'react-router-dom';
+
+import CircularProgress from '@material-ui/core/CircularProgress';
+
+import User from './User';
+import { useGetIdentityQuery } from './UserQuery.generated';
+
+type Props = RouteComponentProps<{
+  id: string;
+}>;
+
+const UserQuery: React.FC<Props> = ({ match }: Props) => {
+  const { loading, error, data } = useGetIdentityQuery({
+    variables: { prefix: match.params.id },
+  });
+  if (loading) return <CircularProgress />;
+  if (error) return <p>Error: {error.message}</p>;
+  const repository = data?.repository;
+  if (!repository?.identity || !repository.identityActivity) return <p>404.</p>;
+  return (
+    <User
+      identity={repository.identity}
+      activity={repository.identityActivity}
+    />
+  );
+};
+
+export default UserQuery;
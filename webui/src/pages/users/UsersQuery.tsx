@@ -0,0 +1,110 @@
+import React from 'react';
+import { Link } from 'react-router-dom';
+
+import Avatar from '@material-ui/core/Avatar';
+import Button from '@material-ui/core/Button';
+import CircularProgress from '@material-ui/core/CircularProgress';
+import Paper from '@material-ui/core/Paper';
+import { makeStyles } from '@material-ui/core/styles';
+
+import { useListIdentitiesQuery } from './UsersQuery.generated';
+
+const PAGE_SIZE = 50;
+
+const useStyles = makeStyles(theme => ({
+  main: {
+    maxWidth: 800,
+    margin: 'auto',
+    marginTop: theme.spacing(4),
+    marginBottom: theme.spacing(4),
+    overflow: 'hidden',
+  },
+  header: {
+    ...theme.typography.h6,
+    padding: theme.spacing(2, 4),
+  },
+  user: {
+    display: 'flex',
+    alignItems: 'center',
+    padding: theme.spacing(1, 4),
+    color: theme.palette.text.primary,
+    textDecoration: 'none',
+    '&:hover': {
+      backgroundColor: theme.palette.action.hover,
+    },
+  },
+  name: {
+    marginLeft: theme.spacing(2),
+    fontWeight: 500,
+  },
+  details: {
+    marginLeft: theme.spacing(1),
+    color: theme.palette.text.secondary,
+  },
+  more: {
+    display: 'flex',
+    justifyContent: 'center',
+    padding: theme.spacing(1),
+  },
+}));
+
+function UsersQuery() {
+  const classes = useStyles();
+  const { loading, error, data, fetchMore } = useListIdentitiesQuery({
+    variables: { first: PAGE_SIZE },
+  });
+
+  if (loading) return <CircularProgress />;
+  if (error) return <p>Error: {error.message}</p>;
+  const identities = data?.repository?.allIdentities;
+  if (!identities) return <p>404.</p>;
+
+  const loadMore = () =>
+    fetchMore({
+      variables: { first: PAGE_SIZE, after: identities.pageInfo.endCursor },
+      updateQuery: (previous, { fetchMoreResult }) => {
+        const next = fetchMoreResult?.repository?.allIdentities;
+        const prev = previous.repository?.allIdentities;
+        if (!next || !prev || !previous.repository) return previous;
+        return {
+          ...previous,
+          repository: {
+            ...previous.repository,
+            allIdentities: {
+              ...next,
+              nodes: [...prev.nodes, ...next.nodes],
+            },
+          },
+        };
+      },
+    });
+
+  return (
+    <Paper className={classes.main}>
+      <div className={classes.header}>{identities.totalCount} users</div>
+      {identities.nodes.map(user => (
+        <Link
+          key={user.id}
+          to={`/user/${user.humanId}`}
+          className={classes.user}
+        >
+          <Avatar src={user.avatarUrl ? user.avatarUrl : undefined}>
+            {user.displayName.charAt(0).toUpperCase()}
+          </Avatar>
+          <span className={classes.name}>{user.displayName}</span>
+          {user.login && (
+            <span className={classes.details}>@{user.login}</span>
+          )}
+          <span className={classes.details}>{user.humanId}</span>
+        </Link>
+      ))}
+      {identities.pageInfo.hasNextPage && (
+        <div className={classes.more}>
+          <Button onClick={loadMore}>More users</Button>
+        </div>
+      )}
+    </Paper>
+  );
+}
+
+export default UsersQuery;
@@ -1,11 +1,20 @@
 import { createMuiTheme } from '@material-ui/core/styles';
 
-const theme = createMuiTheme({
-  palette: {
-    primary: {
-      main: '#263238',
-    },
-  },
-});
+export const defaultAccentColor = '#263238';
+
+// The theme selected by the user. 'system' follows the preference of the
+// operating system.
+export type ThemeMode = 'light' | 'dark' | 'system';
 
-export default theme;
+export const themeModes: ThemeMode[] = ['light', 'dark', 'system'];
+
+export function makeTheme(type: 'light' | 'dark', accentColor?: string) {
+  return createMuiTheme({
+    palette: {
+      type,
+      primary: {
+        main: accentColor || defaultAccentColor,
+      },
+    },
+  });
+}